@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/go-resty/resty/v2"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/core/integration_api/resources/v1/dto"
+	"github.com/rs/zerolog/log"
+	"os"
+)
+
+const (
+	baseUrl = "BASE_URL"
+)
+
+func main() {
+	lambda.Start(Run)
+}
+
+// Run receives order ids from aggregator outbox queue and dispatches their pending statuses
+func Run(ctx context.Context, sqsEvent events.SQSEvent) error {
+	req := dto.DispatchAggregatorOutboxRequest{
+		OrderIDs: make([]string, 0, len(sqsEvent.Records)),
+	}
+
+	for _, message := range sqsEvent.Records {
+		log.Info().Msgf("aggregator outbox message %s, order_id=%s", message.MessageId, message.Body)
+		if message.Body == "" {
+			continue
+		}
+		req.OrderIDs = append(req.OrderIDs, message.Body)
+	}
+
+	if len(req.OrderIDs) == 0 {
+		return nil
+	}
+
+	var errorResp errors.ErrorResponse
+
+	resp, err := resty.New().
+		SetBaseURL(os.Getenv(baseUrl)).
+		R().
+		SetContext(ctx).
+		SetBody(req).
+		SetError(&errorResp).
+		Post("/api/aggregator-outbox/dispatch")
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		return fmt.Errorf("dispatch aggregator outbox: %s %s", resp.Status(), errorResp.Msg)
+	}
+
+	return nil
+}
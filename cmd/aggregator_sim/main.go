@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/service/aggregator"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// aggregator_sim replays recorded aggregator webhooks against integration api, started with aggregator_simulator=true,
+// and checks requests which integration api would send to aggregators
+//
+//	BASE_URL=http://localhost:8080 KWAAKA_ADMIN_TOKEN=... GLOVO_TOKEN=... SCENARIO=scenarios/glovo_create_order.json go run ./cmd/aggregator_sim
+const (
+	baseUrl          = "BASE_URL"
+	kwaakaAdminToken = "KWAAKA_ADMIN_TOKEN"
+	scenarioPath     = "SCENARIO"
+
+	callsUrl = "/v1/kwaaka-admin/aggregator-simulator/calls"
+	resetUrl = "/v1/kwaaka-admin/aggregator-simulator/reset"
+)
+
+type Scenario struct {
+	Name          string                    `json:"name"`
+	Steps         []Step                    `json:"steps"`
+	ExpectedCalls []aggregator.ExpectedCall `json:"expected_calls"`
+}
+
+type Step struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// PayloadFile - путь относительно файла сценария
+	PayloadFile string            `json:"payload_file"`
+	Payload     json.RawMessage   `json:"payload"`
+	Headers     map[string]string `json:"headers"`
+	WaitSeconds int               `json:"wait_seconds"`
+}
+
+func main() {
+	if err := run(); err != nil {
+		log.Printf("error: %s", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	path := os.Getenv(scenarioPath)
+
+	scenario, err := readScenario(path)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("STARTING AGGREGATOR SIMULATOR, scenario: %s", scenario.Name)
+
+	cli := resty.New().
+		SetBaseURL(os.Getenv(baseUrl)).
+		SetHeader("Authorization", os.Getenv(kwaakaAdminToken))
+
+	if err = post(cli.R(), resetUrl); err != nil {
+		return err
+	}
+
+	for i, step := range scenario.Steps {
+		payload, err := step.payload(filepath.Dir(path))
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i, err)
+		}
+
+		req := cli.R().SetBody(payload).SetHeader("Content-Type", "application/json")
+		for key, value := range step.Headers {
+			req.SetHeader(key, os.ExpandEnv(value))
+		}
+
+		method := step.Method
+		if method == "" {
+			method = http.MethodPost
+		}
+
+		resp, err := req.Execute(method, step.Path)
+		if err != nil {
+			return fmt.Errorf("step %d: %w", i, err)
+		}
+		log.Printf("step %d: %s %s, status: %d, response: %s", i, method, step.Path, resp.StatusCode(), resp.String())
+
+		time.Sleep(time.Duration(step.WaitSeconds) * time.Second)
+	}
+
+	var calls []aggregator.Call
+	resp, err := cli.R().SetResult(&calls).Get(callsUrl)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("get captured calls: %s", resp.String())
+	}
+
+	for _, call := range calls {
+		log.Printf("captured call: %+v", call)
+	}
+
+	if err = aggregator.MatchSequence(calls, scenario.ExpectedCalls); err != nil {
+		return err
+	}
+
+	log.Printf("SCENARIO PASSED: %s", scenario.Name)
+
+	return nil
+}
+
+func readScenario(path string) (Scenario, error) {
+	if path == "" {
+		return Scenario{}, fmt.Errorf("%s is empty", scenarioPath)
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return Scenario{}, err
+	}
+
+	var scenario Scenario
+	if err = json.Unmarshal(body, &scenario); err != nil {
+		return Scenario{}, err
+	}
+
+	return scenario, nil
+}
+
+func (s Step) payload(scenarioDir string) ([]byte, error) {
+	if s.PayloadFile == "" {
+		return s.Payload, nil
+	}
+
+	return os.ReadFile(filepath.Join(scenarioDir, s.PayloadFile))
+}
+
+func post(req *resty.Request, url string) error {
+	var errorResp errors.ErrorResponse
+
+	resp, err := req.SetError(&errorResp).Post(url)
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		return fmt.Errorf("%s: %s", url, errorResp.Msg)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/go-resty/resty/v2"
+	"github.com/kwaaka-team/orders-core/cmd"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"log"
+	"os"
+)
+
+const (
+	baseUrl = "BASE_URL"
+)
+
+func main() {
+	if cmd.IsLambda() {
+		lambda.Start(run)
+	} else {
+		if err := run(context.Background()); err != nil {
+			log.Printf("error: %s", err)
+			return
+		}
+	}
+}
+
+func run(ctx context.Context) error {
+	log.Printf("STARTING APPLY-AVAILABILITY-SCHEDULES REQUEST")
+
+	cli := resty.New().SetBaseURL(os.Getenv(baseUrl))
+
+	var errorResp errors.ErrorResponse
+
+	resp, err := cli.R().
+		SetContext(ctx).
+		SetError(&errorResp).
+		Post("/api/apply-availability-schedules")
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		log.Printf("apply availability schedules error: %s", errorResp.Msg)
+		return fmt.Errorf("status code: %d, response: %s", resp.StatusCode(), errorResp.Msg)
+	}
+
+	log.Printf("apply availability schedules result: %s", resp.String())
+
+	return nil
+}
@@ -19,7 +19,7 @@ func run() error {
 		return err
 	}
 
-	wppService, err := wppService.NewWhatsappService(nil, opts.WhatsAppConfiguration.Instance, opts.WhatsAppConfiguration.AuthToken, opts.WhatsAppConfiguration.BaseUrl, nil, nil, nil, nil, nil)
+	wppService, err := wppService.NewWhatsappService(nil, opts.WhatsAppConfiguration.Instance, opts.WhatsAppConfiguration.AuthToken, opts.WhatsAppConfiguration.BaseUrl, nil, nil, nil, nil, nil, nil)
 	if err != nil {
 		return err
 	}
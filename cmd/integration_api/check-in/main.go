@@ -66,6 +66,11 @@ func run() error {
 		return err
 	}
 
+	sandboxOrderRepository, err := pos.NewMongoSandboxOrderRepository(db)
+	if err != nil {
+		return err
+	}
+
 	posFactory, err := pos.NewFactory(
 		anotherBillRepository,
 		sqsCli,
@@ -79,6 +84,7 @@ func run() error {
 		cfg.RKeeper7XMLConfiguration.LicenseBaseURL,
 		cfg.SyrveConfiguration.BaseURL,
 		cfg.YarosConfiguration.BaseURL, cfg.YarosConfiguration.InfoSystem, cfg.TillypadConfiguration.BaseUrl, cfg.Ytimes.BaseUrl, cfg.Ytimes.Token, cfg.PosistConfiguration.BaseUrl,
+		sandboxOrderRepository,
 	)
 	if err != nil {
 		return err
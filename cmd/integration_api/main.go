@@ -7,6 +7,7 @@ import (
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/getsentry/sentry-go"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/kwaaka-team/orders-core/cmd"
@@ -40,8 +41,10 @@ import (
 	"github.com/kwaaka-team/orders-core/pkg/whatsapp"
 	"github.com/kwaaka-team/orders-core/pkg/whatsapp/clients"
 	"github.com/kwaaka-team/orders-core/service/aggregator"
+	"github.com/kwaaka-team/orders-core/service/availability_schedule"
 	"github.com/kwaaka-team/orders-core/service/aws_s3"
 	"github.com/kwaaka-team/orders-core/service/bitrix"
+	"github.com/kwaaka-team/orders-core/service/customer"
 	"github.com/kwaaka-team/orders-core/service/error_solutions"
 	errorSolutionsRepo "github.com/kwaaka-team/orders-core/service/error_solutions/repository"
 	"github.com/kwaaka-team/orders-core/service/gourmet"
@@ -53,6 +56,8 @@ import (
 	menuServicePkg "github.com/kwaaka-team/orders-core/service/menu"
 	orderServicePkg "github.com/kwaaka-team/orders-core/service/order"
 	"github.com/kwaaka-team/orders-core/service/order/delivery"
+	"github.com/kwaaka-team/orders-core/service/order/idempotency"
+	"github.com/kwaaka-team/orders-core/service/order/outbox"
 	"github.com/kwaaka-team/orders-core/service/order_report"
 	"github.com/kwaaka-team/orders-core/service/order_rules"
 	paymentServicePkg "github.com/kwaaka-team/orders-core/service/payment"
@@ -60,11 +65,16 @@ import (
 	"github.com/kwaaka-team/orders-core/service/pos"
 	posRepository "github.com/kwaaka-team/orders-core/service/pos/repository"
 	"github.com/kwaaka-team/orders-core/service/promo_code"
+	redemptionRepo "github.com/kwaaka-team/orders-core/service/promo_code/redemption_repository"
 	promoCodeRepo "github.com/kwaaka-team/orders-core/service/promo_code/repository"
 	userPromoCodeRepo "github.com/kwaaka-team/orders-core/service/promo_code/user_repository"
+	"github.com/kwaaka-team/orders-core/service/promotion"
 	"github.com/kwaaka-team/orders-core/service/refund"
 	"github.com/kwaaka-team/orders-core/service/restaurant_set"
+	"github.com/kwaaka-team/orders-core/service/review"
+	"github.com/kwaaka-team/orders-core/service/settlement"
 	"github.com/kwaaka-team/orders-core/service/shaurma_food"
+	"github.com/kwaaka-team/orders-core/service/sla"
 	"github.com/kwaaka-team/orders-core/service/sms"
 	"github.com/kwaaka-team/orders-core/service/stoplist"
 	storeServicePkg "github.com/kwaaka-team/orders-core/service/store"
@@ -221,6 +231,14 @@ func run() error {
 		Password: cfg.RedisConfig.Password,
 	})
 
+	var aggregatorRecorder *aggregator.Recorder
+	if cfg.AggregatorSimulator {
+		aggregatorRecorder = aggregator.NewRecorder()
+	}
+
+	// completedPublisher - заказы, закрытые кроном автозакрытия или доставленные 3pl без обновления статуса от pos
+	completedPublisher := &orderServicePkg.Publisher{}
+
 	storeService, stopListService, aggFactory, posFactory, orderRepo, menuService, storeGroupService, paymentFactory, customerRepo, subscriptionRepo, paymentRepo, kwaaka3plService, orderRuleService, orderReport, cartService, restaurantSetService, refundRepo, errorSolutionService, err := createServices(
 		ds,
 		cfg,
@@ -233,6 +251,8 @@ func run() error {
 		menuCli,
 		cognitoSvc,
 		opts.IntegrationBaseURL,
+		aggregatorRecorder,
+		completedPublisher,
 	)
 	if err != nil {
 		return err
@@ -264,17 +284,44 @@ func run() error {
 	}
 	publisher.AddSubscriber(subscriber3PL)
 
+	outboxRepo, err := outbox.NewMongoRepository(ds)
+	if err != nil {
+		return err
+	}
+	aggregatorOutboxService, err := orderServicePkg.NewAggregatorOutboxService(outboxRepo, orderRepo, storeService, aggFactory)
+	if err != nil {
+		return err
+	}
+	outboxSubscriber, err := orderServicePkg.NewAggregatorOutboxSubscriber(aggregatorOutboxService, sqsCli, cfg.QueueUrls.AggregatorOutboxQueueUrl)
+	if err != nil {
+		return err
+	}
+	publisher.AddSubscriber(outboxSubscriber)
+
+	// outboxPublisher - статусы, которые сервис заказов сохраняет в outbox без уведомления остальных подписчиков (автопринятие, готовность, ожидание отправки)
+	outboxPublisher := &orderServicePkg.Publisher{}
+	outboxPublisher.AddSubscriber(outboxSubscriber)
+
 	posSender, err := orderServicePkg.NewPosSender(cfg, menuCli, storeCli, menuService, storeService, orderRepo, orderCli.GetDataStore(), posFactory, errorSolutionService, stopListService, telegramService, sqsCli)
 	if err != nil {
 		return err
 	}
 
-	orderServiceImpl, err := createOrderService(cfg, menuCli, storeCli, storeService, aggFactory, posFactory, orderRepo, menuService, storeGroupService, publisher, posSender, orderRuleService, paymentRepo, cartService, errorSolutionService)
+	promotionRepo, err := promotion.NewMongoRepository(ds)
+	if err != nil {
+		return err
+	}
+	promotionService, err := promotion.NewService(promotionRepo, storeService, orderRepo)
+	if err != nil {
+		return err
+	}
+
+	orderServiceImpl, err := createOrderService(cfg, menuCli, storeCli, storeService, aggFactory, posFactory, orderRepo, menuService, storeGroupService, publisher, posSender, orderRuleService, paymentRepo, cartService, errorSolutionService, promotionService, completedPublisher, outboxPublisher)
 	if err != nil {
 		return err
 	}
 
-	emptyPosServiceImpl, err := orderServicePkg.NewEmptyPosService(storeService, aggFactory, orderRepo)
+	emptyPosServiceImpl, err := orderServicePkg.NewEmptyPosService(storeService, aggFactory, orderRepo, outboxPublisher)
 	if err != nil {
 		return err
 	}
@@ -322,16 +369,36 @@ func run() error {
 		return err
 	}
 
-	paymentService, err = createPaymentService(paymentFactory, customerRepo, subscriptionRepo, paymentRepo, sqsCli, cfg.QueueUrls.PaymentsQueueUrl, logger, storeService, storeGroupService, refundRepo)
+	orderClaimRepo, err := idempotency.NewMongoRepository(ds)
+	if err != nil {
+		return err
+	}
+
+	orderService, err = orderServicePkg.NewIdempotencyDecorator(orderService, orderClaimRepo, orderRepo, storeService, aggFactory)
+	if err != nil {
+		return err
+	}
+
+	customerProfileRepo, err := customer.NewMongoRepository(ds)
+	if err != nil {
+		return err
+	}
+
+	customerService, err := customer.NewService(customerProfileRepo, orderRepo, storeService, customerRepo)
+	if err != nil {
+		return err
+	}
+
+	paymentService, err = createPaymentService(paymentFactory, customerRepo, subscriptionRepo, paymentRepo, sqsCli, cfg.QueueUrls.PaymentsQueueUrl, logger, storeService, storeGroupService, refundRepo, orderRepo, cartService, promotionService, customerService)
 	if err != nil {
 		return err
 	}
 
 	var orderCronService orderServicePkg.OrderCronService = orderServiceImpl
 	var statusUpdateService orderServicePkg.StatusUpdateService = orderServiceImpl
-	var orderReviewService orderServicePkg.ReviewService = orderServiceImpl
 	var orderInfoSharingService orderServicePkg.InfoSharingService = orderServiceImpl
 	var orderCancellationService orderServicePkg.CancellationService = orderServiceImpl
+	var orderModificationService orderServicePkg.ModificationService = orderServiceImpl
 
 	posterService, err := pos.NewPosterService(nil, cfg.PosterConfiguration.BaseURL, "", posterStoreAuthRepo, storeCli, menuCli, cfg.ApplicationID, cfg.ApplicationSecret, cfg.RedirectURI, logger)
 	if err != nil {
@@ -355,7 +422,7 @@ func run() error {
 
 	newsletterRepo := repository2.NewNewsletterRepository(ds)
 
-	wppService, err := wppService.NewWhatsappService(whatsappService, cfg.WhatsAppConfiguration.Instance, cfg.WhatsAppConfiguration.AuthToken, cfg.WhatsAppConfiguration.BaseUrl, newsletterRepo, storeService, orderCli, storeGroupService, redisClient)
+	wppService, err := wppService.NewWhatsappService(whatsappService, cfg.WhatsAppConfiguration.Instance, cfg.WhatsAppConfiguration.AuthToken, cfg.WhatsAppConfiguration.BaseUrl, newsletterRepo, storeService, orderCli, storeGroupService, redisClient, customerService)
 	if err != nil {
 		return err
 	}
@@ -373,6 +440,20 @@ func run() error {
 		return err
 	}
 
+	settlementRepo, err := settlement.NewMongoRepository(ds)
+	if err != nil {
+		return err
+	}
+	settlementService, err := settlement.NewService(opts, settlementRepo, storeService, orderReport, legalEntityService, legalEntityPaymentService, s3Service)
+	if err != nil {
+		return err
+	}
+
+	slaService, err := sla.NewService(orderRepo, storeService)
+	if err != nil {
+		return err
+	}
+
 	promoCodeRepo, err := promoCodeRepo.NewMongoRepository(ds.Client().Database(opts.DSDB))
 	if err != nil {
 		return err
@@ -383,16 +464,50 @@ func run() error {
 		return err
 	}
 
-	promoCodeService, err := promo_code.NewPromoCodeService(logger, promoCodeRepo, userPromoCodes, storeService, menuService)
+	promoCodeRedemptions, err := redemptionRepo.NewMongoRepository(ds.Client().Database(opts.DSDB))
 	if err != nil {
 		return err
 	}
 
+	promoCodeService, err := promo_code.NewPromoCodeService(logger, promoCodeRepo, userPromoCodes, promoCodeRedemptions, storeService, menuService, orderRepo, customerService)
+	if err != nil {
+		return err
+	}
+
+	redemptionSubscriber, err := promo_code.NewRedemptionSubscriber(promoCodeService)
+	if err != nil {
+		return err
+	}
+	publisher.AddSubscriber(redemptionSubscriber)
+
+	customerProfileSubscriber, err := customer.NewProfileSubscriber(customerService)
+	if err != nil {
+		return err
+	}
+	publisher.AddSubscriber(customerProfileSubscriber)
+
 	smsService, err := sms.NewSmsService(opts.SmsLogin, opts.SmsPassword, redisClient, storeGroupService)
 	if err != nil {
 		return err
 	}
 
+	reviewRepo, err := review.NewMongoRepository(ds)
+	if err != nil {
+		return err
+	}
+
+	orderReviewService, err := review.NewService(reviewRepo, orderRepo, storeService, wppService, smsService, telegramService)
+	if err != nil {
+		return err
+	}
+
+	reviewRequestSubscriber, err := review.NewRequestSubscriber(orderReviewService)
+	if err != nil {
+		return err
+	}
+	publisher.AddSubscriber(reviewRequestSubscriber)
+	completedPublisher.AddSubscriber(reviewRequestSubscriber)
+
 	bitrixService := bitrix.NewBitrixService(wppService)
 
 	gourmetService, err := gourmet.NewServiceImpl(storeService, opts.IIKOConfiguration.BaseURL)
@@ -400,10 +515,20 @@ func run() error {
 		return err
 	}
 
+	availabilityScheduleRepo, err := availability_schedule.NewMongoRepository(ds)
+	if err != nil {
+		return err
+	}
+
+	availabilityScheduleService, err := availability_schedule.NewService(availabilityScheduleRepo, storeService, menuService, stopListService)
+	if err != nil {
+		return err
+	}
+
 	server := v1.NewServer(orderService, orderReviewService, menuService, posFactory, statusUpdateService, orderCronService, kwaaka3plService, storeService, stopListService, storeGroupService, glovoManager, woltManager, deliverooManager,
 		externalOrderManager, externalMenuManager, externalAuthManager, talabatOrderManager, talabatMenuManager, starterAppOrderManager, iikoManager, posterService, foodBandMenuManager, foodBandOrderManager, foodBandStoreManager, externalPosIntegrationManager,
 		paymentService, jowiManager, opts, logger, cmd.IsLambda(), legalEntityPaymentService, telegramService, orderInfoSharingService, orderCancellationService, shaurmaFoodService, wppBusinessService, wppService, promoCodeService, orderReport,
-		cartService, smsService, bitrixService, restaurantSetService, gourmetService)
+		cartService, smsService, bitrixService, restaurantSetService, gourmetService, aggregatorOutboxService, aggregatorRecorder, orderModificationService, availabilityScheduleService, settlementService, slaService, promotionService, customerService, menuCli, s3.New(opts.AwsSession))
 
 	if cmd.IsLambda() {
 		wrappedHandler := lumigotracer.WrapHandler(server.GinProxy, &lumigotracer.Config{})
@@ -431,8 +556,12 @@ func createPaymentService(paymentFactory *paymentServicePkg.PaymentSystemFactory
 	logger *zap.SugaredLogger,
 	storeService storeServicePkg.Service,
 	storeGroupService storeGroupServicePkg.Service,
-	refundRepo refund.Repository) (paymentServicePkg.Service, error) {
-	paymentService, err := paymentServicePkg.NewService(paymentFactory, customerRepo, paymentRepo, subscriptionRepo, notifyQueue, paymentsQueueUrl, logger, storeService, storeGroupService, refundRepo)
+	refundRepo refund.Repository,
+	orderRepo orderServicePkg.Repository,
+	cartService orderServicePkg.CartService,
+	promotionService promotion.Service,
+	customerService customer.Service) (paymentServicePkg.Service, error) {
+	paymentService, err := paymentServicePkg.NewService(paymentFactory, customerRepo, paymentRepo, subscriptionRepo, notifyQueue, paymentsQueueUrl, logger, storeService, storeGroupService, refundRepo, orderRepo, cartService, promotionService, customerService)
 	if err != nil {
 		return nil, err
 	}
@@ -455,6 +584,9 @@ func createOrderService(
 	paymentRepo paymentRepository.PaymentsRepository,
 	cartService orderServicePkg.CartService,
 	errSolutionService error_solutions.Service,
+	promotionService promotion.Service,
+	completedPublisher *orderServicePkg.Publisher,
+	outboxPublisher *orderServicePkg.Publisher,
 ) (*orderServicePkg.ServiceImpl, error) {
 
 	sf := orderServicePkg.ServiceFactory{
@@ -473,6 +605,10 @@ func createOrderService(
 		PaymentRepo:       paymentRepo,
 		CartService:       cartService,
 		ErrSolution:       errSolutionService,
+		PromotionService:  promotionService,
+
+		CompletedPublisher: completedPublisher,
+		OutboxPublisher:    outboxPublisher,
 	}
 	orderService, err := sf.Create()
 	if err != nil {
@@ -481,7 +617,7 @@ func createOrderService(
 	return orderService, nil
 }
 
-func createServices(db *mongo.Database, cfg config.Configuration, s3Service aws_s3.Service, sqsCli notifyQueue.SQSInterface, telegramService orderServicePkg.TelegramService, logger *zap.SugaredLogger, whatsapp clients.Whatsapp, orderCli order.Client, menuCli menu.Client, cognito *cognitoidentityprovider.CognitoIdentityProvider, ocBaseUrl string) (storeServicePkg.Service,
+func createServices(db *mongo.Database, cfg config.Configuration, s3Service aws_s3.Service, sqsCli notifyQueue.SQSInterface, telegramService orderServicePkg.TelegramService, logger *zap.SugaredLogger, whatsapp clients.Whatsapp, orderCli order.Client, menuCli menu.Client, cognito *cognitoidentityprovider.CognitoIdentityProvider, ocBaseUrl string, aggregatorRecorder *aggregator.Recorder, completedPublisher *orderServicePkg.Publisher) (storeServicePkg.Service,
 	stoplist.Service,
 	aggregator.Factory,
 	pos.Factory,
@@ -544,7 +680,8 @@ func createServices(db *mongo.Database, cfg config.Configuration, s3Service aws_
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
 
-	aggFactory, err := aggregator.NewFactory(
+	var aggFactory aggregator.Factory
+	aggFactory, err = aggregator.NewFactory(
 		cfg.WoltConfiguration.BaseURL,
 		cfg.GlovoConfiguration.BaseURL, cfg.GlovoConfiguration.Token,
 		cfg.TalabatConfiguration.MiddlewareBaseURL, cfg.TalabatConfiguration.MenuBaseUrl,
@@ -554,11 +691,23 @@ func createServices(db *mongo.Database, cfg config.Configuration, s3Service aws_
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
 
+	if aggregatorRecorder != nil {
+		aggFactory, err = aggregator.NewFakeFactory(aggFactory, aggregatorRecorder)
+		if err != nil {
+			return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
+		}
+	}
+
 	bkOfferRepository, err := mongo2.NewBKOfferRepository2(db)
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
 
+	sandboxOrderRepository, err := pos.NewMongoSandboxOrderRepository(db)
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
+	}
+
 	posFactory, err := pos.NewFactory(
 		anotherBillRepository, sqsCli, cfg.RetryConfiguration.QueueName,
 		cfg.IIKOConfiguration.BaseURL, cfg.IIKOConfiguration.TransportToFrontTimeout, cfg.PosterConfiguration.BaseURL,
@@ -566,6 +715,7 @@ func createServices(db *mongo.Database, cfg config.Configuration, s3Service aws_
 		cfg.JowiConfiguration.ApiKey, cfg.JowiConfiguration.ApiSecret, cfg.RKeeperConfiguration.RKeeperBaseURL, cfg.RKeeperConfiguration.RKeeperApiKey,
 		cfg.BurgerKingConfiguration.BaseURL, bkOfferRepository, cfg.RKeeper7XMLConfiguration.LicenseBaseURL,
 		cfg.SyrveConfiguration.BaseURL, cfg.YarosConfiguration.BaseURL, cfg.YarosConfiguration.InfoSystem, cfg.TillypadConfiguration.BaseUrl, cfg.Ytimes.BaseUrl, cfg.Ytimes.Token, cfg.PosistConfiguration.BaseUrl,
+		sandboxOrderRepository,
 	)
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
@@ -591,19 +741,30 @@ func createServices(db *mongo.Database, cfg config.Configuration, s3Service aws_
 		Password: cfg.RedisConfig.Password,
 	})
 
-	wppService, err := wppService.NewWhatsappService(whatsapp, cfg.WhatsAppConfiguration.Instance, cfg.WhatsAppConfiguration.AuthToken, cfg.WhatsAppConfiguration.BaseUrl, newsletterRepo, storeFactory, orderCli, storeGroupService, redisClient)
+	customerRepo, err := paymentRepository.NewCustomersMongoRepository(db)
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
 
-	cartRepo := orderServicePkg.NewCartRepository(db)
-	cartService := orderServicePkg.NewCartService(cartRepo)
+	customerProfileRepo, err := customer.NewMongoRepository(db)
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
+	}
 
-	paymentFactory, err := paymentServicePkg.NewFactory(cfg.IokaConfiguration.BaseUrl, cfg.IokaConfiguration.ApiKey, cfg.PaymeConfiguration.BaseUrl, cfg.PaymeConfiguration.ApiKey, cfg.WoopPayConfiguration.BaseUrl, cfg.WoopPayConfiguration.ResultUrl, wppService, cfg.KaspiSaleScoutConfiguration.BaseUrl, cfg.KaspiSaleScoutConfiguration.Token, cfg.KaspiSaleScoutConfiguration.MerchantID, logger, ocBaseUrl, cartService)
+	customerService, err := customer.NewService(customerProfileRepo, orderRepo, storeFactory, customerRepo)
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
-	customerRepo, err := paymentRepository.NewCustomersMongoRepository(db)
+
+	wppService, err := wppService.NewWhatsappService(whatsapp, cfg.WhatsAppConfiguration.Instance, cfg.WhatsAppConfiguration.AuthToken, cfg.WhatsAppConfiguration.BaseUrl, newsletterRepo, storeFactory, orderCli, storeGroupService, redisClient, customerService)
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
+	}
+
+	cartRepo := orderServicePkg.NewCartRepository(db)
+	cartService := orderServicePkg.NewCartService(cartRepo)
+
+	paymentFactory, err := paymentServicePkg.NewFactory(cfg.IokaConfiguration.BaseUrl, cfg.IokaConfiguration.ApiKey, cfg.PaymeConfiguration.BaseUrl, cfg.PaymeConfiguration.ApiKey, cfg.WoopPayConfiguration.BaseUrl, cfg.WoopPayConfiguration.ResultUrl, wppService, cfg.KaspiSaleScoutConfiguration.BaseUrl, cfg.KaspiSaleScoutConfiguration.Token, cfg.KaspiSaleScoutConfiguration.MerchantID, logger, ocBaseUrl, cartService)
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
@@ -616,7 +777,7 @@ func createServices(db *mongo.Database, cfg config.Configuration, s3Service aws_
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
 
-	kwaaka3pl, err := kwaaka_3pl.NewKwaaka3plService(sqsCli, cfg.Kwaaka3pl.Kwaaka3plQueue, orderRepo, storeFactory, cfg.Kwaaka3pl.Kwaaka3plBaseUrl, cfg.Kwaaka3pl.Kwaaka3plAuthToken, logger, telegramService, menuCli, deliveryRepo)
+	kwaaka3pl, err := kwaaka_3pl.NewKwaaka3plService(sqsCli, cfg.Kwaaka3pl.Kwaaka3plQueue, orderRepo, storeFactory, cfg.Kwaaka3pl.Kwaaka3plBaseUrl, cfg.Kwaaka3pl.Kwaaka3plAuthToken, logger, telegramService, menuCli, deliveryRepo, wppService, completedPublisher)
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
@@ -631,8 +792,6 @@ func createServices(db *mongo.Database, cfg config.Configuration, s3Service aws_
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
 
-	orderReport := order_report.NewOrderReportService(storeFactory, orderRepo, kwaaka3pl, cartService, storeGroupService)
-
 	restaurantSetRepo, err := restaurant_set.NewMongoRepository(db)
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
@@ -648,6 +807,8 @@ func createServices(db *mongo.Database, cfg config.Configuration, s3Service aws_
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
 	}
 
+	orderReport := order_report.NewOrderReportService(storeFactory, orderRepo, kwaaka3pl, cartService, storeGroupService, refundRepo)
+
 	errorSolutionRepo, err := errorSolutionsRepo.NewMongoRepository(db)
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, err
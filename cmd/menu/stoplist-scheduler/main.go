@@ -16,7 +16,6 @@ import (
 	storeDto "github.com/kwaaka-team/orders-core/pkg/store/dto"
 	"github.com/kwaaka-team/orders-core/service/entity_changes_history"
 	entityChangesHistoryModels "github.com/kwaaka-team/orders-core/service/entity_changes_history/models"
-	menuServicePkg "github.com/kwaaka-team/orders-core/service/menu"
 	"sync"
 )
 
@@ -58,7 +57,7 @@ func Run(ctx context.Context, sqsEvent events.SQSEvent) error {
 		return err
 	}
 
-	menuMan := managers.NewMenuManager(opts, ds, ds.MenuRepository(entityChangesHistoryRepo), ds.StoreRepository(), nil, nil, stopListMan, nil, nil, nil, nil, storeCli, nil, menuServicePkg.MongoRepository{}, ds.RestGroupMenuRepository())
+	menuMan := managers.NewMenuManager(opts, ds, ds.MenuRepository(entityChangesHistoryRepo), ds.StoreRepository(), nil, nil, stopListMan, nil, nil, nil, nil, storeCli, nil, nil, ds.RestGroupMenuRepository())
 
 	//init manager, write DRY logic code; update aggregatorMenu, POSMenu & aggregatorWEB
 	for idx, message := range sqsEvent.Records {
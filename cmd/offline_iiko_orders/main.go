@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/kwaaka-team/orders-core/cmd"
 	"github.com/kwaaka-team/orders-core/core/config"
 	"github.com/kwaaka-team/orders-core/core/offline_orders"
 	"github.com/kwaaka-team/orders-core/pkg/iiko/models"
+	"github.com/kwaaka-team/orders-core/service/customer"
 	_ "github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 )
@@ -51,7 +53,26 @@ func run(event events.SQSEvent) error {
 		offlineOrders = append(offlineOrders, offlineOrder)
 	}
 
-	srv := offline_orders.NewOfflineOrdersService(db)
+	coreDB, err := cmd.CreateMongo(ctx, opts.DSURL, opts.DSDB)
+	if err != nil {
+		log.Err(err).Msgf("error connect mongo message %s", err.Error())
+		return err
+	}
+
+	customerRepo, err := customer.NewMongoRepository(coreDB)
+	if err != nil {
+		return err
+	}
+
+	identityLinker, err := customer.NewIdentityLinker(customerRepo)
+	if err != nil {
+		return err
+	}
+
+	srv, err := offline_orders.NewOfflineOrdersService(db, identityLinker)
+	if err != nil {
+		return err
+	}
 
 	err = srv.SaveOrders(ctx, offlineOrders)
 	if err != nil {
@@ -3,12 +3,21 @@ package main
 import (
 	"context"
 	"github.com/aws/aws-lambda-go/lambda"
-	"github.com/kwaaka-team/orders-core/core/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/kwaaka-team/orders-core/cmd"
+	"github.com/kwaaka-team/orders-core/config/general"
+	"github.com/kwaaka-team/orders-core/core/managers/telegram"
+	"github.com/kwaaka-team/orders-core/pkg/que"
+	"github.com/kwaaka-team/orders-core/pkg/whatsapp"
+	"github.com/kwaaka-team/orders-core/pkg/whatsapp/clients"
 	"github.com/kwaaka-team/orders-core/service/order"
+	"github.com/kwaaka-team/orders-core/service/review"
+	"github.com/kwaaka-team/orders-core/service/sms"
+	"github.com/kwaaka-team/orders-core/service/store"
+	wppService "github.com/kwaaka-team/orders-core/service/whatsapp"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func main() {
@@ -17,19 +26,19 @@ func main() {
 func run() error {
 	ctx := context.Background()
 
-	opts, err := config.LoadConfig(ctx)
+	opts, err := general.LoadConfig(ctx)
 	if err != nil {
 		return err
 	}
 
-	db, err := createDB(ctx, opts)
+	db, err := cmd.CreateMongo(ctx, opts.DSURL, opts.DSDB)
 	if err != nil {
 		return err
 	}
 
 	log.Printf("[INFO] connected to %s", db.Name())
 
-	orderService, err := createOrderService(db)
+	orderService, err := createOrderService(db, opts)
 	if err != nil {
 		return err
 	}
@@ -44,7 +53,8 @@ func run() error {
 	return nil
 }
 
-func createOrderService(db *mongo.Database) (*order.ServiceImpl, error) {
+// createOrderService - закрытые кроном заказы передаются в review, как закрытые pos заказы в integration_api
+func createOrderService(db *mongo.Database, opts general.Configuration) (*order.ServiceImpl, error) {
 
 	if db == nil {
 		return nil, errors.New("db is nil")
@@ -55,7 +65,25 @@ func createOrderService(db *mongo.Database) (*order.ServiceImpl, error) {
 		return nil, err
 	}
 
-	orderService, err := order.NewServiceImpl(orderRepo)
+	storeRepo, err := store.NewStoreMongoRepository(db)
+	if err != nil {
+		return nil, err
+	}
+
+	storeService, err := store.NewService(storeRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewSubscriber, err := createReviewSubscriber(db, opts, orderRepo, storeService)
+	if err != nil {
+		return nil, err
+	}
+
+	completedPublisher := &order.Publisher{}
+	completedPublisher.AddSubscriber(reviewSubscriber)
+
+	orderService, err := order.NewAutoCloseService(orderRepo, storeService, completedPublisher)
 	if err != nil {
 		return nil, err
 	}
@@ -63,12 +91,44 @@ func createOrderService(db *mongo.Database) (*order.ServiceImpl, error) {
 	return orderService, nil
 }
 
-func createDB(ctx context.Context, cfg config.Configuration) (*mongo.Database, error) {
+func createReviewSubscriber(db *mongo.Database, opts general.Configuration, orderRepo order.Repository, storeService store.Service) (*review.RequestSubscriber, error) {
+	sqsCli := que.NewSQS(sqs.NewFromConfig(opts.AwsConfig))
+
+	telegramService, err := order.NewTelegramService(sqsCli, opts.QueConfiguration.Telegram, opts.NotificationConfiguration, telegram.NewTelegramRepo(db))
+	if err != nil {
+		return nil, err
+	}
+
+	whatsappClient, err := whatsapp.NewWhatsappClient(&clients.Config{
+		Instance:  opts.WhatsAppConfiguration.Instance,
+		AuthToken: opts.WhatsAppConfiguration.AuthToken,
+		BaseURL:   opts.WhatsAppConfiguration.BaseUrl,
+		Insecure:  true,
+		Protocol:  "http",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	whatsappService, err := wppService.NewWhatsappService(whatsappClient, opts.WhatsAppConfiguration.Instance, opts.WhatsAppConfiguration.AuthToken, opts.WhatsAppConfiguration.BaseUrl, nil, storeService, nil, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	smsService, err := sms.NewSmsService(opts.SmsLogin, opts.SmsPassword, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewRepo, err := review.NewMongoRepository(db)
+	if err != nil {
+		return nil, err
+	}
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.DSURL))
+	reviewService, err := review.NewService(reviewRepo, orderRepo, storeService, whatsappService, smsService, telegramService)
 	if err != nil {
 		return nil, err
 	}
 
-	return client.Database(cfg.DSDB), nil
+	return review.NewRequestSubscriber(reviewService)
 }
@@ -102,7 +102,8 @@ func Run(ctx context.Context) error {
 
 func hasStatusInStatusesHistory(status string, history []coreOrderModels.OrderStatusUpdate) bool {
 	for _, s := range history {
-		if s.Name == status {
+		// отклоненный state machine статус к заказу не применен
+		if s.Name == status && !s.Rejected {
 			return true
 		}
 	}
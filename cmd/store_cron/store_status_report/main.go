@@ -14,6 +14,7 @@ import (
 	"github.com/kwaaka-team/orders-core/service/aggregator"
 	"github.com/kwaaka-team/orders-core/service/menu"
 	"github.com/kwaaka-team/orders-core/service/order"
+	"github.com/kwaaka-team/orders-core/service/sla"
 	"github.com/kwaaka-team/orders-core/service/store"
 	"github.com/kwaaka-team/orders-core/service/store/repository/storeclosedtime"
 	storeGroupServicePkg "github.com/kwaaka-team/orders-core/service/storegroup"
@@ -86,11 +87,21 @@ func run() error {
 		return err
 	}
 
+	orderRepository, err := order.NewMongoRepository(db)
+	if err != nil {
+		return err
+	}
+
+	slaService, err := sla.NewService(orderRepository, storeFactory)
+	if err != nil {
+		return err
+	}
+
 	subject := &storeStatus.Subject{}
 
-	status, err := storeStatus.NewStoreStatus(aggFactory, storeFactory, subject, storeActiveTimeRepository, storeGroupService)
+	status, err := storeStatus.NewStoreStatusReport(aggFactory, storeFactory, subject, storeActiveTimeRepository, storeGroupService, slaService)
 	if err != nil {
-		return fmt.Errorf("main - fn run - fn NewStoreStatus - %w", err)
+		return fmt.Errorf("main - fn run - fn NewStoreStatusReport - %w", err)
 	}
 
 	subject.AddObserver(storeStatus.TelegramObserver{TelegramClient: telegramService})
@@ -0,0 +1,65 @@
+package managers
+
+import (
+	"context"
+	"log"
+
+	models2 "github.com/kwaaka-team/orders-core/core/auth/models"
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+	"github.com/pkg/errors"
+)
+
+// IdentityLinker - привязка пользователя авторизации к профилю клиента по телефону, реализуется сервисом клиентов
+type IdentityLinker interface {
+	LinkIdentity(ctx context.Context, phone string, identity coreModels.CustomerIdentity) error
+}
+
+// identityLinkingAuth привязывает пользователя к профилю клиента при создании и обновлении
+type identityLinkingAuth struct {
+	AuthManager
+	linker IdentityLinker
+}
+
+func NewIdentityLinkingAuthManager(manager AuthManager, linker IdentityLinker) (AuthManager, error) {
+	if manager == nil {
+		return nil, errors.New("auth manager is nil")
+	}
+	if linker == nil {
+		return nil, errors.New("identity linker is nil")
+	}
+
+	return &identityLinkingAuth{
+		AuthManager: manager,
+		linker:      linker,
+	}, nil
+}
+
+func (a *identityLinkingAuth) CreateUser(ctx context.Context, user models2.User) error {
+	if err := a.AuthManager.CreateUser(ctx, user); err != nil {
+		return err
+	}
+	a.linkIdentity(ctx, user)
+	return nil
+}
+
+func (a *identityLinkingAuth) UpdateUserInfo(ctx context.Context, user models2.User) error {
+	if err := a.AuthManager.UpdateUserInfo(ctx, user); err != nil {
+		return err
+	}
+	a.linkIdentity(ctx, user)
+	return nil
+}
+
+// linkIdentity - ошибка привязки не прерывает авторизацию
+func (a *identityLinkingAuth) linkIdentity(ctx context.Context, user models2.User) {
+	if user.UID == "" || user.PhoneNumber == "" {
+		return
+	}
+
+	if err := a.linker.LinkIdentity(ctx, user.PhoneNumber, coreModels.CustomerIdentity{
+		Source:     coreModels.CustomerIdentityAuthUser,
+		ExternalID: user.UID,
+	}); err != nil {
+		log.Printf("link auth user %s to customer profile error: %v", user.UID, err)
+	}
+}
@@ -20,6 +20,8 @@ type Configuration struct {
 	SecretEnvironments string `env:"Prod_Env" envDefault:"ProdEnvs"`
 	Stage              string `json:"stage" envDefault:"dev"`
 	TimeZone           string `json:"tz"`
+	// AggregatorSimulator - запросы в агрегаторы не отправляются, а записываются для cmd/aggregator_sim
+	AggregatorSimulator bool `json:"aggregator_simulator"`
 
 	DeliverooConfiguration
 	QueConfiguration
@@ -95,6 +97,7 @@ type Kwaaka3pl struct {
 type QueueUrls struct {
 	PaymentsQueueUrl         string `json:"payments_queue_url"`
 	WhatsappMessagesQueueUrl string `json:"whatsapp_messages_queue_url"`
+	AggregatorOutboxQueueUrl string `json:"aggregator_outbox_queue_url"`
 }
 type IokaConfiguration struct {
 	BaseUrl string `json:"ioka_base_url"`
@@ -16,10 +16,18 @@ const (
 	connectionTimeout = 3 * time.Second
 	ensureIdxTimeout  = 300 * time.Second
 
-	menuCollectionName        = "menus"
-	menuUploadTransactionName = "menu_upload_transactions"
-	orderCollectionName       = "orders"
-	bkOfferCollectionName     = "bk_offers"
+	menuCollectionName           = "menus"
+	menuUploadTransactionName    = "menu_upload_transactions"
+	orderCollectionName          = "orders"
+	bkOfferCollectionName        = "bk_offers"
+	orderClaimCollectionName     = "order_claims"
+	settlementCollectionName     = "settlement_statements"
+	promotionUsageCollectionName = "promotion_usages"
+	redemptionCollectionName     = "promo_code_redemptions"
+	sandboxOrderCollectionName   = "sandbox_orders"
+
+	// sandboxOrderTTL - заказы тестовой pos системы нужны только на время проверки флоу
+	sandboxOrderTTL = 7 * 24 * time.Hour
 )
 
 type Mongo struct {
@@ -137,6 +145,21 @@ func (m *Mongo) ensureIndexes() error {
 	if err := m.ensureOrderIndexes(ctx); err != nil {
 		return err
 	}
+	if err := m.ensureOrderClaimIndexes(ctx); err != nil {
+		return err
+	}
+	if err := m.ensureSettlementIndexes(ctx); err != nil {
+		return err
+	}
+	if err := m.ensurePromotionUsageIndexes(ctx); err != nil {
+		return err
+	}
+	if err := m.ensureRedemptionIndexes(ctx); err != nil {
+		return err
+	}
+	if err := m.ensureSandboxOrderIndexes(ctx); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -225,6 +248,204 @@ func (m *Mongo) ensureOrderIndexes(ctx context.Context) (err error) {
 	return err
 }
 
+func (m *Mongo) ensureOrderClaimIndexes(ctx context.Context) (err error) {
+	col := m.DB.Collection(orderClaimCollectionName)
+
+	existingIndexes, err := m.existingIndexes(ctx, col)
+	if err != nil {
+		return err
+	}
+
+	indexesMap := map[string]mongo.IndexModel{
+		"Unique Order Claim": {
+			Keys: bson.D{
+				{Key: "delivery_service", Value: 1},
+				{Key: "order_id", Value: 1},
+				{Key: "store_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	indexes := make([]mongo.IndexModel, 0, len(indexesMap))
+
+	for name, idx := range indexesMap {
+		if _, ok := existingIndexes[name]; ok {
+			continue
+		}
+
+		idx.Options.SetName(name)
+		indexes = append(indexes, idx)
+	}
+
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(m.ensureIdxTimeout)
+	_, err = col.Indexes().CreateMany(ctx, indexes, opts)
+
+	return err
+}
+
+func (m *Mongo) ensureSettlementIndexes(ctx context.Context) (err error) {
+	col := m.DB.Collection(settlementCollectionName)
+
+	existingIndexes, err := m.existingIndexes(ctx, col)
+	if err != nil {
+		return err
+	}
+
+	indexesMap := map[string]mongo.IndexModel{
+		"Unique Settlement Period": {
+			Keys: bson.D{
+				{Key: "legal_entity_id", Value: 1},
+				{Key: "period_from", Value: 1},
+				{Key: "period_to", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	indexes := make([]mongo.IndexModel, 0, len(indexesMap))
+
+	for name, idx := range indexesMap {
+		if _, ok := existingIndexes[name]; ok {
+			continue
+		}
+
+		idx.Options.SetName(name)
+		indexes = append(indexes, idx)
+	}
+
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(m.ensureIdxTimeout)
+	_, err = col.Indexes().CreateMany(ctx, indexes, opts)
+
+	return err
+}
+
+func (m *Mongo) ensurePromotionUsageIndexes(ctx context.Context) (err error) {
+	col := m.DB.Collection(promotionUsageCollectionName)
+
+	existingIndexes, err := m.existingIndexes(ctx, col)
+	if err != nil {
+		return err
+	}
+
+	indexesMap := map[string]mongo.IndexModel{
+		"Unique Promotion Usage": {
+			Keys: bson.D{
+				{Key: "customer_phone", Value: 1},
+				{Key: "promotion_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	indexes := make([]mongo.IndexModel, 0, len(indexesMap))
+
+	for name, idx := range indexesMap {
+		if _, ok := existingIndexes[name]; ok {
+			continue
+		}
+
+		idx.Options.SetName(name)
+		indexes = append(indexes, idx)
+	}
+
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(m.ensureIdxTimeout)
+	_, err = col.Indexes().CreateMany(ctx, indexes, opts)
+
+	return err
+}
+
+func (m *Mongo) ensureRedemptionIndexes(ctx context.Context) (err error) {
+	col := m.DB.Collection(redemptionCollectionName)
+
+	existingIndexes, err := m.existingIndexes(ctx, col)
+	if err != nil {
+		return err
+	}
+
+	// по заказу может быть только одно действующее списание промокода, откаченные не мешают новому
+	indexesMap := map[string]mongo.IndexModel{
+		"Unique Redeemed Order": {
+			Keys: bson.D{
+				{Key: "order_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.D{
+				{Key: "status", Value: "redeemed"},
+			}),
+		},
+	}
+
+	indexes := make([]mongo.IndexModel, 0, len(indexesMap))
+
+	for name, idx := range indexesMap {
+		if _, ok := existingIndexes[name]; ok {
+			continue
+		}
+
+		idx.Options.SetName(name)
+		indexes = append(indexes, idx)
+	}
+
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(m.ensureIdxTimeout)
+	_, err = col.Indexes().CreateMany(ctx, indexes, opts)
+
+	return err
+}
+
+func (m *Mongo) ensureSandboxOrderIndexes(ctx context.Context) (err error) {
+	col := m.DB.Collection(sandboxOrderCollectionName)
+
+	existingIndexes, err := m.existingIndexes(ctx, col)
+	if err != nil {
+		return err
+	}
+
+	indexesMap := map[string]mongo.IndexModel{
+		"Sandbox Order TTL": {
+			Keys: bson.D{
+				{Key: "created_at", Value: 1},
+			},
+			Options: options.Index().SetExpireAfterSeconds(int32(sandboxOrderTTL.Seconds())),
+		},
+	}
+
+	indexes := make([]mongo.IndexModel, 0, len(indexesMap))
+
+	for name, idx := range indexesMap {
+		if _, ok := existingIndexes[name]; ok {
+			continue
+		}
+
+		idx.Options.SetName(name)
+		indexes = append(indexes, idx)
+	}
+
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(m.ensureIdxTimeout)
+	_, err = col.Indexes().CreateMany(ctx, indexes, opts)
+
+	return err
+}
+
 func (m *Mongo) existingIndexes(ctx context.Context, collection *mongo.Collection) (map[string]struct{}, error) {
 	cur, err := collection.Indexes().List(ctx)
 	if err != nil {
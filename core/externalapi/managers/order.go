@@ -2,6 +2,7 @@ package managers
 
 import (
 	"context"
+	coreErrors "github.com/kwaaka-team/orders-core/core/errors"
 	"github.com/kwaaka-team/orders-core/core/externalapi/models"
 	"github.com/kwaaka-team/orders-core/core/externalapi/utils"
 	coreModels "github.com/kwaaka-team/orders-core/core/models"
@@ -14,8 +15,14 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+var (
+	ErrOrderNotFound  = errors.New("order not found")
+	ErrOrderForbidden = errors.New("order belongs to another store")
+)
+
 type OrderClient interface {
 	UpdateOrder(ctx context.Context, order models.Order, orderID, service, clientSecret string) error
+	UpdateOrderDetails(ctx context.Context, req models.Order, orderID, service, clientSecret string) (coreModels.Order, error)
 	GetOrder(ctx context.Context, orderID, service string) (models.Order, error)
 	CancelOrder(ctx context.Context, req models.CancelOrderRequest, id, service, clientSecret string) error
 	GetOrderStatus(ctx context.Context, orderID, service string) (models.OrderStatusResponse, error)
@@ -82,6 +89,57 @@ func (manager *OrderClientManager) UpdateOrder(ctx context.Context, req models.O
 	return nil
 }
 
+// UpdateOrderDetails сохраняет изменения заказа кроме состава: ресторан определяется по секрету клиента, заказ - по id из пути.
+// Возвращает заказ с новым составом из запроса, который применяется в pos дельтой
+func (manager *OrderClientManager) UpdateOrderDetails(ctx context.Context, req models.Order, orderID, service, clientSecret string) (coreModels.Order, error) {
+	store, err := manager.storeCli.FindStore(ctx, storeModels.StoreSelector{
+		DeliveryService: service,
+		ClientSecret:    clientSecret,
+	})
+	if err != nil {
+		log.Trace().Err(err).Msg("Can't find store by client secret")
+		return coreModels.Order{}, ErrOrderForbidden
+	}
+
+	stored, err := manager.orderCli.GetOrder(ctx, dto.OrderSelector{
+		ID: orderID,
+	})
+	if err != nil {
+		if errors.Is(err, coreErrors.ErrNotFound) {
+			return coreModels.Order{}, ErrOrderNotFound
+		}
+		return coreModels.Order{}, err
+	}
+
+	if stored.RestaurantID != store.ID || stored.DeliveryService != service {
+		return coreModels.Order{}, ErrOrderForbidden
+	}
+
+	updated, err := req.ToModel(store, service)
+	if err != nil {
+		return coreModels.Order{}, err
+	}
+
+	stored.Customer = updated.Customer
+	stored.DeliveryAddress = updated.DeliveryAddress
+	stored.SpecialRequirements = updated.SpecialRequirements
+	stored.AllergyInfo = updated.AllergyInfo
+	stored.Persons = updated.Persons
+	stored.PaymentMethod = updated.PaymentMethod
+	stored.EstimatedPickupTime = updated.EstimatedPickupTime
+	stored.IsPickedUpByCustomer = updated.IsPickedUpByCustomer
+	stored.Promos = updated.Promos
+
+	if err = manager.orderCli.UpdateOrder(ctx, stored); err != nil {
+		log.Trace().Err(err).Msg("Can't update order")
+		return coreModels.Order{}, err
+	}
+
+	updated.ID = orderID
+	updated.OrderID = stored.OrderID
+	return updated, nil
+}
+
 func (manager *OrderClientManager) CancelOrder(ctx context.Context, req models.CancelOrderRequest, id, service, clientSecret string) error {
 	_, err := manager.storeCli.FindStore(ctx, storeModels.StoreSelector{
 		DeliveryService: service,
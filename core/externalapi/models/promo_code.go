@@ -25,6 +25,20 @@ type PromoCode struct {
 	ForAllProduct     bool             `json:"for_all_product" bson:"for_all_product"`
 	Product           []models.Product `json:"products" bson:"products"`
 	IsDeleted         bool             `json:"is_deleted" bson:"is_deleted"`
+	// лимиты промокода, 0 - без ограничений, дневные лимиты считаются по дню ресторана заказа
+	TotalUsageLimit int     `json:"total_usage_limit" bson:"total_usage_limit"`
+	DailyUsageLimit int     `json:"daily_usage_limit" bson:"daily_usage_limit"`
+	TotalBudget     float64 `json:"total_budget" bson:"total_budget"`
+	DailyBudget     float64 `json:"daily_budget" bson:"daily_budget"`
+	// счетчики списаний, меняются только атомарно при списании и откате
+	RedemptionsCount int                            `json:"redemptions_count" bson:"redemptions_count"`
+	DiscountSpent    float64                        `json:"discount_spent" bson:"discount_spent"`
+	DailyUsages      map[string]PromoCodeDailyUsage `json:"daily_usages,omitempty" bson:"daily_usages,omitempty"`
+}
+
+type PromoCodeDailyUsage struct {
+	Count    int     `json:"count" bson:"count"`
+	Discount float64 `json:"discount" bson:"discount"`
 }
 
 type UserAndPromoCode struct {
@@ -71,6 +85,10 @@ type UpdatePromoCode struct {
 	ForAllProduct     *bool             `json:"for_all_product"`
 	Product           *[]models.Product `json:"products"`
 	IsDeleted         *bool             `json:"is_deleted"`
+	TotalUsageLimit   *int              `json:"total_usage_limit"`
+	DailyUsageLimit   *int              `json:"daily_usage_limit"`
+	TotalBudget       *float64          `json:"total_budget"`
+	DailyBudget       *float64          `json:"daily_budget"`
 }
 
 type UserPromoCodeUsageTimeRequest struct {
@@ -78,3 +96,81 @@ type UserPromoCodeUsageTimeRequest struct {
 	PromoCodeValue string `json:"promo_code"`
 	RestaurantId   string `json:"restaurant_id"`
 }
+
+const (
+	PromoCodeRedemptionRedeemed   = "redeemed"
+	PromoCodeRedemptionRolledBack = "rolled_back"
+)
+
+// PromoCodeRedemption - списание промокода по заказу, при отмене или полном возврате заказа откатывается
+type PromoCodeRedemption struct {
+	ID           string `json:"id" bson:"_id,omitempty"`
+	PromoCodeID  string `json:"promo_code_id" bson:"promo_code_id"`
+	PromoCode    string `json:"promo_code" bson:"promo_code"`
+	RestaurantID string `json:"restaurant_id" bson:"restaurant_id"`
+	OrderID      string `json:"order_id" bson:"order_id"`
+	UserId       string `json:"user_id" bson:"user_id"`
+	Phone        string `json:"phone" bson:"phone"`
+	DeviceID     string `json:"device_id" bson:"device_id"`
+	// Day - день списания во времени ресторана, ключ daily_usages промокода
+	Day        string  `json:"day" bson:"day"`
+	OrderTotal float64 `json:"order_total" bson:"order_total"`
+	Discount   float64 `json:"discount" bson:"discount"`
+	// IsNewCustomer - у телефона не было заказов в ресторане до списания
+	IsNewCustomer  bool       `json:"is_new_customer" bson:"is_new_customer"`
+	Status         string     `json:"status" bson:"status"`
+	RollbackReason string     `json:"rollback_reason,omitempty" bson:"rollback_reason,omitempty"`
+	CreatedAt      time.Time  `json:"created_at" bson:"created_at"`
+	RolledBackAt   *time.Time `json:"rolled_back_at,omitempty" bson:"rolled_back_at,omitempty"`
+}
+
+type RedeemPromoCodeRequest struct {
+	ValidateUserPromoCode
+	OrderID  string `json:"order_id" binding:"required"`
+	Phone    string `json:"phone"`
+	DeviceID string `json:"device_id"`
+}
+
+type RollbackPromoCodeRedemptionRequest struct {
+	Reason string `json:"reason"`
+}
+
+type PromoCodeAnalyticsRequest struct {
+	RestaurantGroupID string    `json:"restaurant_group_id" binding:"required"`
+	StartDate         time.Time `json:"start_date" binding:"required"`
+	EndDate           time.Time `json:"end_date" binding:"required"`
+}
+
+// PromoCodeAnalytics - revenue - выручка заказов со списанием за вычетом скидки,
+// incremental_revenue - выручка заказов новых клиентов за вычетом стоимости всех скидок
+type PromoCodeAnalytics struct {
+	PromoCodeID         string  `json:"promo_code_id,omitempty"`
+	Code                string  `json:"code,omitempty"`
+	Name                string  `json:"name,omitempty"`
+	Redemptions         int     `json:"redemptions"`
+	RolledBack          int     `json:"rolled_back"`
+	DiscountCost        float64 `json:"discount_cost"`
+	Revenue             float64 `json:"revenue"`
+	NewCustomers        int     `json:"new_customers"`
+	NewCustomersRevenue float64 `json:"new_customers_revenue"`
+	IncrementalRevenue  float64 `json:"incremental_revenue"`
+}
+
+// PromoCodeAbuse - телефон или устройство, с которого промокоды списывались на несколько пользователей
+type PromoCodeAbuse struct {
+	IdentityType string   `json:"identity_type"`
+	Identity     string   `json:"identity"`
+	UserIDs      []string `json:"user_ids"`
+	PromoCodes   []string `json:"promo_codes"`
+	Redemptions  int      `json:"redemptions"`
+	Discount     float64  `json:"discount"`
+}
+
+type PromoCodeAnalyticsResponse struct {
+	RestaurantGroupID string               `json:"restaurant_group_id"`
+	StartDate         time.Time            `json:"start_date"`
+	EndDate           time.Time            `json:"end_date"`
+	PromoCodes        []PromoCodeAnalytics `json:"promo_codes"`
+	Total             PromoCodeAnalytics   `json:"total"`
+	Abuses            []PromoCodeAbuse     `json:"abuses"`
+}
@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/core/integration_api/resources/v1/dto"
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+)
+
+// GetAggregatorCommissions
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting commission contracts of restaurant with aggregators
+//	@Security	ApiKeyAuth
+//	@Param		store_id	path		string	true	"store_id"
+//	@Success	200			{object}	dto.AggregatorCommissionsRequest
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/aggregator-commissions/{store_id} [get]
+func (server *Server) GetAggregatorCommissions(c *gin.Context) {
+	store, err := server.storeService.GetByID(c.Request.Context(), c.Param("store_id"))
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.AggregatorCommissionsRequest{Contracts: store.AggregatorCommissions})
+}
+
+// UpdateAggregatorCommissions
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for replacing commission contracts of restaurant with aggregators
+//	@Security	ApiKeyAuth
+//	@Summary	Commission is calculated and saved on every new order of aggregator with contract
+//	@Param		store_id	path	string								true	"store_id"
+//	@Param		request		body	dto.AggregatorCommissionsRequest	true	"request"
+//	@Success	204
+//	@Failure	400	{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/aggregator-commissions/{store_id} [put]
+func (server *Server) UpdateAggregatorCommissions(c *gin.Context) {
+	var req dto.AggregatorCommissionsRequest
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	if err := server.storeService.UpdateAggregatorCommissions(c.Request.Context(), c.Param("store_id"), req.Contracts); err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AggregatorCommissionAnalytics
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for comparing aggregators commission and net revenue of restaurant group for period
+//	@Security	ApiKeyAuth
+//	@Param		request	body		coreModels.AggregatorCommissionAnalyticsRequest	true	"request"
+//	@Success	200		{object}	coreModels.AggregatorCommissionAnalyticsResponse
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/aggregator-commissions/analytics [post]
+func (server *Server) AggregatorCommissionAnalytics(c *gin.Context) {
+	var req coreModels.AggregatorCommissionAnalyticsRequest
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	res, err := server.orderReport.AggregatorCommissionAnalytics(c.Request.Context(), req)
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// AggregatorCommissionAnalyticsToXlsx
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for exporting aggregators commission analytics to xlsx
+//	@Security	ApiKeyAuth
+//	@Param		request	body		coreModels.AggregatorCommissionAnalyticsRequest	true	"request"
+//	@Success	200		{object}	[]byte
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/aggregator-commissions/analytics/xlsx [post]
+func (server *Server) AggregatorCommissionAnalyticsToXlsx(c *gin.Context) {
+	var req coreModels.AggregatorCommissionAnalyticsRequest
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	res, err := server.orderReport.AggregatorCommissionAnalyticsToXlsx(c.Request.Context(), req)
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=AggregatorCommissions.xlsx")
+	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", res)
+}
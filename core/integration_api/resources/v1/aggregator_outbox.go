@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/core/integration_api/resources/v1/dto"
+	"github.com/kwaaka-team/orders-core/service/order/outbox"
+	"net/http"
+)
+
+func (server *Server) DispatchAggregatorOutbox(c *gin.Context) {
+	var req dto.DispatchAggregatorOutboxRequest
+
+	if c.Request.ContentLength > 0 {
+		if err := c.BindJSON(&req); err != nil {
+			server.Logger.Infof(errBindBody, err.Error())
+			c.Set(errorKey, err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{
+				Msg: err.Error(),
+			})
+			return
+		}
+	}
+
+	if len(req.OrderIDs) == 0 {
+		if err := server.aggregatorOutboxService.DispatchDue(c.Request.Context()); err != nil {
+			server.Logger.Error(err)
+			c.Set(errorKey, err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, errors.ErrorResponse{Msg: err.Error()})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	for _, orderID := range req.OrderIDs {
+		if err := server.aggregatorOutboxService.DispatchOrder(c.Request.Context(), orderID); err != nil {
+			server.Logger.Errorf("dispatch aggregator outbox, order_id=%s: %s", orderID, err.Error())
+		}
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetAggregatorOutboxMessages docs
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting aggregator status outbox messages
+//	@Security	ApiKeyAuth
+//	@Summary	Method for getting aggregator status outbox messages
+//	@Param		query	body		outbox.ListQuery	true	"query"
+//	@Success	200		{object}	dto.AggregatorOutboxListResponse
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Failure	500		{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/aggregator-outbox/list [post]
+func (server *Server) GetAggregatorOutboxMessages(c *gin.Context) {
+	var req outbox.ListQuery
+
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{
+			Msg: err.Error(),
+		})
+		return
+	}
+
+	messages, total, err := server.aggregatorOutboxService.ListMessages(c.Request.Context(), req)
+	if err != nil {
+		server.Logger.Error(err)
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.AggregatorOutboxListResponse{
+		Messages: messages,
+		Total:    total,
+	})
+}
+
+// RequeueAggregatorOutboxMessage docs
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for resending dead letter aggregator status
+//	@Security	ApiKeyAuth
+//	@Summary	Method for resending dead letter aggregator status
+//	@Param		message_id	path	string	true	"message_id"
+//	@Success	204
+//	@Failure	400	{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/aggregator-outbox/{message_id}/requeue [post]
+func (server *Server) RequeueAggregatorOutboxMessage(c *gin.Context) {
+	messageID := c.Param("message_id")
+
+	if err := server.aggregatorOutboxService.Requeue(c.Request.Context(), messageID); err != nil {
+		server.Logger.Error(err)
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"net/http"
+)
+
+const errAggregatorSimulatorDisabled = "aggregator simulator is disabled"
+
+// GetAggregatorSimulatorCalls docs
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting captured aggregator requests
+//	@Security	ApiKeyAuth
+//	@Summary	Method for getting captured aggregator requests
+//	@Success	200	{object}	[]aggregator.Call
+//	@Failure	404	{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/aggregator-simulator/calls [get]
+func (server *Server) GetAggregatorSimulatorCalls(c *gin.Context) {
+	if server.aggregatorRecorder == nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, errors.ErrorResponse{Msg: errAggregatorSimulatorDisabled})
+		return
+	}
+
+	c.JSON(http.StatusOK, server.aggregatorRecorder.Calls())
+}
+
+func (server *Server) ResetAggregatorSimulator(c *gin.Context) {
+	if server.aggregatorRecorder == nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, errors.ErrorResponse{Msg: errAggregatorSimulatorDisabled})
+		return
+	}
+
+	server.aggregatorRecorder.Reset()
+
+	c.Status(http.StatusNoContent)
+}
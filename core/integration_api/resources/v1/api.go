@@ -41,15 +41,14 @@ func (server *Server) SetMarkUpToAggregatorMenu(c *gin.Context) {
 		return
 	}
 
-	markupPercent := store.GetMenuMarkupPercent(req.MenuId)
-	if markupPercent == 0 {
-		server.Logger.Error("restaurant.menus.markup_percent is 0")
-		c.Set(errorKey, "restaurant.menus.markup_percent is 0")
-		c.AbortWithStatusJSON(http.StatusBadRequest, "restaurant.menus.markup_percent is 0")
+	if len(store.MarkupRules) == 0 && store.GetMenuMarkupPercent(req.MenuId) == 0 {
+		server.Logger.Error("restaurant.menus.markup_percent is 0 and restaurant.markup_rules are empty")
+		c.Set(errorKey, "restaurant.menus.markup_percent is 0 and restaurant.markup_rules are empty")
+		c.AbortWithStatusJSON(http.StatusBadRequest, "restaurant.menus.markup_percent is 0 and restaurant.markup_rules are empty")
 		return
 	}
 
-	if err = server.menuService.SetMarkupToAggregatorMenu(c.Request.Context(), req.MenuId, store.MenuID, store.Settings.Currency, markupPercent); err != nil {
+	if err = server.menuService.SetMarkupToAggregatorMenu(c.Request.Context(), store, req.MenuId); err != nil {
 		server.Logger.Error(err)
 		c.Set(errorKey, err.Error())
 		c.AbortWithStatusJSON(http.StatusBadRequest, err.Error())
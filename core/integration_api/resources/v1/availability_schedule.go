@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/service/availability_schedule/models"
+)
+
+// CreateAvailabilitySchedule
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for creating recurring availability schedule
+//	@Security	ApiKeyAuth
+//	@Summary	Products, sections or attribute groups of pos menu are available only inside windows, time is in store timezone
+//	@Param		schedule	body		models.Schedule	true	"schedule"
+//	@Success	200			{string}	string
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/availability-schedules [post]
+func (server *Server) CreateAvailabilitySchedule(c *gin.Context) {
+	var req models.Schedule
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	id, err := server.availabilityScheduleService.Create(c.Request.Context(), req)
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, id)
+}
+
+// UpdateAvailabilitySchedule
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for updating availability schedule
+//	@Security	ApiKeyAuth
+//	@Param		schedule_id	path		string			true	"schedule_id"
+//	@Param		schedule	body		models.Schedule	true	"schedule"
+//	@Success	204
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/availability-schedules/{schedule_id} [put]
+func (server *Server) UpdateAvailabilitySchedule(c *gin.Context) {
+	var req models.Schedule
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+	req.ID = c.Param("schedule_id")
+
+	if err := server.availabilityScheduleService.Update(c.Request.Context(), req); err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteAvailabilitySchedule
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for deleting availability schedule, products stopped by it become available
+//	@Security	ApiKeyAuth
+//	@Param		schedule_id	path	string	true	"schedule_id"
+//	@Success	204
+//	@Failure	400	{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/availability-schedules/{schedule_id} [delete]
+func (server *Server) DeleteAvailabilitySchedule(c *gin.Context) {
+	if err := server.availabilityScheduleService.Delete(c.Request.Context(), c.Param("schedule_id")); err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetAvailabilitySchedules
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting availability schedules of store
+//	@Security	ApiKeyAuth
+//	@Param		store_id	path		string	true	"store_id"
+//	@Success	200			{array}		models.Schedule
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/availability-schedules/store/{store_id} [get]
+func (server *Server) GetAvailabilitySchedules(c *gin.Context) {
+	schedules, err := server.availabilityScheduleService.GetByStoreID(c.Request.Context(), c.Param("store_id"))
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// PreviewAvailabilitySchedules
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for preview of availability at given time
+//	@Security	ApiKeyAuth
+//	@Param		store_id	path		string	true	"store_id"
+//	@Param		at			query		string	false	"time in RFC3339, now by default"
+//	@Success	200			{object}	models.Preview
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/availability-schedules/store/{store_id}/preview [get]
+func (server *Server) PreviewAvailabilitySchedules(c *gin.Context) {
+	at := time.Now().UTC()
+	if query := c.Query("at"); query != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, query); err != nil {
+			c.Set(errorKey, err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: errGetQuery + ": " + err.Error()})
+			return
+		}
+	}
+
+	preview, err := server.availabilityScheduleService.Preview(c.Request.Context(), c.Param("store_id"), at)
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+func (server *Server) ApplyAvailabilitySchedules(c *gin.Context) {
+	results, err := server.availabilityScheduleService.Apply(c.Request.Context(), time.Now().UTC())
+	if err != nil {
+		server.Logger.Error(err)
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
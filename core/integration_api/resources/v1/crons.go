@@ -58,6 +58,31 @@ func (server *Server) UpdateStopListByPosTypes(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+func (server *Server) ReconcileStopListByPosTypes(c *gin.Context) {
+	var req dto.ReconcileStopListCronRequest
+
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Info(errBindBody)
+		c.Set(errorKey, fmt.Errorf("[RECONCILE STOPLIST CRON] %w", err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{
+			Msg: err.Error(),
+		})
+		return
+	}
+
+	reports, err := server.stopListService.ReconcileStopListByPosTypes(c.Request.Context(), req.PosTypes, req.Heal)
+	if err != nil {
+		server.Logger.Error(err)
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errors.ErrorResponse{
+			Msg: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
 func (server *Server) UpdateStopListBySection(c *gin.Context) {
 	var req dto.UpdateStopListBySectionCronRequest
 
@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	"github.com/kwaaka-team/orders-core/core/models"
+	"github.com/kwaaka-team/orders-core/core/models/selector"
+	errorsPkg "github.com/pkg/errors"
+)
+
+// GetCustomerProfile
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting customer profile in restaurant group
+//	@Security	ApiKeyAuth
+//	@Summary	Phone in any format, profile contains lifetime value, favourite items, saved addresses and cards
+//	@Param		phone				path		string	true	"phone"
+//	@Param		restaurant_group_id	query		string	true	"restaurant_group_id"
+//	@Success	200					{object}	models.CustomerGroupProfile
+//	@Failure	400					{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/customers/{phone}/profile [get]
+func (server *Server) GetCustomerProfile(c *gin.Context) {
+	profile, err := server.customerService.GetProfile(c.Request.Context(), c.Param("phone"), c.Query("restaurant_group_id"))
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// GetCustomerProfileByIdentity
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting customer profile by linked identity
+//	@Security	ApiKeyAuth
+//	@Param		source				path		string	true	"source"
+//	@Param		external_id			path		string	true	"external_id"
+//	@Param		restaurant_group_id	query		string	true	"restaurant_group_id"
+//	@Success	200					{object}	models.CustomerGroupProfile
+//	@Failure	400					{object}	errors.ErrorResponse
+//	@Failure	404					{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/customers/identities/{source}/{external_id}/profile [get]
+func (server *Server) GetCustomerProfileByIdentity(c *gin.Context) {
+	identity := models.CustomerIdentity{
+		Source:     c.Param("source"),
+		ExternalID: c.Param("external_id"),
+	}
+
+	profile, err := server.customerService.GetProfileByIdentity(c.Request.Context(), identity, c.Query("restaurant_group_id"))
+	if err != nil {
+		c.Set(errorKey, err)
+		if errorsPkg.Is(err, drivers.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, errors.ErrorResponse{Msg: err.Error()})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// GetCustomerOrders
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting customer order history in restaurant group
+//	@Security	ApiKeyAuth
+//	@Param		phone				path		string	true	"phone"
+//	@Param		restaurant_group_id	query		string	true	"restaurant_group_id"
+//	@Param		page				query		string	false	"page"
+//	@Param		limit				query		string	false	"limit"
+//	@Success	200					{object}	models.CustomerOrderHistoryResponse
+//	@Failure	400					{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/customers/{phone}/orders [get]
+func (server *Server) GetCustomerOrders(c *gin.Context) {
+	page, limit, err := parsePaging(c)
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	orders, total, err := server.customerService.GetOrderHistory(c.Request.Context(), c.Param("phone"), c.Query("restaurant_group_id"), selector.Pagination{
+		Page:  page,
+		Limit: limit,
+	})
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CustomerOrderHistoryResponse{
+		Orders: orders,
+		Total:  total,
+	})
+}
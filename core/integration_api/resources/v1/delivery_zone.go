@@ -0,0 +1,47 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/core/integration_api/resources/v1/dto"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	pkgErrors "github.com/pkg/errors"
+)
+
+// QuoteDelivery
+//
+//	@Tags		qrmenu, kwaaka-admin
+//	@Title		Method for quoting delivery price by restaurant polygons
+//	@Security	ApiKeyAuth
+//	@Summary	Polygon with highest priority containing the address is used, basket is order total without delivery
+//	@Param		restaurant_id	path		string						true	"restaurant_id"
+//	@Param		request			body		dto.DeliveryQuoteRequest	true	"request"
+//	@Success	200				{object}	models.DeliveryQuote
+//	@Failure	400				{object}	errors.ErrorResponse
+//	@Failure	422				{object}	errors.ErrorResponse
+//	@Router		/v1/qr-menu/delivery-quote/{restaurant_id} [post]
+//	@Router		/v1/kwaaka-admin/delivery-quote/{restaurant_id} [post]
+func (server *Server) QuoteDelivery(c *gin.Context) {
+	var req dto.DeliveryQuoteRequest
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	quote, err := server.storeService.QuoteDelivery(c.Request.Context(), c.Param("restaurant_id"), req.Coordinates, req.Basket)
+	if err != nil {
+		c.Set(errorKey, err)
+		if pkgErrors.Is(err, storeModels.ErrOutOfDeliveryZone) {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, errors.ErrorResponse{Msg: err.Error()})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
@@ -8,6 +8,11 @@ type UpdateStopListCronRequest struct {
 	PosTypes []string `json:"pos_types"`
 }
 
+type ReconcileStopListCronRequest struct {
+	PosTypes []string `json:"pos_types"`
+	Heal     bool     `json:"heal"`
+}
+
 type UpdateStopListBySectionCronRequest struct {
 	WoltSectionIDs    []string `json:"wolt_ids"`
 	GlovoSectionIDs   []string `json:"glovo_ids"`
@@ -19,3 +24,7 @@ type UpdateStopListBySectionCronRequest struct {
 type SendDeferSumbissionRequest struct {
 	RestaurantGroupId string `json:"restaurant_group_id"`
 }
+
+type DispatchAggregatorOutboxRequest struct {
+	OrderIDs []string `json:"order_ids"`
+}
@@ -1,11 +1,85 @@
 package dto
 
+import (
+	menuModels "github.com/kwaaka-team/orders-core/core/menu/models"
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/order/outbox"
+	refundModels "github.com/kwaaka-team/orders-core/service/refund/models"
+)
+
 type UpsertMenuRequest struct {
 	StoreId string `json:"store_id"`
 }
 
+type UpdateOrderItemsRequest struct {
+	DeliveryService string                    `json:"delivery_service" binding:"required"`
+	Products        []coreModels.OrderProduct `json:"products" binding:"required"`
+}
+
+type RefundOrderLinesRequest struct {
+	Reason string                           `json:"reason"`
+	Lines  []refundModels.RefundLineRequest `json:"lines" binding:"required"`
+}
+
 type BusyModeRequest struct {
 	RestaurantID   string `json:"restaurant_id"`
 	BusyMode       bool   `json:"busy_mode"`
 	BusyModeMinute int    `json:"busy_mode_minute"`
 }
+
+type AggregatorOutboxListResponse struct {
+	Messages []outbox.Message `json:"messages"`
+	Total    int64            `json:"total"`
+}
+
+type MenuVersionsResponse struct {
+	Versions []menuModels.MenuVersion `json:"versions"`
+	Total    int64                    `json:"total"`
+}
+
+type MenuRollbackRequest struct {
+	Upload   bool   `json:"upload"`
+	UserRole string `json:"user_role"`
+	UserName string `json:"user_name"`
+}
+
+type MenuRollbackResponse struct {
+	TransactionID string `json:"transaction_id,omitempty"`
+}
+
+type MenuDryRunRequest struct {
+	StoreID  string `json:"store_id" binding:"required"`
+	MenuID   string `json:"menu_id" binding:"required"`
+	Delivery string `json:"delivery"`
+	UserRole string `json:"user_role"`
+}
+
+type MarkupRulesRequest struct {
+	Rules storeModels.MarkupRules `json:"rules"`
+}
+
+type AggregatorCommissionsRequest struct {
+	Contracts storeModels.AggregatorCommissionContracts `json:"contracts"`
+}
+
+// MarkupPreviewRequest - без rules превью считается по сохраненным правилам ресторана
+type MarkupPreviewRequest struct {
+	MenuID string                  `json:"menu_id" binding:"required"`
+	Rules  storeModels.MarkupRules `json:"rules"`
+}
+
+type DeliveryQuoteRequest struct {
+	Coordinates storeModels.Coordinates `json:"coordinates" binding:"required"`
+	Basket      float64                 `json:"basket"`
+}
+
+type ProductMatchSuggestionsResponse struct {
+	Suggestions []menuModels.ProductMatchSuggestion `json:"suggestions"`
+}
+
+type AcceptProductMatchesRequest struct {
+	StoreID  string                    `json:"store_id" binding:"required"`
+	Matches  []menuModels.ProductMatch `json:"matches" binding:"required"`
+	UserName string                    `json:"user_name"`
+}
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/kwaaka-team/orders-core/core/errors"
+	externalManagers "github.com/kwaaka-team/orders-core/core/externalapi/managers"
 	"github.com/kwaaka-team/orders-core/core/externalapi/models"
 	"github.com/kwaaka-team/orders-core/core/externalapi/resources/http/v1/dto"
 	"github.com/kwaaka-team/orders-core/core/externalapi/utils"
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+	errorsPkg "github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"net/http"
 	"strings"
@@ -235,6 +238,8 @@ func (server *Server) CancelOrder(c *gin.Context) {
 //	@Success	200				{object}	models.Order
 //	@Failure	401				{object}	[]errors.ErrorResponse
 //	@Failure	400				{object}	[]errors.ErrorResponse
+//	@Failure	403				{object}	[]errors.ErrorResponse
+//	@Failure	404				{object}	[]errors.ErrorResponse
 //	@Failure	500				{object}	[]errors.ErrorResponse
 //	@Router		/v1/order/{order_id} [put]
 func (server *Server) UpdateOrder(c *gin.Context) {
@@ -277,8 +282,31 @@ func (server *Server) UpdateOrder(c *gin.Context) {
 		return
 	}
 
-	err := server.externalOrderManager.UpdateOrder(c.Request.Context(), req, orderID, service, clientSecret)
+	var err error
+	switch service {
+	case coreModels.YANDEX.String():
+		err = server.updateYandexOrder(c, req, orderID, service, clientSecret)
+	default:
+		err = server.externalOrderManager.UpdateOrder(c.Request.Context(), req, orderID, service, clientSecret)
+	}
 	if err != nil {
+		switch {
+		case errorsPkg.Is(err, externalManagers.ErrOrderNotFound):
+			c.Set(errorKey, fmt.Sprintf("update order error: %s", err.Error()))
+			c.AbortWithStatusJSON(http.StatusNotFound, []errors.ErrorResponse{{
+				Code:        http.StatusNotFound,
+				Description: err.Error(),
+			}})
+			return
+		case errorsPkg.Is(err, externalManagers.ErrOrderForbidden):
+			c.Set(errorKey, fmt.Sprintf("update order error: %s", err.Error()))
+			c.AbortWithStatusJSON(http.StatusForbidden, []errors.ErrorResponse{{
+				Code:        http.StatusForbidden,
+				Description: err.Error(),
+			}})
+			return
+		}
+
 		server.Logger.Infof("update order error: %s", err.Error())
 		c.Set(errorKey, fmt.Sprintf("update order error: %s", err.Error()))
 		c.AbortWithStatusJSON(http.StatusBadRequest, []errors.ErrorResponse{{
@@ -293,6 +321,18 @@ func (server *Server) UpdateOrder(c *gin.Context) {
 	})
 }
 
+// updateYandexOrder у яндекса изменение заказа приходит вместе с составом: детали заказа сохраняются как раньше,
+// а состав применяется в pos дельтой. Ресторан берется из секрета клиента, заказ - из пути
+func (server *Server) updateYandexOrder(c *gin.Context, req models.Order, orderID, service, clientSecret string) error {
+	order, err := server.externalOrderManager.UpdateOrderDetails(c.Request.Context(), req, orderID, service, clientSecret)
+	if err != nil {
+		return err
+	}
+
+	_, err = server.orderModificationService.UpdateOrderItemsByAggregator(c.Request.Context(), order.OrderID, service, order.Products)
+	return err
+}
+
 // GetOrderStatus docs
 //
 //	@Tags		external
@@ -86,6 +86,41 @@ func (server *Server) CreateOrderGlovo(c *gin.Context) {
 	c.JSON(http.StatusOK, res.ID)
 }
 
+// UpdateOrderGlovo docs
+//
+//	@Tags		glovo
+//	@Title		Method for update order items
+//	@Security	ApiKeyAuth
+//	@Summary	Method applies changed products of Order in pos
+//	@Param		order	body		models.Order	true	"order"
+//	@Failure	401		{object}	errors.ErrorResponse
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Failure	500		{object}	errors.ErrorResponse
+//	@Router		/glovo/updateOrder [post]
+func (server *Server) UpdateOrderGlovo(c *gin.Context) {
+	var req models.Order
+
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{
+			Msg: err.Error(),
+		})
+		return
+	}
+
+	if _, err := server.orderModificationService.UpdateOrderItemsByAggregatorRequest(c.Request.Context(), req.StoreID, models.GLOVO.String(), req); err != nil {
+		server.Logger.Infof("update order items error: %s", err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{
+			Msg: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
 // CancelOrderGlovo docs
 //
 //	@Tags		glovo
@@ -6,6 +6,8 @@ import (
 	err2 "github.com/kwaaka-team/orders-core/core/errors"
 	"github.com/kwaaka-team/orders-core/core/models"
 	"github.com/kwaaka-team/orders-core/domain/logger"
+	models2 "github.com/kwaaka-team/orders-core/service/kwaaka_3pl/models"
+	"github.com/pkg/errors"
 	"net/http"
 )
 
@@ -85,6 +87,57 @@ func (server *Server) CancelOrderDispatcher(c *gin.Context) {
 	c.JSON(http.StatusOK, "")
 }
 
+// DispatchBestProvider docs
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for dispatching order to best 3pl provider
+//	@Security	ApiKeyAuth
+//	@Summary	Providers are ranked by quoted price and ETA with weights from restaurant kwaaka_3pl.dispatch
+//	@Param		order_id	path		string	true	"order_id"
+//	@Success	200			{object}	models.DispatchResult
+//	@Failure	500			{object}	errors.ErrorResponse
+//	@Router		/kwaaka-admin/dispatch/{order_id} [post]
+func (server *Server) DispatchBestProvider(c *gin.Context) {
+	res, err := server.orderKwaaka3plService.DispatchBestProvider(c.Request.Context(), c.Param("order_id"))
+	if err != nil {
+		server.Logger.Errorf("dispatch order to best 3pl provider error: %s", err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err2.ErrorResponse{
+			Msg: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// GetDeliveryTracking docs
+//
+//	@Tags		qrmenu
+//	@Title		Method for customer tracking of 3pl delivery
+//	@Security	ApiKeyAuth
+//	@Summary	Delivery is actualized with 3pl before response, eta is empty for finished delivery, courier phone is masked
+//	@Param		order_id	path		string	true	"order_id"
+//	@Success	200			{object}	models.DeliveryTracking
+//	@Failure	404			{object}	errors.ErrorResponse
+//	@Failure	500			{object}	errors.ErrorResponse
+//	@Router		/v1/qr-menu/delivery-tracking/{order_id} [get]
+func (server *Server) GetDeliveryTracking(c *gin.Context) {
+	res, err := server.orderKwaaka3plService.GetDeliveryTracking(c.Request.Context(), c.Param("order_id"))
+	if err != nil {
+		c.Set(errorKey, err)
+		if errors.Is(err, models2.ErrDeliveryOrderIdIsEmpty) {
+			c.AbortWithStatusJSON(http.StatusNotFound, err2.ErrorResponse{Msg: err.Error()})
+			return
+		}
+		server.Logger.Errorf("get delivery tracking error: %s", err.Error())
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err2.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res.WithMaskedCourierPhone())
+}
+
 func (server *Server) CancelCourierSearch(c *gin.Context) {
 	server.Logger.Info(logger.LoggerInfo{
 		System:  "kwaaka 3pl cancel order dispatcher request",
@@ -252,6 +305,19 @@ func (s *Server) GetOrdersByCustomerPhone(c *gin.Context) {
 		return
 	}
 
+	// профиль клиента по группе ресторана не обязателен для диспетчера
+	store, err := s.storeService.GetByID(c.Request.Context(), restaurantID)
+	if err != nil {
+		s.Logger.Errorf("get store %s for customer profile error: %s", restaurantID, err)
+	} else if store.RestaurantGroupID != "" {
+		profile, err := s.customerService.GetProfile(c.Request.Context(), customerPhone, store.RestaurantGroupID)
+		if err != nil {
+			s.Logger.Errorf("get customer profile error: %s", err)
+		} else {
+			res.CustomerProfile = &profile
+		}
+	}
+
 	c.JSON(http.StatusOK, res)
 }
 
@@ -183,7 +183,7 @@ func (server *Server) KwaakaAdminStopListByProductID(c *gin.Context) {
 		return
 	}
 
-	if err := server.stopListService.UpdateStopListByPosProductID(c.Request.Context(), req.IsAvailabe, req.StoreID, req.ProductID); err != nil {
+	if err := server.stopListService.UpdateStopListByPosProductIDWithReason(c.Request.Context(), coreMenuModels.DisableReasonAdmin, req.IsAvailabe, req.StoreID, req.ProductID); err != nil {
 		server.Logger.Errorf("update stoplist by product id error: %s", err.Error())
 		c.AbortWithStatusJSON(http.StatusInternalServerError, errors.ErrorResponse{
 			Msg: err.Error(),
@@ -276,6 +276,8 @@ func (server *Server) CancelOrderKwaakaAdmin(c *gin.Context) {
 		return
 	}
 
+	server.rollbackPromoCodeRedemption(c.Request.Context(), orderID, "cancelled by kwaaka admin")
+
 	if err := server.orderKwaaka3plService.Cancel3plOrder(c.Request.Context(), orderID, coreModels.OrderInfoForTelegramMsg{}); err != nil {
 		server.Logger.Errorf("kwaaka admin cancel dispatcher error: %s", err.Error())
 		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{
@@ -287,6 +289,69 @@ func (server *Server) CancelOrderKwaakaAdmin(c *gin.Context) {
 	c.AbortWithStatus(http.StatusNoContent)
 }
 
+// ReconcileStopListKwaakaAdmin
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for stoplist drift report
+//	@Security	ApiKeyAuth
+//	@Summary	Method compares pos stoplist with aggregator menus and aggregators, drift is healed if heal=true
+//	@Param		store_id	path		string	true	"store_id"
+//	@Param		heal		query		bool	false	"send drifted products to aggregator"
+//	@Success	200			{array}		stoplist.StopListReconcileReport
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/stoplist/reconcile/{store_id} [post]
+func (server *Server) ReconcileStopListKwaakaAdmin(c *gin.Context) {
+	heal := c.Query("heal") == "true"
+
+	reports, err := server.stopListService.ReconcileStopListByStoreID(c.Request.Context(), c.Param("store_id"), heal)
+	if err != nil {
+		server.Logger.Errorf("reconcile stoplist error: %s", err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{
+			Msg: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// UpdateOrderItemsKwaakaAdmin
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for applying aggregator order items change in pos
+//	@Security	ApiKeyAuth
+//	@Summary	Method for applying aggregator order items change in pos
+//	@Param		order_id	path	string										true	"order_id"
+//	@Param		body		body	integrationApiModels.UpdateOrderItemsRequest	true	"new order products"
+//	@Success	200			{object}	coreModels.Order
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		v1/kwaaka-admin/order-items/{order_id} [put]
+func (server *Server) UpdateOrderItemsKwaakaAdmin(c *gin.Context) {
+	var req integrationApiModels.UpdateOrderItemsRequest
+
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{
+			Msg: err.Error(),
+		})
+		return
+	}
+
+	order, err := server.orderModificationService.UpdateOrderItemsByAggregator(c.Request.Context(), c.Param("order_id"), req.DeliveryService, req.Products)
+	if err != nil {
+		server.Logger.Errorf("update order items error: %s", err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{
+			Msg: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
 // UpdateKwaakaAdminBusyMode godoc
 //
 //	@Summary		update kwaaka_admin busy mode status and value
@@ -0,0 +1,110 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/core/integration_api/resources/v1/dto"
+)
+
+// GetMarkupRules
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting markup rules of restaurant
+//	@Security	ApiKeyAuth
+//	@Param		store_id	path		string	true	"store_id"
+//	@Success	200			{object}	dto.MarkupRulesRequest
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/markup-rules/{store_id} [get]
+func (server *Server) GetMarkupRules(c *gin.Context) {
+	store, err := server.storeService.GetByID(c.Request.Context(), c.Param("store_id"))
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MarkupRulesRequest{Rules: store.MarkupRules})
+}
+
+// UpdateMarkupRules
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for replacing markup rules of restaurant
+//	@Security	ApiKeyAuth
+//	@Summary	Rules are applied to pos prices in aggregator menus, if no rule matches markup_percent of menu is used
+//	@Param		store_id	path	string					true	"store_id"
+//	@Param		request		body	dto.MarkupRulesRequest	true	"request"
+//	@Success	204
+//	@Failure	400	{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/markup-rules/{store_id} [put]
+func (server *Server) UpdateMarkupRules(c *gin.Context) {
+	var req dto.MarkupRulesRequest
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	if err := server.storeService.UpdateMarkupRules(c.Request.Context(), c.Param("store_id"), req.Rules); err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PreviewMarkup
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for previewing aggregator menu prices after markup
+//	@Security	ApiKeyAuth
+//	@Summary	Without rules in body saved rules of restaurant are used, menu is not changed
+//	@Param		store_id	path		string						true	"store_id"
+//	@Param		request		body		dto.MarkupPreviewRequest	true	"request"
+//	@Success	200			{object}	models.MarkupPreview
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/markup-rules/{store_id}/preview [post]
+func (server *Server) PreviewMarkup(c *gin.Context) {
+	var req dto.MarkupPreviewRequest
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	store, err := server.storeService.GetByID(c.Request.Context(), c.Param("store_id"))
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	if !store.VerifyMenuOwnership(req.MenuID) {
+		c.Set(errorKey, "menu doesn't belong to the restaurant")
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: "menu doesn't belong to the restaurant"})
+		return
+	}
+
+	if req.Rules != nil {
+		if err = req.Rules.Validate(); err != nil {
+			c.Set(errorKey, err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+			return
+		}
+		store.MarkupRules = req.Rules
+	}
+
+	preview, err := server.menuService.PreviewMarkup(c.Request.Context(), store, req.MenuID)
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
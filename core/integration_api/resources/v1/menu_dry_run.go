@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/core/integration_api/resources/v1/dto"
+	menuDto "github.com/kwaaka-team/orders-core/pkg/menu/dto"
+)
+
+// DryRunUploadMenu
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for dry run of menu upload
+//	@Security	ApiKeyAuth
+//	@Summary	Menu is marshalled for aggregator and checked against its constraints, aggregator is not called
+//	@Param		request	body		dto.MenuDryRunRequest	true	"request"
+//	@Success	200		{object}	models.DryRunReport
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/menu/dry-run-upload [post]
+func (server *Server) DryRunUploadMenu(c *gin.Context) {
+	var req dto.MenuDryRunRequest
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	report, err := server.menuCli.DryRunUploadMenu(c.Request.Context(), menuDto.MenuUploadRequest{
+		StoreId:      req.StoreID,
+		MenuId:       req.MenuID,
+		DeliveryName: req.Delivery,
+		UserRole:     req.UserRole,
+	})
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
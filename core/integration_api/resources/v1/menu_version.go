@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/core/integration_api/resources/v1/dto"
+	menuDto "github.com/kwaaka-team/orders-core/pkg/menu/dto"
+)
+
+// GetMenuVersions
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting versions of menu, newest first, without menu body
+//	@Security	ApiKeyAuth
+//	@Param		menu_id	path		string	true	"menu_id"
+//	@Param		page	query		string	false	"page"
+//	@Param		limit	query		string	false	"limit"
+//	@Success	200		{object}	dto.MenuVersionsResponse
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/menu/{menu_id}/versions [get]
+func (server *Server) GetMenuVersions(c *gin.Context) {
+	page, limit, err := parsePaging(c)
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: errGetQuery + ": " + err.Error()})
+		return
+	}
+
+	versions, total, err := server.menuCli.ListMenuVersions(c.Request.Context(), c.Param("menu_id"), page, limit)
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MenuVersionsResponse{
+		Versions: versions,
+		Total:    total,
+	})
+}
+
+// GetMenuVersion
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting menu version with menu snapshot
+//	@Security	ApiKeyAuth
+//	@Param		version_id	path		string	true	"version_id"
+//	@Success	200			{object}	models.MenuVersion
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/menu-versions/{version_id} [get]
+func (server *Server) GetMenuVersion(c *gin.Context) {
+	version, err := server.menuCli.GetMenuVersion(c.Request.Context(), c.Param("version_id"))
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
+
+// DiffMenuVersions
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for diff between two versions of menu
+//	@Security	ApiKeyAuth
+//	@Summary	Products, prices, attribute groups, min/max and images changes; without "to" version is compared with current menu
+//	@Param		version_id	path		string	true	"version_id"
+//	@Param		to			query		string	false	"to version_id"
+//	@Success	200			{object}	models.MenuDiff
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/menu-versions/{version_id}/diff [get]
+func (server *Server) DiffMenuVersions(c *gin.Context) {
+	diff, err := server.menuCli.DiffMenuVersions(c.Request.Context(), c.Param("version_id"), c.Query("to"))
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RollbackMenuVersion
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for restoring menu from version
+//	@Security	ApiKeyAuth
+//	@Summary	Stop list stays current; with upload menu is sent to aggregator through UploadMenu
+//	@Param		version_id	path		string					true	"version_id"
+//	@Param		request		body		dto.MenuRollbackRequest	true	"request"
+//	@Success	200			{object}	dto.MenuRollbackResponse
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/menu-versions/{version_id}/rollback [post]
+func (server *Server) RollbackMenuVersion(c *gin.Context) {
+	var req dto.MenuRollbackRequest
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	transactionID, err := server.menuCli.RollbackMenuVersion(c.Request.Context(), menuDto.MenuRollbackRequest{
+		VersionID: c.Param("version_id"),
+		Upload:    req.Upload,
+		Sv3:       server.sv3,
+		UserRole:  req.UserRole,
+		UserName:  req.UserName,
+	})
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MenuRollbackResponse{TransactionID: transactionID})
+}
@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	models "github.com/kwaaka-team/orders-core/core/externalapi/models"
+	models0 "github.com/kwaaka-team/orders-core/core/models"
 	dto "github.com/kwaaka-team/orders-core/pkg/order/dto"
 )
 
@@ -109,3 +110,18 @@ func (mr *MockOrderClientMockRecorder) UpdateOrder(ctx, order, orderID, service,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrder", reflect.TypeOf((*MockOrderClient)(nil).UpdateOrder), ctx, order, orderID, service, clientSecret)
 }
+
+// UpdateOrderDetails mocks base method.
+func (m *MockOrderClient) UpdateOrderDetails(ctx context.Context, req models.Order, orderID, service, clientSecret string) (models0.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderDetails", ctx, req, orderID, service, clientSecret)
+	ret0, _ := ret[0].(models0.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderDetails indicates an expected call of UpdateOrderDetails.
+func (mr *MockOrderClientMockRecorder) UpdateOrderDetails(ctx, req, orderID, service, clientSecret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderDetails", reflect.TypeOf((*MockOrderClient)(nil).UpdateOrderDetails), ctx, req, orderID, service, clientSecret)
+}
@@ -1,8 +1,11 @@
 package v1
 
 import (
+	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	integrationApiModels "github.com/kwaaka-team/orders-core/core/integration_api/resources/v1/dto"
 	"github.com/kwaaka-team/orders-core/core/managers/telegram"
 	models2 "github.com/kwaaka-team/orders-core/core/menu/models"
 	"github.com/kwaaka-team/orders-core/core/service/iiko/resources/http/v1/detector"
@@ -135,6 +138,8 @@ func (server *Server) RefundPayment(c *gin.Context) {
 		return
 	}
 
+	server.rollbackPromoCodeOnFullRefund(c.Request.Context(), orderID, paymentOrder, reason)
+
 	order, err := server.orderInfoSharingService.GetOrder(c.Request.Context(), paymentOrder.OrderID)
 	if err != nil {
 		server.Logger.Errorf("couldn't find order to send notification of refund: %s", err.Error())
@@ -162,22 +167,96 @@ func (server *Server) RefundPayment(c *gin.Context) {
 	c.JSON(http.StatusOK, refundResponse)
 }
 
+// RefundOrderLines
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for refunding order positions
+//	@Summary	Method for refunding chosen order positions, amount is calculated with promos and service fee
+//	@Param		order_id	path	string										true	"order id"
+//	@Param		body		body	integrationApiModels.RefundOrderLinesRequest	true	"positions to refund"
+//	@Success	200			{object}	models.Refund
+//	@Failure	401			{object}	errors.ErrorResponse
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/refund-lines/{order_id} [post]
+func (server *Server) RefundOrderLines(c *gin.Context) {
+	var req integrationApiModels.RefundOrderLinesRequest
+
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{
+			Msg: err.Error(),
+		})
+		return
+	}
+
+	paymentOrder, _, refund, err := server.paymentManager.RefundOrderLines(c.Request.Context(), c.Param("order_id"), req.Reason, req.Lines)
+	if err != nil {
+		server.Logger.Errorf("refund order lines: %s", err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{
+			Msg: err.Error(),
+		})
+		return
+	}
+
+	server.rollbackPromoCodeOnFullRefund(c.Request.Context(), c.Param("order_id"), paymentOrder, req.Reason)
+
+	order, err := server.orderInfoSharingService.GetOrder(c.Request.Context(), paymentOrder.OrderID)
+	if err != nil {
+		server.Logger.Errorf("couldn't find order to send notification of refund: %s", err.Error())
+		c.JSON(http.StatusOK, refund)
+		return
+	}
+
+	store, err := server.storeService.GetByID(c.Request.Context(), paymentOrder.RestaurantID)
+	if err != nil {
+		server.Logger.Errorf("couldn't find store to send notification of refund: %s", err.Error())
+		c.JSON(http.StatusOK, refund)
+		return
+	}
+
+	if err = server.TelegramService.SendMessageToQueue(telegram.Refund, order, store, "", strconv.Itoa(refund.Amount), req.Reason, models2.Product{}); err != nil {
+		server.Logger.Errorf("couldn't send refund notification to telegram chat: %s", err.Error())
+	}
+
+	c.JSON(http.StatusOK, refund)
+}
+
+// rollbackPromoCodeOnFullRefund - при полном возврате (сумма всех возвратов заказа) промокод возвращается клиенту, частичный возврат списание не отменяет
+func (server *Server) rollbackPromoCodeOnFullRefund(ctx context.Context, orderID string, paymentOrder models.PaymentOrder, reason string) {
+	refunds, err := server.paymentManager.GetRefunds(ctx, orderID)
+	if err != nil {
+		server.Logger.Errorf("couldn't get refunds of order %s for promo code rollback: %s", orderID, err.Error())
+		return
+	}
+
+	var refunded int
+	for _, refund := range refunds {
+		refunded += refund.Amount
+	}
+
+	if refunded*100 >= paymentOrder.Amount {
+		server.rollbackPromoCodeRedemption(ctx, paymentOrder.OrderID, "refund: "+reason)
+	}
+}
+
 // GetRefund
 //
 //	@Tags		kwaaka-admin
-//	@Title		Method for get refund
-//	@Summary	Method for get refund
-//	@Param		order_id	path   string	 true	"order id for finding refund"
-//	@Success	200 {object}    models.Refund
+//	@Title		Method for get refunds of order
+//	@Summary	Method for get refunds of order: whole order and by lines
+//	@Param		order_id	path   string	 true	"order id for finding refunds"
+//	@Success	200 {array}    models.Refund
 //	@Failure	400	{object}	errors.ErrorResponse
 //	@Failure	500	{object}	errors.ErrorResponse
 //	@Router		/v1/kwaaka-admin/get-refund/:order_id [get]
 func (srv *Server) GetRefund(c *gin.Context) {
 	orderID := c.Param("order_id")
 
-	res, err := srv.paymentManager.GetRefund(c.Request.Context(), orderID)
+	res, err := srv.paymentManager.GetRefunds(c.Request.Context(), orderID)
 	if err != nil {
-		srv.Logger.Errorf("couldn't get refund by order id: %s", err.Error())
+		srv.Logger.Errorf("couldn't get refunds by order id: %s", err.Error())
 		c.Set(errorKey, err)
 		c.JSON(http.StatusInternalServerError, err.Error())
 		return
@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/core/integration_api/resources/v1/dto"
+	menuDto "github.com/kwaaka-team/orders-core/pkg/menu/dto"
+)
+
+// SuggestProductMatches
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for suggesting pos products for unmatched aggregator products
+//	@Security	ApiKeyAuth
+//	@Summary	Candidates are scored by name (with transliteration), price, section and attribute groups
+//	@Param		menu_id			path		string	true	"aggregator menu_id"
+//	@Param		store_id		query		string	true	"store_id"
+//	@Param		min_confidence	query		number	false	"min confidence from 0 to 1, default 0.5"
+//	@Success	200				{object}	dto.ProductMatchSuggestionsResponse
+//	@Failure	400				{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/menu/{menu_id}/match-suggestions [get]
+func (server *Server) SuggestProductMatches(c *gin.Context) {
+	var minConfidence float64
+	if value := c.Query("min_confidence"); value != "" {
+		var err error
+		if minConfidence, err = strconv.ParseFloat(value, 64); err != nil {
+			c.Set(errorKey, err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: errGetQuery + ": " + err.Error()})
+			return
+		}
+	}
+
+	suggestions, err := server.menuCli.SuggestProductMatches(c.Request.Context(), c.Query("store_id"), c.Param("menu_id"), minConfidence)
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ProductMatchSuggestionsResponse{Suggestions: suggestions})
+}
+
+// AcceptProductMatches
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for accepting product matches in bulk
+//	@Security	ApiKeyAuth
+//	@Summary	pos_id and product_id of pos product are written into aggregator menu products
+//	@Param		menu_id	path		string							true	"aggregator menu_id"
+//	@Param		request	body		dto.AcceptProductMatchesRequest	true	"request"
+//	@Success	200		{object}	models.ProductMatchAcceptResult
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/menu/{menu_id}/match-suggestions/accept [post]
+func (server *Server) AcceptProductMatches(c *gin.Context) {
+	var req dto.AcceptProductMatchesRequest
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	result, err := server.menuCli.AcceptProductMatches(c.Request.Context(), menuDto.AcceptProductMatchesRequest{
+		StoreID:  req.StoreID,
+		MenuID:   c.Param("menu_id"),
+		Matches:  req.Matches,
+		UserName: req.UserName,
+	})
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	errResp "github.com/kwaaka-team/orders-core/core/errors"
@@ -209,7 +210,7 @@ func (server *Server) GetPromoCodesByRestaurantID(c *gin.Context) {
 // ValidatePromoCodeForUser docs
 //
 //	@Tags		qr-menu/promo-code
-//	@Title		Method for validate promo code for user
+//	@Title		Method for validate promo code for user, read only: usage of promo code is not counted, promo code is applied by redeem
 //	@Security	ApiKeyAuth
 //	@Summary	Method ValidatePromoCodeForUser
 //	@Param		request	body	models.ValidateUserPromoCode	true	"request"
@@ -287,23 +288,20 @@ func (server *Server) GetPromoCodeByCodeAndRestaurantId(c *gin.Context) {
 	c.JSON(http.StatusOK, promoCode)
 }
 
-// AddUserPromoCodeUsageTimeToDB docs
+// RedeemPromoCode docs
 //
 //	@Tags		qr-menu/promo-code
-//	@Title		Method for add user promo code usage time to database
+//	@Title		Method for redeem promo code for order
 //	@Security	ApiKeyAuth
-//	@Summary	Method get AddUserPromoCodeUsageTimeToDB
-//	@Param		request	body	models.UserPromoCodeUsageTimeRequest	true	"request"
-//	@Success	200
-//	@Failure	401		{object}	errors.ErrorResponse
+//	@Summary	Promo code is checked and atomically written off from usage limits and budgets, repeated request for the same order returns existing redemption
+//	@Param		request	body		models.RedeemPromoCodeRequest	true	"request"
+//	@Success	200		{object}	models.ValidateUserPromoCodeResponse
 //	@Failure	400		{object}	errors.ErrorResponse
 //	@Failure	500		{object}	errors.ErrorResponse
-//	@Router		/v1/qr-menu/promo-code/add-usage-time [post]
-func (server *Server) AddUserPromoCodeUsageTimeToDB(c *gin.Context) {
-
-	server.Logger.Infof("server start to add user promo code usage time to database")
+//	@Router		/v1/qr-menu/promo-code/redeem [post]
+func (server *Server) RedeemPromoCode(c *gin.Context) {
 
-	var request models.UserPromoCodeUsageTimeRequest
+	var request models.RedeemPromoCodeRequest
 	if err := c.BindJSON(&request); err != nil {
 		server.Logger.Infof("bind error: %s", err.Error())
 		c.Set(errorKey, fmt.Sprintf("bind error: %s", err.Error()))
@@ -314,16 +312,103 @@ func (server *Server) AddUserPromoCodeUsageTimeToDB(c *gin.Context) {
 		return
 	}
 
-	err := server.PromoCode.AddUserPromoCodeUsageTimeToDB(c.Request.Context(), request.UserId, request.PromoCodeValue, request.RestaurantId)
+	result, err := server.PromoCode.RedeemPromoCode(c.Request.Context(), request)
 	if err != nil {
-		server.Logger.Infof("add user promo code usage time to db error: %s", err.Error())
-		c.Set(errorKey, fmt.Sprintf("add user promo code usage time to db error: %s", err.Error()))
-		c.AbortWithStatusJSON(http.StatusInternalServerError, []errResp.ErrorResponse{{
+		server.Logger.Infof("redeem promo code for order: %v error: %s", request.OrderID, err.Error())
+		c.Set(errorKey, fmt.Sprintf("redeem promo code for order: %v error: %s", request.OrderID, err.Error()))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errResp.ErrorResponse{
 			Code:        http.StatusInternalServerError,
 			Description: err.Error(),
-		}})
+		})
 		return
 	}
 
-	c.Status(http.StatusOK)
+	c.JSON(http.StatusOK, result)
+}
+
+// RollbackPromoCodeRedemption docs
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for rollback promo code redemption of order
+//	@Security	ApiKeyAuth
+//	@Param		order_id	path	string										true	"order_id"
+//	@Param		request		body	models.RollbackPromoCodeRedemptionRequest	true	"request"
+//	@Success	204
+//	@Failure	400	{object}	errors.ErrorResponse
+//	@Failure	404	{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/promo-code/redemptions/{order_id}/rollback [post]
+func (server *Server) RollbackPromoCodeRedemption(c *gin.Context) {
+
+	var request models.RollbackPromoCodeRedemptionRequest
+	if err := c.BindJSON(&request); err != nil {
+		server.Logger.Infof("bind error: %s", err.Error())
+		c.Set(errorKey, fmt.Sprintf("bind error: %s", err.Error()))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errResp.ErrorResponse{
+			Code:        http.StatusBadRequest,
+			Description: err.Error(),
+		})
+		return
+	}
+
+	orderID := c.Param("order_id")
+
+	if err := server.PromoCode.RollbackRedemption(c.Request.Context(), orderID, request.Reason); err != nil {
+		server.Logger.Infof("rollback promo code redemption for order: %v error: %s", orderID, err.Error())
+		c.Set(errorKey, fmt.Sprintf("rollback promo code redemption for order: %v error: %s", orderID, err.Error()))
+		status := http.StatusBadRequest
+		if errors.Is(err, dto.ErrRedemptionNotFound) {
+			status = http.StatusNotFound
+		}
+		c.AbortWithStatusJSON(status, errResp.ErrorResponse{
+			Code:        status,
+			Description: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PromoCodeAnalytics docs
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for promo code redemptions analytics of restaurant group
+//	@Security	ApiKeyAuth
+//	@Summary	Redemptions, discount cost, incremental revenue of new customers and phones or devices redeeming for many users
+//	@Param		request	body		models.PromoCodeAnalyticsRequest	true	"request"
+//	@Success	200		{object}	models.PromoCodeAnalyticsResponse
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/promo-code/analytics [post]
+func (server *Server) PromoCodeAnalytics(c *gin.Context) {
+
+	var request models.PromoCodeAnalyticsRequest
+	if err := c.BindJSON(&request); err != nil {
+		server.Logger.Infof("bind error: %s", err.Error())
+		c.Set(errorKey, fmt.Sprintf("bind error: %s", err.Error()))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errResp.ErrorResponse{
+			Code:        http.StatusBadRequest,
+			Description: err.Error(),
+		})
+		return
+	}
+
+	result, err := server.PromoCode.Analytics(c.Request.Context(), request)
+	if err != nil {
+		server.Logger.Infof("promo code analytics error: %s", err.Error())
+		c.Set(errorKey, fmt.Sprintf("promo code analytics error: %s", err.Error()))
+		c.AbortWithStatusJSON(http.StatusBadRequest, errResp.ErrorResponse{
+			Code:        http.StatusBadRequest,
+			Description: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// rollbackPromoCodeRedemption - откат списания промокода после отмены или возврата, заказы без промокода пропускаются
+func (server *Server) rollbackPromoCodeRedemption(ctx context.Context, orderID, reason string) {
+	if err := server.PromoCode.RollbackRedemption(ctx, orderID, reason); err != nil && !errors.Is(err, dto.ErrRedemptionNotFound) {
+		server.Logger.Errorf("rollback promo code redemption for order: %v error: %s", orderID, err.Error())
+	}
 }
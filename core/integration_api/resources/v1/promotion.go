@@ -0,0 +1,172 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+	"github.com/kwaaka-team/orders-core/service/promotion/models"
+)
+
+// CreatePromotion
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for creating promotion of direct channels
+//	@Security	ApiKeyAuth
+//	@Summary	Promotions are applied by priority, non stackable promotion is applied alone
+//	@Param		promotion	body		models.Promotion	true	"promotion"
+//	@Success	200			{string}	string
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/promotions [post]
+func (server *Server) CreatePromotion(c *gin.Context) {
+	var req models.Promotion
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	id, err := server.promotionService.Create(c.Request.Context(), req)
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, id)
+}
+
+// UpdatePromotion
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for updating promotion
+//	@Security	ApiKeyAuth
+//	@Param		promotion_id	path		string				true	"promotion_id"
+//	@Param		promotion		body		models.Promotion	true	"promotion"
+//	@Success	204
+//	@Failure	400				{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/promotions/{promotion_id} [put]
+func (server *Server) UpdatePromotion(c *gin.Context) {
+	var req models.Promotion
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+	req.ID = c.Param("promotion_id")
+
+	if err := server.promotionService.Update(c.Request.Context(), req); err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeletePromotion
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for deleting promotion
+//	@Security	ApiKeyAuth
+//	@Param		promotion_id	path	string	true	"promotion_id"
+//	@Success	204
+//	@Failure	400	{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/promotions/{promotion_id} [delete]
+func (server *Server) DeletePromotion(c *gin.Context) {
+	if err := server.promotionService.Delete(c.Request.Context(), c.Param("promotion_id")); err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetPromotion
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting promotion
+//	@Security	ApiKeyAuth
+//	@Param		promotion_id	path		string	true	"promotion_id"
+//	@Success	200				{object}	models.Promotion
+//	@Failure	400				{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/promotions/{promotion_id} [get]
+func (server *Server) GetPromotion(c *gin.Context) {
+	promotion, err := server.promotionService.GetByID(c.Request.Context(), c.Param("promotion_id"))
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, promotion)
+}
+
+// GetPromotions
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting promotions of store
+//	@Security	ApiKeyAuth
+//	@Param		store_id	path		string	true	"store_id"
+//	@Success	200			{array}		models.Promotion
+//	@Failure	400			{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/promotions/store/{store_id} [get]
+func (server *Server) GetPromotions(c *gin.Context) {
+	promotions, err := server.promotionService.GetByStoreID(c.Request.Context(), c.Param("store_id"))
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, promotions)
+}
+
+// CalculatePromotionsKwaakaAdmin
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for calculating promotions discounts of kwaaka admin cart
+//	@Security	ApiKeyAuth
+//	@Param		cart	body		coreModels.Cart	true	"cart"
+//	@Success	200		{object}	models.Result
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/promotions/calculate [post]
+func (server *Server) CalculatePromotionsKwaakaAdmin(c *gin.Context) {
+	server.calculatePromotions(c, coreModels.KWAAKA_ADMIN.String())
+}
+
+// CalculatePromotionsQRMenu
+//
+//	@Tags		qrmenu
+//	@Title		Method for calculating promotions discounts of qr menu cart
+//	@Security	ApiKeyAuth
+//	@Param		cart	body		coreModels.Cart	true	"cart"
+//	@Success	200		{object}	models.Result
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Router		/v1/qr-menu/promotions/calculate [post]
+func (server *Server) CalculatePromotionsQRMenu(c *gin.Context) {
+	server.calculatePromotions(c, coreModels.QRMENU.String())
+}
+
+func (server *Server) calculatePromotions(c *gin.Context, deliveryService string) {
+	var req coreModels.Cart
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	res, err := server.promotionService.Calculate(c.Request.Context(), req, deliveryService)
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
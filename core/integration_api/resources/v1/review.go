@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	"github.com/kwaaka-team/orders-core/core/models"
+	errorsPkg "github.com/pkg/errors"
+)
+
+// GetOrderReview
+//
+//	@Tags		qrmenu
+//	@Title		Method for getting order review by token from review link
+//	@Security	ApiKeyAuth
+//	@Summary	Review contains order items, which customer can rate separately
+//	@Param		token	path		string	true	"token"
+//	@Success	200		{object}	models.OrderReview
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Failure	404		{object}	errors.ErrorResponse
+//	@Router		/v1/qr-menu/reviews/{token} [get]
+func (server *Server) GetOrderReview(c *gin.Context) {
+	review, err := server.orderReviewService.GetReview(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.Set(errorKey, err)
+		if errorsPkg.Is(err, drivers.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, errors.ErrorResponse{Msg: err.Error()})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// SubmitOrderReview
+//
+//	@Tags		qrmenu
+//	@Title		Method for rating order and its products by token from review link
+//	@Security	ApiKeyAuth
+//	@Summary	Rating from 1 to 5, low rating is sent to store telegram chat, high rating returns 2gis review link in redirect_url
+//	@Param		token	path		string							true	"token"
+//	@Param		request	body		models.SubmitOrderReviewRequest	true	"request"
+//	@Success	200		{object}	models.SubmitOrderReviewResponse
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Failure	404		{object}	errors.ErrorResponse
+//	@Failure	409		{object}	errors.ErrorResponse
+//	@Router		/v1/qr-menu/reviews/{token} [post]
+func (server *Server) SubmitOrderReview(c *gin.Context) {
+	var req models.SubmitOrderReviewRequest
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	res, err := server.orderReviewService.SubmitReview(c.Request.Context(), c.Param("token"), req)
+	if err != nil {
+		c.Set(errorKey, err)
+		switch {
+		case errorsPkg.Is(err, drivers.ErrNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, errors.ErrorResponse{Msg: err.Error()})
+		case errorsPkg.Is(err, models.ErrOrderReviewAlreadyRated):
+			c.AbortWithStatusJSON(http.StatusConflict, errors.ErrorResponse{Msg: err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// ReviewRatings
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for order review ratings of restaurants for period
+//	@Security	ApiKeyAuth
+//	@Summary	Average, count and distribution of ratings per store, product and courier provider
+//	@Param		request	body		models.ReviewRatingsRequest	true	"request"
+//	@Success	200		{object}	models.ReviewRatings
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/reviews/ratings [post]
+func (server *Server) ReviewRatings(c *gin.Context) {
+	var req models.ReviewRatingsRequest
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	res, err := server.orderReviewService.Ratings(c.Request.Context(), req)
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,93 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/service/settlement/models"
+)
+
+// GenerateSettlementStatement
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for generating settlement statement of legal entity for period
+//	@Security	ApiKeyAuth
+//	@Summary	Opening balance is closing balance of previous statement, bill is created in legal entity payment if restaurant owes Kwaaka
+//	@Param		request	body		models.GenerateRequest	true	"request"
+//	@Success	200		{object}	models.Statement
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/settlement-statements [post]
+func (server *Server) GenerateSettlementStatement(c *gin.Context) {
+	var req models.GenerateRequest
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	statement, err := server.settlementService.Generate(c.Request.Context(), req)
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, statement)
+}
+
+// GetSettlementStatement
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting settlement statement with lines
+//	@Security	ApiKeyAuth
+//	@Param		statement_id	path		string	true	"statement_id"
+//	@Success	200				{object}	models.Statement
+//	@Failure	400				{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/settlement-statements/{statement_id} [get]
+func (server *Server) GetSettlementStatement(c *gin.Context) {
+	statement, err := server.settlementService.GetByID(c.Request.Context(), c.Param("statement_id"))
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, statement)
+}
+
+// GetSettlementStatementsByLegalEntity
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting settlement statements of legal entity without lines
+//	@Security	ApiKeyAuth
+//	@Param		legal_entity_id	path		string	true	"legal_entity_id"
+//	@Success	200				{array}		models.Statement
+//	@Failure	400				{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/settlement-statements/legal-entity/{legal_entity_id} [get]
+func (server *Server) GetSettlementStatementsByLegalEntity(c *gin.Context) {
+	statements, err := server.settlementService.GetByLegalEntityID(c.Request.Context(), c.Param("legal_entity_id"))
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, statements)
+}
+
+func (server *Server) GenerateSettlementStatements(c *gin.Context) {
+	from, to := models.PreviousMonth(time.Now().UTC())
+
+	statements, err := server.settlementService.GenerateAll(c.Request.Context(), from, to)
+	if err != nil {
+		server.Logger.Error(err)
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, statements)
+}
@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+)
+
+// SLAAnalytics
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for order stages percentiles and sla breaches of restaurants for period
+//	@Security	ApiKeyAuth
+//	@Summary	p50/p90/p99 in minutes per store, pos type and aggregator, thresholds from request replace thresholds of restaurants
+//	@Param		request	body		coreModels.SLAAnalyticsRequest	true	"request"
+//	@Success	200		{object}	coreModels.SLAAnalytics
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/sla/analytics [post]
+func (server *Server) SLAAnalytics(c *gin.Context) {
+	var req coreModels.SLAAnalyticsRequest
+	if err := c.BindJSON(&req); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	res, err := server.slaService.Analytics(c.Request.Context(), req)
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
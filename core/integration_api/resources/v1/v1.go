@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/service/s3"
 	ginAdapter "github.com/awslabs/aws-lambda-go-api-proxy/gin"
 	sentrygin "github.com/getsentry/sentry-go/gin"
 	"github.com/gin-contrib/cors"
@@ -19,7 +20,11 @@ import (
 	starterAppManagers "github.com/kwaaka-team/orders-core/core/starter_app/managers"
 	talabatManagers "github.com/kwaaka-team/orders-core/core/talabat/manager"
 	woltManagers "github.com/kwaaka-team/orders-core/core/wolt/managers"
+	"github.com/kwaaka-team/orders-core/pkg/menu"
+	"github.com/kwaaka-team/orders-core/service/aggregator"
+	"github.com/kwaaka-team/orders-core/service/availability_schedule"
 	"github.com/kwaaka-team/orders-core/service/bitrix"
+	"github.com/kwaaka-team/orders-core/service/customer"
 	"github.com/kwaaka-team/orders-core/service/gourmet"
 	"github.com/kwaaka-team/orders-core/service/kwaaka_3pl"
 	"github.com/kwaaka-team/orders-core/service/legal_entity_payment"
@@ -29,8 +34,11 @@ import (
 	"github.com/kwaaka-team/orders-core/service/payment"
 	posService "github.com/kwaaka-team/orders-core/service/pos"
 	"github.com/kwaaka-team/orders-core/service/promo_code"
+	"github.com/kwaaka-team/orders-core/service/promotion"
 	"github.com/kwaaka-team/orders-core/service/restaurant_set"
+	"github.com/kwaaka-team/orders-core/service/settlement"
 	"github.com/kwaaka-team/orders-core/service/shaurma_food"
+	"github.com/kwaaka-team/orders-core/service/sla"
 	"github.com/kwaaka-team/orders-core/service/sms"
 	"github.com/kwaaka-team/orders-core/service/stoplist"
 	storeServicePkg "github.com/kwaaka-team/orders-core/service/store"
@@ -86,6 +94,7 @@ type Server struct {
 	TelegramService               order.TelegramService
 	orderInfoSharingService       order.InfoSharingService
 	orderCancellationService      order.CancellationService
+	orderModificationService      order.ModificationService
 	shaurmaFoodService            *shaurma_food.Service
 	WppBusinessService            whatsapp_business.Service
 	WhatsappService               whatsapp.Service
@@ -96,6 +105,15 @@ type Server struct {
 	bitrixService                 bitrix.Service
 	restaurantSetService          restaurant_set.Service
 	gourmetService                *gourmet.ServiceImpl
+	aggregatorOutboxService       order.AggregatorOutboxService
+	aggregatorRecorder            *aggregator.Recorder
+	availabilityScheduleService   availability_schedule.Service
+	settlementService             settlement.Service
+	slaService                    sla.Service
+	promotionService              promotion.Service
+	customerService               customer.Service
+	menuCli                       menu.Client
+	sv3                           *s3.S3
 }
 
 func NewServer(
@@ -143,6 +161,16 @@ func NewServer(
 	bitrixService bitrix.Service,
 	restaurantSetService restaurant_set.Service,
 	gourmetService *gourmet.ServiceImpl,
+	aggregatorOutboxService order.AggregatorOutboxService,
+	aggregatorRecorder *aggregator.Recorder,
+	orderModificationService order.ModificationService,
+	availabilityScheduleService availability_schedule.Service,
+	settlementService settlement.Service,
+	slaService sla.Service,
+	promotionService promotion.Service,
+	customerService customer.Service,
+	menuCli menu.Client,
+	sv3 *s3.S3,
 ) *Server {
 
 	server := &Server{
@@ -190,6 +218,16 @@ func NewServer(
 		bitrixService:                 bitrixService,
 		restaurantSetService:          restaurantSetService,
 		gourmetService:                gourmetService,
+		aggregatorOutboxService:       aggregatorOutboxService,
+		aggregatorRecorder:            aggregatorRecorder,
+		orderModificationService:      orderModificationService,
+		availabilityScheduleService:   availabilityScheduleService,
+		settlementService:             settlementService,
+		slaService:                    slaService,
+		promotionService:              promotionService,
+		customerService:               customerService,
+		menuCli:                       menuCli,
+		sv3:                           sv3,
 	}
 
 	ginLambda = ginAdapter.New(server.Router)
@@ -214,6 +252,10 @@ func (server *Server) register(engine *gin.Engine, isProduction bool) {
 		api.POST("/update-order-status", server.UpdateOrderStatusByPosTypes)
 		api.POST("/update-stoplist", server.UpdateStopListByPosTypes)
 		api.POST("/update-stoplist-by-section", server.UpdateStopListBySection)
+		api.POST("/reconcile-stoplist", server.ReconcileStopListByPosTypes)
+		api.POST("/apply-availability-schedules", server.ApplyAvailabilitySchedules)
+		api.POST("/generate-settlement-statements", server.GenerateSettlementStatements)
+		api.POST("/send-scheduled-newsletters", server.SendScheduledNewsletters)
 
 		api.POST("/generate-new-aggregator-menu", server.GenerateNewAggregatorMenu)
 		api.POST("/auto-update-aggregator-menu", server.AutoUpdateAggregatorMenu)
@@ -236,6 +278,8 @@ func (server *Server) register(engine *gin.Engine, isProduction bool) {
 		api.POST("/delivery/performer-lookup-time", server.PerformerLookupMoreThan15Minute)
 
 		api.POST("/send-telegram-message", server.SendTelegramMessage)
+
+		api.POST("/aggregator-outbox/dispatch", server.DispatchAggregatorOutbox)
 	}
 
 	posIIKO := engine.Group("/iiko")
@@ -326,12 +370,14 @@ func (server *Server) register(engine *gin.Engine, isProduction bool) {
 		{
 			glovo.POST("/placeOrder", server.CreateOrderGlovo)
 			glovo.POST("/cancelOrder", server.CancelOrderGlovo)
+			glovo.POST("/updateOrder", server.UpdateOrderGlovo)
 		}
 
 		wolt := v1.Group("/wolt")
 
 		{
 			wolt.POST("/placeOrder", server.CreateOrderWolt)
+			wolt.POST("/updateOrder", server.UpdateOrderWolt)
 		}
 
 		deliveroo := v1.Group("/deliveroo")
@@ -447,6 +493,11 @@ func (server *Server) register(engine *gin.Engine, isProduction bool) {
 			qrMenu.POST("/applePay/session", server.OpenApplePaySessionByQRmenu)
 			qrMenu.POST("/applePay/createPayment/:payment_order_id", server.CreateApplePayPayment)
 			qrMenu.GET("/twogis-review-link/:restaurant_id", server.GetTwoGisReviewLink)
+			qrMenu.POST("/delivery-quote/:restaurant_id", server.QuoteDelivery)
+			qrMenu.GET("/delivery-tracking/:order_id", server.GetDeliveryTracking)
+			qrMenu.POST("/promotions/calculate", server.CalculatePromotionsQRMenu)
+			qrMenu.GET("/reviews/:token", server.GetOrderReview)
+			qrMenu.POST("/reviews/:token", server.SubmitOrderReview)
 			wppBusiness := qrMenu.Group("/wpp-business")
 			{
 				wppBusiness.POST("/send-verification-code", server.SendVerificationCode)
@@ -458,7 +509,7 @@ func (server *Server) register(engine *gin.Engine, isProduction bool) {
 			promoCode := qrMenu.Group("/promo-code")
 			{
 				promoCode.POST("/validate-promo-code", server.ValidatePromoCodeForUser)
-				promoCode.POST("/add-usage-time", server.AddUserPromoCodeUsageTimeToDB)
+				promoCode.POST("/redeem", server.RedeemPromoCode)
 			}
 			restaurantSet := qrMenu.Group("/restaurant-set")
 			{
@@ -481,7 +532,9 @@ func (server *Server) register(engine *gin.Engine, isProduction bool) {
 		{
 			kwaakaAdmin.POST("/placeOrder", server.CreateOrderKwaakaAdmin)
 			kwaakaAdmin.DELETE("/cancelOrder/:order_id", server.CancelOrderKwaakaAdmin)
+			kwaakaAdmin.PUT("/order-items/:order_id", server.UpdateOrderItemsKwaakaAdmin)
 			kwaakaAdmin.POST("/setOrdersDispatcher", server.SetOrdersDispatcher)
+			kwaakaAdmin.POST("/dispatch/:order_id", server.DispatchBestProvider)
 			kwaakaAdmin.PUT("/cancelOrder", server.CancelOrderDispatcher)
 			kwaakaAdmin.POST("/courier-search-cancel/:delivery_order_id", server.CancelCourierSearch)
 			kwaakaAdmin.POST("/save-3pl-history/:delivery_order_id", server.Save3plHistory)
@@ -490,6 +543,45 @@ func (server *Server) register(engine *gin.Engine, isProduction bool) {
 
 			kwaakaAdmin.POST("/stoplist/product", server.KwaakaAdminStopListByProductID)
 			kwaakaAdmin.POST("/stoplist/attribute", server.KwaakaAdminStopListByAttributeID)
+			kwaakaAdmin.POST("/stoplist/reconcile/:store_id", server.ReconcileStopListKwaakaAdmin)
+			kwaakaAdmin.POST("/availability-schedules", server.CreateAvailabilitySchedule)
+			kwaakaAdmin.PUT("/availability-schedules/:schedule_id", server.UpdateAvailabilitySchedule)
+			kwaakaAdmin.DELETE("/availability-schedules/:schedule_id", server.DeleteAvailabilitySchedule)
+			kwaakaAdmin.GET("/availability-schedules/store/:store_id", server.GetAvailabilitySchedules)
+			kwaakaAdmin.GET("/availability-schedules/store/:store_id/preview", server.PreviewAvailabilitySchedules)
+
+			kwaakaAdmin.POST("/settlement-statements", server.GenerateSettlementStatement)
+			kwaakaAdmin.GET("/settlement-statements/:statement_id", server.GetSettlementStatement)
+			kwaakaAdmin.GET("/settlement-statements/legal-entity/:legal_entity_id", server.GetSettlementStatementsByLegalEntity)
+
+			kwaakaAdmin.POST("/sla/analytics", server.SLAAnalytics)
+
+			kwaakaAdmin.POST("/promotions", server.CreatePromotion)
+			kwaakaAdmin.POST("/promotions/calculate", server.CalculatePromotionsKwaakaAdmin)
+			kwaakaAdmin.PUT("/promotions/:promotion_id", server.UpdatePromotion)
+			kwaakaAdmin.DELETE("/promotions/:promotion_id", server.DeletePromotion)
+			kwaakaAdmin.GET("/promotions/:promotion_id", server.GetPromotion)
+			kwaakaAdmin.GET("/promotions/store/:store_id", server.GetPromotions)
+
+			kwaakaAdmin.GET("/customers/:phone/profile", server.GetCustomerProfile)
+			kwaakaAdmin.GET("/customers/:phone/orders", server.GetCustomerOrders)
+			kwaakaAdmin.GET("/customers/identities/:source/:external_id/profile", server.GetCustomerProfileByIdentity)
+			kwaakaAdmin.POST("/reviews/ratings", server.ReviewRatings)
+
+			kwaakaAdmin.GET("/menu/:menu_id/versions", server.GetMenuVersions)
+			kwaakaAdmin.GET("/menu-versions/:version_id", server.GetMenuVersion)
+			kwaakaAdmin.GET("/menu-versions/:version_id/diff", server.DiffMenuVersions)
+			kwaakaAdmin.POST("/menu-versions/:version_id/rollback", server.RollbackMenuVersion)
+			kwaakaAdmin.POST("/menu/dry-run-upload", server.DryRunUploadMenu)
+			kwaakaAdmin.GET("/menu/:menu_id/match-suggestions", server.SuggestProductMatches)
+			kwaakaAdmin.POST("/menu/:menu_id/match-suggestions/accept", server.AcceptProductMatches)
+			kwaakaAdmin.GET("/markup-rules/:store_id", server.GetMarkupRules)
+			kwaakaAdmin.PUT("/markup-rules/:store_id", server.UpdateMarkupRules)
+			kwaakaAdmin.POST("/markup-rules/:store_id/preview", server.PreviewMarkup)
+			kwaakaAdmin.GET("/aggregator-commissions/:store_id", server.GetAggregatorCommissions)
+			kwaakaAdmin.PUT("/aggregator-commissions/:store_id", server.UpdateAggregatorCommissions)
+			kwaakaAdmin.POST("/aggregator-commissions/analytics", server.AggregatorCommissionAnalytics)
+			kwaakaAdmin.POST("/aggregator-commissions/analytics/xlsx", server.AggregatorCommissionAnalyticsToXlsx)
 			kwaakaAdmin.GET("/get_all_stores/:restaurant_group_id", server.GetRestaurantsByGroupId)
 			kwaakaAdmin.GET("/:restaurant_group_id", server.GetStoresInRestaurantGroupByQuery)
 			kwaakaAdmin.GET("/get-order-by-delivery-id/:delivery_id", server.GetCustomerByDeliveryId)
@@ -509,6 +601,7 @@ func (server *Server) register(engine *gin.Engine, isProduction bool) {
 			kwaakaAdmin.POST("/polygon", server.CreatePolygon)
 			kwaakaAdmin.PUT("/polygon", server.UpdatePolygon)
 			kwaakaAdmin.GET("/polygon/:restaurant_id", server.GetPolygonByRestaurantID)
+			kwaakaAdmin.POST("/delivery-quote/:restaurant_id", server.QuoteDelivery)
 
 			kwaakaAdmin.POST("/payment-order", server.CreatePaymentOrder)
 			kwaakaAdmin.POST("/payment-order/create-payment-link/:order_id", server.CreatePaymentLink)
@@ -519,6 +612,7 @@ func (server *Server) register(engine *gin.Engine, isProduction bool) {
 			kwaakaAdmin.POST("/order-report/kwaaka/totals", server.OrderReportForKwaakaTotals)
 			kwaakaAdmin.POST("/order-report/xlsx", server.OrderReportToXlsx)
 			kwaakaAdmin.POST("/refund/:order_id", server.RefundPayment)
+			kwaakaAdmin.POST("/refund-lines/:order_id", server.RefundOrderLines)
 			kwaakaAdmin.GET("/get-refund/:order_id", server.GetRefund)
 
 			kwaakaAdmin.POST("/menu/:menu_id/product/:product_id/name", server.AddNameInProduct)
@@ -545,6 +639,8 @@ func (server *Server) register(engine *gin.Engine, isProduction bool) {
 			promoCode.GET("/code/:promo-code", server.GetAvailablePromoCodeByCode)
 			promoCode.GET("/:promo-code/restaurant/:restaurant-id", server.GetPromoCodeByCodeAndRestaurantId)
 			promoCode.GET("/restaurant/:restaurant-id", server.GetPromoCodesByRestaurantID)
+			promoCode.POST("/analytics", server.PromoCodeAnalytics)
+			promoCode.POST("/redemptions/:order_id/rollback", server.RollbackPromoCodeRedemption)
 		}
 
 		legalEntityPayment := kwaakaAdmin.Group("/legal-entity-payment")
@@ -560,6 +656,18 @@ func (server *Server) register(engine *gin.Engine, isProduction bool) {
 			legalEntityPayment.PATCH("/confirm-payment", server.ConfirmPayment)
 		}
 
+		aggregatorOutbox := kwaakaAdmin.Group("/aggregator-outbox")
+		{
+			aggregatorOutbox.POST("/list", server.GetAggregatorOutboxMessages)
+			aggregatorOutbox.POST("/:message_id/requeue", server.RequeueAggregatorOutboxMessage)
+		}
+
+		aggregatorSimulator := kwaakaAdmin.Group("/aggregator-simulator")
+		{
+			aggregatorSimulator.GET("/calls", server.GetAggregatorSimulatorCalls)
+			aggregatorSimulator.POST("/reset", server.ResetAggregatorSimulator)
+		}
+
 		dispatcher := kwaakaAdmin.Group("/dispatcher")
 		{
 			dispatcher.GET("/customer/phone/:phone/orders", server.GetOrdersByCustomerPhone)
@@ -574,6 +682,8 @@ func (server *Server) register(engine *gin.Engine, isProduction bool) {
 		whatsapp := kwaakaAdmin.Group("/whatsapp")
 		{
 			whatsapp.POST("/send-newsletter", server.SendNewsletter)
+			whatsapp.GET("/newsletters/:newsletter_id", server.GetNewsletter)
+			whatsapp.GET("/newsletters/restaurant-group/:rest_group_id", server.GetNewsletters)
 			whatsapp.POST("/send-message", server.SendMessage)
 		}
 	}
@@ -5,27 +5,97 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/kwaaka-team/orders-core/core/errors"
 	"github.com/kwaaka-team/orders-core/core/integration_api/resources/v1/dto"
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
 	"github.com/kwaaka-team/orders-core/service/payment/models"
 	paymentDto "github.com/kwaaka-team/orders-core/service/payment/whatsapp/dto"
 	wppService "github.com/kwaaka-team/orders-core/service/whatsapp"
+	wppRepository "github.com/kwaaka-team/orders-core/service/whatsapp/repository"
 	"net/http"
+	"time"
 )
 
+// SendNewsletter
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for scheduling whatsapp newsletter of restaurant group
+//	@Security	ApiKeyAuth
+//	@Summary	Recipients are qr menu customers matching segment, text supports {{name}} and {{promo_code}}, send_time is in stores timezone
+//	@Param		request	body		models.ScheduleNewsletterRequest	true	"request"
+//	@Success	200		{object}	models.Newsletter
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/whatsapp/send-newsletter [post]
 func (srv *Server) SendNewsletter(c *gin.Context) {
-	var req dto.SendNewsletterRequest
+	var req coreModels.ScheduleNewsletterRequest
 	if err := c.BindJSON(&req); err != nil {
 		c.Set(errorKey, err)
 		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
 		return
 	}
 
-	if err := srv.WhatsappService.SendNewsletter(c.Request.Context(), req.RestGroupId, req.Text, req.Name); err != nil {
+	newsletter, err := srv.WhatsappService.ScheduleNewsletter(c.Request.Context(), req)
+	if err != nil {
 		c.Set(errorKey, err)
 		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{Msg: err.Error()})
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	c.JSON(http.StatusOK, newsletter)
+}
+
+// GetNewsletter
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting newsletter with delivery status of every recipient
+//	@Security	ApiKeyAuth
+//	@Param		newsletter_id	path		string	true	"newsletter_id"
+//	@Success	200				{object}	models.Newsletter
+//	@Failure	404				{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/whatsapp/newsletters/{newsletter_id} [get]
+func (srv *Server) GetNewsletter(c *gin.Context) {
+	newsletter, err := srv.WhatsappService.GetNewsletter(c.Request.Context(), c.Param("newsletter_id"))
+	if err != nil {
+		c.Set(errorKey, err)
+		if goErr.Is(err, wppRepository.ErrNewsletterNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, errors.ErrorResponse{Msg: err.Error()})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newsletter)
+}
+
+// GetNewsletters
+//
+//	@Tags		kwaaka-admin
+//	@Title		Method for getting newsletters of restaurant group without deliveries
+//	@Security	ApiKeyAuth
+//	@Param		rest_group_id	path		string	true	"rest_group_id"
+//	@Success	200				{array}		models.Newsletter
+//	@Failure	500				{object}	errors.ErrorResponse
+//	@Router		/v1/kwaaka-admin/whatsapp/newsletters/restaurant-group/{rest_group_id} [get]
+func (srv *Server) GetNewsletters(c *gin.Context) {
+	newsletters, err := srv.WhatsappService.GetNewsletters(c.Request.Context(), c.Param("rest_group_id"))
+	if err != nil {
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newsletters)
+}
+
+func (srv *Server) SendScheduledNewsletters(c *gin.Context) {
+	newsletters, err := srv.WhatsappService.SendScheduledNewsletters(c.Request.Context(), time.Now().UTC())
+	if err != nil {
+		srv.Logger.Error(err)
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, errors.ErrorResponse{Msg: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newsletters)
 }
 
 func (srv *Server) SendMessage(c *gin.Context) {
@@ -67,3 +67,36 @@ func (server *Server) CreateOrderWolt(c *gin.Context) {
 
 	c.JSON(http.StatusOK, "")
 }
+
+// UpdateOrderWolt docs
+//
+//	@Tags		wolt
+//	@Title		Method for update order items
+//	@Security	ApiKeyAuth
+//	@Summary	Method applies changed products of Order in pos
+//	@Param		order	body		models.OrderNotification	true	"order"
+//	@Failure	400		{object}	errors.ErrorResponse
+//	@Router		/wolt/updateOrder [post]
+func (server *Server) UpdateOrderWolt(c *gin.Context) {
+	var webhook models.OrderNotification
+
+	if err := c.BindJSON(&webhook); err != nil {
+		server.Logger.Infof(errBindBody, err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{
+			Msg: err.Error(),
+		})
+		return
+	}
+
+	if _, err := server.orderModificationService.UpdateOrderItemsByAggregatorRequest(c.Request.Context(), webhook.Body.VenueId, models.WOLT.String(), webhook); err != nil {
+		server.Logger.Infof("update order items error: %s", err.Error())
+		c.Set(errorKey, err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, errors.ErrorResponse{
+			Msg: err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
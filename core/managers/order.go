@@ -929,6 +929,8 @@ func (manager OrderManager) sendKwaaka3plOrder(ctx context.Context, order models
 		})
 	}
 
+	checkDeliveryZone(order, store)
+
 	if err := manager.kwaaka3plService.Create3plOrder(ctx, models3.CreateDeliveryRequest{
 		ID:                order.ID,
 		FullDeliveryPrice: order.FullDeliveryPrice,
@@ -1025,6 +1027,16 @@ func (manager OrderManager) sendKwaaka3plOrder(ctx context.Context, order models
 	return nil
 }
 
+// checkDeliveryZone - созданный и оплаченный заказ отправляется в 3pl и вне зон доставки ресторана, адрес вне зон только логируется
+func checkDeliveryZone(order models.Order, store coreStoreModels.Store) {
+	if _, err := store.Kwaaka3PL.QuoteDelivery(coreStoreModels.Coordinates{
+		Latitude:  order.DeliveryAddress.Latitude,
+		Longitude: order.DeliveryAddress.Longitude,
+	}, order.EstimatedTotalPrice.Value); errors.Is(err, coreStoreModels.ErrOutOfDeliveryZone) {
+		log.Warn().Msgf("core/managers/order - fn checkDeliveryZone: order %s is dispatched out of restaurant %s delivery zones", order.ID, store.ID)
+	}
+}
+
 func (manager OrderManager) cancelAndFindAnotherProvider(ctx context.Context, order models.Order, store coreStoreModels.Store) error {
 	deliveryInfo, err := manager.kwaaka3plService.GetDeliveryInfoByOrderId(ctx, order.OrderID)
 	if err != nil {
@@ -1047,6 +1059,8 @@ func (manager OrderManager) cancelAndFindAnotherProvider(ctx context.Context, or
 		})
 	}
 
+	checkDeliveryZone(order, store)
+
 	providerProposals, err := manager.kwaaka3plService.ListPotentialProviders(ctx, models3.ListProvidersRequest{
 		Address: models3.OrderAddress{
 			City:   order.DeliveryAddress.City,
@@ -1777,6 +1791,9 @@ func isNecessaryUpdateOrderStatus(order models.Order, store coreStoreModels.Stor
 		return true
 	}
 	for _, v := range order.StatusesHistory {
+		if v.Rejected {
+			continue
+		}
 		histStatus := ConvertPosStatusToAggregator(v.Name, order, store)
 		currStatus := ConvertPosStatusToAggregator(order.Status, order, store)
 		if histStatus == currStatus || currStatus == "" {
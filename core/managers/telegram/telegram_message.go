@@ -59,6 +59,7 @@ const (
 	NoDeliveryDispatcher                NotificationType = "no_delivery_dispatcher"
 	AutoUpdatePublicateMenu             NotificationType = "auto_update_publicate_menu"
 	PutProductToStopListWithErrSolution NotificationType = "put_product_to_stoplist_with_err_solution"
+	LowRatingReview                     NotificationType = "low_rating_review"
 )
 
 func (s NotificationType) String() string {
@@ -362,7 +363,7 @@ func ConstructStoreClosedToNotify(store coreStoreModels.Store, msg, deliveryServ
 	}
 }
 
-func ConstructStoreStatusReportToNotify(store coreStoreModels.Store, durations []coreStoreModels.OpenTimeDuration) string {
+func ConstructStoreStatusReportToNotify(store coreStoreModels.Store, durations []coreStoreModels.OpenTimeDuration, breaches []models.SLABreach) string {
 	if len(durations) == 0 {
 		return ""
 	}
@@ -389,9 +390,23 @@ func ConstructStoreStatusReportToNotify(store coreStoreModels.Store, durations [
 
 	message = fmt.Sprintf("%s Uptime:\n%s = %d%% (avg)\n%s", store.Name, store.Name, sum/len(durations), message)
 
+	if len(breaches) != 0 {
+		message += "\nХудшие нарушения SLA:\n"
+		for _, breach := range breaches {
+			message += fmt.Sprintf("-%s (%s): %s %.0f мин при норме %.0f мин\n", breach.OrderCode, breach.DeliveryService, slaStageNames[breach.Stage], breach.Duration, breach.Threshold)
+		}
+	}
+
 	return message
 }
 
+var slaStageNames = map[string]string{
+	models.SLAStageWebhookToAccepted:         "принятие в pos",
+	models.SLAStageAcceptedToCookingComplete: "приготовление",
+	models.SLAStageReadyToPickedUp:           "ожидание курьера",
+	models.SLAStageTimeToRead:                "прочтение заказа",
+}
+
 func ConstructOrderStatusChangeMessage(msg *tgbotapi.MessageConfig, status string) {
 	var text string
 
@@ -651,6 +666,34 @@ func ConstructPutProductToStopListWithErrSolutionMessage(store coreStoreModels.S
 	return message + errSolution
 }
 
+func ConstructLowRatingReviewMessage(order models.Order, store coreStoreModels.Store, review models.OrderReview) string {
+	message := fmt.Sprintf(
+		"<b>[⭐] Низкая оценка заказа: %d из %d</b>\n"+
+			"<b>Ресторан:</b> %s\n"+
+			"<b>ID заказа:</b> %s\n"+
+			"<b>Cервис:</b> %s\n",
+		review.Rating, models.ReviewMaxRating, store.Name, order.OrderID, order.DeliveryService)
+
+	if review.CourierProvider != "" {
+		deliveryDispatcher := review.CourierProvider
+		if val, ok := deliveryDispatcherMap[review.CourierProvider]; ok {
+			deliveryDispatcher = val
+		}
+		message += fmt.Sprintf("<b>Доставка:</b> %s\n", deliveryDispatcher)
+	}
+	if review.Comment != "" {
+		message += fmt.Sprintf("<b>Комментарий:</b> %s\n", review.Comment)
+	}
+
+	for _, product := range review.Products {
+		message += fmt.Sprintf("<b>%s:</b> %d %s\n", product.Name, product.Rating, product.Comment)
+	}
+
+	customerInfo := fmt.Sprintf("\n<b>Данные о клиенте:</b>\n<b>Имя:</b> %s\n<b>Номер:</b> %s\n", order.Customer.Name, order.Customer.PhoneNumber)
+
+	return message + customerInfo
+}
+
 func checkStoreTimezone(tz string, offset float64) float64 {
 	switch tz {
 	case "Asia/Almaty":
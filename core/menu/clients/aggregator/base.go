@@ -28,6 +28,7 @@ type Base interface {
 	BulkAttribute(ctx context.Context, storeID string, attributes models.Attributes) (string, error)
 	GetMenu(ctx context.Context, extStoreId string) (models.Menu, error)
 	ValidateMenu(ctx context.Context, request models.MenuValidateRequest) (models.MenuUploadTransaction, error)
+	DryRunMenu(ctx context.Context, menu models.Menu, store storeModels.Store, offers []models.BkOffers) (models.DryRunReport, error)
 }
 
 // NewManager used for integrate with aggregators as glovo, wolt, yandex etc.
@@ -107,6 +107,10 @@ func (m mnm) ValidateMenu(ctx context.Context, request models.MenuValidateReques
 	return models.MenuUploadTransaction{}, errors.New("method not implemented")
 }
 
+func (m mnm) DryRunMenu(ctx context.Context, menu models.Menu, store storeModels.Store, offers []models.BkOffers) (models.DryRunReport, error) {
+	return models.DryRunReport{}, errors.New("method not implemented")
+}
+
 func NewManager(ctx context.Context, username, password, baseUrl string) (mnm, error) {
 
 	cli, err := deliverooConf.NewDeliverooClient(&deliverooCli.Config{
@@ -0,0 +1,113 @@
+package express24
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/kwaaka-team/orders-core/core/menu/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+)
+
+var dryRunLimits = models.DryRunLimits{
+	MaxNameLength:        255,
+	MaxDescriptionLength: 1000,
+}
+
+// DryRunMenu validates sync request for express24 without calling express24 api
+func (s express24MenuImpl) DryRunMenu(ctx context.Context, menu models.Menu, store storeModels.Store, offers []models.BkOffers) (models.DryRunReport, error) {
+	report := models.NewDryRunReport(models.EXPRESS24.String())
+
+	// маршаллер берет первое название и описание без проверки
+	products := make(models.Products, 0, len(menu.Products))
+	for _, product := range menu.Products {
+		if product.IsDeleted {
+			continue
+		}
+		switch {
+		case len(product.Name) == 0:
+			report.AddError(models.DryRunNameEmpty, models.DryRunProduct, product.ExtID, "name", "name is empty")
+		case len(product.Description) == 0:
+			report.AddError(models.DryRunDescriptionEmpty, models.DryRunProduct, product.ExtID, "description", "description is empty")
+		case len(product.Price) == 0:
+			report.AddError(models.DryRunPriceNotPositive, models.DryRunProduct, product.ExtID, "price", "price is empty")
+		default:
+			products = append(products, product)
+			if price := product.Price[0].Value; price != math.Trunc(price) {
+				report.AddWarning(models.DryRunPriceNotPositive, models.DryRunProduct, product.ExtID, "price",
+					fmt.Sprintf("price %v will be truncated to %d", price, int(price)))
+			}
+		}
+	}
+	menu.Products = products
+
+	syncMenu := s.toSyncRequest(menu, store.Express24.Vat)
+
+	categories := make(map[string]struct{}, len(syncMenu.Categories))
+	for _, category := range syncMenu.Categories {
+		categories[category.ExternalID] = struct{}{}
+		report.CheckName(models.DryRunSection, category.ExternalID, category.Name, dryRunLimits)
+	}
+
+	attributes := make(map[string]struct{}, len(syncMenu.ModifierItems))
+	for _, item := range syncMenu.ModifierItems {
+		attributes[item.ExternalID] = struct{}{}
+		report.CheckName(models.DryRunAttribute, item.ExternalID, item.Name, dryRunLimits)
+	}
+
+	groups := make(map[string]models.AttributeGroup, len(menu.AttributesGroups))
+	for _, group := range menu.AttributesGroups {
+		groups[group.ExtID] = group
+	}
+
+	modifiers := make(map[string]struct{}, len(syncMenu.Modifiers))
+	for _, modifier := range syncMenu.Modifiers {
+		modifiers[modifier.ExternalID] = struct{}{}
+		report.CheckName(models.DryRunAttributeGroup, modifier.ExternalID, modifier.Name, dryRunLimits)
+
+		count := 0
+		for _, itemID := range modifier.Items {
+			if _, ok := attributes[itemID]; ok {
+				count++
+			}
+		}
+		group := groups[modifier.ExternalID]
+		report.CheckMinMax(modifier.ExternalID, group.Min, group.Max, count)
+		if group.Min > 0 {
+			report.AddWarning(models.DryRunMinMaxInvalid, models.DryRunAttributeGroup, modifier.ExternalID, "min",
+				"express24 modifiers have no min, required group will be optional")
+		}
+	}
+
+	marshalled := make(map[string]struct{}, len(syncMenu.Products))
+	for _, product := range syncMenu.Products {
+		marshalled[product.ExternalID] = struct{}{}
+
+		report.CheckName(models.DryRunProduct, product.ExternalID, product.Name, dryRunLimits)
+		report.CheckDescription(models.DryRunProduct, product.ExternalID, product.Description, dryRunLimits)
+		report.CheckPrice(models.DryRunProduct, product.ExternalID, float64(product.Price))
+
+		image := ""
+		if len(product.Images) != 0 {
+			image = product.Images[0].URL
+		}
+		report.CheckImage(models.DryRunProduct, product.ExternalID, image, dryRunLimits)
+
+		if _, ok := categories[product.CategoryID]; !ok {
+			report.AddError(models.DryRunMissingReference, models.DryRunProduct, product.ExternalID, "section",
+				fmt.Sprintf("category %s is not found", product.CategoryID))
+		}
+		for _, modifierID := range product.Modifiers {
+			if _, ok := modifiers[modifierID]; !ok {
+				report.AddError(models.DryRunMissingReference, models.DryRunProduct, product.ExternalID, "attributes_groups",
+					fmt.Sprintf("modifier %s is not found", modifierID))
+			}
+		}
+	}
+
+	report.ProductsCount = len(marshalled)
+	report.AttributesCount = len(attributes)
+	report.Finish()
+
+	return report, nil
+}
@@ -32,6 +32,7 @@ type Express24Menu interface {
 	BulkAttribute(ctx context.Context, storeID string, attributes models.Attributes) (string, error)
 	GetMenu(ctx context.Context, extStoreId string) (models.Menu, error)
 	ValidateMenu(ctx context.Context, request models.MenuValidateRequest) (models.MenuUploadTransaction, error)
+	DryRunMenu(ctx context.Context, menu models.Menu, store storeModels.Store, offers []models.BkOffers) (models.DryRunReport, error)
 }
 
 type express24MenuImpl struct {
@@ -595,6 +596,24 @@ func (s express24MenuImpl) alterBranchAvailability(ctx context.Context, product
 }
 
 func (s express24MenuImpl) createSyncRequest(ctx context.Context, menu models.Menu, vatValue int, oldMenu *dto.MenuSyncReq) (*dto.MenuSyncReq, error) {
+	newMenu := s.toSyncRequest(menu, vatValue)
+
+	generalMenu := dto.MenuSyncReq{}
+	if oldMenu != nil {
+		concatMenu := s.concatMenu(oldMenu, &newMenu)
+		generalMenu = *concatMenu
+	} else {
+		generalMenu = newMenu
+	}
+
+	if err := s.cli.SyncMenu(ctx, generalMenu); err != nil {
+		return nil, err
+	}
+
+	return &newMenu, nil
+}
+
+func (s express24MenuImpl) toSyncRequest(menu models.Menu, vatValue int) dto.MenuSyncReq {
 	var (
 		categoriesReq    []dto.MenuSyncCategoryReq
 		subCategoriesReq []dto.MenuSyncSubCategoryReq
@@ -631,27 +650,13 @@ func (s express24MenuImpl) createSyncRequest(ctx context.Context, menu models.Me
 		productsReq = append(productsReq, s.toSyncProducts(product, vatValue))
 	}
 
-	newMenu := dto.MenuSyncReq{
+	return dto.MenuSyncReq{
 		Categories:    categoriesReq,
 		SubCategories: subCategoriesReq,
 		Products:      productsReq,
 		Modifiers:     modifiersReq,
 		ModifierItems: modifierItemsReq,
 	}
-
-	generalMenu := dto.MenuSyncReq{}
-	if oldMenu != nil {
-		concatMenu := s.concatMenu(oldMenu, &newMenu)
-		generalMenu = *concatMenu
-	} else {
-		generalMenu = newMenu
-	}
-
-	if err := s.cli.SyncMenu(ctx, generalMenu); err != nil {
-		return nil, err
-	}
-
-	return &newMenu, nil
 }
 
 func (s express24MenuImpl) toModifierItem(att models.Attribute) dto.MenuSyncModifierItemsReq {
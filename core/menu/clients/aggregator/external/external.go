@@ -20,6 +20,7 @@ type External interface {
 	BulkAttribute(ctx context.Context, storeID string, attributes models.Attributes) (string, error)
 	GetMenu(ctx context.Context, extStoreId string) (models.Menu, error)
 	ValidateMenu(ctx context.Context, request models.MenuValidateRequest) (models.MenuUploadTransaction, error)
+	DryRunMenu(ctx context.Context, menu models.Menu, store storeModels.Store, offers []models.BkOffers) (models.DryRunReport, error)
 }
 
 type mnm struct {
@@ -109,3 +110,7 @@ func (m mnm) GetMenu(ctx context.Context, extStoreId string) (models.Menu, error
 func (m mnm) ValidateMenu(ctx context.Context, request models.MenuValidateRequest) (models.MenuUploadTransaction, error) {
 	return models.MenuUploadTransaction{}, ErrNotImplemented
 }
+
+func (m mnm) DryRunMenu(ctx context.Context, menu models.Menu, store storeModels.Store, offers []models.BkOffers) (models.DryRunReport, error) {
+	return models.DryRunReport{}, ErrNotImplemented
+}
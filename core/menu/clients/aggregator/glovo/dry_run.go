@@ -0,0 +1,83 @@
+package glovo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kwaaka-team/orders-core/core/menu/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+)
+
+var dryRunLimits = models.DryRunLimits{
+	MaxNameLength:        200,
+	MaxDescriptionLength: 1000,
+}
+
+// DryRunMenu validates menu marshalled for glovo without calling glovo api
+func (m mnm) DryRunMenu(ctx context.Context, menu models.Menu, store storeModels.Store, offers []models.BkOffers) (models.DryRunReport, error) {
+	report := models.NewDryRunReport(models.GLOVO.String())
+
+	menuGlovo := ToGlovoMenu(menu, offers)
+
+	attributes := make(map[string]struct{}, len(menuGlovo.Attributes))
+	for _, attribute := range menuGlovo.Attributes {
+		attributes[attribute.ID] = struct{}{}
+		report.CheckName(models.DryRunAttribute, attribute.ID, attribute.Name, dryRunLimits)
+	}
+
+	groups := make(map[string]struct{}, len(menuGlovo.AttributeGroups))
+	for _, group := range menuGlovo.AttributeGroups {
+		groups[group.ID] = struct{}{}
+		report.CheckName(models.DryRunAttributeGroup, group.ID, group.Name, dryRunLimits)
+
+		count := 0
+		for _, attributeID := range group.Attributes {
+			if _, ok := attributes[attributeID]; !ok {
+				report.AddError(models.DryRunMissingReference, models.DryRunAttributeGroup, group.ID, "attributes",
+					fmt.Sprintf("attribute %s is not found", attributeID))
+				continue
+			}
+			count++
+		}
+		report.CheckMinMax(group.ID, group.Min, group.Max, count)
+	}
+
+	inCollections := make(map[string]struct{}, len(menuGlovo.Products))
+	for _, collection := range menuGlovo.Collections {
+		for _, section := range collection.Sections {
+			report.CheckName(models.DryRunSection, section.ID, section.Name, dryRunLimits)
+			for _, productID := range section.Products {
+				inCollections[productID] = struct{}{}
+			}
+		}
+	}
+
+	marshalled := make(map[string]struct{}, len(menuGlovo.Products))
+	for _, product := range menuGlovo.Products {
+		marshalled[product.ID] = struct{}{}
+
+		report.CheckName(models.DryRunProduct, product.ID, product.Name, dryRunLimits)
+		report.CheckDescription(models.DryRunProduct, product.ID, product.Description, dryRunLimits)
+		report.CheckImage(models.DryRunProduct, product.ID, product.ImageURL, dryRunLimits)
+		report.CheckPrice(models.DryRunProduct, product.ID, product.Price)
+
+		if _, ok := inCollections[product.ID]; !ok {
+			report.AddError(models.DryRunMissingReference, models.DryRunProduct, product.ID, "section",
+				"section of product is not in any collection")
+		}
+
+		for _, groupID := range product.AttributesGroups {
+			if _, ok := groups[groupID]; !ok {
+				report.AddError(models.DryRunMissingReference, models.DryRunProduct, product.ID, "attributes_groups",
+					fmt.Sprintf("attribute group %s is not found", groupID))
+			}
+		}
+	}
+
+	report.CheckSkipped(menu.Products, menu.Sections, marshalled)
+	report.ProductsCount = len(marshalled)
+	report.AttributesCount = len(attributes)
+	report.Finish()
+
+	return report, nil
+}
@@ -26,6 +26,7 @@ type Glovo interface {
 	BulkAttribute(ctx context.Context, storeID string, attributes models.Attributes) (string, error)
 	GetMenu(ctx context.Context, extStoreId string) (models.Menu, error)
 	ValidateMenu(ctx context.Context, request models.MenuValidateRequest) (models.MenuUploadTransaction, error)
+	DryRunMenu(ctx context.Context, menu models.Menu, store storeModels.Store, offers []models.BkOffers) (models.DryRunReport, error)
 }
 
 type mnm struct {
@@ -42,6 +42,7 @@ type Moysklad interface {
 	BulkAttribute(ctx context.Context, storeID string, attributes models.Attributes) (string, error)
 	GetMenu(ctx context.Context, extStoreId string) (models.Menu, error)
 	ValidateMenu(ctx context.Context, request models.MenuValidateRequest) (models.MenuUploadTransaction, error)
+	DryRunMenu(ctx context.Context, menu models.Menu, store storeModels.Store, offers []models.BkOffers) (models.DryRunReport, error)
 }
 
 type Manager struct {
@@ -318,3 +319,7 @@ func (m Manager) add(ctx context.Context, position models.MoySkladPosition) (str
 func (m Manager) ValidateMenu(ctx context.Context, request models.MenuValidateRequest) (models.MenuUploadTransaction, error) {
 	return models.MenuUploadTransaction{}, nil
 }
+
+func (m Manager) DryRunMenu(ctx context.Context, menu models.Menu, store storeModels.Store, offers []models.BkOffers) (models.DryRunReport, error) {
+	return models.DryRunReport{}, constErrors.ErrNotImplemented
+}
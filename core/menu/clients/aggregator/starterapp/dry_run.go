@@ -0,0 +1,93 @@
+package starterapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kwaaka-team/orders-core/core/menu/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+)
+
+var dryRunLimits = models.DryRunLimits{
+	MaxNameLength:        255,
+	MaxDescriptionLength: 2000,
+}
+
+// DryRunMenu validates menu for starter app without calling starter app api.
+// Starter app menu is synced entity by entity with ids from previous responses, so requests are checked on menu models
+func (s starterAppImpl) DryRunMenu(ctx context.Context, menu models.Menu, store storeModels.Store, offers []models.BkOffers) (models.DryRunReport, error) {
+	report := models.NewDryRunReport(models.STARTERAPP.String())
+
+	sections := make(map[string]struct{}, len(menu.Sections))
+	for _, section := range menu.Sections {
+		sections[section.ExtID] = struct{}{}
+		report.CheckName(models.DryRunSection, section.ExtID, section.Name, dryRunLimits)
+	}
+
+	attributes := make(map[string]struct{}, len(menu.Attributes))
+	for _, attribute := range menu.Attributes {
+		if attribute.IsDeleted {
+			continue
+		}
+		attributes[attribute.ExtID] = struct{}{}
+		report.CheckName(models.DryRunAttribute, attribute.ExtID, attribute.Name, dryRunLimits)
+	}
+
+	groups := make(map[string]struct{}, len(menu.AttributesGroups))
+	for _, group := range menu.AttributesGroups {
+		groups[group.ExtID] = struct{}{}
+		report.CheckName(models.DryRunAttributeGroup, group.ExtID, group.Name, dryRunLimits)
+
+		count := 0
+		for _, attributeID := range group.Attributes {
+			if _, ok := attributes[attributeID]; ok {
+				count++
+			}
+		}
+		report.CheckMinMax(group.ExtID, group.Min, group.Max, count)
+	}
+
+	marshalled := make(map[string]struct{}, len(menu.Products))
+	for _, product := range menu.Products {
+		if product.IsDeleted {
+			continue
+		}
+		marshalled[product.ExtID] = struct{}{}
+
+		name, description, image, price := "", "", "", 0.0
+		if len(product.Name) != 0 {
+			name = product.Name[0].Value
+		}
+		if len(product.Description) != 0 {
+			description = product.Description[0].Value
+		}
+		if len(product.ImageURLs) != 0 {
+			image = product.ImageURLs[0]
+		}
+		if len(product.Price) != 0 {
+			price = product.Price[0].Value
+		}
+
+		report.CheckName(models.DryRunProduct, product.ExtID, name, dryRunLimits)
+		report.CheckDescription(models.DryRunProduct, product.ExtID, description, dryRunLimits)
+		report.CheckImage(models.DryRunProduct, product.ExtID, image, dryRunLimits)
+		report.CheckPrice(models.DryRunProduct, product.ExtID, price)
+
+		if _, ok := sections[product.Section]; !ok {
+			report.AddError(models.DryRunMissingReference, models.DryRunProduct, product.ExtID, "section",
+				fmt.Sprintf("section %s is not found", product.Section))
+		}
+		for _, groupID := range product.AttributesGroups {
+			if _, ok := groups[groupID]; !ok {
+				report.AddError(models.DryRunMissingReference, models.DryRunProduct, product.ExtID, "attributes_groups",
+					fmt.Sprintf("attribute group %s is not found", groupID))
+			}
+		}
+	}
+
+	report.ProductsCount = len(marshalled)
+	report.AttributesCount = len(attributes)
+	report.Finish()
+
+	return report, nil
+}
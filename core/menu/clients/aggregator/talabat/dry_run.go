@@ -0,0 +1,164 @@
+package talabat
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/kwaaka-team/orders-core/core/menu/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	talabatModels "github.com/kwaaka-team/orders-core/pkg/talabat/models"
+)
+
+// menu -> product -> topping -> product
+var dryRunLimits = models.DryRunLimits{
+	MaxNameLength:        100,
+	MaxDescriptionLength: 500,
+	MaxNestingDepth:      4,
+}
+
+// DryRunMenu validates menu marshalled for talabat without calling talabat api.
+// Old talabat menu is built from menus of all branches in db, so for it menu is checked before marshalling
+func (m mnm) DryRunMenu(ctx context.Context, menu models.Menu, store storeModels.Store, offers []models.BkOffers) (models.DryRunReport, error) {
+	report := models.NewDryRunReport(models.TALABAT.String())
+
+	if !store.Talabat.IsNewMenu {
+		dryRunOldMenu(&report, menu)
+		report.Finish()
+		return report, nil
+	}
+
+	// продукты без названия или описания маршаллер не переживет, они отмечаются ошибкой и не маршаллятся
+	products := make(models.Products, 0, len(menu.Products))
+	for _, product := range menu.Products {
+		if isValidProduct(product) && len(product.Name) == 0 {
+			report.AddError(models.DryRunNameEmpty, models.DryRunProduct, product.ExtID, "name", "name is empty")
+			continue
+		}
+		if isValidProduct(product) && len(product.Description) == 0 {
+			report.AddError(models.DryRunDescriptionEmpty, models.DryRunProduct, product.ExtID, "description", "description is empty")
+			continue
+		}
+		products = append(products, product)
+	}
+	menu.Products = products
+
+	catalog, err := m.constructTalabatNewMenu(ctx, menu)
+	if err != nil {
+		return models.DryRunReport{}, err
+	}
+
+	marshalled := make(map[string]struct{})
+	attributes := make(map[string]struct{})
+
+	for id, item := range catalog.Items {
+		switch item.Type {
+		case "Category":
+			report.CheckName(models.DryRunSection, id, catalogTitle(item.Title), dryRunLimits)
+		case "Topping":
+			count := 0
+			for attributeID := range item.Products {
+				if _, ok := catalog.Items[attributeID]; ok {
+					count++
+				}
+			}
+			if item.Quantity != nil {
+				report.CheckMinMax(id, item.Quantity.Min, item.Quantity.Max, count)
+			}
+			report.CheckName(models.DryRunAttributeGroup, id, catalogTitle(item.Title), dryRunLimits)
+		case "Product":
+			if item.Title == nil {
+				attributes[id] = struct{}{}
+				continue
+			}
+			marshalled[id] = struct{}{}
+
+			report.CheckName(models.DryRunProduct, id, catalogTitle(item.Title), dryRunLimits)
+			report.CheckDescription(models.DryRunProduct, id, catalogTitle(item.Description), dryRunLimits)
+			if len(item.Images) == 0 {
+				report.CheckImage(models.DryRunProduct, id, "", dryRunLimits)
+			}
+			price, _ := strconv.ParseFloat(item.Price, 64)
+			report.CheckPrice(models.DryRunProduct, id, price)
+		}
+	}
+
+	if root, ok := catalog.Items[menu.ID]; ok {
+		checkCatalogDepth(&report, catalog, root, 1, map[string]struct{}{})
+	}
+
+	report.CheckSkipped(menu.Products, menu.Sections, marshalled)
+	report.ProductsCount = len(marshalled)
+	report.AttributesCount = len(attributes)
+	report.Finish()
+
+	return report, nil
+}
+
+func checkCatalogDepth(report *models.DryRunReport, catalog talabatModels.Catalog, item talabatModels.CatalogItem, depth int, path map[string]struct{}) {
+	if depth > dryRunLimits.MaxNestingDepth {
+		report.CheckDepth(models.DryRunProduct, item.Id, depth, dryRunLimits)
+		return
+	}
+
+	path[item.Id] = struct{}{}
+	defer delete(path, item.Id)
+
+	for _, children := range []map[string]talabatModels.SubItem{item.Products, item.Toppings} {
+		for id := range children {
+			if _, ok := path[id]; ok {
+				report.AddError(models.DryRunNestingTooDeep, models.DryRunProduct, id, "", fmt.Sprintf("item %s is nested into itself", id))
+				continue
+			}
+			child, ok := catalog.Items[id]
+			if !ok {
+				report.AddError(models.DryRunMissingReference, models.DryRunProduct, item.Id, "", fmt.Sprintf("item %s is not found", id))
+				continue
+			}
+			checkCatalogDepth(report, catalog, child, depth+1, path)
+		}
+	}
+}
+
+func dryRunOldMenu(report *models.DryRunReport, menu models.Menu) {
+	groups := make(map[string]models.AttributeGroup, len(menu.AttributesGroups))
+	for _, group := range menu.AttributesGroups {
+		groups[group.ExtID] = group
+	}
+	used := make(map[string]models.AttributeGroup)
+
+	for _, product := range menu.Products {
+		if !isValidProduct(product) {
+			continue
+		}
+		report.ProductsCount++
+
+		report.CheckName(models.DryRunProduct, product.ExtID, getLangValueByLangCode(product.Name, "en"), dryRunLimits)
+		report.CheckDescription(models.DryRunProduct, product.ExtID, getLangValueByLangCode(product.Description, "en"), dryRunLimits)
+		report.CheckPrice(models.DryRunProduct, product.ExtID, product.Price[0].Value)
+
+		// старый маршаллер берет первую картинку без проверки
+		image := ""
+		if len(product.ImageURLs) != 0 {
+			image = product.ImageURLs[0]
+		}
+		report.CheckImage(models.DryRunProduct, product.ExtID, image, models.DryRunLimits{ImageRequired: true})
+
+		for _, groupID := range product.AttributesGroups {
+			if group, ok := groups[groupID]; ok {
+				used[groupID] = group
+			}
+		}
+	}
+
+	for _, group := range used {
+		report.CheckMinMax(group.ExtID, group.Min, group.Max, len(group.Attributes))
+	}
+}
+
+func catalogTitle(title *talabatModels.Title) string {
+	if title == nil {
+		return ""
+	}
+	return title.Default
+}
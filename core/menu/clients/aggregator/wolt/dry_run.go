@@ -0,0 +1,79 @@
+package wolt
+
+import (
+	"context"
+
+	"github.com/kwaaka-team/orders-core/core/menu/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	woltModels "github.com/kwaaka-team/orders-core/pkg/wolt/clients/dto"
+)
+
+// category -> item -> option -> value -> sub option value
+var dryRunLimits = models.DryRunLimits{
+	MaxNameLength:        150,
+	MaxDescriptionLength: 2000,
+	MaxNestingDepth:      5,
+}
+
+// DryRunMenu validates menu marshalled for wolt without calling wolt api
+func (m mnm) DryRunMenu(ctx context.Context, menu models.Menu, store storeModels.Store, offers []models.BkOffers) (models.DryRunReport, error) {
+	report := models.NewDryRunReport(models.WOLT.String())
+
+	menuWolt := toWoltMenu(store, menu)
+
+	marshalled := make(map[string]struct{})
+	attributes := make(map[string]struct{})
+
+	for _, category := range menuWolt.Categories {
+		report.CheckName(models.DryRunSection, category.ID, itemName(category.Name), dryRunLimits)
+
+		for _, item := range category.Items {
+			marshalled[item.ExternalData] = struct{}{}
+
+			report.CheckName(models.DryRunProduct, item.ExternalData, itemName(item.Name), dryRunLimits)
+			report.CheckDescription(models.DryRunProduct, item.ExternalData, itemName(item.Description), dryRunLimits)
+			report.CheckImage(models.DryRunProduct, item.ExternalData, item.ImageUrl, dryRunLimits)
+			report.CheckPrice(models.DryRunProduct, item.ExternalData, float64(item.Price))
+
+			depth := 2
+			for _, option := range item.Options {
+				depth = max(depth, 3)
+				report.CheckName(models.DryRunAttributeGroup, option.ExternalData, itemName(option.Name), dryRunLimits)
+				if option.SelectionRange != nil {
+					report.CheckMinMax(option.ExternalData, option.SelectionRange.Min, option.SelectionRange.Max, len(option.Values))
+				} else if len(option.Values) == 0 {
+					report.CheckMinMax(option.ExternalData, 1, 1, 0)
+				}
+
+				for _, value := range option.Values {
+					depth = max(depth, 4)
+					if len(value.SubOptionValues) != 0 {
+						depth = max(depth, 5)
+					}
+					if _, ok := attributes[value.ExternalData]; ok {
+						continue
+					}
+					attributes[value.ExternalData] = struct{}{}
+					report.CheckName(models.DryRunAttribute, value.ExternalData, itemName(value.Name), dryRunLimits)
+				}
+			}
+			report.CheckDepth(models.DryRunProduct, item.ExternalData, depth, dryRunLimits)
+		}
+	}
+
+	report.CheckSkipped(menu.Products, menu.Sections, marshalled)
+	report.ProductsCount = len(marshalled)
+	report.AttributesCount = len(attributes)
+	report.Finish()
+
+	return report, nil
+}
+
+func itemName(names []woltModels.ItemName) string {
+	for _, name := range names {
+		if name.Value != "" {
+			return name.Value
+		}
+	}
+	return ""
+}
@@ -85,3 +85,7 @@ func (m mnm) UploadMenu(ctx context.Context, menuId, extStoreId string, menu mod
 func (m mnm) ValidateMenu(ctx context.Context, request models.MenuValidateRequest) (models.MenuUploadTransaction, error) {
 	return models.MenuUploadTransaction{}, nil
 }
+
+func (m mnm) DryRunMenu(ctx context.Context, menu models.Menu, store storeModels.Store, offers []models.BkOffers) (models.DryRunReport, error) {
+	return models.DryRunReport{}, errors.New("method not implemented")
+}
@@ -77,6 +77,9 @@ type MenuRepository interface {
 	UpdateProductStarterAppOfferIDByExtID(ctx context.Context, menuID, extID, starterAppOfferID string) error
 	UpdateAttributeStarterAppOfferIDByExtID(ctx context.Context, menuID, extID, starterAppOfferID string) error
 
+	GetMenuVersion(ctx context.Context, versionID string) (models.MenuVersion, error)
+	ListMenuVersions(ctx context.Context, menuID string, pagination selector.Pagination) ([]models.MenuVersion, int64, error)
+
 	SectionRepository
 	ProductRepository
 	AttributeRepository
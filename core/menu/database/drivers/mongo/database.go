@@ -207,6 +207,9 @@ func (m *Mongo) ensureIndexes() error {
 	if err := m.ensureMenuUploadTransactionIndexes(ctx); err != nil {
 		return err
 	}
+	if err := m.ensureMenuVersionIndexes(ctx); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -278,6 +281,43 @@ func (m *Mongo) ensureMenuUploadTransactionIndexes(ctx context.Context) (err err
 	return err
 }
 
+// ensureMenuVersionIndexes - уникальный номер версии в рамках меню, на нем держится запись версий без гонок
+func (m *Mongo) ensureMenuVersionIndexes(ctx context.Context) (err error) {
+	col := m.DB.Collection(menuVersionCollection)
+
+	existingIndexes, err := m.existingIndexes(ctx, col)
+	if err != nil {
+		return err
+	}
+
+	indexesMap := map[string]mongo.IndexModel{
+		"menu_id_version_idx": {
+			Keys:    bson.D{{Key: "menu_id", Value: 1}, {Key: "version", Value: -1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	indexes := make([]mongo.IndexModel, 0, len(indexesMap))
+
+	for name, idx := range indexesMap {
+		if _, ok := existingIndexes[name]; ok {
+			continue
+		}
+
+		idx.Options.SetName(name)
+		indexes = append(indexes, idx)
+	}
+
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	opts := options.CreateIndexes().SetMaxTime(m.ensureIdxTimeout)
+	_, err = col.Indexes().CreateMany(ctx, indexes, opts)
+
+	return err
+}
+
 func (m *Mongo) existingIndexes(ctx context.Context, collection *mongo.Collection) (map[string]struct{}, error) {
 	cur, err := collection.Indexes().List(ctx)
 	if err != nil {
@@ -20,12 +20,14 @@ import (
 
 type MenuRepository struct {
 	menuColl                 *mongo.Collection
+	versions                 *MenuVersionRepository
 	entityChangesHistoryRepo entity_changes_history.Repository
 }
 
 func NewMenuRepository(menuColl *mongo.Collection, entityChangesHistoryRepo entity_changes_history.Repository) *MenuRepository {
 	return &MenuRepository{
 		menuColl:                 menuColl,
+		versions:                 NewMenuVersionRepository(menuColl.Database()),
 		entityChangesHistoryRepo: entityChangesHistoryRepo,
 	}
 }
@@ -136,29 +138,36 @@ func (repo *MenuRepository) Insert(ctx context.Context, menu models.Menu) (strin
 		return "", drivers.ErrInvalid
 	}
 
+	repo.insertMenuVersion(ctx, oid.Hex(), entityChangesHistoryModels.EntityChangesHistory{
+		CallFunction: "Insert",
+	})
+
 	return oid.Hex(), nil
 }
 
-func (repo *MenuRepository) setMenuHistory(ctx context.Context, menuId string, history entityChangesHistoryModels.EntityChangesHistory, operationType, repositoryMethod string) {
-	oldMenu, err := repo.Get(ctx, selector.EmptyMenuSearch().SetMenuID(menuId))
+func (repo *MenuRepository) setMenuHistory(ctx context.Context, oldMenu models.Menu, history entityChangesHistoryModels.EntityChangesHistory, operationType, repositoryMethod string) {
+	history.OldBody = oldMenu
+	history.ModifiedAt = time.Now().UTC()
+	history.OperationType = operationType
+	history.RepositoryMethod = repositoryMethod
+	history.CollectionName = "menus"
+
+	_, err := repo.entityChangesHistoryRepo.InsertHistory(ctx, history)
 	if err != nil {
-		log.Err(err).Msgf("(MenuRepository) Get menu for entity changes history error")
-	} else {
-		history.OldBody = oldMenu
-		history.ModifiedAt = time.Now().UTC()
-		history.OperationType = operationType
-		history.RepositoryMethod = repositoryMethod
-		history.CollectionName = "menus"
-
-		_, err = repo.entityChangesHistoryRepo.InsertHistory(ctx, history)
-		if err != nil {
-			log.Err(err).Msgf("insert entity changes history error")
-		}
+		log.Err(err).Msgf("insert entity changes history error")
 	}
 }
 
 func (repo *MenuRepository) Update(ctx context.Context, menu models.Menu, history entityChangesHistoryModels.EntityChangesHistory) error {
-	repo.setMenuHistory(ctx, menu.ID, history, "update", "Update")
+	oldMenu, oldMenuErr := repo.Get(ctx, selector.EmptyMenuSearch().SetMenuID(menu.ID))
+	if oldMenuErr != nil {
+		log.Err(oldMenuErr).Msgf("(MenuRepository) Get menu for entity changes history error")
+	}
+
+	previousVersion, err := repo.versions.Last(ctx, menu.ID)
+	if err != nil {
+		log.Err(err).Msgf("(MenuRepository) get last version of menu %s error", menu.ID)
+	}
 
 	oid, err := primitive.ObjectIDFromHex(menu.ID)
 	if err != nil {
@@ -185,6 +194,14 @@ func (repo *MenuRepository) Update(ctx context.Context, menu models.Menu, histor
 	if res.MatchedCount == 0 {
 		return drivers.ErrNotFound
 	}
+
+	history.PreviousSnapshotID = previousVersion.ID
+	history.SnapshotID = repo.insertMenuVersion(ctx, menu.ID, history)
+
+	if oldMenuErr == nil {
+		repo.setMenuHistory(ctx, oldMenu, history, "update", "Update")
+	}
+
 	return nil
 }
 
@@ -216,6 +233,10 @@ func (repo *MenuRepository) AddRowToAttributeGroup(ctx context.Context, menuId s
 		return drivers.ErrNotFound
 	}
 
+	repo.insertMenuVersion(ctx, menuId, entityChangesHistoryModels.EntityChangesHistory{
+		CallFunction: "AddRowToAttributeGroup",
+	})
+
 	return nil
 }
 
@@ -241,6 +262,10 @@ func (repo *MenuRepository) Upsert(ctx context.Context, req models.Menu) (models
 		return models.Menu{}, errorSwitch(err)
 	}
 
+	repo.insertMenuVersion(ctx, menu.ID, entityChangesHistoryModels.EntityChangesHistory{
+		CallFunction: "Upsert",
+	})
+
 	return menu, nil
 }
 
@@ -375,6 +400,11 @@ func (repo *MenuRepository) UpdateMenuName(ctx context.Context, query models.Upd
 	if res.MatchedCount == 0 {
 		return drivers.ErrNotFound
 	}
+
+	repo.insertMenuVersion(ctx, query.MenuID, entityChangesHistoryModels.EntityChangesHistory{
+		CallFunction: "UpdateMenuName",
+	})
+
 	return nil
 }
 
@@ -402,6 +432,10 @@ func (repo *MenuRepository) CreateGlovoSuperCollection(ctx context.Context, menu
 		return drivers.ErrNotFound
 	}
 
+	repo.insertMenuVersion(ctx, menuId, entityChangesHistoryModels.EntityChangesHistory{
+		CallFunction: "CreateGlovoSuperCollection",
+	})
+
 	return nil
 }
 
@@ -0,0 +1,170 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	"github.com/kwaaka-team/orders-core/core/menu/models"
+	"github.com/kwaaka-team/orders-core/core/menu/models/selector"
+	entityChangesHistoryModels "github.com/kwaaka-team/orders-core/service/entity_changes_history/models"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	menuVersionCollection = "menu_versions"
+	// menuVersionsLimit - сколько последних версий хранится на меню, более старые удаляются после записи новой
+	menuVersionsLimit = 100
+	// menuVersionInsertAttempts - номер версии занимает уникальный индекс (menu_id, version), при гонке берем следующий
+	menuVersionInsertAttempts = 5
+)
+
+// MenuVersionRepository пишет снимки меню, используется всеми репозиториями, которые пишут в коллекцию меню
+type MenuVersionRepository struct {
+	menuColl    *mongo.Collection
+	versionColl *mongo.Collection
+}
+
+func NewMenuVersionRepository(db *mongo.Database) *MenuVersionRepository {
+	return &MenuVersionRepository{
+		menuColl:    db.Collection(menuCollection),
+		versionColl: db.Collection(menuVersionCollection),
+	}
+}
+
+// Save saves snapshot of menu as it is stored after write, error is only logged - write itself is already done
+func (repo *MenuVersionRepository) Save(ctx context.Context, menuID string, history entityChangesHistoryModels.EntityChangesHistory) string {
+	oid, err := primitive.ObjectIDFromHex(menuID)
+	if err != nil {
+		log.Err(err).Msgf("(MenuVersionRepository) invalid menu id %s", menuID)
+		return ""
+	}
+
+	var menu models.Menu
+	if err = repo.menuColl.FindOne(ctx, bson.D{{Key: "_id", Value: oid}}).Decode(&menu); err != nil {
+		log.Err(err).Msgf("(MenuVersionRepository) get menu %s for version error", menuID)
+		return ""
+	}
+
+	for attempt := 0; attempt < menuVersionInsertAttempts; attempt++ {
+		last, err := repo.Last(ctx, menuID)
+		if err != nil {
+			log.Err(err).Msgf("(MenuVersionRepository) get last version of menu %s error", menuID)
+			return ""
+		}
+
+		version := models.MenuVersion{
+			MenuID:       menuID,
+			Version:      last.Version + 1,
+			Menu:         &menu,
+			Author:       history.Author,
+			CallFunction: history.CallFunction,
+			TaskType:     history.TaskType,
+			CreatedAt:    time.Now().UTC(),
+		}
+
+		res, err := repo.versionColl.InsertOne(ctx, version)
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+		if err != nil {
+			log.Err(err).Msgf("(MenuVersionRepository) insert version of menu %s error", menuID)
+			return ""
+		}
+
+		repo.deleteOldVersions(ctx, menuID, version.Version)
+
+		versionID, ok := res.InsertedID.(primitive.ObjectID)
+		if !ok {
+			return ""
+		}
+		return versionID.Hex()
+	}
+
+	log.Error().Msgf("(MenuVersionRepository) version of menu %s is not saved after %d attempts", menuID, menuVersionInsertAttempts)
+	return ""
+}
+
+func (repo *MenuVersionRepository) Last(ctx context.Context, menuID string) (models.MenuVersion, error) {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.D{{Key: "menu", Value: 0}})
+
+	var version models.MenuVersion
+	if err := repo.versionColl.FindOne(ctx, bson.D{{Key: "menu_id", Value: menuID}}, opts).Decode(&version); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.MenuVersion{}, nil
+		}
+		return models.MenuVersion{}, err
+	}
+
+	return version, nil
+}
+
+// deleteOldVersions keeps last menuVersionsLimit versions of menu
+func (repo *MenuVersionRepository) deleteOldVersions(ctx context.Context, menuID string, lastVersion int) {
+	filter := bson.D{
+		{Key: "menu_id", Value: menuID},
+		{Key: "version", Value: bson.D{{Key: "$lte", Value: lastVersion - menuVersionsLimit}}},
+	}
+
+	if _, err := repo.versionColl.DeleteMany(ctx, filter); err != nil {
+		log.Err(err).Msgf("(MenuVersionRepository) delete old versions of menu %s error", menuID)
+	}
+}
+
+func (repo *MenuRepository) insertMenuVersion(ctx context.Context, menuID string, history entityChangesHistoryModels.EntityChangesHistory) string {
+	return repo.versions.Save(ctx, menuID, history)
+}
+
+func (repo *MenuRepository) GetMenuVersion(ctx context.Context, versionID string) (models.MenuVersion, error) {
+	oid, err := primitive.ObjectIDFromHex(versionID)
+	if err != nil {
+		return models.MenuVersion{}, drivers.ErrInvalid
+	}
+
+	var version models.MenuVersion
+	if err = repo.versions.versionColl.FindOne(ctx, bson.D{{Key: "_id", Value: oid}}).Decode(&version); err != nil {
+		return models.MenuVersion{}, errorSwitch(err)
+	}
+
+	return version, nil
+}
+
+// ListMenuVersions returns versions without menu body, newest first
+func (repo *MenuRepository) ListMenuVersions(ctx context.Context, menuID string, pagination selector.Pagination) ([]models.MenuVersion, int64, error) {
+	filter := bson.D{{Key: "menu_id", Value: menuID}}
+
+	total, err := repo.versions.versionColl.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, errorSwitch(err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.D{{Key: "menu", Value: 0}})
+	if pagination.HasPagination() {
+		opts.SetSkip(pagination.Skip()).SetLimit(pagination.Limit)
+	}
+
+	cur, err := repo.versions.versionColl.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, errorSwitch(err)
+	}
+	defer cur.Close(ctx)
+
+	versions := make([]models.MenuVersion, 0, cur.RemainingBatchLength())
+	for cur.Next(ctx) {
+		var version models.MenuVersion
+		if err = cur.Decode(&version); err != nil {
+			return nil, 0, errorSwitch(err)
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, total, nil
+}
@@ -63,6 +63,13 @@ type MenuManager interface {
 	ValidateAggAndPosMatching(ctx context.Context, menuID string, storeID string, limit int) (aggregatorProducts []models.Product, posProducts []models.Product, total int, err error)
 
 	RecoveryMenu(ctx context.Context, req models.Menu, entityChangesHistoryRequest entityChangesHistoryModels.EntityChangesHistoryRequest) error
+	ListMenuVersions(ctx context.Context, menuID string, pagination selector.Pagination) ([]models.MenuVersion, int64, error)
+	GetMenuVersion(ctx context.Context, versionID string) (models.MenuVersion, error)
+	DiffMenuVersions(ctx context.Context, fromVersionID, toVersionID string) (models.MenuDiff, error)
+	RollbackMenuVersion(ctx context.Context, versionID string, upload bool, sv3 *s3.S3, userRole, userName string, history entityChangesHistoryModels.EntityChangesHistoryRequest) (string, error)
+	DryRunUploadMenu(ctx context.Context, storeId, menuId, aggregatorName, userRole string) (models.DryRunReport, error)
+	SuggestProductMatches(ctx context.Context, storeID, menuID string, minConfidence float64) ([]models.ProductMatchSuggestion, error)
+	AcceptProductMatches(ctx context.Context, storeID, menuID string, matches []models.ProductMatch, history entityChangesHistoryModels.EntityChangesHistoryRequest) (models.ProductMatchAcceptResult, error)
 	MergeMenus(ctx context.Context, restaurantID string, restaurantIDs []string, history entityChangesHistoryModels.EntityChangesHistoryRequest) (string, error)
 
 	StopPositionsInVirtualStore(ctx context.Context, restaurantID, originalRestaurantID string) error
@@ -117,7 +124,7 @@ type mnm struct {
 	notifyCli       que.SQSInterface
 	storeCli        store.Client
 	bkOffersRepo    BkOffersManager
-	menuServiceRepo menuServicePkg.MongoRepository
+	menuServiceRepo menuServicePkg.Repository
 }
 
 func NewMenuManager(
@@ -134,7 +141,7 @@ func NewMenuManager(
 	stRepo drivers.StopListTransactionRepository,
 	storeCli store.Client,
 	bkOffersRepo drivers.BkOffersRepository,
-	menuServiceRepo menuServicePkg.MongoRepository,
+	menuServiceRepo menuServicePkg.Repository,
 	restGroupMenuRepo drivers.RestaurantGroupMenuRepository) MenuManager {
 
 	return &mnm{
@@ -255,19 +262,25 @@ func (m *mnm) StopListSchedule(ctx context.Context, setting models.StopListSched
 			continue
 		}
 
-		transData, err = m.updateProductsInAggregator(ctx,
-			storeModels.AggregatorName(menu.Delivery),
-			rst,
-			products,
-		)
+		// в агрегатор отправляем итоговую доступность, продукт на стопе кассы расписание не включает
+		updated, err := m.updateAggregatorMenuProducts(ctx, menu.ID, products, setting.Available, history)
 		if err != nil {
 			return err
 		}
 
-		_, err = m.updateAggregatorMenuProducts(ctx, menu.ID, products, setting.Available, history)
+		if len(updated) == 0 {
+			continue
+		}
+
+		trx, err := m.updateProductsInAggregator(ctx,
+			storeModels.AggregatorName(menu.Delivery),
+			rst,
+			updated,
+		)
 		if err != nil {
 			return err
 		}
+		transData = append(transData, trx...)
 	}
 
 	result := models.StopListTransaction{
@@ -1493,7 +1506,7 @@ type ChangedProductsInfo struct {
 	NewPrice float64 `json:"new_price"`
 }
 
-func (m *mnm) changeAggregatorProductsPrices(products []models.Product, posMenuProductsPrices map[string]float64, changedProductsInfo map[string]ChangedProductsInfo, productIdsMap map[string]bool) []models.Product {
+func (m *mnm) changeAggregatorProductsPrices(products []models.Product, posMenuProductsPrices map[string]float64, changedProductsInfo map[string]ChangedProductsInfo, productIdsMap map[string]bool, markup models.Markup) []models.Product {
 	for i := range products {
 		if products[i].DiscountPrice.Value != 0 {
 			continue
@@ -1517,6 +1530,8 @@ func (m *mnm) changeAggregatorProductsPrices(products []models.Product, posMenuP
 			continue
 		}
 
+		price, _ = markup.Price(products[i], price)
+
 		for j := range products[i].Price {
 			if products[i].Price[j].Value != price {
 				var productName string
@@ -1588,7 +1603,9 @@ func (m *mnm) updatePricesInAggregatorMenus(ctx context.Context, store storeMode
 			}
 		}
 
-		updatedProductsWithPrice := m.changeAggregatorProductsPrices(aggregatorMenu.Products, posMenuProductsPrices, changedProductsInfo, productIdsMap)
+		markup := models.NewMarkup(store, menuDs.ID, menuDs.Delivery, aggregatorMenu.Sections)
+
+		updatedProductsWithPrice := m.changeAggregatorProductsPrices(aggregatorMenu.Products, posMenuProductsPrices, changedProductsInfo, productIdsMap, markup)
 
 		aggregatorMenu.Products = updatedProductsWithPrice
 
@@ -2367,6 +2384,8 @@ func (m *mnm) AutoUpdateMenuPrices(ctx context.Context, storeId string) error {
 				return err
 			}
 
+			markup := models.NewMarkup(store, store.Menus[i].ID, store.Menus[i].Delivery, aggrMenu.Sections)
+
 			for j := range aggrMenu.Products {
 				id := aggrMenu.Products[j].ExtID
 				if aggrMenu.Products[j].PosID != "" {
@@ -2374,7 +2393,7 @@ func (m *mnm) AutoUpdateMenuPrices(ctx context.Context, storeId string) error {
 				}
 				if price, ok := posMenuPrices[id]; ok {
 					if len(aggrMenu.Products[j].Price) > 0 {
-						aggrMenu.Products[j].Price[0].Value = price
+						aggrMenu.Products[j].Price[0].Value, _ = markup.Price(aggrMenu.Products[j], price)
 					}
 				}
 			}
@@ -0,0 +1,65 @@
+package managers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	"github.com/kwaaka-team/orders-core/core/menu/models"
+	"github.com/kwaaka-team/orders-core/core/menu/models/selector"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/pkg/errors"
+)
+
+// DryRunUploadMenu runs the same checks as UploadMenu and aggregator marshaller with its constraints, aggregator is not called
+func (m *mnm) DryRunUploadMenu(ctx context.Context, storeId, menuId, aggregatorName, userRole string) (models.DryRunReport, error) {
+	store, err := m.storeRepo.Get(ctx, selector.EmptyStoreSearch().SetID(storeId))
+	if err != nil {
+		if errors.Is(err, drivers.ErrNotFound) {
+			return models.DryRunReport{}, fmt.Errorf("dry run upload menu error: %w menu_id %s in store %s", drivers.ErrNotFound, menuId, storeId)
+		}
+		return models.DryRunReport{}, err
+	}
+
+	menu, err := m.menuRepo.Get(ctx, selector.EmptyMenuSearch().SetMenuID(menuId))
+	if err != nil {
+		if errors.Is(err, drivers.ErrNotFound) {
+			return models.DryRunReport{}, fmt.Errorf("dry run upload menu error: %w menu_id %s ", drivers.ErrNotFound, menuId)
+		}
+		return models.DryRunReport{}, err
+	}
+
+	if aggregatorName == "" && menu.Delivery != "" {
+		aggregatorName = menu.Delivery
+	}
+
+	bkOffers, err := m.bkOffersRepo.List(ctx, selector.EmptyBkOffersSearch())
+	if err != nil && !errors.Is(err, drivers.ErrNotFound) {
+		return models.DryRunReport{}, err
+	}
+
+	aggrManager, err := m.getAggregatorManager(ctx, store, storeModels.AggregatorName(aggregatorName))
+	if err != nil {
+		return models.DryRunReport{}, err
+	}
+
+	report, err := aggrManager.DryRunMenu(ctx, menu, store, bkOffers)
+	if err != nil {
+		return models.DryRunReport{}, errors.Wrapf(err, "dry run %s menu error", aggregatorName)
+	}
+	report.StoreID = store.ID
+	report.MenuID = menuId
+
+	if err = m.menuValidator.ValidateMenu(ctx, menu); err != nil {
+		report.AddError(models.DryRunGenericValidation, models.DryRunMenu, menuId, "", err.Error())
+	}
+	if len(store.GetAggregatorStoreIDs(aggregatorName)) == 0 {
+		report.AddError(models.DryRunMissingReference, models.DryRunMenu, menuId, "store_id", fmt.Sprintf("store has no %s store ids", aggregatorName))
+	}
+	if menu.HasWoltPromo && aggregatorName == models.WOLT.String() && userRole != "Admins" {
+		report.AddError(models.DryRunGenericValidation, models.DryRunMenu, menuId, "", "menu has wolt promo, only admins can publish it")
+	}
+	report.Finish()
+
+	return report, nil
+}
@@ -0,0 +1,253 @@
+package managers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	"github.com/kwaaka-team/orders-core/core/menu/models"
+	"github.com/kwaaka-team/orders-core/core/menu/models/selector"
+	entityChangesHistoryModels "github.com/kwaaka-team/orders-core/service/entity_changes_history/models"
+	"github.com/pkg/errors"
+)
+
+var ErrMenuVersionMismatch = errors.New("versions belong to different menus")
+
+func (m *mnm) ListMenuVersions(ctx context.Context, menuID string, pagination selector.Pagination) ([]models.MenuVersion, int64, error) {
+	return m.menuRepo.ListMenuVersions(ctx, menuID, pagination)
+}
+
+func (m *mnm) GetMenuVersion(ctx context.Context, versionID string) (models.MenuVersion, error) {
+	return m.menuRepo.GetMenuVersion(ctx, versionID)
+}
+
+// DiffMenuVersions compares two versions of one menu, if toVersionID is empty - version is compared with current menu
+func (m *mnm) DiffMenuVersions(ctx context.Context, fromVersionID, toVersionID string) (models.MenuDiff, error) {
+	from, err := m.menuRepo.GetMenuVersion(ctx, fromVersionID)
+	if err != nil {
+		return models.MenuDiff{}, err
+	}
+
+	var to models.Menu
+	if toVersionID == "" {
+		if to, err = m.menuRepo.Get(ctx, selector.EmptyMenuSearch().SetMenuID(from.MenuID)); err != nil {
+			return models.MenuDiff{}, err
+		}
+	} else {
+		toVersion, err := m.menuRepo.GetMenuVersion(ctx, toVersionID)
+		if err != nil {
+			return models.MenuDiff{}, err
+		}
+		if toVersion.MenuID != from.MenuID {
+			return models.MenuDiff{}, ErrMenuVersionMismatch
+		}
+		to = versionMenu(toVersion)
+	}
+
+	diff := diffMenus(versionMenu(from), to)
+	diff.MenuID = from.MenuID
+	diff.FromVersionID = fromVersionID
+	diff.ToVersionID = toVersionID
+
+	return diff, nil
+}
+
+// RollbackMenuVersion restores menu from version (stop list state stays current) and uploads it to aggregator if upload is true.
+// Rollback is a usual write, so it creates a new version and the history is kept
+func (m *mnm) RollbackMenuVersion(ctx context.Context, versionID string, upload bool, sv3 *s3.S3, userRole, userName string, history entityChangesHistoryModels.EntityChangesHistoryRequest) (string, error) {
+	version, err := m.menuRepo.GetMenuVersion(ctx, versionID)
+	if err != nil {
+		return "", err
+	}
+
+	current, err := m.menuRepo.Get(ctx, selector.EmptyMenuSearch().SetMenuID(version.MenuID))
+	if err != nil {
+		return "", err
+	}
+
+	restored := versionMenu(version)
+	restored.ID = version.MenuID
+	keepAvailability(&restored, current)
+
+	if err = m.menuRepo.Update(ctx, restored, entityChangesHistoryModels.EntityChangesHistory{
+		CallFunction: "RollbackMenuVersion",
+		Author:       history.Author,
+		TaskType:     history.TaskType,
+	}); err != nil {
+		return "", err
+	}
+
+	if !upload {
+		return "", nil
+	}
+
+	store, err := m.storeRepo.Get(ctx, selector.EmptyStoreSearch().SetAggregatorMenuID(version.MenuID))
+	if err != nil {
+		if errors.Is(err, drivers.ErrNotFound) {
+			return "", errors.Wrapf(err, "menu %s is not aggregator menu of any store", version.MenuID)
+		}
+		return "", err
+	}
+
+	return m.UploadMenu(ctx, store.ID, version.MenuID, restored.Delivery, sv3, userRole, userName)
+}
+
+func versionMenu(version models.MenuVersion) models.Menu {
+	if version.Menu == nil {
+		return models.Menu{ID: version.MenuID}
+	}
+	return *version.Menu
+}
+
+// keepAvailability - стоп-лист не часть версии меню, доступность продуктов и атрибутов остается текущей
+func keepAvailability(restored *models.Menu, current models.Menu) {
+	products := make(map[string]models.Product, len(current.Products))
+	for _, product := range current.Products {
+		products[product.ExtID] = product
+	}
+	for i := range restored.Products {
+		product, ok := products[restored.Products[i].ExtID]
+		if !ok {
+			continue
+		}
+		restored.Products[i].IsAvailable = product.IsAvailable
+		restored.Products[i].IsDisabled = product.IsDisabled
+		restored.Products[i].DisabledByValidation = product.DisabledByValidation
+		restored.Products[i].DisableReasons = product.DisableReasons
+	}
+
+	attributes := make(map[string]bool, len(current.Attributes))
+	for _, attribute := range current.Attributes {
+		attributes[attribute.ExtID] = attribute.IsAvailable
+	}
+	for i := range restored.Attributes {
+		if isAvailable, ok := attributes[restored.Attributes[i].ExtID]; ok {
+			restored.Attributes[i].IsAvailable = isAvailable
+		}
+	}
+}
+
+func diffMenus(from, to models.Menu) models.MenuDiff {
+	return models.MenuDiff{
+		Products:        diffProducts(from.Products, to.Products),
+		AttributeGroups: diffAttributeGroups(from.AttributesGroups, to.AttributesGroups),
+	}
+}
+
+func diffProducts(from, to models.Products) []models.EntityDiff {
+	old := make(map[string]models.Product, len(from))
+	for _, product := range from {
+		old[product.ExtID] = product
+	}
+
+	diffs := make([]models.EntityDiff, 0)
+	seen := make(map[string]struct{}, len(to))
+
+	for _, product := range to {
+		seen[product.ExtID] = struct{}{}
+
+		prev, ok := old[product.ExtID]
+		if !ok {
+			diffs = append(diffs, models.EntityDiff{ExtID: product.ExtID, Name: productName(product), Change: models.DiffAdded})
+			continue
+		}
+
+		fields := make([]models.FieldChange, 0)
+		fields = appendFieldChange(fields, "name", productName(prev), productName(product))
+		fields = appendFieldChange(fields, "price", productPrice(prev), productPrice(product))
+		fields = appendFieldChange(fields, "images", prev.ImageURLs, product.ImageURLs)
+		fields = appendFieldChange(fields, "attributes_groups", prev.AttributesGroups, product.AttributesGroups)
+		fields = appendFieldChange(fields, "section", prev.Section, product.Section)
+		fields = appendFieldChange(fields, "is_deleted", prev.IsDeleted, product.IsDeleted)
+
+		if len(fields) != 0 {
+			diffs = append(diffs, models.EntityDiff{ExtID: product.ExtID, Name: productName(product), Change: models.DiffChanged, Fields: fields})
+		}
+	}
+
+	for _, product := range from {
+		if _, ok := seen[product.ExtID]; !ok {
+			diffs = append(diffs, models.EntityDiff{ExtID: product.ExtID, Name: productName(product), Change: models.DiffRemoved})
+		}
+	}
+
+	sortDiffs(diffs)
+
+	return diffs
+}
+
+func diffAttributeGroups(from, to models.AttributeGroups) []models.EntityDiff {
+	old := make(map[string]models.AttributeGroup, len(from))
+	for _, group := range from {
+		old[group.ExtID] = group
+	}
+
+	diffs := make([]models.EntityDiff, 0)
+	seen := make(map[string]struct{}, len(to))
+
+	for _, group := range to {
+		seen[group.ExtID] = struct{}{}
+
+		prev, ok := old[group.ExtID]
+		if !ok {
+			diffs = append(diffs, models.EntityDiff{ExtID: group.ExtID, Name: group.Name, Change: models.DiffAdded})
+			continue
+		}
+
+		fields := make([]models.FieldChange, 0)
+		fields = appendFieldChange(fields, "name", prev.Name, group.Name)
+		fields = appendFieldChange(fields, "min", prev.Min, group.Min)
+		fields = appendFieldChange(fields, "max", prev.Max, group.Max)
+		fields = appendFieldChange(fields, "attributes", prev.Attributes, group.Attributes)
+		fields = appendFieldChange(fields, "attribute_min_max", prev.AttributeMinMax, group.AttributeMinMax)
+
+		if len(fields) != 0 {
+			diffs = append(diffs, models.EntityDiff{ExtID: group.ExtID, Name: group.Name, Change: models.DiffChanged, Fields: fields})
+		}
+	}
+
+	for _, group := range from {
+		if _, ok := seen[group.ExtID]; !ok {
+			diffs = append(diffs, models.EntityDiff{ExtID: group.ExtID, Name: group.Name, Change: models.DiffRemoved})
+		}
+	}
+
+	sortDiffs(diffs)
+
+	return diffs
+}
+
+func appendFieldChange(fields []models.FieldChange, field string, old, new interface{}) []models.FieldChange {
+	if isEmptyValue(old) && isEmptyValue(new) || reflect.DeepEqual(old, new) {
+		return fields
+	}
+	return append(fields, models.FieldChange{Field: field, Old: old, New: new})
+}
+
+// isEmptyValue - nil и пустой слайс из базы считаются одинаковыми
+func isEmptyValue(v interface{}) bool {
+	value := reflect.ValueOf(v)
+	return value.Kind() == reflect.Slice && value.Len() == 0
+}
+
+func sortDiffs(diffs []models.EntityDiff) {
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].ExtID < diffs[j].ExtID
+	})
+}
+
+func productName(product models.Product) string {
+	if len(product.Name) == 0 {
+		return ""
+	}
+	return product.Name[0].Value
+}
+
+func productPrice(product models.Product) float64 {
+	if len(product.Price) == 0 {
+		return 0
+	}
+	return product.Price[0].Value
+}
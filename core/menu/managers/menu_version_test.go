@@ -0,0 +1,60 @@
+package managers
+
+import (
+	"testing"
+
+	"github.com/kwaaka-team/orders-core/core/menu/models"
+)
+
+func TestDiffMenus(t *testing.T) {
+	from := models.Menu{
+		Products: models.Products{
+			{ExtID: "1", Name: []models.LanguageDescription{{Value: "Burger"}}, Price: []models.Price{{Value: 1000}}},
+			{ExtID: "2", Name: []models.LanguageDescription{{Value: "Cola"}}, Price: []models.Price{{Value: 500}}},
+			{ExtID: "3", Name: []models.LanguageDescription{{Value: "Fries"}}, Price: []models.Price{{Value: 700}}, ImageURLs: []string{}},
+		},
+		AttributesGroups: models.AttributeGroups{
+			{ExtID: "g1", Name: "Sauces", Min: 0, Max: 2},
+		},
+	}
+	to := models.Menu{
+		Products: models.Products{
+			{ExtID: "1", Name: []models.LanguageDescription{{Value: "Burger"}}, Price: []models.Price{{Value: 1200}}},
+			{ExtID: "3", Name: []models.LanguageDescription{{Value: "Fries"}}, Price: []models.Price{{Value: 700}}},
+			{ExtID: "4", Name: []models.LanguageDescription{{Value: "Tea"}}, Price: []models.Price{{Value: 300}}},
+		},
+		AttributesGroups: models.AttributeGroups{
+			{ExtID: "g1", Name: "Sauces", Min: 1, Max: 2},
+		},
+	}
+
+	diff := diffMenus(from, to)
+
+	expected := []struct {
+		extID  string
+		change models.DiffChange
+		fields int
+	}{
+		{"1", models.DiffChanged, 1},
+		{"2", models.DiffRemoved, 0},
+		{"4", models.DiffAdded, 0},
+	}
+
+	if len(diff.Products) != len(expected) {
+		t.Fatalf("expected %d product diffs, got %d: %+v", len(expected), len(diff.Products), diff.Products)
+	}
+	for i, test := range expected {
+		got := diff.Products[i]
+		if got.ExtID != test.extID || got.Change != test.change || len(got.Fields) != test.fields {
+			t.Errorf("product diff %d: expected %s %s with %d fields, got %+v", i, test.extID, test.change, test.fields, got)
+		}
+	}
+
+	if len(diff.AttributeGroups) != 1 || diff.AttributeGroups[0].Fields[0].Field != "min" {
+		t.Errorf("expected min change of attribute group, got %+v", diff.AttributeGroups)
+	}
+
+	if !diffMenus(to, to).IsEmpty() {
+		t.Errorf("expected empty diff for same menu")
+	}
+}
@@ -206,7 +206,7 @@ func (m *mnm) updateProductAvailable(ctx context.Context, menuId string, product
 
 	for i := range menu.Products {
 		if menu.Products[i].ExtID == product.ExtID {
-			menu.Products[i].IsAvailable = product.IsAvailable
+			menu.Products[i].SetDisableReason(models.DisableReasonAdmin, !product.IsAvailable)
 			IsProductFound = true
 		}
 	}
@@ -0,0 +1,412 @@
+package managers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	"github.com/kwaaka-team/orders-core/core/menu/models"
+	"github.com/kwaaka-team/orders-core/core/menu/models/selector"
+	entityChangesHistoryModels "github.com/kwaaka-team/orders-core/service/entity_changes_history/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultMatchConfidence = 0.5
+	matchCandidatesLimit   = 3
+	// кандидаты с совсем непохожим названием не предлагаются, даже если цена и секция совпали
+	minMatchNameScore = 0.3
+
+	matchNameWeight           = 0.6
+	matchPriceWeight          = 0.2
+	matchSectionWeight        = 0.1
+	matchAttributeGroupWeight = 0.1
+)
+
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z", 'и': "i",
+	'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+	'у': "u", 'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sch", 'ъ': "", 'ы': "i", 'ь': "",
+	'э': "e", 'ю': "yu", 'я': "ya",
+	// казахские буквы
+	'ә': "a", 'ғ': "g", 'қ': "k", 'ң': "n", 'ө': "o", 'ұ': "u", 'ү': "u", 'һ': "h", 'і': "i",
+}
+
+// после транслитерации латинские варианты написания приводятся к одному, "khachapuri" и "хачапури" -> "hachapuri"
+var latinSpellingReplacer = strings.NewReplacer("kh", "h", "y", "i", "w", "v")
+
+// normalizeProductName приводит название к нижнему регистру и латинице без знаков препинания и лишних пробелов
+func normalizeProductName(s string) string {
+	var builder strings.Builder
+	for _, char := range strings.ToLower(s) {
+		if latin, ok := cyrillicToLatin[char]; ok {
+			builder.WriteString(latin)
+			continue
+		}
+		builder.WriteRune(char)
+	}
+
+	return latinSpellingReplacer.Replace(reduceSpaces(removeNonAlphaNumericSymbols(builder.String())))
+}
+
+// nameSimilarity - максимум из совпадения слов (без учета порядка) и совпадения биграмм (опечатки, слитное написание)
+func nameSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	return math.Max(tokenSimilarity(a, b), bigramSimilarity(a, b))
+}
+
+func tokenSimilarity(a, b string) float64 {
+	tokens := make(map[string]struct{})
+	for _, token := range strings.Fields(a) {
+		tokens[token] = struct{}{}
+	}
+
+	common, union := 0, len(tokens)
+	seen := make(map[string]struct{})
+	for _, token := range strings.Fields(b) {
+		if _, ok := seen[token]; ok {
+			continue
+		}
+		seen[token] = struct{}{}
+
+		if _, ok := tokens[token]; ok {
+			common++
+			continue
+		}
+		union++
+	}
+
+	if union == 0 {
+		return 0
+	}
+	return float64(common) / float64(union)
+}
+
+func bigramSimilarity(a, b string) float64 {
+	bigramsA, bigramsB := bigrams(a), bigrams(b)
+	if len(bigramsA) == 0 || len(bigramsB) == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int, len(bigramsA))
+	for _, bigram := range bigramsA {
+		counts[bigram]++
+	}
+
+	common := 0
+	for _, bigram := range bigramsB {
+		if counts[bigram] > 0 {
+			counts[bigram]--
+			common++
+		}
+	}
+
+	return 2 * float64(common) / float64(len(bigramsA)+len(bigramsB))
+}
+
+func bigrams(s string) []string {
+	runes := []rune(strings.ReplaceAll(s, " ", ""))
+	if len(runes) < 2 {
+		return nil
+	}
+
+	result := make([]string, 0, len(runes)-1)
+	for i := 0; i < len(runes)-1; i++ {
+		result = append(result, string(runes[i:i+2]))
+	}
+	return result
+}
+
+func priceSimilarity(a, b float64) float64 {
+	if a <= 0 || b <= 0 {
+		return 0
+	}
+	return 1 - math.Abs(a-b)/math.Max(a, b)
+}
+
+// attributeGroupSimilarity сравнивает нормализованные названия групп атрибутов, у продуктов без групп совпадение полное
+func attributeGroupSimilarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	common := 0
+	for name := range a {
+		if _, ok := b[name]; ok {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+// matchProduct - нормализованные данные продукта для сравнения
+type matchProduct struct {
+	product         models.Product
+	names           []string
+	price           float64
+	sectionName     string
+	section         string
+	attributeGroups map[string]struct{}
+}
+
+func newMatchProducts(menu models.Menu) []matchProduct {
+	sections := make(map[string]string, len(menu.Sections)+len(menu.Groups))
+	for _, group := range menu.Groups {
+		sections[group.ID] = group.Name
+	}
+	for _, section := range menu.Sections {
+		sections[section.ExtID] = section.Name
+	}
+
+	groups := make(map[string]string, len(menu.AttributesGroups))
+	for _, group := range menu.AttributesGroups {
+		groups[group.ExtID] = normalizeProductName(group.Name)
+	}
+
+	result := make([]matchProduct, 0, len(menu.Products))
+	for _, product := range menu.Products {
+		if product.IsDeleted {
+			continue
+		}
+
+		item := matchProduct{
+			product:         product,
+			attributeGroups: make(map[string]struct{}, len(product.AttributesGroups)),
+		}
+
+		for _, name := range product.Name {
+			if normalized := normalizeProductName(name.Value); normalized != "" {
+				item.names = append(item.names, normalized)
+			}
+		}
+		if normalized := normalizeProductName(product.ExtName); normalized != "" {
+			item.names = append(item.names, normalized)
+		}
+
+		if len(product.Price) != 0 {
+			item.price = product.Price[0].Value
+		}
+
+		section, ok := sections[product.Section]
+		if !ok {
+			section = sections[product.ParentGroupID]
+		}
+		item.sectionName = section
+		item.section = normalizeProductName(section)
+
+		for _, groupID := range product.AttributesGroups {
+			if name := groups[groupID]; name != "" {
+				item.attributeGroups[name] = struct{}{}
+			}
+		}
+
+		result = append(result, item)
+	}
+
+	return result
+}
+
+func (p matchProduct) name() string {
+	if len(p.product.Name) != 0 {
+		return p.product.Name[0].Value
+	}
+	return p.product.ExtName
+}
+
+func scoreProductMatch(aggregatorProduct, posProduct matchProduct) (float64, models.ProductMatchScores) {
+	var scores models.ProductMatchScores
+
+	for _, aggregatorName := range aggregatorProduct.names {
+		for _, posName := range posProduct.names {
+			scores.Name = math.Max(scores.Name, nameSimilarity(aggregatorName, posName))
+		}
+	}
+	scores.Price = priceSimilarity(aggregatorProduct.price, posProduct.price)
+	scores.Section = nameSimilarity(aggregatorProduct.section, posProduct.section)
+	scores.AttributeGroup = attributeGroupSimilarity(aggregatorProduct.attributeGroups, posProduct.attributeGroups)
+
+	confidence := scores.Name*matchNameWeight +
+		scores.Price*matchPriceWeight +
+		scores.Section*matchSectionWeight +
+		scores.AttributeGroup*matchAttributeGroupWeight
+
+	return math.Round(confidence*100) / 100, scores
+}
+
+// suggestProductMatches предлагает до matchCandidatesLimit продуктов pos меню для каждого несматченного продукта агрегатора
+func suggestProductMatches(aggregatorMenu, posMenu models.Menu, minConfidence float64) []models.ProductMatchSuggestion {
+	posProducts := newMatchProducts(posMenu)
+
+	positions := make(map[string]struct{}, len(posProducts))
+	for _, posProduct := range posProducts {
+		positions[posProduct.product.ExtID] = struct{}{}
+	}
+
+	suggestions := make([]models.ProductMatchSuggestion, 0)
+	for _, aggregatorProduct := range newMatchProducts(aggregatorMenu) {
+		id := aggregatorProduct.product.ExtID
+		if aggregatorProduct.product.PosID != "" {
+			id = aggregatorProduct.product.PosID
+		}
+		if _, ok := positions[id]; ok {
+			continue
+		}
+
+		suggestion := models.ProductMatchSuggestion{
+			ProductID:  aggregatorProduct.product.ExtID,
+			Name:       aggregatorProduct.name(),
+			Price:      aggregatorProduct.price,
+			Section:    aggregatorProduct.sectionName,
+			Candidates: make([]models.ProductMatchCandidate, 0),
+		}
+
+		for _, posProduct := range posProducts {
+			confidence, scores := scoreProductMatch(aggregatorProduct, posProduct)
+			if scores.Name < minMatchNameScore || confidence < minConfidence {
+				continue
+			}
+
+			suggestion.Candidates = append(suggestion.Candidates, models.ProductMatchCandidate{
+				PosID:      posProduct.product.ExtID,
+				ProductID:  posProduct.product.ProductID,
+				Name:       posProduct.name(),
+				Price:      posProduct.price,
+				Section:    posProduct.sectionName,
+				Confidence: confidence,
+				Scores:     scores,
+			})
+		}
+
+		sort.SliceStable(suggestion.Candidates, func(i, j int) bool {
+			return suggestion.Candidates[i].Confidence > suggestion.Candidates[j].Confidence
+		})
+		if len(suggestion.Candidates) > matchCandidatesLimit {
+			suggestion.Candidates = suggestion.Candidates[:matchCandidatesLimit]
+		}
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	// сначала продукты с самым уверенным кандидатом, их проще принять пачкой
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return topConfidence(suggestions[i]) > topConfidence(suggestions[j])
+	})
+
+	return suggestions
+}
+
+func topConfidence(suggestion models.ProductMatchSuggestion) float64 {
+	if len(suggestion.Candidates) == 0 {
+		return 0
+	}
+	return suggestion.Candidates[0].Confidence
+}
+
+func (m *mnm) getMatchMenus(ctx context.Context, storeID, menuID string) (models.Menu, models.Menu, error) {
+	store, err := m.storeRepo.Get(ctx, selector.EmptyStoreSearch().SetID(storeID))
+	if err != nil {
+		if errors.Is(err, drivers.ErrNotFound) {
+			return models.Menu{}, models.Menu{}, fmt.Errorf("product matching error: %w store %s", drivers.ErrNotFound, storeID)
+		}
+		return models.Menu{}, models.Menu{}, err
+	}
+
+	if store.MenuID == menuID {
+		return models.Menu{}, models.Menu{}, errors.Errorf("menu %s is pos menu of store %s", menuID, storeID)
+	}
+
+	aggregatorMenu, err := m.menuRepo.Get(ctx, selector.EmptyMenuSearch().SetMenuID(menuID))
+	if err != nil {
+		return models.Menu{}, models.Menu{}, err
+	}
+
+	posMenu, err := m.menuRepo.Get(ctx, selector.EmptyMenuSearch().SetMenuID(store.MenuID))
+	if err != nil {
+		return models.Menu{}, models.Menu{}, err
+	}
+
+	return aggregatorMenu, posMenu, nil
+}
+
+// SuggestProductMatches предлагает pos продукты для продуктов агрегатора, которые не нашлись в pos меню по PosID/ExtID
+func (m *mnm) SuggestProductMatches(ctx context.Context, storeID, menuID string, minConfidence float64) ([]models.ProductMatchSuggestion, error) {
+	aggregatorMenu, posMenu, err := m.getMatchMenus(ctx, storeID, menuID)
+	if err != nil {
+		return nil, err
+	}
+
+	if minConfidence <= 0 {
+		minConfidence = defaultMatchConfidence
+	}
+
+	return suggestProductMatches(aggregatorMenu, posMenu, minConfidence), nil
+}
+
+// AcceptProductMatches записывает PosID и ProductID pos продукта в продукты меню агрегатора
+func (m *mnm) AcceptProductMatches(ctx context.Context, storeID, menuID string, matches []models.ProductMatch, history entityChangesHistoryModels.EntityChangesHistoryRequest) (models.ProductMatchAcceptResult, error) {
+	aggregatorMenu, posMenu, err := m.getMatchMenus(ctx, storeID, menuID)
+	if err != nil {
+		return models.ProductMatchAcceptResult{}, err
+	}
+
+	posProducts := make(map[string]models.Product, len(posMenu.Products))
+	for _, product := range posMenu.Products {
+		if !product.IsDeleted {
+			posProducts[product.ExtID] = product
+		}
+	}
+
+	products := make(map[string]int, len(aggregatorMenu.Products))
+	for i, product := range aggregatorMenu.Products {
+		products[product.ExtID] = i
+	}
+
+	result := models.ProductMatchAcceptResult{
+		Rejected: make([]models.ProductMatchRejection, 0),
+	}
+	for _, match := range matches {
+		index, ok := products[match.ProductID]
+		if !ok {
+			result.Rejected = append(result.Rejected, models.ProductMatchRejection{
+				ProductID: match.ProductID, PosID: match.PosID, Reason: "product is not found in aggregator menu",
+			})
+			continue
+		}
+
+		posProduct, ok := posProducts[match.PosID]
+		if !ok {
+			result.Rejected = append(result.Rejected, models.ProductMatchRejection{
+				ProductID: match.ProductID, PosID: match.PosID, Reason: "product is not found in pos menu",
+			})
+			continue
+		}
+
+		aggregatorMenu.Products[index].PosID = posProduct.ExtID
+		aggregatorMenu.Products[index].ProductID = posProduct.ProductID
+		result.Accepted++
+	}
+
+	if result.Accepted == 0 {
+		return result, nil
+	}
+
+	if err = m.menuRepo.Update(ctx, aggregatorMenu, entityChangesHistoryModels.EntityChangesHistory{
+		CallFunction: "AcceptProductMatches",
+		Author:       history.Author,
+		TaskType:     history.TaskType,
+	}); err != nil {
+		return models.ProductMatchAcceptResult{}, err
+	}
+
+	return result, nil
+}
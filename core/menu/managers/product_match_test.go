@@ -0,0 +1,57 @@
+package managers
+
+import (
+	"testing"
+
+	"github.com/kwaaka-team/orders-core/core/menu/models"
+)
+
+func TestNormalizeProductName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Хачапури по-аджарски", "hachapuri poadzharski"},
+		{"Khachapuri  (big)", "hachapuri big"},
+		{"Чай Қазақша", "chai kazaksha"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		result := normalizeProductName(test.input)
+		if result != test.expected {
+			t.Errorf("For input %q, expected %q, but got %q", test.input, test.expected, result)
+		}
+	}
+}
+
+func TestSuggestProductMatches(t *testing.T) {
+	posMenu := models.Menu{
+		Sections: models.Sections{{ExtID: "s1", Name: "Выпечка"}},
+		Products: models.Products{
+			{ExtID: "pos-1", ProductID: "p1", Section: "s1", Name: []models.LanguageDescription{{Value: "Хачапури по-аджарски"}}, Price: []models.Price{{Value: 2500}}},
+			{ExtID: "pos-2", ProductID: "p2", Section: "s1", Name: []models.LanguageDescription{{Value: "Лобиани"}}, Price: []models.Price{{Value: 2000}}},
+			{ExtID: "pos-3", ProductID: "p3", Name: []models.LanguageDescription{{Value: "Khachapuri"}}, IsDeleted: true},
+		},
+	}
+	aggregatorMenu := models.Menu{
+		Sections: models.Sections{{ExtID: "a1", Name: "Vypechka"}},
+		Products: models.Products{
+			{ExtID: "agg-1", Section: "a1", Name: []models.LanguageDescription{{Value: "Khachapuri po adzharski"}}, Price: []models.Price{{Value: 2600}}},
+			{ExtID: "agg-2", PosID: "pos-2", Name: []models.LanguageDescription{{Value: "Lobiani"}}},
+			{ExtID: "agg-3", Name: []models.LanguageDescription{{Value: "Coca-Cola"}}, Price: []models.Price{{Value: 500}}},
+		},
+	}
+
+	suggestions := suggestProductMatches(aggregatorMenu, posMenu, defaultMatchConfidence)
+	if len(suggestions) != 2 {
+		t.Fatalf("expected suggestions for 2 unmatched products, got %+v", suggestions)
+	}
+
+	if suggestions[0].ProductID != "agg-1" || len(suggestions[0].Candidates) != 1 || suggestions[0].Candidates[0].PosID != "pos-1" {
+		t.Errorf("expected agg-1 to be matched with pos-1, got %+v", suggestions[0])
+	}
+	if suggestions[1].ProductID != "agg-3" || len(suggestions[1].Candidates) != 0 {
+		t.Errorf("expected agg-3 without candidates, got %+v", suggestions[1])
+	}
+}
@@ -120,32 +120,15 @@ func (m *mnm) updateStopListAggregator(ctx context.Context, menuID string, stopL
 			}
 		}
 
-		// if product disabled by admin or by cron with section, then skip it
-		if menu.Products[i].IsDisabled {
-			log.Info().Msgf("product %s is disabled", id)
-			continue
-		}
-
 		if menu.Products[i].IsDeleted {
-			menu.Products[i].IsAvailable = false
+			menu.Products[i].SetDisableReason(models.DisableReasonPosStopList, true)
 			products = append(products, menu.Products[i])
 			continue
 		}
 
-		// if product available is equal true add him to update slice
-		if _, ok := existStopLists[id]; ok {
-			// if balance not zero we do not update this product in aggregator
-			menu.Products[i].IsAvailable = false
-			products = append(products, menu.Products[i])
-			continue
-		}
-
-		// if product available is equal false, but not exist in POS stop list, add him to update slice
-		if !menu.Products[i].IsAvailable {
-			menu.Products[i].IsAvailable = true
-			products = append(products, menu.Products[i])
-			continue
-		}
+		// касса меняет только свою причину, продукт выключенный админом или по расписанию останется выключенным
+		_, isOnStop := existStopLists[id]
+		menu.Products[i].SetDisableReason(models.DisableReasonPosStopList, isOnStop)
 
 		products = append(products, menu.Products[i])
 	}
@@ -590,7 +573,7 @@ func (m *mnm) updateAggregatorMenuAttributesStopList(ctx context.Context, menuID
 	return aggregatorStopList, nil
 }
 
-// todo: dry
+// updateAggregatorMenuProducts sets schedule reason for items, returned products have resulting availability
 func (m *mnm) updateAggregatorMenuProducts(ctx context.Context, menuID string, items []models.Product, isAvailable bool, history entityChangesHistoryModels.EntityChangesHistoryRequest) (models.Products, error) {
 	menu, err := m.menuRepo.Get(ctx, selector.EmptyMenuSearch().SetMenuID(menuID))
 	if err != nil {
@@ -613,23 +596,16 @@ func (m *mnm) updateAggregatorMenuProducts(ctx context.Context, menuID string, i
 		if menu.Products[i].ProductID != "" && menu.Products[i].ProductID != menu.Products[i].ExtID {
 			id = menu.Products[i].ProductID
 		}
-		// if product disabled by admin or by cron with section, then skip it
-		if isAvailable {
-			menu.Products[i].IsDisabled = false
-		} else {
-			menu.Products[i].IsDisabled = true
-		}
 
 		if menu.Products[i].IsDeleted {
-			menu.Products[i].IsAvailable = false
 			continue
 		}
 
 		if item, ok := itemsMap[id]; ok {
-			menu.Products[i].IsAvailable = item.IsAvailable
+			menu.Products[i].SetDisableReason(models.DisableReasonSchedule, !isAvailable)
 			menu.Products[i].Price = item.Price
 			aggregatorStopList = append(aggregatorStopList, menu.Products[i])
-			if item.IsAvailable {
+			if menu.Products[i].IsAvailable {
 				delete(stopListMap, id)
 				continue
 			}
@@ -0,0 +1,83 @@
+package models
+
+// DisableReason - причина, по которой продукт недоступен. Продукт доступен, только если активных причин нет,
+// поэтому крон или касса снимают только свою причину и не включают продукт, выключенный кем-то другим
+type DisableReason string
+
+const (
+	DisableReasonPosStopList DisableReason = "pos_stoplist"
+	DisableReasonAdmin       DisableReason = "admin"
+	DisableReasonSchedule    DisableReason = "schedule"
+	DisableReasonSection     DisableReason = "section"
+	DisableReasonValidation  DisableReason = "validation"
+	DisableReasonBalance     DisableReason = "balance"
+	// DisableReasonAvailabilityWindow - вне окна повторяющегося расписания доступности
+	DisableReasonAvailabilityWindow DisableReason = "availability_window"
+)
+
+func (r DisableReason) String() string {
+	return string(r)
+}
+
+// disablesByAdmin - причины, которые раньше хранились в is_disabled
+func (r DisableReason) disablesByAdmin() bool {
+	return r == DisableReasonAdmin || r == DisableReasonSchedule || r == DisableReasonSection || r == DisableReasonAvailabilityWindow
+}
+
+// ActiveDisableReasons returns reasons of product, for products saved before reasons they are restored from legacy flags
+func (p Product) ActiveDisableReasons() []DisableReason {
+	if p.DisableReasons != nil {
+		return p.DisableReasons
+	}
+
+	reasons := make([]DisableReason, 0, 2)
+	if p.IsDisabled {
+		reasons = append(reasons, DisableReasonAdmin)
+	}
+	if p.DisabledByValidation {
+		reasons = append(reasons, DisableReasonValidation)
+	}
+	if !p.IsAvailable && len(reasons) == 0 {
+		reasons = append(reasons, DisableReasonPosStopList)
+	}
+
+	return reasons
+}
+
+func (p Product) HasDisableReason(reason DisableReason) bool {
+	for _, r := range p.ActiveDisableReasons() {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDisableReason adds or removes one reason and recalculates available, is_disabled and disabled_by_validation
+func (p *Product) SetDisableReason(reason DisableReason, active bool) {
+	current := p.ActiveDisableReasons()
+
+	reasons := make([]DisableReason, 0, len(current)+1)
+	for _, r := range current {
+		if r != reason {
+			reasons = append(reasons, r)
+		}
+	}
+	if active {
+		reasons = append(reasons, reason)
+	}
+
+	p.DisableReasons = reasons
+	p.IsAvailable = len(reasons) == 0
+	p.IsDisabled = false
+	p.DisabledByValidation = false
+
+	for _, r := range reasons {
+		if r.disablesByAdmin() {
+			p.IsDisabled = true
+		}
+		if r == DisableReasonValidation {
+			p.DisabledByValidation = true
+		}
+	}
+}
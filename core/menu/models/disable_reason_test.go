@@ -0,0 +1,89 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProduct_SetDisableReason(t *testing.T) {
+	type step struct {
+		reason DisableReason
+		active bool
+	}
+
+	tests := []struct {
+		name          string
+		product       Product
+		steps         []step
+		wantAvailable bool
+		wantDisabled  bool
+		wantReasons   []DisableReason
+	}{
+		{
+			name:    "cron does not enable product stopped by pos",
+			product: Product{IsAvailable: true},
+			steps: []step{
+				{DisableReasonSchedule, true},
+				{DisableReasonPosStopList, true},
+				{DisableReasonSchedule, false},
+			},
+			wantAvailable: false,
+			wantDisabled:  false,
+			wantReasons:   []DisableReason{DisableReasonPosStopList},
+		},
+		{
+			name:    "pos does not enable product disabled by admin",
+			product: Product{IsAvailable: true},
+			steps: []step{
+				{DisableReasonAdmin, true},
+				{DisableReasonPosStopList, true},
+				{DisableReasonPosStopList, false},
+			},
+			wantAvailable: false,
+			wantDisabled:  true,
+			wantReasons:   []DisableReason{DisableReasonAdmin},
+		},
+		{
+			name:    "product is available when all reasons are removed",
+			product: Product{IsAvailable: true},
+			steps: []step{
+				{DisableReasonSection, true},
+				{DisableReasonBalance, true},
+				{DisableReasonBalance, false},
+				{DisableReasonSection, false},
+			},
+			wantAvailable: true,
+			wantDisabled:  false,
+			wantReasons:   []DisableReason{},
+		},
+		{
+			name:    "legacy flags are kept as reasons",
+			product: Product{IsAvailable: false, DisabledByValidation: true},
+			steps: []step{
+				{DisableReasonPosStopList, false},
+			},
+			wantAvailable: false,
+			wantDisabled:  false,
+			wantReasons:   []DisableReason{DisableReasonValidation},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := tt.product
+			for _, s := range tt.steps {
+				product.SetDisableReason(s.reason, s.active)
+			}
+
+			if product.IsAvailable != tt.wantAvailable {
+				t.Errorf("IsAvailable = %v, want %v", product.IsAvailable, tt.wantAvailable)
+			}
+			if product.IsDisabled != tt.wantDisabled {
+				t.Errorf("IsDisabled = %v, want %v", product.IsDisabled, tt.wantDisabled)
+			}
+			if !reflect.DeepEqual(product.DisableReasons, tt.wantReasons) {
+				t.Errorf("DisableReasons = %v, want %v", product.DisableReasons, tt.wantReasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+type DryRunSeverity string
+
+const (
+	DryRunError   DryRunSeverity = "error"
+	DryRunWarning DryRunSeverity = "warning"
+)
+
+type DryRunCode string
+
+const (
+	DryRunGenericValidation DryRunCode = "generic_validation"
+	DryRunNameEmpty         DryRunCode = "name_empty"
+	DryRunNameTooLong       DryRunCode = "name_too_long"
+	DryRunDescriptionEmpty  DryRunCode = "description_empty"
+	DryRunDescriptionLong   DryRunCode = "description_too_long"
+	DryRunImageRequired     DryRunCode = "image_required"
+	DryRunPriceNotPositive  DryRunCode = "price_not_positive"
+	DryRunMinMaxInvalid     DryRunCode = "min_max_invalid"
+	DryRunNestingTooDeep    DryRunCode = "nesting_too_deep"
+	DryRunMissingReference  DryRunCode = "missing_reference"
+	DryRunSkipped           DryRunCode = "skipped_by_marshaller"
+)
+
+type DryRunEntity string
+
+const (
+	DryRunProduct        DryRunEntity = "product"
+	DryRunAttribute      DryRunEntity = "attribute"
+	DryRunAttributeGroup DryRunEntity = "attribute_group"
+	DryRunSection        DryRunEntity = "section"
+	DryRunMenu           DryRunEntity = "menu"
+)
+
+type DryRunIssue struct {
+	Severity   DryRunSeverity `json:"severity"`
+	Code       DryRunCode     `json:"code"`
+	EntityType DryRunEntity   `json:"entity_type"`
+	EntityID   string         `json:"entity_id"`
+	Field      string         `json:"field,omitempty"`
+	Message    string         `json:"message"`
+}
+
+// DryRunReport - результат проверки меню перед выгрузкой, агрегатор не вызывается
+type DryRunReport struct {
+	StoreID         string        `json:"store_id"`
+	MenuID          string        `json:"menu_id"`
+	Aggregator      string        `json:"aggregator"`
+	Valid           bool          `json:"valid"`
+	ProductsCount   int           `json:"products_count"`
+	AttributesCount int           `json:"attributes_count"`
+	Issues          []DryRunIssue `json:"issues"`
+	CheckedAt       time.Time     `json:"checked_at"`
+}
+
+// DryRunLimits - известные ограничения агрегатора, 0 - без ограничения
+type DryRunLimits struct {
+	MaxNameLength        int
+	MaxDescriptionLength int
+	ImageRequired        bool
+	MaxNestingDepth      int
+}
+
+func NewDryRunReport(aggregator string) DryRunReport {
+	return DryRunReport{
+		Aggregator: aggregator,
+		Issues:     make([]DryRunIssue, 0),
+		CheckedAt:  time.Now().UTC(),
+	}
+}
+
+func (r *DryRunReport) AddError(code DryRunCode, entityType DryRunEntity, entityID, field, message string) {
+	r.Issues = append(r.Issues, DryRunIssue{
+		Severity:   DryRunError,
+		Code:       code,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Field:      field,
+		Message:    message,
+	})
+}
+
+func (r *DryRunReport) AddWarning(code DryRunCode, entityType DryRunEntity, entityID, field, message string) {
+	r.Issues = append(r.Issues, DryRunIssue{
+		Severity:   DryRunWarning,
+		Code:       code,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Field:      field,
+		Message:    message,
+	})
+}
+
+// Finish выставляет Valid и сортирует замечания: ошибки первыми. Отчет валиден если нет ошибок, предупреждения допускаются
+func (r *DryRunReport) Finish() {
+	sort.SliceStable(r.Issues, func(i, j int) bool {
+		if r.Issues[i].Severity != r.Issues[j].Severity {
+			return r.Issues[i].Severity == DryRunError
+		}
+		if r.Issues[i].EntityType != r.Issues[j].EntityType {
+			return r.Issues[i].EntityType < r.Issues[j].EntityType
+		}
+		return r.Issues[i].EntityID < r.Issues[j].EntityID
+	})
+
+	r.Valid = len(r.Issues) == 0 || r.Issues[0].Severity != DryRunError
+}
+
+func (r *DryRunReport) CheckName(entityType DryRunEntity, entityID, name string, limits DryRunLimits) {
+	if name == "" {
+		r.AddError(DryRunNameEmpty, entityType, entityID, "name", "name is empty")
+		return
+	}
+	if limits.MaxNameLength != 0 && utf8.RuneCountInString(name) > limits.MaxNameLength {
+		r.AddError(DryRunNameTooLong, entityType, entityID, "name",
+			fmt.Sprintf("name length %d is more than %d", utf8.RuneCountInString(name), limits.MaxNameLength))
+	}
+}
+
+func (r *DryRunReport) CheckDescription(entityType DryRunEntity, entityID, description string, limits DryRunLimits) {
+	if limits.MaxDescriptionLength != 0 && utf8.RuneCountInString(description) > limits.MaxDescriptionLength {
+		r.AddError(DryRunDescriptionLong, entityType, entityID, "description",
+			fmt.Sprintf("description length %d is more than %d", utf8.RuneCountInString(description), limits.MaxDescriptionLength))
+	}
+}
+
+func (r *DryRunReport) CheckImage(entityType DryRunEntity, entityID, imageURL string, limits DryRunLimits) {
+	if imageURL != "" {
+		return
+	}
+	if limits.ImageRequired {
+		r.AddError(DryRunImageRequired, entityType, entityID, "image", "image is required")
+		return
+	}
+	r.AddWarning(DryRunImageRequired, entityType, entityID, "image", "image is empty")
+}
+
+func (r *DryRunReport) CheckPrice(entityType DryRunEntity, entityID string, price float64) {
+	if price <= 0 {
+		r.AddError(DryRunPriceNotPositive, entityType, entityID, "price", fmt.Sprintf("price %v must be more than 0", price))
+	}
+}
+
+// CheckMinMax проверяет min/max группы атрибутов относительно количества выгружаемых атрибутов
+func (r *DryRunReport) CheckMinMax(entityID string, min, max, attributesCount int) {
+	switch {
+	case min < 0 || max < 0:
+		r.AddError(DryRunMinMaxInvalid, DryRunAttributeGroup, entityID, "min", fmt.Sprintf("min %d and max %d must not be negative", min, max))
+	case max != 0 && min > max:
+		r.AddError(DryRunMinMaxInvalid, DryRunAttributeGroup, entityID, "min", fmt.Sprintf("min %d is more than max %d", min, max))
+	case min > attributesCount:
+		r.AddError(DryRunMinMaxInvalid, DryRunAttributeGroup, entityID, "min", fmt.Sprintf("min %d is more than %d attributes in group", min, attributesCount))
+	case attributesCount == 0:
+		r.AddWarning(DryRunMinMaxInvalid, DryRunAttributeGroup, entityID, "attributes", "attribute group has no attributes")
+	}
+}
+
+func (r *DryRunReport) CheckDepth(entityType DryRunEntity, entityID string, depth int, limits DryRunLimits) {
+	if limits.MaxNestingDepth != 0 && depth > limits.MaxNestingDepth {
+		r.AddError(DryRunNestingTooDeep, entityType, entityID, "", fmt.Sprintf("nesting depth %d is more than %d", depth, limits.MaxNestingDepth))
+	}
+}
+
+// CheckSkipped предупреждает о продуктах, которые маршаллер агрегатора молча не выгрузит
+func (r *DryRunReport) CheckSkipped(products Products, sections Sections, marshalled map[string]struct{}) {
+	sectionIDs := make(map[string]struct{}, len(sections))
+	for _, section := range sections {
+		sectionIDs[section.ExtID] = struct{}{}
+	}
+
+	for _, product := range products {
+		if product.IsDeleted {
+			continue
+		}
+		if _, ok := marshalled[product.ExtID]; ok {
+			continue
+		}
+
+		reason := "product is skipped by marshaller"
+		switch _, hasSection := sectionIDs[product.Section]; {
+		case product.ExtID == "":
+			reason = "product has empty ext_id"
+		case len(product.Price) == 0:
+			reason = "product has no price"
+		case len(product.Name) == 0:
+			reason = "product has no name"
+		case !hasSection:
+			reason = fmt.Sprintf("section %s is not found", product.Section)
+		}
+		r.AddWarning(DryRunSkipped, DryRunProduct, product.ExtID, "", reason)
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import "testing"
+
+func TestDryRunReport(t *testing.T) {
+	report := NewDryRunReport("wolt")
+
+	limits := DryRunLimits{MaxNameLength: 5}
+	report.CheckName(DryRunProduct, "1", "Burger", limits)
+	report.CheckImage(DryRunProduct, "1", "", limits)
+	report.CheckPrice(DryRunProduct, "1", 0)
+	report.CheckMinMax("g1", 2, 1, 3)
+	report.CheckMinMax("g2", 2, 3, 1)
+	report.CheckMinMax("g3", 0, 1, 2)
+	report.CheckSkipped(Products{{ExtID: "2", Section: "s1", Price: []Price{{Value: 1}}, Name: []LanguageDescription{{Value: "Tea"}}}}, nil, map[string]struct{}{})
+	report.Finish()
+
+	expected := []struct {
+		code     DryRunCode
+		severity DryRunSeverity
+	}{
+		{DryRunMinMaxInvalid, DryRunError},
+		{DryRunMinMaxInvalid, DryRunError},
+		{DryRunNameTooLong, DryRunError},
+		{DryRunPriceNotPositive, DryRunError},
+		{DryRunImageRequired, DryRunWarning},
+		{DryRunSkipped, DryRunWarning},
+	}
+
+	if report.Valid {
+		t.Errorf("expected report with errors to be invalid")
+	}
+	if len(report.Issues) != len(expected) {
+		t.Fatalf("expected %d issues, got %d: %+v", len(expected), len(report.Issues), report.Issues)
+	}
+	for i, test := range expected {
+		if report.Issues[i].Code != test.code || report.Issues[i].Severity != test.severity {
+			t.Errorf("issue %d: expected %s %s, got %+v", i, test.severity, test.code, report.Issues[i])
+		}
+	}
+
+	warnings := NewDryRunReport("glovo")
+	warnings.CheckImage(DryRunProduct, "1", "", DryRunLimits{})
+	warnings.Finish()
+	if !warnings.Valid {
+		t.Errorf("expected report with only warnings to be valid")
+	}
+}
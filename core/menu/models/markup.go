@@ -0,0 +1,63 @@
+package models
+
+import (
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+)
+
+// Markup считает цену продукта меню агрегатора из цены pos по правилам наценки ресторана
+type Markup struct {
+	store      storeModels.Store
+	menuID     string
+	aggregator string
+	sections   map[string]string
+}
+
+func NewMarkup(store storeModels.Store, menuID, aggregator string, sections Sections) Markup {
+	names := make(map[string]string, len(sections))
+	for _, section := range sections {
+		names[section.ExtID] = section.Name
+	}
+
+	return Markup{
+		store:      store,
+		menuID:     menuID,
+		aggregator: aggregator,
+		sections:   names,
+	}
+}
+
+// Price возвращает цену с наценкой и название примененного правила, пустое для markup_percent меню.
+// Теги продукта выводятся только из флагов is_alcohol и is_tobacco, которые приходят из pos
+func (m Markup) Price(product Product, posPrice float64) (float64, string) {
+	tags := make([]string, 0, 2)
+	if product.IsAlcohol {
+		tags = append(tags, storeModels.MarkupTagAlcohol)
+	}
+	if product.IsTobacco {
+		tags = append(tags, storeModels.MarkupTagTobacco)
+	}
+
+	return m.store.MarkupPrice(m.menuID, storeModels.MarkupProduct{
+		Aggregator:  m.aggregator,
+		SectionID:   product.Section,
+		SectionName: m.sections[product.Section],
+		Tags:        tags,
+		Price:       posPrice,
+	})
+}
+
+type MarkupPreviewItem struct {
+	ProductID    string  `json:"product_id"`
+	Name         string  `json:"name"`
+	SectionID    string  `json:"section_id"`
+	PosPrice     float64 `json:"pos_price"`
+	CurrentPrice float64 `json:"current_price"`
+	NewPrice     float64 `json:"new_price"`
+	Rule         string  `json:"rule,omitempty"`
+}
+
+type MarkupPreview struct {
+	MenuID     string              `json:"menu_id"`
+	Aggregator string              `json:"aggregator"`
+	Products   []MarkupPreviewItem `json:"products"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+)
+
+func TestMarkupPrice(t *testing.T) {
+	store := storeModels.Store{
+		Menus: storeModels.StoreDSMenus{{ID: "glovo-menu", MarkupPercent: 10}},
+		MarkupRules: storeModels.MarkupRules{
+			{Name: "wolt", Aggregators: []string{"wolt"}, Percent: 12, Rounding: storeModels.MarkupRoundingUp, RoundTo: 10, ExcludeSections: []string{"Напитки"}},
+			{Name: "wolt cheap", Priority: 1, Aggregators: []string{"wolt"}, MaxPrice: 500, Percent: 5, MinAmount: 100},
+			{Name: "alcohol", Tags: []string{"alcohol"}, Percent: 20},
+		},
+	}
+	sections := Sections{{ExtID: "food", Name: "Еда"}, {ExtID: "drinks", Name: "Напитки"}}
+
+	tests := []struct {
+		name     string
+		menuID   string
+		delivery string
+		product  Product
+		price    float64
+		expected float64
+		rule     string
+	}{
+		{"percent with rounding up", "wolt-menu", "wolt", Product{Section: "food"}, 2000, 2240, "wolt"},
+		{"rounding up to step", "wolt-menu", "wolt", Product{Section: "food"}, 2010, 2260, "wolt"},
+		{"min amount in price band by priority", "wolt-menu", "wolt", Product{Section: "food"}, 400, 500, "wolt cheap"},
+		{"excluded section keeps pos price", "wolt-menu", "wolt", Product{Section: "drinks"}, 600, 600, ""},
+		{"tag from product flag", "wolt-menu", "wolt", Product{Section: "drinks", IsAlcohol: true}, 1000, 1200, "alcohol"},
+		{"menu markup percent without rule", "glovo-menu", "glovo", Product{Section: "food"}, 1000, 1100, ""},
+		{"zero price is not marked up", "wolt-menu", "wolt", Product{Section: "food"}, 0, 0, ""},
+	}
+
+	for _, test := range tests {
+		price, rule := NewMarkup(store, test.menuID, test.delivery, sections).Price(test.product, test.price)
+		if price != test.expected || rule != test.rule {
+			t.Errorf("%s: expected %v by %q, got %v by %q", test.name, test.expected, test.rule, price, rule)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// MenuVersion - неизменяемый снимок меню после каждой записи через MenuRepository.Insert/Update
+type MenuVersion struct {
+	ID           string    `bson:"_id,omitempty" json:"id"`
+	MenuID       string    `bson:"menu_id" json:"menu_id"`
+	Version      int       `bson:"version" json:"version"`
+	Menu         *Menu     `bson:"menu,omitempty" json:"menu,omitempty"`
+	Author       string    `bson:"author" json:"author"`
+	CallFunction string    `bson:"call_function" json:"call_function"`
+	TaskType     string    `bson:"task_type" json:"task_type"`
+	CreatedAt    time.Time `bson:"created_at" json:"created_at"`
+}
+
+type DiffChange string
+
+const (
+	DiffAdded   DiffChange = "added"
+	DiffRemoved DiffChange = "removed"
+	DiffChanged DiffChange = "changed"
+)
+
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type EntityDiff struct {
+	ExtID  string        `json:"ext_id"`
+	Name   string        `json:"name"`
+	Change DiffChange    `json:"change"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+type MenuDiff struct {
+	MenuID          string       `json:"menu_id"`
+	FromVersionID   string       `json:"from_version_id"`
+	ToVersionID     string       `json:"to_version_id"`
+	Products        []EntityDiff `json:"products"`
+	AttributeGroups []EntityDiff `json:"attribute_groups"`
+}
+
+func (d MenuDiff) IsEmpty() bool {
+	return len(d.Products) == 0 && len(d.AttributeGroups) == 0
+}
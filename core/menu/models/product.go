@@ -73,6 +73,7 @@ type Product struct {
 	IsCatchWeight           bool                    `bson:"is_catch_weight" json:"is_catch_weight"`
 	VendorCode              string                  `bson:"vendor_code" json:"vendor_code"`
 	DisabledByValidation    bool                    `bson:"disabled_by_validation" json:"disabled_by_validation"`
+	DisableReasons          []DisableReason         `bson:"disable_reasons,omitempty" json:"disable_reasons,omitempty"`
 	Halal                   bool                    `bson:"halal" json:"halal"`
 }
 type DiscountPrice struct {
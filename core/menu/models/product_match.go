@@ -0,0 +1,45 @@
+package models
+
+// ProductMatchScores - составляющие уверенности, каждая от 0 до 1
+type ProductMatchScores struct {
+	Name           float64 `json:"name"`
+	Price          float64 `json:"price"`
+	Section        float64 `json:"section"`
+	AttributeGroup float64 `json:"attribute_group"`
+}
+
+type ProductMatchCandidate struct {
+	PosID      string             `json:"pos_id"`
+	ProductID  string             `json:"product_id"`
+	Name       string             `json:"name"`
+	Price      float64            `json:"price"`
+	Section    string             `json:"section"`
+	Confidence float64            `json:"confidence"`
+	Scores     ProductMatchScores `json:"scores"`
+}
+
+// ProductMatchSuggestion - кандидаты из pos меню для несматченного продукта агрегатора, лучший первый
+type ProductMatchSuggestion struct {
+	ProductID  string                  `json:"product_id"`
+	Name       string                  `json:"name"`
+	Price      float64                 `json:"price"`
+	Section    string                  `json:"section"`
+	Candidates []ProductMatchCandidate `json:"candidates"`
+}
+
+// ProductMatch - ProductID продукта агрегатора (ext_id) и PosID продукта pos меню (ext_id)
+type ProductMatch struct {
+	ProductID string `json:"product_id"`
+	PosID     string `json:"pos_id"`
+}
+
+type ProductMatchRejection struct {
+	ProductID string `json:"product_id"`
+	PosID     string `json:"pos_id"`
+	Reason    string `json:"reason"`
+}
+
+type ProductMatchAcceptResult struct {
+	Accepted int                     `json:"accepted"`
+	Rejected []ProductMatchRejection `json:"rejected"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "math"
+
+// OrderCommission - разбор заказа агрегатора по договору ресторана: что ушло на промо, сколько оставил агрегатор и что осталось ресторану
+type OrderCommission struct {
+	Gross      float64 `bson:"gross" json:"gross"`
+	Promo      float64 `bson:"promo" json:"promo"`
+	PromoCost  float64 `bson:"promo_cost" json:"promo_cost"`
+	Commission float64 `bson:"commission" json:"commission"`
+	NetRevenue float64 `bson:"net_revenue" json:"net_revenue"`
+}
+
+// CalculateAggregatorCommission - percentage берется от суммы заказа за вычетом скидки партнера, fixedFee - плата за каждый заказ,
+// aggregatorPromoShare - процент скидки партнера, который компенсирует агрегатор, остальное - стоимость промо для ресторана
+func CalculateAggregatorCommission(order Order, percentage, fixedFee, aggregatorPromoShare float64) OrderCommission {
+	gross := order.EstimatedTotalPrice.Value
+	promo := math.Min(order.PartnerDiscountsProducts.Value, gross)
+
+	res := OrderCommission{
+		Gross:      gross,
+		Promo:      promo,
+		PromoCost:  roundCommission(promo * (100 - aggregatorPromoShare) / 100),
+		Commission: roundCommission((gross-promo)*percentage/100 + fixedFee),
+	}
+	res.NetRevenue = roundCommission(res.Gross - res.PromoCost - res.Commission)
+
+	return res
+}
+
+func roundCommission(value float64) float64 {
+	return math.Round(value*100) / 100
+}
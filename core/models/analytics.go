@@ -68,3 +68,50 @@ type AnalyticsTotalAmountQuantity struct {
 	Previous  OrderTotalAmountQuantity
 	Cancelled OrderTotalAmountQuantity
 }
+
+type AggregatorCommissionAnalyticsRequest struct {
+	RestaurantGroupID string    `json:"restaurant_group_id" binding:"required"`
+	StartDate         time.Time `json:"start_date" binding:"required"`
+	EndDate           time.Time `json:"end_date" binding:"required"`
+}
+
+// AggregatorCommissionAnalytics - итоги агрегатора за период, проценты считаются от валовой суммы заказов
+type AggregatorCommissionAnalytics struct {
+	DeliveryService   string  `json:"delivery_service"`
+	OrdersCount       int     `json:"orders_count"`
+	Gross             float64 `json:"gross"`
+	Promo             float64 `json:"promo"`
+	PromoCost         float64 `json:"promo_cost"`
+	Commission        float64 `json:"commission"`
+	NetRevenue        float64 `json:"net_revenue"`
+	CommissionPercent float64 `json:"commission_percent"`
+	NetRevenuePercent float64 `json:"net_revenue_percent"`
+}
+
+type RestaurantAggregatorCommissionAnalytics struct {
+	RestaurantID   string                          `json:"restaurant_id"`
+	RestaurantName string                          `json:"restaurant_name"`
+	Aggregators    []AggregatorCommissionAnalytics `json:"aggregators"`
+}
+
+type AggregatorCommissionAnalyticsResponse struct {
+	RestaurantGroupID string                                    `json:"restaurant_group_id"`
+	StartDate         time.Time                                 `json:"start_date"`
+	EndDate           time.Time                                 `json:"end_date"`
+	Aggregators       []AggregatorCommissionAnalytics           `json:"aggregators"`
+	Restaurants       []RestaurantAggregatorCommissionAnalytics `json:"restaurants"`
+}
+
+func (a *AggregatorCommissionAnalytics) Add(commission OrderCommission) {
+	a.OrdersCount++
+	a.Gross = roundCommission(a.Gross + commission.Gross)
+	a.Promo = roundCommission(a.Promo + commission.Promo)
+	a.PromoCost = roundCommission(a.PromoCost + commission.PromoCost)
+	a.Commission = roundCommission(a.Commission + commission.Commission)
+	a.NetRevenue = roundCommission(a.NetRevenue + commission.NetRevenue)
+
+	if a.Gross != 0 {
+		a.CommissionPercent = roundCommission(a.Commission / a.Gross * 100)
+		a.NetRevenuePercent = roundCommission(a.NetRevenue / a.Gross * 100)
+	}
+}
@@ -0,0 +1,245 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	CustomerIdentityQrMenuUser    = "qr_menu_user"
+	CustomerIdentityAuthUser      = "auth_user"
+	CustomerIdentityPaymentSystem = "payment_system"
+	CustomerIdentityIikoOffline   = "iiko_offline"
+
+	favouriteItemsLimit = 10
+	addressesLimit      = 5
+)
+
+// CustomerIdentity - идентификатор клиента во внешней системе, привязанный к телефону
+type CustomerIdentity struct {
+	Source     string `bson:"source" json:"source" binding:"required"`
+	ExternalID string `bson:"external_id" json:"external_id" binding:"required"`
+}
+
+// CustomerProfile - клиент прямых каналов и агрегаторов, ID - нормализованный телефон
+type CustomerProfile struct {
+	ID         string             `bson:"_id" json:"id"`
+	Names      []string           `bson:"names" json:"names"`
+	Emails     []string           `bson:"emails" json:"emails"`
+	Identities []CustomerIdentity `bson:"identities" json:"identities"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+type CustomerFavouriteItem struct {
+	ProductID   string `json:"product_id"`
+	Name        string `json:"name"`
+	Quantity    int    `json:"quantity"`
+	OrdersCount int    `json:"orders_count"`
+}
+
+type CustomerAddress struct {
+	DeliveryAddress
+	OrdersCount int       `json:"orders_count"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+}
+
+type CustomerCard struct {
+	ID            string `json:"id"`
+	PanMasked     string `json:"pan_masked"`
+	ExpiryDate    string `json:"expiry_date"`
+	PaymentSystem string `json:"payment_system"`
+}
+
+// CustomerStats - заказы клиента без тестовых, отмененные не входят в lifetime_value и average_bill
+type CustomerStats struct {
+	OrdersCount    int            `json:"orders_count"`
+	CancelledCount int            `json:"cancelled_count"`
+	LifetimeValue  float64        `json:"lifetime_value"`
+	AverageBill    float64        `json:"average_bill"`
+	FirstOrderAt   time.Time      `json:"first_order_at"`
+	LastOrderAt    time.Time      `json:"last_order_at"`
+	Channels       map[string]int `json:"channels"`
+	// Stores - количество заказов по ресторанам группы
+	Stores map[string]int `json:"stores"`
+}
+
+// CustomerGroupProfile - профиль клиента в рамках группы ресторанов
+type CustomerGroupProfile struct {
+	CustomerProfile
+	Phone             string                  `json:"phone"`
+	Name              string                  `json:"name"`
+	RestaurantGroupID string                  `json:"restaurant_group_id"`
+	Stats             CustomerStats           `json:"stats"`
+	FavouriteItems    []CustomerFavouriteItem `json:"favourite_items"`
+	Addresses         []CustomerAddress       `json:"addresses"`
+	Cards             []CustomerCard          `json:"cards"`
+}
+
+type CustomerOrderHistoryResponse struct {
+	Orders []Order `json:"orders"`
+	Total  int     `json:"total"`
+}
+
+// NormalizePhone приводит телефон к виду +<цифры>, казахстанский номер с 8 в начале - к +7.
+// Пустая строка, если в телефоне меньше 10 цифр
+func NormalizePhone(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+
+	res := digits.String()
+	switch {
+	case len(res) < 10:
+		return ""
+	case len(res) == 10:
+		res = "7" + res
+	case len(res) == 11 && res[0] == '8':
+		res = "7" + res[1:]
+	}
+
+	return "+" + res
+}
+
+// PhoneVariants - варианты записи нормализованного телефона в заказах агрегаторов и прямых каналов
+func PhoneVariants(phone string) []string {
+	normalized := NormalizePhone(phone)
+	if normalized == "" {
+		return nil
+	}
+
+	variants := []string{normalized, normalized[1:]}
+	if strings.HasPrefix(normalized, "+7") {
+		variants = append(variants, "8"+normalized[2:])
+	}
+
+	return variants
+}
+
+// BuildCustomerGroupProfile считает статистику, любимые позиции и адреса клиента по заказам группы ресторанов
+func BuildCustomerGroupProfile(profile CustomerProfile, orders []Order, cards []CustomerCard) CustomerGroupProfile {
+	res := CustomerGroupProfile{
+		CustomerProfile: profile,
+		Phone:           profile.ID,
+		Stats:           CustomerStats{Channels: make(map[string]int), Stores: make(map[string]int)},
+		FavouriteItems:  make([]CustomerFavouriteItem, 0),
+		Addresses:       make([]CustomerAddress, 0),
+		Cards:           cards,
+	}
+	if res.Cards == nil {
+		res.Cards = make([]CustomerCard, 0)
+	}
+
+	sorted := make([]Order, 0, len(orders))
+	for _, order := range orders {
+		if !order.IsTestOrder {
+			sorted = append(sorted, order)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].OrderTime.Value.Time.After(sorted[j].OrderTime.Value.Time)
+	})
+
+	items := make(map[string]*CustomerFavouriteItem)
+	addresses := make(map[string]*CustomerAddress)
+	var completed int
+
+	for _, order := range sorted {
+		orderTime := order.OrderTime.Value.Time
+
+		res.Stats.OrdersCount++
+		res.Stats.Channels[order.DeliveryService]++
+		res.Stats.Stores[order.RestaurantID]++
+		if res.Stats.LastOrderAt.IsZero() {
+			res.Stats.LastOrderAt = orderTime
+		}
+		res.Stats.FirstOrderAt = orderTime
+
+		if res.Name == "" && order.Customer.Name != "" {
+			res.Name = order.Customer.Name
+		}
+
+		if isCancelledStatus(order.Status) {
+			res.Stats.CancelledCount++
+			continue
+		}
+
+		completed++
+		res.Stats.LifetimeValue += order.EstimatedTotalPrice.Value
+
+		for _, product := range order.Products {
+			key := product.ID
+			if key == "" {
+				key = product.Name
+			}
+			item, ok := items[key]
+			if !ok {
+				item = &CustomerFavouriteItem{ProductID: product.ID, Name: product.Name}
+				items[key] = item
+			}
+			item.Quantity += product.Quantity
+			item.OrdersCount++
+		}
+
+		if order.IsPickedUpByCustomer || order.DeliveryAddress.Label == "" {
+			continue
+		}
+		address, ok := addresses[order.DeliveryAddress.Label]
+		if !ok {
+			// заказы отсортированы от новых к старым, первым попадается последний вариант адреса
+			address = &CustomerAddress{DeliveryAddress: order.DeliveryAddress, LastUsedAt: orderTime}
+			addresses[order.DeliveryAddress.Label] = address
+		}
+		address.OrdersCount++
+	}
+
+	res.Stats.LifetimeValue = math.Round(res.Stats.LifetimeValue*100) / 100
+	if completed != 0 {
+		res.Stats.AverageBill = math.Round(res.Stats.LifetimeValue/float64(completed)*100) / 100
+	}
+
+	for _, item := range items {
+		res.FavouriteItems = append(res.FavouriteItems, *item)
+	}
+	sort.Slice(res.FavouriteItems, func(i, j int) bool {
+		if res.FavouriteItems[i].OrdersCount != res.FavouriteItems[j].OrdersCount {
+			return res.FavouriteItems[i].OrdersCount > res.FavouriteItems[j].OrdersCount
+		}
+		if res.FavouriteItems[i].Quantity != res.FavouriteItems[j].Quantity {
+			return res.FavouriteItems[i].Quantity > res.FavouriteItems[j].Quantity
+		}
+		return res.FavouriteItems[i].Name < res.FavouriteItems[j].Name
+	})
+	if len(res.FavouriteItems) > favouriteItemsLimit {
+		res.FavouriteItems = res.FavouriteItems[:favouriteItemsLimit]
+	}
+
+	for _, address := range addresses {
+		res.Addresses = append(res.Addresses, *address)
+	}
+	sort.Slice(res.Addresses, func(i, j int) bool {
+		return res.Addresses[i].LastUsedAt.After(res.Addresses[j].LastUsedAt)
+	})
+	if len(res.Addresses) > addressesLimit {
+		res.Addresses = res.Addresses[:addressesLimit]
+	}
+
+	if res.Name == "" && len(profile.Names) != 0 {
+		res.Name = profile.Names[len(profile.Names)-1]
+	}
+
+	return res
+}
+
+func isCancelledStatus(status string) bool {
+	switch OrderStatus(status) {
+	case STATUS_CANCELLED, STATUS_CANCELLED_BY_POS_SYSTEM, STATUS_CANCELLED_BY_DELIVERY_SERVICE, STATUS_FAILED, STATUS_SKIPPED:
+		return true
+	}
+	return false
+}
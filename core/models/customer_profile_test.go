@@ -0,0 +1,78 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		phone    string
+		expected string
+	}{
+		{phone: "+7 (700) 123-45-67", expected: "+77001234567"},
+		{phone: "77001234567", expected: "+77001234567"},
+		{phone: "87001234567", expected: "+77001234567"},
+		{phone: "7001234567", expected: "+77001234567"},
+		{phone: "+971501234567", expected: "+971501234567"},
+		{phone: "12345", expected: ""},
+	}
+
+	for _, tt := range tests {
+		if res := NormalizePhone(tt.phone); res != tt.expected {
+			t.Errorf("phone %s: expected %s, got %s", tt.phone, tt.expected, res)
+		}
+	}
+
+	if variants := PhoneVariants("8 700 123 45 67"); !reflect.DeepEqual(variants, []string{"+77001234567", "77001234567", "87001234567"}) {
+		t.Errorf("unexpected variants %v", variants)
+	}
+}
+
+func TestBuildCustomerGroupProfile(t *testing.T) {
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	newOrder := func(daysAgo int, deliveryService, status, address string, total float64, products ...OrderProduct) Order {
+		return Order{
+			DeliveryService:     deliveryService,
+			Status:              status,
+			OrderTime:           TransactionTime{Value: Time{Time: day.AddDate(0, 0, -daysAgo)}},
+			EstimatedTotalPrice: Price{Value: total},
+			Customer:            Customer{Name: "Name " + deliveryService},
+			DeliveryAddress:     DeliveryAddress{Label: address},
+			Products:            products,
+		}
+	}
+	burger := OrderProduct{ID: "burger", Name: "Burger", Quantity: 2}
+	cola := OrderProduct{ID: "cola", Name: "Cola", Quantity: 3}
+
+	profile := BuildCustomerGroupProfile(CustomerProfile{ID: "+77001234567"}, []Order{
+		newOrder(10, "glovo", string(STATUS_CLOSED), "Abay 1", 5000, burger),
+		newOrder(1, "qr_menu", string(STATUS_DELIVERED), "Abay 1", 3000, burger, cola),
+		newOrder(5, "qr_menu", string(STATUS_CLOSED), "Dostyk 5", 4000, cola),
+		newOrder(3, "wolt", string(STATUS_CANCELLED_BY_POS_SYSTEM), "Satpayev 7", 9000, cola),
+		{IsTestOrder: true, EstimatedTotalPrice: Price{Value: 100000}},
+	}, nil)
+
+	stats := profile.Stats
+	if stats.OrdersCount != 4 || stats.CancelledCount != 1 || stats.LifetimeValue != 12000 || stats.AverageBill != 4000 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if !stats.LastOrderAt.Equal(day.AddDate(0, 0, -1)) || !stats.FirstOrderAt.Equal(day.AddDate(0, 0, -10)) {
+		t.Errorf("unexpected order dates %+v", stats)
+	}
+	if stats.Channels["qr_menu"] != 2 || stats.Channels["wolt"] != 1 {
+		t.Errorf("unexpected channels %v", stats.Channels)
+	}
+	if profile.Name != "Name qr_menu" || profile.Phone != "+77001234567" {
+		t.Errorf("unexpected customer %s %s", profile.Name, profile.Phone)
+	}
+
+	if len(profile.FavouriteItems) != 2 || profile.FavouriteItems[0].ProductID != "cola" || profile.FavouriteItems[0].Quantity != 6 || profile.FavouriteItems[1].Quantity != 4 {
+		t.Errorf("unexpected favourite items %+v", profile.FavouriteItems)
+	}
+
+	if len(profile.Addresses) != 2 || profile.Addresses[0].Label != "Abay 1" || profile.Addresses[0].OrdersCount != 2 || profile.Addresses[1].Label != "Dostyk 5" {
+		t.Errorf("unexpected addresses %+v", profile.Addresses)
+	}
+}
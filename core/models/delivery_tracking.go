@@ -0,0 +1,127 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	models3 "github.com/kwaaka-team/orders-core/service/kwaaka_3pl/models"
+)
+
+// DeliveryTracking - отслеживание 3pl доставки для клиента
+type DeliveryTracking struct {
+	OrderID        string              `json:"order_id"`
+	OrderCode      string              `json:"order_code"`
+	RestaurantName string              `json:"restaurant_name"`
+	DeliveryID     string              `json:"delivery_id"`
+	Provider       string              `json:"provider"`
+	Status         string              `json:"status"`
+	Statuses       []GetDeliveryStatus `json:"statuses"`
+	CourierName    string              `json:"courier_name,omitempty"`
+	CourierPhone   string              `json:"courier_phone,omitempty"`
+	TrackingUrl    string              `json:"tracking_url,omitempty"`
+	Latitude       float64             `json:"latitude,omitempty"`
+	Longitude      float64             `json:"longitude,omitempty"`
+	ETA            *time.Time          `json:"eta,omitempty"`
+}
+
+// NewDeliveryTracking собирает отслеживание из заказа и доставки 3pl.
+// ETA - время забора курьером (или ожидаемое время готовности) плюс dispatcher_delivery_time, для завершенной доставки не считается
+func NewDeliveryTracking(order Order, delivery Delivery3plOrder) DeliveryTracking {
+	tracking := DeliveryTracking{
+		OrderID:        order.OrderID,
+		OrderCode:      order.OrderCode,
+		RestaurantName: order.RestaurantName,
+		DeliveryID:     delivery.Id,
+		Provider:       order.DeliveryDispatcher,
+		Status:         delivery.Status,
+		Statuses:       delivery.StatusHistory,
+		CourierName:    delivery.Courier.CourierName,
+		CourierPhone:   delivery.Courier.CourierPhone,
+		TrackingUrl:    delivery.Courier.TrackingUrl,
+		Latitude:       delivery.Courier.Latitude,
+		Longitude:      delivery.Courier.Longitude,
+	}
+
+	if tracking.CourierName == "" {
+		tracking.CourierName = order.Courier.Name
+	}
+	if tracking.CourierPhone == "" {
+		tracking.CourierPhone = order.Courier.PhoneNumber
+	}
+
+	if !IsDeliveryTrackingFinished(delivery.Status) && order.DispatcherDeliveryTime > 0 {
+		from := order.EstimatedPickupTime.Value.Time
+		for _, status := range delivery.StatusHistory {
+			if status.Status == models3.PickedUp {
+				from = status.CreatedAt
+			}
+		}
+		if !from.IsZero() {
+			eta := from.Add(time.Duration(order.DispatcherDeliveryTime) * time.Minute)
+			tracking.ETA = &eta
+		}
+	}
+
+	return tracking
+}
+
+// WithMaskedCourierPhone - отслеживание для публичного эндпоинта qr menu: телефон курьера скрыт кроме кода страны и последних 4 цифр
+func (t DeliveryTracking) WithMaskedCourierPhone() DeliveryTracking {
+	t.CourierPhone = maskPhone(t.CourierPhone)
+	return t
+}
+
+func maskPhone(phone string) string {
+	const prefix, suffix = 2, 4
+
+	runes := []rune(phone)
+	if len(runes) <= prefix+suffix {
+		return strings.Repeat("*", len(runes))
+	}
+	for i := prefix; i < len(runes)-suffix; i++ {
+		runes[i] = '*'
+	}
+	return string(runes)
+}
+
+// IsDeliveryTrackingFinished - доставка завершена, отслеживать больше нечего
+func IsDeliveryTrackingFinished(status string) bool {
+	switch status {
+	case models3.Delivered, models3.Cancelled, models3.Failed, models3.Returned:
+		return true
+	}
+	return false
+}
+
+// CustomerMessage - сообщение клиенту о текущем статусе доставки, ETA выводится в часовом поясе ресторана utcOffset.
+// Для статусов без сообщения - пустая строка
+func (t DeliveryTracking) CustomerMessage(utcOffset float64) string {
+	var msg string
+	switch t.Status {
+	case models3.PerformerLookup:
+		msg = fmt.Sprintf("Ищем курьера для вашего заказа %s из %s", t.OrderCode, t.RestaurantName)
+	case models3.ComingToPickup:
+		msg = fmt.Sprintf("Курьер едет в %s за вашим заказом %s", t.RestaurantName, t.OrderCode)
+	case models3.PickedUp:
+		msg = fmt.Sprintf("Курьер забрал ваш заказ %s и уже в пути", t.OrderCode)
+	case models3.Delivered:
+		return fmt.Sprintf("Ваш заказ %s доставлен. Приятного аппетита!", t.OrderCode)
+	case models3.Cancelled, models3.Failed:
+		return fmt.Sprintf("Доставка заказа %s отменена, ресторан свяжется с вами", t.OrderCode)
+	default:
+		return ""
+	}
+
+	if t.CourierName != "" || t.CourierPhone != "" {
+		msg += fmt.Sprintf("\nКурьер: %s %s", t.CourierName, t.CourierPhone)
+	}
+	if t.ETA != nil {
+		msg += fmt.Sprintf("\nОжидаемое время доставки: %s", t.ETA.Add(time.Duration(utcOffset*float64(time.Hour))).Format("15:04"))
+	}
+	if t.TrackingUrl != "" {
+		msg += "\nОтследить курьера: " + t.TrackingUrl
+	}
+
+	return msg
+}
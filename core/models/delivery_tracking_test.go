@@ -0,0 +1,84 @@
+package models
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewDeliveryTracking(t *testing.T) {
+	readyAt := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	pickedAt := readyAt.Add(5 * time.Minute)
+
+	order := Order{
+		OrderID:                "order",
+		OrderCode:              "A-12",
+		RestaurantName:         "Burger",
+		DeliveryDispatcher:     "wolt",
+		DispatcherDeliveryTime: 20,
+		EstimatedPickupTime:    TransactionTime{Value: Time{readyAt}},
+		Courier:                Courier{Name: "Askar", PhoneNumber: "+77010000000"},
+	}
+
+	tests := []struct {
+		name     string
+		delivery Delivery3plOrder
+		eta      *time.Time
+	}{
+		{
+			name:     "eta from estimated pickup time",
+			delivery: Delivery3plOrder{Status: "COMING_TO_PICKUP"},
+			eta:      timePtr(readyAt.Add(20 * time.Minute)),
+		},
+		{
+			name: "eta from picked up status",
+			delivery: Delivery3plOrder{Status: "PICKED_UP", StatusHistory: []GetDeliveryStatus{
+				{Status: "COMING_TO_PICKUP", CreatedAt: readyAt.Add(-10 * time.Minute)},
+				{Status: "PICKED_UP", CreatedAt: pickedAt},
+			}},
+			eta: timePtr(pickedAt.Add(20 * time.Minute)),
+		},
+		{
+			name:     "no eta for finished delivery",
+			delivery: Delivery3plOrder{Status: "DELIVERED"},
+		},
+	}
+
+	for _, test := range tests {
+		tracking := NewDeliveryTracking(order, test.delivery)
+		assert.Equal(t, test.eta, tracking.ETA, test.name)
+		assert.Equal(t, "Askar", tracking.CourierName, test.name)
+	}
+
+	tracking := NewDeliveryTracking(order, Delivery3plOrder{
+		Status:  "PICKED_UP",
+		Courier: GetDeliveryOrderTrackingUrl{TrackingUrl: "https://track", CourierName: "Dana", CourierPhone: "+77020000000"},
+		StatusHistory: []GetDeliveryStatus{
+			{Status: "PICKED_UP", CreatedAt: pickedAt},
+		},
+	})
+	assert.Equal(t, "Курьер забрал ваш заказ A-12 и уже в пути\nКурьер: Dana +77020000000\nОжидаемое время доставки: 17:25\nОтследить курьера: https://track", tracking.CustomerMessage(5))
+	assert.Empty(t, NewDeliveryTracking(order, Delivery3plOrder{Status: "RETURNING"}).CustomerMessage(5))
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func TestDeliveryTrackingWithMaskedCourierPhone(t *testing.T) {
+	tests := []struct {
+		phone    string
+		expected string
+	}{
+		{"+77020001234", "+7******1234"},
+		{"87020001234", "87*****1234"},
+		{"12345", "*****"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		tracking := DeliveryTracking{CourierName: "Dana", CourierPhone: test.phone}.WithMaskedCourierPhone()
+		assert.Equal(t, test.expected, tracking.CourierPhone, test.phone)
+		assert.Equal(t, "Dana", tracking.CourierName, test.phone)
+	}
+}
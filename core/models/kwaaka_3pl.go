@@ -33,6 +33,7 @@ type GetDeliveryOrderTrackingUrl struct {
 	Longitude    float64 `json:"longitude" bson:"longitude"`
 	Latitude     float64 `json:"latitude" bson:"latitude"`
 	CourierPhone string  `json:"phone_number" bson:"phone_number"`
+	CourierName  string  `json:"name,omitempty" bson:"name,omitempty"`
 }
 
 type DeliveryService struct {
@@ -50,6 +51,8 @@ type GetOrdersByCustomerPhoneRequest struct {
 type GetOrdersByCustomerPhoneResponse struct {
 	Orders               []OrderByCustomerPhone `json:"orders"`
 	CustomerOrderHistory CustomerOrderHistory   `json:"customer_order_history"`
+	// CustomerProfile - профиль клиента в группе ресторана: lifetime value, любимые позиции, адреса и карты
+	CustomerProfile *CustomerGroupProfile `json:"customer_profile,omitempty"`
 }
 type CustomerOrderHistory struct {
 	Phone       string    `json:"phone"`
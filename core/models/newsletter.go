@@ -1,14 +1,176 @@
 package models
 
-import "time"
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	NewsletterDeliveryPending  = "pending"
+	NewsletterDeliverySent     = "sent"
+	NewsletterDeliveryFailed   = "failed"
+	NewsletterDeliveryOptedOut = "opted_out"
+
+	NewsletterVariableName      = "{{name}}"
+	NewsletterVariablePromoCode = "{{promo_code}}"
+
+	// newsletterFavouriteTop - сколько первых любимых позиций клиента проверяется для сегмента по продукту
+	newsletterFavouriteTop = 3
+	// newsletterLookbackDays - за сколько дней до порога давности последнего заказа сегмента учитываются заказы клиентов
+	newsletterLookbackDays = 365
+)
+
+// newsletterOptOutKeywords - ответы клиента, после которых рассылки группы ему не отправляются
+var newsletterOptOutKeywords = []string{"STOP", "СТОП", "ОТПИСАТЬСЯ"}
 
 type Newsletter struct {
-	Name              string    `bson:"name"`
-	RestaurantGroupId string    `bson:"restaurant_group_id"`
-	Text              string    `bson:"text"`
-	Recipients        []string  `bson:"recipients"`
-	Status            string    `bson:"status"`
-	SendDate          time.Time `bson:"send_date"`
-	CreatedAt         time.Time `bson:"created_at"`
-	UpdatedAt         time.Time `bson:"updated_at"`
+	ID                string               `bson:"_id,omitempty" json:"id"`
+	Name              string               `bson:"name" json:"name"`
+	RestaurantGroupId string               `bson:"restaurant_group_id" json:"restaurant_group_id"`
+	Text              string               `bson:"text" json:"text"`
+	PromoCode         string               `bson:"promo_code" json:"promo_code"`
+	Segment           NewsletterSegment    `bson:"segment" json:"segment"`
+	Recipients        []string             `bson:"recipients" json:"recipients"`
+	Deliveries        []NewsletterDelivery `bson:"deliveries" json:"deliveries"`
+	Status            string               `bson:"status" json:"status"`
+	// SendDate - время отправки в UTC, задается во времени ресторанов группы
+	SendDate    time.Time `bson:"send_date" json:"send_date"`
+	LockedUntil time.Time `bson:"locked_until" json:"-"`
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// NewsletterSegment - фильтр клиентов рассылки, пустые поля не ограничивают
+type NewsletterSegment struct {
+	// LastOrderOlderThanDays - последний заказ был раньше, чем столько дней назад
+	LastOrderOlderThanDays int `bson:"last_order_older_than_days" json:"last_order_older_than_days"`
+	MinOrdersCount         int `bson:"min_orders_count" json:"min_orders_count"`
+	MaxOrdersCount         int `bson:"max_orders_count" json:"max_orders_count"`
+	// FavouriteProductID - продукт в тройке любимых позиций клиента
+	FavouriteProductID string `bson:"favourite_product_id" json:"favourite_product_id"`
+	// StoreIDs, Cities - клиент заказывал в одном из ресторанов или городов
+	StoreIDs []string `bson:"store_ids" json:"store_ids"`
+	Cities   []string `bson:"cities" json:"cities"`
+}
+
+type NewsletterDelivery struct {
+	Phone  string    `bson:"phone" json:"phone"`
+	Name   string    `bson:"name" json:"name"`
+	Status string    `bson:"status" json:"status"`
+	Error  string    `bson:"error,omitempty" json:"error,omitempty"`
+	SentAt time.Time `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+}
+
+type NewsletterOptOut struct {
+	Phone string `bson:"phone" json:"phone"`
+	// RestaurantGroupID - группа, от рассылок которой отписался клиент, пустая - от всех рассылок
+	RestaurantGroupID string    `bson:"restaurant_group_id" json:"restaurant_group_id"`
+	CreatedAt         time.Time `bson:"created_at" json:"created_at"`
+}
+
+// OrdersFrom - начало периода заказов для подбора получателей, статистика сегмента считается по заказам этого периода
+func (s NewsletterSegment) OrdersFrom(now time.Time) time.Time {
+	return now.AddDate(0, 0, -(s.LastOrderOlderThanDays + newsletterLookbackDays))
+}
+
+func (s NewsletterSegment) HasStores() bool {
+	return len(s.StoreIDs) != 0 || len(s.Cities) != 0
+}
+
+// Match проверяет клиента по сегменту, stores - рестораны сегмента с учетом городов
+func (s NewsletterSegment) Match(profile CustomerGroupProfile, stores map[string]bool, now time.Time) bool {
+	if s.LastOrderOlderThanDays > 0 && profile.Stats.LastOrderAt.After(now.AddDate(0, 0, -s.LastOrderOlderThanDays)) {
+		return false
+	}
+	if s.MinOrdersCount > 0 && profile.Stats.OrdersCount < s.MinOrdersCount {
+		return false
+	}
+	if s.MaxOrdersCount > 0 && profile.Stats.OrdersCount > s.MaxOrdersCount {
+		return false
+	}
+
+	if s.FavouriteProductID != "" {
+		favourite := false
+		for i, item := range profile.FavouriteItems {
+			if i == newsletterFavouriteTop {
+				break
+			}
+			if item.ProductID == s.FavouriteProductID {
+				favourite = true
+				break
+			}
+		}
+		if !favourite {
+			return false
+		}
+	}
+
+	if s.HasStores() {
+		ordered := false
+		for storeID := range profile.Stats.Stores {
+			if stores[storeID] {
+				ordered = true
+				break
+			}
+		}
+		if !ordered {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RenderNewsletterText подставляет имя клиента и промокод в текст рассылки
+func RenderNewsletterText(text, name, promoCode string) string {
+	return strings.NewReplacer(
+		NewsletterVariableName, strings.TrimSpace(name),
+		NewsletterVariablePromoCode, promoCode,
+	).Replace(text)
+}
+
+// IsNewsletterOptOut - сообщение клиента является отпиской от рассылок
+func IsNewsletterOptOut(message string) bool {
+	message = strings.ToUpper(strings.Trim(strings.TrimSpace(message), ".!"))
+	for _, keyword := range newsletterOptOutKeywords {
+		if message == keyword {
+			return true
+		}
+	}
+	return false
+}
+
+type ScheduleNewsletterRequest struct {
+	Name              string            `json:"name"`
+	RestaurantGroupID string            `json:"rest_group_id" binding:"required"`
+	Text              string            `json:"text" binding:"required"`
+	PromoCode         string            `json:"promo_code"`
+	Segment           NewsletterSegment `json:"segment"`
+	// SendTime - время отправки во времени ресторанов группы в формате 2006-01-02 15:04, пустое - сразу
+	SendTime string `json:"send_time"`
+}
+
+// NewsletterDeliveries - получатели рассылки из клиентов группы, заказывавших через qr_menu и подходящих под сегмент
+func NewsletterDeliveries(customers []CustomerGroupProfile, segment NewsletterSegment, stores map[string]bool, now time.Time) []NewsletterDelivery {
+	deliveries := make([]NewsletterDelivery, 0)
+	for _, customer := range customers {
+		if customer.Phone == "" || customer.Stats.Channels["qr_menu"] == 0 {
+			continue
+		}
+		if !segment.Match(customer, stores, now) {
+			continue
+		}
+		deliveries = append(deliveries, NewsletterDelivery{
+			Phone:  customer.Phone,
+			Name:   customer.Name,
+			Status: NewsletterDeliveryPending,
+		})
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].Phone < deliveries[j].Phone
+	})
+
+	return deliveries
 }
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewsletterDeliveries(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	newCustomer := func(phone string, lastOrderDaysAgo, orders int, store string, favourite ...string) CustomerGroupProfile {
+		profile := CustomerGroupProfile{
+			Phone: phone,
+			Name:  "Name " + phone,
+			Stats: CustomerStats{
+				OrdersCount: orders,
+				LastOrderAt: now.AddDate(0, 0, -lastOrderDaysAgo),
+				Channels:    map[string]int{"qr_menu": orders},
+				Stores:      map[string]int{store: orders},
+			},
+		}
+		for _, productID := range favourite {
+			profile.FavouriteItems = append(profile.FavouriteItems, CustomerFavouriteItem{ProductID: productID})
+		}
+		return profile
+	}
+	glovoOnly := newCustomer("+77000000009", 60, 5, "almaty")
+	glovoOnly.Stats.Channels = map[string]int{"glovo": 5}
+
+	customers := []CustomerGroupProfile{
+		newCustomer("+77000000003", 40, 5, "astana", "burger"),
+		newCustomer("+77000000001", 31, 1, "almaty", "cola", "fries", "salad", "burger"),
+		newCustomer("+77000000002", 10, 8, "almaty", "burger"),
+		glovoOnly,
+	}
+	almaty := map[string]bool{"almaty": true}
+
+	tests := []struct {
+		name     string
+		segment  NewsletterSegment
+		stores   map[string]bool
+		expected []string
+	}{
+		{
+			name:     "all qr menu customers",
+			expected: []string{"+77000000001", "+77000000002", "+77000000003"},
+		},
+		{
+			name:     "last order older than 30 days",
+			segment:  NewsletterSegment{LastOrderOlderThanDays: 30},
+			expected: []string{"+77000000001", "+77000000003"},
+		},
+		{
+			name:     "orders count between 2 and 6",
+			segment:  NewsletterSegment{MinOrdersCount: 2, MaxOrdersCount: 6},
+			expected: []string{"+77000000003"},
+		},
+		{
+			name:     "burger in top 3 favourite items",
+			segment:  NewsletterSegment{FavouriteProductID: "burger"},
+			expected: []string{"+77000000002", "+77000000003"},
+		},
+		{
+			name:     "city stores",
+			segment:  NewsletterSegment{Cities: []string{"Almaty"}},
+			stores:   almaty,
+			expected: []string{"+77000000001", "+77000000002"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveries := NewsletterDeliveries(customers, tt.segment, tt.stores, now)
+			if len(deliveries) != len(tt.expected) {
+				t.Fatalf("expected %v, got %+v", tt.expected, deliveries)
+			}
+			for i, phone := range tt.expected {
+				if deliveries[i].Phone != phone || deliveries[i].Status != NewsletterDeliveryPending {
+					t.Errorf("expected %s pending, got %+v", phone, deliveries[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewsletterText(t *testing.T) {
+	if text := RenderNewsletterText("{{name}}, ваш промокод {{promo_code}}", "Айгерим ", "SPRING"); text != "Айгерим, ваш промокод SPRING" {
+		t.Errorf("unexpected text %s", text)
+	}
+
+	for message, expected := range map[string]bool{"stop": true, " Стоп! ": true, "Отписаться": true, "не стоп": false, "Да": false} {
+		if IsNewsletterOptOut(message) != expected {
+			t.Errorf("message %q: expected opt out %v", message, expected)
+		}
+	}
+}
+
+func TestNewsletterSegmentOrdersFrom(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	if from := (NewsletterSegment{}).OrdersFrom(now); !from.Equal(now.AddDate(0, 0, -365)) {
+		t.Errorf("unexpected orders from %v", from)
+	}
+	if from := (NewsletterSegment{LastOrderOlderThanDays: 30}).OrdersFrom(now); !from.Equal(now.AddDate(0, 0, -395)) {
+		t.Errorf("unexpected orders from %v for recency segment", from)
+	}
+}
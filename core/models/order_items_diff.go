@@ -0,0 +1,124 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OrderItemsDiff - изменение состава заказа со стороны агрегатора, применяется в pos дельтой
+type OrderItemsDiff struct {
+	Added     []OrderProduct            `bson:"added" json:"added"`
+	Removed   []OrderProduct            `bson:"removed" json:"removed"`
+	Changed   []OrderItemQuantityChange `bson:"changed" json:"changed"`
+	CreatedAt time.Time                 `bson:"created_at" json:"created_at"`
+}
+
+type OrderItemQuantityChange struct {
+	Product     OrderProduct `bson:"product" json:"product"`
+	OldQuantity int          `bson:"old_quantity" json:"old_quantity"`
+	NewQuantity int          `bson:"new_quantity" json:"new_quantity"`
+}
+
+func (c OrderItemQuantityChange) Delta() int {
+	return c.NewQuantity - c.OldQuantity
+}
+
+// NewOrderItemsDiff compares positions by product, size and attributes, quantities of equal positions are summed
+func NewOrderItemsDiff(oldProducts, newProducts []OrderProduct) OrderItemsDiff {
+	oldPositions, oldKeys := groupOrderPositions(oldProducts)
+	newPositions, newKeys := groupOrderPositions(newProducts)
+
+	diff := OrderItemsDiff{
+		CreatedAt: time.Now().UTC(),
+	}
+
+	for _, key := range oldKeys {
+		oldProduct := oldPositions[key]
+		newProduct, ok := newPositions[key]
+		switch {
+		case !ok:
+			diff.Removed = append(diff.Removed, oldProduct)
+		case oldProduct.Quantity != newProduct.Quantity:
+			diff.Changed = append(diff.Changed, OrderItemQuantityChange{
+				Product:     newProduct,
+				OldQuantity: oldProduct.Quantity,
+				NewQuantity: newProduct.Quantity,
+			})
+		}
+	}
+
+	for _, key := range newKeys {
+		if _, ok := oldPositions[key]; !ok {
+			diff.Added = append(diff.Added, newPositions[key])
+		}
+	}
+
+	return diff
+}
+
+func (d OrderItemsDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Increased returns added positions and increments of changed ones, quantity is the delta
+func (d OrderItemsDiff) Increased() []OrderProduct {
+	result := make([]OrderProduct, 0, len(d.Added))
+	result = append(result, d.Added...)
+
+	for _, change := range d.Changed {
+		if change.Delta() > 0 {
+			result = append(result, withQuantity(change.Product, change.Delta()))
+		}
+	}
+
+	return result
+}
+
+// Decreased returns removed positions and decrements of changed ones, quantity is the delta
+func (d OrderItemsDiff) Decreased() []OrderProduct {
+	result := make([]OrderProduct, 0, len(d.Removed))
+	result = append(result, d.Removed...)
+
+	for _, change := range d.Changed {
+		if change.Delta() < 0 {
+			result = append(result, withQuantity(change.Product, -change.Delta()))
+		}
+	}
+
+	return result
+}
+
+func withQuantity(product OrderProduct, quantity int) OrderProduct {
+	product.Quantity = quantity
+	return product
+}
+
+func groupOrderPositions(products []OrderProduct) (map[string]OrderProduct, []string) {
+	positions := make(map[string]OrderProduct, len(products))
+	keys := make([]string, 0, len(products))
+
+	for _, product := range products {
+		key := orderPositionKey(product)
+		if position, ok := positions[key]; ok {
+			position.Quantity += product.Quantity
+			positions[key] = position
+			continue
+		}
+		positions[key] = product
+		keys = append(keys, key)
+	}
+
+	return positions, keys
+}
+
+func orderPositionKey(product OrderProduct) string {
+	attributes := make([]string, 0, len(product.Attributes))
+	for _, attribute := range product.Attributes {
+		attributes = append(attributes, fmt.Sprintf("%s:%d", attribute.ID, attribute.Quantity))
+	}
+	sort.Strings(attributes)
+
+	return fmt.Sprintf("%s|%s|%s", product.ID, product.SizeId, strings.Join(attributes, ","))
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewOrderItemsDiff(t *testing.T) {
+	cheese := ProductAttribute{ID: "cheese", Quantity: 1}
+
+	oldProducts := []OrderProduct{
+		{ID: "burger", Name: "Burger", Quantity: 1},
+		{ID: "burger", Name: "Burger", Quantity: 1},
+		{ID: "burger", Name: "Burger", Quantity: 1, Attributes: []ProductAttribute{cheese}},
+		{ID: "cola", Name: "Cola", Quantity: 2},
+		{ID: "fries", Name: "Fries", Quantity: 1},
+	}
+	newProducts := []OrderProduct{
+		{ID: "burger", Name: "Burger", Quantity: 3},
+		{ID: "burger", Name: "Burger", Quantity: 1, Attributes: []ProductAttribute{cheese}},
+		{ID: "cola", Name: "Cola", Quantity: 1},
+		{ID: "sauce", Name: "Sauce", Quantity: 1},
+	}
+
+	diff := NewOrderItemsDiff(oldProducts, newProducts)
+
+	assert.False(t, diff.IsEmpty())
+	assert.Equal(t, []OrderProduct{{ID: "sauce", Name: "Sauce", Quantity: 1}}, diff.Added)
+	assert.Equal(t, []OrderProduct{{ID: "fries", Name: "Fries", Quantity: 1}}, diff.Removed)
+
+	if assert.Len(t, diff.Changed, 2) {
+		assert.Equal(t, "burger", diff.Changed[0].Product.ID)
+		assert.Equal(t, 1, diff.Changed[0].Delta())
+		assert.Equal(t, "cola", diff.Changed[1].Product.ID)
+		assert.Equal(t, -1, diff.Changed[1].Delta())
+	}
+
+	assert.Equal(t, []OrderProduct{
+		{ID: "sauce", Name: "Sauce", Quantity: 1},
+		{ID: "burger", Name: "Burger", Quantity: 1},
+	}, diff.Increased())
+	assert.Equal(t, []OrderProduct{
+		{ID: "fries", Name: "Fries", Quantity: 1},
+		{ID: "cola", Name: "Cola", Quantity: 1},
+	}, diff.Decreased())
+
+	assert.True(t, NewOrderItemsDiff(oldProducts, oldProducts).IsEmpty())
+}
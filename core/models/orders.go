@@ -147,6 +147,9 @@ type DeliveryAddress struct {
 type OrderStatusUpdate struct {
 	Name string    `bson:"name" json:"name"`
 	Time time.Time `bson:"time" json:"time"`
+	// Rejected - статус не был применен, т.к. переход запрещен state machine
+	Rejected bool   `bson:"rejected,omitempty" json:"rejected,omitempty"`
+	Reason   string `bson:"reason,omitempty" json:"reason,omitempty"`
 }
 
 type CancelReason struct {
@@ -269,11 +272,14 @@ type Order struct {
 	OperatorName                    string               `json:"operator_name" bson:"operator_name"`
 	History3plDeliveryInfo          []History3plDelivery `json:"history_3pl_delivery_info" bson:"history_3pl_delivery_info,omitempty"`
 	DeliveryDispatcherPrice         float64              `bson:"delivery_dispatcher_price" json:"delivery_dispatcher_price,omitempty"`
+	CustomerTrackingStatus          string               `bson:"customer_tracking_status,omitempty" json:"customer_tracking_status,omitempty"` // последний статус 3pl доставки, отправленный клиенту
+	AggregatorCommission            *OrderCommission     `bson:"aggregator_commission,omitempty" json:"aggregator_commission,omitempty"`
 	IsTestOrder                     bool                 `bson:"is_test_order" json:"is_test_order,omitempty"`
 	PositionsOnStop                 []PositionsOnStop    `bson:"positions_on_stop" json:"positions_on_stop,omitempty"`
 	// Todo temporary `Canceled3PlDeliveryInfo` field for kwaaka report analytics. Delete after a couple of months
 	Canceled3PlDeliveryInfo []Cancelled3PLDelivery `bson:"canceled_3pl_delivery_info,omitempty" json:"canceled_3pl_delivery_info,omitempty"`
 	IsCashPayment           bool                   `bson:"is_cash_payment" json:"is_cash_payment,omitempty"`
+	ItemsChanges            []OrderItemsDiff       `bson:"items_changes,omitempty" json:"items_changes,omitempty"`
 }
 
 type FailReason struct {
@@ -364,6 +370,9 @@ type History3plDelivery struct {
 	KwaakaChargedDeliveryPrice float64         `bson:"kwaaka_charged_delivery_price" json:"delivery_service_fee"`
 	DeliveryAddress            DeliveryAddress `bson:"delivery_address" json:"delivery_address"`
 	Customer                   Customer        `bson:"customer" json:"customer"`
+	Reason                     string          `bson:"reason,omitempty" json:"reason,omitempty"`
+	NextDispatcher             string          `bson:"next_dispatcher,omitempty" json:"next_dispatcher,omitempty"`
+	ReassignedAt               time.Time       `bson:"reassigned_at,omitempty" json:"reassigned_at,omitempty"`
 }
 
 type OrderInfoForTelegramMsg struct {
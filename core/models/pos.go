@@ -19,6 +19,7 @@ const (
 	Kwaaka      Pos = "kwaaka_pos"
 	Ytimes      Pos = "ytimes"
 	Posist      Pos = "posist"
+	Sandbox     Pos = "sandbox"
 )
 
 func (p Pos) String() string {
@@ -44,6 +44,7 @@ type OrderReport struct {
 	PaymentSystem                string             `json:"payment_system"`
 	EstimatedTotalPrice          float64            `json:"estimated_total_price"`
 	TotalOrderPrice              float64            `json:"total_order_price"` // Общая стоимость заказа
+	RefundedAmount               float64            `json:"refunded_amount"`   // Возвраты по позициям заказа
 	CustomerName                 string             `json:"customer_name"`
 	CustomerPhone                string             `json:"customer_phone"`
 	SendCourier                  bool               `json:"send_courier"`
@@ -0,0 +1,260 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	OrderReviewRequested = "requested"
+	OrderReviewRated     = "rated"
+
+	ReviewChannelWhatsapp = "whatsapp"
+	ReviewChannelSms      = "sms"
+
+	ReviewMinRating = 1
+	ReviewMaxRating = 5
+	// ReviewLowRating - оценка заказа не выше этой отправляется в telegram чат ресторана
+	ReviewLowRating = 3
+	// ReviewHighRating - клиента с оценкой заказа не ниже этой перенаправляем оставить отзыв в 2ГИС
+	ReviewHighRating = 5
+)
+
+var (
+	ErrInvalidReviewRating     = errors.New("rating must be from 1 to 5")
+	ErrReviewProductNotInOrder = errors.New("reviewed product is not in order")
+	ErrOrderReviewAlreadyRated = errors.New("order review is already rated")
+)
+
+// OrderReview - запрос отзыва по заказу и оценка клиента, один на заказ. Клиент открывает отзыв по Token из ссылки
+type OrderReview struct {
+	OrderID           string `bson:"_id" json:"order_id"`
+	Token             string `bson:"token" json:"-"`
+	RestaurantID      string `bson:"restaurant_id" json:"restaurant_id"`
+	RestaurantName    string `bson:"restaurant_name" json:"restaurant_name"`
+	RestaurantGroupID string `bson:"restaurant_group_id" json:"restaurant_group_id"`
+	DeliveryService   string `bson:"delivery_service" json:"delivery_service"`
+	// CourierProvider - сервис доставки курьером (delivery_dispatcher заказа), пустой - самовывоз или курьер ресторана
+	CourierProvider string            `bson:"courier_provider" json:"courier_provider"`
+	CustomerPhone   string            `bson:"customer_phone" json:"-"`
+	CustomerName    string            `bson:"customer_name" json:"customer_name"`
+	Channel         string            `bson:"channel" json:"channel"`
+	Status          string            `bson:"status" json:"status"`
+	Items           []OrderReviewItem `bson:"items" json:"items"`
+	Rating          int               `bson:"rating" json:"rating"`
+	Comment         string            `bson:"comment" json:"comment"`
+	Products        []ProductReview   `bson:"products" json:"products"`
+	RequestedAt     time.Time         `bson:"requested_at" json:"requested_at"`
+	RatedAt         time.Time         `bson:"rated_at,omitempty" json:"rated_at,omitempty"`
+}
+
+// OrderReviewItem - позиция заказа, которую клиент может оценить отдельно
+type OrderReviewItem struct {
+	ProductID string `bson:"product_id" json:"product_id"`
+	Name      string `bson:"name" json:"name"`
+}
+
+type ProductReview struct {
+	ProductID string `bson:"product_id" json:"product_id"`
+	Name      string `bson:"name" json:"name"`
+	Rating    int    `bson:"rating" json:"rating"`
+	Comment   string `bson:"comment" json:"comment"`
+}
+
+type SubmitOrderReviewRequest struct {
+	Rating   int             `json:"rating" binding:"required"`
+	Comment  string          `json:"comment"`
+	Products []ProductReview `json:"products"`
+}
+
+type SubmitOrderReviewResponse struct {
+	Review OrderReview `json:"review"`
+	// RedirectURL - ссылка на отзыв в 2ГИС для высокой оценки
+	RedirectURL string `json:"redirect_url,omitempty"`
+}
+
+func NewOrderReview(order Order, token, restaurantName, restaurantGroupID string, now time.Time) OrderReview {
+	items := make([]OrderReviewItem, 0, len(order.Products))
+	added := make(map[string]bool, len(order.Products))
+	for _, product := range order.Products {
+		if product.ID == "" || added[product.ID] {
+			continue
+		}
+		added[product.ID] = true
+		items = append(items, OrderReviewItem{ProductID: product.ID, Name: product.Name})
+	}
+
+	return OrderReview{
+		OrderID:           order.ID,
+		Token:             token,
+		RestaurantID:      order.RestaurantID,
+		RestaurantName:    restaurantName,
+		RestaurantGroupID: restaurantGroupID,
+		DeliveryService:   order.DeliveryService,
+		CourierProvider:   order.DeliveryDispatcher,
+		CustomerPhone:     NormalizePhone(order.Customer.PhoneNumber),
+		CustomerName:      strings.TrimSpace(order.Customer.Name),
+		Status:            OrderReviewRequested,
+		Items:             items,
+		Products:          []ProductReview{},
+		RequestedAt:       now,
+	}
+}
+
+// Rate проверяет и записывает оценку клиента. Позиции без оценки не сохраняются
+func (r *OrderReview) Rate(req SubmitOrderReviewRequest, now time.Time) error {
+	if r.Status == OrderReviewRated {
+		return ErrOrderReviewAlreadyRated
+	}
+	if !isValidReviewRating(req.Rating) {
+		return ErrInvalidReviewRating
+	}
+
+	names := make(map[string]string, len(r.Items))
+	for _, item := range r.Items {
+		names[item.ProductID] = item.Name
+	}
+
+	products := make([]ProductReview, 0, len(req.Products))
+	for _, product := range req.Products {
+		if product.Rating == 0 {
+			continue
+		}
+		name, ok := names[product.ProductID]
+		if !ok {
+			return ErrReviewProductNotInOrder
+		}
+		if !isValidReviewRating(product.Rating) {
+			return ErrInvalidReviewRating
+		}
+		product.Name = name
+		product.Comment = strings.TrimSpace(product.Comment)
+		products = append(products, product)
+	}
+
+	r.Rating = req.Rating
+	r.Comment = strings.TrimSpace(req.Comment)
+	r.Products = products
+	r.Status = OrderReviewRated
+	r.RatedAt = now
+
+	return nil
+}
+
+func (r OrderReview) IsLowRating() bool {
+	return r.Status == OrderReviewRated && r.Rating <= ReviewLowRating
+}
+
+func (r OrderReview) IsHighRating() bool {
+	return r.Status == OrderReviewRated && r.Rating >= ReviewHighRating
+}
+
+// RequestMessage - сообщение клиенту со ссылкой на отзыв, qrMenuURL - адрес qr меню ресторана
+func (r OrderReview) RequestMessage(qrMenuURL string) string {
+	link := strings.TrimRight(qrMenuURL, "/") + "/review/" + r.Token
+	return fmt.Sprintf("Спасибо за заказ в %s! Оцените его, пожалуйста, это займет минуту: %s", r.RestaurantName, link)
+}
+
+func isValidReviewRating(rating int) bool {
+	return rating >= ReviewMinRating && rating <= ReviewMaxRating
+}
+
+type ReviewRatingsRequest struct {
+	RestaurantIDs     []string  `json:"restaurant_ids"`
+	RestaurantGroupID string    `json:"restaurant_group_id"`
+	StartDate         time.Time `json:"start_date" binding:"required"`
+	EndDate           time.Time `json:"end_date" binding:"required"`
+}
+
+// RatingStats - оценки ресторана, продукта или сервиса доставки
+type RatingStats struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Count   int     `json:"count"`
+	Average float64 `json:"average"`
+	// LowCount - оценки не выше ReviewLowRating
+	LowCount int `json:"low_count"`
+	// Distribution - количество оценок по звездам, индекс 0 - одна звезда
+	Distribution [ReviewMaxRating]int `json:"distribution"`
+	sum          int
+}
+
+func (s *RatingStats) add(rating int) {
+	s.Count++
+	s.sum += rating
+	s.Distribution[rating-1]++
+	if rating <= ReviewLowRating {
+		s.LowCount++
+	}
+	s.Average = math.Round(float64(s.sum)/float64(s.Count)*100) / 100
+}
+
+type ReviewRatings struct {
+	StartDate        time.Time     `json:"start_date"`
+	EndDate          time.Time     `json:"end_date"`
+	Total            RatingStats   `json:"total"`
+	Stores           []RatingStats `json:"stores"`
+	Products         []RatingStats `json:"products"`
+	CourierProviders []RatingStats `json:"courier_providers"`
+}
+
+// BuildReviewRatings собирает оценки отзывов по ресторанам, продуктам и сервисам доставки, больше оценок - выше в списке
+func BuildReviewRatings(reviews []OrderReview) ReviewRatings {
+	var (
+		total     RatingStats
+		stores    = map[string]*RatingStats{}
+		products  = map[string]*RatingStats{}
+		providers = map[string]*RatingStats{}
+	)
+
+	stats := func(m map[string]*RatingStats, id, name string) *RatingStats {
+		if _, ok := m[id]; !ok {
+			m[id] = &RatingStats{ID: id, Name: name}
+		}
+		return m[id]
+	}
+
+	for _, review := range reviews {
+		if review.Status != OrderReviewRated || !isValidReviewRating(review.Rating) {
+			continue
+		}
+
+		total.add(review.Rating)
+		stats(stores, review.RestaurantID, review.RestaurantName).add(review.Rating)
+		if review.CourierProvider != "" {
+			stats(providers, review.CourierProvider, review.CourierProvider).add(review.Rating)
+		}
+		for _, product := range review.Products {
+			if isValidReviewRating(product.Rating) {
+				stats(products, product.ProductID, product.Name).add(product.Rating)
+			}
+		}
+	}
+
+	return ReviewRatings{
+		Total:            total,
+		Stores:           sortedRatingStats(stores),
+		Products:         sortedRatingStats(products),
+		CourierProviders: sortedRatingStats(providers),
+	}
+}
+
+func sortedRatingStats(m map[string]*RatingStats) []RatingStats {
+	res := make([]RatingStats, 0, len(m))
+	for _, stats := range m {
+		res = append(res, *stats)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].ID < res[j].ID
+	})
+
+	return res
+}
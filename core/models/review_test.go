@@ -0,0 +1,100 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrderReviewRate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	order := Order{
+		ID:           "order",
+		RestaurantID: "store",
+		Customer:     Customer{PhoneNumber: "87001234567"},
+		Products: []OrderProduct{
+			{ID: "burger", Name: "Бургер"},
+			{ID: "cola", Name: "Кола"},
+			{ID: "burger", Name: "Бургер"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		req      SubmitOrderReviewRequest
+		err      error
+		products int
+	}{
+		{
+			name:     "order and product ratings",
+			req:      SubmitOrderReviewRequest{Rating: 2, Comment: " холодный ", Products: []ProductReview{{ProductID: "burger", Rating: 1}, {ProductID: "cola"}}},
+			products: 1,
+		},
+		{
+			name: "rating out of range",
+			req:  SubmitOrderReviewRequest{Rating: 6},
+			err:  ErrInvalidReviewRating,
+		},
+		{
+			name: "product rating out of range",
+			req:  SubmitOrderReviewRequest{Rating: 5, Products: []ProductReview{{ProductID: "cola", Rating: 7}}},
+			err:  ErrInvalidReviewRating,
+		},
+		{
+			name: "product not in order",
+			req:  SubmitOrderReviewRequest{Rating: 5, Products: []ProductReview{{ProductID: "pizza", Rating: 5}}},
+			err:  ErrReviewProductNotInOrder,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			review := NewOrderReview(order, "token", "Store", "group", now)
+			if review.CustomerPhone != "+77001234567" || len(review.Items) != 2 {
+				t.Fatalf("unexpected review %+v", review)
+			}
+
+			err := review.Rate(tt.req, now)
+			if err != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(review.Products) != tt.products || review.Products[0].Name != "Бургер" || review.Comment != "холодный" {
+				t.Errorf("unexpected rated review %+v", review)
+			}
+			if !review.IsLowRating() || review.IsHighRating() {
+				t.Errorf("expected low rating")
+			}
+			if review.Rate(tt.req, now) != ErrOrderReviewAlreadyRated {
+				t.Errorf("expected already rated error")
+			}
+		})
+	}
+}
+
+func TestBuildReviewRatings(t *testing.T) {
+	newReview := func(store, provider string, rating int, products ...ProductReview) OrderReview {
+		return OrderReview{RestaurantID: store, RestaurantName: store, CourierProvider: provider, Status: OrderReviewRated, Rating: rating, Products: products}
+	}
+
+	ratings := BuildReviewRatings([]OrderReview{
+		newReview("a", "yandex", 5, ProductReview{ProductID: "burger", Name: "Бургер", Rating: 4}),
+		newReview("a", "", 2, ProductReview{ProductID: "burger", Name: "Бургер", Rating: 1}),
+		newReview("b", "yandex", 4),
+		{RestaurantID: "b", Status: OrderReviewRequested},
+	})
+
+	if ratings.Total.Count != 3 || ratings.Total.Average != 3.67 || ratings.Total.LowCount != 1 {
+		t.Errorf("unexpected total %+v", ratings.Total)
+	}
+	if len(ratings.Stores) != 2 || ratings.Stores[0].ID != "a" || ratings.Stores[0].Average != 3.5 || ratings.Stores[0].Distribution != [ReviewMaxRating]int{0, 1, 0, 0, 1} {
+		t.Errorf("unexpected stores %+v", ratings.Stores)
+	}
+	if len(ratings.Products) != 1 || ratings.Products[0].Count != 2 || ratings.Products[0].Average != 2.5 {
+		t.Errorf("unexpected products %+v", ratings.Products)
+	}
+	if len(ratings.CourierProviders) != 1 || ratings.CourierProviders[0].Count != 2 || ratings.CourierProviders[0].Average != 4.5 {
+		t.Errorf("unexpected courier providers %+v", ratings.CourierProviders)
+	}
+}
@@ -31,6 +31,7 @@ type Order struct {
 	IsDeferSubmission           bool
 	IsParentOrder               bool
 	CustomerNumber              string
+	CustomerPhones              []string
 	FailReason                  string
 	FailedReasonCode            string
 	FailedReasonTimeoutCodes    []string
@@ -136,6 +137,16 @@ func (o Order) SetCustomerNumber(customerNumber string) Order {
 	return o
 }
 
+func (o Order) HasCustomerPhones() bool {
+	return len(o.CustomerPhones) != 0
+}
+
+// SetCustomerPhones - заказы клиента по всем вариантам записи телефона
+func (o Order) SetCustomerPhones(phones []string) Order {
+	o.CustomerPhones = phones
+	return o
+}
+
 func (o Order) SetDeliveryService(deliveryService string) Order {
 	o.DeliveryService = deliveryService
 	return o
@@ -0,0 +1,283 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	SLAStageWebhookToAccepted         = "webhook_to_accepted"
+	SLAStageAcceptedToCookingComplete = "accepted_to_cooking_complete"
+	SLAStageReadyToPickedUp           = "ready_to_picked_up"
+	SLAStageTimeToRead                = "time_to_read"
+)
+
+var SLAStages = []string{SLAStageWebhookToAccepted, SLAStageAcceptedToCookingComplete, SLAStageReadyToPickedUp, SLAStageTimeToRead}
+
+// SLAThresholds - допустимая длительность этапов заказа в минутах, 0 - порог по умолчанию
+type SLAThresholds struct {
+	WebhookToAccepted         float64 `bson:"webhook_to_accepted" json:"webhook_to_accepted"`
+	AcceptedToCookingComplete float64 `bson:"accepted_to_cooking_complete" json:"accepted_to_cooking_complete"`
+	ReadyToPickedUp           float64 `bson:"ready_to_picked_up" json:"ready_to_picked_up"`
+	TimeToRead                float64 `bson:"time_to_read" json:"time_to_read"`
+}
+
+func DefaultSLAThresholds() SLAThresholds {
+	return SLAThresholds{
+		WebhookToAccepted:         3,
+		AcceptedToCookingComplete: 30,
+		ReadyToPickedUp:           15,
+		TimeToRead:                2,
+	}
+}
+
+// Merge - пороги t поверх base, незаданные пороги берутся из base
+func (t SLAThresholds) Merge(base SLAThresholds) SLAThresholds {
+	if t.WebhookToAccepted == 0 {
+		t.WebhookToAccepted = base.WebhookToAccepted
+	}
+	if t.AcceptedToCookingComplete == 0 {
+		t.AcceptedToCookingComplete = base.AcceptedToCookingComplete
+	}
+	if t.ReadyToPickedUp == 0 {
+		t.ReadyToPickedUp = base.ReadyToPickedUp
+	}
+	if t.TimeToRead == 0 {
+		t.TimeToRead = base.TimeToRead
+	}
+	return t
+}
+
+func (t SLAThresholds) Get(stage string) time.Duration {
+	var minutes float64
+	switch stage {
+	case SLAStageWebhookToAccepted:
+		minutes = t.WebhookToAccepted
+	case SLAStageAcceptedToCookingComplete:
+		minutes = t.AcceptedToCookingComplete
+	case SLAStageReadyToPickedUp:
+		minutes = t.ReadyToPickedUp
+	case SLAStageTimeToRead:
+		minutes = t.TimeToRead
+	}
+	return time.Duration(minutes * float64(time.Minute))
+}
+
+type SLAAnalyticsRequest struct {
+	RestaurantIDs     []string       `json:"restaurant_ids"`
+	RestaurantGroupID string         `json:"restaurant_group_id"`
+	StartDate         time.Time      `json:"start_date" binding:"required"`
+	EndDate           time.Time      `json:"end_date" binding:"required"`
+	Thresholds        *SLAThresholds `json:"thresholds"`
+	BreachesLimit     int            `json:"breaches_limit"`
+}
+
+// SLAStageStats - перцентили длительности этапа в минутах
+type SLAStageStats struct {
+	Stage    string  `json:"stage"`
+	Count    int     `json:"count"`
+	P50      float64 `json:"p50"`
+	P90      float64 `json:"p90"`
+	P99      float64 `json:"p99"`
+	Breaches int     `json:"breaches"`
+}
+
+type SLAGroupStats struct {
+	Key         string          `json:"key"`
+	Name        string          `json:"name,omitempty"`
+	OrdersCount int             `json:"orders_count"`
+	Stages      []SLAStageStats `json:"stages"`
+}
+
+// SLABreach - этап заказа, превысивший порог, длительности в минутах
+type SLABreach struct {
+	OrderID         string  `json:"order_id"`
+	OrderCode       string  `json:"order_code"`
+	RestaurantID    string  `json:"restaurant_id"`
+	RestaurantName  string  `json:"restaurant_name"`
+	DeliveryService string  `json:"delivery_service"`
+	PosType         string  `json:"pos_type"`
+	Stage           string  `json:"stage"`
+	Duration        float64 `json:"duration"`
+	Threshold       float64 `json:"threshold"`
+}
+
+type SLAAnalytics struct {
+	StartDate   time.Time       `json:"start_date"`
+	EndDate     time.Time       `json:"end_date"`
+	Stores      []SLAGroupStats `json:"stores"`
+	PosTypes    []SLAGroupStats `json:"pos_types"`
+	Aggregators []SLAGroupStats `json:"aggregators"`
+	Breaches    []SLABreach     `json:"breaches"`
+}
+
+// OrderSLADurations - длительности этапов заказа по statuses_history и reading_time, этапы без нужных статусов пропускаются.
+// Приход вебхука - created_at заказа, принятие в pos - первый статус ACCEPTED/COOKING_STARTED.
+// NEW не считается принятием: он пишется в историю при создании заказа
+func OrderSLADurations(order Order) map[string]time.Duration {
+	received := order.CreatedAt.Time
+	if received.IsZero() {
+		received = order.OrderTime.Value.Time
+	}
+
+	accepted := order.firstStatusTime(STATUS_ACCEPTED, STATUS_COOKING_STARTED)
+	cookingComplete := order.firstStatusTime(STATUS_COOKING_COMPLETE, STATUS_READY_FOR_PICKUP)
+	ready := order.firstStatusTime(STATUS_READY_FOR_PICKUP, STATUS_COOKING_COMPLETE)
+	pickedUp := order.firstStatusTime(STATUS_OUT_FOR_DELIVERY, STATUS_PICKED_UP_BY_CUSTOMER)
+
+	var read time.Time
+	if len(order.ReadingTime) != 0 {
+		read = order.ReadingTime[0].Value.Time
+	}
+
+	durations := make(map[string]time.Duration, len(SLAStages))
+	add := func(stage string, from, to time.Time) {
+		if from.IsZero() || to.IsZero() || to.Before(from) {
+			return
+		}
+		durations[stage] = to.Sub(from)
+	}
+
+	add(SLAStageWebhookToAccepted, received, accepted)
+	add(SLAStageAcceptedToCookingComplete, accepted, cookingComplete)
+	add(SLAStageReadyToPickedUp, ready, pickedUp)
+	add(SLAStageTimeToRead, received, read)
+
+	return durations
+}
+
+func (o Order) firstStatusTime(statuses ...OrderStatus) time.Time {
+	var res time.Time
+	for _, history := range o.StatusesHistory {
+		if history.Rejected {
+			continue
+		}
+		for _, status := range statuses {
+			if history.Name == string(status) && (res.IsZero() || history.Time.Before(res)) {
+				res = history.Time
+			}
+		}
+	}
+	return res
+}
+
+// DurationPercentile - перцентиль по методу ближайшего ранга, durations должны быть отсортированы
+func DurationPercentile(durations []time.Duration, percentile float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(percentile / 100 * float64(len(durations))))
+	if rank < 1 {
+		rank = 1
+	}
+	return durations[rank-1]
+}
+
+// BuildSLAAnalytics считает перцентили по ресторанам, pos системам и агрегаторам и находит нарушения порогов ресторана заказа.
+// В breaches попадают breachesLimit нарушений с наибольшим превышением порога, 0 - все
+func BuildSLAAnalytics(orders []Order, thresholds func(restaurantID string) SLAThresholds, breachesLimit int) SLAAnalytics {
+	type group struct {
+		name      string
+		orders    int
+		durations map[string][]time.Duration
+		breaches  map[string]int
+	}
+
+	stores, posTypes, aggregators := map[string]*group{}, map[string]*group{}, map[string]*group{}
+	var breaches []SLABreach
+
+	add := func(groups map[string]*group, key, name string, durations map[string]time.Duration, breached map[string]bool) {
+		g, ok := groups[key]
+		if !ok {
+			g = &group{name: name, durations: map[string][]time.Duration{}, breaches: map[string]int{}}
+			groups[key] = g
+		}
+		g.orders++
+		for stage, duration := range durations {
+			g.durations[stage] = append(g.durations[stage], duration)
+			if breached[stage] {
+				g.breaches[stage]++
+			}
+		}
+	}
+
+	for _, order := range orders {
+		if order.IsTestOrder {
+			continue
+		}
+
+		durations := OrderSLADurations(order)
+		if len(durations) == 0 {
+			continue
+		}
+
+		storeThresholds := thresholds(order.RestaurantID)
+		breached := make(map[string]bool)
+		for stage, duration := range durations {
+			threshold := storeThresholds.Get(stage)
+			if threshold == 0 || duration <= threshold {
+				continue
+			}
+			breached[stage] = true
+			breaches = append(breaches, SLABreach{
+				OrderID:         order.OrderID,
+				OrderCode:       order.OrderCode,
+				RestaurantID:    order.RestaurantID,
+				RestaurantName:  order.RestaurantName,
+				DeliveryService: order.DeliveryService,
+				PosType:         order.PosType,
+				Stage:           stage,
+				Duration:        durationMinutes(duration),
+				Threshold:       durationMinutes(threshold),
+			})
+		}
+
+		add(stores, order.RestaurantID, order.RestaurantName, durations, breached)
+		add(posTypes, order.PosType, "", durations, breached)
+		add(aggregators, order.DeliveryService, "", durations, breached)
+	}
+
+	toStats := func(groups map[string]*group) []SLAGroupStats {
+		res := make([]SLAGroupStats, 0, len(groups))
+		for key, g := range groups {
+			stats := SLAGroupStats{Key: key, Name: g.name, OrdersCount: g.orders}
+			for _, stage := range SLAStages {
+				durations, ok := g.durations[stage]
+				if !ok {
+					continue
+				}
+				sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+				stats.Stages = append(stats.Stages, SLAStageStats{
+					Stage:    stage,
+					Count:    len(durations),
+					P50:      durationMinutes(DurationPercentile(durations, 50)),
+					P90:      durationMinutes(DurationPercentile(durations, 90)),
+					P99:      durationMinutes(DurationPercentile(durations, 99)),
+					Breaches: g.breaches[stage],
+				})
+			}
+			res = append(res, stats)
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].Key < res[j].Key })
+		return res
+	}
+
+	sort.SliceStable(breaches, func(i, j int) bool {
+		return breaches[i].Duration-breaches[i].Threshold > breaches[j].Duration-breaches[j].Threshold
+	})
+	if breachesLimit > 0 && len(breaches) > breachesLimit {
+		breaches = breaches[:breachesLimit]
+	}
+
+	return SLAAnalytics{
+		Stores:      toStats(stores),
+		PosTypes:    toStats(posTypes),
+		Aggregators: toStats(aggregators),
+		Breaches:    breaches,
+	}
+}
+
+func durationMinutes(duration time.Duration) float64 {
+	return math.Round(duration.Minutes()*100) / 100
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildSLAAnalytics(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	newOrder := func(id string, acceptedAfter, readyAfter, pickedUpAfter time.Duration) Order {
+		return Order{
+			OrderID:         id,
+			RestaurantID:    "store",
+			RestaurantName:  "Store",
+			DeliveryService: "glovo",
+			PosType:         "iiko",
+			CreatedAt:       Time{Time: start},
+			ReadingTime:     []TransactionTime{{Value: Time{Time: start.Add(time.Minute)}}},
+			StatusesHistory: []OrderStatusUpdate{
+				{Name: string(STATUS_NEW), Time: start},
+				{Name: string(STATUS_ACCEPTED), Time: start.Add(time.Hour), Rejected: true},
+				{Name: string(STATUS_ACCEPTED), Time: start.Add(acceptedAfter)},
+				{Name: string(STATUS_READY_FOR_PICKUP), Time: start.Add(readyAfter)},
+				{Name: string(STATUS_OUT_FOR_DELIVERY), Time: start.Add(pickedUpAfter)},
+			},
+		}
+	}
+
+	var orders []Order
+	for i := 1; i <= 10; i++ {
+		orders = append(orders, newOrder("order", time.Duration(i)*time.Minute, 25*time.Minute, 30*time.Minute))
+	}
+	orders = append(orders, Order{RestaurantID: "store", IsTestOrder: true, CreatedAt: Time{Time: start}})
+
+	analytics := BuildSLAAnalytics(orders, func(string) SLAThresholds {
+		return SLAThresholds{WebhookToAccepted: 8}.Merge(DefaultSLAThresholds())
+	}, 1)
+
+	if len(analytics.Stores) != 1 || len(analytics.PosTypes) != 1 || len(analytics.Aggregators) != 1 {
+		t.Fatalf("unexpected groups %+v", analytics)
+	}
+
+	stages := make(map[string]SLAStageStats)
+	for _, stage := range analytics.Stores[0].Stages {
+		stages[stage.Stage] = stage
+	}
+
+	accepted := stages[SLAStageWebhookToAccepted]
+	if accepted.Count != 10 || accepted.P50 != 5 || accepted.P90 != 9 || accepted.P99 != 10 || accepted.Breaches != 2 {
+		t.Errorf("unexpected webhook to accepted stats %+v", accepted)
+	}
+	if stage := stages[SLAStageReadyToPickedUp]; stage.P50 != 5 || stage.Breaches != 0 {
+		t.Errorf("unexpected ready to picked up stats %+v", stage)
+	}
+	if stage := stages[SLAStageTimeToRead]; stage.P99 != 1 {
+		t.Errorf("unexpected time to read stats %+v", stage)
+	}
+
+	if len(analytics.Breaches) != 1 || analytics.Breaches[0].Duration != 10 || analytics.Breaches[0].Threshold != 8 {
+		t.Errorf("unexpected breaches %+v", analytics.Breaches)
+	}
+}
+
+func TestOrderSLADurations_NewIsNotAccepted(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		history []OrderStatusUpdate
+		want    time.Duration
+		ok      bool
+	}{
+		{
+			name:    "only new seeded on creation",
+			history: []OrderStatusUpdate{{Name: string(STATUS_NEW), Time: start}},
+		},
+		{
+			name: "new seeded on creation, accepted by pos later",
+			history: []OrderStatusUpdate{
+				{Name: string(STATUS_NEW), Time: start},
+				{Name: string(STATUS_ACCEPTED), Time: start.Add(4 * time.Minute)},
+			},
+			want: 4 * time.Minute,
+			ok:   true,
+		},
+		{
+			name: "cooking started without accepted",
+			history: []OrderStatusUpdate{
+				{Name: string(STATUS_NEW), Time: start},
+				{Name: string(STATUS_COOKING_STARTED), Time: start.Add(6 * time.Minute)},
+			},
+			want: 6 * time.Minute,
+			ok:   true,
+		},
+	}
+
+	for _, test := range tests {
+		durations := OrderSLADurations(Order{CreatedAt: Time{Time: start}, StatusesHistory: test.history})
+
+		got, ok := durations[SLAStageWebhookToAccepted]
+		if ok != test.ok || got != test.want {
+			t.Errorf("%s: expected %v (%v), got %v (%v)", test.name, test.want, test.ok, got, ok)
+		}
+	}
+}
@@ -3,18 +3,34 @@ package offline_orders
 import (
 	"context"
 	"database/sql"
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
 	"github.com/kwaaka-team/orders-core/pkg/iiko/models"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 )
 
+// IdentityLinker - привязка клиента iiko к профилю клиента по телефону, реализуется сервисом клиентов
+type IdentityLinker interface {
+	LinkIdentity(ctx context.Context, phone string, identity coreModels.CustomerIdentity) error
+}
+
 type Service struct {
-	db *sql.DB
+	db             *sql.DB
+	identityLinker IdentityLinker
 }
 
-func NewOfflineOrdersService(db *sql.DB) *Service {
-	return &Service{
-		db: db,
+func NewOfflineOrdersService(db *sql.DB, identityLinker IdentityLinker) (*Service, error) {
+	if db == nil {
+		return nil, errors.New("postgres db is nil")
+	}
+	if identityLinker == nil {
+		return nil, errors.New("identity linker is nil")
 	}
+
+	return &Service{
+		db:             db,
+		identityLinker: identityLinker,
+	}, nil
 }
 
 func (s *Service) SaveOrders(ctx context.Context, offlineOrders []models.ExtendedOrderEvent) error {
@@ -25,9 +41,31 @@ func (s *Service) SaveOrders(ctx context.Context, offlineOrders []models.Extende
 		return err
 	}
 	log.Info().Msgf("Saving offline order finished")
+
+	s.linkCustomers(ctx, offlineOrders)
 	return nil
 }
 
+// linkCustomers привязывает клиентов iiko к профилям клиентов, повторная привязка ничего не меняет. Ошибка привязки не прерывает сохранение заказов
+func (s *Service) linkCustomers(ctx context.Context, offlineOrders []models.ExtendedOrderEvent) {
+	for _, order := range offlineOrders {
+		phone := order.RegularCustomerInfo.Phone
+		if phone == "" {
+			phone = order.Order.Phone
+		}
+		if order.RegularCustomerInfo.Id == "" || phone == "" {
+			continue
+		}
+
+		if err := s.identityLinker.LinkIdentity(ctx, phone, coreModels.CustomerIdentity{
+			Source:     coreModels.CustomerIdentityIikoOffline,
+			ExternalID: order.RegularCustomerInfo.Id,
+		}); err != nil {
+			log.Err(err).Msgf("link iiko customer %s to customer profile", order.RegularCustomerInfo.Id)
+		}
+	}
+}
+
 func (s *Service) insertOfflineOrders(postgres *sql.DB, orders []models.ExtendedOrderEvent) error {
 	tx, err := postgres.Begin()
 	if err != nil {
@@ -6,6 +6,7 @@ type Store struct {
 	Currency                string
 	LanguageCode            string
 	StoreGroupId            string
+	LegalEntityID           string
 	Usernames               []string
 	Street                  string
 	City                    string
@@ -154,6 +155,15 @@ func (s Store) HasStoreGroupId() bool {
 	return s.StoreGroupId != ""
 }
 
+func (s Store) SetLegalEntityID(legalEntityID string) Store {
+	s.LegalEntityID = legalEntityID
+	return s
+}
+
+func (s Store) HasLegalEntityID() bool {
+	return s.LegalEntityID != ""
+}
+
 func (s Store) ActiveMenu() bool {
 	if s.IsActiveMenu != nil && *s.IsActiveMenu {
 		return true
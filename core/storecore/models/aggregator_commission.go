@@ -0,0 +1,60 @@
+package models
+
+import (
+	coreOrderModels "github.com/kwaaka-team/orders-core/core/models"
+	"github.com/pkg/errors"
+)
+
+// AggregatorCommissionContract - договор ресторана с агрегатором.
+// Percentage - процент от суммы заказа после скидки партнера, FixedFee - плата за заказ, AggregatorPromoShare - процент скидки, который компенсирует агрегатор
+type AggregatorCommissionContract struct {
+	DeliveryService      string  `bson:"delivery_service" json:"delivery_service"`
+	Percentage           float64 `bson:"percentage" json:"percentage"`
+	FixedFee             float64 `bson:"fixed_fee" json:"fixed_fee"`
+	AggregatorPromoShare float64 `bson:"aggregator_promo_share" json:"aggregator_promo_share"`
+}
+
+type AggregatorCommissionContracts []AggregatorCommissionContract
+
+func (c AggregatorCommissionContract) Validate() error {
+	if c.DeliveryService == "" {
+		return errors.New("aggregator commission contract: delivery service is empty")
+	}
+	if c.Percentage < 0 || c.Percentage > 100 {
+		return errors.Errorf("aggregator commission contract %s: percentage must be between 0 and 100", c.DeliveryService)
+	}
+	if c.FixedFee < 0 {
+		return errors.Errorf("aggregator commission contract %s: fixed fee must not be negative", c.DeliveryService)
+	}
+	if c.AggregatorPromoShare < 0 || c.AggregatorPromoShare > 100 {
+		return errors.Errorf("aggregator commission contract %s: aggregator promo share must be between 0 and 100", c.DeliveryService)
+	}
+	return nil
+}
+
+func (c AggregatorCommissionContract) Calculate(order coreOrderModels.Order) coreOrderModels.OrderCommission {
+	return coreOrderModels.CalculateAggregatorCommission(order, c.Percentage, c.FixedFee, c.AggregatorPromoShare)
+}
+
+func (c AggregatorCommissionContracts) Validate() error {
+	deliveryServices := make(map[string]struct{}, len(c))
+	for _, contract := range c {
+		if err := contract.Validate(); err != nil {
+			return err
+		}
+		if _, ok := deliveryServices[contract.DeliveryService]; ok {
+			return errors.Errorf("aggregator commission contract %s is duplicated", contract.DeliveryService)
+		}
+		deliveryServices[contract.DeliveryService] = struct{}{}
+	}
+	return nil
+}
+
+func (c AggregatorCommissionContracts) Find(deliveryService string) (AggregatorCommissionContract, bool) {
+	for _, contract := range c {
+		if contract.DeliveryService == deliveryService {
+			return contract, true
+		}
+	}
+	return AggregatorCommissionContract{}, false
+}
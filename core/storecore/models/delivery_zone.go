@@ -0,0 +1,133 @@
+package models
+
+import (
+	"github.com/kwaaka-team/orders-core/pkg/geo"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrOutOfDeliveryZone   = errors.New("address is out of restaurant delivery zones")
+	ErrNoDeliveryZones     = errors.New("no delivery zones configured")
+	ErrMinBasketNotReached = errors.New("basket is less than min basket for order")
+)
+
+// DeliveryQuote - цена доставки и минимальная сумма заказа для адреса по полигонам kwaaka_3pl ресторана
+type DeliveryQuote struct {
+	PolygonID                string  `json:"polygon_id"`
+	Priority                 int     `json:"priority"`
+	CPO                      float64 `json:"cpo"`
+	ClientDeliveryPrice      float64 `json:"client_delivery_price"`
+	MinBasketForOrder        float64 `json:"min_basket_for_order"`
+	MinBasketForFreeDelivery float64 `json:"min_basket_for_free_delivery"`
+	IsFreeDelivery           bool    `json:"is_free_delivery"`
+	IsMinBasketReached       bool    `json:"is_min_basket_reached"`
+	AmountToMinBasket        float64 `json:"amount_to_min_basket"`
+}
+
+// DeliveryZone - полигон, содержащий точку; при пересечении полигонов выбирается полигон с большим priority
+func (k Kwaaka3PL) DeliveryZone(point Coordinates) (Polygon, bool) {
+	zones := make([]geo.Zone, 0, len(k.Polygons))
+	for _, polygon := range k.Polygons {
+		vertices := make([]geo.Point, 0, len(polygon.Coordinates))
+		for _, coordinate := range polygon.Coordinates {
+			vertices = append(vertices, geo.Point{Lat: coordinate.Latitude, Lon: coordinate.Longitude})
+		}
+		zones = append(zones, geo.Zone{Priority: polygon.Priority, Vertices: vertices})
+	}
+
+	i, ok := geo.Resolve(zones, geo.Point{Lat: point.Latitude, Lon: point.Longitude})
+	if !ok {
+		return Polygon{}, false
+	}
+
+	return k.Polygons[i], true
+}
+
+// QuoteDelivery считает доставку для адреса и суммы корзины.
+// Клиент платит fixed_delivery_price_for_client, если polygon_based_fixed_delivery_price_for_client, иначе cpo полигона;
+// при min_basket_based_delivery_price доставка бесплатна от min_basket_for_free_delivery
+func (k Kwaaka3PL) QuoteDelivery(point Coordinates, basket float64) (DeliveryQuote, error) {
+	if len(k.Polygons) == 0 {
+		return DeliveryQuote{}, ErrNoDeliveryZones
+	}
+
+	polygon, ok := k.DeliveryZone(point)
+	if !ok {
+		return DeliveryQuote{}, ErrOutOfDeliveryZone
+	}
+
+	return polygon.Quote(basket), nil
+}
+
+// PriceDelivery - QuoteDelivery с настройками ресторана: без зон цена считается по cpo ресторана (LegacyQuote),
+// у is_dynamic ресторана цена доставки для клиента не меняется
+func (k Kwaaka3PL) PriceDelivery(point Coordinates, basket, clientDeliveryPrice float64) (DeliveryQuote, error) {
+	quote, err := k.QuoteDelivery(point, basket)
+	if errors.Is(err, ErrNoDeliveryZones) {
+		return k.LegacyQuote(clientDeliveryPrice), nil
+	}
+	if err != nil {
+		return DeliveryQuote{}, err
+	}
+
+	if k.IsDynamic {
+		quote.ClientDeliveryPrice = clientDeliveryPrice
+		quote.IsFreeDelivery = clientDeliveryPrice == 0
+	}
+
+	return quote, nil
+}
+
+// LegacyQuote - доставка ресторана без зон: клиент платит cpo ресторана, у is_dynamic ресторана - цену из заказа
+func (k Kwaaka3PL) LegacyQuote(clientDeliveryPrice float64) DeliveryQuote {
+	quote := DeliveryQuote{
+		CPO:                 k.CPO,
+		ClientDeliveryPrice: k.CPO,
+		IsMinBasketReached:  true,
+	}
+	if k.IsDynamic {
+		quote.ClientDeliveryPrice = clientDeliveryPrice
+	}
+	return quote
+}
+
+// CheckoutQuote - PriceDelivery для оформления заказа: корзина меньше min_basket_for_order зоны отклоняется
+func (k Kwaaka3PL) CheckoutQuote(point Coordinates, basket, clientDeliveryPrice float64) (DeliveryQuote, error) {
+	quote, err := k.PriceDelivery(point, basket, clientDeliveryPrice)
+	if err != nil {
+		return DeliveryQuote{}, err
+	}
+
+	if !quote.IsMinBasketReached {
+		return DeliveryQuote{}, errors.Wrapf(ErrMinBasketNotReached, "polygon %s, amount to min basket: %v", quote.PolygonID, quote.AmountToMinBasket)
+	}
+
+	return quote, nil
+}
+
+func (p Polygon) Quote(basket float64) DeliveryQuote {
+	quote := DeliveryQuote{
+		PolygonID:                p.ID,
+		Priority:                 p.Priority,
+		CPO:                      p.CPO,
+		ClientDeliveryPrice:      p.CPO,
+		MinBasketForOrder:        p.MinBasketForOrder,
+		MinBasketForFreeDelivery: p.MinBasketForFreeDelivery,
+		IsMinBasketReached:       basket >= p.MinBasketForOrder,
+	}
+
+	if p.PolygonBasedFixedDeliveryPriceForClient {
+		quote.ClientDeliveryPrice = p.FixedDeliveryPriceForClient
+	}
+
+	if p.MinBasketBasedDeliveryPrice && p.MinBasketForFreeDelivery > 0 && basket >= p.MinBasketForFreeDelivery {
+		quote.IsFreeDelivery = true
+		quote.ClientDeliveryPrice = 0
+	}
+
+	if !quote.IsMinBasketReached {
+		quote.AmountToMinBasket = p.MinBasketForOrder - basket
+	}
+
+	return quote
+}
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestKwaaka3PLQuoteDelivery(t *testing.T) {
+	square := func(lat, lon, size float64) []Coordinates {
+		return []Coordinates{
+			{Latitude: lat, Longitude: lon},
+			{Latitude: lat, Longitude: lon + size},
+			{Latitude: lat + size, Longitude: lon + size},
+			{Latitude: lat + size, Longitude: lon},
+		}
+	}
+
+	kwaaka3pl := Kwaaka3PL{
+		Polygons: []Polygon{
+			{ID: "city", Priority: 1, CPO: 1500, MinBasketForOrder: 3000, Coordinates: square(43.0, 76.0, 0.5)},
+			{ID: "center", Priority: 2, CPO: 1000, MinBasketForOrder: 2000, MinBasketBasedDeliveryPrice: true, MinBasketForFreeDelivery: 10000, Coordinates: square(43.2, 76.8, 0.1)},
+			{ID: "center-fixed", Priority: 2, CPO: 1200, PolygonBasedFixedDeliveryPriceForClient: true, FixedDeliveryPriceForClient: 500, Coordinates: square(43.2, 76.8, 0.1)},
+			{ID: "east", Priority: 1, CPO: 2000, PolygonBasedFixedDeliveryPriceForClient: true, FixedDeliveryPriceForClient: 700, Coordinates: square(43.0, 76.5, 0.5)},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		point    Coordinates
+		basket   float64
+		expected DeliveryQuote
+	}{
+		{
+			name:     "single polygon below min basket",
+			point:    Coordinates{Latitude: 43.1, Longitude: 76.1},
+			basket:   2500,
+			expected: DeliveryQuote{PolygonID: "city", Priority: 1, CPO: 1500, ClientDeliveryPrice: 1500, MinBasketForOrder: 3000, AmountToMinBasket: 500},
+		},
+		{
+			name:     "higher priority wins, first on equal priority",
+			point:    Coordinates{Latitude: 43.25, Longitude: 76.85},
+			basket:   5000,
+			expected: DeliveryQuote{PolygonID: "center", Priority: 2, CPO: 1000, ClientDeliveryPrice: 1000, MinBasketForOrder: 2000, MinBasketForFreeDelivery: 10000, IsMinBasketReached: true},
+		},
+		{
+			name:     "free delivery from min basket",
+			point:    Coordinates{Latitude: 43.25, Longitude: 76.85},
+			basket:   10000,
+			expected: DeliveryQuote{PolygonID: "center", Priority: 2, CPO: 1000, MinBasketForOrder: 2000, MinBasketForFreeDelivery: 10000, IsFreeDelivery: true, IsMinBasketReached: true},
+		},
+		{
+			name:     "point on shared border goes to first polygon",
+			point:    Coordinates{Latitude: 43.2, Longitude: 76.5},
+			basket:   3000,
+			expected: DeliveryQuote{PolygonID: "city", Priority: 1, CPO: 1500, ClientDeliveryPrice: 1500, MinBasketForOrder: 3000, IsMinBasketReached: true},
+		},
+		{
+			name:     "fixed client price",
+			point:    Coordinates{Latitude: 43.1, Longitude: 76.6},
+			basket:   1000,
+			expected: DeliveryQuote{PolygonID: "east", Priority: 1, CPO: 2000, ClientDeliveryPrice: 700, IsMinBasketReached: true},
+		},
+	}
+
+	for _, test := range tests {
+		quote, err := kwaaka3pl.QuoteDelivery(test.point, test.basket)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if quote != test.expected {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.expected, quote)
+		}
+	}
+
+	if _, err := kwaaka3pl.QuoteDelivery(Coordinates{Latitude: 51.1, Longitude: 71.4}, 5000); !errors.Is(err, ErrOutOfDeliveryZone) {
+		t.Errorf("out of zones: expected ErrOutOfDeliveryZone, got %v", err)
+	}
+
+	if _, err := (Kwaaka3PL{}).QuoteDelivery(Coordinates{Latitude: 43.1, Longitude: 76.1}, 5000); !errors.Is(err, ErrNoDeliveryZones) {
+		t.Errorf("no zones: expected ErrNoDeliveryZones, got %v", err)
+	}
+}
+
+func TestKwaaka3PLPriceDelivery(t *testing.T) {
+	city := Polygon{ID: "city", CPO: 1500, MinBasketForOrder: 3000, Coordinates: []Coordinates{
+		{Latitude: 43.0, Longitude: 76.0},
+		{Latitude: 43.0, Longitude: 76.5},
+		{Latitude: 43.5, Longitude: 76.5},
+		{Latitude: 43.5, Longitude: 76.0},
+	}}
+	inside := Coordinates{Latitude: 43.1, Longitude: 76.1}
+
+	tests := []struct {
+		name        string
+		kwaaka3pl   Kwaaka3PL
+		point       Coordinates
+		basket      float64
+		price       float64
+		expected    float64
+		expectedErr error
+	}{
+		{"no zones, store cpo", Kwaaka3PL{CPO: 900}, inside, 100, 400, 900, nil},
+		{"no zones, dynamic store keeps client price", Kwaaka3PL{CPO: 900, IsDynamic: true}, inside, 100, 400, 400, nil},
+		{"zone cpo", Kwaaka3PL{Polygons: []Polygon{city}}, inside, 3000, 400, 1500, nil},
+		{"dynamic store keeps client price in zone", Kwaaka3PL{IsDynamic: true, Polygons: []Polygon{city}}, inside, 3000, 400, 400, nil},
+		{"out of zones", Kwaaka3PL{Polygons: []Polygon{city}}, Coordinates{Latitude: 51.1, Longitude: 71.4}, 3000, 400, 0, ErrOutOfDeliveryZone},
+	}
+
+	for _, test := range tests {
+		quote, err := test.kwaaka3pl.PriceDelivery(test.point, test.basket, test.price)
+		if !errors.Is(err, test.expectedErr) {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expectedErr, err)
+		}
+		if quote.ClientDeliveryPrice != test.expected {
+			t.Errorf("%s: expected price %v, got %v", test.name, test.expected, quote.ClientDeliveryPrice)
+		}
+	}
+}
+
+func TestKwaaka3PLCheckoutQuote(t *testing.T) {
+	kwaaka3pl := Kwaaka3PL{
+		Polygons: []Polygon{
+			{ID: "city", CPO: 1500, MinBasketForOrder: 3000, Coordinates: []Coordinates{
+				{Latitude: 43.0, Longitude: 76.0},
+				{Latitude: 43.0, Longitude: 76.5},
+				{Latitude: 43.5, Longitude: 76.5},
+				{Latitude: 43.5, Longitude: 76.0},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		point       Coordinates
+		basket      float64
+		price       float64
+		expectedErr error
+	}{
+		{"zone fee", Coordinates{Latitude: 43.1, Longitude: 76.1}, 3000, 1500, nil},
+		{"below min basket", Coordinates{Latitude: 43.1, Longitude: 76.1}, 2999, 0, ErrMinBasketNotReached},
+		{"out of zones", Coordinates{Latitude: 51.1, Longitude: 71.4}, 5000, 0, ErrOutOfDeliveryZone},
+	}
+
+	for _, test := range tests {
+		quote, err := kwaaka3pl.CheckoutQuote(test.point, test.basket, 0)
+		if !errors.Is(err, test.expectedErr) {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expectedErr, err)
+		}
+		if quote.ClientDeliveryPrice != test.price {
+			t.Errorf("%s: expected price %v, got %v", test.name, test.price, quote.ClientDeliveryPrice)
+		}
+	}
+}
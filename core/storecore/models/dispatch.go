@@ -0,0 +1,41 @@
+package models
+
+const (
+	defaultDispatchPriceWeight     = 0.5
+	defaultDispatchEtaWeight       = 0.5
+	defaultNoCourierTimeoutMinutes = 15
+	defaultDispatchMaxAttempts     = 3
+)
+
+// Dispatch - настройки автоматического выбора провайдера 3pl доставки.
+// Провайдеры ранжируются по цене и времени доставки с весами PriceWeight и EtaWeight,
+// при AutoFallback доставка без курьера дольше NoCourierTimeoutMinutes переназначается следующему провайдеру, не больше MaxAttempts раз
+type Dispatch struct {
+	AutoFallback            bool    `bson:"auto_fallback" json:"auto_fallback"`
+	PriceWeight             float64 `bson:"price_weight" json:"price_weight"`
+	EtaWeight               float64 `bson:"eta_weight" json:"eta_weight"`
+	NoCourierTimeoutMinutes int     `bson:"no_courier_timeout_minutes" json:"no_courier_timeout_minutes"`
+	MaxAttempts             int     `bson:"max_attempts" json:"max_attempts"`
+}
+
+// Weights - веса цены и времени доставки, без настроек цена и время равнозначны
+func (d Dispatch) Weights() (float64, float64) {
+	if d.PriceWeight <= 0 && d.EtaWeight <= 0 {
+		return defaultDispatchPriceWeight, defaultDispatchEtaWeight
+	}
+	return d.PriceWeight, d.EtaWeight
+}
+
+func (d Dispatch) NoCourierTimeout() int {
+	if d.NoCourierTimeoutMinutes <= 0 {
+		return defaultNoCourierTimeoutMinutes
+	}
+	return d.NoCourierTimeoutMinutes
+}
+
+func (d Dispatch) Attempts() int {
+	if d.MaxAttempts <= 0 {
+		return defaultDispatchMaxAttempts
+	}
+	return d.MaxAttempts
+}
@@ -0,0 +1,183 @@
+package models
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type MarkupRounding string
+
+const (
+	MarkupRoundingNone    MarkupRounding = ""
+	MarkupRoundingUp      MarkupRounding = "up"
+	MarkupRoundingDown    MarkupRounding = "down"
+	MarkupRoundingNearest MarkupRounding = "nearest"
+)
+
+// Теги продукта для правил наценки, выводятся из флагов продукта меню
+const (
+	MarkupTagAlcohol = "alcohol"
+	MarkupTagTobacco = "tobacco"
+)
+
+// погрешность float, чтобы 2240.0000001 не округлялось вверх до 2250
+const markupRoundingEpsilon = 1e-9
+
+// MarkupRule - наценка на цену pos для продуктов меню агрегатора.
+// Пустые Aggregators, Sections и Tags подходят под любой продукт, MaxPrice не входит в ценовой диапазон, 0 - без верхней границы
+type MarkupRule struct {
+	Name            string         `bson:"name" json:"name"`
+	Priority        int            `bson:"priority" json:"priority"`
+	Aggregators     []string       `bson:"aggregators,omitempty" json:"aggregators"`
+	Sections        []string       `bson:"sections,omitempty" json:"sections"`
+	Tags            []string       `bson:"tags,omitempty" json:"tags"`
+	ExcludeSections []string       `bson:"exclude_sections,omitempty" json:"exclude_sections"`
+	ExcludeTags     []string       `bson:"exclude_tags,omitempty" json:"exclude_tags"`
+	MinPrice        float64        `bson:"min_price" json:"min_price"`
+	MaxPrice        float64        `bson:"max_price" json:"max_price"`
+	Percent         float64        `bson:"percent" json:"percent"`
+	MinAmount       float64        `bson:"min_amount" json:"min_amount"`
+	Rounding        MarkupRounding `bson:"rounding" json:"rounding"`
+	RoundTo         float64        `bson:"round_to" json:"round_to"`
+}
+
+// MarkupProduct - данные продукта меню агрегатора, по которым подбирается правило. Секция сравнивается и по id, и по названию
+type MarkupProduct struct {
+	Aggregator  string
+	SectionID   string
+	SectionName string
+	Tags        []string
+	Price       float64
+}
+
+type MarkupRules []MarkupRule
+
+func (r MarkupRule) Validate() error {
+	switch r.Rounding {
+	case MarkupRoundingNone, MarkupRoundingUp, MarkupRoundingDown, MarkupRoundingNearest:
+	default:
+		return errors.Errorf("markup rule %s: unknown rounding %s", r.Name, r.Rounding)
+	}
+
+	if r.Percent <= -100 {
+		return errors.Errorf("markup rule %s: percent must be greater than -100", r.Name)
+	}
+	if r.MinAmount < 0 || r.RoundTo < 0 || r.MinPrice < 0 || r.MaxPrice < 0 {
+		return errors.Errorf("markup rule %s: min_amount, round_to and price band must not be negative", r.Name)
+	}
+	if r.MaxPrice != 0 && r.MaxPrice <= r.MinPrice {
+		return errors.Errorf("markup rule %s: max_price must be greater than min_price", r.Name)
+	}
+	for _, tag := range append(r.Tags[:len(r.Tags):len(r.Tags)], r.ExcludeTags...) {
+		if !strings.EqualFold(tag, MarkupTagAlcohol) && !strings.EqualFold(tag, MarkupTagTobacco) {
+			return errors.Errorf("markup rule %s: unknown tag %s, only %s and %s are supported", r.Name, tag, MarkupTagAlcohol, MarkupTagTobacco)
+		}
+	}
+
+	return nil
+}
+
+func (r MarkupRule) Matches(product MarkupProduct) bool {
+	if len(r.Aggregators) != 0 && !containsFold(r.Aggregators, product.Aggregator) {
+		return false
+	}
+	if len(r.Sections) != 0 && !containsFold(r.Sections, product.SectionID, product.SectionName) {
+		return false
+	}
+	if len(r.Tags) != 0 && !containsFold(r.Tags, product.Tags...) {
+		return false
+	}
+	if containsFold(r.ExcludeSections, product.SectionID, product.SectionName) || containsFold(r.ExcludeTags, product.Tags...) {
+		return false
+	}
+	if product.Price < r.MinPrice || (r.MaxPrice != 0 && product.Price >= r.MaxPrice) {
+		return false
+	}
+
+	return true
+}
+
+// Apply - цена с наценкой: процент, но не меньше MinAmount, затем округление до RoundTo
+func (r MarkupRule) Apply(price float64) float64 {
+	markup := price * r.Percent / 100
+	if markup < r.MinAmount {
+		markup = r.MinAmount
+	}
+
+	return roundMarkupPrice(price+markup, r.Rounding, r.RoundTo)
+}
+
+func roundMarkupPrice(price float64, rounding MarkupRounding, step float64) float64 {
+	if step <= 0 {
+		step = 1
+	}
+
+	switch rounding {
+	case MarkupRoundingUp:
+		return math.Ceil(price/step-markupRoundingEpsilon) * step
+	case MarkupRoundingDown:
+		return math.Floor(price/step+markupRoundingEpsilon) * step
+	case MarkupRoundingNearest:
+		return math.Round(price/step) * step
+	}
+
+	return price
+}
+
+func (rules MarkupRules) Validate() error {
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Match возвращает подходящее правило с наибольшим приоритетом, при равном приоритете - первое в списке
+func (rules MarkupRules) Match(product MarkupProduct) (MarkupRule, bool) {
+	sorted := make(MarkupRules, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority > sorted[j].Priority
+	})
+
+	for _, rule := range sorted {
+		if rule.Matches(product) {
+			return rule, true
+		}
+	}
+
+	return MarkupRule{}, false
+}
+
+// MarkupPrice - цена продукта меню агрегатора из цены pos: по правилам наценки ресторана,
+// если ни одно правило не подошло - по markup_percent меню, как в /set-markup-to-aggregator-menu
+func (s Store) MarkupPrice(menuId string, product MarkupProduct) (float64, string) {
+	if product.Price <= 0 {
+		return product.Price, ""
+	}
+
+	if rule, ok := s.MarkupRules.Match(product); ok {
+		return rule.Apply(product.Price), rule.Name
+	}
+
+	if markupPercent := s.GetMenuMarkupPercent(menuId); markupPercent != 0 {
+		return float64(int(product.Price) * (100 + markupPercent) / 100), ""
+	}
+
+	return product.Price, ""
+}
+
+func containsFold(list []string, values ...string) bool {
+	for _, item := range list {
+		for _, value := range values {
+			if value != "" && strings.EqualFold(item, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package models
+
+import "testing"
+
+func TestMarkupRuleValidateTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    MarkupRule
+		wantErr bool
+	}{
+		{"derived tags", MarkupRule{Name: "alcohol", Tags: []string{"alcohol"}, ExcludeTags: []string{"Tobacco"}}, false},
+		{"without tags", MarkupRule{Name: "all", Percent: 10}, false},
+		{"tag not derived from product", MarkupRule{Name: "spicy", Tags: []string{"spicy"}}, true},
+		{"excluded tag not derived from product", MarkupRule{Name: "no vegan", ExcludeTags: []string{"vegan"}}, true},
+	}
+
+	for _, test := range tests {
+		if err := test.rule.Validate(); (err != nil) != test.wantErr {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.wantErr, err)
+		}
+	}
+}
@@ -16,4 +16,6 @@ type StoreQRMenuConfig struct {
 	AdjustedPickupMinutes int                        `bson:"adjusted_pickup_minutes" json:"adjusted_pickup_minutes"`
 	BusyMode              bool                       `bson:"busy_mode" json:"busy_mode"`
 	IsMarketplaceForIIKO  bool                       `bson:"is_marketplace_for_iiko" json:"is_marketplace_for_iiko"` //если true, тогда  создается заказ на кассе и ресторан назначает курьера
+	// SendReviewRequest - после доставки или закрытия заказа клиенту отправляется ссылка на отзыв
+	SendReviewRequest bool `bson:"send_review_request" json:"send_review_request"`
 }
@@ -0,0 +1,24 @@
+package models
+
+// SandboxConfig - настройки тестовой pos системы
+type SandboxConfig struct {
+	// Timeline - статусы, которые заказ проходит после создания
+	Timeline []SandboxTimelineStep `json:"timeline" bson:"timeline"`
+	// StopList - id продуктов в стоп листе
+	StopList []string         `json:"stop_list" bson:"stop_list"`
+	Products []SandboxProduct `json:"products" bson:"products"`
+	// Failure - timeout, product_not_found, terminal_offline
+	Failure string `json:"failure" bson:"failure"`
+}
+
+type SandboxTimelineStep struct {
+	Status string `json:"status" bson:"status"`
+	// AfterSeconds - время от предыдущего шага
+	AfterSeconds int `json:"after_seconds" bson:"after_seconds"`
+}
+
+type SandboxProduct struct {
+	ID    string  `json:"id" bson:"id"`
+	Name  string  `json:"name" bson:"name"`
+	Price float64 `json:"price" bson:"price"`
+}
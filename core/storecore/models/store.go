@@ -4,6 +4,7 @@ import (
 	"sort"
 	"time"
 
+	coreOrderModels "github.com/kwaaka-team/orders-core/core/models"
 	"github.com/pkg/errors"
 )
 
@@ -23,6 +24,8 @@ type StoreTelegramConfig struct {
 	CheckInChatID     string `bson:"check_in_chat_id" json:"check_in_chat_id"`
 	StoreStatusChatId string `bson:"store_status_chat_id"`
 	TelegramBotToken  string `bson:"telegram_bot_token"`
+	// ReviewChatID - чат для низких оценок заказов, пустой - group_chat_id
+	ReviewChatID string `bson:"review_chat_id" json:"review_chat_id"`
 }
 
 type TimezoneSetting struct {
@@ -146,6 +149,7 @@ type Store struct {
 	Poster                         StorePosterConfig              `bson:"poster" json:"poster"`
 	Jowi                           StoreJowiConfig                `bson:"jowi" json:"jowi"`
 	Posist                         StorePosistConfig              `bson:"posist" json:"posist"`
+	Sandbox                        SandboxConfig                  `bson:"sandbox" json:"sandbox"`
 	Telegram                       StoreTelegramConfig            `bson:"telegram" json:"telegram"`
 	ExternalPosIntegrationSettings ExternalPosIntegrationSettings `bson:"external_pos_integration_settings" json:"external_pos_integration_settings"`
 	Notification                   Notification                   `bson:"notification" json:"notification"`
@@ -182,6 +186,9 @@ type Store struct {
 	ValidationSettings             ValidationSettings             `bson:"validation_settings,omitempty" json:"validation_settings,omitempty"`
 	AutoUpdateSettings             AutoUpdateSettings             `bson:"auto_update_settings" json:"auto_update_settings"`
 	OrderAutoCloseSettings         OrderAutoCloseSettings         `bson:"order_auto_close_settings" json:"order_auto_close_settings"`
+	MarkupRules                    MarkupRules                    `bson:"markup_rules,omitempty" json:"markup_rules"`
+	AggregatorCommissions          AggregatorCommissionContracts  `bson:"aggregator_commissions,omitempty" json:"aggregator_commissions"`
+	SLAThresholds                  coreOrderModels.SLAThresholds  `bson:"sla_thresholds,omitempty" json:"sla_thresholds"`
 }
 
 type StoreStarterAppConfig struct {
@@ -254,6 +261,8 @@ type Kwaaka3PL struct {
 	TaxiClass              string    `bson:"taxi_class" json:"taxi_class"`
 	ChatID                 string    `bson:"chat_id" json:"chat_id"`
 	DeliveryPosProductId   string    `bson:"delivery_pos_product_id" json:"delivery_pos_product_id"`
+	Dispatch               Dispatch  `bson:"dispatch" json:"dispatch"`
+	NotifyCustomerTracking bool      `bson:"notify_customer_tracking" json:"notify_customer_tracking"`
 }
 
 type ValidationSettings struct {
@@ -431,6 +440,16 @@ type TimeZone struct {
 	UTCOffset float64 `bson:"utc_offset" json:"utc_offset"`
 }
 
+// Location returns store location by tz name, if tz is empty or unknown - fixed zone by utc offset in hours
+func (t TimeZone) Location() *time.Location {
+	if t.TZ != "" {
+		if loc, err := time.LoadLocation(t.TZ); err == nil {
+			return loc
+		}
+	}
+	return time.FixedZone("", int(t.UTCOffset*3600))
+}
+
 type MenuGroup struct {
 	MenuID  string `bson:"menu_id" json:"menu_id"`
 	GroupID string `bson:"group_id" json:"group_id"`
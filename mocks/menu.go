@@ -243,6 +243,66 @@ func (mr *MockClientMockRecorder) GetEmptyProducts(ctx, menuID, page, limit inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmptyProducts", reflect.TypeOf((*MockClient)(nil).GetEmptyProducts), ctx, menuID, page, limit)
 }
 
+// DiffMenuVersions mocks base method.
+func (m *MockClient) DiffMenuVersions(ctx context.Context, fromVersionID, toVersionID string) (models0.MenuDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffMenuVersions", ctx, fromVersionID, toVersionID)
+	ret0, _ := ret[0].(models0.MenuDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffMenuVersions indicates an expected call of DiffMenuVersions.
+func (mr *MockClientMockRecorder) DiffMenuVersions(ctx, fromVersionID, toVersionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffMenuVersions", reflect.TypeOf((*MockClient)(nil).DiffMenuVersions), ctx, fromVersionID, toVersionID)
+}
+
+// DryRunUploadMenu mocks base method.
+func (m *MockClient) DryRunUploadMenu(ctx context.Context, req dto.MenuUploadRequest) (models0.DryRunReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRunUploadMenu", ctx, req)
+	ret0, _ := ret[0].(models0.DryRunReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRunUploadMenu indicates an expected call of DryRunUploadMenu.
+func (mr *MockClientMockRecorder) DryRunUploadMenu(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRunUploadMenu", reflect.TypeOf((*MockClient)(nil).DryRunUploadMenu), ctx, req)
+}
+
+// SuggestProductMatches mocks base method.
+func (m *MockClient) SuggestProductMatches(ctx context.Context, storeID, menuID string, minConfidence float64) ([]models0.ProductMatchSuggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestProductMatches", ctx, storeID, menuID, minConfidence)
+	ret0, _ := ret[0].([]models0.ProductMatchSuggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestProductMatches indicates an expected call of SuggestProductMatches.
+func (mr *MockClientMockRecorder) SuggestProductMatches(ctx, storeID, menuID, minConfidence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestProductMatches", reflect.TypeOf((*MockClient)(nil).SuggestProductMatches), ctx, storeID, menuID, minConfidence)
+}
+
+// AcceptProductMatches mocks base method.
+func (m *MockClient) AcceptProductMatches(ctx context.Context, req dto.AcceptProductMatchesRequest) (models0.ProductMatchAcceptResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptProductMatches", ctx, req)
+	ret0, _ := ret[0].(models0.ProductMatchAcceptResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptProductMatches indicates an expected call of AcceptProductMatches.
+func (mr *MockClientMockRecorder) AcceptProductMatches(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptProductMatches", reflect.TypeOf((*MockClient)(nil).AcceptProductMatches), ctx, req)
+}
+
 // GetMenu mocks base method.
 func (m *MockClient) GetMenu(ctx context.Context, externalStoreID string, deliveryService dto.DeliveryService) (models0.Menu, error) {
 	m.ctrl.T.Helper()
@@ -469,6 +529,37 @@ func (mr *MockClientMockRecorder) PosIntegrationUpdateStopList(ctx, storeId, req
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PosIntegrationUpdateStopList", reflect.TypeOf((*MockClient)(nil).PosIntegrationUpdateStopList), ctx, storeId, request, author)
 }
 
+// ListMenuVersions mocks base method.
+func (m *MockClient) ListMenuVersions(ctx context.Context, menuID string, page, limit int64) ([]models0.MenuVersion, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMenuVersions", ctx, menuID, page, limit)
+	ret0, _ := ret[0].([]models0.MenuVersion)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListMenuVersions indicates an expected call of ListMenuVersions.
+func (mr *MockClientMockRecorder) ListMenuVersions(ctx, menuID, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMenuVersions", reflect.TypeOf((*MockClient)(nil).ListMenuVersions), ctx, menuID, page, limit)
+}
+
+// GetMenuVersion mocks base method.
+func (m *MockClient) GetMenuVersion(ctx context.Context, versionID string) (models0.MenuVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMenuVersion", ctx, versionID)
+	ret0, _ := ret[0].(models0.MenuVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMenuVersion indicates an expected call of GetMenuVersion.
+func (mr *MockClientMockRecorder) GetMenuVersion(ctx, versionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMenuVersion", reflect.TypeOf((*MockClient)(nil).GetMenuVersion), ctx, versionID)
+}
+
 // RecoveryMenu mocks base method.
 func (m *MockClient) RecoveryMenu(ctx context.Context, menuId, author string) error {
 	m.ctrl.T.Helper()
@@ -497,6 +588,21 @@ func (mr *MockClientMockRecorder) RenewPositionsInVirtualStore(ctx, restaurantID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewPositionsInVirtualStore", reflect.TypeOf((*MockClient)(nil).RenewPositionsInVirtualStore), ctx, restaurantID, originalRestaurantID)
 }
 
+// RollbackMenuVersion mocks base method.
+func (m *MockClient) RollbackMenuVersion(ctx context.Context, req dto.MenuRollbackRequest) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RollbackMenuVersion", ctx, req)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackMenuVersion indicates an expected call of RollbackMenuVersion.
+func (mr *MockClientMockRecorder) RollbackMenuVersion(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackMenuVersion", reflect.TypeOf((*MockClient)(nil).RollbackMenuVersion), ctx, req)
+}
+
 // StopPositionsInVirtualStore mocks base method.
 func (m *MockClient) StopPositionsInVirtualStore(ctx context.Context, restaurantID, originalRestaurantID string) error {
 	m.ctrl.T.Helper()
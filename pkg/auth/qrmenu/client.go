@@ -11,6 +11,9 @@ import (
 	"github.com/kwaaka-team/orders-core/core/auth/models"
 	"github.com/kwaaka-team/orders-core/core/auth/models/selector"
 	dto2 "github.com/kwaaka-team/orders-core/pkg/auth/qrmenu/dto"
+	"github.com/kwaaka-team/orders-core/service/customer"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"strconv"
 )
 
@@ -49,9 +52,30 @@ func NewClient() (Client, error) {
 		return nil, fmt.Errorf("cannot connect to datastore: %s", err)
 	}
 
+	// Профили клиентов хранятся в основной базе
+	coreDB, err := mongo.Connect(context.Background(), options.Client().ApplyURI(opts.DSURL))
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to core database: %s", err)
+	}
+
+	customerRepo, err := customer.NewMongoRepository(coreDB.Database(opts.DSDB))
+	if err != nil {
+		return nil, err
+	}
+
+	identityLinker, err := customer.NewIdentityLinker(customerRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	authManager, err := managers.NewIdentityLinkingAuthManager(managers.NewAuthManager(ds.AuthRepository(), validator.NewUserValidator()), identityLinker)
+	if err != nil {
+		return nil, err
+	}
+
 	return &authCore{
 		conf:        opts,
-		authManager: managers.NewAuthManager(ds.AuthRepository(), validator.NewUserValidator()),
+		authManager: authManager,
 	}, nil
 }
 
@@ -0,0 +1,67 @@
+package geo
+
+import "math"
+
+// погрешность сравнения координат, точка на границе полигона считается внутри
+const epsilon = 1e-9
+
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// Zone - полигон с приоритетом, при пересечении зон выбирается зона с большим приоритетом
+type Zone struct {
+	Priority int
+	Vertices []Point
+}
+
+// Contains - точка внутри полигона или на его границе (ray casting), полигон замыкается автоматически
+func Contains(vertices []Point, point Point) bool {
+	if len(vertices) < 3 {
+		return false
+	}
+
+	inside := false
+	for i, j := 0, len(vertices)-1; i < len(vertices); j, i = i, i+1 {
+		a, b := vertices[j], vertices[i]
+
+		if onSegment(a, b, point) {
+			return true
+		}
+
+		if (a.Lat > point.Lat) != (b.Lat > point.Lat) {
+			lon := a.Lon + (point.Lat-a.Lat)*(b.Lon-a.Lon)/(b.Lat-a.Lat)
+			if point.Lon < lon {
+				inside = !inside
+			}
+		}
+	}
+
+	return inside
+}
+
+// Resolve возвращает индекс зоны, содержащей точку: с наибольшим приоритетом, при равном - первой в списке
+func Resolve(zones []Zone, point Point) (int, bool) {
+	found := -1
+	for i, zone := range zones {
+		if found != -1 && zone.Priority <= zones[found].Priority {
+			continue
+		}
+		if Contains(zone.Vertices, point) {
+			found = i
+		}
+	}
+
+	return found, found != -1
+}
+
+func onSegment(a, b, p Point) bool {
+	cross := (b.Lon-a.Lon)*(p.Lat-a.Lat) - (b.Lat-a.Lat)*(p.Lon-a.Lon)
+	if math.Abs(cross) > epsilon {
+		return false
+	}
+
+	return p.Lon >= math.Min(a.Lon, b.Lon)-epsilon && p.Lon <= math.Max(a.Lon, b.Lon)+epsilon &&
+		p.Lat >= math.Min(a.Lat, b.Lat)-epsilon && p.Lat <= math.Max(a.Lat, b.Lat)+epsilon
+}
@@ -57,6 +57,13 @@ type Client interface {
 	ValidateAggProductErr(ctx context.Context, menuID string, storeID string, limit int) (aggregatorProduct []models.Product, posProduct []models.Product, total int, err error)
 
 	RecoveryMenu(ctx context.Context, menuId, author string) error
+	ListMenuVersions(ctx context.Context, menuID string, page int64, limit int64) ([]models.MenuVersion, int64, error)
+	GetMenuVersion(ctx context.Context, versionID string) (models.MenuVersion, error)
+	DiffMenuVersions(ctx context.Context, fromVersionID, toVersionID string) (models.MenuDiff, error)
+	RollbackMenuVersion(ctx context.Context, req dto.MenuRollbackRequest) (string, error)
+	DryRunUploadMenu(ctx context.Context, req dto.MenuUploadRequest) (models.DryRunReport, error)
+	SuggestProductMatches(ctx context.Context, storeID, menuID string, minConfidence float64) ([]models.ProductMatchSuggestion, error)
+	AcceptProductMatches(ctx context.Context, req dto.AcceptProductMatchesRequest) (models.ProductMatchAcceptResult, error)
 	MergeMenus(ctx context.Context, restaurantID string, restaurantIDs []string, author string) (string, error)
 	StopPositionsInVirtualStore(ctx context.Context, restaurantID, originalRestaurantID string) error
 	RenewPositionsInVirtualStore(ctx context.Context, restaurantID, originalRestaurantID string) error
@@ -149,7 +156,7 @@ func New(cfg dto.Config) (Client, error) {
 		storeCli:                     storeCli,
 		menuUploadTransactionManager: menuUploadTransactionManager,
 		storeManager:                 managers.NewStoreManager(opts, ds.StoreRepository(), ds.MenuRepository(entityChangesHistoryRepo), validator.NewStoreValidator()),
-		menuManager:                  managers.NewMenuManager(opts, ds, ds.MenuRepository(entityChangesHistoryRepo), ds.StoreRepository(), ds.PromoRepository(), menuUploadTransactionManager, stopListMan, validator.NewMenuValidator(), sqsCli, ds.MSPositionsRepository(), ds.StopListTransactionRepository(), storeCli, bkOffersManager, *menuServiceRepo, ds.RestGroupMenuRepository()),
+		menuManager:                  managers.NewMenuManager(opts, ds, ds.MenuRepository(entityChangesHistoryRepo), ds.StoreRepository(), ds.PromoRepository(), menuUploadTransactionManager, stopListMan, validator.NewMenuValidator(), sqsCli, ds.MSPositionsRepository(), ds.StopListTransactionRepository(), storeCli, bkOffersManager, menuServiceRepo, ds.RestGroupMenuRepository()),
 	}, nil
 }
 
@@ -419,6 +426,21 @@ func (cli *menuImpl) UploadMenu(ctx context.Context, req dto.MenuUploadRequest)
 	return res, nil
 }
 
+func (cli *menuImpl) DryRunUploadMenu(ctx context.Context, req dto.MenuUploadRequest) (models.DryRunReport, error) {
+	return cli.menuManager.DryRunUploadMenu(ctx, req.StoreId, req.MenuId, req.DeliveryName, req.UserRole)
+}
+
+func (cli *menuImpl) SuggestProductMatches(ctx context.Context, storeID, menuID string, minConfidence float64) ([]models.ProductMatchSuggestion, error) {
+	return cli.menuManager.SuggestProductMatches(ctx, storeID, menuID, minConfidence)
+}
+
+func (cli *menuImpl) AcceptProductMatches(ctx context.Context, req dto.AcceptProductMatchesRequest) (models.ProductMatchAcceptResult, error) {
+	return cli.menuManager.AcceptProductMatches(ctx, req.StoreID, req.MenuID, req.Matches, entityChangesHistoryModels.EntityChangesHistoryRequest{
+		Author:   req.UserName,
+		TaskType: "pkg/menu/client.go - AcceptProductMatches",
+	})
+}
+
 func (cli *menuImpl) CreateMenuUploadTransaction(ctx context.Context, req dto.MenuUploadTransaction) (string, error) {
 	transactionID, err := cli.menuUploadTransactionManager.Create(ctx, dto.FromMenuUploadTransaction(req))
 
@@ -488,6 +510,28 @@ func (cli *menuImpl) RecoveryMenu(ctx context.Context, menuId, author string) er
 	})
 }
 
+func (cli *menuImpl) ListMenuVersions(ctx context.Context, menuID string, page int64, limit int64) ([]models.MenuVersion, int64, error) {
+	return cli.menuManager.ListMenuVersions(ctx, menuID, selector.Pagination{
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+func (cli *menuImpl) GetMenuVersion(ctx context.Context, versionID string) (models.MenuVersion, error) {
+	return cli.menuManager.GetMenuVersion(ctx, versionID)
+}
+
+func (cli *menuImpl) DiffMenuVersions(ctx context.Context, fromVersionID, toVersionID string) (models.MenuDiff, error) {
+	return cli.menuManager.DiffMenuVersions(ctx, fromVersionID, toVersionID)
+}
+
+func (cli *menuImpl) RollbackMenuVersion(ctx context.Context, req dto.MenuRollbackRequest) (string, error) {
+	return cli.menuManager.RollbackMenuVersion(ctx, req.VersionID, req.Upload, req.Sv3, req.UserRole, req.UserName, entityChangesHistoryModels.EntityChangesHistoryRequest{
+		Author:   req.UserName,
+		TaskType: "pkg/menu/client.go - RollbackMenuVersion",
+	})
+}
+
 func (m *menuImpl) StopPositionsInVirtualStore(ctx context.Context, restaurantID, originalRestaurantID string) error {
 	if err := m.menuManager.StopPositionsInVirtualStore(ctx, restaurantID, originalRestaurantID); err != nil {
 		return err
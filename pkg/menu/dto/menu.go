@@ -56,6 +56,22 @@ type MenuUploadRequest struct {
 	UserName     string
 }
 
+// MenuRollbackRequest - Upload отправляет восстановленное меню агрегатору через UploadMenu
+type MenuRollbackRequest struct {
+	VersionID string
+	Upload    bool
+	Sv3       *s3.S3
+	UserRole  string
+	UserName  string
+}
+
+type AcceptProductMatchesRequest struct {
+	StoreID  string
+	MenuID   string
+	Matches  []models.ProductMatch
+	UserName string
+}
+
 type MenuUploadVerifyRequest struct {
 	TransactionId string
 }
@@ -157,7 +157,7 @@ func NewClient() (*OrderCoreClient, error) {
 		return nil, fmt.Errorf("cannot initialize telegram service %v", err)
 	}
 
-	kwaaka3pl, err := kwaaka_3pl.NewKwaaka3plService(sqsCli, opts.Kwaaka3plQueue, orderRepo, storeFactory, opts.Kwaaka3pl.Kwaaka3plBaseUrl, opts.Kwaaka3pl.Kwaaka3plAuthToken, logger, telegramService, menuCli, deliveryRepo)
+	kwaaka3pl, err := kwaaka_3pl.NewKwaaka3plService(sqsCli, opts.Kwaaka3plQueue, orderRepo, storeFactory, opts.Kwaaka3pl.Kwaaka3plBaseUrl, opts.Kwaaka3pl.Kwaaka3plAuthToken, logger, telegramService, menuCli, deliveryRepo, nil, nil)
 	if err != nil {
 		return nil, fmt.Errorf("cannot initialize kwaaka 3pl client: %v", err)
 	}
@@ -235,6 +235,11 @@ func CreateServices(db *mongo.Database, globalConfig config.Configuration) (*sto
 		return nil, nil, nil, nil, nil, err
 	}
 
+	sandboxOrderRepository, err := pos.NewMongoSandboxOrderRepository(db)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
 	sqsCli := notifyClient.NewSQS(sqs.NewFromConfig(globalConfig.AwsConfig))
 
 	posFactory, err := pos.NewFactory(
@@ -244,6 +249,7 @@ func CreateServices(db *mongo.Database, globalConfig config.Configuration) (*sto
 		globalConfig.JowiConfiguration.BaseURL, globalConfig.JowiConfiguration.ApiKey, globalConfig.JowiConfiguration.ApiSecret,
 		globalConfig.RKeeperBaseURL, globalConfig.RKeeperApiKey, globalConfig.BurgerKingConfiguration.BaseURL, bkOfferRepository,
 		globalConfig.RKeeper7XMLConfiguration.LicenseBaseURL, globalConfig.SyrveConfiguration.BaseURL, globalConfig.YarosConfiguration.BaseURL, globalConfig.YarosConfiguration.InfoSystem, globalConfig.TillypadConfiguration.BaseUrl, globalConfig.Ytimes.BaseUrl, globalConfig.Ytimes.Token, globalConfig.PosistConfiguration.BaseUrl,
+		sandboxOrderRepository,
 	)
 	if err != nil {
 		return nil, nil, nil, nil, nil, err
@@ -68,6 +68,37 @@ func (c *Client) GetOrder(ctx context.Context, id string) (models2.CreateOrderRe
 	return response, nil
 }
 
+func (c *Client) UpdateOrder(ctx context.Context, req models2.UpdateOrderRequest) (models2.CreateOrderResponse, error) {
+	path := "/api/incomingOrders.updateIncomingOrder"
+
+	var (
+		response    models2.CreateOrderResponse
+		errResponse models2.ErrorResponse
+	)
+
+	resp, err := c.cli.R().
+		SetContext(ctx).
+		SetError(&errResponse).
+		SetResult(&response).
+		SetBody(&req).
+		Post(path)
+	if err != nil {
+		return models2.CreateOrderResponse{}, fmt.Errorf("%v + %v", err, response)
+	}
+
+	if resp.IsError() {
+		return models2.CreateOrderResponse{}, errResponse
+	}
+
+	if response.Message != "" {
+		return models2.CreateOrderResponse{}, fmt.Errorf("update order error: %s, status: %d", response.Message, response.Code)
+	}
+
+	log.Info().Msgf("poster UpdateOrder response: %+v", response)
+
+	return response, nil
+}
+
 func (c *Client) GetOrders(ctx context.Context, req models2.GetOrdersRequest) (models2.GetOrdersResponse, error) {
 	path := "/api/incomingOrders.getIncomingOrders"
 	var (
@@ -28,6 +28,11 @@ type CreateOrderProductRequest struct {
 	Price         int                              `json:"price"`
 }
 
+type UpdateOrderRequest struct {
+	IncomingOrderID int                         `json:"incoming_order_id"` // required
+	Products        []CreateOrderProductRequest `json:"products"`          // новый состав заказа целиком
+}
+
 type CreateOrderAddressRequest struct {
 	Address1  string `json:"address1"`
 	Address2  string `json:"address2"`
@@ -18,6 +18,7 @@ type Poster interface {
 	GetSpots(ctx context.Context) (models2.GetSpotsResponse, error)
 	CreateOrder(ctx context.Context, req models2.CreateOrderRequest) (models2.CreateOrderResponse, error)
 	GetOrder(ctx context.Context, id string) (models2.CreateOrderResponse, error)
+	UpdateOrder(ctx context.Context, req models2.UpdateOrderRequest) (models2.CreateOrderResponse, error)
 	GetOrders(ctx context.Context, req models2.GetOrdersRequest) (models2.GetOrdersResponse, error)
 	GetStopList(ctx context.Context) (models2.GetStopListResponse, error)
 	GetIngredients(ctx context.Context) (models2.GetIngridientsResponse, error)
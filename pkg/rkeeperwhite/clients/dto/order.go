@@ -78,6 +78,17 @@ type DeliveryAddress struct {
 	Coordinates Coordinate `json:"coordinates,omitempty"`
 }
 
+type UpdateOrderRequest struct {
+	TaskType string            `json:"taskType"`
+	Params   UpdateOrderParams `json:"params"`
+}
+
+type UpdateOrderParams struct {
+	Async     Sync                 `json:"async"`
+	OrderGuid string               `json:"orderGuid"`
+	Products  []CreateOrderProduct `json:"products"`
+}
+
 type CreateOrderProduct struct {
 	Id          string                  `json:"id"`
 	Name        string                  `json:"name,omitempty"`
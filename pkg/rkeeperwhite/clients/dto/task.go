@@ -8,6 +8,7 @@ const (
 	Task        TaskType = "GetTaskResponse"
 	CancelOrder TaskType = "CancelOrder"
 	PayOrder    TaskType = "PayOrder"
+	UpdateOrder TaskType = "UpdateOrder"
 )
 
 func (t TaskType) String() string {
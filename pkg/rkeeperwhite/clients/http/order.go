@@ -94,6 +94,46 @@ func (cli Client) CreateOrder(ctx context.Context, objectID int, order dto2.Orde
 	return response, nil
 }
 
+func (cli Client) UpdateOrder(ctx context.Context, objectID int, orderGUID string, products []dto2.CreateOrderProduct) (dto2.SyncResponse, error) {
+	path := "/api/v2/aggregators/Create"
+
+	var (
+		response dto2.SyncResponse
+		body     = dto2.UpdateOrderRequest{
+			TaskType: dto2.UpdateOrder.String(),
+			Params: dto2.UpdateOrderParams{
+				Async: dto2.Sync{
+					ObjectID: objectID,
+					Timeout:  120,
+				},
+				OrderGuid: orderGUID,
+				Products:  products,
+			},
+		}
+	)
+
+	utils.Beautify("rkeeper update order request body", body)
+
+	resp, err := cli.restyClient.R().
+		SetContext(ctx).
+		EnableTrace().
+		SetBody(&body).
+		SetResult(&response).
+		Post(path)
+
+	if err != nil {
+		return dto2.SyncResponse{}, err
+	}
+
+	if resp.IsError() {
+		return dto2.SyncResponse{}, errors.New(resp.Status() + " " + string(resp.Body()))
+	}
+
+	utils.Beautify("rkeeper update order response body", response)
+
+	return response, nil
+}
+
 func (cl Client) CancelOrder(ctx context.Context, objectID int, orderGUID string) (dto2.SyncResponse, error) {
 	path := "/api/v2/aggregators/Create"
 
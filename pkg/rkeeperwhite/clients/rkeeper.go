@@ -19,6 +19,7 @@ type RKeeper interface {
 	GetOrderTask(ctx context.Context, taskGUID string) (dto2.GetOrderTaskResponse, error)
 	GetOrder(ctx context.Context, orderGUID string, objectID int) (dto2.SyncResponse, error)
 	CreateOrder(ctx context.Context, objectID int, order dto2.Order) (dto2.SyncResponse, error)
+	UpdateOrder(ctx context.Context, objectID int, orderGUID string, products []dto2.CreateOrderProduct) (dto2.SyncResponse, error)
 
 	CancelOrder(ctx context.Context, objectID int, orderGUID string) (dto2.SyncResponse, error)
 	CancelOrderTask(ctx context.Context, taskGUID string) (dto2.CancelOrderResponse, error)
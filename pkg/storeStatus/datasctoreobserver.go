@@ -2,6 +2,7 @@ package storeStatus
 
 import (
 	"context"
+	orderModels "github.com/kwaaka-team/orders-core/core/models"
 	"github.com/kwaaka-team/orders-core/core/storecore/models"
 	"github.com/kwaaka-team/orders-core/service/store/repository/storeclosedtime"
 	"time"
@@ -25,7 +26,7 @@ func (s DatastoreObserver) Notify(ctx context.Context, restaurant models.Store,
 	return nil
 }
 
-func (s DatastoreObserver) NotifyStatusReport(ctx context.Context, restaurant models.Store, durations []models.OpenTimeDuration) error {
+func (s DatastoreObserver) NotifyStatusReport(ctx context.Context, restaurant models.Store, durations []models.OpenTimeDuration, breaches []orderModels.SLABreach) error {
 	return nil
 }
 
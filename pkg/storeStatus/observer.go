@@ -2,13 +2,14 @@ package storeStatus
 
 import (
 	"context"
+	orderModels "github.com/kwaaka-team/orders-core/core/models"
 	"github.com/kwaaka-team/orders-core/core/storecore/models"
 	"github.com/rs/zerolog/log"
 )
 
 type Observer interface {
 	Notify(ctx context.Context, restaurant models.Store, externalStoreID, deliveryService string, storeIsOpened bool) error
-	NotifyStatusReport(ctx context.Context, restaurant models.Store, durations []models.OpenTimeDuration) error
+	NotifyStatusReport(ctx context.Context, restaurant models.Store, durations []models.OpenTimeDuration, breaches []orderModels.SLABreach) error
 	NotifyStatusChange(ctx context.Context, status string, phone string) error
 }
 
@@ -29,9 +30,9 @@ func (s *Subject) NotifyObservers(ctx context.Context, restaurant models.Store,
 	return nil
 }
 
-func (s *Subject) NotifyStatusReportObservers(ctx context.Context, restaurant models.Store, durations []models.OpenTimeDuration) error {
+func (s *Subject) NotifyStatusReportObservers(ctx context.Context, restaurant models.Store, durations []models.OpenTimeDuration, breaches []orderModels.SLABreach) error {
 	for _, observer := range s.observers {
-		if err := observer.NotifyStatusReport(ctx, restaurant, durations); err != nil {
+		if err := observer.NotifyStatusReport(ctx, restaurant, durations, breaches); err != nil {
 			log.Info().Msgf("error was occured during sending message notification service: %v", durations)
 		}
 	}
@@ -5,6 +5,7 @@ import (
 	"github.com/kwaaka-team/orders-core/core/models"
 	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
 	"github.com/kwaaka-team/orders-core/service/aggregator"
+	"github.com/kwaaka-team/orders-core/service/sla"
 	"github.com/kwaaka-team/orders-core/service/store"
 	"github.com/kwaaka-team/orders-core/service/store/repository/storeclosedtime"
 	storeGroupServicePkg "github.com/kwaaka-team/orders-core/service/storegroup"
@@ -14,12 +15,15 @@ import (
 	"time"
 )
 
+const slaReportBreachesLimit = 3
+
 type StoreStatusService struct {
 	aggFactory        aggregator.Factory
 	storeService      store.Service
 	datastoreClient   storeclosedtime.Repository
 	storeGroupService storeGroupServicePkg.Service
 	subject           *Subject
+	slaService        sla.Service
 	StoreSchedule     *StoreSchedule
 }
 
@@ -29,17 +33,37 @@ type Status interface {
 	ReportStatuses(ctx context.Context) error
 }
 
+// NewStoreStatus - сервис статусов без нарушений SLA в отчете, для отчета с SLA используется NewStoreStatusReport
 func NewStoreStatus(aggFactory aggregator.Factory, storeService store.Service, subject *Subject, datastoreClient storeclosedtime.Repository, storeGroupService storeGroupServicePkg.Service) (Status, error) {
+	return newStoreStatusService(aggFactory, storeService, subject, datastoreClient, storeGroupService)
+}
+
+// NewStoreStatusReport - сервис статусов, который дополняет отчет худшими нарушениями SLA
+func NewStoreStatusReport(aggFactory aggregator.Factory, storeService store.Service, subject *Subject, datastoreClient storeclosedtime.Repository, storeGroupService storeGroupServicePkg.Service, slaService sla.Service) (Status, error) {
+	if slaService == nil {
+		return nil, errors.New("sla service is empty")
+	}
+
+	ss, err := newStoreStatusService(aggFactory, storeService, subject, datastoreClient, storeGroupService)
+	if err != nil {
+		return nil, err
+	}
+	ss.slaService = slaService
+
+	return ss, nil
+}
+
+func newStoreStatusService(aggFactory aggregator.Factory, storeService store.Service, subject *Subject, datastoreClient storeclosedtime.Repository, storeGroupService storeGroupServicePkg.Service) (StoreStatusService, error) {
 	if aggFactory == nil {
-		return nil, errors.New("aggregator factory is empty")
+		return StoreStatusService{}, errors.New("aggregator factory is empty")
 	}
 
 	if storeService == nil {
-		return nil, errors.New("store service service is empty")
+		return StoreStatusService{}, errors.New("store service service is empty")
 	}
 
 	if subject == nil {
-		return nil, errors.New("store service service is empty")
+		return StoreStatusService{}, errors.New("store service service is empty")
 	}
 
 	return StoreStatusService{
@@ -81,10 +105,16 @@ func (ss StoreStatusService) ReportStatuses(ctx context.Context) error {
 
 	aggregators := []string{models.GLOVO.String(), models.WOLT.String()}
 
+	reported := make([]storeModels.Store, 0, len(restaurants))
 	for _, restaurant := range restaurants {
-		if restaurant.Telegram.StoreStatusChatId == "" {
-			continue
+		if restaurant.Telegram.StoreStatusChatId != "" {
+			reported = append(reported, restaurant)
 		}
+	}
+
+	breaches := ss.worstSLABreaches(ctx, reported)
+
+	for _, restaurant := range reported {
 
 		log.Info().Msgf("Get open close status for %s store", restaurant.Name)
 		var durations []storeModels.OpenTimeDuration
@@ -146,7 +176,7 @@ func (ss StoreStatusService) ReportStatuses(ctx context.Context) error {
 			})
 		}
 
-		if err := ss.subject.NotifyStatusReportObservers(ctx, restaurant, durations); err != nil {
+		if err := ss.subject.NotifyStatusReportObservers(ctx, restaurant, durations, breaches[restaurant.ID]); err != nil {
 			log.Err(err).Msgf("failed to notify status report for %s restaurant", restaurant.Name)
 			continue
 		}
@@ -155,6 +185,22 @@ func (ss StoreStatusService) ReportStatuses(ctx context.Context) error {
 	return nil
 }
 
+// worstSLABreaches - худшие нарушения SLA ресторанов за последние сутки для отчета
+func (ss StoreStatusService) worstSLABreaches(ctx context.Context, restaurants []storeModels.Store) map[string][]models.SLABreach {
+	if ss.slaService == nil || len(restaurants) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	breaches, err := ss.slaService.WorstBreaches(ctx, restaurants, now.Add(-24*time.Hour), now, slaReportBreachesLimit)
+	if err != nil {
+		log.Err(err).Msg("failed to get sla breaches for status report")
+		return nil
+	}
+
+	return breaches
+}
+
 func (ss StoreStatusService) UpdateStoresSchedule(ctx context.Context, deliveryService string) error {
 	restaurants, err := ss.storeService.FindStoresByDeliveryService(ctx, deliveryService)
 	if err != nil {
@@ -22,8 +22,8 @@ func (s TelegramObserver) Notify(ctx context.Context, restaurant models.Store, e
 	}
 }
 
-func (s TelegramObserver) NotifyStatusReport(ctx context.Context, restaurant models.Store, durations []models.OpenTimeDuration) error {
-	return s.TelegramClient.SendMessageToQueue(telegram.StoreStatusReport, orderModels.Order{}, restaurant, "", telegram.ConstructStoreStatusReportToNotify(restaurant, durations), "", models2.Product{})
+func (s TelegramObserver) NotifyStatusReport(ctx context.Context, restaurant models.Store, durations []models.OpenTimeDuration, breaches []orderModels.SLABreach) error {
+	return s.TelegramClient.SendMessageToQueue(telegram.StoreStatusReport, orderModels.Order{}, restaurant, "", telegram.ConstructStoreStatusReportToNotify(restaurant, durations, breaches), "", models2.Product{})
 }
 
 func (s TelegramObserver) NotifyStatusChange(ctx context.Context, status string, phone string) error {
@@ -3,6 +3,7 @@ package storeStatus
 import (
 	"context"
 	"fmt"
+	orderModels "github.com/kwaaka-team/orders-core/core/models"
 	"github.com/kwaaka-team/orders-core/core/storecore/models"
 	"github.com/kwaaka-team/orders-core/pkg/whatsapp/clients"
 	"github.com/rs/zerolog/log"
@@ -28,7 +29,7 @@ func (s WhatsAppObserver) Notify(ctx context.Context, restaurant models.Store, e
 	return nil
 }
 
-func (s WhatsAppObserver) NotifyStatusReport(ctx context.Context, restaurant models.Store, durations []models.OpenTimeDuration) error {
+func (s WhatsAppObserver) NotifyStatusReport(ctx context.Context, restaurant models.Store, durations []models.OpenTimeDuration, breaches []orderModels.SLABreach) error {
 	return nil
 }
 
@@ -75,6 +75,15 @@ func (s *express24Service) GetStoreIDFromAggregatorOrderRequest(req interface{})
 	return order.Store.Branch.ExternalId, nil
 }
 
+func (s *express24Service) GetOrderIDFromAggregatorOrderRequest(req interface{}) (string, error) {
+	order, ok := req.(expressModels.Order)
+	if !ok {
+		return "", errors.New("casting error")
+	}
+
+	return strconv.Itoa(order.Id), nil
+}
+
 func (s *express24Service) GetSystemCreateOrderRequestByAggregatorRequest(req interface{}, store storeModels.Store) (models.Order, error) {
 
 	order, ok := req.(expressModels.Order)
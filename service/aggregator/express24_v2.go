@@ -73,6 +73,15 @@ func (s *express24ServiceV2) GetStoreIDFromAggregatorOrderRequest(req interface{
 	return order.Store.Branch.ExternalId, nil
 }
 
+func (s *express24ServiceV2) GetOrderIDFromAggregatorOrderRequest(req interface{}) (string, error) {
+	order, ok := req.(expressModels.Order)
+	if !ok {
+		return "", errors.New("casting error")
+	}
+
+	return strconv.Itoa(order.Id), nil
+}
+
 func (s *express24ServiceV2) GetSystemCreateOrderRequestByAggregatorRequest(req interface{}, store storeModels.Store) (models.Order, error) {
 
 	order, ok := req.(expressModels.Order)
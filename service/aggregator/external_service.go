@@ -53,6 +53,15 @@ func (s *externalService) GetStoreIDFromAggregatorOrderRequest(req interface{})
 	return order.RestaurantId, nil
 }
 
+func (s *externalService) GetOrderIDFromAggregatorOrderRequest(req interface{}) (string, error) {
+	order, ok := req.(externalApiModels.Order)
+	if !ok {
+		return "", errors.New("casting error")
+	}
+
+	return order.EatsId, nil
+}
+
 func (s *externalService) splitVirtualStoreOrder(req interface{}, store storeModels.Store) ([]interface{}, error) {
 	order, ok := req.(externalApiModels.Order)
 	if !ok {
@@ -0,0 +1,260 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	menuModels "github.com/kwaaka-team/orders-core/core/menu/models"
+	"github.com/kwaaka-team/orders-core/core/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	models3 "github.com/kwaaka-team/orders-core/core/wolt/models"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+const (
+	MethodUpdateOrderInAggregator        = "UpdateOrderInAggregator"
+	MethodUpdateStopListByProducts       = "UpdateStopListByProducts"
+	MethodUpdateStopListByProductsBulk   = "UpdateStopListByProductsBulk"
+	MethodUpdateStopListByAttributesBulk = "UpdateStopListByAttributesBulk"
+	MethodOpenStore                      = "OpenStore"
+	MethodSendOrderErrorNotification     = "SendOrderErrorNotification"
+	MethodSendStopListNotification       = "SendStopListUpdateNotification"
+)
+
+// Call is outgoing request to aggregator, captured by simulator instead of sending
+type Call struct {
+	Method            string    `json:"method"`
+	DeliveryService   string    `json:"delivery_service"`
+	AggregatorStoreID string    `json:"aggregator_store_id,omitempty"`
+	OrderID           string    `json:"order_id,omitempty"`
+	Status            string    `json:"status,omitempty"`
+	ProductIDs        []string  `json:"product_ids,omitempty"`
+	Time              time.Time `json:"time"`
+}
+
+// ExpectedCall - empty fields match any value
+type ExpectedCall struct {
+	Method          string   `json:"method"`
+	DeliveryService string   `json:"delivery_service,omitempty"`
+	OrderID         string   `json:"order_id,omitempty"`
+	Status          string   `json:"status,omitempty"`
+	ProductIDs      []string `json:"product_ids,omitempty"`
+}
+
+func (e ExpectedCall) match(call Call) bool {
+	if e.Method != call.Method {
+		return false
+	}
+	if e.DeliveryService != "" && e.DeliveryService != call.DeliveryService {
+		return false
+	}
+	if e.OrderID != "" && e.OrderID != call.OrderID {
+		return false
+	}
+	if e.Status != "" && e.Status != call.Status {
+		return false
+	}
+	if len(e.ProductIDs) != 0 && fmt.Sprint(e.ProductIDs) != fmt.Sprint(call.ProductIDs) {
+		return false
+	}
+	return true
+}
+
+// MatchSequence checks that calls of expected methods were made in expected order.
+// Calls of methods, which are not in expected, are skipped
+func MatchSequence(calls []Call, expected []ExpectedCall) error {
+	methods := make(map[string]struct{}, len(expected))
+	for _, e := range expected {
+		methods[e.Method] = struct{}{}
+	}
+
+	actual := make([]Call, 0, len(calls))
+	for _, call := range calls {
+		if _, ok := methods[call.Method]; ok {
+			actual = append(actual, call)
+		}
+	}
+
+	if len(actual) != len(expected) {
+		return errors.Errorf("expected %d calls, got %d: %+v", len(expected), len(actual), actual)
+	}
+
+	for i := range expected {
+		if !expected[i].match(actual[i]) {
+			return errors.Errorf("call %d: expected %+v, got %+v", i, expected[i], actual[i])
+		}
+	}
+
+	return nil
+}
+
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+func (r *Recorder) Record(call Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	call.Time = time.Now().UTC()
+	r.calls = append(r.calls, call)
+}
+
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	calls := make([]Call, len(r.calls))
+	copy(calls, r.calls)
+
+	return calls
+}
+
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = nil
+}
+
+// FakeFactory returns aggregators, which map requests by real services, but do not send anything to aggregators
+type FakeFactory struct {
+	factory  Factory
+	recorder *Recorder
+}
+
+func NewFakeFactory(factory Factory, recorder *Recorder) (*FakeFactory, error) {
+	if factory == nil {
+		return nil, errors.Wrap(constructorError, "aggregator factory is nil")
+	}
+	if recorder == nil {
+		return nil, errors.Wrap(constructorError, "recorder is nil")
+	}
+
+	return &FakeFactory{
+		factory:  factory,
+		recorder: recorder,
+	}, nil
+}
+
+func (f *FakeFactory) GetAggregator(aggName string, store storeModels.Store) (Aggregator, error) {
+	aggregator, err := f.factory.GetAggregator(aggName, store)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFakeAggregator(aggregator, aggName, f.recorder), nil
+}
+
+type FakeAggregator struct {
+	Aggregator
+	deliveryService string
+	recorder        *Recorder
+}
+
+func NewFakeAggregator(aggregator Aggregator, deliveryService string, recorder *Recorder) *FakeAggregator {
+	return &FakeAggregator{
+		Aggregator:      aggregator,
+		deliveryService: deliveryService,
+		recorder:        recorder,
+	}
+}
+
+func (f *FakeAggregator) UpdateOrderInAggregator(ctx context.Context, order models.Order, store storeModels.Store, aggregatorStatus string) error {
+	f.recorder.Record(Call{
+		Method:            MethodUpdateOrderInAggregator,
+		DeliveryService:   f.deliveryService,
+		AggregatorStoreID: order.StoreID,
+		OrderID:           order.OrderID,
+		Status:            aggregatorStatus,
+	})
+	return nil
+}
+
+func (f *FakeAggregator) UpdateStopListByProducts(ctx context.Context, aggregatorStoreID string, products []menuModels.Product, isAvailable bool) (string, error) {
+	f.recorder.Record(Call{
+		Method:            MethodUpdateStopListByProducts,
+		DeliveryService:   f.deliveryService,
+		AggregatorStoreID: aggregatorStoreID,
+		Status:            fmt.Sprintf("available=%t", isAvailable),
+		ProductIDs:        productIDs(products),
+	})
+	return "", nil
+}
+
+func (f *FakeAggregator) UpdateStopListByProductsBulk(ctx context.Context, aggregatorStoreID string, products []menuModels.Product, isSendRemains bool) (string, error) {
+	f.recorder.Record(Call{
+		Method:            MethodUpdateStopListByProductsBulk,
+		DeliveryService:   f.deliveryService,
+		AggregatorStoreID: aggregatorStoreID,
+		ProductIDs:        productIDs(products),
+	})
+	return "", nil
+}
+
+func (f *FakeAggregator) UpdateStopListByAttributesBulk(ctx context.Context, aggregatorStoreID string, attributes []menuModels.Attribute) (string, error) {
+	ids := make([]string, 0, len(attributes))
+	for _, attribute := range attributes {
+		ids = append(ids, attribute.ExtID)
+	}
+
+	f.recorder.Record(Call{
+		Method:            MethodUpdateStopListByAttributesBulk,
+		DeliveryService:   f.deliveryService,
+		AggregatorStoreID: aggregatorStoreID,
+		ProductIDs:        ids,
+	})
+	return "", nil
+}
+
+func (f *FakeAggregator) OpenStore(ctx context.Context, aggregatorStoreId string) error {
+	f.recorder.Record(Call{
+		Method:            MethodOpenStore,
+		DeliveryService:   f.deliveryService,
+		AggregatorStoreID: aggregatorStoreId,
+	})
+	return nil
+}
+
+func (f *FakeAggregator) GetStoreStatus(ctx context.Context, aggregatorStoreId string) (bool, error) {
+	return true, nil
+}
+
+func (f *FakeAggregator) GetStoreSchedule(ctx context.Context, aggregatorStoreId string) (storeModels.AggregatorSchedule, error) {
+	return storeModels.AggregatorSchedule{}, nil
+}
+
+func (f *FakeAggregator) GetAggregatorOrder(ctx context.Context, orderID string) (models3.Order, error) {
+	return models3.Order{}, nil
+}
+
+func (f *FakeAggregator) SendOrderErrorNotification(ctx context.Context, req interface{}) error {
+	f.recorder.Record(Call{
+		Method:          MethodSendOrderErrorNotification,
+		DeliveryService: f.deliveryService,
+	})
+	return nil
+}
+
+func (f *FakeAggregator) SendStopListUpdateNotification(ctx context.Context, aggregatorStoreID string) error {
+	f.recorder.Record(Call{
+		Method:            MethodSendStopListNotification,
+		DeliveryService:   f.deliveryService,
+		AggregatorStoreID: aggregatorStoreID,
+	})
+	return nil
+}
+
+func productIDs(products []menuModels.Product) []string {
+	ids := make([]string, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ExtID)
+	}
+	return ids
+}
@@ -0,0 +1,35 @@
+package aggregator
+
+import (
+	"context"
+	"github.com/kwaaka-team/orders-core/core/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFakeAggregatorSequence(t *testing.T) {
+	recorder := NewRecorder()
+	fake := NewFakeAggregator(nil, models.GLOVO.String(), recorder)
+
+	order := models.Order{OrderID: "1000000001", StoreID: "2000000002"}
+
+	assert.NoError(t, fake.OpenStore(context.Background(), "2000000002"))
+	assert.NoError(t, fake.UpdateOrderInAggregator(context.Background(), order, storeModels.Store{}, "ACCEPTED"))
+	assert.NoError(t, fake.UpdateOrderInAggregator(context.Background(), order, storeModels.Store{}, "READY_FOR_PICKUP"))
+
+	err := MatchSequence(recorder.Calls(), []ExpectedCall{
+		{Method: MethodUpdateOrderInAggregator, OrderID: "1000000001", Status: "ACCEPTED"},
+		{Method: MethodUpdateOrderInAggregator, Status: "READY_FOR_PICKUP"},
+	})
+	assert.NoError(t, err)
+
+	err = MatchSequence(recorder.Calls(), []ExpectedCall{
+		{Method: MethodUpdateOrderInAggregator, Status: "READY_FOR_PICKUP"},
+		{Method: MethodUpdateOrderInAggregator, Status: "ACCEPTED"},
+	})
+	assert.Error(t, err)
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Calls())
+}
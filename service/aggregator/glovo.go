@@ -446,6 +446,15 @@ func (s *glovoService) GetStoreIDFromAggregatorOrderRequest(req interface{}) (st
 	return order.StoreID, nil
 }
 
+func (s *glovoService) GetOrderIDFromAggregatorOrderRequest(req interface{}) (string, error) {
+	order, ok := req.(models2.Order)
+	if !ok {
+		return "", errors.New("casting error")
+	}
+
+	return order.OrderID, nil
+}
+
 func (s *glovoService) GetAggregatorOrder(ctx context.Context, orderID string) (models3.Order, error) {
 	return models3.Order{}, nil
 }
@@ -63,6 +63,15 @@ func (s *kwaakaAdminService) GetStoreIDFromAggregatorOrderRequest(req interface{
 	return order.RestaurantID, nil
 }
 
+func (s *kwaakaAdminService) GetOrderIDFromAggregatorOrderRequest(req interface{}) (string, error) {
+	order, ok := req.(models2.Order)
+	if !ok {
+		return "", errors.New("casting error")
+	}
+
+	return order.ID, nil
+}
+
 func (s *kwaakaAdminService) GetSystemCreateOrderRequestByAggregatorRequest(r interface{}, store storeModels.Store) (models.Order, error) {
 	req, ok := r.(models2.Order)
 	if !ok {
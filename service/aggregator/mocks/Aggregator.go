@@ -11,6 +11,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/kwaaka-team/orders-core/core/storecore/models"
+
+	woltmodels "github.com/kwaaka-team/orders-core/core/wolt/models"
 )
 
 // Aggregator is an autogenerated mock type for the Aggregator type
@@ -138,9 +140,9 @@ func (_m *Aggregator) UpdateStopListByProducts(ctx context.Context, aggregatorSt
 	return r0, r1
 }
 
-// UpdateStopListByProductsBulk provides a mock function with given fields: ctx, aggregatorStoreID, products
-func (_m *Aggregator) UpdateStopListByProductsBulk(ctx context.Context, aggregatorStoreID string, products []menumodels.Product) (string, error) {
-	ret := _m.Called(ctx, aggregatorStoreID, products)
+// UpdateStopListByProductsBulk provides a mock function with given fields: ctx, aggregatorStoreID, products, isSendRemains
+func (_m *Aggregator) UpdateStopListByProductsBulk(ctx context.Context, aggregatorStoreID string, products []menumodels.Product, isSendRemains bool) (string, error) {
+	ret := _m.Called(ctx, aggregatorStoreID, products, isSendRemains)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateStopListByProductsBulk")
@@ -148,17 +150,269 @@ func (_m *Aggregator) UpdateStopListByProductsBulk(ctx context.Context, aggregat
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, []menumodels.Product) (string, error)); ok {
-		return rf(ctx, aggregatorStoreID, products)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []menumodels.Product, bool) (string, error)); ok {
+		return rf(ctx, aggregatorStoreID, products, isSendRemains)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, []menumodels.Product) string); ok {
-		r0 = rf(ctx, aggregatorStoreID, products)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []menumodels.Product, bool) string); ok {
+		r0 = rf(ctx, aggregatorStoreID, products, isSendRemains)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, []menumodels.Product) error); ok {
-		r1 = rf(ctx, aggregatorStoreID, products)
+	if rf, ok := ret.Get(1).(func(context.Context, string, []menumodels.Product, bool) error); ok {
+		r1 = rf(ctx, aggregatorStoreID, products, isSendRemains)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAggregatorOrder provides a mock function with given fields: ctx, orderID
+func (_m *Aggregator) GetAggregatorOrder(ctx context.Context, orderID string) (woltmodels.Order, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAggregatorOrder")
+	}
+
+	var r0 woltmodels.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (woltmodels.Order, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) woltmodels.Order); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		r0 = ret.Get(0).(woltmodels.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderIDFromAggregatorOrderRequest provides a mock function with given fields: req
+func (_m *Aggregator) GetOrderIDFromAggregatorOrderRequest(req interface{}) (string, error) {
+	ret := _m.Called(req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderIDFromAggregatorOrderRequest")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(interface{}) (string, error)); ok {
+		return rf(req)
+	}
+	if rf, ok := ret.Get(0).(func(interface{}) string); ok {
+		r0 = rf(req)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(interface{}) error); ok {
+		r1 = rf(req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStoreIDFromAggregatorOrderRequest provides a mock function with given fields: req
+func (_m *Aggregator) GetStoreIDFromAggregatorOrderRequest(req interface{}) (string, error) {
+	ret := _m.Called(req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStoreIDFromAggregatorOrderRequest")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(interface{}) (string, error)); ok {
+		return rf(req)
+	}
+	if rf, ok := ret.Get(0).(func(interface{}) string); ok {
+		r0 = rf(req)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(interface{}) error); ok {
+		r1 = rf(req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStoreSchedule provides a mock function with given fields: ctx, aggregatorStoreId
+func (_m *Aggregator) GetStoreSchedule(ctx context.Context, aggregatorStoreId string) (models.AggregatorSchedule, error) {
+	ret := _m.Called(ctx, aggregatorStoreId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStoreSchedule")
+	}
+
+	var r0 models.AggregatorSchedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.AggregatorSchedule, error)); ok {
+		return rf(ctx, aggregatorStoreId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.AggregatorSchedule); ok {
+		r0 = rf(ctx, aggregatorStoreId)
+	} else {
+		r0 = ret.Get(0).(models.AggregatorSchedule)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, aggregatorStoreId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStoreStatus provides a mock function with given fields: ctx, aggregatorStoreId
+func (_m *Aggregator) GetStoreStatus(ctx context.Context, aggregatorStoreId string) (bool, error) {
+	ret := _m.Called(ctx, aggregatorStoreId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStoreStatus")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, aggregatorStoreId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, aggregatorStoreId)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, aggregatorStoreId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsMarketPlace provides a mock function with given fields: restaurantSelfDelivery, store
+func (_m *Aggregator) IsMarketPlace(restaurantSelfDelivery bool, store models.Store) (bool, error) {
+	ret := _m.Called(restaurantSelfDelivery, store)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsMarketPlace")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(bool, models.Store) (bool, error)); ok {
+		return rf(restaurantSelfDelivery, store)
+	}
+	if rf, ok := ret.Get(0).(func(bool, models.Store) bool); ok {
+		r0 = rf(restaurantSelfDelivery, store)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(bool, models.Store) error); ok {
+		r1 = rf(restaurantSelfDelivery, store)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OpenStore provides a mock function with given fields: ctx, aggregatorStoreId
+func (_m *Aggregator) OpenStore(ctx context.Context, aggregatorStoreId string) error {
+	ret := _m.Called(ctx, aggregatorStoreId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenStore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, aggregatorStoreId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendOrderErrorNotification provides a mock function with given fields: ctx, req
+func (_m *Aggregator) SendOrderErrorNotification(ctx context.Context, req interface{}) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendOrderErrorNotification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendStopListUpdateNotification provides a mock function with given fields: ctx, aggregatorStoreID
+func (_m *Aggregator) SendStopListUpdateNotification(ctx context.Context, aggregatorStoreID string) error {
+	ret := _m.Called(ctx, aggregatorStoreID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendStopListUpdateNotification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, aggregatorStoreID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SplitVirtualStoreOrder provides a mock function with given fields: req, store
+func (_m *Aggregator) SplitVirtualStoreOrder(req interface{}, store models.Store) ([]interface{}, error) {
+	ret := _m.Called(req, store)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SplitVirtualStoreOrder")
+	}
+
+	var r0 []interface{}
+	var r1 error
+	if rf, ok := ret.Get(0).(func(interface{}, models.Store) ([]interface{}, error)); ok {
+		return rf(req, store)
+	}
+	if rf, ok := ret.Get(0).(func(interface{}, models.Store) []interface{}); ok {
+		r0 = rf(req, store)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(interface{}, models.Store) error); ok {
+		r1 = rf(req, store)
 	} else {
 		r1 = ret.Error(1)
 	}
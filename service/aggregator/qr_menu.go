@@ -52,6 +52,15 @@ func (s *qrMenuService) GetStoreIDFromAggregatorOrderRequest(req interface{}) (s
 	return order.RestaurantID, nil
 }
 
+func (s *qrMenuService) GetOrderIDFromAggregatorOrderRequest(req interface{}) (string, error) {
+	order, ok := req.(models2.Order)
+	if !ok {
+		return "", errors.New("casting error")
+	}
+
+	return order.ID, nil
+}
+
 func (s *qrMenuService) SplitVirtualStoreOrder(req interface{}, store storeModels.Store) ([]interface{}, error) {
 	order, ok := req.(models2.Order)
 	if !ok {
@@ -28,11 +28,17 @@ type Aggregator interface {
 	IsMarketPlace(restaurantSelfDelivery bool, store storeModels.Store) (bool, error)
 	SplitVirtualStoreOrder(req interface{}, store storeModels.Store) ([]interface{}, error)
 	GetStoreIDFromAggregatorOrderRequest(req interface{}) (string, error)
+	GetOrderIDFromAggregatorOrderRequest(req interface{}) (string, error)
 	GetAggregatorOrder(ctx context.Context, orderID string) (models3.Order, error)
 	SendOrderErrorNotification(ctx context.Context, req interface{}) error
 	SendStopListUpdateNotification(ctx context.Context, aggregatorStoreID string) error
 }
 
+// ProductsAvailabilityReader - агрегаторы, API которых отдает текущую доступность продуктов
+type ProductsAvailabilityReader interface {
+	GetProductsAvailability(ctx context.Context, aggregatorStoreID string) (map[string]bool, error)
+}
+
 type Factory interface {
 	GetAggregator(aggName string, store storeModels.Store) (Aggregator, error)
 }
@@ -441,6 +441,15 @@ func (s starterAppService) GetStoreIDFromAggregatorOrderRequest(req interface{})
 	return order.ShopId, nil
 }
 
+func (s starterAppService) GetOrderIDFromAggregatorOrderRequest(req interface{}) (string, error) {
+	order, ok := req.(starterAppModels.Order)
+	if !ok {
+		return "", errors.New("casting error")
+	}
+
+	return order.GlobalId, nil
+}
+
 func (s starterAppService) GetAggregatorOrder(ctx context.Context, orderID string) (models3.Order, error) {
 	return models3.Order{}, errors.New("method not implemented")
 }
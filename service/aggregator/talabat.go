@@ -132,6 +132,15 @@ func (s *talabatService) GetStoreIDFromAggregatorOrderRequest(req interface{}) (
 	return "", nil
 }
 
+func (s *talabatService) GetOrderIDFromAggregatorOrderRequest(req interface{}) (string, error) {
+	order, ok := req.(models2.CreateOrderRequest)
+	if !ok {
+		return "", errors.New("casting error")
+	}
+
+	return order.Token, nil
+}
+
 func (s *talabatService) GetSystemCreateOrderRequestByAggregatorRequest(r interface{}, store storeModels.Store) (models.Order, error) {
 	req, ok := r.(models2.CreateOrderRequest)
 	if !ok {
@@ -222,6 +222,18 @@ func (s *woltService) GetStoreIDFromAggregatorOrderRequest(req interface{}) (str
 	return order.Venue.ID, nil
 }
 
+// GetOrderIDFromAggregatorOrderRequest - id заказа из дочернего заказа виртуального ресторана или из вебхука wolt
+func (s *woltService) GetOrderIDFromAggregatorOrderRequest(req interface{}) (string, error) {
+	switch order := req.(type) {
+	case models2.Order:
+		return order.ID, nil
+	case models2.OrderNotification:
+		return order.Body.Id, nil
+	default:
+		return "", errors.New("casting error")
+	}
+}
+
 func (s *woltService) GetSystemCreateOrderRequestByAggregatorRequest(r interface{}, store storeModels.Store) (models.Order, error) {
 	virtualClildOrder, ok := r.(models2.Order)
 	if ok {
@@ -806,6 +818,26 @@ func (s *woltService) UpdateStopListByProductsBulk(ctx context.Context, aggregat
 	return "", nil
 }
 
+// GetProductsAvailability returns availability of products in venue menu by ext id
+func (s *woltService) GetProductsAvailability(ctx context.Context, aggregatorStoreID string) (map[string]bool, error) {
+	woltMenu, err := s.cli.GetMenu(ctx, aggregatorStoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	availability := make(map[string]bool)
+	for _, category := range woltMenu.Categories {
+		for _, item := range category.Items {
+			if item.ExternalData == "" {
+				continue
+			}
+			availability[item.ExternalData] = item.Enabled
+		}
+	}
+
+	return availability, nil
+}
+
 func (s *woltService) GetAggregatorOrder(ctx context.Context, orderID string) (models2.Order, error) {
 	log.Info().Msgf("start to get aggregator order of order_id: %s", orderID)
 	order, err := s.cli.GetOrder(ctx, orderID)
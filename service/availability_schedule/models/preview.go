@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	menuModels "github.com/kwaaka-team/orders-core/core/menu/models"
+)
+
+// Preview - доступность продуктов и атрибутов, попадающих под расписания ресторана, на момент at
+type Preview struct {
+	StoreID    string             `json:"store_id"`
+	At         time.Time          `json:"at"`
+	LocalTime  string             `json:"local_time"`
+	Products   []ProductPreview   `json:"products"`
+	Attributes []AttributePreview `json:"attributes"`
+}
+
+type ProductPreview struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// ScheduleAvailable - доступность только по расписаниям, Available - с учетом остальных причин отключения
+	ScheduleAvailable bool                       `json:"schedule_available"`
+	Available         bool                       `json:"available"`
+	DisableReasons    []menuModels.DisableReason `json:"disable_reasons"`
+	ScheduleIDs       []string                   `json:"schedule_ids"`
+}
+
+type AttributePreview struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Available   bool     `json:"available"`
+	ScheduleIDs []string `json:"schedule_ids"`
+}
+
+type ApplyResult struct {
+	StoreID            string `json:"store_id"`
+	EnabledProducts    int    `json:"enabled_products"`
+	DisabledProducts   int    `json:"disabled_products"`
+	EnabledAttributes  int    `json:"enabled_attributes"`
+	DisabledAttributes int    `json:"disabled_attributes"`
+	Error              string `json:"error,omitempty"`
+}
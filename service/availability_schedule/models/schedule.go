@@ -0,0 +1,126 @@
+package models
+
+import (
+	"time"
+
+	menuModels "github.com/kwaaka-team/orders-core/core/menu/models"
+	"github.com/pkg/errors"
+)
+
+type EntityType string
+
+const (
+	EntityTypeProduct        EntityType = "product"
+	EntityTypeSection        EntityType = "section"
+	EntityTypeAttributeGroup EntityType = "attribute_group"
+)
+
+var (
+	ErrInvalidEntityType = errors.New("invalid entity type")
+	ErrEmptyEntityIDs    = errors.New("entity ids are empty")
+	ErrEmptyWindows      = errors.New("availability windows are empty")
+	ErrInvalidWindow     = errors.New("invalid availability window")
+)
+
+// Schedule - повторяющееся правило доступности: продукты, категории или группы атрибутов pos меню ресторана
+// доступны только внутри окон, время окон - в таймзоне ресторана
+type Schedule struct {
+	ID         string     `bson:"_id,omitempty" json:"id"`
+	StoreID    string     `bson:"store_id" json:"store_id"`
+	Name       string     `bson:"name" json:"name"`
+	EntityType EntityType `bson:"entity_type" json:"entity_type"`
+	EntityIDs  []string   `bson:"entity_ids" json:"entity_ids"`
+	Windows    []Window   `bson:"windows" json:"windows"`
+	IsActive   bool       `bson:"is_active" json:"is_active"`
+	// AppliedAvailable - последнее примененное состояние, атрибуты обновляются только при его смене
+	AppliedAvailable *bool     `bson:"applied_available,omitempty" json:"applied_available,omitempty"`
+	CreatedAt        time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Window - окно доступности, если end_time меньше start_time, окно заканчивается на следующий день
+type Window struct {
+	// Weekdays - дни начала окна, 0 - воскресенье, пустой список - каждый день
+	Weekdays  []int                    `bson:"weekdays" json:"weekdays"`
+	StartTime menuModels.TimeScheduler `bson:"start_time" json:"start_time"`
+	EndTime   menuModels.TimeScheduler `bson:"end_time" json:"end_time"`
+}
+
+func (s Schedule) Validate() error {
+	switch s.EntityType {
+	case EntityTypeProduct, EntityTypeSection, EntityTypeAttributeGroup:
+	default:
+		return errors.Wrap(ErrInvalidEntityType, string(s.EntityType))
+	}
+
+	if len(s.EntityIDs) == 0 {
+		return ErrEmptyEntityIDs
+	}
+	if len(s.Windows) == 0 {
+		return ErrEmptyWindows
+	}
+
+	for _, w := range s.Windows {
+		if err := w.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsAvailableAt - t должен быть во времени ресторана
+func (s Schedule) IsAvailableAt(t time.Time) bool {
+	for _, w := range s.Windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w Window) Validate() error {
+	for _, t := range []menuModels.TimeScheduler{w.StartTime, w.EndTime} {
+		if t.Hour < 0 || t.Hour > 23 || t.Minute < 0 || t.Minute > 59 {
+			return errors.Wrapf(ErrInvalidWindow, "time %02d:%02d", t.Hour, t.Minute)
+		}
+	}
+	if w.StartTime == w.EndTime {
+		return errors.Wrap(ErrInvalidWindow, "start time equals end time")
+	}
+	for _, day := range w.Weekdays {
+		if day < 0 || day > 6 {
+			return errors.Wrapf(ErrInvalidWindow, "weekday %d", day)
+		}
+	}
+	return nil
+}
+
+// Contains - t должен быть во времени ресторана
+func (w Window) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	start := w.StartTime.Hour*60 + w.StartTime.Minute
+	end := w.EndTime.Hour*60 + w.EndTime.Minute
+
+	if start < end {
+		return w.hasWeekday(t.Weekday()) && minute >= start && minute < end
+	}
+
+	// окно через полночь: вечер дня начала или утро следующего дня
+	if minute >= start {
+		return w.hasWeekday(t.Weekday())
+	}
+	return minute < end && w.hasWeekday((t.Weekday()+6)%7)
+}
+
+func (w Window) hasWeekday(day time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, d := range w.Weekdays {
+		if time.Weekday(d) == day {
+			return true
+		}
+	}
+	return false
+}
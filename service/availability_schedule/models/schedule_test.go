@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	menuModels "github.com/kwaaka-team/orders-core/core/menu/models"
+)
+
+func TestSchedule_IsAvailableAt(t *testing.T) {
+	lunch := Schedule{Windows: []Window{{
+		Weekdays:  []int{1, 2, 3, 4, 5},
+		StartTime: menuModels.TimeScheduler{Hour: 12},
+		EndTime:   menuModels.TimeScheduler{Hour: 15},
+	}}}
+	night := Schedule{Windows: []Window{{
+		Weekdays:  []int{5},
+		StartTime: menuModels.TimeScheduler{Hour: 22, Minute: 30},
+		EndTime:   menuModels.TimeScheduler{Hour: 2},
+	}}}
+
+	// 2026-10-16 - пятница
+	tests := []struct {
+		name     string
+		schedule Schedule
+		at       time.Time
+		want     bool
+	}{
+		{"lunch starts", lunch, time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC), true},
+		{"lunch ends", lunch, time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC), false},
+		{"lunch is not on saturday", lunch, time.Date(2026, 10, 17, 13, 0, 0, 0, time.UTC), false},
+		{"night on friday evening", night, time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC), true},
+		{"night continues on saturday", night, time.Date(2026, 10, 17, 1, 59, 0, 0, time.UTC), true},
+		{"night ends on saturday", night, time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC), false},
+		{"night is not on friday morning", night, time.Date(2026, 10, 16, 1, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.IsAvailableAt(tt.at); got != tt.want {
+				t.Errorf("IsAvailableAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
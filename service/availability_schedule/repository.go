@@ -0,0 +1,174 @@
+package availability_schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	"github.com/kwaaka-team/orders-core/service/availability_schedule/models"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const collectionName = "availability_schedules"
+
+type Repository interface {
+	Create(ctx context.Context, schedule models.Schedule) (string, error)
+	Update(ctx context.Context, schedule models.Schedule) error
+	Delete(ctx context.Context, id string) error
+	GetByID(ctx context.Context, id string) (models.Schedule, error)
+	FindByStoreID(ctx context.Context, storeID string) ([]models.Schedule, error)
+	FindActive(ctx context.Context) ([]models.Schedule, error)
+	SetAppliedAvailable(ctx context.Context, id string, isAvailable bool) error
+}
+
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(db *mongo.Database) (*MongoRepository, error) {
+	return &MongoRepository{collection: db.Collection(collectionName)}, nil
+}
+
+func (m *MongoRepository) Create(ctx context.Context, schedule models.Schedule) (string, error) {
+	schedule.ID = ""
+	schedule.AppliedAvailable = nil
+	schedule.CreatedAt = time.Now().UTC()
+	schedule.UpdatedAt = schedule.CreatedAt
+
+	res, err := m.collection.InsertOne(ctx, schedule)
+	if err != nil {
+		return "", errorSwitch(err)
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", drivers.ErrInvalid
+	}
+
+	return oid.Hex(), nil
+}
+
+func (m *MongoRepository) Update(ctx context.Context, schedule models.Schedule) error {
+	oid, err := primitive.ObjectIDFromHex(schedule.ID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "name", Value: schedule.Name},
+			{Key: "entity_type", Value: schedule.EntityType},
+			{Key: "entity_ids", Value: schedule.EntityIDs},
+			{Key: "windows", Value: schedule.Windows},
+			{Key: "is_active", Value: schedule.IsActive},
+			{Key: "updated_at", Value: time.Now().UTC()},
+		}},
+		// правило изменилось, атрибуты нужно применить заново
+		{Key: "$unset", Value: bson.D{
+			{Key: "applied_available", Value: ""},
+		}},
+	}
+
+	res, err := m.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: oid}}, update)
+	if err != nil {
+		return errorSwitch(err)
+	}
+	if res.MatchedCount == 0 {
+		return drivers.ErrNotFound
+	}
+
+	return nil
+}
+
+func (m *MongoRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	res, err := m.collection.DeleteOne(ctx, bson.D{{Key: "_id", Value: oid}})
+	if err != nil {
+		return errorSwitch(err)
+	}
+	if res.DeletedCount == 0 {
+		return drivers.ErrNotFound
+	}
+
+	return nil
+}
+
+func (m *MongoRepository) GetByID(ctx context.Context, id string) (models.Schedule, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.Schedule{}, err
+	}
+
+	var schedule models.Schedule
+	if err = m.collection.FindOne(ctx, bson.D{{Key: "_id", Value: oid}}).Decode(&schedule); err != nil {
+		return models.Schedule{}, errorSwitch(err)
+	}
+
+	return schedule, nil
+}
+
+func (m *MongoRepository) FindByStoreID(ctx context.Context, storeID string) ([]models.Schedule, error) {
+	return m.find(ctx, bson.D{{Key: "store_id", Value: storeID}})
+}
+
+func (m *MongoRepository) FindActive(ctx context.Context) ([]models.Schedule, error) {
+	return m.find(ctx, bson.D{{Key: "is_active", Value: true}})
+}
+
+func (m *MongoRepository) SetAppliedAvailable(ctx context.Context, id string, isAvailable bool) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "applied_available", Value: isAvailable},
+		}},
+	}
+
+	if _, err = m.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: oid}}, update); err != nil {
+		return errorSwitch(err)
+	}
+
+	return nil
+}
+
+func (m *MongoRepository) find(ctx context.Context, filter bson.D) ([]models.Schedule, error) {
+	cur, err := m.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, errorSwitch(err)
+	}
+	defer cur.Close(ctx)
+
+	schedules := make([]models.Schedule, 0, cur.RemainingBatchLength())
+	for cur.Next(ctx) {
+		var schedule models.Schedule
+		if err = cur.Decode(&schedule); err != nil {
+			log.Err(err).Msgf("error decoding into availability schedule model")
+			continue
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
+
+func errorSwitch(err error) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return drivers.ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return drivers.ErrAlreadyExist
+	default:
+		return err
+	}
+}
@@ -0,0 +1,484 @@
+package availability_schedule
+
+import (
+	"context"
+	"time"
+
+	menuModels "github.com/kwaaka-team/orders-core/core/menu/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/availability_schedule/models"
+	menuServicePkg "github.com/kwaaka-team/orders-core/service/menu"
+	"github.com/kwaaka-team/orders-core/service/stoplist"
+	storeServicePkg "github.com/kwaaka-team/orders-core/service/store"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type Service interface {
+	Create(ctx context.Context, schedule models.Schedule) (string, error)
+	Update(ctx context.Context, schedule models.Schedule) error
+	Delete(ctx context.Context, id string) error
+	GetByID(ctx context.Context, id string) (models.Schedule, error)
+	GetByStoreID(ctx context.Context, storeID string) ([]models.Schedule, error)
+	Apply(ctx context.Context, now time.Time) ([]models.ApplyResult, error)
+	ApplyByStoreID(ctx context.Context, storeID string, now time.Time) (models.ApplyResult, error)
+	Preview(ctx context.Context, storeID string, at time.Time) (models.Preview, error)
+}
+
+type ServiceImpl struct {
+	repo            Repository
+	storeService    storeServicePkg.Service
+	menuService     *menuServicePkg.Service
+	stopListService stoplist.Service
+}
+
+func NewService(repo Repository, storeService storeServicePkg.Service, menuService *menuServicePkg.Service, stopListService stoplist.Service) (*ServiceImpl, error) {
+	if repo == nil {
+		return nil, errors.New("availability schedule repository is nil")
+	}
+	if storeService == nil {
+		return nil, errors.New("store service is nil")
+	}
+	if menuService == nil {
+		return nil, errors.New("menu service is nil")
+	}
+	if stopListService == nil {
+		return nil, errors.New("stoplist service is nil")
+	}
+
+	return &ServiceImpl{
+		repo:            repo,
+		storeService:    storeService,
+		menuService:     menuService,
+		stopListService: stopListService,
+	}, nil
+}
+
+func (s *ServiceImpl) Create(ctx context.Context, schedule models.Schedule) (string, error) {
+	if err := schedule.Validate(); err != nil {
+		return "", err
+	}
+
+	if _, err := s.storeService.GetByID(ctx, schedule.StoreID); err != nil {
+		return "", err
+	}
+
+	id, err := s.repo.Create(ctx, schedule)
+	if err != nil {
+		return "", err
+	}
+
+	s.applyAfterChange(ctx, schedule.StoreID)
+
+	return id, nil
+}
+
+func (s *ServiceImpl) Update(ctx context.Context, schedule models.Schedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+
+	old, err := s.repo.GetByID(ctx, schedule.ID)
+	if err != nil {
+		return err
+	}
+	schedule.StoreID = old.StoreID
+
+	if err = s.repo.Update(ctx, schedule); err != nil {
+		return err
+	}
+
+	if err = s.releaseAttributes(ctx, old); err != nil {
+		log.Err(err).Msgf("release attributes of availability schedule %s", old.ID)
+	}
+	s.applyAfterChange(ctx, old.StoreID)
+
+	return nil
+}
+
+func (s *ServiceImpl) Delete(ctx context.Context, id string) error {
+	old, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err = s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if err = s.releaseAttributes(ctx, old); err != nil {
+		log.Err(err).Msgf("release attributes of availability schedule %s", old.ID)
+	}
+	s.applyAfterChange(ctx, old.StoreID)
+
+	return nil
+}
+
+func (s *ServiceImpl) GetByID(ctx context.Context, id string) (models.Schedule, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *ServiceImpl) GetByStoreID(ctx context.Context, storeID string) ([]models.Schedule, error) {
+	return s.repo.FindByStoreID(ctx, storeID)
+}
+
+// Apply применяет активные расписания всех ресторанов, вызывается кроном
+func (s *ServiceImpl) Apply(ctx context.Context, now time.Time) ([]models.ApplyResult, error) {
+	schedules, err := s.repo.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	storeIDs := make([]string, 0)
+	byStore := make(map[string][]models.Schedule)
+	for _, schedule := range schedules {
+		if _, ok := byStore[schedule.StoreID]; !ok {
+			storeIDs = append(storeIDs, schedule.StoreID)
+		}
+		byStore[schedule.StoreID] = append(byStore[schedule.StoreID], schedule)
+	}
+
+	results := make([]models.ApplyResult, 0, len(storeIDs))
+	for _, storeID := range storeIDs {
+		result := models.ApplyResult{StoreID: storeID}
+
+		store, err := s.storeService.GetByID(ctx, storeID)
+		if err == nil {
+			result, err = s.applyStore(ctx, store, byStore[storeID], now)
+		}
+		if err != nil {
+			log.Err(err).Msgf("apply availability schedules error, store_id = %s", storeID)
+			result.Error = err.Error()
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (s *ServiceImpl) ApplyByStoreID(ctx context.Context, storeID string, now time.Time) (models.ApplyResult, error) {
+	store, err := s.storeService.GetByID(ctx, storeID)
+	if err != nil {
+		return models.ApplyResult{}, err
+	}
+
+	schedules, err := s.activeStoreSchedules(ctx, storeID)
+	if err != nil {
+		return models.ApplyResult{}, err
+	}
+
+	return s.applyStore(ctx, store, schedules, now)
+}
+
+func (s *ServiceImpl) Preview(ctx context.Context, storeID string, at time.Time) (models.Preview, error) {
+	store, err := s.storeService.GetByID(ctx, storeID)
+	if err != nil {
+		return models.Preview{}, err
+	}
+
+	schedules, err := s.activeStoreSchedules(ctx, storeID)
+	if err != nil {
+		return models.Preview{}, err
+	}
+
+	posMenu, err := s.menuService.FindById(ctx, store.MenuID)
+	if err != nil {
+		return models.Preview{}, err
+	}
+
+	local := at.In(store.Settings.TimeZone.Location())
+
+	preview := models.Preview{
+		StoreID:    store.ID,
+		At:         at.UTC(),
+		LocalTime:  local.Format("2006-01-02 15:04 Mon"),
+		Products:   make([]models.ProductPreview, 0),
+		Attributes: make([]models.AttributePreview, 0),
+	}
+
+	products := s.productsAvailability(*posMenu, schedules, local)
+	for _, product := range posMenu.Products {
+		state, ok := products[s.menuService.GetPosIDFromPosProduct(product)]
+		if !ok || product.IsDeleted {
+			continue
+		}
+
+		product.SetDisableReason(menuModels.DisableReasonAvailabilityWindow, !state.available)
+
+		item := models.ProductPreview{
+			ID:                s.menuService.GetPosIDFromPosProduct(product),
+			ScheduleAvailable: state.available,
+			Available:         product.IsAvailable,
+			DisableReasons:    product.DisableReasons,
+			ScheduleIDs:       state.scheduleIDs,
+		}
+		if len(product.Name) != 0 {
+			item.Name = product.Name[0].Value
+		}
+		preview.Products = append(preview.Products, item)
+	}
+
+	attributes := attributesAvailability(*posMenu, schedules, local)
+	for _, attribute := range posMenu.Attributes {
+		state, ok := attributes[attribute.ExtID]
+		if !ok || attribute.IsDeleted {
+			continue
+		}
+
+		preview.Attributes = append(preview.Attributes, models.AttributePreview{
+			ID:          attribute.ExtID,
+			Name:        attribute.Name,
+			Available:   state.available,
+			ScheduleIDs: state.scheduleIDs,
+		})
+	}
+
+	return preview, nil
+}
+
+// applyStore снимает или ставит причину availability_window продуктам, которые расходятся с расписаниями,
+// продукты без расписаний освобождаются. Атрибуты обновляются только при смене состояния расписания
+func (s *ServiceImpl) applyStore(ctx context.Context, store storeModels.Store, schedules []models.Schedule, now time.Time) (models.ApplyResult, error) {
+	result := models.ApplyResult{StoreID: store.ID}
+
+	posMenu, err := s.menuService.FindById(ctx, store.MenuID)
+	if err != nil {
+		return result, err
+	}
+
+	local := now.In(store.Settings.TimeZone.Location())
+
+	products := s.productsAvailability(*posMenu, schedules, local)
+
+	var enable, disable []string
+	for _, product := range posMenu.Products {
+		if product.IsDeleted {
+			continue
+		}
+
+		id := s.menuService.GetPosIDFromPosProduct(product)
+
+		isAvailable := true
+		if state, ok := products[id]; ok {
+			isAvailable = state.available
+		}
+
+		if product.HasDisableReason(menuModels.DisableReasonAvailabilityWindow) != isAvailable {
+			continue
+		}
+
+		if isAvailable {
+			enable = append(enable, id)
+		} else {
+			disable = append(disable, id)
+		}
+	}
+
+	if len(disable) != 0 {
+		if err = s.stopListService.UpdateStopListByPosProductIDsWithReason(ctx, menuModels.DisableReasonAvailabilityWindow, false, store.ID, disable); err != nil {
+			return result, err
+		}
+		result.DisabledProducts = len(disable)
+	}
+
+	if len(enable) != 0 {
+		if err = s.stopListService.UpdateStopListByPosProductIDsWithReason(ctx, menuModels.DisableReasonAvailabilityWindow, true, store.ID, enable); err != nil {
+			return result, err
+		}
+		result.EnabledProducts = len(enable)
+	}
+
+	if err = s.applyAttributes(ctx, store, *posMenu, schedules, local, &result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (s *ServiceImpl) applyAttributes(ctx context.Context, store storeModels.Store, posMenu menuModels.Menu, schedules []models.Schedule, local time.Time, result *models.ApplyResult) error {
+	attributes := attributesAvailability(posMenu, schedules, local)
+	groups := attributeGroups(posMenu)
+
+	changed := make([]models.Schedule, 0)
+	pushed := make(map[string]struct{})
+	var enable, disable []string
+
+	for _, schedule := range schedules {
+		if schedule.EntityType != models.EntityTypeAttributeGroup {
+			continue
+		}
+
+		isAvailable := schedule.IsAvailableAt(local)
+		if schedule.AppliedAvailable != nil && *schedule.AppliedAvailable == isAvailable {
+			continue
+		}
+		changed = append(changed, schedule)
+
+		for _, groupID := range schedule.EntityIDs {
+			for _, attributeID := range groups[groupID] {
+				if _, ok := pushed[attributeID]; ok {
+					continue
+				}
+				pushed[attributeID] = struct{}{}
+
+				if attributes[attributeID].available {
+					enable = append(enable, attributeID)
+				} else {
+					disable = append(disable, attributeID)
+				}
+			}
+		}
+	}
+
+	if len(disable) != 0 {
+		if err := s.stopListService.UpdateStopListByAttributeIDs(ctx, false, store.ID, disable); err != nil {
+			return err
+		}
+		result.DisabledAttributes = len(disable)
+	}
+
+	if len(enable) != 0 {
+		if err := s.stopListService.UpdateStopListByAttributeIDs(ctx, true, store.ID, enable); err != nil {
+			return err
+		}
+		result.EnabledAttributes = len(enable)
+	}
+
+	for _, schedule := range changed {
+		if err := s.repo.SetAppliedAvailable(ctx, schedule.ID, schedule.IsAvailableAt(local)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseAttributes включает атрибуты измененного или удаленного расписания, если оно их выключило
+func (s *ServiceImpl) releaseAttributes(ctx context.Context, schedule models.Schedule) error {
+	if schedule.EntityType != models.EntityTypeAttributeGroup || schedule.AppliedAvailable == nil || *schedule.AppliedAvailable {
+		return nil
+	}
+
+	store, err := s.storeService.GetByID(ctx, schedule.StoreID)
+	if err != nil {
+		return err
+	}
+
+	posMenu, err := s.menuService.FindById(ctx, store.MenuID)
+	if err != nil {
+		return err
+	}
+
+	groups := attributeGroups(*posMenu)
+
+	attributeIDs := make([]string, 0)
+	for _, groupID := range schedule.EntityIDs {
+		attributeIDs = append(attributeIDs, groups[groupID]...)
+	}
+	if len(attributeIDs) == 0 {
+		return nil
+	}
+
+	return s.stopListService.UpdateStopListByAttributeIDs(ctx, true, store.ID, attributeIDs)
+}
+
+func (s *ServiceImpl) applyAfterChange(ctx context.Context, storeID string) {
+	if _, err := s.ApplyByStoreID(ctx, storeID, time.Now().UTC()); err != nil {
+		log.Err(err).Msgf("apply availability schedules after change error, store_id = %s", storeID)
+	}
+}
+
+func (s *ServiceImpl) activeStoreSchedules(ctx context.Context, storeID string) ([]models.Schedule, error) {
+	schedules, err := s.repo.FindByStoreID(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]models.Schedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		if schedule.IsActive {
+			active = append(active, schedule)
+		}
+	}
+
+	return active, nil
+}
+
+type entityAvailability struct {
+	available   bool
+	scheduleIDs []string
+}
+
+// productsAvailability - доступность продуктов pos меню по расписаниям, ключ - pos id продукта.
+// Продукт доступен, только если он доступен во всех своих расписаниях
+func (s *ServiceImpl) productsAvailability(posMenu menuModels.Menu, schedules []models.Schedule, local time.Time) map[string]*entityAvailability {
+	res := make(map[string]*entityAvailability)
+
+	for _, schedule := range schedules {
+		if schedule.EntityType != models.EntityTypeProduct && schedule.EntityType != models.EntityTypeSection {
+			continue
+		}
+
+		ids := make(map[string]struct{}, len(schedule.EntityIDs))
+		for _, id := range schedule.EntityIDs {
+			ids[id] = struct{}{}
+		}
+		isAvailable := schedule.IsAvailableAt(local)
+
+		for _, product := range posMenu.Products {
+			id := s.menuService.GetPosIDFromPosProduct(product)
+
+			key := id
+			if schedule.EntityType == models.EntityTypeSection {
+				key = product.Section
+			}
+			if _, ok := ids[key]; !ok {
+				continue
+			}
+
+			addAvailability(res, id, schedule.ID, isAvailable)
+		}
+	}
+
+	return res
+}
+
+// attributesAvailability - доступность атрибутов pos меню по расписаниям групп атрибутов, ключ - ext id атрибута
+func attributesAvailability(posMenu menuModels.Menu, schedules []models.Schedule, local time.Time) map[string]*entityAvailability {
+	res := make(map[string]*entityAvailability)
+	groups := attributeGroups(posMenu)
+
+	for _, schedule := range schedules {
+		if schedule.EntityType != models.EntityTypeAttributeGroup {
+			continue
+		}
+
+		isAvailable := schedule.IsAvailableAt(local)
+		for _, groupID := range schedule.EntityIDs {
+			for _, attributeID := range groups[groupID] {
+				addAvailability(res, attributeID, schedule.ID, isAvailable)
+			}
+		}
+	}
+
+	return res
+}
+
+func attributeGroups(posMenu menuModels.Menu) map[string][]string {
+	groups := make(map[string][]string, len(posMenu.AttributesGroups))
+	for _, group := range posMenu.AttributesGroups {
+		groups[group.ExtID] = group.Attributes
+	}
+	return groups
+}
+
+func addAvailability(res map[string]*entityAvailability, id, scheduleID string, isAvailable bool) {
+	state, ok := res[id]
+	if !ok {
+		state = &entityAvailability{available: true}
+		res[id] = state
+	}
+	state.available = state.available && isAvailable
+	state.scheduleIDs = append(state.scheduleIDs, scheduleID)
+}
@@ -38,6 +38,7 @@ func (svc *ServiceImpl) getExtension(contentType string) string {
 		"application/pdf":    ".pdf",
 		"application/msword": ".doc",
 		"application/vnd.openxmlformats-officedocument.wordprocessingml.document": ".docx",
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":       ".xlsx",
 	}
 
 	return fileExtension[contentType]
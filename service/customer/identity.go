@@ -0,0 +1,34 @@
+package customer
+
+import (
+	"context"
+
+	"github.com/kwaaka-team/orders-core/core/models"
+	"github.com/pkg/errors"
+)
+
+// IdentityLinker привязывает идентификаторы внешних систем к профилю клиента в местах, где они создаются
+type IdentityLinker struct {
+	repo Repository
+}
+
+func NewIdentityLinker(repo Repository) (*IdentityLinker, error) {
+	if repo == nil {
+		return nil, errors.New("customer repository is nil")
+	}
+
+	return &IdentityLinker{repo: repo}, nil
+}
+
+// LinkIdentity привязывает к телефону идентификатор внешней системы: пользователя qr меню или авторизации, клиента платежной системы, клиента iiko из офлайн заказов
+func (l *IdentityLinker) LinkIdentity(ctx context.Context, phone string, identity models.CustomerIdentity) error {
+	normalized := models.NormalizePhone(phone)
+	if normalized == "" {
+		return ErrInvalidPhone
+	}
+	if identity.Source == "" || identity.ExternalID == "" {
+		return errors.New("identity source and external id are required")
+	}
+
+	return l.repo.Upsert(ctx, normalized, nil, nil, []models.CustomerIdentity{identity})
+}
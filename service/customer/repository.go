@@ -0,0 +1,121 @@
+package customer
+
+import (
+	"context"
+	"time"
+
+	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	"github.com/kwaaka-team/orders-core/core/models"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const collectionName = "customer_profiles"
+
+type Repository interface {
+	Upsert(ctx context.Context, phone string, names, emails []string, identities []models.CustomerIdentity) error
+	Get(ctx context.Context, phone string) (models.CustomerProfile, error)
+	FindByIDs(ctx context.Context, phones []string) ([]models.CustomerProfile, error)
+	FindByIdentity(ctx context.Context, identity models.CustomerIdentity) (models.CustomerProfile, error)
+}
+
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(db *mongo.Database) (*MongoRepository, error) {
+	return &MongoRepository{
+		collection: db.Collection(collectionName),
+	}, nil
+}
+
+// Upsert добавляет к профилю телефона новые имена, почты и идентификаторы, существующие не дублируются
+func (m *MongoRepository) Upsert(ctx context.Context, phone string, names, emails []string, identities []models.CustomerIdentity) error {
+	now := time.Now().UTC()
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: now}}},
+		{Key: "$setOnInsert", Value: bson.D{{Key: "created_at", Value: now}}},
+		{Key: "$addToSet", Value: bson.D{
+			{Key: "names", Value: bson.D{{Key: "$each", Value: nonEmpty(names)}}},
+			{Key: "emails", Value: bson.D{{Key: "$each", Value: nonEmpty(emails)}}},
+			{Key: "identities", Value: bson.D{{Key: "$each", Value: identitiesOrEmpty(identities)}}},
+		}},
+	}
+
+	_, err := m.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: phone}}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return errorSwitch(err)
+	}
+
+	return nil
+}
+
+func (m *MongoRepository) Get(ctx context.Context, phone string) (models.CustomerProfile, error) {
+	var profile models.CustomerProfile
+	if err := m.collection.FindOne(ctx, bson.D{{Key: "_id", Value: phone}}).Decode(&profile); err != nil {
+		return models.CustomerProfile{}, errorSwitch(err)
+	}
+
+	return profile, nil
+}
+
+func (m *MongoRepository) FindByIDs(ctx context.Context, phones []string) ([]models.CustomerProfile, error) {
+	cursor, err := m.collection.Find(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: phones}}}})
+	if err != nil {
+		return nil, errorSwitch(err)
+	}
+
+	profiles := make([]models.CustomerProfile, 0)
+	if err := cursor.All(ctx, &profiles); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+func (m *MongoRepository) FindByIdentity(ctx context.Context, identity models.CustomerIdentity) (models.CustomerProfile, error) {
+	filter := bson.D{
+		{Key: "identities", Value: bson.D{{Key: "$elemMatch", Value: bson.D{
+			{Key: "source", Value: identity.Source},
+			{Key: "external_id", Value: identity.ExternalID},
+		}}}},
+	}
+
+	var profile models.CustomerProfile
+	if err := m.collection.FindOne(ctx, filter).Decode(&profile); err != nil {
+		return models.CustomerProfile{}, errorSwitch(err)
+	}
+
+	return profile, nil
+}
+
+func nonEmpty(values []string) []string {
+	res := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			res = append(res, value)
+		}
+	}
+	return res
+}
+
+func identitiesOrEmpty(identities []models.CustomerIdentity) []models.CustomerIdentity {
+	if identities == nil {
+		return make([]models.CustomerIdentity, 0)
+	}
+	return identities
+}
+
+func errorSwitch(err error) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return drivers.ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return drivers.ErrAlreadyExist
+	default:
+		return err
+	}
+}
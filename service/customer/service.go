@@ -0,0 +1,288 @@
+package customer
+
+import (
+	"context"
+	"time"
+
+	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	"github.com/kwaaka-team/orders-core/core/models"
+	"github.com/kwaaka-team/orders-core/core/models/selector"
+	storeSelector "github.com/kwaaka-team/orders-core/core/storecore/managers/selector"
+	paymentModels "github.com/kwaaka-team/orders-core/service/payment/models"
+	paymentRepository "github.com/kwaaka-team/orders-core/service/payment/repository"
+	storeServicePkg "github.com/kwaaka-team/orders-core/service/store"
+	"github.com/pkg/errors"
+)
+
+// approvedCardStatus - статус привязанной карты платежной системы, доступной для оплаты
+const approvedCardStatus = "APPROVED"
+
+var ErrInvalidPhone = errors.New("invalid customer phone")
+
+type Service interface {
+	GetProfile(ctx context.Context, phone, restaurantGroupID string) (models.CustomerGroupProfile, error)
+	GetProfileByIdentity(ctx context.Context, identity models.CustomerIdentity, restaurantGroupID string) (models.CustomerGroupProfile, error)
+	GetOrderHistory(ctx context.Context, phone, restaurantGroupID string, pagination selector.Pagination) ([]models.Order, int, error)
+	GetGroupCustomers(ctx context.Context, restaurantGroupID, deliveryService string, from, to time.Time) ([]models.CustomerGroupProfile, error)
+	LinkIdentity(ctx context.Context, phone string, identity models.CustomerIdentity) error
+	SaveFromOrder(ctx context.Context, order models.Order) error
+}
+
+// OrderFinder - заказы клиента, реализуется репозиторием заказов
+type OrderFinder interface {
+	GetAllOrders(ctx context.Context, query selector.Order) ([]models.Order, int, error)
+	GetCustomerStatsOrders(ctx context.Context, query selector.Order) ([]models.Order, error)
+}
+
+type ServiceImpl struct {
+	repo             Repository
+	orderFinder      OrderFinder
+	storeService     storeServicePkg.Service
+	paymentCustomers paymentRepository.CustomersRepository
+	linker           *IdentityLinker
+}
+
+func NewService(repo Repository, orderFinder OrderFinder, storeService storeServicePkg.Service, paymentCustomers paymentRepository.CustomersRepository) (*ServiceImpl, error) {
+	if repo == nil {
+		return nil, errors.New("customer repository is nil")
+	}
+	if orderFinder == nil {
+		return nil, errors.New("order finder is nil")
+	}
+	if storeService == nil {
+		return nil, errors.New("store service is nil")
+	}
+	if paymentCustomers == nil {
+		return nil, errors.New("payment customers repository is nil")
+	}
+
+	return &ServiceImpl{
+		repo:             repo,
+		orderFinder:      orderFinder,
+		storeService:     storeService,
+		paymentCustomers: paymentCustomers,
+		linker:           &IdentityLinker{repo: repo},
+	}, nil
+}
+
+// GetProfile - профиль клиента по телефону в любом формате со статистикой заказов и картами в ресторанах группы
+func (s *ServiceImpl) GetProfile(ctx context.Context, phone, restaurantGroupID string) (models.CustomerGroupProfile, error) {
+	normalized := models.NormalizePhone(phone)
+	if normalized == "" {
+		return models.CustomerGroupProfile{}, ErrInvalidPhone
+	}
+
+	profile, err := s.repo.Get(ctx, normalized)
+	switch {
+	case errors.Is(err, drivers.ErrNotFound):
+		profile = models.CustomerProfile{ID: normalized}
+	case err != nil:
+		return models.CustomerGroupProfile{}, err
+	}
+
+	storeIDs, err := s.groupStoreIDs(ctx, restaurantGroupID)
+	if err != nil {
+		return models.CustomerGroupProfile{}, err
+	}
+
+	var orders []models.Order
+	if len(storeIDs) != 0 {
+		if orders, _, err = s.orderFinder.GetAllOrders(ctx, selector.EmptyOrderSearch().
+			SetRestaurants(storeIDs).
+			SetCustomerPhones(models.PhoneVariants(normalized))); err != nil {
+			return models.CustomerGroupProfile{}, err
+		}
+	}
+
+	cards, err := s.getCards(ctx, normalized, storeIDs)
+	if err != nil {
+		return models.CustomerGroupProfile{}, err
+	}
+
+	res := models.BuildCustomerGroupProfile(profile, orders, cards)
+	res.RestaurantGroupID = restaurantGroupID
+
+	return res, nil
+}
+
+// GetProfileByIdentity - профиль по идентификатору внешней системы, например пользователю qr меню
+func (s *ServiceImpl) GetProfileByIdentity(ctx context.Context, identity models.CustomerIdentity, restaurantGroupID string) (models.CustomerGroupProfile, error) {
+	profile, err := s.repo.FindByIdentity(ctx, identity)
+	if err != nil {
+		return models.CustomerGroupProfile{}, err
+	}
+
+	return s.GetProfile(ctx, profile.ID, restaurantGroupID)
+}
+
+// GetOrderHistory - заказы клиента в ресторанах группы от новых к старым
+func (s *ServiceImpl) GetOrderHistory(ctx context.Context, phone, restaurantGroupID string, pagination selector.Pagination) ([]models.Order, int, error) {
+	normalized := models.NormalizePhone(phone)
+	if normalized == "" {
+		return nil, 0, ErrInvalidPhone
+	}
+
+	storeIDs, err := s.groupStoreIDs(ctx, restaurantGroupID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(storeIDs) == 0 {
+		return []models.Order{}, 0, nil
+	}
+
+	return s.orderFinder.GetAllOrders(ctx, selector.EmptyOrderSearch().
+		SetRestaurants(storeIDs).
+		SetCustomerPhones(models.PhoneVariants(normalized)).
+		SetPage(pagination.Page).
+		SetLimit(pagination.Limit).
+		SetSorting("order_time.value", -1))
+}
+
+// GetGroupCustomers - профили клиентов, заказывавших в ресторанах группы за период, без карт и адресов.
+// Пустой deliveryService - заказы всех каналов
+func (s *ServiceImpl) GetGroupCustomers(ctx context.Context, restaurantGroupID, deliveryService string, from, to time.Time) ([]models.CustomerGroupProfile, error) {
+	storeIDs, err := s.groupStoreIDs(ctx, restaurantGroupID)
+	if err != nil {
+		return nil, err
+	}
+	if len(storeIDs) == 0 {
+		return []models.CustomerGroupProfile{}, nil
+	}
+
+	orders, err := s.orderFinder.GetCustomerStatsOrders(ctx, selector.EmptyOrderSearch().
+		SetRestaurants(storeIDs).
+		SetDeliveryService(deliveryService).
+		SetOrderTimeFrom(from).
+		SetOrderTimeTo(to))
+	if err != nil {
+		return nil, err
+	}
+
+	byPhone := make(map[string][]models.Order)
+	phones := make([]string, 0)
+	for _, order := range orders {
+		phone := customerPhone(order.Customer)
+		if phone == "" {
+			continue
+		}
+		if _, ok := byPhone[phone]; !ok {
+			phones = append(phones, phone)
+		}
+		byPhone[phone] = append(byPhone[phone], order)
+	}
+	if len(phones) == 0 {
+		return []models.CustomerGroupProfile{}, nil
+	}
+
+	profiles, err := s.repo.FindByIDs(ctx, phones)
+	if err != nil {
+		return nil, err
+	}
+	profileByPhone := make(map[string]models.CustomerProfile, len(profiles))
+	for _, profile := range profiles {
+		profileByPhone[profile.ID] = profile
+	}
+
+	res := make([]models.CustomerGroupProfile, 0, len(phones))
+	for _, phone := range phones {
+		profile, ok := profileByPhone[phone]
+		if !ok {
+			profile = models.CustomerProfile{ID: phone}
+		}
+		groupProfile := models.BuildCustomerGroupProfile(profile, byPhone[phone], nil)
+		groupProfile.RestaurantGroupID = restaurantGroupID
+		res = append(res, groupProfile)
+	}
+
+	return res, nil
+}
+
+// LinkIdentity привязывает к телефону идентификатор внешней системы
+func (s *ServiceImpl) LinkIdentity(ctx context.Context, phone string, identity models.CustomerIdentity) error {
+	return s.linker.LinkIdentity(ctx, phone, identity)
+}
+
+// SaveFromOrder добавляет в профиль имя и почту клиента из заказа
+func (s *ServiceImpl) SaveFromOrder(ctx context.Context, order models.Order) error {
+	phone := customerPhone(order.Customer)
+	if phone == "" {
+		return nil
+	}
+
+	return s.repo.Upsert(ctx, phone, []string{order.Customer.Name}, []string{order.Customer.Email}, nil)
+}
+
+func (s *ServiceImpl) groupStoreIDs(ctx context.Context, restaurantGroupID string) ([]string, error) {
+	if restaurantGroupID == "" {
+		return nil, errors.New("restaurant group id is required")
+	}
+
+	stores, err := s.storeService.GetRestaurantsByGroupId(ctx, storeSelector.Pagination{}, restaurantGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	storeIDs := make([]string, 0, len(stores))
+	for _, st := range stores {
+		storeIDs = append(storeIDs, st.ID)
+	}
+
+	return storeIDs, nil
+}
+
+// getCards - одобренные карты клиента платежной системы, привязанные к ресторанам группы
+func (s *ServiceImpl) getCards(ctx context.Context, phone string, storeIDs []string) ([]models.CustomerCard, error) {
+	if len(storeIDs) == 0 {
+		return nil, nil
+	}
+
+	customers, err := s.paymentCustomers.GetCustomersByPhones(ctx, models.PhoneVariants(phone))
+	if err != nil {
+		return nil, err
+	}
+
+	inGroup := make(map[string]bool, len(storeIDs))
+	for _, storeID := range storeIDs {
+		inGroup[storeID] = true
+	}
+
+	cards := make([]models.CustomerCard, 0)
+	seen := make(map[string]bool)
+	for _, customer := range customers {
+		if !hasRestaurant(customer, inGroup) {
+			continue
+		}
+		for _, card := range customer.Cards {
+			if card.Status != approvedCardStatus || seen[card.ID] {
+				continue
+			}
+			seen[card.ID] = true
+			cards = append(cards, models.CustomerCard{
+				ID:            card.ID,
+				PanMasked:     card.PanMasked,
+				ExpiryDate:    card.ExpiryDate,
+				PaymentSystem: card.PaymentSystem,
+			})
+		}
+	}
+
+	return cards, nil
+}
+
+func hasRestaurant(customer paymentModels.PaymentSystemCustomer, inGroup map[string]bool) bool {
+	for _, restaurantID := range customer.CustomerRestaurants {
+		if inGroup[restaurantID] {
+			return true
+		}
+	}
+	return false
+}
+
+// customerPhone - нормализованный телефон клиента заказа, пустой для телефона-заглушки 3pl
+func customerPhone(customer models.Customer) string {
+	phone := models.NormalizePhone(customer.PhoneNumber)
+	if phone == "" || phone == models.Default3plCustomerPhone {
+		return ""
+	}
+	return phone
+}
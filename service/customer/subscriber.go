@@ -0,0 +1,29 @@
+package customer
+
+import (
+	"context"
+
+	"github.com/kwaaka-team/orders-core/core/models"
+	coreStoreModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/pkg/errors"
+)
+
+// ProfileSubscriber сохраняет имя и почту клиента в профиль, когда заказ принят
+type ProfileSubscriber struct {
+	customerService Service
+}
+
+func NewProfileSubscriber(customerService Service) (*ProfileSubscriber, error) {
+	if customerService == nil {
+		return nil, errors.New("customer service is nil")
+	}
+	return &ProfileSubscriber{customerService: customerService}, nil
+}
+
+func (s *ProfileSubscriber) SendOrder(ctx context.Context, order models.Order, store coreStoreModels.Store, posStatus models.PosStatus) error {
+	if posStatus != models.ACCEPTED || order.IsTestOrder {
+		return nil
+	}
+
+	return s.customerService.SaveFromOrder(ctx, order)
+}
@@ -11,6 +11,9 @@ type EntityChangesHistory struct {
 	CollectionName   string    `bson:"collection_name"`
 	OldBody          any       `bson:"body"`
 	ModifiedAt       time.Time `bson:"modified_at"`
+	// SnapshotID - версия меню после изменения, PreviousSnapshotID - до изменения (menu_versions)
+	SnapshotID         string `bson:"snapshot_id,omitempty"`
+	PreviousSnapshotID string `bson:"previous_snapshot_id,omitempty"`
 }
 
 type EntityChangesHistoryRequest struct {
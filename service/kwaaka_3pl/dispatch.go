@@ -0,0 +1,234 @@
+package kwaaka_3pl
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kwaaka-team/orders-core/core/managers/telegram"
+	models3 "github.com/kwaaka-team/orders-core/core/menu/models"
+	models2 "github.com/kwaaka-team/orders-core/core/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/kwaaka_3pl/models"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// DispatchBestProvider создает доставку у лучшего по цене и времени провайдера для заказа без доставки
+func (s *ServiceImpl) DispatchBestProvider(ctx context.Context, orderID string) (models.DispatchResult, error) {
+	order, err := s.repository.FindOrderByID(ctx, orderID)
+	if err != nil {
+		return models.DispatchResult{}, err
+	}
+
+	if order.DeliveryOrderID != "" {
+		return models.DispatchResult{}, errors.Errorf("order %s already has delivery %s", order.ID, order.DeliveryOrderID)
+	}
+	if order.IsMarketplace || !order.SendCourier {
+		return models.DispatchResult{}, errors.Errorf("can't dispatch order %s, isMarketPlace: %v, sendCourier: %v", order.ID, order.IsMarketplace, order.SendCourier)
+	}
+
+	store, err := s.storeService.GetByID(ctx, order.RestaurantID)
+	if err != nil {
+		return models.DispatchResult{}, err
+	}
+	if !store.Kwaaka3PL.Is3pl {
+		return models.DispatchResult{}, errors.New("store is not integrated with kwaaka 3pl")
+	}
+
+	quote := s.deliveryZoneQuote(order, store)
+
+	ranked, err := s.rankProviders(ctx, order, store)
+	if err != nil {
+		return models.DispatchResult{}, err
+	}
+	if len(ranked) == 0 {
+		return models.DispatchResult{}, errors.Errorf("no available 3pl providers for order %s", order.ID)
+	}
+
+	if err = s.dispatchToProvider(ctx, order, ranked[0], quote); err != nil {
+		return models.DispatchResult{}, err
+	}
+
+	return models.DispatchResult{
+		OrderID:    order.ID,
+		Dispatcher: ranked[0].ProviderService,
+		Attempt:    s.dispatchAttempt(order),
+		Ranked:     ranked,
+	}, nil
+}
+
+// redispatchOnNoCourier отменяет поиск курьера и переназначает доставку следующему провайдеру, переход пишется в history_3pl_delivery_info
+func (s *ServiceImpl) redispatchOnNoCourier(ctx context.Context, order models2.Order, store storeModels.Store, orderInfo models2.OrderInfoForTelegramMsg) error {
+	attempt := s.dispatchAttempt(order)
+	if attempt >= store.Kwaaka3PL.Dispatch.Attempts() {
+		msg := fmt.Sprintf("<b>Курьер не найден после %d попыток переназначения. Необходимо назначить курьера вручную</b>\n", attempt)
+		return s.telegramService.SendMessageToQueue(telegram.ThirdPartyError, order, store, "", msg+s.convertOrderToMessage(orderInfo), "", models3.Product{})
+	}
+
+	quote := s.deliveryZoneQuote(order, store)
+
+	ranked, err := s.rankProviders(ctx, order, store)
+	if err != nil {
+		return err
+	}
+	if len(ranked) == 0 {
+		msg := "<b>Курьер не найден, других доступных провайдеров нет. Необходимо назначить курьера вручную</b>\n"
+		return s.telegramService.SendMessageToQueue(telegram.ThirdPartyError, order, store, "", msg+s.convertOrderToMessage(orderInfo), "", models3.Product{})
+	}
+
+	if err = s.CancelCourierSearch(ctx, order.DeliveryOrderID); err != nil {
+		return err
+	}
+
+	if err = s.repository.Save3plDeliveryHistoryAndSetEmptyDispatcherService(ctx, order.ID, models2.History3plDelivery{
+		DeliveryOrderID:            order.DeliveryOrderID,
+		DeliveryDispatcher:         order.DeliveryDispatcher,
+		FullDeliveryPrice:          order.FullDeliveryPrice,
+		RestaurantPayDeliveryPrice: order.RestaurantPayDeliveryPrice,
+		KwaakaChargedDeliveryPrice: order.KwaakaChargedDeliveryPrice,
+		DeliveryAddress:            order.DeliveryAddress,
+		Customer:                   order.Customer,
+		Reason:                     models.DispatchReasonNoCourierTimeout,
+		NextDispatcher:             ranked[0].ProviderService,
+		ReassignedAt:               time.Now().UTC(),
+	}, models2.DeliveryAddress{}, models2.Customer{}); err != nil {
+		return err
+	}
+
+	order, err = s.repository.FindOrderByID(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+
+	// поиск курьера уже отменен: при ошибке создания доставки пробуем следующего провайдера, а не оставляем заказ без доставки
+	for _, provider := range ranked {
+		if err = s.dispatchToProvider(ctx, order, provider, quote); err != nil {
+			log.Err(err).Msgf("error: redispatch order id: %s to 3pl provider: %s", order.ID, provider.ProviderService)
+			msg := fmt.Sprintf("<b>Не удалось переназначить доставку провайдеру %s: %s</b>\n", provider.ProviderService, err.Error())
+			s.sendDispatchAlert(order, store, msg+s.convertOrderToMessage(orderInfo))
+			continue
+		}
+
+		msg := fmt.Sprintf("<b>Курьер не найден за %d минут, доставка переназначена: %s -> %s</b>\n", store.Kwaaka3PL.Dispatch.NoCourierTimeout(), orderInfo.DeliveryService, provider.ProviderService)
+		s.sendDispatchAlert(order, store, msg+s.convertOrderToMessage(orderInfo))
+		return nil
+	}
+
+	msg := "<b>Курьер не найден, ни один провайдер не принял доставку после отмены поиска. Необходимо назначить курьера вручную</b>\n"
+	return s.telegramService.SendMessageToQueue(telegram.ThirdPartyError, order, store, "", msg+s.convertOrderToMessage(orderInfo), "", models3.Product{})
+}
+
+func (s *ServiceImpl) sendDispatchAlert(order models2.Order, store storeModels.Store, msg string) {
+	if err := s.telegramService.SendMessageToQueue(telegram.ThirdPartyError, order, store, "", msg, "", models3.Product{}); err != nil {
+		log.Err(err).Msgf("error: SendMessageToQueue for order id: %s", order.ID)
+	}
+}
+
+// rankProviders - доступные ресторану провайдеры, которые еще не пробовали доставлять заказ, лучшие первыми
+func (s *ServiceImpl) rankProviders(ctx context.Context, order models2.Order, store storeModels.Store) ([]models.RankedProvider, error) {
+	proposals, err := s.ListPotentialProviders(ctx, models.ListProvidersRequest{
+		Address: models.OrderAddress{
+			City:   order.DeliveryAddress.City,
+			Street: order.DeliveryAddress.Street,
+			Coordinates: models.Coordinates{
+				Lat: order.DeliveryAddress.Latitude,
+				Lon: order.DeliveryAddress.Longitude,
+			},
+			Language: "ru",
+		},
+		RestaurantCoordinates: models.Coordinates{
+			Lat: store.Address.Coordinates.Latitude,
+			Lon: store.Address.Coordinates.Longitude,
+		},
+		MinPreparationTimeMinutes: int(order.EstimatedPickupTime.Value.Sub(time.Now().UTC()).Minutes()),
+		ItemsSettings: models.ItemsSettings{
+			Quantity: len(order.Products),
+			Size: models.Size{
+				Height: 0.3,
+				Width:  0.3,
+				Length: 0.3,
+			},
+			Weight: 1,
+		},
+		KwaakaChargePercentage: store.Kwaaka3PL.KwaakaChargePercentage,
+		KwaakaChargeAbsolut:    store.Kwaaka3PL.KwaakaChargeAbsolute,
+		IndriveAvailable:       store.Kwaaka3PL.IndriveAvailable,
+		WoltAvailable:          store.Kwaaka3PL.WoltDriveAvailable,
+		YandexAvailable:        store.Kwaaka3PL.YandexAvailable,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tried := make(map[string]bool)
+	if order.DeliveryOrderID != "" {
+		tried[order.DeliveryDispatcher] = true
+	}
+	for _, history := range order.History3plDeliveryInfo {
+		tried[history.DeliveryDispatcher] = true
+	}
+
+	priceWeight, etaWeight := store.Kwaaka3PL.Dispatch.Weights()
+
+	return models.RankProviders(proposals, priceWeight, etaWeight, func(provider string) bool {
+		return tried[provider] || !isProviderAvailable(store.Kwaaka3PL, provider)
+	}), nil
+}
+
+// isProviderAvailable - провайдер включен флагами wolt_drive/yandex/indrive_available ресторана, остальные провайдеры не ограничиваются
+func isProviderAvailable(kwaaka3pl storeModels.Kwaaka3PL, provider string) bool {
+	switch provider {
+	case models.WoltDelivery:
+		return kwaaka3pl.WoltDriveAvailable
+	case models.YandexDelivery:
+		return kwaaka3pl.YandexAvailable
+	case models.IndriveDelivery:
+		return kwaaka3pl.IndriveAvailable
+	}
+	return true
+}
+
+// deliveryZoneQuote - цена доставки заказа для клиента по зоне доставки ресторана.
+// Созданный заказ доставляется и вне зон: отправляется алерт, клиент платит цену из заказа
+func (s *ServiceImpl) deliveryZoneQuote(order models2.Order, store storeModels.Store) storeModels.DeliveryQuote {
+	quote, err := store.Kwaaka3PL.PriceDelivery(storeModels.Coordinates{
+		Latitude:  order.DeliveryAddress.Latitude,
+		Longitude: order.DeliveryAddress.Longitude,
+	}, order.EstimatedTotalPrice.Value, order.ClientDeliveryPrice)
+	if err != nil {
+		log.Warn().Err(err).Msgf("dispatch order %s out of restaurant %s delivery zones", order.ID, store.ID)
+		s.sendDispatchAlert(order, store, fmt.Sprintf("<b>Адрес доставки вне зон доставки ресторана, заказ %s отправлен курьеру</b>\n", order.ID))
+		return storeModels.DeliveryQuote{ClientDeliveryPrice: order.ClientDeliveryPrice}
+	}
+	return quote
+}
+
+func (s *ServiceImpl) dispatchToProvider(ctx context.Context, order models2.Order, provider models.RankedProvider, quote storeModels.DeliveryQuote) error {
+	order.DeliveryDispatcher = provider.ProviderService
+	order.FullDeliveryPrice = float64(provider.Price.Amount)
+	order.KwaakaChargedDeliveryPrice = provider.Price.KwaakaChargeSum
+	order.ClientDeliveryPrice = quote.ClientDeliveryPrice
+	order.RestaurantPayDeliveryPrice = math.Max(order.FullDeliveryPrice-quote.ClientDeliveryPrice, 0)
+	order.DispatcherDeliveryTime = int32(provider.TimeEstimateMinutes)
+
+	if err := s.repository.UpdateOrder(ctx, order); err != nil {
+		return err
+	}
+
+	s.logger.Infof("dispatch order %s to 3pl provider %s, price: %d, eta: %d", order.ID, provider.ProviderService, provider.Price.Amount, provider.TimeEstimateMinutes)
+
+	return s.BulkCreate3plOrder(ctx, []models2.Order{order}, false)
+}
+
+// dispatchAttempt - номер текущей попытки доставки заказа, считаются только автоматические переназначения
+func (s *ServiceImpl) dispatchAttempt(order models2.Order) int {
+	attempt := 1
+	for _, history := range order.History3plDeliveryInfo {
+		if history.Reason == models.DispatchReasonNoCourierTimeout {
+			attempt++
+		}
+	}
+	return attempt
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"sort"
+)
+
+const (
+	DispatchReasonNoCourierTimeout = "no_courier_timeout"
+)
+
+// RankedProvider - предложение провайдера со score, меньше - лучше
+type RankedProvider struct {
+	GetPromise
+	Score float64 `json:"score"`
+}
+
+type DispatchResult struct {
+	OrderID    string           `json:"order_id"`
+	Dispatcher string           `json:"dispatcher"`
+	Attempt    int              `json:"attempt"`
+	Ranked     []RankedProvider `json:"ranked"`
+}
+
+// RankProviders сортирует предложения провайдеров по взвешенной сумме цены и времени доставки, отнесенных к лучшим значениям среди предложений.
+// Предложения с ошибкой и провайдеры из skip пропускаются, при равном score выше провайдер с меньшим priority
+func RankProviders(proposals []ProviderResponse, priceWeight, etaWeight float64, skip func(provider string) bool) []RankedProvider {
+	promises := make([]GetPromise, 0, len(proposals))
+	for _, proposal := range proposals {
+		if proposal.Provider == nil || proposal.Provider.ProviderService == "" {
+			continue
+		}
+		if skip != nil && skip(proposal.Provider.ProviderService) {
+			continue
+		}
+		promises = append(promises, *proposal.Provider)
+	}
+
+	if len(promises) == 0 {
+		return nil
+	}
+
+	minPrice, minEta := promises[0].Price.Amount, promises[0].TimeEstimateMinutes
+	for _, promise := range promises[1:] {
+		if promise.Price.Amount < minPrice {
+			minPrice = promise.Price.Amount
+		}
+		if promise.TimeEstimateMinutes < minEta {
+			minEta = promise.TimeEstimateMinutes
+		}
+	}
+
+	ranked := make([]RankedProvider, 0, len(promises))
+	for _, promise := range promises {
+		ranked = append(ranked, RankedProvider{
+			GetPromise: promise,
+			Score:      priceWeight*relative(promise.Price.Amount, minPrice) + etaWeight*relative(promise.TimeEstimateMinutes, minEta),
+		})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score < ranked[j].Score
+		}
+		return ranked[i].Priority < ranked[j].Priority
+	})
+
+	return ranked
+}
+
+func relative(value, best int) float64 {
+	if best <= 0 {
+		return float64(value - best + 1)
+	}
+	return float64(value) / float64(best)
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestRankProviders(t *testing.T) {
+	proposal := func(provider string, price, eta, priority int) ProviderResponse {
+		return ProviderResponse{Provider: &GetPromise{
+			Price:               Price{Amount: price},
+			TimeEstimateMinutes: eta,
+			ProviderService:     provider,
+			Priority:            priority,
+		}}
+	}
+
+	proposals := []ProviderResponse{
+		proposal(WoltDelivery, 1500, 20, 1),
+		proposal(YandexDelivery, 1000, 40, 2),
+		proposal(IndriveDelivery, 1200, 25, 3),
+		{Error: "no couriers"},
+	}
+
+	tests := []struct {
+		name        string
+		priceWeight float64
+		etaWeight   float64
+		skip        func(string) bool
+		expected    []string
+	}{
+		{"equal weights", 0.5, 0.5, nil, []string{IndriveDelivery, WoltDelivery, YandexDelivery}},
+		{"price only", 1, 0, nil, []string{YandexDelivery, IndriveDelivery, WoltDelivery}},
+		{"eta only", 0, 1, nil, []string{WoltDelivery, IndriveDelivery, YandexDelivery}},
+		{"tried provider skipped", 0.5, 0.5, func(provider string) bool { return provider == IndriveDelivery }, []string{WoltDelivery, YandexDelivery}},
+	}
+
+	for _, test := range tests {
+		ranked := RankProviders(proposals, test.priceWeight, test.etaWeight, test.skip)
+		if len(ranked) != len(test.expected) {
+			t.Errorf("%s: expected %v, got %+v", test.name, test.expected, ranked)
+			continue
+		}
+		for i := range ranked {
+			if ranked[i].ProviderService != test.expected[i] {
+				t.Errorf("%s: expected %v, got %+v", test.name, test.expected, ranked)
+				break
+			}
+		}
+	}
+
+	tie := RankProviders([]ProviderResponse{proposal(WoltDelivery, 1000, 30, 2), proposal(YandexDelivery, 1000, 30, 1)}, 0.5, 0.5, nil)
+	if tie[0].ProviderService != YandexDelivery {
+		t.Errorf("equal score: expected provider with lower priority first, got %+v", tie)
+	}
+}
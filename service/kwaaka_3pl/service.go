@@ -36,6 +36,8 @@ type Service interface {
 	GetDeliveryDispatcherPrices(ctx context.Context, deliveryIDs []string) (models.GetDeliveryDispatcherPricesResponse, error)
 	Instant3plOrder(ctx context.Context, req models2.Order) error
 	CancelCourierSearch(ctx context.Context, deliveryOrderID string) error
+	DispatchBestProvider(ctx context.Context, orderID string) (models.DispatchResult, error)
+	GetDeliveryTracking(ctx context.Context, orderID string) (models2.DeliveryTracking, error)
 	MapIikoStatusTo3plStatus(ctx context.Context, iikoStatus, customerPhoneNumber, storeID string) error
 	Save3plHistory(ctx context.Context, deliveryOrderId string, newDeliveryAddress models2.DeliveryAddress, newCustomer models2.Customer) error
 	GetOrderByOrderID(ctx context.Context, orderID string) (models2.Order, error)
@@ -62,9 +64,11 @@ type ServiceImpl struct {
 	telegramService    order.TelegramService
 	menuClient         menu.Client
 	deliveryRepository delivery.Repository
+	customerNotifier   CustomerNotifier
+	completedPublisher *order.Publisher
 }
 
-func NewKwaaka3plService(sqsCli que.SQSInterface, queueUrl string, repository order.Repository, storeService store.Service, baseUrl, authToken string, logger *zap.SugaredLogger, telegram order.TelegramService, menuCli menu.Client, deliveryRepository delivery.Repository) (*ServiceImpl, error) {
+func NewKwaaka3plService(sqsCli que.SQSInterface, queueUrl string, repository order.Repository, storeService store.Service, baseUrl, authToken string, logger *zap.SugaredLogger, telegram order.TelegramService, menuCli menu.Client, deliveryRepository delivery.Repository, customerNotifier CustomerNotifier, completedPublisher *order.Publisher) (*ServiceImpl, error) {
 	if baseUrl == "" {
 		return nil, errors.New("base URL could not be empty")
 	}
@@ -94,6 +98,8 @@ func NewKwaaka3plService(sqsCli que.SQSInterface, queueUrl string, repository or
 		telegramService:    telegram,
 		menuClient:         menuCli,
 		deliveryRepository: deliveryRepository,
+		customerNotifier:   customerNotifier,
+		completedPublisher: completedPublisher,
 	}, nil
 }
 
@@ -794,19 +800,22 @@ func (s *ServiceImpl) setCustomerName(name string) string {
 }
 
 func (s *ServiceImpl) GetOrdersByCustomerPhone(ctx context.Context, query models2.GetOrdersByCustomerPhoneRequest) (models2.GetOrdersByCustomerPhoneResponse, error) {
-	orders, totalCount, err := s.repository.GetAllOrders(ctx, selector.EmptyOrderSearch().
+	customerQuery := selector.EmptyOrderSearch().
 		SetRestaurants([]string{query.RestaurantID}).
-		SetCustomerNumber(query.CustomerPhone).
+		SetCustomerNumber(query.CustomerPhone)
+	// телефон в заказах агрегаторов записан по-разному, ищем по всем вариантам
+	if variants := models2.PhoneVariants(query.CustomerPhone); len(variants) != 0 {
+		customerQuery = customerQuery.SetCustomerNumber("").SetCustomerPhones(variants)
+	}
+
+	orders, totalCount, err := s.repository.GetAllOrders(ctx, customerQuery.
 		SetPage(query.Pagination.Page).
 		SetLimit(query.Pagination.Limit).SetSorting("order_time.value", -1))
 	if err != nil {
 		return models2.GetOrdersByCustomerPhoneResponse{}, err
 	}
 
-	averageBill, err := s.repository.GetAverageBill(ctx,
-		selector.EmptyOrderSearch().
-			SetRestaurants([]string{query.RestaurantID}).
-			SetCustomerNumber(query.CustomerPhone))
+	averageBill, err := s.repository.GetAverageBill(ctx, customerQuery)
 	if err != nil {
 		return models2.GetOrdersByCustomerPhoneResponse{}, err
 	}
@@ -1167,6 +1176,9 @@ func (s *ServiceImpl) NoDispatcherMessage(ctx context.Context) error {
 		}
 
 		for _, status := range order.StatusesHistory {
+			if status.Rejected {
+				continue
+			}
 			if status.Name == string(models2.STATUS_COOKING_COMPLETE) && status.Time.Before(timeNow.Add(-time.Minute*5)) && order.DeliveryDispatcher == "" && order.SendCourier == true {
 				log.Info().Msgf("no dispatcher order, order_id: %s", order.OrderID)
 
@@ -1239,6 +1251,9 @@ func (s *ServiceImpl) ActualizeDeliveryInfoByDeliveryIDs(ctx context.Context, de
 		return fmt.Errorf("actualize delivery info by deliveryIDS response error: %s, %s", resp.Error(), errResponse.Message)
 	}
 
+	s.notifyCustomers(ctx, deliveryIDs)
+	s.notifyDelivered(ctx, deliveryIDs)
+
 	return nil
 }
 
@@ -1249,7 +1264,7 @@ func (s *ServiceImpl) PerformerLookupMoreThan15Minute(ctx context.Context) error
 
 	deliveries, err := s.deliveryRepository.GetAllDeliveries(ctx, selector.EmptyDelivery3plSearch().
 		SetStatus(models.PerformerLookup).
-		SetUpdatedTimeTo(timeNow.Add(-time.Minute*15)).
+		SetUpdatedTimeTo(timeNow.Add(-time.Minute)).
 		SetCreatedTimeFrom(timeNow.Add(-time.Hour*1)).
 		SetCreatedTimeTo(timeNow))
 	if err != nil {
@@ -1269,12 +1284,22 @@ func (s *ServiceImpl) PerformerLookupMoreThan15Minute(ctx context.Context) error
 			log.Err(err).Msgf("error: GetByID for id: %s", order.RestaurantID)
 			continue
 		}
+		if delivery.UpdatedAt.After(timeNow.Add(-time.Minute * time.Duration(store.Kwaaka3PL.Dispatch.NoCourierTimeout()))) {
+			continue
+		}
 		orderInfo, err := s.GetOrderForTelegramByDeliveryOrderId(ctx, delivery.Id)
 		if err != nil {
 			log.Err(err).Msgf("error: GetOrderForTelegramByDeliveryOrderId for delivery order id: %s", delivery.Id)
 			continue
 		}
 
+		if store.Kwaaka3PL.Dispatch.AutoFallback {
+			if err := s.redispatchOnNoCourier(ctx, order, store, orderInfo); err != nil {
+				log.Err(err).Msgf("error: redispatchOnNoCourier for delivery order id: %s", delivery.Id)
+			}
+			continue
+		}
+
 		msg := "<b>Долгий поиск курьера (15 минут). Необходимо обратиться в службу поддержки провайдера</b>\n"
 		msgOrder := s.convertOrderToMessage(orderInfo)
 		if err := s.telegramService.SendMessageToQueue(telegram.ThirdPartyError, order, store, "", msg+msgOrder, "", models3.Product{}); err != nil {
@@ -0,0 +1,127 @@
+package kwaaka_3pl
+
+import (
+	"context"
+	"net/url"
+
+	models2 "github.com/kwaaka-team/orders-core/core/models"
+	"github.com/kwaaka-team/orders-core/service/kwaaka_3pl/models"
+	"github.com/rs/zerolog/log"
+)
+
+// CustomerNotifier - отправка сообщений клиенту, реализуется whatsapp.Service
+type CustomerNotifier interface {
+	SendMessage(ctx context.Context, to, message, storeId string) error
+}
+
+// GetDeliveryTracking актуализирует доставку заказа у 3pl и возвращает отслеживание для клиента
+func (s *ServiceImpl) GetDeliveryTracking(ctx context.Context, orderID string) (models2.DeliveryTracking, error) {
+	order, err := s.repository.FindOrderByOrderID(ctx, orderID)
+	if err != nil {
+		return models2.DeliveryTracking{}, err
+	}
+
+	if order.DeliveryOrderID == "" {
+		return models2.DeliveryTracking{}, models.ErrDeliveryOrderIdIsEmpty
+	}
+
+	delivery, err := s.deliveryRepository.GetDeliveryByDeliveryID(ctx, order.DeliveryOrderID)
+	if err != nil {
+		return models2.DeliveryTracking{}, err
+	}
+
+	if !models2.IsDeliveryTrackingFinished(delivery.Status) {
+		if err = s.ActualizeDeliveryInfoByDeliveryIDs(ctx, []string{order.DeliveryOrderID}); err != nil {
+			log.Err(err).Msgf("actualize delivery error, delivery id: %s", order.DeliveryOrderID)
+		} else if delivery, err = s.deliveryRepository.GetDeliveryByDeliveryID(ctx, order.DeliveryOrderID); err != nil {
+			return models2.DeliveryTracking{}, err
+		}
+	}
+
+	return models2.NewDeliveryTracking(order, delivery), nil
+}
+
+// notifyCustomers отправляет клиентам заказов qr menu и kwaaka admin сообщение в whatsapp при смене статуса доставки.
+// Последний отправленный статус хранится в customer_tracking_status заказа
+func (s *ServiceImpl) notifyCustomers(ctx context.Context, deliveryIDs []string) {
+	if s.customerNotifier == nil {
+		return
+	}
+
+	for _, deliveryID := range deliveryIDs {
+		delivery, err := s.deliveryRepository.GetDeliveryByDeliveryID(ctx, deliveryID)
+		if err != nil {
+			log.Err(err).Msgf("notify customer: get delivery error, delivery id: %s", deliveryID)
+			continue
+		}
+
+		order, err := s.repository.GetOrderBy3plDeliveryID(ctx, deliveryID)
+		if err != nil {
+			log.Err(err).Msgf("notify customer: get order error, delivery id: %s", deliveryID)
+			continue
+		}
+
+		if order.CustomerTrackingStatus == delivery.Status || order.Customer.PhoneNumber == "" {
+			continue
+		}
+		if order.DeliveryService != models2.QRMENU.String() && order.DeliveryService != models2.KWAAKA_ADMIN.String() {
+			continue
+		}
+
+		store, err := s.storeService.GetByID(ctx, order.RestaurantID)
+		if err != nil {
+			log.Err(err).Msgf("notify customer: get store error, store id: %s", order.RestaurantID)
+			continue
+		}
+		if !store.Kwaaka3PL.NotifyCustomerTracking {
+			continue
+		}
+
+		msg := models2.NewDeliveryTracking(order, delivery).CustomerMessage(store.Settings.TimeZone.UTCOffset)
+		if msg != "" {
+			if err = s.customerNotifier.SendMessage(ctx, order.Customer.PhoneNumber, url.QueryEscape(msg), order.RestaurantID); err != nil {
+				log.Err(err).Msgf("notify customer: send whatsapp message error, order id: %s", order.ID)
+				continue
+			}
+		}
+
+		if err = s.repository.SetCustomerTrackingStatus(ctx, order.ID, delivery.Status); err != nil {
+			log.Err(err).Msgf("notify customer: set customer tracking status error, order id: %s", order.ID)
+		}
+	}
+}
+
+// notifyDelivered передает заказы доставленных 3pl доставок подписчикам завершенных заказов, например для запроса отзыва.
+// Статус заказа от pos при этом не меняется, повторная передача обрабатывается подписчиками
+func (s *ServiceImpl) notifyDelivered(ctx context.Context, deliveryIDs []string) {
+	if s.completedPublisher == nil {
+		return
+	}
+
+	for _, deliveryID := range deliveryIDs {
+		delivery, err := s.deliveryRepository.GetDeliveryByDeliveryID(ctx, deliveryID)
+		if err != nil {
+			log.Err(err).Msgf("notify delivered: get delivery error, delivery id: %s", deliveryID)
+			continue
+		}
+		if delivery.Status != models.Delivered {
+			continue
+		}
+
+		order, err := s.repository.GetOrderBy3plDeliveryID(ctx, deliveryID)
+		if err != nil {
+			log.Err(err).Msgf("notify delivered: get order error, delivery id: %s", deliveryID)
+			continue
+		}
+
+		store, err := s.storeService.GetByID(ctx, order.RestaurantID)
+		if err != nil {
+			log.Err(err).Msgf("notify delivered: get store error, store id: %s", order.RestaurantID)
+			continue
+		}
+
+		if err = s.completedPublisher.NotifySubscribers(ctx, order, store, models2.DELIVERED); err != nil {
+			log.Err(err).Msgf("notify delivered: notify subscribers error, order id: %s", order.ID)
+		}
+	}
+}
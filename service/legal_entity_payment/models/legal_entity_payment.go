@@ -44,6 +44,7 @@ type ListLegalEntityPaymentQuery struct {
 	Statuses       []string  `json:"statuses"`
 	StartDate      time.Time `json:"start_date"`
 	EndDate        time.Time `json:"end_date"`
+	ConfirmedAfter time.Time `json:"confirmed_after"`
 	Pagination
 }
 
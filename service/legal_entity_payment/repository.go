@@ -168,6 +168,12 @@ func (r *PostgresRepository) filterForList(req models.ListLegalEntityPaymentQuer
 		fields += ")"
 	}
 
+	if !req.ConfirmedAfter.IsZero() {
+		fields += fmt.Sprintf(" AND confirm_payment_at > $%d", index)
+		index++
+		queryParams = append(queryParams, req.ConfirmedAfter)
+	}
+
 	fields += " ORDER BY created_at DESC"
 
 	if req.Limit > 0 {
@@ -27,6 +27,7 @@ type Service interface {
 	CreatePayment(ctx context.Context, payment models.LegalEntityPayment) (string, error)
 	GetPaymentByID(ctx context.Context, paymentID string) (models.LegalEntityPayment, error)
 	GetList(ctx context.Context, query models.ListLegalEntityPaymentQuery) ([]models.ListLegalEntityPayment, error)
+	GetConfirmedPayments(ctx context.Context, legalEntityID string, confirmedAfter time.Time) ([]models.LegalEntityPayment, error)
 	Update(ctx context.Context, payment models.UpdateLegalEntityPayment) error
 	Delete(ctx context.Context, paymentID string) error
 	GetLegalEntityPaymentAnalytics(ctx context.Context, query models.LegalEntityPaymentAnalyticsRequest) (models.LegalEntityPaymentAnalyticsResponse, error)
@@ -84,6 +85,15 @@ func (s *ServiceImpl) GetPaymentByID(ctx context.Context, paymentID string) (mod
 	return s.repo.GetByID(ctx, paymentID)
 }
 
+// GetConfirmedPayments - подтвержденные оплаты юр. лица, подтвержденные позже confirmedAfter
+func (s *ServiceImpl) GetConfirmedPayments(ctx context.Context, legalEntityID string, confirmedAfter time.Time) ([]models.LegalEntityPayment, error) {
+	return s.repo.GetList(ctx, models.ListLegalEntityPaymentQuery{
+		LegalEntityIDs: []string{legalEntityID},
+		Statuses:       []string{models.PAID_CONFIRMED.String()},
+		ConfirmedAfter: confirmedAfter,
+	})
+}
+
 func (s *ServiceImpl) GetList(ctx context.Context, query models.ListLegalEntityPaymentQuery) ([]models.ListLegalEntityPayment, error) {
 	allLegalEntities, err := s.legalEntityService.List(ctx, selector.Pagination{Limit: query.Pagination.Limit, Page: query.Pagination.Page - 1}, legalEntityModels.Filter{})
 	if err != nil {
@@ -19,6 +19,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 
 	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	menuDB "github.com/kwaaka-team/orders-core/core/menu/database/drivers/mongo"
 )
 
 const collectionMenuName = "menus"
@@ -34,6 +35,7 @@ type Repository interface {
 	BulkUpdateAttributesAvailability(ctx context.Context, menuId string, attributeIds []string, availability bool) error
 	BulkUpdateProductsAvailability(ctx context.Context, menuId string, productIds []string, availability bool) error
 	BulkUpdateProductsDisabledStatus(ctx context.Context, menuId string, productIds []string, isDisabled bool) error
+	BulkUpdateProductsDisableReason(ctx context.Context, menuId string, productIds []string, reason models.DisableReason, active bool) error
 	BulkUpdateAttributesDisabledStatus(ctx context.Context, menuId string, attributeIds []string, isDisabled bool) error
 	Insert(ctx context.Context, menu models.Menu) (string, error)
 	GetProductsByMenuIDAndExtIds(ctx context.Context, menuId string, productsExtIds []string) (models.Products, error)
@@ -67,11 +69,12 @@ type MongoRepository struct {
 	collection *mongo.Collection
 }
 
-func NewMenuMongoRepository(db *mongo.Database) (*MongoRepository, error) {
+// NewMenuMongoRepository returns repository, which saves version of menu after each write
+func NewMenuMongoRepository(db *mongo.Database) (Repository, error) {
 	r := MongoRepository{
 		collection: db.Collection(collectionMenuName),
 	}
-	return &r, nil
+	return newVersionedRepository(&r, menuDB.NewMenuVersionRepository(db)), nil
 }
 
 func (r *MongoRepository) updateTo(ctx context.Context, menu models.Menu) bson.D {
@@ -380,6 +383,82 @@ func (r *MongoRepository) BulkUpdateProductsDisabledStatus(ctx context.Context,
 	return nil
 }
 
+// BulkUpdateProductsDisableReason adds or removes reason and recalculates availability in one update,
+// products without disable_reasons get reasons from legacy flags
+func (r *MongoRepository) BulkUpdateProductsDisableReason(ctx context.Context, menuId string, productIds []string, reason models.DisableReason, active bool) error {
+	oid, err := primitive.ObjectIDFromHex(menuId)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.D{
+		{
+			Key:   "_id",
+			Value: oid,
+		},
+	}
+
+	legacyReasons := bson.M{
+		"$concatArrays": bson.A{
+			bson.M{"$cond": bson.A{"$$product.is_disabled", bson.A{models.DisableReasonAdmin}, bson.A{}}},
+			bson.M{"$cond": bson.A{"$$product.disabled_by_validation", bson.A{models.DisableReasonValidation}, bson.A{}}},
+			bson.M{"$cond": bson.A{
+				bson.M{"$or": bson.A{"$$product.available", "$$product.is_disabled", "$$product.disabled_by_validation"}},
+				bson.A{},
+				bson.A{models.DisableReasonPosStopList},
+			}},
+		},
+	}
+	currentReasons := bson.M{"$ifNull": bson.A{"$$product.disable_reasons", legacyReasons}}
+
+	newReasons := bson.M{"$setDifference": bson.A{currentReasons, bson.A{reason}}}
+	if active {
+		newReasons = bson.M{"$setUnion": bson.A{currentReasons, bson.A{reason}}}
+	}
+
+	updateProducts := func(fields bson.M) bson.D {
+		return bson.D{{Key: "$set", Value: bson.M{
+			"products": bson.M{
+				"$map": bson.M{
+					"input": "$products",
+					"as":    "product",
+					"in": bson.M{"$cond": bson.A{
+						bson.M{"$in": bson.A{"$$product.ext_id", productIds}},
+						bson.M{"$mergeObjects": bson.A{"$$product", fields}},
+						"$$product",
+					}},
+				},
+			},
+		}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		updateProducts(bson.M{"disable_reasons": newReasons}),
+		updateProducts(bson.M{
+			"available": bson.M{"$eq": bson.A{bson.M{"$size": "$$product.disable_reasons"}, 0}},
+			"is_disabled": bson.M{"$gt": bson.A{
+				bson.M{"$size": bson.M{"$setIntersection": bson.A{
+					"$$product.disable_reasons",
+					bson.A{models.DisableReasonAdmin, models.DisableReasonSchedule, models.DisableReasonSection, models.DisableReasonAvailabilityWindow},
+				}}},
+				0,
+			}},
+			"disabled_by_validation": bson.M{"$in": bson.A{models.DisableReasonValidation, "$$product.disable_reasons"}},
+		}),
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, pipeline)
+	if err != nil {
+		return errorSwitch(err)
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("matched count is equal 0, not found")
+	}
+
+	return nil
+}
+
 func (r *MongoRepository) GetCombosByMenuId(ctx context.Context, menuId string) ([]models.Combo, int64, error) {
 	query := selector.EmptyMenuSearch().SetMenuID(menuId)
 
@@ -68,8 +68,9 @@ func (s *Service) GenerateNewAggregatorMenuFromPosMenu(ctx context.Context, stor
 	return id, nil
 }
 
-func (s *Service) SetMarkupToAggregatorMenu(ctx context.Context, aggregatorMenuId, posMenuId, currencyCode string, markupPercent int) error {
-	posMenu, err := s.repo.FindById(ctx, posMenuId)
+// SetMarkupToAggregatorMenu проставляет в меню агрегатора цены pos с наценкой по правилам ресторана или markup_percent меню
+func (s *Service) SetMarkupToAggregatorMenu(ctx context.Context, store storeModels.Store, aggregatorMenuId string) error {
+	posMenu, err := s.repo.FindById(ctx, store.MenuID)
 	if err != nil {
 		return errors.Wrap(err, "pos menu not found")
 	}
@@ -89,6 +90,8 @@ func (s *Service) SetMarkupToAggregatorMenu(ctx context.Context, aggregatorMenuI
 		posMenuProductsMap[product.ExtID] = product.Price[0].Value
 	}
 
+	markup := coreMenuModels.NewMarkup(store, aggregatorMenuId, aggregatorMenu.Delivery, aggregatorMenu.Sections)
+
 	for i, product := range aggregatorMenu.Products {
 		id := product.ExtID
 		if product.PosID != "" {
@@ -100,10 +103,12 @@ func (s *Service) SetMarkupToAggregatorMenu(ctx context.Context, aggregatorMenuI
 			continue
 		}
 
+		price, _ := markup.Price(product, posPrice)
+
 		aggregatorMenu.Products[i].Price = []coreMenuModels.Price{
 			{
-				Value:        float64(int(posPrice) * (100 + markupPercent) / 100),
-				CurrencyCode: currencyCode,
+				Value:        price,
+				CurrencyCode: store.Settings.Currency,
 			},
 		}
 	}
@@ -115,6 +120,61 @@ func (s *Service) SetMarkupToAggregatorMenu(ctx context.Context, aggregatorMenuI
 	return nil
 }
 
+// PreviewMarkup показывает цены меню агрегатора после наценки, меню не меняется
+func (s *Service) PreviewMarkup(ctx context.Context, store storeModels.Store, aggregatorMenuId string) (coreMenuModels.MarkupPreview, error) {
+	posMenu, err := s.repo.FindById(ctx, store.MenuID)
+	if err != nil {
+		return coreMenuModels.MarkupPreview{}, errors.Wrap(err, "pos menu not found")
+	}
+
+	aggregatorMenu, err := s.repo.FindById(ctx, aggregatorMenuId)
+	if err != nil {
+		return coreMenuModels.MarkupPreview{}, errors.Wrap(err, "aggregator menu not found")
+	}
+
+	posProductsMap := s.getPosProductsMap(*posMenu)
+	markup := coreMenuModels.NewMarkup(store, aggregatorMenuId, aggregatorMenu.Delivery, aggregatorMenu.Sections)
+
+	preview := coreMenuModels.MarkupPreview{
+		MenuID:     aggregatorMenuId,
+		Aggregator: aggregatorMenu.Delivery,
+		Products:   make([]coreMenuModels.MarkupPreviewItem, 0, len(aggregatorMenu.Products)),
+	}
+
+	for _, product := range aggregatorMenu.Products {
+		if product.IsDeleted {
+			continue
+		}
+
+		id := product.ExtID
+		if product.PosID != "" {
+			id = product.PosID
+		}
+
+		posProduct, ok := posProductsMap[id]
+		if !ok || len(posProduct.Price) == 0 {
+			continue
+		}
+
+		item := coreMenuModels.MarkupPreviewItem{
+			ProductID: product.ExtID,
+			SectionID: product.Section,
+			PosPrice:  posProduct.Price[0].Value,
+		}
+		if len(product.Name) != 0 {
+			item.Name = product.Name[0].Value
+		}
+		if len(product.Price) != 0 {
+			item.CurrentPrice = product.Price[0].Value
+		}
+		item.NewPrice, item.Rule = markup.Price(product, item.PosPrice)
+
+		preview.Products = append(preview.Products, item)
+	}
+
+	return preview, nil
+}
+
 func (s *Service) UploadImagesInWoltFormat(ctx context.Context, menuId string) error {
 	systemMenu, err := s.repo.FindById(ctx, menuId)
 	if err != nil {
@@ -468,6 +528,10 @@ func (s *Service) UpdateProductsDisabledStatus(ctx context.Context, menuId strin
 	return s.repo.BulkUpdateProductsDisabledStatus(ctx, menuId, productIds, isDisabled)
 }
 
+func (s *Service) UpdateProductsDisableReason(ctx context.Context, menuId string, productIds []string, reason coreMenuModels.DisableReason, active bool) error {
+	return s.repo.BulkUpdateProductsDisableReason(ctx, menuId, productIds, reason, active)
+}
+
 func (s *Service) UpdateAttributesDisabledStatus(ctx context.Context, menuId string, attributeIds []string, isDisabled bool) error {
 	return s.repo.BulkUpdateAttributesDisabledStatus(ctx, menuId, attributeIds, isDisabled)
 }
@@ -681,6 +745,16 @@ func (s *Service) GenerateAggregatorMenuFromPosMenu(ctx context.Context, store s
 
 	products := s.matchAggregatorAndPosProducts(*aggregatorMenu, posProductsMap, nonDeliveryAttributeGroupIdsMap)
 
+	// новое меню еще не привязано к ресторану, markup_percent берется у меню, из которого оно генерируется
+	markup := coreMenuModels.NewMarkup(store, aggregatorMenuId, deliveryService, aggregatorMenu.Sections)
+	for i := range products {
+		price, _ := markup.Price(products[i], products[i].Price[0].Value)
+		products[i].Price = []coreMenuModels.Price{{
+			Value:        price,
+			CurrencyCode: products[i].Price[0].CurrencyCode,
+		}}
+	}
+
 	used := s.getUsedAttributeGroupsMapInProducts(*aggregatorMenu, posProductsMap)
 
 	attributeGroups := s.removeUnnecessaryAttributeGroups(posMenu.AttributesGroups, used, posAttributesMap)
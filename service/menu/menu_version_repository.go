@@ -0,0 +1,223 @@
+package menu
+
+import (
+	"context"
+
+	"github.com/kwaaka-team/orders-core/core/menu/models"
+	entityChangesHistoryModels "github.com/kwaaka-team/orders-core/service/entity_changes_history/models"
+)
+
+type menuVersionSaver interface {
+	Save(ctx context.Context, menuID string, history entityChangesHistoryModels.EntityChangesHistory) string
+}
+
+// versionedRepository сохраняет версию меню после каждой успешной записи, в том числе после коммита транзакции.
+// Записи только доступности (стоп-лист, выключение продуктов и атрибутов, причины выключения) версию не создают:
+// они частые, вытесняли бы из лимита версии с изменениями меню, а откат доступность все равно не восстанавливает
+type versionedRepository struct {
+	Repository
+	versions menuVersionSaver
+}
+
+func newVersionedRepository(repo Repository, versions menuVersionSaver) *versionedRepository {
+	return &versionedRepository{
+		Repository: repo,
+		versions:   versions,
+	}
+}
+
+func (r *versionedRepository) save(ctx context.Context, callFunction string, menuIDs ...string) {
+	for _, menuID := range menuIDs {
+		if menuID == "" {
+			continue
+		}
+		r.versions.Save(ctx, menuID, entityChangesHistoryModels.EntityChangesHistory{
+			CallFunction: callFunction,
+		})
+	}
+}
+
+func (r *versionedRepository) Insert(ctx context.Context, menu models.Menu) (string, error) {
+	id, err := r.Repository.Insert(ctx, menu)
+	if err != nil {
+		return "", err
+	}
+	r.save(ctx, "Insert", id)
+	return id, nil
+}
+
+func (r *versionedRepository) UpdateMenuEntities(ctx context.Context, menuId string, menu models.Menu) error {
+	if err := r.Repository.UpdateMenuEntities(ctx, menuId, menu); err != nil {
+		return err
+	}
+	r.save(ctx, "UpdateMenuEntities", menuId)
+	return nil
+}
+
+func (r *versionedRepository) BulkUpdateAttributesIsDeleted(ctx context.Context, menuId string, attributeIds []string, isDeleted bool, reason string) error {
+	if err := r.Repository.BulkUpdateAttributesIsDeleted(ctx, menuId, attributeIds, isDeleted, reason); err != nil {
+		return err
+	}
+	r.save(ctx, "BulkUpdateAttributesIsDeleted", menuId)
+	return nil
+}
+
+func (r *versionedRepository) BulkUpdateProductsIsDeleted(ctx context.Context, menuId string, productIds []string, isDeleted bool, reason string) error {
+	if err := r.Repository.BulkUpdateProductsIsDeleted(ctx, menuId, productIds, isDeleted, reason); err != nil {
+		return err
+	}
+	r.save(ctx, "BulkUpdateProductsIsDeleted", menuId)
+	return nil
+}
+
+func (r *versionedRepository) UpdateProductsImageAndDescription(ctx context.Context, menuID string, req []models.UpdateProductImageAndDescription) error {
+	if err := r.Repository.UpdateProductsImageAndDescription(ctx, menuID, req); err != nil {
+		return err
+	}
+	r.save(ctx, "UpdateProductsImageAndDescription", menuID)
+	return nil
+}
+
+func (r *versionedRepository) AddNameInProduct(ctx context.Context, req models.AddLanguageDescriptionRequest) error {
+	if err := r.Repository.AddNameInProduct(ctx, req); err != nil {
+		return err
+	}
+	r.save(ctx, "AddNameInProduct", req.MenuID, req.PosMenuID)
+	return nil
+}
+
+func (r *versionedRepository) AddDescriptionInProduct(ctx context.Context, req models.AddLanguageDescriptionRequest) error {
+	if err := r.Repository.AddDescriptionInProduct(ctx, req); err != nil {
+		return err
+	}
+	r.save(ctx, "AddDescriptionInProduct", req.MenuID, req.PosMenuID)
+	return nil
+}
+
+func (r *versionedRepository) AddNameInSection(ctx context.Context, req models.AddLanguageDescriptionRequest) error {
+	if err := r.Repository.AddNameInSection(ctx, req); err != nil {
+		return err
+	}
+	r.save(ctx, "AddNameInSection", req.MenuID, req.PosMenuID)
+	return nil
+}
+
+func (r *versionedRepository) AddDescriptionInSection(ctx context.Context, req models.AddLanguageDescriptionRequest) error {
+	if err := r.Repository.AddDescriptionInSection(ctx, req); err != nil {
+		return err
+	}
+	r.save(ctx, "AddDescriptionInSection", req.MenuID, req.PosMenuID)
+	return nil
+}
+
+func (r *versionedRepository) AddNameInAttributeGroup(ctx context.Context, req models.AddLanguageDescriptionRequest) error {
+	if err := r.Repository.AddNameInAttributeGroup(ctx, req); err != nil {
+		return err
+	}
+	r.save(ctx, "AddNameInAttributeGroup", req.MenuID, req.PosMenuID)
+	return nil
+}
+
+func (r *versionedRepository) AddNameInAttribute(ctx context.Context, req models.AddLanguageDescriptionRequest) error {
+	if err := r.Repository.AddNameInAttribute(ctx, req); err != nil {
+		return err
+	}
+	r.save(ctx, "AddNameInAttribute", req.MenuID, req.PosMenuID)
+	return nil
+}
+
+func (r *versionedRepository) ChangeNameInProduct(ctx context.Context, req models.AddLanguageDescriptionRequest) error {
+	if err := r.Repository.ChangeNameInProduct(ctx, req); err != nil {
+		return err
+	}
+	r.save(ctx, "ChangeNameInProduct", req.MenuID, req.PosMenuID)
+	return nil
+}
+
+func (r *versionedRepository) ChangeDescriptionInProduct(ctx context.Context, req models.AddLanguageDescriptionRequest) error {
+	if err := r.Repository.ChangeDescriptionInProduct(ctx, req); err != nil {
+		return err
+	}
+	r.save(ctx, "ChangeDescriptionInProduct", req.MenuID, req.PosMenuID)
+	return nil
+}
+
+func (r *versionedRepository) ChangeNameInSection(ctx context.Context, req models.AddLanguageDescriptionRequest) error {
+	if err := r.Repository.ChangeNameInSection(ctx, req); err != nil {
+		return err
+	}
+	r.save(ctx, "ChangeNameInSection", req.MenuID, req.PosMenuID)
+	return nil
+}
+
+func (r *versionedRepository) ChangeDescriptionInSection(ctx context.Context, req models.AddLanguageDescriptionRequest) error {
+	if err := r.Repository.ChangeDescriptionInSection(ctx, req); err != nil {
+		return err
+	}
+	r.save(ctx, "ChangeDescriptionInSection", req.MenuID, req.PosMenuID)
+	return nil
+}
+
+func (r *versionedRepository) ChangeNameInAttributeGroup(ctx context.Context, req models.AddLanguageDescriptionRequest) error {
+	if err := r.Repository.ChangeNameInAttributeGroup(ctx, req); err != nil {
+		return err
+	}
+	r.save(ctx, "ChangeNameInAttributeGroup", req.MenuID, req.PosMenuID)
+	return nil
+}
+
+func (r *versionedRepository) ChangeNameInAttribute(ctx context.Context, req models.AddLanguageDescriptionRequest) error {
+	if err := r.Repository.ChangeNameInAttribute(ctx, req); err != nil {
+		return err
+	}
+	r.save(ctx, "ChangeNameInAttribute", req.MenuID, req.PosMenuID)
+	return nil
+}
+
+func (r *versionedRepository) AddRegulatoryInformation(ctx context.Context, req models.RegulatoryInformationRequest) error {
+	if err := r.Repository.AddRegulatoryInformation(ctx, req); err != nil {
+		return err
+	}
+	r.save(ctx, "AddRegulatoryInformation", req.MenuID, req.PosMenuID)
+	return nil
+}
+
+func (r *versionedRepository) ChangeRegulatoryInformation(ctx context.Context, req models.RegulatoryInformationRequest) error {
+	if err := r.Repository.ChangeRegulatoryInformation(ctx, req); err != nil {
+		return err
+	}
+	r.save(ctx, "ChangeRegulatoryInformation", req.MenuID, req.PosMenuID)
+	return nil
+}
+
+func (r *versionedRepository) DeleteAttributesFromAttributeGroup(ctx context.Context, menuID string, attributeIDs []string) error {
+	if err := r.Repository.DeleteAttributesFromAttributeGroup(ctx, menuID, attributeIDs); err != nil {
+		return err
+	}
+	r.save(ctx, "DeleteAttributesFromAttributeGroup", menuID)
+	return nil
+}
+
+func (r *versionedRepository) UpdateExcludedFromMenuProduct(ctx context.Context, menuID string, productIDs []string) error {
+	if err := r.Repository.UpdateExcludedFromMenuProduct(ctx, menuID, productIDs); err != nil {
+		return err
+	}
+	r.save(ctx, "UpdateExcludedFromMenuProduct", menuID)
+	return nil
+}
+
+func (r *versionedRepository) DeleteAttrGroupFromProduct(ctx context.Context, menuID, productID, attrGroupID string) error {
+	if err := r.Repository.DeleteAttrGroupFromProduct(ctx, menuID, productID, attrGroupID); err != nil {
+		return err
+	}
+	r.save(ctx, "DeleteAttrGroupFromProduct", menuID)
+	return nil
+}
+
+func (r *versionedRepository) UpdateAttributesPrice(ctx context.Context, menuID string, req []models.UpdateAttributePrice) error {
+	if err := r.Repository.UpdateAttributesPrice(ctx, menuID, req); err != nil {
+		return err
+	}
+	r.save(ctx, "UpdateAttributesPrice", menuID)
+	return nil
+}
@@ -0,0 +1,86 @@
+package menu
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kwaaka-team/orders-core/core/menu/models"
+	entityChangesHistoryModels "github.com/kwaaka-team/orders-core/service/entity_changes_history/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type menuVersionSaverStub struct {
+	saved []string
+}
+
+func (s *menuVersionSaverStub) Save(ctx context.Context, menuID string, history entityChangesHistoryModels.EntityChangesHistory) string {
+	s.saved = append(s.saved, menuID+":"+history.CallFunction)
+	return menuID
+}
+
+type writeRepositoryStub struct {
+	Repository
+	err error
+}
+
+func (r writeRepositoryStub) BulkUpdateProductsDisableReason(ctx context.Context, menuId string, productIds []string, reason models.DisableReason, active bool) error {
+	return r.err
+}
+
+func (r writeRepositoryStub) BulkUpdateProductsIsDeleted(ctx context.Context, menuId string, productIds []string, isDeleted bool, reason string) error {
+	return r.err
+}
+
+func (r writeRepositoryStub) ChangeNameInProduct(ctx context.Context, req models.AddLanguageDescriptionRequest) error {
+	return r.err
+}
+
+func TestVersionedRepository(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		write     func(repo Repository) error
+		wantSaved []string
+	}{
+		{
+			name: "bulk write saves version of menu",
+			write: func(repo Repository) error {
+				return repo.BulkUpdateProductsIsDeleted(context.Background(), "menu", []string{"burger"}, true, "removed from pos")
+			},
+			wantSaved: []string{"menu:BulkUpdateProductsIsDeleted"},
+		},
+		{
+			name: "availability write does not save version",
+			write: func(repo Repository) error {
+				return repo.BulkUpdateProductsDisableReason(context.Background(), "menu", []string{"burger"}, models.DisableReasonAdmin, true)
+			},
+		},
+		{
+			name: "write to aggregator and pos menu saves both versions",
+			write: func(repo Repository) error {
+				return repo.ChangeNameInProduct(context.Background(), models.AddLanguageDescriptionRequest{MenuID: "menu", PosMenuID: "pos_menu"})
+			},
+			wantSaved: []string{"menu:ChangeNameInProduct", "pos_menu:ChangeNameInProduct"},
+		},
+		{
+			name: "failed write does not save version",
+			err:  errors.New("matched count is equal 0, not found"),
+			write: func(repo Repository) error {
+				return repo.BulkUpdateProductsIsDeleted(context.Background(), "menu", []string{"burger"}, true, "removed from pos")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versions := &menuVersionSaverStub{}
+			repo := newVersionedRepository(writeRepositoryStub{err: tt.err}, versions)
+
+			err := tt.write(repo)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantSaved, versions.saved)
+		})
+	}
+}
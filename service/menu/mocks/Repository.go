@@ -231,6 +231,24 @@ func (_m *Repository) BulkUpdateProductsDisabledStatus(ctx context.Context, menu
 	return r0
 }
 
+// BulkUpdateProductsDisableReason provides a mock function with given fields: ctx, menuId, productIds, reason, active
+func (_m *Repository) BulkUpdateProductsDisableReason(ctx context.Context, menuId string, productIds []string, reason models.DisableReason, active bool) error {
+	ret := _m.Called(ctx, menuId, productIds, reason, active)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkUpdateProductsDisableReason")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, models.DisableReason, bool) error); ok {
+		r0 = rf(ctx, menuId, productIds, reason, active)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // BulkUpdateProductsIsDeleted provides a mock function with given fields: ctx, menuId, productIds, isDeleted, reason
 func (_m *Repository) BulkUpdateProductsIsDeleted(ctx context.Context, menuId string, productIds []string, isDeleted bool, reason string) error {
 	ret := _m.Called(ctx, menuId, productIds, isDeleted, reason)
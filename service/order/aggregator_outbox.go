@@ -0,0 +1,232 @@
+package order
+
+import (
+	"context"
+	"github.com/kwaaka-team/orders-core/core/models"
+	coreStoreModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/pkg/que"
+	"github.com/kwaaka-team/orders-core/service/aggregator"
+	"github.com/kwaaka-team/orders-core/service/order/outbox"
+	"github.com/kwaaka-team/orders-core/service/store"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+const (
+	dueOrdersLimit = 200
+	// claimLease - how long claimed message is not picked by other dispatchers, must be longer than aggregator request timeout
+	claimLease = 2 * time.Minute
+)
+
+type AggregatorOutboxService interface {
+	DispatchOrder(ctx context.Context, orderID string) error
+	DispatchDue(ctx context.Context) error
+	ListMessages(ctx context.Context, query outbox.ListQuery) ([]outbox.Message, int64, error)
+	Requeue(ctx context.Context, id string) error
+}
+
+type AggregatorOutboxServiceImpl struct {
+	outboxRepository  outbox.Repository
+	orderRepository   Repository
+	storeService      store.Service
+	aggregatorFactory aggregator.Factory
+}
+
+func NewAggregatorOutboxService(outboxRepository outbox.Repository, orderRepository Repository, storeService store.Service, aggregatorFactory aggregator.Factory) (*AggregatorOutboxServiceImpl, error) {
+	if outboxRepository == nil {
+		return nil, errors.Wrap(errConstructor, "outbox repository is nil")
+	}
+	if orderRepository == nil {
+		return nil, errors.Wrap(errConstructor, "order repository is nil")
+	}
+	if storeService == nil {
+		return nil, errors.Wrap(errConstructor, "store service is nil")
+	}
+	if aggregatorFactory == nil {
+		return nil, errors.Wrap(errConstructor, "aggregator factory is nil")
+	}
+
+	return &AggregatorOutboxServiceImpl{
+		outboxRepository:  outboxRepository,
+		orderRepository:   orderRepository,
+		storeService:      storeService,
+		aggregatorFactory: aggregatorFactory,
+	}, nil
+}
+
+// DispatchOrder sends pending statuses of the order one by one, in order of creation.
+// If status could not be sent, next statuses wait for it, so aggregator never receives them in wrong order.
+// Each message is claimed before sending, so queue consumer and cron do not send the same status twice
+func (s *AggregatorOutboxServiceImpl) DispatchOrder(ctx context.Context, orderID string) error {
+	messages, err := s.outboxRepository.FindPendingByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	order, err := s.orderRepository.FindOrderByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	st, err := s.storeService.GetByID(ctx, order.RestaurantID)
+	if err != nil {
+		return err
+	}
+
+	aggregatorService, err := s.aggregatorFactory.GetAggregator(order.DeliveryService, st)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, message := range messages {
+		if message.NextAttemptAt.After(now) {
+			return nil
+		}
+
+		claimed, err := s.outboxRepository.Claim(ctx, message.ID, now, claimLease)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			log.Info().Msgf("outbox message %s is sent or claimed by other dispatcher, order_id=%s", message.ID, orderID)
+			return nil
+		}
+
+		if err = s.dispatch(ctx, aggregatorService, order, st, message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *AggregatorOutboxServiceImpl) dispatch(ctx context.Context, aggregatorService aggregator.Aggregator, order models.Order, st coreStoreModels.Store, message outbox.Message) error {
+	sendErr := aggregatorService.UpdateOrderInAggregator(ctx, order, st, message.AggregatorStatus)
+	if sendErr == nil {
+		log.Info().Msgf("outbox message %s sent, order_id=%s, delivery_service=%s, status=%s", message.ID, order.ID, message.DeliveryService, message.AggregatorStatus)
+		return s.outboxRepository.MarkSent(ctx, message.ID)
+	}
+
+	attempts := message.Attempts + 1
+	policy := outbox.PolicyFor(message.DeliveryService)
+
+	if policy.IsExhausted(attempts) {
+		log.Err(sendErr).Msgf("outbox message %s moved to dead letter after %d attempts, order_id=%s, delivery_service=%s, status=%s", message.ID, attempts, order.ID, message.DeliveryService, message.AggregatorStatus)
+		return s.outboxRepository.MarkDeadLetter(ctx, message.ID, attempts, sendErr.Error())
+	}
+
+	nextAttemptAt := policy.NextAttemptAt(attempts, time.Now().UTC())
+	if err := s.outboxRepository.MarkRetry(ctx, message.ID, attempts, nextAttemptAt, sendErr.Error()); err != nil {
+		return err
+	}
+
+	return errors.Wrapf(sendErr, "outbox message %s, attempt %d, next attempt at %s", message.ID, attempts, nextAttemptAt.Format(time.RFC3339))
+}
+
+func (s *AggregatorOutboxServiceImpl) DispatchDue(ctx context.Context) error {
+	orderIDs, err := s.outboxRepository.FindDueOrderIDs(ctx, time.Now().UTC(), dueOrdersLimit)
+	if err != nil {
+		return err
+	}
+
+	for _, orderID := range orderIDs {
+		if err = s.DispatchOrder(ctx, orderID); err != nil {
+			log.Err(err).Msgf("dispatch aggregator outbox, order_id=%s", orderID)
+		}
+	}
+
+	return nil
+}
+
+func (s *AggregatorOutboxServiceImpl) ListMessages(ctx context.Context, query outbox.ListQuery) ([]outbox.Message, int64, error) {
+	return s.outboxRepository.List(ctx, query)
+}
+
+func (s *AggregatorOutboxServiceImpl) Requeue(ctx context.Context, id string) error {
+	message, err := s.outboxRepository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if message.State != outbox.StateDeadLetter {
+		return errors.Errorf("outbox message %s is in %s state, only %s can be requeued", id, message.State, outbox.StateDeadLetter)
+	}
+
+	if err = s.outboxRepository.Requeue(ctx, id); err != nil {
+		return err
+	}
+
+	return s.DispatchOrder(ctx, message.OrderID)
+}
+
+// AggregatorOutboxSubscriber triggers dispatching of outbox after order status update.
+// If queue url is empty, outbox is dispatched in place
+type AggregatorOutboxSubscriber struct {
+	outboxService AggregatorOutboxService
+	sqsCli        que.SQSInterface
+	queueUrl      string
+}
+
+func NewAggregatorOutboxSubscriber(outboxService AggregatorOutboxService, sqsCli que.SQSInterface, queueUrl string) (*AggregatorOutboxSubscriber, error) {
+	if outboxService == nil {
+		return nil, errors.Wrap(errConstructor, "aggregator outbox service is nil")
+	}
+
+	return &AggregatorOutboxSubscriber{
+		outboxService: outboxService,
+		sqsCli:        sqsCli,
+		queueUrl:      queueUrl,
+	}, nil
+}
+
+func (s *AggregatorOutboxSubscriber) SendOrder(ctx context.Context, order models.Order, store coreStoreModels.Store, posStatus models.PosStatus) error {
+	if s.sqsCli != nil && s.queueUrl != "" {
+		err := s.sqsCli.SendSQSMessage(ctx, s.queueUrl, order.ID)
+		if err == nil {
+			return nil
+		}
+		log.Err(err).Msgf("send aggregator outbox message to queue, order_id=%s, dispatching in place", order.ID)
+	}
+
+	return s.outboxService.DispatchOrder(ctx, order.ID)
+}
+
+// enqueueAggregatorStatus saves order status together with status push to aggregator in outbox and triggers outbox dispatch,
+// aggregator is not called in place, so status is not lost if aggregator is unavailable
+func enqueueAggregatorStatus(ctx context.Context, repository Repository, outboxPublisher *Publisher, order models.Order, store coreStoreModels.Store, systemStatus models.PosStatus, aggregatorStatus string) error {
+	message := outbox.NewMessage(order.ID, order.OrderID, store.ID, order.DeliveryService, systemStatus.String(), aggregatorStatus)
+	if err := repository.UpdateOrderStatusWithOutbox(ctx, order.ID, systemStatus.String(), message); err != nil {
+		return err
+	}
+
+	notifyOutbox(ctx, outboxPublisher, order, store, systemStatus)
+	return nil
+}
+
+// enqueueAggregatorPush saves status push to aggregator in outbox when order status in system is not changed
+func enqueueAggregatorPush(ctx context.Context, repository Repository, outboxPublisher *Publisher, order models.Order, store coreStoreModels.Store, systemStatus models.PosStatus, aggregatorStatus string) error {
+	message := outbox.NewMessage(order.ID, order.OrderID, store.ID, order.DeliveryService, systemStatus.String(), aggregatorStatus)
+	if err := repository.EnqueueAggregatorStatus(ctx, message); err != nil {
+		return err
+	}
+
+	notifyOutbox(ctx, outboxPublisher, order, store, systemStatus)
+	return nil
+}
+
+// notifyOutbox triggers outbox dispatch, without publisher message is sent by aggregator outbox cron
+func notifyOutbox(ctx context.Context, outboxPublisher *Publisher, order models.Order, store coreStoreModels.Store, systemStatus models.PosStatus) {
+	if outboxPublisher == nil {
+		return
+	}
+
+	if err := outboxPublisher.NotifySubscribers(ctx, order, store, systemStatus); err != nil {
+		log.Err(err).Msgf("trigger aggregator outbox dispatch, order_id=%s", order.ID)
+	}
+}
@@ -0,0 +1,279 @@
+package order
+
+import (
+	"context"
+	"github.com/kwaaka-team/orders-core/core/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/aggregator"
+	"github.com/kwaaka-team/orders-core/service/order/outbox"
+	"github.com/kwaaka-team/orders-core/service/store/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"sync"
+	"testing"
+	"time"
+)
+
+type outboxRepositoryStub struct {
+	outbox.Repository
+	mu       sync.Mutex
+	messages []outbox.Message
+}
+
+func (r *outboxRepositoryStub) FindPendingByOrderID(ctx context.Context, orderID string) ([]outbox.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make([]outbox.Message, 0)
+	for _, message := range r.messages {
+		if message.OrderID == orderID && message.State == outbox.StatePending {
+			res = append(res, message)
+		}
+	}
+	return res, nil
+}
+
+func (r *outboxRepositoryStub) Claim(ctx context.Context, id string, now time.Time, lease time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	message := r.find(id)
+	if message.State != outbox.StatePending || message.NextAttemptAt.After(now) || message.LockedUntil.After(now) {
+		return false, nil
+	}
+	message.LockedUntil = now.Add(lease)
+	return true, nil
+}
+
+func (r *outboxRepositoryStub) MarkSent(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	message := r.find(id)
+	message.State = outbox.StateSent
+	message.LockedUntil = time.Time{}
+	return nil
+}
+
+func (r *outboxRepositoryStub) MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastErr string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	message := r.find(id)
+	message.Attempts = attempts
+	message.NextAttemptAt = nextAttemptAt
+	message.LockedUntil = time.Time{}
+	return nil
+}
+
+func (r *outboxRepositoryStub) find(id string) *outbox.Message {
+	for i := range r.messages {
+		if r.messages[i].ID == id {
+			return &r.messages[i]
+		}
+	}
+	return &outbox.Message{}
+}
+
+type orderRepositoryStub struct {
+	Repository
+	order models.Order
+}
+
+func (r orderRepositoryStub) FindOrderByID(ctx context.Context, id string) (models.Order, error) {
+	return r.order, nil
+}
+
+type aggregatorFactoryStub struct {
+	aggregator aggregator.Aggregator
+}
+
+func (f aggregatorFactoryStub) GetAggregator(aggName string, store storeModels.Store) (aggregator.Aggregator, error) {
+	return f.aggregator, nil
+}
+
+type aggregatorStub struct {
+	aggregator.Aggregator
+	mu   sync.Mutex
+	sent []string
+	err  error
+}
+
+func (a *aggregatorStub) UpdateOrderInAggregator(ctx context.Context, order models.Order, store storeModels.Store, aggregatorStatus string) error {
+	time.Sleep(10 * time.Millisecond)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.err != nil {
+		return a.err
+	}
+	a.sent = append(a.sent, aggregatorStatus)
+	return nil
+}
+
+func newOutboxServiceForTest(t *testing.T, agg *aggregatorStub) (*AggregatorOutboxServiceImpl, *outboxRepositoryStub) {
+	now := time.Now().UTC().Add(-time.Minute)
+	repo := &outboxRepositoryStub{messages: []outbox.Message{
+		{ID: "1", OrderID: "order", DeliveryService: models.GLOVO.String(), AggregatorStatus: "ACCEPTED", State: outbox.StatePending, NextAttemptAt: now},
+		{ID: "2", OrderID: "order", DeliveryService: models.GLOVO.String(), AggregatorStatus: "READY", State: outbox.StatePending, NextAttemptAt: now},
+	}}
+
+	storeService := &mocks.Service{}
+	storeService.On("GetByID", mock.Anything, "store").Return(storeModels.Store{ID: "store"}, nil)
+
+	s, err := NewAggregatorOutboxService(repo, orderRepositoryStub{order: models.Order{ID: "order", RestaurantID: "store", DeliveryService: models.GLOVO.String()}}, storeService, aggregatorFactoryStub{aggregator: agg})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, repo
+}
+
+func TestAggregatorOutboxService_DispatchOrder_Concurrent(t *testing.T) {
+	agg := &aggregatorStub{}
+	s, repo := newOutboxServiceForTest(t, agg)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, s.DispatchOrder(context.Background(), "order"))
+		}()
+	}
+	wg.Wait()
+
+	// dispatchers, which lost the claim, stop and leave the rest to the winner or next run
+	assert.NoError(t, s.DispatchOrder(context.Background(), "order"))
+
+	assert.Equal(t, []string{"ACCEPTED", "READY"}, agg.sent)
+	for _, message := range repo.messages {
+		assert.Equal(t, outbox.StateSent, message.State)
+	}
+}
+
+func TestAggregatorOutboxService_DispatchOrder_RetryKeepsOrder(t *testing.T) {
+	agg := &aggregatorStub{err: errors.New("aggregator unavailable")}
+	s, repo := newOutboxServiceForTest(t, agg)
+
+	assert.Error(t, s.DispatchOrder(context.Background(), "order"))
+
+	assert.Empty(t, agg.sent)
+	assert.Equal(t, 1, repo.messages[0].Attempts)
+	assert.True(t, repo.messages[0].NextAttemptAt.After(time.Now().UTC()))
+	assert.True(t, repo.messages[0].LockedUntil.IsZero())
+	assert.Equal(t, 0, repo.messages[1].Attempts)
+}
+
+type enqueueRepositoryStub struct {
+	Repository
+	statuses []string
+	messages []outbox.Message
+}
+
+func (r *enqueueRepositoryStub) UpdateOrderStatusWithOutbox(ctx context.Context, orderID string, newStatus string, message outbox.Message) error {
+	r.statuses = append(r.statuses, newStatus)
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+func (r *enqueueRepositoryStub) EnqueueAggregatorStatus(ctx context.Context, message outbox.Message) error {
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+func (r *enqueueRepositoryStub) UpdateOrderStatusByID(ctx context.Context, orderID string, newStatus string) error {
+	r.statuses = append(r.statuses, newStatus)
+	return nil
+}
+
+func (r *enqueueRepositoryStub) UpdateOrderDeferStatus(ctx context.Context, status bool, id string) error {
+	return nil
+}
+
+type statusMappingAggregatorStub struct {
+	*aggregatorStub
+}
+
+func (a statusMappingAggregatorStub) MapSystemStatusToAggregatorStatus(order models.Order, posStatus models.PosStatus, store storeModels.Store) string {
+	return "agg_" + posStatus.String()
+}
+
+func TestAggregatorStatusPaths_EnqueueToOutbox(t *testing.T) {
+	st := storeModels.Store{ID: "store", DeferSubmission: storeModels.DeferSubmission{DefaultTime: -60, BusyTime: 60}}
+	order := models.Order{ID: "order", OrderID: "glovo_order", DeliveryService: models.GLOVO.String(), OrderTime: models.TransactionTime{Value: models.Time{Time: time.Now().UTC()}}}
+
+	tests := []struct {
+		name             string
+		run              func(s *ServiceImpl, e *EmptyPosService, agg aggregator.Aggregator) error
+		wantStatuses     []string
+		wantAggregatorSt string
+	}{
+		{
+			name: "auto accept",
+			run: func(s *ServiceImpl, e *EmptyPosService, agg aggregator.Aggregator) error {
+				_, err := s.acceptOrder(context.Background(), agg, order, st)
+				return err
+			},
+			wantStatuses:     []string{models.ACCEPTED.String()},
+			wantAggregatorSt: "agg_" + models.ACCEPTED.String(),
+		},
+		{
+			name: "wait sending",
+			run: func(s *ServiceImpl, e *EmptyPosService, agg aggregator.Aggregator) error {
+				return s.waitSendingOrder(context.Background(), order, st)
+			},
+			wantStatuses:     []string{models.WAIT_SENDING.String()},
+			wantAggregatorSt: "agg_" + models.WAIT_SENDING.String(),
+		},
+		{
+			name: "deferred order ready",
+			run: func(s *ServiceImpl, e *EmptyPosService, agg aggregator.Aggregator) error {
+				return s.sendOrderReadyStatusToAgg(context.Background(), order, st)
+			},
+			wantAggregatorSt: models.READY_FOR_PICKUP.String(),
+		},
+		{
+			name: "empty pos auto accept",
+			run: func(s *ServiceImpl, e *EmptyPosService, agg aggregator.Aggregator) error {
+				return e.updateStatus(context.Background(), agg, order, st, models.ACCEPTED)
+			},
+			wantStatuses:     []string{models.ACCEPTED.String()},
+			wantAggregatorSt: "agg_" + models.ACCEPTED.String(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agg := statusMappingAggregatorStub{aggregatorStub: &aggregatorStub{}}
+			repo := &enqueueRepositoryStub{}
+			subscriber := &completedSubscriberStub{}
+			outboxPublisher := &Publisher{}
+			outboxPublisher.AddSubscriber(subscriber)
+
+			s := &ServiceImpl{
+				repository:        repo,
+				aggregatorFactory: aggregatorFactoryStub{aggregator: agg},
+				outboxPublisher:   outboxPublisher,
+			}
+			e := &EmptyPosService{
+				repository:        repo,
+				aggregatorFactory: aggregatorFactoryStub{aggregator: agg},
+				outboxPublisher:   outboxPublisher,
+			}
+
+			assert.NoError(t, tt.run(s, e, agg))
+
+			assert.Empty(t, agg.sent, "aggregator must not be called in place")
+			assert.Equal(t, tt.wantStatuses, repo.statuses)
+			if assert.Len(t, repo.messages, 1) {
+				assert.Equal(t, tt.wantAggregatorSt, repo.messages[0].AggregatorStatus)
+				assert.Equal(t, "order", repo.messages[0].OrderID)
+				assert.Equal(t, outbox.StatePending, repo.messages[0].State)
+			}
+			assert.Len(t, subscriber.orders, 1, "outbox dispatch must be triggered")
+		})
+	}
+}
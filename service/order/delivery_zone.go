@@ -0,0 +1,29 @@
+package order
+
+import (
+	"math"
+
+	"github.com/kwaaka-team/orders-core/core/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+)
+
+// applyDeliveryZone - цена доставки заказа qr menu и kwaaka admin по зоне доставки 3pl ресторана вместо цены из запроса клиента,
+// без зон - по настройкам доставки ресторана
+func applyDeliveryZone(order models.Order, store storeModels.Store) (models.Order, error) {
+	if !store.Kwaaka3PL.Is3pl || !order.SendCourier {
+		return order, nil
+	}
+
+	quote, err := store.Kwaaka3PL.CheckoutQuote(storeModels.Coordinates{
+		Latitude:  order.DeliveryAddress.Latitude,
+		Longitude: order.DeliveryAddress.Longitude,
+	}, order.EstimatedTotalPrice.Value, order.ClientDeliveryPrice)
+	if err != nil {
+		return order, err
+	}
+
+	order.ClientDeliveryPrice = quote.ClientDeliveryPrice
+	order.RestaurantPayDeliveryPrice = math.Max(order.FullDeliveryPrice-quote.ClientDeliveryPrice, 0)
+
+	return order, nil
+}
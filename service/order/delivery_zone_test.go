@@ -0,0 +1,106 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/kwaaka-team/orders-core/core/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyDeliveryZone(t *testing.T) {
+	store := storeModels.Store{
+		Kwaaka3PL: storeModels.Kwaaka3PL{
+			Is3pl: true,
+			Polygons: []storeModels.Polygon{
+				{ID: "city", CPO: 1500, PolygonBasedFixedDeliveryPriceForClient: true, FixedDeliveryPriceForClient: 500, MinBasketForOrder: 3000, Coordinates: []storeModels.Coordinates{
+					{Latitude: 43.0, Longitude: 76.0},
+					{Latitude: 43.0, Longitude: 76.5},
+					{Latitude: 43.5, Longitude: 76.5},
+					{Latitude: 43.5, Longitude: 76.0},
+				}},
+			},
+		},
+	}
+
+	newOrder := func(lat, lon, basket float64, sendCourier bool) models.Order {
+		return models.Order{
+			SendCourier:         sendCourier,
+			DeliveryAddress:     models.DeliveryAddress{Latitude: lat, Longitude: lon},
+			EstimatedTotalPrice: models.Price{Value: basket},
+			FullDeliveryPrice:   1800,
+			ClientDeliveryPrice: 0,
+		}
+	}
+
+	tests := []struct {
+		name                string
+		order               models.Order
+		store               storeModels.Store
+		clientDeliveryPrice float64
+		restaurantPay       float64
+		expectedErr         error
+	}{
+		{
+			name:                "client pays zone fee instead of requested price",
+			order:               newOrder(43.1, 76.1, 5000, true),
+			store:               store,
+			clientDeliveryPrice: 500,
+			restaurantPay:       1300,
+		},
+		{
+			name:        "address out of zones is rejected",
+			order:       newOrder(51.1, 71.4, 5000, true),
+			store:       store,
+			expectedErr: storeModels.ErrOutOfDeliveryZone,
+		},
+		{
+			name:        "basket below zone min basket is rejected",
+			order:       newOrder(43.1, 76.1, 1000, true),
+			store:       store,
+			expectedErr: storeModels.ErrMinBasketNotReached,
+		},
+		{
+			name:                "store without zones charges store cpo",
+			order:               newOrder(51.1, 71.4, 1000, true),
+			store:               storeModels.Store{Kwaaka3PL: storeModels.Kwaaka3PL{Is3pl: true, CPO: 700}},
+			clientDeliveryPrice: 700,
+			restaurantPay:       1100,
+		},
+		{
+			name: "dynamic store without zones keeps client price",
+			order: func() models.Order {
+				o := newOrder(51.1, 71.4, 1000, true)
+				o.ClientDeliveryPrice = 900
+				return o
+			}(),
+			store:               storeModels.Store{Kwaaka3PL: storeModels.Kwaaka3PL{Is3pl: true, IsDynamic: true, CPO: 700}},
+			clientDeliveryPrice: 900,
+			restaurantPay:       900,
+		},
+		{
+			name:  "pickup order is not quoted",
+			order: newOrder(51.1, 71.4, 1000, false),
+			store: store,
+		},
+		{
+			name:  "store without 3pl is not quoted",
+			order: newOrder(51.1, 71.4, 1000, true),
+			store: storeModels.Store{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := applyDeliveryZone(tt.order, tt.store)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr), err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.clientDeliveryPrice, order.ClientDeliveryPrice)
+			assert.Equal(t, tt.restaurantPay, order.RestaurantPayDeliveryPrice)
+		})
+	}
+}
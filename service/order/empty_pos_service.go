@@ -20,12 +20,14 @@ type EmptyPosService struct {
 	storeService      store.Service
 	aggregatorFactory aggregator.Factory
 	repository        Repository
+	outboxPublisher   *Publisher
 }
 
 func NewEmptyPosService(
 	storeService store.Service,
 	aggregatorFactory aggregator.Factory,
-	repository Repository) (*EmptyPosService, error) {
+	repository Repository,
+	outboxPublisher *Publisher) (*EmptyPosService, error) {
 	if storeService == nil {
 		return nil, errors.New("store service is nil")
 	}
@@ -40,6 +42,7 @@ func NewEmptyPosService(
 		storeService:      storeService,
 		aggregatorFactory: aggregatorFactory,
 		repository:        repository,
+		outboxPublisher:   outboxPublisher,
 	}, nil
 }
 
@@ -118,15 +121,7 @@ func (s *EmptyPosService) CreateOrder(ctx context.Context, externalStoreID, deli
 func (s *EmptyPosService) updateStatus(ctx context.Context, agg aggregator.Aggregator, order models.Order, st storeModels.Store, status models.PosStatus) error {
 	aggStatus := agg.MapSystemStatusToAggregatorStatus(order, status, st)
 
-	if err := agg.UpdateOrderInAggregator(ctx, order, st, aggStatus); err != nil {
-		return err
-	}
-
-	if err := s.repository.UpdateOrderStatusByID(ctx, order.ID, status.String()); err != nil {
-		return err
-	}
-
-	return nil
+	return enqueueAggregatorStatus(ctx, s.repository, s.outboxPublisher, order, st, status, aggStatus)
 }
 
 func (s *EmptyPosService) saveOrderToDb(ctx context.Context, req models.Order) (models.Order, error) {
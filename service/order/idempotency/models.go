@@ -0,0 +1,47 @@
+package idempotency
+
+import (
+	"time"
+)
+
+const CollectionName = "order_claims"
+
+type State string
+
+const (
+	StateInProgress State = "IN_PROGRESS"
+	StateDone       State = "DONE"
+	StateFailed     State = "FAILED"
+)
+
+func (s State) String() string {
+	return string(s)
+}
+
+// Key identifies aggregator order, collection has unique index by these fields
+type Key struct {
+	DeliveryService string `bson:"delivery_service" json:"delivery_service"`
+	OrderID         string `bson:"order_id" json:"order_id"`
+	StoreID         string `bson:"store_id" json:"store_id"`
+}
+
+// Claim is created before order is sent to pos, so repeated webhook of aggregator does not create second order
+type Claim struct {
+	ID            string `bson:"_id,omitempty" json:"id"`
+	Key           `bson:",inline"`
+	State         State     `bson:"state" json:"state"`
+	SystemOrderID string    `bson:"system_order_id,omitempty" json:"system_order_id,omitempty"`
+	Error         string    `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt     time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+func NewClaim(key Key) Claim {
+	now := time.Now().UTC()
+	return Claim{
+		Key:       key,
+		State:     StateInProgress,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
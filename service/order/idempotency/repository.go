@@ -0,0 +1,112 @@
+package idempotency
+
+import (
+	"context"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+type Repository interface {
+	// Claim returns true if claim was created, otherwise returns existing claim
+	Claim(ctx context.Context, key Key) (Claim, bool, error)
+	TakeOver(ctx context.Context, id string, staleBefore time.Time) (bool, error)
+	Complete(ctx context.Context, id string, state State, systemOrderID, errMsg string) error
+	Release(ctx context.Context, id string) error
+}
+
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(db *mongo.Database) (*MongoRepository, error) {
+	return &MongoRepository{
+		collection: db.Collection(CollectionName),
+	}, nil
+}
+
+func (r *MongoRepository) Claim(ctx context.Context, key Key) (Claim, bool, error) {
+	claim := NewClaim(key)
+
+	res, err := r.collection.InsertOne(ctx, claim)
+	if err == nil {
+		claim.ID = res.InsertedID.(primitive.ObjectID).Hex()
+		return claim, true, nil
+	}
+
+	if !mongo.IsDuplicateKeyError(err) {
+		return Claim{}, false, err
+	}
+
+	filter := bson.D{
+		{Key: "delivery_service", Value: key.DeliveryService},
+		{Key: "order_id", Value: key.OrderID},
+		{Key: "store_id", Value: key.StoreID},
+	}
+
+	var existing Claim
+	if err = r.collection.FindOne(ctx, filter).Decode(&existing); err != nil {
+		return Claim{}, false, errors.ErrorSwitch(err)
+	}
+
+	return existing, false, nil
+}
+
+// TakeOver continues claim, which was not completed in time, for example if lambda was interrupted
+func (r *MongoRepository) TakeOver(ctx context.Context, id string, staleBefore time.Time) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: oid},
+		{Key: "state", Value: StateInProgress},
+		{Key: "updated_at", Value: bson.M{"$lt": staleBefore}},
+	}
+
+	res, err := r.collection.UpdateOne(ctx, filter, bson.D{{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now().UTC()}}}})
+	if err != nil {
+		return false, err
+	}
+
+	return res.ModifiedCount != 0, nil
+}
+
+func (r *MongoRepository) Complete(ctx context.Context, id string, state State, systemOrderID, errMsg string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "state", Value: state},
+		{Key: "system_order_id", Value: systemOrderID},
+		{Key: "error", Value: errMsg},
+		{Key: "updated_at", Value: time.Now().UTC()},
+	}}}
+
+	res, err := r.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: oid}}, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+// Release deletes claim, so order can be created by next webhook
+func (r *MongoRepository) Release(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.D{{Key: "_id", Value: oid}})
+	return err
+}
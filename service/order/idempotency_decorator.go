@@ -0,0 +1,154 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"github.com/kwaaka-team/orders-core/core/managers/validator"
+	"github.com/kwaaka-team/orders-core/core/models"
+	"github.com/kwaaka-team/orders-core/service/aggregator"
+	"github.com/kwaaka-team/orders-core/service/order/idempotency"
+	"github.com/kwaaka-team/orders-core/service/store"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// claimStaleAfter - время, после которого незавершенный claim считается брошенным
+const claimStaleAfter = 5 * time.Minute
+
+// IdempotencyDecorator deduplicates repeated aggregator webhooks by (delivery_service, order_id, store_id).
+// Repeated webhook gets response of the original order and is not sent to pos
+type IdempotencyDecorator struct {
+	service           CreationService
+	claimRepository   idempotency.Repository
+	orderRepository   Repository
+	storeService      store.Service
+	aggregatorFactory aggregator.Factory
+}
+
+func NewIdempotencyDecorator(service CreationService, claimRepository idempotency.Repository, orderRepository Repository, storeService store.Service, aggregatorFactory aggregator.Factory) (*IdempotencyDecorator, error) {
+	if service == nil {
+		return nil, errors.Wrap(errConstructor, "order service is nil")
+	}
+	if claimRepository == nil {
+		return nil, errors.Wrap(errConstructor, "claim repository is nil")
+	}
+	if orderRepository == nil {
+		return nil, errors.Wrap(errConstructor, "order repository is nil")
+	}
+	if storeService == nil {
+		return nil, errors.Wrap(errConstructor, "store service is nil")
+	}
+	if aggregatorFactory == nil {
+		return nil, errors.Wrap(errConstructor, "aggregator factory is nil")
+	}
+
+	return &IdempotencyDecorator{
+		service:           service,
+		claimRepository:   claimRepository,
+		orderRepository:   orderRepository,
+		storeService:      storeService,
+		aggregatorFactory: aggregatorFactory,
+	}, nil
+}
+
+func (s *IdempotencyDecorator) CreateOrder(ctx context.Context, externalStoreID, deliveryService string, aggReq interface{}, storeSecret string) (models.Order, error) {
+	key, err := s.claimKey(ctx, externalStoreID, deliveryService, aggReq)
+	if err != nil {
+		log.Err(err).Msgf("idempotency key error, external_store_id=%s, delivery_service=%s, creating order without claim", externalStoreID, deliveryService)
+		return s.service.CreateOrder(ctx, externalStoreID, deliveryService, aggReq, storeSecret)
+	}
+
+	claim, created, err := s.claimRepository.Claim(ctx, key)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	if !created {
+		order, replay, err := s.replay(ctx, claim)
+		if replay {
+			return order, err
+		}
+	}
+
+	order, orderErr := s.service.CreateOrder(ctx, externalStoreID, deliveryService, aggReq, storeSecret)
+
+	if err = s.complete(ctx, claim, order, orderErr); err != nil {
+		log.Err(err).Msgf("complete order claim error, order_id=%s, delivery_service=%s", key.OrderID, key.DeliveryService)
+	}
+
+	return order, orderErr
+}
+
+func (s *IdempotencyDecorator) claimKey(ctx context.Context, externalStoreID, deliveryService string, aggReq interface{}) (idempotency.Key, error) {
+	st, err := s.storeService.GetByExternalIdAndDeliveryService(ctx, externalStoreID, deliveryService)
+	if err != nil {
+		return idempotency.Key{}, err
+	}
+
+	agg, err := s.aggregatorFactory.GetAggregator(deliveryService, st)
+	if err != nil {
+		return idempotency.Key{}, err
+	}
+
+	orderID, err := agg.GetOrderIDFromAggregatorOrderRequest(aggReq)
+	if err != nil {
+		return idempotency.Key{}, err
+	}
+
+	if orderID == "" {
+		return idempotency.Key{}, errors.New("aggregator order id is empty")
+	}
+
+	return idempotency.Key{
+		DeliveryService: deliveryService,
+		OrderID:         orderID,
+		StoreID:         externalStoreID,
+	}, nil
+}
+
+// replay returns false if order should be created, when previous attempt was interrupted
+func (s *IdempotencyDecorator) replay(ctx context.Context, claim idempotency.Claim) (models.Order, bool, error) {
+	log.Info().Msgf("repeated order webhook, order_id=%s, delivery_service=%s, claim state=%s", claim.OrderID, claim.DeliveryService, claim.State)
+
+	switch claim.State {
+	case idempotency.StateDone:
+		order, err := s.orderRepository.FindOrderByID(ctx, claim.SystemOrderID)
+		return order, true, err
+	case idempotency.StateFailed:
+		order, err := s.orderRepository.FindOrderByID(ctx, claim.SystemOrderID)
+		if err != nil {
+			return models.Order{}, true, err
+		}
+		return order, true, errors.Wrap(validator.ErrPassed, fmt.Sprintf("order %s already failed: %s", claim.OrderID, claim.Error))
+	}
+
+	takenOver, err := s.claimRepository.TakeOver(ctx, claim.ID, time.Now().UTC().Add(-claimStaleAfter))
+	if err != nil {
+		return models.Order{}, true, err
+	}
+	if takenOver {
+		log.Info().Msgf("order claim %s is stale, creating order again, order_id=%s", claim.ID, claim.OrderID)
+		return models.Order{}, false, nil
+	}
+
+	return models.Order{}, true, errors.Wrap(validator.ErrPassed, fmt.Sprintf("order %s is in progress", claim.OrderID))
+}
+
+func (s *IdempotencyDecorator) complete(ctx context.Context, claim idempotency.Claim, order models.Order, orderErr error) error {
+	// заказ не сохранился, следующий webhook должен создать его заново
+	if order.ID == "" {
+		return s.claimRepository.Release(ctx, claim.ID)
+	}
+
+	// заказ уже был сохранен раньше, повтор получает его как созданный
+	if errors.Is(orderErr, validator.ErrPassed) {
+		return s.claimRepository.Complete(ctx, claim.ID, idempotency.StateDone, order.ID, "")
+	}
+
+	if orderErr != nil {
+		return s.claimRepository.Complete(ctx, claim.ID, idempotency.StateFailed, order.ID, orderErr.Error())
+	}
+
+	return s.claimRepository.Complete(ctx, claim.ID, idempotency.StateDone, order.ID, "")
+}
@@ -0,0 +1,229 @@
+package order
+
+import (
+	"context"
+	errs "github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/core/managers/validator"
+	"github.com/kwaaka-team/orders-core/core/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/aggregator"
+	"github.com/kwaaka-team/orders-core/service/order/idempotency"
+	"github.com/kwaaka-team/orders-core/service/store/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type claimRepositoryStub struct {
+	mu     sync.Mutex
+	claims map[idempotency.Key]*idempotency.Claim
+	nextID int
+}
+
+func (r *claimRepositoryStub) Claim(ctx context.Context, key idempotency.Key) (idempotency.Claim, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if claim, ok := r.claims[key]; ok {
+		return *claim, false, nil
+	}
+
+	r.nextID++
+	claim := idempotency.NewClaim(key)
+	claim.ID = strconv.Itoa(r.nextID)
+	r.claims[key] = &claim
+	return claim, true, nil
+}
+
+func (r *claimRepositoryStub) TakeOver(ctx context.Context, id string, staleBefore time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	claim := r.find(id)
+	if claim == nil || claim.State != idempotency.StateInProgress || !claim.UpdatedAt.Before(staleBefore) {
+		return false, nil
+	}
+	claim.UpdatedAt = time.Now().UTC()
+	return true, nil
+}
+
+func (r *claimRepositoryStub) Complete(ctx context.Context, id string, state idempotency.State, systemOrderID, errMsg string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	claim := r.find(id)
+	if claim == nil {
+		return errors.New("claim not found")
+	}
+	claim.State = state
+	claim.SystemOrderID = systemOrderID
+	claim.Error = errMsg
+	return nil
+}
+
+func (r *claimRepositoryStub) Release(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, claim := range r.claims {
+		if claim.ID == id {
+			delete(r.claims, key)
+		}
+	}
+	return nil
+}
+
+func (r *claimRepositoryStub) find(id string) *idempotency.Claim {
+	for _, claim := range r.claims {
+		if claim.ID == id {
+			return claim
+		}
+	}
+	return nil
+}
+
+type creationServiceStub struct {
+	calls int32
+	delay time.Duration
+	order models.Order
+	err   error
+}
+
+func (s *creationServiceStub) CreateOrder(ctx context.Context, externalStoreID, deliveryService string, aggReq interface{}, storeSecret string) (models.Order, error) {
+	atomic.AddInt32(&s.calls, 1)
+	time.Sleep(s.delay)
+	return s.order, s.err
+}
+
+type orderIDAggregatorStub struct {
+	aggregator.Aggregator
+}
+
+func (a orderIDAggregatorStub) GetOrderIDFromAggregatorOrderRequest(req interface{}) (string, error) {
+	return req.(string), nil
+}
+
+func newIdempotencyDecoratorForTest(t *testing.T, inner *creationServiceStub, saved models.Order) (*IdempotencyDecorator, *claimRepositoryStub) {
+	claims := &claimRepositoryStub{claims: map[idempotency.Key]*idempotency.Claim{}}
+
+	storeService := &mocks.Service{}
+	storeService.On("GetByExternalIdAndDeliveryService", mock.Anything, "external_store", models.GLOVO.String()).Return(storeModels.Store{ID: "store"}, nil)
+
+	s, err := NewIdempotencyDecorator(inner, claims, orderRepositoryStub{order: saved}, storeService, aggregatorFactoryStub{aggregator: orderIDAggregatorStub{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, claims
+}
+
+func TestIdempotencyDecorator_CreateOrder_Duplicate(t *testing.T) {
+	order := models.Order{ID: "system_order", OrderID: "glovo_order"}
+	inner := &creationServiceStub{order: order}
+	s, _ := newIdempotencyDecoratorForTest(t, inner, order)
+
+	first, err := s.CreateOrder(context.Background(), "external_store", models.GLOVO.String(), "glovo_order", "")
+	assert.NoError(t, err)
+
+	second, err := s.CreateOrder(context.Background(), "external_store", models.GLOVO.String(), "glovo_order", "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, int32(1), inner.calls)
+}
+
+func TestIdempotencyDecorator_CreateOrder_Concurrent(t *testing.T) {
+	order := models.Order{ID: "system_order", OrderID: "glovo_order"}
+	inner := &creationServiceStub{order: order, delay: 50 * time.Millisecond}
+	s, _ := newIdempotencyDecoratorForTest(t, inner, order)
+
+	var (
+		wg     sync.WaitGroup
+		passed int32
+	)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.CreateOrder(context.Background(), "external_store", models.GLOVO.String(), "glovo_order", "")
+			if errors.Is(err, validator.ErrPassed) {
+				atomic.AddInt32(&passed, 1)
+				return
+			}
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), inner.calls)
+	assert.Equal(t, int32(4), passed)
+}
+
+func TestIdempotencyDecorator_CreateOrder_InnerFailureReleasesKey(t *testing.T) {
+	inner := &creationServiceStub{err: errors.New("store is closed")}
+	s, claims := newIdempotencyDecoratorForTest(t, inner, models.Order{})
+
+	_, err := s.CreateOrder(context.Background(), "external_store", models.GLOVO.String(), "glovo_order", "")
+	assert.Error(t, err)
+	assert.Empty(t, claims.claims)
+
+	order := models.Order{ID: "system_order", OrderID: "glovo_order"}
+	inner.order, inner.err = order, nil
+
+	res, err := s.CreateOrder(context.Background(), "external_store", models.GLOVO.String(), "glovo_order", "")
+	assert.NoError(t, err)
+	assert.Equal(t, order, res)
+	assert.Equal(t, int32(2), inner.calls)
+	assert.Len(t, claims.claims, 1)
+}
+
+func TestIdempotencyDecorator_CreateOrder_AlreadySaved(t *testing.T) {
+	// заказ уже сохранен прошлым webhook без claim, сервис возвращает его с ErrPassed
+	order := models.Order{ID: "system_order", OrderID: "glovo_order"}
+	inner := &creationServiceStub{order: order, err: errors.Wrap(validator.ErrPassed, "order glovo_order passed")}
+	s, claims := newIdempotencyDecoratorForTest(t, inner, order)
+
+	res, err := s.CreateOrder(context.Background(), "external_store", models.GLOVO.String(), "glovo_order", "")
+	assert.ErrorIs(t, err, validator.ErrPassed)
+	assert.Equal(t, order, res)
+
+	if assert.Len(t, claims.claims, 1) {
+		for _, claim := range claims.claims {
+			assert.Equal(t, idempotency.StateDone, claim.State)
+			assert.Equal(t, order.ID, claim.SystemOrderID)
+		}
+	}
+
+	res, err = s.CreateOrder(context.Background(), "external_store", models.GLOVO.String(), "glovo_order", "")
+	assert.NoError(t, err)
+	assert.Equal(t, order, res)
+	assert.Equal(t, int32(1), inner.calls)
+}
+
+type duplicateOrderRepositoryStub struct {
+	Repository
+	existing models.Order
+}
+
+func (r duplicateOrderRepositoryStub) InsertOrder(ctx context.Context, order models.Order) (models.Order, error) {
+	return models.Order{}, errs.ErrAlreadyExist
+}
+
+func (r duplicateOrderRepositoryStub) FindDuplicateOrder(ctx context.Context, order models.Order) (models.Order, error) {
+	return r.existing, nil
+}
+
+func TestSaveOrderToDb_Duplicate(t *testing.T) {
+	existing := models.Order{ID: "system_order", OrderID: "glovo_order", DeliveryService: models.GLOVO.String()}
+	s := &ServiceImpl{repository: duplicateOrderRepositoryStub{existing: existing}}
+
+	order, err := s.saveOrderToDb(context.Background(), models.Order{OrderID: "glovo_order", DeliveryService: models.GLOVO.String()})
+
+	assert.ErrorIs(t, err, validator.ErrPassed)
+	assert.Equal(t, existing, order)
+}
@@ -0,0 +1,179 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	coreMenuModels "github.com/kwaaka-team/orders-core/core/menu/models"
+	"github.com/kwaaka-team/orders-core/core/models"
+	woltModels "github.com/kwaaka-team/orders-core/core/wolt/models"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"math"
+)
+
+var errOrderNotModifiable = errors.New("order items can not be modified")
+
+// UpdateOrderItemsByAggregator applies new products of order from aggregator in pos as a delta, order is not recreated
+func (s *ServiceImpl) UpdateOrderItemsByAggregator(ctx context.Context, orderID, delivery string, products []models.OrderProduct) (models.Order, error) {
+	order, err := s.repository.FindOrderByOrderID(ctx, orderID)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	if delivery != order.DeliveryService {
+		return order, errors.New("You cannot modify order of this aggregator")
+	}
+
+	if err = s.validateOrderModifiable(order); err != nil {
+		return order, err
+	}
+
+	st, err := s.storeService.GetByID(ctx, order.RestaurantID)
+	if err != nil {
+		return order, err
+	}
+
+	aggMenu, err := s.menuService.GetAggregatorMenuIfExists(ctx, st, delivery)
+	if err != nil {
+		return order, err
+	}
+
+	products = toPosPositions(products, aggMenu)
+
+	diff := models.NewOrderItemsDiff(order.Products, products)
+	if diff.IsEmpty() {
+		log.Info().Msgf("order %s items are not changed", order.OrderID)
+		return order, nil
+	}
+
+	posService, err := s.posFactory.GetPosService(models.Pos(order.PosType), st)
+	if err != nil {
+		return order, err
+	}
+
+	recalcOrderTotals(&order, products)
+	order.Products = products
+
+	order, err = posService.UpdateOrderItems(ctx, order, st, diff)
+	if err != nil {
+		return order, errors.Wrapf(err, "update order %s items in pos %s", order.OrderID, order.PosType)
+	}
+
+	order.ItemsChanges = append(order.ItemsChanges, diff)
+
+	if err = s.repository.UpdateOrder(ctx, order); err != nil {
+		return order, err
+	}
+
+	log.Info().Msgf("order %s items updated: added=%d, removed=%d, changed=%d", order.OrderID, len(diff.Added), len(diff.Removed), len(diff.Changed))
+
+	return order, nil
+}
+
+// UpdateOrderItemsByAggregatorRequest applies new products from order update webhook of aggregator,
+// wolt webhook has only order id, so order is requested from wolt api
+func (s *ServiceImpl) UpdateOrderItemsByAggregatorRequest(ctx context.Context, externalStoreID, delivery string, aggReq interface{}) (models.Order, error) {
+	st, err := s.storeService.GetByExternalIdAndDeliveryService(ctx, externalStoreID, delivery)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	agg, err := s.aggregatorFactory.GetAggregator(delivery, st)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	if webhook, ok := aggReq.(woltModels.OrderNotification); ok {
+		if aggReq, err = agg.GetAggregatorOrder(ctx, webhook.Body.Id); err != nil {
+			return models.Order{}, err
+		}
+	}
+
+	req, err := agg.GetSystemCreateOrderRequestByAggregatorRequest(aggReq, st)
+	if err != nil {
+		return models.Order{}, err
+	}
+
+	return s.UpdateOrderItemsByAggregator(ctx, req.OrderID, delivery, req.Products)
+}
+
+// recalcOrderTotals сдвигает суммы заказа на разницу стоимости старого и нового состава,
+// скидки и доставка, уже учтенные в суммах, не пересчитываются
+func recalcOrderTotals(order *models.Order, products []models.OrderProduct) {
+	delta := productsTotal(products) - productsTotal(order.Products)
+	if delta == 0 {
+		return
+	}
+
+	order.EstimatedTotalPrice.Value = shiftTotal(order.EstimatedTotalPrice.Value, delta)
+	if order.TotalCustomerToPay.Value != 0 {
+		order.TotalCustomerToPay.Value = shiftTotal(order.TotalCustomerToPay.Value, delta)
+	}
+}
+
+func productsTotal(products []models.OrderProduct) float64 {
+	var total float64
+	for _, product := range products {
+		price := product.Price.Value
+		for _, attribute := range product.Attributes {
+			price += attribute.Price.Value * float64(attribute.Quantity)
+		}
+		total += price * float64(product.Quantity)
+	}
+	return total
+}
+
+func shiftTotal(total, delta float64) float64 {
+	return math.Max(math.Round((total+delta)*100)/100, 0)
+}
+
+func (s *ServiceImpl) validateOrderModifiable(order models.Order) error {
+	if order.PosOrderID == "" {
+		return errors.Wrap(errOrderNotModifiable, fmt.Sprintf("order %s is not created in pos", order.OrderID))
+	}
+
+	switch order.Status {
+	case models.CLOSED.String(), models.FAILED.String(), models.CANCELLED_BY_POS_SYSTEM.String(),
+		string(models.STATUS_CANCELLED), string(models.STATUS_CANCELLED_BY_DELIVERY_SERVICE), string(models.STATUS_SKIPPED):
+		return errors.Wrap(errOrderNotModifiable, fmt.Sprintf("order %s has status %s", order.OrderID, order.Status))
+	}
+
+	return nil
+}
+
+// toPosPositions replaces aggregator ids of products and attributes by pos ids, as it is done on order creation
+func toPosPositions(products []models.OrderProduct, aggregatorMenu coreMenuModels.Menu) []models.OrderProduct {
+	productIDs := make(map[string]string, len(aggregatorMenu.Products))
+	for _, product := range aggregatorMenu.Products {
+		if product.PosID != "" {
+			productIDs[product.ExtID] = product.PosID
+		}
+	}
+
+	attributeIDs := make(map[string]string, len(aggregatorMenu.Attributes))
+	for _, attribute := range aggregatorMenu.Attributes {
+		if attribute.PosID != "" {
+			attributeIDs[attribute.ExtID] = attribute.PosID
+		}
+	}
+
+	result := make([]models.OrderProduct, 0, len(products))
+	for _, product := range products {
+		if posID, ok := productIDs[product.ID]; ok {
+			product.ID = posID
+		}
+
+		attributes := make([]models.ProductAttribute, 0, len(product.Attributes))
+		for _, attribute := range product.Attributes {
+			if posID, ok := attributeIDs[attribute.ID]; ok {
+				attribute.ID = posID
+			}
+			attributes = append(attributes, attribute)
+		}
+		product.Attributes = attributes
+
+		result = append(result, product)
+	}
+
+	return result
+}
@@ -0,0 +1,215 @@
+package order
+
+import (
+	"context"
+	"github.com/kwaaka-team/orders-core/core/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/aggregator"
+	"github.com/kwaaka-team/orders-core/service/pos"
+	"github.com/kwaaka-team/orders-core/service/store/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+type itemsOrderRepositoryStub struct {
+	Repository
+	order   models.Order
+	updated []models.Order
+}
+
+func (r *itemsOrderRepositoryStub) FindOrderByOrderID(ctx context.Context, orderID string) (models.Order, error) {
+	if orderID != r.order.OrderID {
+		return models.Order{}, errors.New("order not found")
+	}
+	return r.order, nil
+}
+
+func (r *itemsOrderRepositoryStub) UpdateOrder(ctx context.Context, order models.Order) error {
+	r.updated = append(r.updated, order)
+	return nil
+}
+
+type posFactoryStub struct {
+	service pos.Service
+}
+
+func (f posFactoryStub) GetPosService(posType models.Pos, store storeModels.Store) (pos.Service, error) {
+	return f.service, nil
+}
+
+type itemsPosServiceStub struct {
+	pos.Service
+	err   error
+	diffs []models.OrderItemsDiff
+}
+
+func (p *itemsPosServiceStub) UpdateOrderItems(ctx context.Context, order models.Order, store storeModels.Store, diff models.OrderItemsDiff) (models.Order, error) {
+	p.diffs = append(p.diffs, diff)
+	return order, p.err
+}
+
+type orderRequestAggregatorStub struct {
+	aggregator.Aggregator
+}
+
+func (a orderRequestAggregatorStub) GetSystemCreateOrderRequestByAggregatorRequest(req interface{}, store storeModels.Store) (models.Order, error) {
+	return req.(models.Order), nil
+}
+
+func TestServiceImpl_UpdateOrderItemsByAggregatorRequest(t *testing.T) {
+	saved := models.Order{
+		OrderID:         "glovo_order",
+		PosOrderID:      "pos_order",
+		RestaurantID:    "restaurant",
+		StoreID:         "external_store",
+		DeliveryService: models.GLOVO.String(),
+		Status:          models.ACCEPTED.String(),
+		Products: []models.OrderProduct{
+			{ID: "burger", Quantity: 1, Price: models.Price{Value: 1000}},
+		},
+		EstimatedTotalPrice: models.Price{Value: 1000},
+	}
+
+	tests := []struct {
+		name         string
+		order        models.Order
+		products     []models.OrderProduct
+		posErr       error
+		wantErr      error
+		wantDiffs    int
+		wantSaved    bool
+		wantEstimate float64
+	}{
+		{
+			name:         "changed items are applied in pos and saved with new totals",
+			order:        saved,
+			products:     []models.OrderProduct{{ID: "burger", Quantity: 2, Price: models.Price{Value: 1000}}},
+			wantDiffs:    1,
+			wantSaved:    true,
+			wantEstimate: 2000,
+		},
+		{
+			name:     "unchanged items are not sent to pos",
+			order:    saved,
+			products: []models.OrderProduct{{ID: "burger", Quantity: 1}},
+		},
+		{
+			name:      "change rejected by pos is not saved",
+			order:     saved,
+			products:  []models.OrderProduct{},
+			posErr:    pos.ErrOrderItemsChangeNotApplicable,
+			wantErr:   pos.ErrOrderItemsChangeNotApplicable,
+			wantDiffs: 1,
+		},
+		{
+			name: "order not created in pos can not be modified",
+			order: func() models.Order {
+				o := saved
+				o.PosOrderID = ""
+				return o
+			}(),
+			products: []models.OrderProduct{{ID: "burger", Quantity: 2}},
+			wantErr:  errOrderNotModifiable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &itemsOrderRepositoryStub{order: tt.order}
+			posService := &itemsPosServiceStub{err: tt.posErr}
+
+			storeService := &mocks.Service{}
+			storeService.On("GetByExternalIdAndDeliveryService", mock.Anything, "external_store", models.GLOVO.String()).Return(storeModels.Store{ID: "restaurant"}, nil)
+			storeService.On("GetByID", mock.Anything, "restaurant").Return(storeModels.Store{ID: "restaurant"}, nil)
+
+			s := &ServiceImpl{
+				storeService:      storeService,
+				aggregatorFactory: aggregatorFactoryStub{aggregator: orderRequestAggregatorStub{}},
+				posFactory:        posFactoryStub{service: posService},
+				repository:        repo,
+			}
+
+			_, err := s.UpdateOrderItemsByAggregatorRequest(context.Background(), "external_store", models.GLOVO.String(), models.Order{
+				OrderID:  "glovo_order",
+				Products: tt.products,
+			})
+
+			assert.True(t, errors.Is(err, tt.wantErr), err)
+			assert.Len(t, posService.diffs, tt.wantDiffs)
+			if !tt.wantSaved {
+				assert.Empty(t, repo.updated)
+				return
+			}
+			assert.Len(t, repo.updated, 1)
+			assert.Equal(t, tt.products[0].Quantity, repo.updated[0].Products[0].Quantity)
+			assert.Len(t, repo.updated[0].ItemsChanges, 1)
+			assert.Equal(t, tt.wantEstimate, repo.updated[0].EstimatedTotalPrice.Value)
+		})
+	}
+}
+
+func TestRecalcOrderTotals(t *testing.T) {
+	burger := models.OrderProduct{
+		ID:       "burger",
+		Quantity: 1,
+		Price:    models.Price{Value: 1000},
+		Attributes: []models.ProductAttribute{
+			{ID: "cheese", Quantity: 2, Price: models.Price{Value: 150}},
+		},
+	}
+	cola := models.OrderProduct{ID: "cola", Quantity: 1, Price: models.Price{Value: 500}}
+
+	tests := []struct {
+		name         string
+		order        models.Order
+		products     []models.OrderProduct
+		wantEstimate float64
+		wantToPay    float64
+	}{
+		{
+			name: "added item increases totals",
+			order: models.Order{
+				Products:            []models.OrderProduct{burger},
+				EstimatedTotalPrice: models.Price{Value: 1300},
+				TotalCustomerToPay:  models.Price{Value: 1800},
+			},
+			products:     []models.OrderProduct{burger, cola},
+			wantEstimate: 1800,
+			wantToPay:    2300,
+		},
+		{
+			name: "removed item and changed quantity decrease totals with attributes",
+			order: models.Order{
+				Products:            []models.OrderProduct{burger, cola},
+				EstimatedTotalPrice: models.Price{Value: 1800},
+			},
+			products: []models.OrderProduct{func() models.OrderProduct {
+				p := cola
+				p.Quantity = 2
+				return p
+			}()},
+			wantEstimate: 1000,
+		},
+		{
+			name: "totals are not negative",
+			order: models.Order{
+				Products:            []models.OrderProduct{burger},
+				EstimatedTotalPrice: models.Price{Value: 100},
+			},
+			products:     []models.OrderProduct{},
+			wantEstimate: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := tt.order
+			recalcOrderTotals(&order, tt.products)
+
+			assert.Equal(t, tt.wantEstimate, order.EstimatedTotalPrice.Value)
+			assert.Equal(t, tt.wantToPay, order.TotalCustomerToPay.Value)
+		})
+	}
+}
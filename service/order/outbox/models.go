@@ -0,0 +1,64 @@
+package outbox
+
+import (
+	"time"
+)
+
+const CollectionName = "aggregator_status_outbox"
+
+type State string
+
+const (
+	StatePending    State = "PENDING"
+	StateSent       State = "SENT"
+	StateDeadLetter State = "DEAD_LETTER"
+)
+
+func (s State) String() string {
+	return string(s)
+}
+
+// Message is a single status push to aggregator, stored together with order status update
+type Message struct {
+	ID               string    `bson:"_id,omitempty" json:"id"`
+	OrderID          string    `bson:"order_id" json:"order_id"`
+	AggregatorOrder  string    `bson:"aggregator_order_id" json:"aggregator_order_id"`
+	RestaurantID     string    `bson:"restaurant_id" json:"restaurant_id"`
+	DeliveryService  string    `bson:"delivery_service" json:"delivery_service"`
+	SystemStatus     string    `bson:"system_status" json:"system_status"`
+	AggregatorStatus string    `bson:"aggregator_status" json:"aggregator_status"`
+	State            State     `bson:"state" json:"state"`
+	Attempts         int       `bson:"attempts" json:"attempts"`
+	LastError        string    `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	NextAttemptAt    time.Time `bson:"next_attempt_at" json:"next_attempt_at"`
+	// LockedUntil is set when dispatcher claims message, other dispatchers skip it until lease expires
+	LockedUntil time.Time `bson:"locked_until,omitempty" json:"-"`
+	SentAt      time.Time `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+func NewMessage(orderID, aggregatorOrderID, restaurantID, deliveryService, systemStatus, aggregatorStatus string) Message {
+	now := time.Now().UTC()
+	return Message{
+		OrderID:          orderID,
+		AggregatorOrder:  aggregatorOrderID,
+		RestaurantID:     restaurantID,
+		DeliveryService:  deliveryService,
+		SystemStatus:     systemStatus,
+		AggregatorStatus: aggregatorStatus,
+		State:            StatePending,
+		NextAttemptAt:    now,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
+type ListQuery struct {
+	RestaurantID    string `json:"restaurant_id"`
+	DeliveryService string `json:"delivery_service"`
+	OrderID         string `json:"order_id"`
+	State           State  `json:"state"`
+	Page            int64  `json:"page"`
+	Limit           int64  `json:"limit"`
+}
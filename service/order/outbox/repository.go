@@ -0,0 +1,245 @@
+package outbox
+
+import (
+	"context"
+	"github.com/kwaaka-team/orders-core/core/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type Repository interface {
+	Insert(ctx context.Context, message Message) (string, error)
+	FindByID(ctx context.Context, id string) (Message, error)
+	FindPendingByOrderID(ctx context.Context, orderID string) ([]Message, error)
+	FindDueOrderIDs(ctx context.Context, now time.Time, limit int64) ([]string, error)
+	Claim(ctx context.Context, id string, now time.Time, lease time.Duration) (bool, error)
+	MarkSent(ctx context.Context, id string) error
+	MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastErr string) error
+	MarkDeadLetter(ctx context.Context, id string, attempts int, lastErr string) error
+	Requeue(ctx context.Context, id string) error
+	List(ctx context.Context, query ListQuery) ([]Message, int64, error)
+}
+
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(db *mongo.Database) (*MongoRepository, error) {
+	return &MongoRepository{
+		collection: db.Collection(CollectionName),
+	}, nil
+}
+
+// Insert uses ctx as is, so it can be called inside session context of order repository transaction
+func (r *MongoRepository) Insert(ctx context.Context, message Message) (string, error) {
+	message.ID = ""
+	res, err := r.collection.InsertOne(ctx, message)
+	if err != nil {
+		return "", errors.ErrorSwitch(err)
+	}
+
+	return res.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *MongoRepository) FindByID(ctx context.Context, id string) (Message, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Message{}, err
+	}
+
+	var message Message
+	if err = r.collection.FindOne(ctx, bson.D{{Key: "_id", Value: oid}}).Decode(&message); err != nil {
+		return Message{}, errors.ErrorSwitch(err)
+	}
+
+	return message, nil
+}
+
+func (r *MongoRepository) FindPendingByOrderID(ctx context.Context, orderID string) ([]Message, error) {
+	filter := bson.D{
+		{Key: "order_id", Value: orderID},
+		{Key: "state", Value: StatePending},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]Message, 0, cursor.RemainingBatchLength())
+	if err = cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r *MongoRepository) FindDueOrderIDs(ctx context.Context, now time.Time, limit int64) ([]string, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "state", Value: StatePending},
+			{Key: "next_attempt_at", Value: bson.M{"$lte": now}},
+		}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$order_id"},
+			{Key: "first_created_at", Value: bson.M{"$min": "$created_at"}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "first_created_at", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []struct {
+		OrderID string `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+
+	orderIDs := make([]string, 0, len(result))
+	for _, item := range result {
+		orderIDs = append(orderIDs, item.OrderID)
+	}
+
+	return orderIDs, nil
+}
+
+// Claim locks pending due message for lease, so only one dispatcher sends it. Returns false, if message is sent,
+// not due yet or claimed by other dispatcher
+func (r *MongoRepository) Claim(ctx context.Context, id string, now time.Time, lease time.Duration) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: oid},
+		{Key: "state", Value: StatePending},
+		{Key: "next_attempt_at", Value: bson.M{"$lte": now}},
+		{Key: "$or", Value: bson.A{
+			bson.M{"locked_until": bson.M{"$exists": false}},
+			bson.M{"locked_until": bson.M{"$lte": now}},
+		}},
+	}
+
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "locked_until", Value: now.Add(lease)}}}}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *MongoRepository) MarkSent(ctx context.Context, id string) error {
+	now := time.Now().UTC()
+	return r.updateByID(ctx, id, bson.D{
+		{Key: "state", Value: StateSent},
+		{Key: "sent_at", Value: now},
+		{Key: "updated_at", Value: now},
+	}, bson.D{{Key: "attempts", Value: 1}})
+}
+
+func (r *MongoRepository) MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastErr string) error {
+	return r.updateByID(ctx, id, bson.D{
+		{Key: "attempts", Value: attempts},
+		{Key: "next_attempt_at", Value: nextAttemptAt},
+		{Key: "last_error", Value: lastErr},
+		{Key: "updated_at", Value: time.Now().UTC()},
+	}, nil)
+}
+
+func (r *MongoRepository) MarkDeadLetter(ctx context.Context, id string, attempts int, lastErr string) error {
+	return r.updateByID(ctx, id, bson.D{
+		{Key: "state", Value: StateDeadLetter},
+		{Key: "attempts", Value: attempts},
+		{Key: "last_error", Value: lastErr},
+		{Key: "updated_at", Value: time.Now().UTC()},
+	}, nil)
+}
+
+func (r *MongoRepository) Requeue(ctx context.Context, id string) error {
+	now := time.Now().UTC()
+	return r.updateByID(ctx, id, bson.D{
+		{Key: "state", Value: StatePending},
+		{Key: "attempts", Value: 0},
+		{Key: "next_attempt_at", Value: now},
+		{Key: "updated_at", Value: now},
+	}, nil)
+}
+
+func (r *MongoRepository) List(ctx context.Context, query ListQuery) ([]Message, int64, error) {
+	filter := bson.D{}
+	if query.RestaurantID != "" {
+		filter = append(filter, bson.E{Key: "restaurant_id", Value: query.RestaurantID})
+	}
+	if query.DeliveryService != "" {
+		filter = append(filter, bson.E{Key: "delivery_service", Value: query.DeliveryService})
+	}
+	if query.OrderID != "" {
+		filter = append(filter, bson.E{Key: "order_id", Value: query.OrderID})
+	}
+	if query.State != "" {
+		filter = append(filter, bson.E{Key: "state", Value: query.State})
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	if query.Limit > 0 {
+		page := query.Page
+		if page < 1 {
+			page = 1
+		}
+		opts.SetLimit(query.Limit).SetSkip((page - 1) * query.Limit)
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	messages := make([]Message, 0, cursor.RemainingBatchLength())
+	if err = cursor.All(ctx, &messages); err != nil {
+		return nil, 0, err
+	}
+
+	return messages, total, nil
+}
+
+func (r *MongoRepository) updateByID(ctx context.Context, id string, set bson.D, inc bson.D) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: set},
+		{Key: "$unset", Value: bson.D{{Key: "locked_until", Value: ""}}},
+	}
+	if len(inc) != 0 {
+		update = append(update, bson.E{Key: "$inc", Value: inc})
+	}
+
+	res, err := r.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: oid}}, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package outbox
+
+import (
+	"github.com/kwaaka-team/orders-core/core/models"
+	"time"
+)
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var defaultPolicy = RetryPolicy{
+	MaxAttempts: 6,
+	BaseDelay:   30 * time.Second,
+	MaxDelay:    15 * time.Minute,
+}
+
+// wolt and glovo cancel not accepted/not ready orders fast, so we retry more often
+var policies = map[string]RetryPolicy{
+	models.WOLT.String(): {
+		MaxAttempts: 8,
+		BaseDelay:   10 * time.Second,
+		MaxDelay:    5 * time.Minute,
+	},
+	models.GLOVO.String(): {
+		MaxAttempts: 8,
+		BaseDelay:   10 * time.Second,
+		MaxDelay:    5 * time.Minute,
+	},
+	models.TALABAT.String(): {
+		MaxAttempts: 6,
+		BaseDelay:   15 * time.Second,
+		MaxDelay:    10 * time.Minute,
+	},
+}
+
+func PolicyFor(deliveryService string) RetryPolicy {
+	if policy, ok := policies[deliveryService]; ok {
+		return policy
+	}
+	return defaultPolicy
+}
+
+func (p RetryPolicy) IsExhausted(attempts int) bool {
+	return attempts >= p.MaxAttempts
+}
+
+// NextAttemptAt returns exponential backoff time: base * 2^(attempts-1), limited by MaxDelay
+func (p RetryPolicy) NextAttemptAt(attempts int, now time.Time) time.Time {
+	delay := p.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			delay = p.MaxDelay
+			break
+		}
+	}
+	return now.Add(delay)
+}
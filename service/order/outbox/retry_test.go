@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"github.com/kwaaka-team/orders-core/core/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_NextAttemptAt(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 10 * time.Second, MaxDelay: time.Minute}
+
+	cases := []struct {
+		name     string
+		attempts int
+		expected time.Time
+	}{
+		{name: "first retry", attempts: 1, expected: now.Add(10 * time.Second)},
+		{name: "second retry", attempts: 2, expected: now.Add(20 * time.Second)},
+		{name: "third retry", attempts: 3, expected: now.Add(40 * time.Second)},
+		{name: "limited by max delay", attempts: 10, expected: now.Add(time.Minute)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, policy.NextAttemptAt(tc.attempts, now))
+		})
+	}
+}
+
+func TestPolicyFor(t *testing.T) {
+	assert.Equal(t, policies[models.WOLT.String()], PolicyFor(models.WOLT.String()))
+	assert.Equal(t, defaultPolicy, PolicyFor(models.EXPRESS24.String()))
+	assert.True(t, defaultPolicy.IsExhausted(defaultPolicy.MaxAttempts))
+	assert.False(t, defaultPolicy.IsExhausted(defaultPolicy.MaxAttempts-1))
+}
@@ -0,0 +1,87 @@
+package order
+
+import (
+	"context"
+	"testing"
+
+	errs "github.com/kwaaka-team/orders-core/core/errors"
+	"github.com/kwaaka-team/orders-core/core/models"
+	paymentModels "github.com/kwaaka-team/orders-core/service/payment/models"
+	paymentRepo "github.com/kwaaka-team/orders-core/service/payment/repository"
+	"github.com/kwaaka-team/orders-core/service/promotion"
+	promotionModels "github.com/kwaaka-team/orders-core/service/promotion/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type promotionPaymentsRepositoryStub struct {
+	paymentRepo.PaymentsRepository
+	payments map[string]paymentModels.PaymentOrder
+}
+
+func (r *promotionPaymentsRepositoryStub) GetPaymentOrderByOrderID(ctx context.Context, cartID string) (paymentModels.PaymentOrder, error) {
+	payment, ok := r.payments[cartID]
+	if !ok {
+		return paymentModels.PaymentOrder{}, errs.ErrNotFound
+	}
+	return payment, nil
+}
+
+// promotionServiceStub - пересчет дает скидку 100, как если бы happy hours еще шли
+type promotionServiceStub struct {
+	promotion.Service
+	snapshots    []promotionModels.Result
+	recalculated int
+}
+
+func (s *promotionServiceStub) ApplySnapshot(ctx context.Context, order models.Order, cart models.Cart, result promotionModels.Result) (models.Order, error) {
+	s.snapshots = append(s.snapshots, result)
+	return promotionModels.FillOrderTotals(order, result), nil
+}
+
+func (s *promotionServiceStub) ApplyToOrder(ctx context.Context, order models.Order, cart models.Cart) (models.Order, promotionModels.Result, error) {
+	s.recalculated++
+	result := promotionModels.Result{Discount: 100, Promotions: []promotionModels.AppliedPromotion{{PromotionID: "recalculated", Discount: 100}}}
+	return promotionModels.FillOrderTotals(order, result), result, nil
+}
+
+func TestApplyPromotions_PaidSnapshot(t *testing.T) {
+	paid := promotionModels.Result{Discount: 300, Promotions: []promotionModels.AppliedPromotion{{PromotionID: "happy_hours", Discount: 300}}}
+
+	tests := []struct {
+		name             string
+		payments         map[string]paymentModels.PaymentOrder
+		wantSnapshot     bool
+		wantCustomerPays float64
+	}{
+		{
+			name:             "paid cart gets discounts of payment even after happy hours",
+			payments:         map[string]paymentModels.PaymentOrder{"cart": {OrderID: "cart", Promotions: &paid}},
+			wantSnapshot:     true,
+			wantCustomerPays: 700,
+		},
+		{
+			name:             "payment without promotions is recalculated",
+			payments:         map[string]paymentModels.PaymentOrder{"cart": {OrderID: "cart"}},
+			wantCustomerPays: 900,
+		},
+		{
+			name:             "cart without online payment is recalculated",
+			wantCustomerPays: 900,
+		},
+	}
+
+	for _, test := range tests {
+		promotions := &promotionServiceStub{}
+		s := &ServiceImpl{
+			promotionService: promotions,
+			paymentRepo:      &promotionPaymentsRepositoryStub{payments: test.payments},
+		}
+
+		order := models.Order{OrderID: "cart_1", EstimatedTotalPrice: models.Price{Value: 1000}, TotalCustomerToPay: models.Price{Value: 1000}}
+		res, _ := s.applyPromotions(context.Background(), order, models.Cart{}, "cart")
+
+		assert.Equal(t, test.wantSnapshot, len(promotions.snapshots) == 1, test.name)
+		assert.Equal(t, !test.wantSnapshot, promotions.recalculated == 1, test.name)
+		assert.Equal(t, test.wantCustomerPays, res.TotalCustomerToPay.Value, test.name)
+	}
+}
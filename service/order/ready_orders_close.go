@@ -3,6 +3,9 @@ package order
 import (
 	"context"
 	"github.com/kwaaka-team/orders-core/core/models"
+	"github.com/kwaaka-team/orders-core/service/store"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
 	"time"
 )
 
@@ -10,6 +13,24 @@ type OrderStatusReadyToClose interface {
 	UpdateOrdersWithTwoMoreHours(ctx context.Context) error
 }
 
+// NewAutoCloseService - сервис крона автозакрытия, закрытые заказы передаются подписчикам completedPublisher
+func NewAutoCloseService(repository Repository, storeService store.Service, completedPublisher *Publisher) (*ServiceImpl, error) {
+	if repository == nil {
+		return nil, errors.New("order repository is nil")
+	}
+	if storeService == nil {
+		return nil, errors.New("store service is nil")
+	}
+	if completedPublisher == nil {
+		return nil, errors.New("completed publisher is nil")
+	}
+	return &ServiceImpl{
+		repository:         repository,
+		storeService:       storeService,
+		completedPublisher: completedPublisher,
+	}, nil
+}
+
 func (s *ServiceImpl) UpdateOrdersWithTwoMoreHours(ctx context.Context) error {
 
 	nonActiveOrderStatuses = append(nonActiveOrderStatuses, models.COOKING_COMPLETE.String())
@@ -37,7 +58,14 @@ func (s *ServiceImpl) isStatusMoreThatTwoHours(order models.Order) bool {
 
 	twoHourDuration := 2 * time.Hour
 
-	return currentTime.Sub(order.StatusesHistory[len(order.StatusesHistory)-1].Time) >= twoHourDuration && order.StatusesHistory[len(order.StatusesHistory)-1].Name == order.Status
+	for i := len(order.StatusesHistory) - 1; i >= 0; i-- {
+		if order.StatusesHistory[i].Rejected {
+			continue
+		}
+		return currentTime.Sub(order.StatusesHistory[i].Time) >= twoHourDuration && order.StatusesHistory[i].Name == order.Status
+	}
+
+	return false
 }
 
 func (s *ServiceImpl) closeOrder(ctx context.Context, order models.Order) error {
@@ -50,6 +78,9 @@ func (s *ServiceImpl) closeOrder(ctx context.Context, order models.Order) error
 		if err := s.repository.AddStatusToHistory(ctx, order.OrderID, models.CLOSED.String()); err != nil {
 			return err
 		}
+
+		order.Status = models.CLOSED.String()
+		s.notifyCompleted(ctx, order)
 	} else {
 		if err := s.repository.UpdateOrderStatusByID(ctx, order.ID, order.Status); err != nil {
 			return err
@@ -57,3 +88,20 @@ func (s *ServiceImpl) closeOrder(ctx context.Context, order models.Order) error
 	}
 	return nil
 }
+
+// notifyCompleted передает закрытый заказ подписчикам завершенных заказов, ошибки не прерывают автозакрытие
+func (s *ServiceImpl) notifyCompleted(ctx context.Context, order models.Order) {
+	if s.completedPublisher == nil {
+		return
+	}
+
+	store, err := s.storeService.GetByID(ctx, order.RestaurantID)
+	if err != nil {
+		log.Err(err).Msgf("auto close: get store error, order id: %s", order.ID)
+		return
+	}
+
+	if err := s.completedPublisher.NotifySubscribers(ctx, order, store, models.CLOSED); err != nil {
+		log.Err(err).Msgf("auto close: notify subscribers error, order id: %s", order.ID)
+	}
+}
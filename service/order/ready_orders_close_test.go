@@ -0,0 +1,79 @@
+package order
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kwaaka-team/orders-core/core/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type closeOrderRepositoryStub struct {
+	Repository
+	statuses []string
+}
+
+func (r *closeOrderRepositoryStub) UpdateOrderStatusByID(ctx context.Context, id, status string) error {
+	r.statuses = append(r.statuses, status)
+	return nil
+}
+
+func (r *closeOrderRepositoryStub) AddStatusToHistory(ctx context.Context, id, status string) error {
+	return nil
+}
+
+type completedSubscriberStub struct {
+	orders   []models.Order
+	statuses []models.PosStatus
+}
+
+func (s *completedSubscriberStub) SendOrder(ctx context.Context, order models.Order, store storeModels.Store, posStatus models.PosStatus) error {
+	s.orders = append(s.orders, order)
+	s.statuses = append(s.statuses, posStatus)
+	return nil
+}
+
+func TestCloseOrder_NotifiesCompletedSubscribers(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   string
+		notified bool
+	}{
+		{name: "cooking complete is closed", status: models.COOKING_COMPLETE.String(), notified: true},
+		{name: "other status is kept", status: models.ACCEPTED.String(), notified: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			storeService := &mocks.Service{}
+			storeService.On("GetByID", mock.Anything, "store").Return(storeModels.Store{ID: "store"}, nil)
+
+			subscriber := &completedSubscriberStub{}
+			publisher := &Publisher{}
+			publisher.AddSubscriber(subscriber)
+
+			service, err := NewAutoCloseService(&closeOrderRepositoryStub{}, storeService, publisher)
+			assert.NoError(t, err)
+
+			err = service.closeOrder(context.Background(), models.Order{ID: "order", RestaurantID: "store", Status: tc.status})
+
+			assert.NoError(t, err)
+			if !tc.notified {
+				assert.Empty(t, subscriber.orders)
+				return
+			}
+			if assert.Len(t, subscriber.orders, 1) {
+				assert.Equal(t, models.CLOSED.String(), subscriber.orders[0].Status)
+				assert.Equal(t, models.CLOSED, subscriber.statuses[0])
+			}
+		})
+	}
+}
+
+func TestNewAutoCloseService_RequiresCompletedPublisher(t *testing.T) {
+	_, err := NewAutoCloseService(&closeOrderRepositoryStub{}, &mocks.Service{}, nil)
+	assert.Error(t, err)
+}
@@ -7,11 +7,15 @@ import (
 	"github.com/kwaaka-team/orders-core/core/errors"
 	"github.com/kwaaka-team/orders-core/core/models"
 	"github.com/kwaaka-team/orders-core/core/models/selector"
+	"github.com/kwaaka-team/orders-core/service/order/outbox"
+	"github.com/kwaaka-team/orders-core/service/order/statemachine"
 	"github.com/rs/zerolog/log"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"time"
 )
 
@@ -19,6 +23,8 @@ const orderCollectionName = "orders"
 
 type Repository interface {
 	UpdateOrderStatusByID(ctx context.Context, orderID string, newStatus string) error
+	UpdateOrderStatusWithOutbox(ctx context.Context, orderID string, newStatus string, message outbox.Message) error
+	EnqueueAggregatorStatus(ctx context.Context, message outbox.Message) error
 	UpdateOrder(ctx context.Context, order models.Order) error
 	InsertOrder(ctx context.Context, order models.Order) (models.Order, error)
 	FindOrderByPosOrderID(ctx context.Context, posOrderID string) (models.Order, error)
@@ -31,6 +37,7 @@ type Repository interface {
 	UpdateOrderDeliveryIDByOrderID(ctx context.Context, orderID string, deliveryOrderID string) error
 	GetOrdersByStatusesAndPosType(ctx context.Context, posType string, statuses []string) ([]models.Order, error)
 	FindOrderByOrderID(ctx context.Context, orderID string) (models.Order, error)
+	FindDuplicateOrder(ctx context.Context, order models.Order) (models.Order, error)
 	GetAverageBill(ctx context.Context, order selector.Order) (float64, error)
 	Get3plOrdersForCron(ctx context.Context, indriveCallTime int64) ([]models.Order, error)
 	SetProposals(ctx context.Context, orderID string, proposals []models.Proposal) error
@@ -41,8 +48,16 @@ type Repository interface {
 	GetIIKO3plOrdersForCron(ctx context.Context, callTime int64) ([]models.Order, error)
 	SetCancelledDeliveryDispatcherPrice(ctx context.Context, orderID string, deliveryDispatcherPrice float64) error
 	FindOrderByDeliveryOrderID(ctx context.Context, deliveryID string) (models.Order, error)
+	SetCustomerTrackingStatus(ctx context.Context, orderID string, status string) error
+	SetOrderReview(ctx context.Context, orderID string, review models.Review) error
+	GetSLAOrdersByRestaurants(ctx context.Context, restaurantIDs []string, from, to time.Time) (map[string][]models.Order, error)
+	GetCustomerStatsOrders(ctx context.Context, query selector.Order) ([]models.Order, error)
 }
 
+const statusUpdateAttempts = 3
+
+var errStatusConcurrentUpdate = errorsGo.New("order status was changed concurrently")
+
 var nonActiveOrderStatuses = []string{
 	string(models.STATUS_CANCELLED),
 	models.CLOSED.String(),
@@ -53,12 +68,19 @@ var nonActiveOrderStatuses = []string{
 }
 
 type MongoRepository struct {
-	collection *mongo.Collection
+	collection       *mongo.Collection
+	outboxRepository outbox.Repository
 }
 
 func NewMongoRepository(db *mongo.Database) (*MongoRepository, error) {
+	outboxRepository, err := outbox.NewMongoRepository(db)
+	if err != nil {
+		return nil, err
+	}
+
 	r := MongoRepository{
-		collection: db.Collection(orderCollectionName),
+		collection:       db.Collection(orderCollectionName),
+		outboxRepository: outboxRepository,
 	}
 	return &r, nil
 }
@@ -256,6 +278,19 @@ func (r *MongoRepository) UpdateOrder(ctx context.Context, order models.Order) e
 		{Key: "_id", Value: oid},
 	}
 
+	if order.Status != "" {
+		current, err := r.findOrderForTransition(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		if err = statemachine.For(current).Validate(current.Status, order.Status); err != nil {
+			log.Warn().Msgf("order %s status transition rejected: %s", order.ID, err.Error())
+			order.StatusesHistory = rejectLastStatus(order.StatusesHistory, order.Status, err.Error())
+			order.Status = current.Status
+		}
+	}
+
 	// TODO: models.UpdateOrder, if you want update order and set, it will be error if order.ID was string while setting in primitive.ObjectID
 	order.ID = ""
 	order.UpdatedAt = models.TimeNow()
@@ -294,6 +329,8 @@ func (r *MongoRepository) UpdateOrderDeferStatus(ctx context.Context, status boo
 	return nil
 }
 
+// UpdateOrderStatusByID checks transition by state machine of the order flow.
+// Illegal transition is not applied, it is saved to statuses history as rejected with reason
 func (r *MongoRepository) UpdateOrderStatusByID(ctx context.Context, orderID string, newStatus string) error {
 	query := selector.EmptyOrderSearch().SetID(orderID)
 	filter, err := r.filterFrom(query)
@@ -301,25 +338,128 @@ func (r *MongoRepository) UpdateOrderStatusByID(ctx context.Context, orderID str
 		return errors.ErrorSwitch(err)
 	}
 
-	updateFields := bson.D{{Key: "status", Value: newStatus}}
+	for attempt := 0; attempt < statusUpdateAttempts; attempt++ {
+		order, err := r.findOrderForTransition(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		if err = statemachine.For(order).Validate(order.Status, newStatus); err != nil {
+			log.Warn().Msgf("order %s status transition rejected: %s", orderID, err.Error())
+			if rejectErr := r.rejectStatus(ctx, filter, newStatus, err.Error()); rejectErr != nil {
+				return rejectErr
+			}
+			return err
+		}
+
+		statusHistory := models.OrderStatusUpdate{
+			Name: newStatus,
+			Time: time.Now(),
+		}
+
+		update := bson.D{{Key: "$set", Value: bson.D{{Key: "status", Value: newStatus}}}, {Key: "$push", Value: bson.M{"statuses_history": statusHistory}}}
+
+		// обновляем только если статус не поменялся с момента проверки перехода
+		casFilter := append(filter[:len(filter):len(filter)], bson.E{Key: "status", Value: order.Status})
+
+		res, err := r.collection.UpdateOne(ctx, casFilter, update)
+		if err != nil {
+			return err
+		}
+
+		if res.MatchedCount != 0 {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: order %s, status %s", errStatusConcurrentUpdate, orderID, newStatus)
+}
+
+func (r *MongoRepository) findOrderForTransition(ctx context.Context, filter bson.D) (models.Order, error) {
+	opts := options.FindOne().SetProjection(bson.D{
+		{Key: "status", Value: 1},
+		{Key: "type", Value: 1},
+		{Key: "delivery_service", Value: 1},
+		{Key: "is_marketplace", Value: 1},
+		{Key: "restaurant_self_delivery", Value: 1},
+	})
+
+	var order models.Order
+	if err := r.collection.FindOne(ctx, filter, opts).Decode(&order); err != nil {
+		return models.Order{}, errors.ErrorSwitch(err)
+	}
+
+	return order, nil
+}
 
+// rejectLastStatus marks already appended status as rejected, or appends rejected one
+func rejectLastStatus(history []models.OrderStatusUpdate, status, reason string) []models.OrderStatusUpdate {
+	if last := len(history) - 1; last >= 0 && history[last].Name == status && !history[last].Rejected {
+		history[last].Rejected = true
+		history[last].Reason = reason
+		return history
+	}
+
+	return append(history, models.OrderStatusUpdate{
+		Name:     status,
+		Time:     time.Now(),
+		Rejected: true,
+		Reason:   reason,
+	})
+}
+
+func (r *MongoRepository) rejectStatus(ctx context.Context, filter bson.D, status, reason string) error {
 	statusHistory := models.OrderStatusUpdate{
-		Name: newStatus,
-		Time: time.Now(),
+		Name:     status,
+		Time:     time.Now(),
+		Rejected: true,
+		Reason:   reason,
 	}
 
-	update := bson.D{{Key: "$set", Value: updateFields}, {Key: "$push", Value: bson.M{"statuses_history": statusHistory}}}
+	_, err := r.collection.UpdateOne(ctx, filter, bson.D{{Key: "$push", Value: bson.M{"statuses_history": statusHistory}}})
+	return err
+}
 
-	res, err := r.collection.UpdateOne(ctx, filter, update)
+// UpdateOrderStatusWithOutbox updates order status and saves aggregator status push to outbox in one transaction,
+// so status in aggregator can not be lost if aggregator is unavailable at the moment
+func (r *MongoRepository) UpdateOrderStatusWithOutbox(ctx context.Context, orderID string, newStatus string, message outbox.Message) error {
+	session, err := r.collection.Database().Client().StartSession()
 	if err != nil {
 		return err
 	}
+	defer session.EndSession(context.Background())
 
-	if res.MatchedCount == 0 {
-		return errors.ErrNotFound
+	txOpts := options.Transaction().
+		SetWriteConcern(writeconcern.Majority()).
+		SetReadPreference(readpref.Primary())
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if err := r.UpdateOrderStatusByID(sessCtx, orderID, newStatus); err != nil {
+			return nil, err
+		}
+
+		return r.outboxRepository.Insert(sessCtx, message)
+	}, txOpts)
+
+	// запись об отклоненном статусе откатилась вместе с транзакцией
+	var transitionErr *statemachine.TransitionError
+	if errorsGo.As(err, &transitionErr) {
+		filter, filterErr := r.filterFrom(selector.EmptyOrderSearch().SetID(orderID))
+		if filterErr != nil {
+			return errors.ErrorSwitch(filterErr)
+		}
+		if rejectErr := r.rejectStatus(ctx, filter, newStatus, transitionErr.Error()); rejectErr != nil {
+			return rejectErr
+		}
 	}
 
-	return nil
+	return err
+}
+
+// EnqueueAggregatorStatus saves status push to aggregator in outbox without order status update
+func (r *MongoRepository) EnqueueAggregatorStatus(ctx context.Context, message outbox.Message) error {
+	_, err := r.outboxRepository.Insert(ctx, message)
+	return err
 }
 
 func (r *MongoRepository) filterFrom(query selector.Order) (bson.D, error) {
@@ -429,6 +569,15 @@ func (r *MongoRepository) filterFrom(query selector.Order) (bson.D, error) {
 		})
 	}
 
+	if query.HasCustomerPhones() {
+		result = append(result, bson.E{
+			Key: "customer.phone_number",
+			Value: bson.D{
+				{Key: "$in", Value: query.CustomerPhones},
+			},
+		})
+	}
+
 	if query.HasOrderTimeTo() {
 		result = append(result, bson.E{
 			Key: "order_time.value",
@@ -631,6 +780,23 @@ func (r *MongoRepository) AddStatusToHistory(ctx context.Context, id, status str
 	return nil
 }
 
+// FindDuplicateOrder - сохраненный заказ с тем же ключом уникального индекса (order_id, store_id, delivery_service, restaurant_id)
+func (r *MongoRepository) FindDuplicateOrder(ctx context.Context, order models.Order) (models.Order, error) {
+	filter := bson.D{
+		{Key: "order_id", Value: order.OrderID},
+		{Key: "store_id", Value: order.StoreID},
+		{Key: "delivery_service", Value: order.DeliveryService},
+		{Key: "restaurant_id", Value: order.RestaurantID},
+	}
+
+	var res models.Order
+	if err := r.collection.FindOne(ctx, filter).Decode(&res); err != nil {
+		return models.Order{}, errors.ErrorSwitch(err)
+	}
+
+	return res, nil
+}
+
 func (r *MongoRepository) FindOrderByOrderID(ctx context.Context, orderID string) (models.Order, error) {
 	query := selector.EmptyOrderSearch().SetOrderID(orderID)
 
@@ -689,10 +855,15 @@ func (r *MongoRepository) GetAverageBill(ctx context.Context, query selector.Ord
 }
 
 func (r *MongoRepository) filterFromAverageBill(query selector.Order) []bson.M {
+	var customerPhone interface{} = query.CustomerNumber
+	if query.HasCustomerPhones() {
+		customerPhone = bson.M{"$in": query.CustomerPhones}
+	}
+
 	pipeline := []bson.M{
 		{
 			"$match": bson.M{
-				"customer.phone_number": query.CustomerNumber,
+				"customer.phone_number": customerPhone,
 				"restaurant_id": bson.M{
 					"$in": query.Restaurants,
 				},
@@ -717,6 +888,92 @@ func (r *MongoRepository) filterFromAverageBill(query selector.Order) []bson.M {
 	return pipeline
 }
 
+// GetSLAOrdersByRestaurants - заказы ресторанов за период, сгруппированные по restaurant_id.
+// Заказы читаются курсором по одному, возвращаются только поля, нужные для расчета SLA
+func (r *MongoRepository) GetSLAOrdersByRestaurants(ctx context.Context, restaurantIDs []string, from, to time.Time) (map[string][]models.Order, error) {
+	if len(restaurantIDs) == 0 {
+		return map[string][]models.Order{}, nil
+	}
+
+	filter := bson.D{
+		{Key: "restaurant_id", Value: bson.M{"$in": restaurantIDs}},
+		{Key: "order_time.value", Value: bson.M{
+			"$gte": primitive.NewDateTimeFromTime(from),
+			"$lte": primitive.NewDateTimeFromTime(to),
+		}},
+		{Key: "is_test_order", Value: bson.M{"$ne": true}},
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "restaurant_id", Value: 1}, {Key: "order_time.value", Value: 1}}).
+		SetProjection(bson.D{
+			{Key: "order_id", Value: 1},
+			{Key: "order_code", Value: 1},
+			{Key: "restaurant_id", Value: 1},
+			{Key: "restaurant_name", Value: 1},
+			{Key: "delivery_service", Value: 1},
+			{Key: "pos_type", Value: 1},
+			{Key: "order_time", Value: 1},
+			{Key: "created_at", Value: 1},
+			{Key: "reading_time", Value: 1},
+			{Key: "statuses_history", Value: 1},
+		})
+
+	cur, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	res := make(map[string][]models.Order, len(restaurantIDs))
+	for cur.Next(ctx) {
+		var order models.Order
+		if err = cur.Decode(&order); err != nil {
+			return nil, err
+		}
+		res[order.RestaurantID] = append(res[order.RestaurantID], order)
+	}
+	if err = cur.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetCustomerStatsOrders - заказы по фильтру только с полями, нужными для статистики клиентов
+func (r *MongoRepository) GetCustomerStatsOrders(ctx context.Context, query selector.Order) ([]models.Order, error) {
+	filter, err := r.filterFrom(query)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetProjection(bson.D{
+		{Key: "restaurant_id", Value: 1},
+		{Key: "delivery_service", Value: 1},
+		{Key: "order_time", Value: 1},
+		{Key: "status", Value: 1},
+		{Key: "is_test_order", Value: 1},
+		{Key: "estimated_total_price", Value: 1},
+		{Key: "customer.name", Value: 1},
+		{Key: "customer.phone_number", Value: 1},
+		{Key: "products.id", Value: 1},
+		{Key: "products.name", Value: 1},
+		{Key: "products.quantity", Value: 1},
+	})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]models.Order, 0, cursor.RemainingBatchLength())
+	if err := cursor.All(ctx, &orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
 func (r *MongoRepository) Get3plOrdersForCron(ctx context.Context, callTime int64) ([]models.Order, error) {
 
 	log.Info().Msgf("start to get orders for cron [Get3plOrdersForCron] BulkCreate3plOrder")
@@ -947,6 +1204,48 @@ func (r *MongoRepository) SetDeliveryDispatcherPrice(ctx context.Context, orderI
 	return nil
 }
 
+func (r *MongoRepository) SetCustomerTrackingStatus(ctx context.Context, orderID string, status string) error {
+	oid, err := primitive.ObjectIDFromHex(orderID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: oid},
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "customer_tracking_status", Value: status}}},
+	}
+
+	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *MongoRepository) SetOrderReview(ctx context.Context, orderID string, review models.Review) error {
+	oid, err := primitive.ObjectIDFromHex(orderID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: oid},
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "review", Value: review}}},
+	}
+
+	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (r *MongoRepository) SetCancelledDeliveryDispatcherPrice(ctx context.Context, orderID string, deliveryDispatcherPrice float64) error {
 	oid, err := primitive.ObjectIDFromHex(orderID)
 	if err != nil {
@@ -19,9 +19,13 @@ import (
 	"github.com/kwaaka-team/orders-core/service/error_solutions"
 	models2 "github.com/kwaaka-team/orders-core/service/error_solutions/models"
 	"github.com/kwaaka-team/orders-core/service/menu"
+	"github.com/kwaaka-team/orders-core/service/order/outbox"
+	"github.com/kwaaka-team/orders-core/service/order/statemachine"
 	"github.com/kwaaka-team/orders-core/service/order_rules"
 	paymentRepo "github.com/kwaaka-team/orders-core/service/payment/repository"
 	"github.com/kwaaka-team/orders-core/service/pos"
+	"github.com/kwaaka-team/orders-core/service/promotion"
+	promotionModels "github.com/kwaaka-team/orders-core/service/promotion/models"
 	"github.com/kwaaka-team/orders-core/service/store"
 	storeGroupServicePkg "github.com/kwaaka-team/orders-core/service/storegroup"
 	"github.com/pkg/errors"
@@ -55,6 +59,10 @@ type CreationService interface {
 }
 
 type ReviewService interface {
+	RequestReview(ctx context.Context, order models.Order, store storeModels.Store) error
+	GetReview(ctx context.Context, token string) (models.OrderReview, error)
+	SubmitReview(ctx context.Context, token string, req models.SubmitOrderReviewRequest) (models.SubmitOrderReviewResponse, error)
+	Ratings(ctx context.Context, query models.ReviewRatingsRequest) (models.ReviewRatings, error)
 }
 
 type InfoSharingService interface {
@@ -67,6 +75,11 @@ type CancellationService interface {
 	CancelOrderByAggregator(ctx context.Context, orderID string, delivery string) error
 }
 
+type ModificationService interface {
+	UpdateOrderItemsByAggregator(ctx context.Context, orderID, delivery string, products []models.OrderProduct) (models.Order, error)
+	UpdateOrderItemsByAggregatorRequest(ctx context.Context, externalStoreID, delivery string, aggReq interface{}) (models.Order, error)
+}
+
 type ServiceImpl struct {
 	storeService      store.Service
 	aggregatorFactory aggregator.Factory
@@ -79,6 +92,10 @@ type ServiceImpl struct {
 	storeClient       storeClient.Client
 	storeGroupService storeGroupServicePkg.Service
 	publisher         *Publisher
+	// completedPublisher - подписчики заказов, закрытых кроном автозакрытия
+	completedPublisher *Publisher
+	// outboxPublisher - запуск отправки outbox статусов в агрегатор, без него статусы отправляет крон outbox
+	outboxPublisher *Publisher
 
 	orderRuleService order_rules.Service
 	posSender        PosSender
@@ -88,6 +105,8 @@ type ServiceImpl struct {
 	cartService CartService
 
 	errSolution error_solutions.Service
+
+	promotionService promotion.Service
 }
 
 func NewServiceImpl(repository Repository) (*ServiceImpl, error) {
@@ -120,6 +139,14 @@ type ServiceFactory struct {
 	CartService CartService
 
 	ErrSolution error_solutions.Service
+
+	// PromotionService - акции прямых каналов, без него скидки акций в заказ не записываются
+	PromotionService promotion.Service
+
+	// CompletedPublisher - подписчики заказов, закрытых кроном автозакрытия, например запрос отзыва. Может быть пустым
+	CompletedPublisher *Publisher
+	// OutboxPublisher - подписчик отправки outbox статусов в агрегатор. Может быть пустым
+	OutboxPublisher *Publisher
 }
 
 func (f ServiceFactory) Create() (*ServiceImpl, error) {
@@ -142,6 +169,10 @@ func (f ServiceFactory) Create() (*ServiceImpl, error) {
 		paymentRepo:       f.PaymentRepo,
 		cartService:       f.CartService,
 		errSolution:       f.ErrSolution,
+		promotionService:  f.PromotionService,
+
+		completedPublisher: f.CompletedPublisher,
+		outboxPublisher:    f.OutboxPublisher,
 	}, nil
 }
 
@@ -266,23 +297,19 @@ func (s *ServiceImpl) checkOrder(order models.Order) bool {
 	return true
 }
 
+// sendOrderReadyStatusToAgg отправляет статус готовности отложенного заказа в агрегатор через outbox
 func (s *ServiceImpl) sendOrderReadyStatusToAgg(ctx context.Context, order models.Order, store storeModels.Store) error {
-	aggService, err := s.aggregatorFactory.GetAggregator(order.DeliveryService, store)
-	if err != nil {
-		return err
-	}
-
 	if !s.validateOrderTime(order, store) {
 		return nil
 	}
 
 	switch order.DeliveryService {
 	case models.GLOVO.String(), models.YANDEX.String():
-		if err := aggService.UpdateOrderInAggregator(ctx, order, store, models.READY_FOR_PICKUP.String()); err != nil {
+		if err := enqueueAggregatorPush(ctx, s.repository, s.outboxPublisher, order, store, models.READY_FOR_PICKUP, models.READY_FOR_PICKUP.String()); err != nil {
 			log.Err(err).Msgf("error while updating status in %s, error: %s , orderID %s", order.DeliveryService, err, order.ID)
 		}
 	case models.WOLT.String():
-		if err := aggService.UpdateOrderInAggregator(ctx, order, store, models.Ready.String()); err != nil {
+		if err := enqueueAggregatorPush(ctx, s.repository, s.outboxPublisher, order, store, models.READY_FOR_PICKUP, models.Ready.String()); err != nil {
 			log.Err(err).Msgf("error while updating status in %s, error: %s , orderID %s", order.DeliveryService, err, order.ID)
 		}
 	}
@@ -570,7 +597,18 @@ func (s *ServiceImpl) updateOrderStatus(ctx context.Context, store storeModels.S
 		return nil
 	}
 
-	if err = s.repository.UpdateOrderStatusByID(ctx, order.ID, newSystemStatus.String()); err != nil {
+	message, sendToAggregator, aggErr := s.newAggregatorOutboxMessage(store, order, newSystemStatus)
+
+	if sendToAggregator {
+		err = s.repository.UpdateOrderStatusWithOutbox(ctx, order.ID, newSystemStatus.String(), message)
+	} else {
+		err = s.repository.UpdateOrderStatusByID(ctx, order.ID, newSystemStatus.String())
+	}
+	if errors.Is(err, statemachine.ErrIllegalTransition) {
+		log.Warn().Msgf("order status quarantined, order_id=%s: %s", order.ID, err.Error())
+		return nil
+	}
+	if err != nil {
 		return err
 	}
 
@@ -579,27 +617,28 @@ func (s *ServiceImpl) updateOrderStatus(ctx context.Context, store storeModels.S
 		log.Err(err).Msgf("createOrder subscribers error")
 	}
 
+	return aggErr
+}
+
+// newAggregatorOutboxMessage returns status push to aggregator, which will be saved with order status and sent by outbox
+func (s *ServiceImpl) newAggregatorOutboxMessage(store storeModels.Store, order models.Order, newSystemStatus models.PosStatus) (outbox.Message, bool, error) {
 	ignoreStatusUpdate, err := s.ignoreStatusUpdateInAggregator(store, order, newSystemStatus)
 	if err != nil {
-		return err
+		return outbox.Message{}, false, err
 	}
 
 	if ignoreStatusUpdate {
-		return nil
+		return outbox.Message{}, false, nil
 	}
 
 	aggregatorService, err := s.aggregatorFactory.GetAggregator(order.DeliveryService, store)
 	if err != nil {
-		return err
+		return outbox.Message{}, false, err
 	}
 
 	aggStatus := aggregatorService.MapSystemStatusToAggregatorStatus(order, newSystemStatus, store)
 
-	if err = aggregatorService.UpdateOrderInAggregator(ctx, order, store, aggStatus); err != nil {
-		return err
-	}
-
-	return nil
+	return outbox.NewMessage(order.ID, order.OrderID, store.ID, order.DeliveryService, newSystemStatus.String(), aggStatus), true, nil
 }
 
 func (s *ServiceImpl) isIgnoreRepeatedOrderStatus(currentSystemStatus, newSystemStatus string) bool {
@@ -779,6 +818,7 @@ func (s *ServiceImpl) CreateOrder(ctx context.Context, externalStoreID, delivery
 
 	req = s.setDeferSubmission(req, st)
 
+	releasePromotions := func(ctx context.Context) {}
 	switch req.DeliveryService {
 	case models.QRMENU.String():
 		req.CookingTime = st.QRMenu.CookingTime
@@ -788,6 +828,10 @@ func (s *ServiceImpl) CreateOrder(ctx context.Context, externalStoreID, delivery
 			return req, err
 		}
 		req.PaymentSystem = cart.PaymentSystem
+		if req, err = applyDeliveryZone(req, st); err != nil {
+			return req, err
+		}
+		req, releasePromotions = s.applyPromotions(ctx, req, cart, strings.Split(req.OrderID, "_")[0])
 	case models.KWAAKA_ADMIN.String():
 		req.CookingTime = st.QRMenu.CookingTime
 
@@ -796,14 +840,25 @@ func (s *ServiceImpl) CreateOrder(ctx context.Context, externalStoreID, delivery
 			return req, err
 		}
 		req.PaymentSystem = cart.PaymentType
+		if req, err = applyDeliveryZone(req, st); err != nil {
+			return req, err
+		}
+		req, releasePromotions = s.applyPromotions(ctx, req, cart, strings.Split(req.OrderID, "_")[0])
 	}
 
 	errSolutions, err := s.errSolution.GetAllErrorSolutions(ctx)
 	if err != nil {
+		releasePromotions(ctx)
 		return models.Order{}, err
 	}
 
 	order, err := s.saveOrderToDb(ctx, req)
+	if err != nil {
+		releasePromotions(ctx)
+	}
+	if errors.Is(err, validator.ErrPassed) {
+		return order, err
+	}
 
 	//TODO delete
 	skipErrors := false
@@ -881,15 +936,7 @@ func (s *ServiceImpl) CreateOrder(ctx context.Context, externalStoreID, delivery
 	}
 
 	if isAutoAcceptOn {
-		order.Status = models.ACCEPTED.String()
-
-		aggStatus := agg.MapSystemStatusToAggregatorStatus(order, models.ACCEPTED, st)
-
-		if err = agg.UpdateOrderInAggregator(ctx, order, st, aggStatus); err != nil {
-			return models.Order{}, err
-		}
-
-		if err = s.repository.UpdateOrderStatusByID(ctx, order.ID, models.ACCEPTED.String()); err != nil {
+		if order, err = s.acceptOrder(ctx, agg, order, st); err != nil {
 			return models.Order{}, err
 		}
 		if deliveryService == models.YANDEX.String() {
@@ -924,15 +971,7 @@ func (s *ServiceImpl) CreateOrder(ctx context.Context, externalStoreID, delivery
 		return order, err
 	}
 	if isPostAutoAcceptOn {
-		order.Status = models.ACCEPTED.String()
-
-		aggStatus := agg.MapSystemStatusToAggregatorStatus(order, models.ACCEPTED, st)
-
-		if err = agg.UpdateOrderInAggregator(ctx, order, st, aggStatus); err != nil {
-			return models.Order{}, err
-		}
-
-		if err = s.repository.UpdateOrderStatusByID(ctx, order.ID, models.ACCEPTED.String()); err != nil {
+		if order, err = s.acceptOrder(ctx, agg, order, st); err != nil {
 			return models.Order{}, err
 		}
 		if deliveryService == models.YANDEX.String() {
@@ -962,6 +1001,11 @@ func fillRequestData(req models.Order, st storeModels.Store, aggregatorRequestBo
 		req.LogMessages.FromDelivery = aggregatorRequestBody
 	}
 
+	if contract, ok := st.AggregatorCommissions.Find(req.DeliveryService); ok {
+		commission := contract.Calculate(req)
+		req.AggregatorCommission = &commission
+	}
+
 	return req
 }
 
@@ -970,16 +1014,15 @@ func (s *ServiceImpl) saveOrderToDb(ctx context.Context, req models.Order) (mode
 	if err != nil {
 		log.Err(err).Msgf("orders core, insert order error")
 
+		// IdempotencyDecorator не покрывает дочерние заказы виртуального ресторана и заказы без ключа claim,
+		// для них дубль ловит только уникальный индекс заказов. Сохраненный заказ не перезаписываем, а возвращаем
 		if errors.Is(err, errs.ErrAlreadyExist) {
 			log.Info().Msg("Order already exist, skipping...")
-			req.FailReason = models.FailReason{
-				Code:    pos.ORDER_ALREADY_EXIST_CODE,
-				Message: pos.ORDER_ALREADY_EXIST,
+			existing, findErr := s.repository.FindDuplicateOrder(ctx, req)
+			if findErr != nil {
+				log.Err(findErr).Msgf("find existing order %s", req.OrderID)
 			}
-			if err1 := s.repository.UpdateOrder(ctx, req); err1 != nil {
-				return models.Order{}, err
-			}
-			return req, errors.Wrap(validator.ErrPassed, fmt.Sprintf("order %s passed", req.OrderID))
+			return existing, errors.Wrap(validator.ErrPassed, fmt.Sprintf("order %s passed", req.OrderID))
 		}
 
 		return s.failOrder(ctx, order, err.Error())
@@ -1057,30 +1100,37 @@ func (s *ServiceImpl) successOrder(ctx context.Context, req models.Order) (model
 	return req, nil
 }
 
+// acceptOrder принимает заказ: статус ACCEPTED сохраняется вместе с outbox сообщением для агрегатора
+func (s *ServiceImpl) acceptOrder(ctx context.Context, agg aggregator.Aggregator, order models.Order, store storeModels.Store) (models.Order, error) {
+	order.Status = models.ACCEPTED.String()
+
+	aggStatus := agg.MapSystemStatusToAggregatorStatus(order, models.ACCEPTED, store)
+
+	if err := enqueueAggregatorStatus(ctx, s.repository, s.outboxPublisher, order, store, models.ACCEPTED, aggStatus); err != nil {
+		return order, err
+	}
+
+	return order, nil
+}
+
 func (s *ServiceImpl) waitSendingOrder(ctx context.Context, order models.Order, store storeModels.Store) error {
 
 	log.Info().Msgf("Order waiting sending, status: %v", string(models.STATUS_WAIT_SENDING))
 
 	systemStatus := models.WAIT_SENDING
 
-	if err := s.repository.UpdateOrderStatusByID(ctx, order.ID, systemStatus.String()); err != nil {
-		return err
+	if order.IsChildOrder {
+		return s.repository.UpdateOrderStatusByID(ctx, order.ID, systemStatus.String())
 	}
 
-	if !order.IsChildOrder {
-		agg, err := s.aggregatorFactory.GetAggregator(order.DeliveryService, store)
-		if err != nil {
-			return err
-		}
-
-		aggStatus := agg.MapSystemStatusToAggregatorStatus(order, systemStatus, store)
-
-		if err = agg.UpdateOrderInAggregator(ctx, order, store, aggStatus); err != nil {
-			return err
-		}
+	agg, err := s.aggregatorFactory.GetAggregator(order.DeliveryService, store)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	aggStatus := agg.MapSystemStatusToAggregatorStatus(order, systemStatus, store)
+
+	return enqueueAggregatorStatus(ctx, s.repository, s.outboxPublisher, order, store, systemStatus, aggStatus)
 }
 
 func (s *ServiceImpl) getPayments(paymentMethod string, paymentTypes storeModels.DeliveryServicePaymentType) models.PosPaymentInfo {
@@ -1248,6 +1298,62 @@ func (s *ServiceImpl) setDeferSubmission(order models.Order, store storeModels.S
 	return order
 }
 
+// applyPromotions - ошибка акций не должна мешать созданию заказа, заказ уходит без скидок акций.
+// Если корзина оплачена онлайн, применяются скидки, сохраненные в платеже при оплате, иначе скидки считаются заново.
+// Возвращает откат учтенных использований акций на случай, если заказ не сохранится
+func (s *ServiceImpl) applyPromotions(ctx context.Context, order models.Order, cart models.Cart, cartID string) (models.Order, func(ctx context.Context)) {
+	release := func(ctx context.Context) {}
+	if s.promotionService == nil {
+		return order, release
+	}
+
+	result, ok := s.paidPromotions(ctx, cartID)
+	if ok {
+		res, err := s.promotionService.ApplySnapshot(ctx, order, cart, result)
+		if err != nil {
+			log.Err(err).Msgf("apply paid promotions to order %s", order.OrderID)
+			return res, release
+		}
+		return res, s.releasePromotions(cart, result, order.OrderID)
+	}
+
+	res, result, err := s.promotionService.ApplyToOrder(ctx, order, cart)
+	if err != nil {
+		log.Err(err).Msgf("apply promotions to order %s", order.OrderID)
+		return res, release
+	}
+
+	return res, s.releasePromotions(cart, result, order.OrderID)
+}
+
+// paidPromotions - снимок скидок акций из онлайн оплаты корзины
+func (s *ServiceImpl) paidPromotions(ctx context.Context, cartID string) (promotionModels.Result, bool) {
+	if s.paymentRepo == nil {
+		return promotionModels.Result{}, false
+	}
+
+	paymentOrder, err := s.paymentRepo.GetPaymentOrderByOrderID(ctx, cartID)
+	if err != nil {
+		if !errors.Is(err, errs.ErrNotFound) {
+			log.Err(err).Msgf("get payment order of cart %s for promotions", cartID)
+		}
+		return promotionModels.Result{}, false
+	}
+	if paymentOrder.Promotions == nil {
+		return promotionModels.Result{}, false
+	}
+
+	return *paymentOrder.Promotions, true
+}
+
+func (s *ServiceImpl) releasePromotions(cart models.Cart, result promotionModels.Result, orderID string) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		if err := s.promotionService.ReleaseUsages(ctx, cart, result); err != nil {
+			log.Err(err).Msgf("release promotion usages of order %s", orderID)
+		}
+	}
+}
+
 func (s *ServiceImpl) getItemsAvailableStatus(ctx context.Context, order models.Order, posMenuID string) error {
 
 	menu, err := s.menuService.GetMenuById(ctx, posMenuID)
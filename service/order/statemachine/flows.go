@@ -0,0 +1,148 @@
+package statemachine
+
+import (
+	"fmt"
+	"github.com/kwaaka-team/orders-core/core/models"
+)
+
+var (
+	newStatuses = []string{
+		string(models.STATUS_NEW),
+		string(models.STATUS_PENDING),
+		string(models.STATUS_PROCESSING),
+	}
+	waitSendingStatuses = []string{
+		models.WAIT_SENDING.String(),
+	}
+	acceptedStatuses = []string{
+		models.ACCEPTED.String(),
+		models.WAIT_COOKING.String(),
+		models.READY_FOR_COOKING.String(),
+	}
+	cookingStartedStatuses = []string{
+		models.COOKING_STARTED.String(),
+	}
+	cookingCompleteStatuses = []string{
+		models.COOKING_COMPLETE.String(),
+	}
+	readyStatuses = []string{
+		models.READY_FOR_PICKUP.String(),
+	}
+	// курьер забрал заказ, порядок этих статусов у pos систем разный
+	handoverStatuses = []string{
+		models.OUT_FOR_DELIVERY.String(),
+		models.ON_WAY.String(),
+		models.PICKED_UP_BY_CUSTOMER.String(),
+	}
+	deliveredStatuses = []string{
+		models.DELIVERED.String(),
+	}
+	closedStatuses = []string{
+		models.CLOSED.String(),
+	}
+	cancelledStatuses = []string{
+		string(models.STATUS_CANCELLED),
+		string(models.STATUS_CANCELLED_BY_DELIVERY_SERVICE),
+		models.CANCELLED_BY_POS_SYSTEM.String(),
+	}
+	failedStatuses = []string{
+		models.FAILED.String(),
+		string(models.STATUS_SKIPPED),
+	}
+	paymentStatuses = []string{
+		models.PAYMENT_NEW.String(),
+		models.PAYMENT_IN_PROGRESS.String(),
+		models.PAYMENT_SUCCESS.String(),
+		models.PAYMENT_CANCELLED.String(),
+		models.PAYMENT_WAITING.String(),
+		models.PAYMENT_DELETED.String(),
+	}
+)
+
+// deliveryServiceRules - дополнительные переходы для отдельных каналов продаж
+var deliveryServiceRules = map[string][]Rule{
+	models.QRMENU.String():       paymentRules(),
+	models.KWAAKA_ADMIN.String(): paymentRules(),
+	models.STARTERAPP.String():   paymentRules(),
+}
+
+// For returns flow by delivery service and order type.
+// Who delivers the order does not change transitions: DELIVERED is sent not only for restaurant courier, but by kwaaka 3pl and tillypad, foodband pos
+func For(order models.Order) Flow {
+	orderType := order.Type
+	if orderType == "" {
+		orderType = models.ORDER_TYPE_INSTANT
+	}
+
+	return build(order.DeliveryService, orderType)
+}
+
+func build(deliveryService, orderType string) Flow {
+	stages := [][]string{newStatuses, acceptedStatuses, cookingStartedStatuses, cookingCompleteStatuses, readyStatuses, handoverStatuses, deliveredStatuses, closedStatuses}
+
+	rules := [][]Rule{
+		forward(stages...),
+		waitSendingRules(orderType),
+		cancelRules(stages),
+		{
+			// повторная отправка упавшего заказа
+			{From: failedStatuses, To: join(newStatuses, waitSendingStatuses, acceptedStatuses, cancelledStatuses)},
+			// сторно закрытого заказа на кассе
+			{From: closedStatuses, To: []string{models.CANCELLED_BY_POS_SYSTEM.String()}},
+		},
+		deliveryServiceRules[deliveryService],
+	}
+
+	return NewFlow(fmt.Sprintf("%s/%s", deliveryService, orderType), rules...)
+}
+
+// forward allows to move to any next stage, pos systems skip statuses often
+func forward(stages ...[]string) []Rule {
+	rules := make([]Rule, 0, len(stages))
+	for i, stage := range stages {
+		rules = append(rules, Rule{From: stage, To: join(stages[i:]...)})
+	}
+
+	return rules
+}
+
+func waitSendingRules(orderType string) []Rule {
+	from := newStatuses
+	if orderType == models.ORDER_TYPE_PREORDER {
+		// предзаказ может быть принят и отложен до времени отправки
+		from = join(newStatuses, acceptedStatuses)
+	}
+
+	return []Rule{
+		{From: from, To: waitSendingStatuses},
+		{From: waitSendingStatuses, To: join(newStatuses, acceptedStatuses, cookingStartedStatuses, cookingCompleteStatuses, readyStatuses, handoverStatuses, deliveredStatuses, closedStatuses)},
+	}
+}
+
+func cancelRules(stages [][]string) []Rule {
+	active := join(append(stages[:len(stages)-1:len(stages)-1], waitSendingStatuses)...)
+
+	return []Rule{
+		{From: active, To: join(cancelledStatuses, []string{models.FAILED.String()})},
+		{From: newStatuses, To: []string{string(models.STATUS_SKIPPED)}},
+		{From: cancelledStatuses, To: cancelledStatuses},
+	}
+}
+
+func paymentRules() []Rule {
+	active := join(newStatuses, waitSendingStatuses, acceptedStatuses, cookingStartedStatuses, cookingCompleteStatuses, readyStatuses, handoverStatuses, deliveredStatuses)
+
+	return []Rule{
+		{From: join(active, paymentStatuses), To: paymentStatuses},
+		{From: paymentStatuses, To: join(active, closedStatuses, cancelledStatuses, []string{models.FAILED.String()})},
+	}
+}
+
+func join(groups ...[]string) []string {
+	var result []string
+	for _, group := range groups {
+		result = append(result, group...)
+	}
+
+	return result
+}
@@ -0,0 +1,88 @@
+package statemachine
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"sort"
+)
+
+var ErrIllegalTransition = errors.New("illegal order status transition")
+
+// Rule allows transition from any of From statuses to any of To statuses
+type Rule struct {
+	From []string
+	To   []string
+}
+
+type Flow struct {
+	Name        string
+	transitions map[string]map[string]struct{}
+}
+
+func NewFlow(name string, rules ...[]Rule) Flow {
+	flow := Flow{
+		Name:        name,
+		transitions: make(map[string]map[string]struct{}),
+	}
+
+	for _, ruleSet := range rules {
+		for _, rule := range ruleSet {
+			for _, from := range rule.From {
+				if _, ok := flow.transitions[from]; !ok {
+					flow.transitions[from] = make(map[string]struct{})
+				}
+				for _, to := range rule.To {
+					flow.transitions[from][to] = struct{}{}
+				}
+			}
+		}
+	}
+
+	return flow
+}
+
+// Can checks transition. Repeated status and first status of order are always allowed
+func (f Flow) Can(from, to string) bool {
+	if from == "" || from == to {
+		return true
+	}
+
+	_, ok := f.transitions[from][to]
+	return ok
+}
+
+func (f Flow) Allowed(from string) []string {
+	result := make([]string, 0, len(f.transitions[from]))
+	for to := range f.transitions[from] {
+		result = append(result, to)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+func (f Flow) Validate(from, to string) error {
+	if f.Can(from, to) {
+		return nil
+	}
+
+	return &TransitionError{
+		Flow: f.Name,
+		From: from,
+		To:   to,
+	}
+}
+
+type TransitionError struct {
+	Flow string
+	From string
+	To   string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s is not allowed in flow %s", ErrIllegalTransition, e.From, e.To, e.Flow)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrIllegalTransition
+}
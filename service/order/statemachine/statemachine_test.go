@@ -0,0 +1,131 @@
+package statemachine
+
+import (
+	"github.com/kwaaka-team/orders-core/core/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		order   models.Order
+		to      string
+		allowed bool
+	}{
+		{
+			name:    "accept new order",
+			order:   models.Order{DeliveryService: "wolt", Status: "NEW"},
+			to:      "ACCEPTED",
+			allowed: true,
+		},
+		{
+			name:    "skip cooking statuses",
+			order:   models.Order{DeliveryService: "glovo", Status: "ACCEPTED"},
+			to:      "READY_FOR_PICKUP",
+			allowed: true,
+		},
+		{
+			name:    "repeated status",
+			order:   models.Order{DeliveryService: "glovo", Status: "COOKING_STARTED"},
+			to:      "COOKING_STARTED",
+			allowed: true,
+		},
+		{
+			name:    "closed order goes back to cooking",
+			order:   models.Order{DeliveryService: "wolt", Status: "CLOSED"},
+			to:      "COOKING_STARTED",
+			allowed: false,
+		},
+		{
+			name:    "cancelled order becomes ready",
+			order:   models.Order{DeliveryService: "yandex", Status: "CANCELLED_BY_DELIVERY_SERVICE"},
+			to:      "READY_FOR_PICKUP",
+			allowed: false,
+		},
+		{
+			name:    "storno of closed order",
+			order:   models.Order{DeliveryService: "wolt", Status: "CLOSED"},
+			to:      "CANCELLED_BY_POS_SYSTEM",
+			allowed: true,
+		},
+		{
+			name:    "instant order is not postponed after accept",
+			order:   models.Order{DeliveryService: "wolt", Type: "INSTANT", Status: "ACCEPTED"},
+			to:      "WAIT_SENDING",
+			allowed: false,
+		},
+		{
+			name:    "preorder is postponed after accept",
+			order:   models.Order{DeliveryService: "wolt", Type: "PREORDER", Status: "ACCEPTED"},
+			to:      "WAIT_SENDING",
+			allowed: true,
+		},
+		{
+			name:    "aggregator courier delivers order",
+			order:   models.Order{DeliveryService: "glovo", Status: "OUT_FOR_DELIVERY"},
+			to:      "DELIVERED",
+			allowed: true,
+		},
+		{
+			name:    "kwaaka 3pl courier delivers qr menu order",
+			order:   models.Order{DeliveryService: "qr_menu", Status: "ON_WAY"},
+			to:      "DELIVERED",
+			allowed: true,
+		},
+		{
+			name:    "pos skips handover and sends delivered",
+			order:   models.Order{DeliveryService: "qr_menu", Status: "READY_FOR_PICKUP"},
+			to:      "DELIVERED",
+			allowed: true,
+		},
+		{
+			name:    "delivered order is closed",
+			order:   models.Order{DeliveryService: "qr_menu", Status: "DELIVERED"},
+			to:      "CLOSED",
+			allowed: true,
+		},
+		{
+			name:    "delivered order goes back to ready",
+			order:   models.Order{DeliveryService: "qr_menu", Status: "DELIVERED"},
+			to:      "READY_FOR_PICKUP",
+			allowed: false,
+		},
+		{
+			name:    "restaurant courier delivers order",
+			order:   models.Order{DeliveryService: "glovo", IsMarketplace: true, Status: "OUT_FOR_DELIVERY"},
+			to:      "DELIVERED",
+			allowed: true,
+		},
+		{
+			name:    "payment in qr menu",
+			order:   models.Order{DeliveryService: "qr_menu", Status: "NEW"},
+			to:      "PAYMENT_SUCCESS",
+			allowed: true,
+		},
+		{
+			name:    "payment in aggregator",
+			order:   models.Order{DeliveryService: "wolt", Status: "NEW"},
+			to:      "PAYMENT_SUCCESS",
+			allowed: false,
+		},
+		{
+			name:    "failed order resent",
+			order:   models.Order{DeliveryService: "wolt", Status: "FAILED"},
+			to:      "ACCEPTED",
+			allowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := For(tt.order).Validate(tt.order.Status, tt.to)
+			if tt.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, ErrIllegalTransition))
+		})
+	}
+}
@@ -160,6 +160,12 @@ func (s TelegramServiceImpl) SendMessageToQueue(notificationType telegram.Notifi
 	case telegram.AutoUpdatePublicateMenu:
 		message = msg
 		chatIDs = append(chatIDs, s.notificationConfig.AutoUpdatePublicateNotificationChatID)
+	case telegram.LowRatingReview:
+		message = msg
+		chatIDs = append(chatIDs, store.Telegram.ReviewChatID)
+		if store.Telegram.ReviewChatID == "" {
+			chatIDs = append(chatIDs, store.Telegram.GroupChatID)
+		}
 	case telegram.PutProductToStopListWithErrSolution:
 		message = telegram.ConstructPutProductToStopListWithErrSolutionMessage(store, product, err)
 		if len(message) > 0 {
@@ -49,15 +49,7 @@ func (s *ServiceImpl) splitVirtualStoreOrder(ctx context.Context, store storeMod
 	}
 
 	if isAutoAcceptOn {
-		order.Status = models.ACCEPTED.String()
-
-		aggStatus := agg.MapSystemStatusToAggregatorStatus(order, models.ACCEPTED, store)
-
-		if err = agg.UpdateOrderInAggregator(ctx, order, store, aggStatus); err != nil {
-			return models.Order{}, err
-		}
-
-		if err = s.repository.UpdateOrderStatusByID(ctx, order.ID, models.ACCEPTED.String()); err != nil {
+		if order, err = s.acceptOrder(ctx, agg, order, store); err != nil {
 			return models.Order{}, err
 		}
 	}
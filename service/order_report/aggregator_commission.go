@@ -0,0 +1,167 @@
+package order_report
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/kwaaka-team/orders-core/core/models"
+	"github.com/kwaaka-team/orders-core/core/models/selector"
+	selector2 "github.com/kwaaka-team/orders-core/core/storecore/managers/selector"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/pkg/errors"
+	"github.com/tealeg/xlsx"
+)
+
+// AggregatorCommissionAnalytics - комиссии и чистая выручка по агрегаторам для ресторанов группы за период
+func (or *OrderReportImpl) AggregatorCommissionAnalytics(ctx context.Context, query models.AggregatorCommissionAnalyticsRequest) (models.AggregatorCommissionAnalyticsResponse, error) {
+	if !query.StartDate.Before(query.EndDate) {
+		return models.AggregatorCommissionAnalyticsResponse{}, errors.New("start date must be before end date")
+	}
+
+	stores, err := or.storeService.GetRestaurantsByGroupId(ctx, selector2.Pagination{}, query.RestaurantGroupID)
+	if err != nil {
+		return models.AggregatorCommissionAnalyticsResponse{}, err
+	}
+
+	storeIDs := make([]string, 0, len(stores))
+	for _, st := range stores {
+		storeIDs = append(storeIDs, st.ID)
+	}
+
+	var orders []models.Order
+	if len(storeIDs) != 0 {
+		orders, _, err = or.repository.GetAllOrders(ctx, selector.EmptyOrderSearch().
+			SetRestaurants(storeIDs).
+			SetOrderTimeFrom(query.StartDate).
+			SetOrderTimeTo(query.EndDate))
+		if err != nil {
+			return models.AggregatorCommissionAnalyticsResponse{}, err
+		}
+	}
+
+	res := aggregatorCommissionAnalytics(orders, stores)
+	res.RestaurantGroupID = query.RestaurantGroupID
+	res.StartDate = query.StartDate
+	res.EndDate = query.EndDate
+
+	return res, nil
+}
+
+func (or *OrderReportImpl) AggregatorCommissionAnalyticsToXlsx(ctx context.Context, query models.AggregatorCommissionAnalyticsRequest) ([]byte, error) {
+	analytics, err := or.AggregatorCommissionAnalytics(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	file := xlsx.NewFile()
+	sheet, err := file.AddSheet("AggregatorCommissions")
+	if err != nil {
+		return nil, err
+	}
+
+	header := sheet.AddRow()
+	for _, title := range []string{"Ресторан", "Агрегатор", "Кол-во заказов", "Сумма заказов", "Скидки партнера", "Стоимость промо для ресторана",
+		"Комиссия агрегатора", "Чистая выручка", "Комиссия, %", "Чистая выручка, %"} {
+		header.AddCell().Value = title
+	}
+
+	addRow := func(restaurantName string, aggregator models.AggregatorCommissionAnalytics) {
+		row := sheet.AddRow()
+		row.AddCell().Value = restaurantName
+		row.AddCell().Value = aggregator.DeliveryService
+		row.AddCell().Value = strconv.Itoa(aggregator.OrdersCount)
+		for _, value := range []float64{aggregator.Gross, aggregator.Promo, aggregator.PromoCost, aggregator.Commission,
+			aggregator.NetRevenue, aggregator.CommissionPercent, aggregator.NetRevenuePercent} {
+			row.AddCell().Value = strconv.FormatFloat(value, 'f', 2, 64)
+		}
+	}
+
+	for _, restaurant := range analytics.Restaurants {
+		for _, aggregator := range restaurant.Aggregators {
+			addRow(restaurant.RestaurantName, aggregator)
+		}
+	}
+	for _, aggregator := range analytics.Aggregators {
+		addRow("Итого", aggregator)
+	}
+
+	var b bytes.Buffer
+	if err = file.Write(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// aggregatorCommissionAnalytics считает итоги по заказам агрегаторов, прямые и тестовые заказы и отмененные пропускаются.
+// Для заказов, созданных до заведения договора, комиссия считается по текущему договору ресторана
+func aggregatorCommissionAnalytics(orders []models.Order, stores []storeModels.Store) models.AggregatorCommissionAnalyticsResponse {
+	storesByID := make(map[string]storeModels.Store, len(stores))
+	for _, st := range stores {
+		storesByID[st.ID] = st
+	}
+
+	totals := make(map[string]*models.AggregatorCommissionAnalytics)
+	byRestaurant := make(map[string]map[string]*models.AggregatorCommissionAnalytics)
+
+	add := func(analytics map[string]*models.AggregatorCommissionAnalytics, deliveryService string, commission models.OrderCommission) {
+		if _, ok := analytics[deliveryService]; !ok {
+			analytics[deliveryService] = &models.AggregatorCommissionAnalytics{DeliveryService: deliveryService}
+		}
+		analytics[deliveryService].Add(commission)
+	}
+
+	for _, order := range orders {
+		if order.IsTestOrder || isCancelledOrder(order.Status) ||
+			order.DeliveryService == directDeliveryService || order.DeliveryService == kwaakaAdminDeliveryService {
+			continue
+		}
+
+		var commission models.OrderCommission
+		switch contract, ok := storesByID[order.RestaurantID].AggregatorCommissions.Find(order.DeliveryService); {
+		case order.AggregatorCommission != nil:
+			commission = *order.AggregatorCommission
+		case ok:
+			commission = contract.Calculate(order)
+		default:
+			commission = models.CalculateAggregatorCommission(order, 0, 0, 0)
+		}
+
+		add(totals, order.DeliveryService, commission)
+
+		if _, ok := byRestaurant[order.RestaurantID]; !ok {
+			byRestaurant[order.RestaurantID] = make(map[string]*models.AggregatorCommissionAnalytics)
+		}
+		add(byRestaurant[order.RestaurantID], order.DeliveryService, commission)
+	}
+
+	res := models.AggregatorCommissionAnalyticsResponse{
+		Aggregators: sortedAggregatorCommissions(totals),
+	}
+	for _, st := range stores {
+		analytics, ok := byRestaurant[st.ID]
+		if !ok {
+			continue
+		}
+		res.Restaurants = append(res.Restaurants, models.RestaurantAggregatorCommissionAnalytics{
+			RestaurantID:   st.ID,
+			RestaurantName: st.Name,
+			Aggregators:    sortedAggregatorCommissions(analytics),
+		})
+	}
+
+	return res
+}
+
+func sortedAggregatorCommissions(analytics map[string]*models.AggregatorCommissionAnalytics) []models.AggregatorCommissionAnalytics {
+	res := make([]models.AggregatorCommissionAnalytics, 0, len(analytics))
+	for _, aggregator := range analytics {
+		res = append(res, *aggregator)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].DeliveryService < res[j].DeliveryService
+	})
+	return res
+}
@@ -0,0 +1,53 @@
+package order_report
+
+import (
+	"testing"
+
+	"github.com/kwaaka-team/orders-core/core/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+)
+
+func TestAggregatorCommissionAnalytics(t *testing.T) {
+	stores := []storeModels.Store{
+		{
+			ID:   "store",
+			Name: "Store",
+			AggregatorCommissions: storeModels.AggregatorCommissionContracts{
+				{DeliveryService: "glovo", Percentage: 20, FixedFee: 100, AggregatorPromoShare: 50},
+			},
+		},
+	}
+
+	stored := models.OrderCommission{Gross: 5000, Commission: 1500, NetRevenue: 3500}
+	orders := []models.Order{
+		// 20% от 9000 + 100, половину скидки 1000 компенсирует glovo
+		{RestaurantID: "store", DeliveryService: "glovo", EstimatedTotalPrice: models.Price{Value: 10000}, PartnerDiscountsProducts: models.Price{Value: 1000}},
+		{RestaurantID: "store", DeliveryService: "wolt", AggregatorCommission: &stored},
+		// без договора комиссия не считается
+		{RestaurantID: "store", DeliveryService: "yandex", EstimatedTotalPrice: models.Price{Value: 2000}},
+		{RestaurantID: "store", DeliveryService: "glovo", EstimatedTotalPrice: models.Price{Value: 3000}, Status: string(models.STATUS_CANCELLED)},
+		{RestaurantID: "store", DeliveryService: "qr_menu", EstimatedTotalPrice: models.Price{Value: 3000}},
+		{RestaurantID: "store", DeliveryService: "glovo", EstimatedTotalPrice: models.Price{Value: 3000}, IsTestOrder: true},
+	}
+
+	res := aggregatorCommissionAnalytics(orders, stores)
+
+	expected := []models.AggregatorCommissionAnalytics{
+		{DeliveryService: "glovo", OrdersCount: 1, Gross: 10000, Promo: 1000, PromoCost: 500, Commission: 1900, NetRevenue: 7600, CommissionPercent: 19, NetRevenuePercent: 76},
+		{DeliveryService: "wolt", OrdersCount: 1, Gross: 5000, Commission: 1500, NetRevenue: 3500, CommissionPercent: 30, NetRevenuePercent: 70},
+		{DeliveryService: "yandex", OrdersCount: 1, Gross: 2000, NetRevenue: 2000, NetRevenuePercent: 100},
+	}
+
+	if len(res.Aggregators) != len(expected) {
+		t.Fatalf("expected %d aggregators, got %+v", len(expected), res.Aggregators)
+	}
+	for i := range expected {
+		if res.Aggregators[i] != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], res.Aggregators[i])
+		}
+	}
+
+	if len(res.Restaurants) != 1 || len(res.Restaurants[0].Aggregators) != len(expected) {
+		t.Errorf("unexpected restaurants %+v", res.Restaurants)
+	}
+}
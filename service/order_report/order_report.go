@@ -10,6 +10,8 @@ import (
 	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
 	"github.com/kwaaka-team/orders-core/service/kwaaka_3pl"
 	"github.com/kwaaka-team/orders-core/service/order"
+	"github.com/kwaaka-team/orders-core/service/refund"
+	settlementModels "github.com/kwaaka-team/orders-core/service/settlement/models"
 	"github.com/kwaaka-team/orders-core/service/store"
 	"github.com/kwaaka-team/orders-core/service/storegroup"
 	"github.com/rs/zerolog/log"
@@ -37,12 +39,23 @@ var (
 	payments                     = map[string]string{"by_cashier": "By Cashier", "kaspi_salescout": "Kaspi Salescout", "ioka": "Ioka", "cash": "Cash"}
 )
 
+// Ставки комиссий, общие для отчета и сверки: сервисный сбор Kwaaka с суммы заказа и эквайринг платежной системы с доставки для клиента
+const (
+	onlinePaymentFeeRate            = 0.05
+	offlinePaymentFeeRate           = 0.03
+	iokaAcquiringFeeRate            = 0.029
+	kaspiSalesScoutAcquiringFeeRate = 0.025
+)
+
 type OrderReport interface {
 	OrderReportForRestaurant(ctx context.Context, query models.OrderReportRequest) (models.OrderReportResponse, error)
 	OrderReportForRestaurantTotals(ctx context.Context, query models.OrderReportRequest) (models.OrderReportResponse, error)
 	OrderReportForKwaakaTotals(ctx context.Context, query models.OrderReportRequest) (models.OrderReportResponse, error)
 	OrderReportToXlsx(ctx context.Context, query models.OrderReportRequest) ([]byte, error)
 	DeliveryDispatcherPrice(ctx context.Context) error
+	SettlementLines(ctx context.Context, storeIDs []string, from, to time.Time) ([]settlementModels.Line, error)
+	AggregatorCommissionAnalytics(ctx context.Context, query models.AggregatorCommissionAnalyticsRequest) (models.AggregatorCommissionAnalyticsResponse, error)
+	AggregatorCommissionAnalyticsToXlsx(ctx context.Context, query models.AggregatorCommissionAnalyticsRequest) ([]byte, error)
 }
 
 type OrderReportImpl struct {
@@ -51,15 +64,17 @@ type OrderReportImpl struct {
 	kwaaka3pl         kwaaka_3pl.Service
 	cartService       order.CartService
 	storeGroupService storegroup.Service
+	refundRepo        refund.Repository
 }
 
-func NewOrderReportService(storeService store.Service, repository order.Repository, kwaaka3pl kwaaka_3pl.Service, cartService order.CartService, storeGroupService storegroup.Service) *OrderReportImpl {
+func NewOrderReportService(storeService store.Service, repository order.Repository, kwaaka3pl kwaaka_3pl.Service, cartService order.CartService, storeGroupService storegroup.Service, refundRepo refund.Repository) *OrderReportImpl {
 	return &OrderReportImpl{
 		storeService:      storeService,
 		repository:        repository,
 		kwaaka3pl:         kwaaka3pl,
 		cartService:       cartService,
 		storeGroupService: storeGroupService,
+		refundRepo:        refundRepo,
 	}
 }
 
@@ -113,6 +128,11 @@ func (or *OrderReportImpl) OrderReportForRestaurant(ctx context.Context, query m
 
 	totalOrdersCount += kwaakaAdminTotalOrdersCount
 
+	refundedAmounts, err := or.getRefundedItemsAmounts(ctx, orders)
+	if err != nil {
+		return models.OrderReportResponse{}, err
+	}
+
 	for _, ordr := range orders {
 		wg.Add(1)
 		go func(order models.Order) {
@@ -182,6 +202,9 @@ func (or *OrderReportImpl) OrderReportForRestaurant(ctx context.Context, query m
 
 			dispatcherDeliveryHistoryPricesSum := or.getDispatcherDeliveryHistoryPricesSUM(deliveryHistoricalDeliveries)
 
+			refundedAmount := refundedAmounts[order.OrderID]
+			productsPrice := order.EstimatedTotalPrice.Value - refundedAmount
+
 			report := models.OrderReport{
 				OrderID:                 order.OrderID,
 				RestaurantID:            order.RestaurantID,
@@ -206,15 +229,16 @@ func (or *OrderReportImpl) OrderReportForRestaurant(ctx context.Context, query m
 				PaymentSystem:                paymentSystemBeautified,
 				EstimatedTotalPrice:          order.EstimatedTotalPrice.Value,
 				TotalOrderPrice:              order.EstimatedTotalPrice.Value + order.ClientDeliveryPrice,
+				RefundedAmount:               refundedAmount,
 				CustomerName:                 order.Customer.Name,
 				CustomerPhone:                order.Customer.PhoneNumber,
 				SendCourier:                  order.SendCourier,
 				Products:                     order.Products,
 				Numbers: models.OrderReportNumbers{
-					RestaurantIncome:  or.getIncome(reportForRestaurant, paymentSystem, order.EstimatedTotalPrice.Value, order.ClientDeliveryPrice, calculatedDeliveryHistoryPricesSum, dispatcherDeliveryHistoryPricesSum),
-					KwaakaIncome:      or.getIncome(reportForKwaaka, paymentSystem, order.EstimatedTotalPrice.Value, order.ClientDeliveryPrice, calculatedDeliveryHistoryPricesSum, dispatcherDeliveryHistoryPricesSum),
-					BalanceKwaaka:     or.getBalance(reportForRestaurant, paymentSystem, order.EstimatedTotalPrice.Value, order.ClientDeliveryPrice, calculatedDeliveryHistoryPricesSum),
-					BalanceRestaurant: or.getBalance(reportForKwaaka, paymentSystem, order.EstimatedTotalPrice.Value, order.ClientDeliveryPrice, calculatedDeliveryHistoryPricesSum),
+					RestaurantIncome:  or.getIncome(reportForRestaurant, paymentSystem, productsPrice, order.ClientDeliveryPrice, calculatedDeliveryHistoryPricesSum, dispatcherDeliveryHistoryPricesSum),
+					KwaakaIncome:      or.getIncome(reportForKwaaka, paymentSystem, productsPrice, order.ClientDeliveryPrice, calculatedDeliveryHistoryPricesSum, dispatcherDeliveryHistoryPricesSum),
+					BalanceKwaaka:     or.getBalance(reportForRestaurant, paymentSystem, productsPrice, order.ClientDeliveryPrice, calculatedDeliveryHistoryPricesSum),
+					BalanceRestaurant: or.getBalance(reportForKwaaka, paymentSystem, productsPrice, order.ClientDeliveryPrice, calculatedDeliveryHistoryPricesSum),
 					// Прогнозируемые цены
 					ProjectedDeliveryHistoryPrices:    or.getProjectedDeliveryHistoryPrices(deliveryHistoricalDeliveries),
 					ProjectedDeliveryHistoryPricesSUM: or.getProjectedDeliveryHistoryPricesSum(deliveryHistoricalDeliveries),
@@ -227,7 +251,7 @@ func (or *OrderReportImpl) OrderReportForRestaurant(ctx context.Context, query m
 
 					ClientDeliveryPrice:        order.ClientDeliveryPrice,
 					KwaakaChargedDeliveryPrice: math.Ceil(order.KwaakaChargedDeliveryPrice),
-					BankBalance:                or.getBankBalance(paymentSystem, order.EstimatedTotalPrice.Value, order.ClientDeliveryPrice),
+					BankBalance:                or.getBankBalance(paymentSystem, productsPrice, order.ClientDeliveryPrice),
 					DeliveryBalance:            dispatcherDeliveryHistoryPricesSum,
 				},
 			}
@@ -275,6 +299,11 @@ func (or *OrderReportImpl) OrderReportForRestaurantTotals(ctx context.Context, q
 		return models.OrderReportResponse{}, err
 	}
 
+	refundedAmounts, err := or.getRefundedItemsAmounts(ctx, orders)
+	if err != nil {
+		return models.OrderReportResponse{}, err
+	}
+
 	for _, ordr := range orders {
 
 		wg.Add(1)
@@ -299,9 +328,11 @@ func (or *OrderReportImpl) OrderReportForRestaurantTotals(ctx context.Context, q
 				return
 			}
 
+			productsPrice := order.EstimatedTotalPrice.Value - refundedAmounts[order.OrderID]
+
 			orderTotalPrice := order.EstimatedTotalPrice.Value + order.ClientDeliveryPrice
-			income := or.getIncome(reportForRestaurant, paymentSystem, order.EstimatedTotalPrice.Value, order.ClientDeliveryPrice, 0, 0)
-			balance := or.getBalance(reportForRestaurant, paymentSystem, order.EstimatedTotalPrice.Value, order.ClientDeliveryPrice, 0)
+			income := or.getIncome(reportForRestaurant, paymentSystem, productsPrice, order.ClientDeliveryPrice, 0, 0)
+			balance := or.getBalance(reportForRestaurant, paymentSystem, productsPrice, order.ClientDeliveryPrice, 0)
 
 			mut.Lock()
 			defer mut.Unlock()
@@ -351,6 +382,11 @@ func (or *OrderReportImpl) OrderReportForKwaakaTotals(ctx context.Context, query
 		return models.OrderReportResponse{}, err
 	}
 
+	refundedAmounts, err := or.getRefundedItemsAmounts(ctx, orders)
+	if err != nil {
+		return models.OrderReportResponse{}, err
+	}
+
 	for _, ordr := range orders {
 
 		wg.Add(1)
@@ -375,9 +411,11 @@ func (or *OrderReportImpl) OrderReportForKwaakaTotals(ctx context.Context, query
 				return
 			}
 
+			productsPrice := order.EstimatedTotalPrice.Value - refundedAmounts[order.OrderID]
+
 			orderTotalPrice := order.EstimatedTotalPrice.Value + order.ClientDeliveryPrice
-			income := or.getIncome(reportForKwaaka, paymentSystem, order.EstimatedTotalPrice.Value, order.ClientDeliveryPrice, 0, 0)
-			balance := or.getBalance(reportForKwaaka, paymentSystem, order.EstimatedTotalPrice.Value, order.ClientDeliveryPrice, 0)
+			income := or.getIncome(reportForKwaaka, paymentSystem, productsPrice, order.ClientDeliveryPrice, 0, 0)
+			balance := or.getBalance(reportForKwaaka, paymentSystem, productsPrice, order.ClientDeliveryPrice, 0)
 
 			mut.Lock()
 			defer mut.Unlock()
@@ -467,6 +505,26 @@ func (or *OrderReportImpl) getMapStoreGroupByStores(ctx context.Context, storesM
 	return restGrByRestMap, storeIDs, nil
 }
 
+// getRefundedItemsAmounts - суммы возвратов по позициям без сервисного сбора по заказам, вычитаются из дохода и баланса
+func (or *OrderReportImpl) getRefundedItemsAmounts(ctx context.Context, orders []models.Order) (map[string]float64, error) {
+	orderIDs := make([]string, 0, len(orders))
+	for _, order := range orders {
+		orderIDs = append(orderIDs, order.OrderID)
+	}
+
+	refunds, err := or.refundRepo.GetRefundsByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	amounts := make(map[string]float64, len(refunds))
+	for _, refund := range refunds {
+		amounts[refund.OrderID] += float64(refund.LinesItemsAmount())
+	}
+
+	return amounts, nil
+}
+
 func (or *OrderReportImpl) getIncome(reportFor, paymentSystem string, estimatedTotalPrice, clientDeliveryPrice, calculatedDeliveryHistoryPricesSum, dispatcherDeliveryHistoryPricesSum float64) float64 {
 	switch reportFor {
 
@@ -495,27 +553,27 @@ func (or *OrderReportImpl) getIncome(reportFor, paymentSystem string, estimatedT
 }
 
 func (or *OrderReportImpl) incomeForRestaurantIOKA(estimatedTotalPrice, clientDeliveryPrice, calculatedDeliveryHistoryPricesSum float64) float64 {
-	return estimatedTotalPrice - math.Ceil(estimatedTotalPrice*0.05) - math.Ceil(0.029*clientDeliveryPrice) - (calculatedDeliveryHistoryPricesSum - clientDeliveryPrice)
+	return estimatedTotalPrice - math.Ceil(estimatedTotalPrice*onlinePaymentFeeRate) - math.Ceil(iokaAcquiringFeeRate*clientDeliveryPrice) - (calculatedDeliveryHistoryPricesSum - clientDeliveryPrice)
 }
 
 func (or *OrderReportImpl) incomeForRestaurantKaspiOrByCashierOrCash(estimatedTotalPrice, clientDeliveryPrice, calculatedDeliveryHistoryPricesSum float64) float64 {
-	return estimatedTotalPrice - math.Ceil(estimatedTotalPrice*0.03) - (calculatedDeliveryHistoryPricesSum - clientDeliveryPrice)
+	return estimatedTotalPrice - math.Ceil(estimatedTotalPrice*offlinePaymentFeeRate) - (calculatedDeliveryHistoryPricesSum - clientDeliveryPrice)
 }
 
 func (or *OrderReportImpl) incomeForRestaurantKaspiSalesCount(estimatedTotalPrice, clientDeliveryPrice, calculatedDeliveryHistoryPricesSum float64) float64 {
-	return estimatedTotalPrice - math.Ceil(estimatedTotalPrice*0.05) - math.Ceil(0.025*clientDeliveryPrice) - (calculatedDeliveryHistoryPricesSum - clientDeliveryPrice)
+	return estimatedTotalPrice - math.Ceil(estimatedTotalPrice*onlinePaymentFeeRate) - math.Ceil(kaspiSalesScoutAcquiringFeeRate*clientDeliveryPrice) - (calculatedDeliveryHistoryPricesSum - clientDeliveryPrice)
 }
 
 func (or *OrderReportImpl) incomeForKwaakaIOKA(estimatedTotalPrice, calculatedDeliveryHistoryPricesSum, dispatcherDeliveryHistoryPricesSum float64) float64 {
-	return math.Ceil(0.021*estimatedTotalPrice) + calculatedDeliveryHistoryPricesSum - dispatcherDeliveryHistoryPricesSum
+	return math.Ceil((onlinePaymentFeeRate-iokaAcquiringFeeRate)*estimatedTotalPrice) + calculatedDeliveryHistoryPricesSum - dispatcherDeliveryHistoryPricesSum
 }
 
 func (or *OrderReportImpl) incomeForKwaakaKaspiOrByCashierOrCash(estimatedTotalPrice, calculatedDeliveryHistoryPricesSum, dispatcherDeliveryHistoryPricesSum float64) float64 {
-	return math.Ceil(0.03*estimatedTotalPrice) + calculatedDeliveryHistoryPricesSum - dispatcherDeliveryHistoryPricesSum
+	return math.Ceil(offlinePaymentFeeRate*estimatedTotalPrice) + calculatedDeliveryHistoryPricesSum - dispatcherDeliveryHistoryPricesSum
 }
 
 func (or *OrderReportImpl) incomeForKwaakaKaspiSalesCount(estimatedTotalPrice, calculatedDeliveryHistoryPricesSum, dispatcherDeliveryHistoryPricesSum float64) float64 {
-	return math.Ceil(0.025*estimatedTotalPrice) + calculatedDeliveryHistoryPricesSum - dispatcherDeliveryHistoryPricesSum
+	return math.Ceil((onlinePaymentFeeRate-kaspiSalesScoutAcquiringFeeRate)*estimatedTotalPrice) + calculatedDeliveryHistoryPricesSum - dispatcherDeliveryHistoryPricesSum
 }
 
 func (or *OrderReportImpl) getBalance(reportFor, paymentSystem string, estimatedTotalPrice, clientDeliveryPrice, calculatedDeliveryHistoryPricesSum float64) float64 {
@@ -546,27 +604,27 @@ func (or *OrderReportImpl) getBalance(reportFor, paymentSystem string, estimated
 }
 
 func (or *OrderReportImpl) balanceForKwaakaIOKA(estimatedTotalPrice, clientDeliveryPrice, calculatedDeliveryHistoryPricesSum float64) float64 {
-	return -estimatedTotalPrice + math.Ceil(estimatedTotalPrice*0.05) + math.Ceil(0.029*clientDeliveryPrice) + (calculatedDeliveryHistoryPricesSum - clientDeliveryPrice)
+	return -estimatedTotalPrice + math.Ceil(estimatedTotalPrice*onlinePaymentFeeRate) + math.Ceil(iokaAcquiringFeeRate*clientDeliveryPrice) + (calculatedDeliveryHistoryPricesSum - clientDeliveryPrice)
 }
 
 func (or *OrderReportImpl) balanceForKwaakaKaspi(estimatedTotalPrice, calculatedDeliveryHistoryPricesSum float64) float64 {
-	return math.Ceil(+0.03*estimatedTotalPrice) + calculatedDeliveryHistoryPricesSum
+	return math.Ceil(+offlinePaymentFeeRate*estimatedTotalPrice) + calculatedDeliveryHistoryPricesSum
 }
 
 func (or *OrderReportImpl) balanceForKwaakaKaspiSalesCount(estimatedTotalPrice, clientDeliveryPrice, calculatedDeliveryHistoryPricesSum float64) float64 {
-	return -estimatedTotalPrice + math.Ceil(estimatedTotalPrice*0.05) + math.Ceil(0.025*clientDeliveryPrice) + (calculatedDeliveryHistoryPricesSum - clientDeliveryPrice)
+	return -estimatedTotalPrice + math.Ceil(estimatedTotalPrice*onlinePaymentFeeRate) + math.Ceil(kaspiSalesScoutAcquiringFeeRate*clientDeliveryPrice) + (calculatedDeliveryHistoryPricesSum - clientDeliveryPrice)
 }
 
 func (or *OrderReportImpl) balanceForRestaurantIOKA(estimatedTotalPrice, clientDeliveryPrice, calculatedDeliveryHistoryPricesSum float64) float64 {
-	return estimatedTotalPrice - math.Ceil(estimatedTotalPrice*0.05) - math.Ceil(0.029*clientDeliveryPrice) - (calculatedDeliveryHistoryPricesSum - clientDeliveryPrice)
+	return estimatedTotalPrice - math.Ceil(estimatedTotalPrice*onlinePaymentFeeRate) - math.Ceil(iokaAcquiringFeeRate*clientDeliveryPrice) - (calculatedDeliveryHistoryPricesSum - clientDeliveryPrice)
 }
 
 func (or *OrderReportImpl) balanceForRestaurantKaspi(estimatedTotalPrice, calculatedDeliveryHistoryPricesSum float64) float64 {
-	return math.Ceil(-0.03*estimatedTotalPrice) - calculatedDeliveryHistoryPricesSum
+	return math.Ceil(-offlinePaymentFeeRate*estimatedTotalPrice) - calculatedDeliveryHistoryPricesSum
 }
 
 func (or *OrderReportImpl) balanceForRestaurantKaspiSalesCount(estimatedTotalPrice, clientDeliveryPrice, calculatedDeliveryHistoryPricesSum float64) float64 {
-	return estimatedTotalPrice - math.Ceil(estimatedTotalPrice*0.05) - math.Ceil(0.025*clientDeliveryPrice) - (calculatedDeliveryHistoryPricesSum - clientDeliveryPrice)
+	return estimatedTotalPrice - math.Ceil(estimatedTotalPrice*onlinePaymentFeeRate) - math.Ceil(kaspiSalesScoutAcquiringFeeRate*clientDeliveryPrice) - (calculatedDeliveryHistoryPricesSum - clientDeliveryPrice)
 }
 
 func (or *OrderReportImpl) getBankBalance(paymentSystem string, estimatedTotalPrice, clientDeliveryPrice float64) float64 {
@@ -580,11 +638,11 @@ func (or *OrderReportImpl) getBankBalance(paymentSystem string, estimatedTotalPr
 }
 
 func (or *OrderReportImpl) getIokaBalanceFieldForKwaaka(estimatedTotalPrice, clientDeliveryPrice float64) float64 {
-	return (estimatedTotalPrice + clientDeliveryPrice) * 0.029
+	return (estimatedTotalPrice + clientDeliveryPrice) * iokaAcquiringFeeRate
 }
 
 func (or *OrderReportImpl) getKaspiSalesCountBalanceFieldForKwaaka(estimatedTotalPrice, clientDeliveryPrice float64) float64 {
-	return (estimatedTotalPrice + clientDeliveryPrice) * 0.025
+	return (estimatedTotalPrice + clientDeliveryPrice) * kaspiSalesScoutAcquiringFeeRate
 }
 
 func (or *OrderReportImpl) isFailedOrder(status string) bool {
@@ -0,0 +1,174 @@
+package order_report
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/kwaaka-team/orders-core/core/models"
+	"github.com/kwaaka-team/orders-core/core/models/selector"
+	settlementModels "github.com/kwaaka-team/orders-core/service/settlement/models"
+	"github.com/rs/zerolog/log"
+)
+
+// SettlementLines - строки сверки по заказам kwaaka direct и kwaaka admin ресторанов за период, формулы те же, что в отчете для ресторана
+func (or *OrderReportImpl) SettlementLines(ctx context.Context, storeIDs []string, from, to time.Time) ([]settlementModels.Line, error) {
+	var (
+		lines []settlementModels.Line
+		wg    sync.WaitGroup
+		mut   sync.Mutex
+	)
+
+	if len(storeIDs) == 0 {
+		return nil, nil
+	}
+
+	var orders []models.Order
+	for _, deliveryService := range []string{directDeliveryService, kwaakaAdminDeliveryService} {
+		res, _, err := or.repository.GetAllOrders(ctx, selector.EmptyOrderSearch().
+			SetRestaurants(storeIDs).
+			SetDeliveryService(deliveryService).
+			SetOrderTimeFrom(from).
+			SetOrderTimeTo(to))
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, res...)
+	}
+
+	refundedAmounts, err := or.getRefundedItemsAmounts(ctx, orders)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ordr := range orders {
+		if ordr.IsTestOrder || isCancelledOrder(ordr.Status) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(order models.Order) {
+			defer wg.Done()
+
+			paymentSystem, err := or.GetPaymentSystem(ctx, order.DeliveryService, order.OrderID)
+			if err != nil {
+				log.Error().Msgf("settlement payment system for order %s error: %s", order.OrderID, err.Error())
+				mut.Lock()
+				lines = append(lines, unreconciledLine(order, err))
+				mut.Unlock()
+				return
+			}
+
+			deliveryStatus, err := or.GetOrderDeliveryStatus(ctx, order.OrderID)
+			if err != nil {
+				deliveryStatus = noInfo
+			}
+
+			driverDeliveryPrice, err := or.GetDeliveryPrice(ctx, order.DeliveryOrderID)
+			if err != nil {
+				driverDeliveryPrice = 0
+			}
+
+			deliveries, _, err := or.GetHistoricalDeliveriesInfo(ctx, order, deliveryStatus, driverDeliveryPrice, order.History3plDeliveryInfo, order.Canceled3PlDeliveryInfo)
+			if err != nil {
+				log.Error().Msgf("settlement lines for order %s error: %s", order.OrderID, err.Error())
+			}
+
+			orderLines := settlementLines(order, paymentSystem, refundedAmounts[order.OrderID], deliveries)
+
+			mut.Lock()
+			lines = append(lines, orderLines...)
+			mut.Unlock()
+		}(ordr)
+	}
+
+	wg.Wait()
+
+	return lines, nil
+}
+
+// settlementLines раскладывает заказ на строки сверки.
+// Деньги клиента проходят через Kwaaka только при оплате через ioka и kaspi salescout, тогда заказ и возвраты идут в пользу ресторана.
+// Комиссия платежной системы считается от суммы заказа за вычетом возвратов, доставка - по расчетной цене (max из прогнозной и фактической с наценкой Kwaaka)
+func settlementLines(order models.Order, paymentSystem string, refundedAmount float64, deliveries []models.DeliveryHistory) []settlementModels.Line {
+	newLine := func(lineType, description string, amount float64) settlementModels.Line {
+		return settlementModels.Line{
+			Type:           lineType,
+			OrderID:        order.OrderID,
+			RestaurantID:   order.RestaurantID,
+			RestaurantName: order.RestaurantName,
+			PaymentSystem:  paymentSystem,
+			Date:           order.OrderTime.Value.Time,
+			Description:    description,
+			Amount:         amount,
+		}
+	}
+
+	var lines []settlementModels.Line
+	productsPrice := order.EstimatedTotalPrice.Value - refundedAmount
+
+	var feeRate, deliveryFeeRate float64
+	switch paymentSystem {
+	case iokaPaymentSystem:
+		feeRate, deliveryFeeRate = onlinePaymentFeeRate, iokaAcquiringFeeRate
+	case kaspiSalesCountPaymentSystem:
+		feeRate, deliveryFeeRate = onlinePaymentFeeRate, kaspiSalesScoutAcquiringFeeRate
+	case kaspiPaymentSystem, whatsappPaymentSystem, byCashierPaymentSystem, cashPaymentSystem:
+		feeRate = offlinePaymentFeeRate
+	default:
+		return nil
+	}
+
+	if paymentSystem == iokaPaymentSystem || paymentSystem == kaspiSalesCountPaymentSystem {
+		lines = append(lines, newLine(settlementModels.LineTypeOrder, fmt.Sprintf("Заказ %s, оплата %s", order.OrderCode, paymentSystem), order.EstimatedTotalPrice.Value+order.ClientDeliveryPrice))
+		if refundedAmount > 0 {
+			lines = append(lines, newLine(settlementModels.LineTypeRefund, fmt.Sprintf("Возврат по заказу %s", order.OrderCode), -refundedAmount))
+		}
+	}
+
+	if fee := math.Ceil(productsPrice*feeRate) + math.Ceil(order.ClientDeliveryPrice*deliveryFeeRate); fee > 0 {
+		lines = append(lines, newLine(settlementModels.LineTypeServiceFee, fmt.Sprintf("Комиссия %s по заказу %s", paymentSystem, order.OrderCode), -fee))
+	}
+
+	for _, delivery := range deliveries {
+		if delivery.DeliveryDispatcherPrice == 0 {
+			continue
+		}
+
+		calculated := math.Max(delivery.FullDeliveryPrice, delivery.DeliveryDispatcherPrice+delivery.KwaakaChargedDeliveryPrice)
+
+		line := newLine(settlementModels.LineTypeDelivery, fmt.Sprintf("Доставка %s по заказу %s", delivery.DeliveryDispatcher, order.OrderCode), -math.Ceil(calculated-delivery.KwaakaChargedDeliveryPrice))
+		line.DeliveryOrderID = delivery.DeliveryOrderID
+		lines = append(lines, line)
+
+		if delivery.KwaakaChargedDeliveryPrice > 0 {
+			line = newLine(settlementModels.LineTypeKwaakaCharge, fmt.Sprintf("Сервисный сбор Kwaaka за доставку по заказу %s", order.OrderCode), -math.Ceil(delivery.KwaakaChargedDeliveryPrice))
+			line.DeliveryOrderID = delivery.DeliveryOrderID
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// unreconciledLine - заказ без платежной системы не пропускается молча, а попадает в сверку строкой без суммы для ручной проверки
+func unreconciledLine(order models.Order, err error) settlementModels.Line {
+	return settlementModels.Line{
+		Type:           settlementModels.LineTypeUnreconciled,
+		OrderID:        order.OrderID,
+		RestaurantID:   order.RestaurantID,
+		RestaurantName: order.RestaurantName,
+		Date:           order.OrderTime.Value.Time,
+		Description:    fmt.Sprintf("Заказ %s не сверен, платежная система не определена: %s", order.OrderCode, err.Error()),
+	}
+}
+
+func isCancelledOrder(status string) bool {
+	switch status {
+	case string(models.STATUS_CANCELLED), string(models.STATUS_FAILED), string(models.STATUS_CANCELLED_BY_POS_SYSTEM), string(models.STATUS_CANCELLED_BY_DELIVERY_SERVICE), string(models.STATUS_SKIPPED):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package order_report
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kwaaka-team/orders-core/core/models"
+	settlementModels "github.com/kwaaka-team/orders-core/service/settlement/models"
+)
+
+func TestSettlementLines(t *testing.T) {
+	order := models.Order{
+		OrderID:             "order",
+		OrderCode:           "A-1",
+		EstimatedTotalPrice: models.Price{Value: 10000},
+		ClientDeliveryPrice: 1000,
+	}
+	deliveries := []models.DeliveryHistory{
+		{DeliveryOrderID: "cancelled", DeliveryDispatcher: "yandex"},
+		{DeliveryOrderID: "delivery", DeliveryDispatcher: "wolt", DeliveryDispatcherPrice: 1100, FullDeliveryPrice: 1200, KwaakaChargedDeliveryPrice: 200},
+	}
+
+	tests := []struct {
+		name          string
+		paymentSystem string
+		refunded      float64
+		expected      map[string]float64
+	}{
+		{
+			name:          "ioka, money collected by kwaaka",
+			paymentSystem: iokaPaymentSystem,
+			refunded:      2000,
+			expected: map[string]float64{
+				settlementModels.LineTypeOrder:        11000,
+				settlementModels.LineTypeRefund:       -2000,
+				settlementModels.LineTypeServiceFee:   -429,
+				settlementModels.LineTypeDelivery:     -1100,
+				settlementModels.LineTypeKwaakaCharge: -200,
+			},
+		},
+		{
+			name:          "cash, money collected by restaurant",
+			paymentSystem: cashPaymentSystem,
+			expected: map[string]float64{
+				settlementModels.LineTypeServiceFee:   -300,
+				settlementModels.LineTypeDelivery:     -1100,
+				settlementModels.LineTypeKwaakaCharge: -200,
+			},
+		},
+		{
+			name:          "unknown payment system",
+			paymentSystem: noInfo,
+			expected:      map[string]float64{},
+		},
+	}
+
+	for _, test := range tests {
+		lines := settlementLines(order, test.paymentSystem, test.refunded, deliveries)
+
+		got := make(map[string]float64, len(lines))
+		for _, line := range lines {
+			got[line.Type] += line.Amount
+		}
+
+		if len(got) != len(test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+			continue
+		}
+		for lineType, amount := range test.expected {
+			if got[lineType] != amount {
+				t.Errorf("%s: expected %s %v, got %v", test.name, lineType, amount, got[lineType])
+			}
+		}
+	}
+}
+
+func TestUnreconciledLine(t *testing.T) {
+	line := unreconciledLine(models.Order{OrderID: "order", OrderCode: "A-1", RestaurantID: "store"}, errors.New("cart not found"))
+
+	if line.Type != settlementModels.LineTypeUnreconciled || line.OrderID != "order" || line.RestaurantID != "store" || line.Amount != 0 {
+		t.Errorf("unexpected unreconciled line %+v", line)
+	}
+}
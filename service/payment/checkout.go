@@ -0,0 +1,77 @@
+package payment
+
+import (
+	"context"
+	"math"
+
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+	coreStoreModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/payment/models"
+	promotionModels "github.com/kwaaka-team/orders-core/service/promotion/models"
+)
+
+type cartGetter interface {
+	GetCartById(ctx context.Context, cartID string) (coreModels.Cart, error)
+}
+
+// promotionCalculator - скидки акций на корзину, реализуется сервисом акций
+type promotionCalculator interface {
+	Calculate(ctx context.Context, cart coreModels.Cart, deliveryService string) (promotionModels.Result, error)
+}
+
+// checkoutDeliveryPrice - цена доставки корзины для клиента: у 3pl ресторана по зоне доставки, адрес вне зон и корзина меньше минимальной отклоняются.
+// Без зон цена считается по настройкам доставки ресторана
+func checkoutDeliveryPrice(cart coreModels.Cart, store coreStoreModels.Store) (float64, error) {
+	if cart.IsPickedUpByCustomer {
+		return 0, nil
+	}
+	if !store.Kwaaka3PL.Is3pl {
+		return cart.Delivery.ClientDeliveryPrice, nil
+	}
+
+	quote, err := store.Kwaaka3PL.CheckoutQuote(coreStoreModels.Coordinates{
+		Latitude:  cart.DeliveryAddress.Latitude,
+		Longitude: cart.DeliveryAddress.Longitude,
+	}, cart.TotalSum, cart.Delivery.ClientDeliveryPrice)
+	if err != nil {
+		return 0, err
+	}
+
+	return quote.ClientDeliveryPrice, nil
+}
+
+// priceCheckout - сумма оплаты корзины считается на сервере (в тиынах), а не берется из запроса клиента.
+// Скидки акций вычитаются так же, как при создании заказа qr menu, и сохраняются в платеже: заказ из оплаты создается с теми же скидками
+func (s *ServiceImpl) priceCheckout(ctx context.Context, paymentOrder models.PaymentOrder, store coreStoreModels.Store) (models.PaymentOrder, error) {
+	if paymentOrder.CartID == "" {
+		return paymentOrder, nil
+	}
+
+	cart, err := s.cartService.GetCartById(ctx, paymentOrder.CartID)
+	if err != nil {
+		return models.PaymentOrder{}, err
+	}
+
+	deliveryPrice, err := checkoutDeliveryPrice(cart, store)
+	if err != nil {
+		return models.PaymentOrder{}, err
+	}
+
+	promotions, err := s.promotionService.Calculate(ctx, cart, coreModels.QRMENU.String())
+	if err != nil {
+		return models.PaymentOrder{}, err
+	}
+
+	paymentOrder.Amount = checkoutAmount(cart, promotions, deliveryPrice)
+	if len(promotions.Promotions) != 0 {
+		paymentOrder.Promotions = &promotions
+	}
+
+	return paymentOrder, nil
+}
+
+// checkoutAmount - сумма оплаты в тиынах: корзина за вычетом скидок акций плюс доставка
+func checkoutAmount(cart coreModels.Cart, promotions promotionModels.Result, deliveryPrice float64) int {
+	total := math.Max(cart.TotalSum-promotions.Discount, 0)
+	return int(math.Round((total + deliveryPrice) * 100))
+}
@@ -0,0 +1,83 @@
+package payment
+
+import (
+	"testing"
+
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+	coreStoreModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	promotionModels "github.com/kwaaka-team/orders-core/service/promotion/models"
+	"github.com/pkg/errors"
+)
+
+func TestCheckoutDeliveryPrice(t *testing.T) {
+	store3pl := coreStoreModels.Store{
+		Kwaaka3PL: coreStoreModels.Kwaaka3PL{
+			Is3pl: true,
+			Polygons: []coreStoreModels.Polygon{
+				{ID: "city", CPO: 1500, MinBasketForOrder: 3000, Coordinates: []coreStoreModels.Coordinates{
+					{Latitude: 43.0, Longitude: 76.0},
+					{Latitude: 43.0, Longitude: 76.5},
+					{Latitude: 43.5, Longitude: 76.5},
+					{Latitude: 43.5, Longitude: 76.0},
+				}},
+			},
+		},
+	}
+
+	newCart := func(lat, lon, total float64) coreModels.Cart {
+		return coreModels.Cart{
+			TotalSum:        total,
+			DeliveryAddress: coreModels.DeliveryAddress{Latitude: lat, Longitude: lon},
+			Delivery:        coreModels.Delivery{ClientDeliveryPrice: 100},
+		}
+	}
+	pickup := newCart(51.1, 71.4, 1000)
+	pickup.IsPickedUpByCustomer = true
+
+	tests := []struct {
+		name        string
+		cart        coreModels.Cart
+		store       coreStoreModels.Store
+		expected    float64
+		expectedErr error
+	}{
+		{"zone fee instead of cart price", newCart(43.1, 76.1, 5000), store3pl, 1500, nil},
+		{"out of zones", newCart(51.1, 71.4, 5000), store3pl, 0, coreStoreModels.ErrOutOfDeliveryZone},
+		{"below min basket", newCart(43.1, 76.1, 1000), store3pl, 0, coreStoreModels.ErrMinBasketNotReached},
+		{"pickup", pickup, store3pl, 0, nil},
+		{"store without 3pl keeps cart price", newCart(51.1, 71.4, 1000), coreStoreModels.Store{}, 100, nil},
+		{"store without zones charges store cpo", newCart(51.1, 71.4, 1000), coreStoreModels.Store{Kwaaka3PL: coreStoreModels.Kwaaka3PL{Is3pl: true, CPO: 700}}, 700, nil},
+		{"dynamic store without zones keeps cart price", newCart(51.1, 71.4, 1000), coreStoreModels.Store{Kwaaka3PL: coreStoreModels.Kwaaka3PL{Is3pl: true, IsDynamic: true, CPO: 700}}, 100, nil},
+	}
+
+	for _, test := range tests {
+		price, err := checkoutDeliveryPrice(test.cart, test.store)
+		if !errors.Is(err, test.expectedErr) {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.expectedErr, err)
+		}
+		if price != test.expected {
+			t.Errorf("%s: expected price %v, got %v", test.name, test.expected, price)
+		}
+	}
+}
+
+func TestCheckoutAmount(t *testing.T) {
+	cart := coreModels.Cart{TotalSum: 5000}
+
+	tests := []struct {
+		name          string
+		promotions    promotionModels.Result
+		deliveryPrice float64
+		expected      int
+	}{
+		{"without promotions", promotionModels.Result{}, 1500, 650000},
+		{"promotion discount is subtracted", promotionModels.Result{Discount: 1000.5}, 1500, 549950},
+		{"discount above cart total keeps delivery", promotionModels.Result{Discount: 6000}, 1500, 150000},
+	}
+
+	for _, test := range tests {
+		if amount := checkoutAmount(cart, test.promotions, test.deliveryPrice); amount != test.expected {
+			t.Errorf("%s: expected amount %d, got %d", test.name, test.expected, amount)
+		}
+	}
+}
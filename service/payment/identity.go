@@ -0,0 +1,27 @@
+package payment
+
+import (
+	"context"
+
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+	"github.com/kwaaka-team/orders-core/service/payment/models"
+)
+
+// identityLinker - привязка идентификатора клиента к профилю по телефону, реализуется сервисом клиентов
+type identityLinker interface {
+	LinkIdentity(ctx context.Context, phone string, identity coreModels.CustomerIdentity) error
+}
+
+// linkCustomerIdentity привязывает клиента платежной системы к профилю клиента, ошибка привязки не прерывает оплату
+func (s *ServiceImpl) linkCustomerIdentity(ctx context.Context, customer models.PaymentSystemCustomer) {
+	if customer.Phone == "" || customer.PaymentSystemCustomerID == "" {
+		return
+	}
+
+	if err := s.identityLinker.LinkIdentity(ctx, customer.Phone, coreModels.CustomerIdentity{
+		Source:     coreModels.CustomerIdentityPaymentSystem,
+		ExternalID: customer.PaymentSystemCustomerID,
+	}); err != nil {
+		s.logger.Errorf("link payment system customer %s to customer profile error: %v", customer.PaymentSystemCustomerID, err)
+	}
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	promotionModels "github.com/kwaaka-team/orders-core/service/promotion/models"
+)
 
 type PaymentOrder struct {
 	ExternalID                string          `bson:"_id,omitempty" json:"external_id,omitempty"`
@@ -51,6 +55,8 @@ type PaymentOrder struct {
 	WhatsappPaymentChatId     string          `bson:"whatsapp_payment_chat_id" json:"whatsapp_payment_chat_id,omitempty"`
 	CustomerName              string          `bson:"customer_name,omitempty" json:"customer_name,omitempty"`
 	MulticardRefundUuid       string          `bson:"multicard_refund_uuid,omitempty" json:"multicard_refund_uuid,omitempty"`
+	// Promotions - скидки акций, по которым посчитана сумма оплаты, заказ создается с ними же
+	Promotions *promotionModels.Result `bson:"promotions,omitempty" json:"promotions,omitempty"`
 }
 
 type StatusHistory struct {
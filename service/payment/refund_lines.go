@@ -0,0 +1,190 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+	coreStoreModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/payment/models"
+	refundModels "github.com/kwaaka-team/orders-core/service/refund/models"
+	"github.com/pkg/errors"
+	"math"
+	"time"
+)
+
+var (
+	errInvalidRefundLine = errors.New("invalid refund line")
+	errRefundInProgress  = errors.New("refund of order is already in progress")
+)
+
+// refundLockLease - время блокировки возвратов заказа, если возврат прервался без снятия блокировки
+const refundLockLease = 5 * time.Minute
+
+// RefundOrderLines refunds chosen order positions, amount is calculated by positions with promos and service fee
+func (s *ServiceImpl) RefundOrderLines(ctx context.Context, orderID, reason string, lines []refundModels.RefundLineRequest) (models.PaymentOrder, models.RefundResponse, refundModels.Refund, error) {
+	paymentOrder, err := s.paymentsRepo.GetPaymentOrderByOrderID(ctx, orderID)
+	if err != nil {
+		return models.PaymentOrder{}, models.RefundResponse{}, refundModels.Refund{}, fmt.Errorf("payment/service - fn RefundOrderLines - fn GetPaymentOrderByOrderID: order id %v: %w", orderID, err)
+	}
+
+	order, err := s.orderRepo.FindOrderByOrderID(ctx, orderID)
+	if err != nil {
+		return models.PaymentOrder{}, models.RefundResponse{}, refundModels.Refund{}, fmt.Errorf("payment/service - fn RefundOrderLines - fn FindOrderByOrderID: order id %v: %w", orderID, err)
+	}
+
+	if order.DeliveryService != coreModels.QRMENU.String() && order.DeliveryService != coreModels.KWAAKA_ADMIN.String() {
+		return models.PaymentOrder{}, models.RefundResponse{}, refundModels.Refund{}, errors.Errorf("refund by lines is not available for %s orders", order.DeliveryService)
+	}
+
+	// возвраты заказа выполняются по одному, иначе параллельные запросы пройдут проверку остатка по одним и тем же возвратам
+	locked, err := s.refundRepo.Lock(ctx, orderID, time.Now().UTC(), refundLockLease)
+	if err != nil {
+		return models.PaymentOrder{}, models.RefundResponse{}, refundModels.Refund{}, fmt.Errorf("payment/service - fn RefundOrderLines - fn Lock: %w", err)
+	}
+	if !locked {
+		return models.PaymentOrder{}, models.RefundResponse{}, refundModels.Refund{}, errors.Wrapf(errRefundInProgress, "order id: %s", orderID)
+	}
+	defer func() {
+		if err := s.refundRepo.Unlock(ctx, orderID); err != nil {
+			s.logger.Errorf("unlock refund of order %s error: %s", orderID, err)
+		}
+	}()
+
+	refunds, err := s.refundRepo.GetRefundsByOrderID(ctx, orderID)
+	if err != nil {
+		return models.PaymentOrder{}, models.RefundResponse{}, refundModels.Refund{}, fmt.Errorf("payment/service - fn RefundOrderLines - fn GetRefundsByOrderID: %w", err)
+	}
+
+	refundLines, err := calculateRefundLines(order, lines, refundedQuantities(refunds))
+	if err != nil {
+		return models.PaymentOrder{}, models.RefundResponse{}, refundModels.Refund{}, err
+	}
+
+	refund := refundModels.Refund{
+		Reason:        reason,
+		OrderID:       orderID,
+		PaymentSystem: paymentOrder.PaymentSystem,
+		Lines:         refundLines,
+	}
+	refund.Amount = refund.LinesAmount()
+
+	switch {
+	case refund.Amount < 100 && paymentOrder.PaymentSystem == models.IOKA:
+		return models.PaymentOrder{}, models.RefundResponse{}, refundModels.Refund{}, fmt.Errorf("payment/service - fn RefundOrderLines: refund amount is less than 100: amount: %d", refund.Amount)
+	case refund.Amount < 1:
+		return models.PaymentOrder{}, models.RefundResponse{}, refundModels.Refund{}, fmt.Errorf("payment/service - fn RefundOrderLines: refund amount is less than 1: amount: %d", refund.Amount)
+	case refund.Amount+refundedAmount(refunds) > paymentOrder.Amount/100:
+		return models.PaymentOrder{}, models.RefundResponse{}, refundModels.Refund{}, fmt.Errorf("payment/service - fn RefundOrderLines: refund amount exceeds the purchase amount: %d", paymentOrder.Amount)
+	}
+
+	paymentService, err := s.paymentSystemFactory.GetPaymentSystem(paymentOrder, coreStoreModels.Store{})
+	if err != nil {
+		return models.PaymentOrder{}, models.RefundResponse{}, refundModels.Refund{}, fmt.Errorf("payment/service - fn RefundOrderLines - fn GetPaymentSystem: payment system: %s, error: %w", paymentOrder.PaymentSystem, err)
+	}
+
+	paymentOrder.RefundReason = reason
+
+	paymentOrder, refundResponse, err := paymentService.RefundPayment(ctx, paymentOrder, refund.Amount)
+	if err != nil {
+		s.logger.Errorf("refund order lines to customer error: %s", err)
+		return models.PaymentOrder{}, models.RefundResponse{}, refundModels.Refund{}, fmt.Errorf("payment/service - fn RefundOrderLines - fn RefundPayment: order id: %s, amount: %d, api error: %w", orderID, refund.Amount, err)
+	}
+
+	if err = s.paymentsRepo.UpdatePaymentOrder(ctx, paymentOrder); err != nil {
+		return models.PaymentOrder{}, models.RefundResponse{}, refundModels.Refund{}, fmt.Errorf("payment/service - fn RefundOrderLines - fn UpdatePaymentOrder: %w", err)
+	}
+
+	refund.PaymentID = refundResponse.PaymentID
+
+	if err = s.refundRepo.InsertRefundInfo(ctx, refund); err != nil {
+		return models.PaymentOrder{}, models.RefundResponse{}, refundModels.Refund{}, fmt.Errorf("payment/service - fn RefundOrderLines - fn InsertRefundInfo: %w", err)
+	}
+
+	return paymentOrder, refundResponse, refund, nil
+}
+
+func refundedQuantities(refunds []refundModels.Refund) map[int]int {
+	result := make(map[int]int)
+	for _, refund := range refunds {
+		for _, line := range refund.Lines {
+			result[line.LineIndex] += line.Quantity
+		}
+	}
+	return result
+}
+
+func refundedAmount(refunds []refundModels.Refund) int {
+	var amount int
+	for _, refund := range refunds {
+		amount += refund.Amount
+	}
+	return amount
+}
+
+// calculateRefundLines distributes paid sum of products (with order discounts and service fee) between positions proportionally to their price
+func calculateRefundLines(order coreModels.Order, requests []refundModels.RefundLineRequest, refunded map[int]int) ([]refundModels.RefundLine, error) {
+	if len(requests) == 0 {
+		return nil, errors.Wrap(errInvalidRefundLine, "no lines to refund")
+	}
+
+	var linesTotal float64
+	for _, product := range order.Products {
+		linesTotal += lineUnitPrice(product) * float64(product.Quantity)
+	}
+	if linesTotal <= 0 {
+		return nil, errors.Wrap(errInvalidRefundLine, "order products total is zero")
+	}
+
+	paid := order.EstimatedTotalPrice.Value + order.ServiceFeeSum
+	ratio := paid / linesTotal
+	itemsRatio := order.EstimatedTotalPrice.Value / linesTotal
+
+	requested := make(map[int]int, len(requests))
+	result := make([]refundModels.RefundLine, 0, len(requests))
+
+	for _, request := range requests {
+		if request.LineIndex < 0 || request.LineIndex >= len(order.Products) {
+			return nil, errors.Wrapf(errInvalidRefundLine, "line %d not found in order %s", request.LineIndex, order.OrderID)
+		}
+		if request.Quantity <= 0 {
+			return nil, errors.Wrapf(errInvalidRefundLine, "line %d quantity must be positive", request.LineIndex)
+		}
+
+		product := order.Products[request.LineIndex]
+
+		requested[request.LineIndex] += request.Quantity
+		if refunded[request.LineIndex]+requested[request.LineIndex] > product.Quantity {
+			return nil, errors.Wrapf(errInvalidRefundLine, "line %d: refund quantity exceeds ordered %d, already refunded %d", request.LineIndex, product.Quantity, refunded[request.LineIndex])
+		}
+
+		linePrice := lineUnitPrice(product) * float64(request.Quantity)
+
+		result = append(result, refundModels.RefundLine{
+			LineIndex:   request.LineIndex,
+			ProductID:   product.ID,
+			Name:        product.Name,
+			Quantity:    request.Quantity,
+			Amount:      int(math.Round(linePrice * ratio)),
+			ItemsAmount: int(math.Round(linePrice * itemsRatio)),
+		})
+	}
+
+	return result, nil
+}
+
+// lineUnitPrice - цена одной единицы позиции с модификаторами и скидками на продукт
+func lineUnitPrice(product coreModels.OrderProduct) float64 {
+	price := product.Price.Value
+	for _, attribute := range product.Attributes {
+		price += attribute.Price.Value * float64(attribute.Quantity)
+	}
+
+	for _, promo := range product.Promos {
+		if promo.Percent > 0 {
+			price -= price * float64(promo.Percent) / 100
+		}
+		price -= float64(promo.Discount)
+	}
+
+	return math.Max(price, 0)
+}
@@ -0,0 +1,145 @@
+package payment
+
+import (
+	"context"
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+	"github.com/kwaaka-team/orders-core/service/order"
+	"github.com/kwaaka-team/orders-core/service/payment/models"
+	"github.com/kwaaka-team/orders-core/service/payment/repository"
+	"github.com/kwaaka-team/orders-core/service/refund"
+	refundModels "github.com/kwaaka-team/orders-core/service/refund/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type paymentsRepositoryStub struct {
+	repository.PaymentsRepository
+}
+
+func (r paymentsRepositoryStub) GetPaymentOrderByOrderID(ctx context.Context, cartID string) (models.PaymentOrder, error) {
+	return models.PaymentOrder{PaymentSystem: models.IOKA, Amount: 247500}, nil
+}
+
+type orderRepositoryStub struct {
+	order.Repository
+	order coreModels.Order
+}
+
+func (r orderRepositoryStub) FindOrderByOrderID(ctx context.Context, orderID string) (coreModels.Order, error) {
+	return r.order, nil
+}
+
+type refundRepositoryStub struct {
+	refund.Repository
+	locked   bool
+	fetched  bool
+	unlocked bool
+	refunds  []refundModels.Refund
+}
+
+func (r *refundRepositoryStub) Lock(ctx context.Context, orderID string, now time.Time, lease time.Duration) (bool, error) {
+	return !r.locked, nil
+}
+
+func (r *refundRepositoryStub) Unlock(ctx context.Context, orderID string) error {
+	r.unlocked = true
+	return nil
+}
+
+func (r *refundRepositoryStub) GetRefundsByOrderID(ctx context.Context, orderID string) ([]refundModels.Refund, error) {
+	r.fetched = true
+	return r.refunds, nil
+}
+
+func TestCalculateRefundLines(t *testing.T) {
+	order := coreModels.Order{
+		OrderID: "order-1",
+		Products: []coreModels.OrderProduct{
+			{ID: "burger", Name: "Burger", Quantity: 2, Price: coreModels.Price{Value: 1000}},
+			{ID: "cola", Name: "Cola", Quantity: 1, Price: coreModels.Price{Value: 500}, Promos: []coreModels.Promo{{Percent: 50}}},
+		},
+		EstimatedTotalPrice: coreModels.Price{Value: 2250},
+		ServiceFeeSum:       225,
+	}
+
+	lines, err := calculateRefundLines(order, []refundModels.RefundLineRequest{
+		{LineIndex: 0, Quantity: 1},
+		{LineIndex: 1, Quantity: 1},
+	}, map[int]int{})
+	require.NoError(t, err)
+
+	// оплачено 2475 за позиции на 2250, коэффициент 1.1, в стоимость позиций сервисный сбор не входит
+	assert.Equal(t, []refundModels.RefundLine{
+		{LineIndex: 0, ProductID: "burger", Name: "Burger", Quantity: 1, Amount: 1100, ItemsAmount: 1000},
+		{LineIndex: 1, ProductID: "cola", Name: "Cola", Quantity: 1, Amount: 275, ItemsAmount: 250},
+	}, lines)
+
+	_, err = calculateRefundLines(order, []refundModels.RefundLineRequest{{LineIndex: 0, Quantity: 1}}, map[int]int{0: 2})
+	assert.ErrorIs(t, err, errInvalidRefundLine)
+
+	_, err = calculateRefundLines(order, []refundModels.RefundLineRequest{{LineIndex: 5, Quantity: 1}}, map[int]int{})
+	assert.ErrorIs(t, err, errInvalidRefundLine)
+}
+
+func TestRefundOrderLines_InProgress(t *testing.T) {
+	refunds := &refundRepositoryStub{locked: true}
+	s := &ServiceImpl{
+		paymentsRepo: paymentsRepositoryStub{},
+		orderRepo:    orderRepositoryStub{order: coreModels.Order{OrderID: "order-1", DeliveryService: coreModels.QRMENU.String()}},
+		refundRepo:   refunds,
+	}
+
+	_, _, _, err := s.RefundOrderLines(context.Background(), "order-1", "", []refundModels.RefundLineRequest{{LineIndex: 0, Quantity: 1}})
+
+	assert.ErrorIs(t, err, errRefundInProgress)
+	assert.False(t, refunds.fetched)
+	assert.False(t, refunds.unlocked)
+}
+
+func TestRefundToCustomer_Limits(t *testing.T) {
+	previous := []refundModels.Refund{
+		{Amount: 1000},
+		{Amount: 1000, Lines: []refundModels.RefundLine{{LineIndex: 0, Quantity: 1, Amount: 1000}}},
+	}
+
+	tests := []struct {
+		name         string
+		refunds      *refundRepositoryStub
+		amount       int
+		wantErr      error
+		wantUnlocked bool
+	}{
+		{
+			name:    "refund in progress by lines or whole order",
+			refunds: &refundRepositoryStub{locked: true},
+			amount:  500,
+			wantErr: errRefundInProgress,
+		},
+		{
+			name:         "amount with previous whole order and line refunds exceeds paid",
+			refunds:      &refundRepositoryStub{refunds: previous},
+			amount:       500,
+			wantUnlocked: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ServiceImpl{
+				paymentsRepo: paymentsRepositoryStub{},
+				refundRepo:   tt.refunds,
+			}
+
+			_, _, internalErr, err := s.RefundToCustomer(context.Background(), "order-1", "", tt.amount)
+
+			assert.NoError(t, internalErr)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+			assert.Equal(t, tt.wantUnlocked, tt.refunds.unlocked)
+		})
+	}
+}
@@ -18,6 +18,7 @@ type CustomersRepository interface {
 	UpdateCustomer(ctx context.Context, customer models.PaymentSystemCustomer) error
 	GetCustomerByEmail(ctx context.Context, email string) (models.PaymentSystemCustomer, error)
 	GetCustomerByPaymentSystemCustomerID(ctx context.Context, paymentSystemCustomerID string) (models.PaymentSystemCustomer, error)
+	GetCustomersByPhones(ctx context.Context, phones []string) ([]models.PaymentSystemCustomer, error)
 }
 
 type CustomersMongoRepository struct {
@@ -58,6 +59,24 @@ func (r *CustomersMongoRepository) GetCustomerByEmail(ctx context.Context, email
 	return customer, nil
 }
 
+func (r *CustomersMongoRepository) GetCustomersByPhones(ctx context.Context, phones []string) ([]models.PaymentSystemCustomer, error) {
+	filter := bson.D{
+		{Key: "phone", Value: bson.D{{Key: "$in", Value: phones}}},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, customeErrors.ErrorSwitch(err)
+	}
+
+	customers := make([]models.PaymentSystemCustomer, 0)
+	if err := cursor.All(ctx, &customers); err != nil {
+		return nil, err
+	}
+
+	return customers, nil
+}
+
 func (r *CustomersMongoRepository) InsertCustomer(ctx context.Context, customer models.PaymentSystemCustomer) (models.PaymentSystemCustomer, error) {
 	customer.CreatedAt = time.Now().UTC()
 	res, err := r.collection.InsertOne(ctx, customer)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	coreStoreModels "github.com/kwaaka-team/orders-core/core/storecore/models"
 	notifyQueue "github.com/kwaaka-team/orders-core/pkg/que"
+	"github.com/kwaaka-team/orders-core/service/order"
 	"github.com/kwaaka-team/orders-core/service/payment/models"
 	paymeDto "github.com/kwaaka-team/orders-core/service/payment/payme/dto"
 	"github.com/kwaaka-team/orders-core/service/payment/repository"
@@ -43,7 +44,8 @@ type Service interface {
 	GetUnpaidPaymentsByPaymentSystem(ctx context.Context, minutes int, paymentSystem string) ([]models.PaymentOrder, error)
 	GetKaspiSaleScoutPaymentStatus(ctx context.Context, paymentID string) (string, error)
 	RefundToCustomer(ctx context.Context, orderID, reason string, amount int) (models.PaymentOrder, models.RefundResponse, error, error)
-	GetRefund(ctx context.Context, orderID string) (refundModels.Refund, error)
+	RefundOrderLines(ctx context.Context, orderID, reason string, lines []refundModels.RefundLineRequest) (models.PaymentOrder, models.RefundResponse, refundModels.Refund, error)
+	GetRefunds(ctx context.Context, orderID string) ([]refundModels.Refund, error)
 	CreatePaymentLinkForCustomerToPay(ctx context.Context, orderId string) (models.PaymentOrder, error)
 }
 
@@ -58,6 +60,10 @@ type ServiceImpl struct {
 	storeService         storeServicePkg.Service
 	storeGroupService    storeGroupServicePkg.Service
 	refundRepo           refund.Repository
+	orderRepo            order.Repository
+	cartService          cartGetter
+	promotionService     promotionCalculator
+	identityLinker       identityLinker
 }
 
 func NewService(paymentSystemFactory *PaymentSystemFactory,
@@ -68,10 +74,26 @@ func NewService(paymentSystemFactory *PaymentSystemFactory,
 	storeService storeServicePkg.Service,
 	storeGroupService storeGroupServicePkg.Service,
 	refundRepo refund.Repository,
+	orderRepo order.Repository,
+	cartService cartGetter,
+	promotionService promotionCalculator,
+	identityLinker identityLinker,
 ) (Service, error) {
 	if paymentSystemFactory == nil {
 		return nil, errors.New("payment system factory is nil")
 	}
+	if orderRepo == nil {
+		return nil, errors.New("order repository is nil")
+	}
+	if cartService == nil {
+		return nil, errors.New("cart service is nil")
+	}
+	if promotionService == nil {
+		return nil, errors.New("promotion service is nil")
+	}
+	if identityLinker == nil {
+		return nil, errors.New("identity linker is nil")
+	}
 
 	return &ServiceImpl{
 		paymentSystemFactory: paymentSystemFactory,
@@ -84,6 +106,10 @@ func NewService(paymentSystemFactory *PaymentSystemFactory,
 		storeService:         storeService,
 		storeGroupService:    storeGroupService,
 		refundRepo:           refundRepo,
+		orderRepo:            orderRepo,
+		cartService:          cartService,
+		promotionService:     promotionService,
+		identityLinker:       identityLinker,
 	}, nil
 }
 
@@ -113,10 +139,11 @@ func (s *ServiceImpl) CreateCustomerInDB(ctx context.Context, customer models.Pa
 		return err
 	}
 
-	_, err = s.customersRepo.InsertCustomer(ctx, customer)
+	inserted, err := s.customersRepo.InsertCustomer(ctx, customer)
 	if err != nil {
 		return err
 	}
+	s.linkCustomerIdentity(ctx, inserted)
 
 	return nil
 }
@@ -215,6 +242,7 @@ func (s *ServiceImpl) CreateCustomerForSavedCardsPayments(ctx context.Context, p
 	if err != nil {
 		return models.PaymentSystemCustomer{}, err
 	}
+	s.linkCustomerIdentity(ctx, customer)
 
 	return customer, nil
 }
@@ -252,6 +280,7 @@ func (s *ServiceImpl) CreateCustomerInPaymentSystem(ctx context.Context, payment
 	if err := s.customersRepo.UpdateCustomer(ctx, customer); err != nil {
 		return "", err
 	}
+	s.linkCustomerIdentity(ctx, customer)
 
 	return customer.CheckoutURL, nil
 }
@@ -321,6 +350,11 @@ func (s *ServiceImpl) CreatePaymentOrder(ctx context.Context, paymentOrder model
 		return models.PaymentOrder{}, err
 	}
 
+	paymentOrder, err = s.priceCheckout(ctx, paymentOrder, store)
+	if err != nil {
+		return models.PaymentOrder{}, err
+	}
+
 	paymentOrder.RestaurantName = store.Name
 	paymentOrder.RestaurantGroupName = storeGroup.Name
 	paymentOrder.WhatsappPaymentChatId = store.WhatsappPaymentChatId
@@ -797,13 +831,32 @@ func (s *ServiceImpl) RefundToCustomer(ctx context.Context, orderID, reason stri
 		return models.PaymentOrder{}, models.RefundResponse{}, fmt.Errorf("payment/service - fn RefundCustomerToCustomer - fn GetPaymentOrderByOrderID: get payment order by order id error: order id %v: %w", orderID, err), nil
 	}
 
+	// возврат всей суммы и возвраты по позициям берут одну блокировку и проверяют остаток по всем прошлым возвратам
+	locked, err := s.refundRepo.Lock(ctx, orderID, time.Now().UTC(), refundLockLease)
+	if err != nil {
+		return models.PaymentOrder{}, models.RefundResponse{}, fmt.Errorf("payment/service - fn RefundCustomerToCustomer - fn Lock: %w", err), nil
+	}
+	if !locked {
+		return models.PaymentOrder{}, models.RefundResponse{}, nil, errors.Wrapf(errRefundInProgress, "order id: %s", orderID)
+	}
+	defer func() {
+		if err := s.refundRepo.Unlock(ctx, orderID); err != nil {
+			s.logger.Errorf("unlock refund of order %s error: %s", orderID, err)
+		}
+	}()
+
+	refunds, err := s.refundRepo.GetRefundsByOrderID(ctx, orderID)
+	if err != nil {
+		return models.PaymentOrder{}, models.RefundResponse{}, fmt.Errorf("payment/service - fn RefundCustomerToCustomer - fn GetRefundsByOrderID: %w", err), nil
+	}
+
 	switch {
 	case amount < 100 && paymentOrder.PaymentSystem == models.IOKA:
 		return models.PaymentOrder{}, models.RefundResponse{}, nil, fmt.Errorf("payment/service - fn RefundCustomerToCustomer: refund amount is less than 100: amount: %d", paymentOrder.Amount)
 	case amount < 1 && paymentOrder.PaymentSystem == models.KaspiSaleScout:
 		return models.PaymentOrder{}, models.RefundResponse{}, nil, fmt.Errorf("payment/service - fn RefundCustomerToCustomer: refund amount is less than 1: amount: %d", paymentOrder.Amount)
-	case amount > paymentOrder.Amount/100:
-		return models.PaymentOrder{}, models.RefundResponse{}, nil, fmt.Errorf("payment/service - fn RefundCustomerToCustomer: refund amount exceeds the purchase amount: %d", paymentOrder.Amount)
+	case amount+refundedAmount(refunds) > paymentOrder.Amount/100:
+		return models.PaymentOrder{}, models.RefundResponse{}, nil, fmt.Errorf("payment/service - fn RefundCustomerToCustomer: refund amount with previous refunds %d exceeds the purchase amount: %d", refundedAmount(refunds), paymentOrder.Amount)
 	}
 
 	paymentService, err := s.paymentSystemFactory.GetPaymentSystem(paymentOrder, coreStoreModels.Store{})
@@ -825,8 +878,8 @@ func (s *ServiceImpl) RefundToCustomer(ctx context.Context, orderID, reason stri
 		return models.PaymentOrder{}, models.RefundResponse{}, fmt.Errorf("payment/service - fn RefundCustomerToCustomer - fn UpdatePaymentOrder: update payment order error: %w", err), nil
 	}
 
+	// у возврата свой id: возвратов всей суммы по одному платежу может быть несколько
 	if err = s.refundRepo.InsertRefundInfo(ctx, refundModels.Refund{
-		ID:            paymentOrder.ExternalID,
 		Amount:        amount,
 		Reason:        paymentOrder.RefundReason,
 		OrderID:       paymentOrder.OrderID,
@@ -839,10 +892,11 @@ func (s *ServiceImpl) RefundToCustomer(ctx context.Context, orderID, reason stri
 	return paymentOrder, refundResponse, nil, nil
 }
 
-func (s *ServiceImpl) GetRefund(ctx context.Context, orderID string) (refundModels.Refund, error) {
-	res, err := s.refundRepo.GetRefund(ctx, orderID)
+// GetRefunds - все возвраты заказа: возвраты всей суммы и по позициям
+func (s *ServiceImpl) GetRefunds(ctx context.Context, orderID string) ([]refundModels.Refund, error) {
+	res, err := s.refundRepo.GetRefundsByOrderID(ctx, orderID)
 	if err != nil {
-		return refundModels.Refund{}, err
+		return nil, err
 	}
 	return res, nil
 }
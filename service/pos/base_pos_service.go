@@ -42,6 +42,10 @@ func (bps *BasePosService) AwakeTerminal(ctx context.Context, store coreStoreMod
 	return nil
 }
 
+func (bps *BasePosService) UpdateOrderItems(ctx context.Context, order models.Order, store coreStoreModels.Store, diff models.OrderItemsDiff) (models.Order, error) {
+	return order, ErrUnsupportedMethod
+}
+
 func setPosOrderId(order models.Order, id string) models.Order {
 	order.PosOrderID = id
 	return order
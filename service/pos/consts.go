@@ -40,6 +40,9 @@ const (
 
 var ErrUnsupportedMethod = errors.New("unsupported method")
 
+// ErrOrderItemsChangeNotApplicable - pos не умеет применить такое изменение состава заказа, заказ не меняется
+var ErrOrderItemsChangeNotApplicable = errors.New("order items change can not be applied in pos")
+
 func MatchingCodes(message string, errorSolutions []models.ErrorSolution) string {
 
 	for _, errorSolution := range errorSolutions {
@@ -10,6 +10,7 @@ import (
 	IIKOClient "github.com/kwaaka-team/orders-core/pkg/iiko/clients/http"
 	iikoModels "github.com/kwaaka-team/orders-core/pkg/iiko/models"
 	"github.com/pkg/errors"
+	"strings"
 )
 
 type iikoService struct {
@@ -282,3 +283,36 @@ func (iikoService *iikoService) CloseOrder(ctx context.Context, posOrderId strin
 
 	return nil
 }
+
+// UpdateOrderItems - в iiko позиции доставки можно только добавить, убрать позицию или уменьшить количество нельзя,
+// такое изменение отклоняется до отправки в iiko
+func (iikoSvc *iikoService) UpdateOrderItems(ctx context.Context, order models.Order, store coreStoreModels.Store, diff models.OrderItemsDiff) (models.Order, error) {
+	if order.PosOrderID == "" {
+		return order, errors.New("iiko pos order id is empty")
+	}
+
+	if decreased := diff.Decreased(); len(decreased) != 0 {
+		positions := make([]string, 0, len(decreased))
+		for _, product := range decreased {
+			positions = append(positions, fmt.Sprintf("%s x%d", product.Name, product.Quantity))
+		}
+		return order, errors.Wrapf(ErrOrderItemsChangeNotApplicable, "iiko can not remove positions: %s", strings.Join(positions, ", "))
+	}
+
+	items, combos, _ := iikoSvc.toItemsAndCombos(models.Order{Products: diff.Increased()})
+
+	resp, err := iikoSvc.iikoClient.AddOrderItem(ctx, iikoModels.OrderItem{
+		OrganizationId: iikoSvc.organizationID,
+		OrderId:        order.PosOrderID,
+		Items:          items,
+		Combos:         combos,
+	})
+	if err != nil {
+		return order, err
+	}
+	if resp.Error != "" {
+		return order, errors.Errorf("iiko add order items error: %s, %s", resp.Error, resp.ErrorDescription)
+	}
+
+	return order, nil
+}
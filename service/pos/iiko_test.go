@@ -0,0 +1,74 @@
+package pos
+
+import (
+	"context"
+	"github.com/kwaaka-team/orders-core/core/models"
+	coreStoreModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	iikoConf "github.com/kwaaka-team/orders-core/pkg/iiko/clients"
+	iikoModels "github.com/kwaaka-team/orders-core/pkg/iiko/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type iikoClientStub struct {
+	iikoConf.IIKO
+	added []iikoModels.OrderItem
+}
+
+func (c *iikoClientStub) AddOrderItem(ctx context.Context, req iikoModels.OrderItem) (iikoModels.OrderItemResponse, error) {
+	c.added = append(c.added, req)
+	return iikoModels.OrderItemResponse{}, nil
+}
+
+func TestIikoService_UpdateOrderItems(t *testing.T) {
+	oldProducts := []models.OrderProduct{
+		{ID: "burger", Name: "Бургер", Quantity: 2},
+		{ID: "cola", Name: "Кола", Quantity: 1},
+	}
+
+	tests := []struct {
+		name        string
+		newProducts []models.OrderProduct
+		wantErr     error
+		wantAdded   int
+	}{
+		{
+			name: "added and increased positions are sent to iiko",
+			newProducts: []models.OrderProduct{
+				{ID: "burger", Name: "Бургер", Quantity: 3},
+				{ID: "cola", Name: "Кола", Quantity: 1},
+				{ID: "fries", Name: "Фри", Quantity: 1},
+			},
+			wantAdded: 1,
+		},
+		{
+			name: "removed position is rejected",
+			newProducts: []models.OrderProduct{
+				{ID: "burger", Name: "Бургер", Quantity: 2},
+			},
+			wantErr: ErrOrderItemsChangeNotApplicable,
+		},
+		{
+			name: "decreased quantity is rejected even with added position",
+			newProducts: []models.OrderProduct{
+				{ID: "burger", Name: "Бургер", Quantity: 1},
+				{ID: "cola", Name: "Кола", Quantity: 1},
+				{ID: "fries", Name: "Фри", Quantity: 1},
+			},
+			wantErr: ErrOrderItemsChangeNotApplicable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &iikoClientStub{}
+			svc := &iikoService{BasePosService: &BasePosService{}, iikoClient: client}
+
+			_, err := svc.UpdateOrderItems(context.Background(), models.Order{PosOrderID: "pos_order"}, coreStoreModels.Store{}, models.NewOrderItemsDiff(oldProducts, tt.newProducts))
+
+			assert.True(t, errors.Is(err, tt.wantErr), err)
+			assert.Len(t, client.added, tt.wantAdded)
+		})
+	}
+}
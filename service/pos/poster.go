@@ -31,6 +31,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -92,7 +93,8 @@ func (s *PosterService) GetOrderStatus(ctx context.Context, order models.Order)
 
 func (s *PosterService) hasStatusInStatusesHistory(status string, history []models.OrderStatusUpdate) bool {
 	for _, s := range history {
-		if s.Name == status {
+		// отклоненный state machine статус к заказу не применен
+		if s.Name == status && !s.Rejected {
 			return true
 		}
 	}
@@ -220,19 +222,30 @@ func (p *PosterService) constructPosOrder(ctx context.Context, order models.Orde
 		posterOrder.DeliveryPrice = int(order.DeliveryFee.Value * 100)
 	}
 
+	posterProducts, err := p.toPosterProducts(order.Products)
+	if err != nil {
+		return nil, models.Order{}, err
+	}
+
+	posterOrder.Products = posterProducts
+
+	return posterOrder, order, nil
+}
+
+func (p *PosterService) toPosterProducts(products []models.OrderProduct) ([]posterModels.CreateOrderProductRequest, error) {
 	var posterProducts = make([]posterModels.CreateOrderProductRequest, 0)
 
-	for _, product := range order.Products {
+	for _, product := range products {
 		productID, err := strconv.Atoi(product.ID)
 		if err != nil {
-			return nil, models.Order{}, err
+			return nil, err
 		}
 		attributesPrice := 0.0
 		productAttributes := make([]posterModels.CreateOrderModificationRequest, 0, len(product.Attributes))
 		for _, attribute := range product.Attributes {
 			attributeId, err := strconv.Atoi(attribute.ID)
 			if err != nil {
-				return nil, models.Order{}, err
+				return nil, err
 			}
 			posterAttribute := posterModels.CreateOrderModificationRequest{
 				M: attributeId,
@@ -251,9 +264,7 @@ func (p *PosterService) constructPosOrder(ctx context.Context, order models.Orde
 		posterProducts = append(posterProducts, posterProduct)
 	}
 
-	posterOrder.Products = posterProducts
-
-	return posterOrder, order, nil
+	return posterProducts, nil
 }
 
 func (p *PosterService) getSumByPaymentType(paymentType int32, sum float64) int {
@@ -977,3 +988,29 @@ func (s *PosterService) SortStoplistItemsByIsIgnored(ctx context.Context, menu c
 func (s *PosterService) CloseOrder(ctx context.Context, posOrderId string) error {
 	return nil
 }
+
+// UpdateOrderItems sends new products of order, poster replaces products of incoming order entirely
+func (s *PosterService) UpdateOrderItems(ctx context.Context, order models.Order, store coreStoreModels.Store, diff models.OrderItemsDiff) (models.Order, error) {
+	incomingOrderID, err := strconv.Atoi(strings.TrimPrefix(order.PosOrderID, store.Poster.AccountNumberString))
+	if err != nil {
+		return order, errors.Wrapf(err, "invalid poster pos order id %s", order.PosOrderID)
+	}
+
+	products, err := s.toPosterProducts(order.Products)
+	if err != nil {
+		return order, err
+	}
+
+	req := posterModels.UpdateOrderRequest{
+		IncomingOrderID: incomingOrderID,
+		Products:        products,
+	}
+
+	utils.Beautify("Poster Update Order Request Body", req)
+
+	if _, err = s.posterCli.UpdateOrder(ctx, req); err != nil {
+		return order, err
+	}
+
+	return order, nil
+}
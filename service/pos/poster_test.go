@@ -0,0 +1,41 @@
+package pos
+
+import (
+	"context"
+	"github.com/kwaaka-team/orders-core/core/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPosterService_GetOrderStatus(t *testing.T) {
+	cookingComplete := time.Now().UTC().Add(-time.Minute)
+
+	tests := []struct {
+		name    string
+		history []models.OrderStatusUpdate
+		want    string
+	}{
+		{
+			name: "cooking complete is not applied yet",
+			want: "ready",
+		},
+		{
+			name:    "cooking complete already applied",
+			history: []models.OrderStatusUpdate{{Name: "COOKING_COMPLETE"}},
+			want:    "",
+		},
+		{
+			name:    "rejected cooking complete is not applied",
+			history: []models.OrderStatusUpdate{{Name: "COOKING_COMPLETE", Rejected: true}},
+			want:    "ready",
+		},
+	}
+
+	service := &PosterService{}
+	for _, test := range tests {
+		status, err := service.GetOrderStatus(context.Background(), models.Order{CookingCompleteTime: cookingComplete, StatusesHistory: test.history})
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.want, status, test.name)
+	}
+}
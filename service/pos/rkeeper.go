@@ -826,6 +826,25 @@ func (r *rkeeperService) CloseOrder(ctx context.Context, posOrderId string) erro
 	return nil
 }
 
+// UpdateOrderItems sends new products of order, rkeeper replaces order content by them
+func (rkeeperSvc *rkeeperService) UpdateOrderItems(ctx context.Context, order models.Order, store coreStoreModels.Store, diff models.OrderItemsDiff) (models.Order, error) {
+	rkeeperOrder := rkeeperSvc.fillProducts(order, rkeeperDto.Order{})
+
+	resp, err := rkeeperSvc.rkeeperCli.UpdateOrder(ctx, store.RKeeper.ObjectId, order.PosOrderID, rkeeperOrder.Products)
+	if err != nil {
+		return order, err
+	}
+
+	if resp.Error.WsError.Code != "" {
+		return order, errors.Errorf("rkeeper update order error: %s, %s", resp.Error.WsError.Code, resp.Error.WsError.Desc)
+	}
+	if resp.Error.AgentError.Code != "" {
+		return order, errors.Errorf("rkeeper update order error: %s, %s", resp.Error.AgentError.Code, resp.Error.AgentError.Desc)
+	}
+
+	return order, nil
+}
+
 func (rkeeperSvc *rkeeperService) CreateOrderTask(ctx context.Context, taskGUID string) (string, error) {
 
 	var (
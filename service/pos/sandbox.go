@@ -0,0 +1,244 @@
+package pos
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/kwaaka-team/orders-core/core/config"
+	errs "github.com/kwaaka-team/orders-core/core/errors"
+	coreMenuModels "github.com/kwaaka-team/orders-core/core/menu/models"
+	"github.com/kwaaka-team/orders-core/core/models"
+	coreStoreModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	menuCore "github.com/kwaaka-team/orders-core/pkg/menu"
+	notifyQueue "github.com/kwaaka-team/orders-core/pkg/que"
+	storeClient "github.com/kwaaka-team/orders-core/pkg/store"
+	"github.com/kwaaka-team/orders-core/service/error_solutions"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	SandboxFailureTimeout         = "timeout"
+	SandboxFailureProductNotFound = "product_not_found"
+	SandboxFailureTerminalOffline = "terminal_offline"
+
+	sandboxStatusNew       = "NEW"
+	sandboxStatusCancelled = "CANCELLED"
+)
+
+var errSandboxTerminalOffline = errors.New("sandbox terminal is offline")
+
+var defaultSandboxTimeline = []coreStoreModels.SandboxTimelineStep{
+	{Status: models.ACCEPTED.String(), AfterSeconds: 0},
+	{Status: models.COOKING_STARTED.String(), AfterSeconds: 60},
+	{Status: models.COOKING_COMPLETE.String(), AfterSeconds: 300},
+	{Status: models.READY_FOR_PICKUP.String(), AfterSeconds: 60},
+}
+
+// sandboxPosService - тестовая pos система для проверки флоу заказа без реальной кассы
+type sandboxPosService struct {
+	*BasePosService
+	cfg    coreStoreModels.SandboxConfig
+	orders SandboxOrderRepository
+}
+
+func newSandboxPosService(bps *BasePosService, cfg coreStoreModels.SandboxConfig, orders SandboxOrderRepository) (*sandboxPosService, error) {
+	if bps == nil {
+		return nil, errors.Wrap(constructorError, "sandbox pos constructor error")
+	}
+	if orders == nil {
+		return nil, errors.Wrap(constructorError, "sandbox order repository is nil")
+	}
+
+	if len(cfg.Timeline) == 0 {
+		cfg.Timeline = defaultSandboxTimeline
+	}
+
+	return &sandboxPosService{
+		BasePosService: bps,
+		cfg:            cfg,
+		orders:         orders,
+	}, nil
+}
+
+func (s *sandboxPosService) MapPosStatusToSystemStatus(posStatus, currentSystemStatus string) (models.PosStatus, error) {
+	switch posStatus {
+	case sandboxStatusNew:
+		return models.NEW, nil
+	case "ACCEPTED":
+		return models.ACCEPTED, nil
+	case "COOKING_STARTED":
+		return models.COOKING_STARTED, nil
+	case "COOKING_COMPLETE":
+		return models.COOKING_COMPLETE, nil
+	case "READY_FOR_PICKUP":
+		return models.READY_FOR_PICKUP, nil
+	case "OUT_FOR_DELIVERY":
+		return models.OUT_FOR_DELIVERY, nil
+	case "DELIVERED":
+		return models.DELIVERED, nil
+	case "CLOSED":
+		return models.CLOSED, nil
+	case sandboxStatusCancelled:
+		return models.CANCELLED_BY_POS_SYSTEM, nil
+	}
+
+	return 0, models.StatusIsNotExist
+}
+
+func (s *sandboxPosService) CreateOrder(ctx context.Context, order models.Order, globalConfig config.Configuration,
+	store coreStoreModels.Store, menu coreMenuModels.Menu, menuClient menuCore.Client, aggregatorMenu coreMenuModels.Menu,
+	storeCli storeClient.Client, errSolution error_solutions.Service, notifyQueue notifyQueue.SQSInterface) (models.Order, error) {
+
+	switch s.cfg.Failure {
+	case SandboxFailureTimeout:
+		order.FailReason.Code = CREATION_TIMEOUT_CODE
+		order.FailReason.Message = "sandbox order creation timeout"
+		return order, errors.Wrap(errs.ErrTimeout, "sandbox order creation")
+	case SandboxFailureTerminalOffline:
+		order.FailReason.Code = OTHER_FAIL_REASON_CODE
+		order.FailReason.Message = errSandboxTerminalOffline.Error()
+		return order, errSandboxTerminalOffline
+	}
+
+	stopList := make(map[string]struct{}, len(s.cfg.StopList))
+	for _, productID := range s.cfg.StopList {
+		stopList[productID] = struct{}{}
+	}
+
+	for _, product := range order.Products {
+		_, inStopList := stopList[product.ID]
+		if s.cfg.Failure == SandboxFailureProductNotFound || inStopList {
+			order.FailReason.Code = PRODUCT_MISSED_CODE
+			order.FailReason.Message = PRODUCT_MISSED + product.ID
+			return order, errors.Wrap(errs.ErrProductNotFound, fmt.Sprintf("PRODUCT NOT FOUND IN POS MENU, ID %s, NAME %s", product.ID, product.Name))
+		}
+	}
+
+	order = setPosOrderId(order, uuid.New().String())
+
+	if err := s.orders.Save(ctx, SandboxOrder{
+		PosOrderID: order.PosOrderID,
+		CreatedAt:  time.Now().UTC(),
+	}); err != nil {
+		return order, err
+	}
+
+	return order, nil
+}
+
+func (s *sandboxPosService) IsAliveStatus(ctx context.Context, store coreStoreModels.Store) (bool, error) {
+	return s.cfg.Failure != SandboxFailureTerminalOffline, nil
+}
+
+// GetOrderStatus returns status by timeline, counted from order creation time in pos, cancelled and closed orders keep their status
+func (s *sandboxPosService) GetOrderStatus(ctx context.Context, order models.Order) (string, error) {
+	createdAt := order.CreatedAt.Time
+
+	sandboxOrder, err := s.orders.Get(ctx, order.PosOrderID)
+	switch {
+	case err == nil:
+		if sandboxOrder.Status != "" {
+			return sandboxOrder.Status, nil
+		}
+		createdAt = sandboxOrder.CreatedAt
+	case !errors.Is(err, errs.ErrNotFound):
+		return "", err
+	}
+
+	return s.timelineStatus(time.Since(createdAt)), nil
+}
+
+func (s *sandboxPosService) timelineStatus(elapsed time.Duration) string {
+	status := sandboxStatusNew
+
+	var at time.Duration
+	for _, step := range s.cfg.Timeline {
+		at += time.Duration(step.AfterSeconds) * time.Second
+		if elapsed < at {
+			break
+		}
+		status = step.Status
+	}
+
+	return status
+}
+
+func (s *sandboxPosService) GetStopList(ctx context.Context) (coreMenuModels.StopListItems, error) {
+	if s.cfg.Failure == SandboxFailureTerminalOffline {
+		return nil, errSandboxTerminalOffline
+	}
+
+	items := make(coreMenuModels.StopListItems, 0, len(s.cfg.StopList))
+	for _, productID := range s.cfg.StopList {
+		items = append(items, coreMenuModels.StopListItem{
+			ProductID: productID,
+		})
+	}
+
+	return items, nil
+}
+
+// GetMenu returns products from sandbox config, if they are empty menu in db is returned as is
+func (s *sandboxPosService) GetMenu(ctx context.Context, store coreStoreModels.Store, systemMenuInDb coreMenuModels.Menu) (coreMenuModels.Menu, error) {
+	if s.cfg.Failure == SandboxFailureTerminalOffline {
+		return coreMenuModels.Menu{}, errSandboxTerminalOffline
+	}
+
+	if len(s.cfg.Products) == 0 {
+		return systemMenuInDb, nil
+	}
+
+	stopList := make(map[string]struct{}, len(s.cfg.StopList))
+	for _, productID := range s.cfg.StopList {
+		stopList[productID] = struct{}{}
+	}
+
+	products := make(coreMenuModels.Products, 0, len(s.cfg.Products))
+	for _, product := range s.cfg.Products {
+		_, inStopList := stopList[product.ID]
+		products = append(products, coreMenuModels.Product{
+			ExtID:            product.ID,
+			ProductID:        product.ID,
+			PosID:            product.ID,
+			Name:             []coreMenuModels.LanguageDescription{{Value: product.Name}},
+			Price:            []coreMenuModels.Price{{Value: product.Price, CurrencyCode: store.Settings.Currency}},
+			IsAvailable:      !inStopList,
+			IsIncludedInMenu: true,
+		})
+	}
+
+	return coreMenuModels.Menu{
+		Name:     models.Sandbox.String(),
+		IsActive: true,
+		Products: products,
+	}, nil
+}
+
+func (s *sandboxPosService) CancelOrder(ctx context.Context, order models.Order, store coreStoreModels.Store) error {
+	return s.orders.SetStatus(ctx, order.PosOrderID, sandboxStatusCancelled)
+}
+
+func (s *sandboxPosService) GetSeqNumber(ctx context.Context) (string, error) {
+	return "", nil
+}
+
+func (s *sandboxPosService) SortStoplistItemsByIsIgnored(ctx context.Context, menu coreMenuModels.Menu, items coreMenuModels.StopListItems) (coreMenuModels.StopListItems, error) {
+	return items, nil
+}
+
+func (s *sandboxPosService) CloseOrder(ctx context.Context, posOrderId string) error {
+	return s.orders.SetStatus(ctx, posOrderId, models.CLOSED.String())
+}
+
+func (s *sandboxPosService) UpdateOrderItems(ctx context.Context, order models.Order, store coreStoreModels.Store, diff models.OrderItemsDiff) (models.Order, error) {
+	if s.cfg.Failure == SandboxFailureTerminalOffline {
+		return order, errSandboxTerminalOffline
+	}
+
+	if _, err := s.orders.Get(ctx, order.PosOrderID); err != nil {
+		return order, errors.Wrapf(err, "sandbox order %s", order.PosOrderID)
+	}
+
+	return order, nil
+}
@@ -0,0 +1,62 @@
+package pos
+
+import (
+	"context"
+	"time"
+
+	errs "github.com/kwaaka-team/orders-core/core/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// sandboxOrderCollectionName - заказы тестовой pos системы, удаляются TTL индексом по created_at
+const sandboxOrderCollectionName = "sandbox_orders"
+
+type SandboxOrder struct {
+	PosOrderID string    `bson:"_id"`
+	Status     string    `bson:"status,omitempty"`
+	CreatedAt  time.Time `bson:"created_at"`
+}
+
+// SandboxOrderRepository хранит заказы тестовой pos системы, чтобы статусы не терялись между инстансами и рестартами
+type SandboxOrderRepository interface {
+	Save(ctx context.Context, order SandboxOrder) error
+	Get(ctx context.Context, posOrderID string) (SandboxOrder, error)
+	SetStatus(ctx context.Context, posOrderID, status string) error
+}
+
+type MongoSandboxOrderRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoSandboxOrderRepository(db *mongo.Database) (*MongoSandboxOrderRepository, error) {
+	return &MongoSandboxOrderRepository{
+		collection: db.Collection(sandboxOrderCollectionName),
+	}, nil
+}
+
+func (r *MongoSandboxOrderRepository) Save(ctx context.Context, order SandboxOrder) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.D{{Key: "_id", Value: order.PosOrderID}}, order, options.Replace().SetUpsert(true))
+	return errs.ErrorSwitch(err)
+}
+
+func (r *MongoSandboxOrderRepository) Get(ctx context.Context, posOrderID string) (SandboxOrder, error) {
+	var order SandboxOrder
+	if err := r.collection.FindOne(ctx, bson.D{{Key: "_id", Value: posOrderID}}).Decode(&order); err != nil {
+		return SandboxOrder{}, errs.ErrorSwitch(err)
+	}
+
+	return order, nil
+}
+
+// SetStatus - статус заказа, заданный отменой или закрытием, заказ, созданный до хранения в mongo, создается
+func (r *MongoSandboxOrderRepository) SetStatus(ctx context.Context, posOrderID, status string) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "status", Value: status}}},
+		{Key: "$setOnInsert", Value: bson.D{{Key: "created_at", Value: time.Now().UTC()}}},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: posOrderID}}, update, options.Update().SetUpsert(true))
+	return errs.ErrorSwitch(err)
+}
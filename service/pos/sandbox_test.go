@@ -0,0 +1,163 @@
+package pos
+
+import (
+	"context"
+	"github.com/kwaaka-team/orders-core/core/config"
+	errs "github.com/kwaaka-team/orders-core/core/errors"
+	coreMenuModels "github.com/kwaaka-team/orders-core/core/menu/models"
+	"github.com/kwaaka-team/orders-core/core/models"
+	coreStoreModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type sandboxOrderRepositoryStub struct {
+	orders map[string]SandboxOrder
+}
+
+func newSandboxOrderRepositoryStub() *sandboxOrderRepositoryStub {
+	return &sandboxOrderRepositoryStub{orders: map[string]SandboxOrder{}}
+}
+
+func (r *sandboxOrderRepositoryStub) Save(ctx context.Context, order SandboxOrder) error {
+	r.orders[order.PosOrderID] = order
+	return nil
+}
+
+func (r *sandboxOrderRepositoryStub) Get(ctx context.Context, posOrderID string) (SandboxOrder, error) {
+	order, ok := r.orders[posOrderID]
+	if !ok {
+		return SandboxOrder{}, errs.ErrNotFound
+	}
+	return order, nil
+}
+
+func (r *sandboxOrderRepositoryStub) SetStatus(ctx context.Context, posOrderID, status string) error {
+	order, ok := r.orders[posOrderID]
+	if !ok {
+		order = SandboxOrder{PosOrderID: posOrderID, CreatedAt: time.Now().UTC()}
+	}
+	order.Status = status
+	r.orders[posOrderID] = order
+	return nil
+}
+
+func TestSandboxTimelineStatus(t *testing.T) {
+	svc, err := newSandboxPosService(&BasePosService{}, coreStoreModels.SandboxConfig{}, newSandboxOrderRepositoryStub())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "ACCEPTED", svc.timelineStatus(0))
+	assert.Equal(t, "ACCEPTED", svc.timelineStatus(59*time.Second))
+	assert.Equal(t, "COOKING_STARTED", svc.timelineStatus(time.Minute))
+	assert.Equal(t, "COOKING_COMPLETE", svc.timelineStatus(6*time.Minute))
+	assert.Equal(t, "READY_FOR_PICKUP", svc.timelineStatus(time.Hour))
+}
+
+func TestSandbox_StatusProgression(t *testing.T) {
+	ctx := context.Background()
+	orders := newSandboxOrderRepositoryStub()
+	svc, err := newSandboxPosService(&BasePosService{}, coreStoreModels.SandboxConfig{}, orders)
+	assert.NoError(t, err)
+
+	order, err := svc.CreateOrder(ctx, models.Order{Products: []models.OrderProduct{{ID: "burger"}}}, config.Configuration{}, coreStoreModels.Store{}, coreMenuModels.Menu{}, nil, coreMenuModels.Menu{}, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, order.PosOrderID)
+	assert.Contains(t, orders.orders, order.PosOrderID)
+
+	// статус считается от создания заказа в pos, а не от created_at заказа
+	order.CreatedAt = models.Time{Time: time.Now().UTC().Add(-time.Hour)}
+	status, err := svc.GetOrderStatus(ctx, order)
+	assert.NoError(t, err)
+	assert.Equal(t, "ACCEPTED", status)
+
+	saved := orders.orders[order.PosOrderID]
+	saved.CreatedAt = time.Now().UTC().Add(-2 * time.Minute)
+	orders.orders[order.PosOrderID] = saved
+
+	status, err = svc.GetOrderStatus(ctx, order)
+	assert.NoError(t, err)
+	assert.Equal(t, "COOKING_STARTED", status)
+
+	_, err = svc.UpdateOrderItems(ctx, order, coreStoreModels.Store{}, models.OrderItemsDiff{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, svc.CloseOrder(ctx, order.PosOrderID))
+	status, err = svc.GetOrderStatus(ctx, order)
+	assert.NoError(t, err)
+	assert.Equal(t, "CLOSED", status)
+
+	systemStatus, err := svc.MapPosStatusToSystemStatus(status, "")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CLOSED, systemStatus)
+}
+
+func TestSandbox_CancelOrder(t *testing.T) {
+	ctx := context.Background()
+	orders := newSandboxOrderRepositoryStub()
+	svc, err := newSandboxPosService(&BasePosService{}, coreStoreModels.SandboxConfig{}, orders)
+	assert.NoError(t, err)
+
+	// заказ, которого нет в хранилище (создан до рестарта), тоже отменяется
+	order := models.Order{PosOrderID: "pos_order", CreatedAt: models.Time{Time: time.Now().UTC().Add(-time.Hour)}}
+	assert.NoError(t, svc.CancelOrder(ctx, order, coreStoreModels.Store{}))
+
+	status, err := svc.GetOrderStatus(ctx, order)
+	assert.NoError(t, err)
+	assert.Equal(t, sandboxStatusCancelled, status)
+
+	systemStatus, err := svc.MapPosStatusToSystemStatus(status, "")
+	assert.NoError(t, err)
+	assert.Equal(t, models.CANCELLED_BY_POS_SYSTEM, systemStatus)
+}
+
+func TestSandbox_UnknownOrder(t *testing.T) {
+	ctx := context.Background()
+	svc, err := newSandboxPosService(&BasePosService{}, coreStoreModels.SandboxConfig{}, newSandboxOrderRepositoryStub())
+	assert.NoError(t, err)
+
+	// заказ без записи в хранилище идет по таймлайну от created_at заказа
+	order := models.Order{PosOrderID: "unknown", CreatedAt: models.Time{Time: time.Now().UTC().Add(-time.Hour)}}
+	status, err := svc.GetOrderStatus(ctx, order)
+	assert.NoError(t, err)
+	assert.Equal(t, "READY_FOR_PICKUP", status)
+
+	_, err = svc.UpdateOrderItems(ctx, order, coreStoreModels.Store{}, models.OrderItemsDiff{})
+	assert.ErrorIs(t, err, errs.ErrNotFound)
+}
+
+func TestSandbox_TerminalOffline(t *testing.T) {
+	ctx := context.Background()
+	orders := newSandboxOrderRepositoryStub()
+	svc, err := newSandboxPosService(&BasePosService{}, coreStoreModels.SandboxConfig{Failure: SandboxFailureTerminalOffline}, orders)
+	assert.NoError(t, err)
+
+	alive, err := svc.IsAliveStatus(ctx, coreStoreModels.Store{})
+	assert.NoError(t, err)
+	assert.False(t, alive)
+
+	order, err := svc.CreateOrder(ctx, models.Order{Products: []models.OrderProduct{{ID: "burger"}}}, config.Configuration{}, coreStoreModels.Store{}, coreMenuModels.Menu{}, nil, coreMenuModels.Menu{}, nil, nil, nil)
+	assert.ErrorIs(t, err, errSandboxTerminalOffline)
+	assert.Equal(t, OTHER_FAIL_REASON_CODE, order.FailReason.Code)
+	assert.Empty(t, orders.orders)
+
+	_, err = svc.GetStopList(ctx)
+	assert.ErrorIs(t, err, errSandboxTerminalOffline)
+
+	_, err = svc.GetMenu(ctx, coreStoreModels.Store{}, coreMenuModels.Menu{})
+	assert.ErrorIs(t, err, errSandboxTerminalOffline)
+
+	_, err = svc.UpdateOrderItems(ctx, models.Order{PosOrderID: "pos_order"}, coreStoreModels.Store{}, models.OrderItemsDiff{})
+	assert.ErrorIs(t, err, errSandboxTerminalOffline)
+}
+
+func TestSandbox_ProductInStopList(t *testing.T) {
+	orders := newSandboxOrderRepositoryStub()
+	svc, err := newSandboxPosService(&BasePosService{}, coreStoreModels.SandboxConfig{StopList: []string{"burger"}}, orders)
+	assert.NoError(t, err)
+
+	order, err := svc.CreateOrder(context.Background(), models.Order{Products: []models.OrderProduct{{ID: "burger"}}}, config.Configuration{}, coreStoreModels.Store{}, coreMenuModels.Menu{}, nil, coreMenuModels.Menu{}, nil, nil, nil)
+	assert.ErrorIs(t, err, errs.ErrProductNotFound)
+	assert.Equal(t, PRODUCT_MISSED_CODE, order.FailReason.Code)
+	assert.Empty(t, orders.orders)
+}
@@ -31,6 +31,7 @@ type Service interface {
 	GetSeqNumber(ctx context.Context) (string, error)
 	SortStoplistItemsByIsIgnored(ctx context.Context, menu coreMenuModels.Menu, items coreMenuModels.StopListItems) (coreMenuModels.StopListItems, error)
 	CloseOrder(ctx context.Context, posOrderId string) error
+	UpdateOrderItems(ctx context.Context, order models.Order, store coreStoreModels.Store, diff models.OrderItemsDiff) (models.Order, error) // applies aggregator order change without recreating order
 }
 
 type Factory interface {
@@ -70,6 +71,8 @@ type FactoryImpl struct {
 	ytimesToken     string
 
 	posistBaseUrl string
+
+	sandboxOrderRepository SandboxOrderRepository
 }
 
 func NewFactory(
@@ -84,6 +87,7 @@ func NewFactory(
 	rkeeper7XMLLisenceUrl,
 	syrveBaseURL,
 	yarosBaseUrl, yarosInfoSystem, tillypadBaseUrl, ytimesBaseUrl, ytimesToken string, posistBaseUrl string,
+	sandboxOrderRepository SandboxOrderRepository,
 ) (*FactoryImpl, error) {
 	var err error
 	transportToFrontTimeout := 0
@@ -141,6 +145,8 @@ func NewFactory(
 		posistBaseUrl:   posistBaseUrl,
 
 		ytimesBaseUrl: ytimesBaseUrl,
+
+		sandboxOrderRepository: sandboxOrderRepository,
 	}, nil
 }
 
@@ -176,6 +182,8 @@ func (f *FactoryImpl) GetPosService(posType models.Pos, store coreStoreModels.St
 		return f.getYtimesPosService(store)
 	case models.Posist:
 		return f.getPosistPosService(store)
+	case models.Sandbox:
+		return f.getSandboxPosService(store)
 	}
 
 	return nil, errors.New("pos " + posType.String() + " is not found")
@@ -329,3 +337,11 @@ func (f *FactoryImpl) getKwaakaPosService() (*kwaakaPosService, error) {
 	}
 	return s, nil
 }
+
+func (f *FactoryImpl) getSandboxPosService(store coreStoreModels.Store) (*sandboxPosService, error) {
+	s, err := newSandboxPosService(f.baseService, store.Sandbox, f.sandboxOrderRepository)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
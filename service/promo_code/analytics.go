@@ -0,0 +1,186 @@
+package promo_code
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/kwaaka-team/orders-core/core/externalapi/models"
+	storeSelector "github.com/kwaaka-team/orders-core/core/storecore/managers/selector"
+	"github.com/pkg/errors"
+)
+
+const (
+	// abuseUsersThreshold - со скольких пользователей на один телефон или устройство списания считаются подозрительными
+	abuseUsersThreshold = 3
+
+	abuseIdentityPhone  = "phone"
+	abuseIdentityDevice = "device"
+)
+
+// Analytics - списания промокодов ресторанов группы за период и подозрительные телефоны и устройства
+func (s *ServiceImpl) Analytics(ctx context.Context, req models.PromoCodeAnalyticsRequest) (models.PromoCodeAnalyticsResponse, error) {
+
+	s.logger.Infof("service: promo code analytics for restaurant group id: %v", req.RestaurantGroupID)
+
+	if !req.StartDate.Before(req.EndDate) {
+		return models.PromoCodeAnalyticsResponse{}, errors.New("start date must be before end date")
+	}
+
+	stores, err := s.store.GetRestaurantsByGroupId(ctx, storeSelector.Pagination{}, req.RestaurantGroupID)
+	if err != nil {
+		return models.PromoCodeAnalyticsResponse{}, err
+	}
+
+	storeIDs := make([]string, 0, len(stores))
+	for _, st := range stores {
+		storeIDs = append(storeIDs, st.ID)
+	}
+
+	var redemptions []models.PromoCodeRedemption
+	if len(storeIDs) != 0 {
+		if redemptions, err = s.redemptionRepo.FindByRestaurantIDs(ctx, storeIDs, req.StartDate, req.EndDate); err != nil {
+			return models.PromoCodeAnalyticsResponse{}, err
+		}
+	}
+
+	promoCodeIDs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, redemption := range redemptions {
+		if !seen[redemption.PromoCodeID] {
+			seen[redemption.PromoCodeID] = true
+			promoCodeIDs = append(promoCodeIDs, redemption.PromoCodeID)
+		}
+	}
+
+	var promoCodes []models.PromoCode
+	if len(promoCodeIDs) != 0 {
+		if promoCodes, err = s.repository.GetPromoCodesByIDs(ctx, promoCodeIDs); err != nil {
+			return models.PromoCodeAnalyticsResponse{}, err
+		}
+	}
+
+	res := promoCodeAnalytics(redemptions, promoCodes)
+	res.RestaurantGroupID = req.RestaurantGroupID
+	res.StartDate = req.StartDate
+	res.EndDate = req.EndDate
+
+	return res, nil
+}
+
+// promoCodeAnalytics считает итоги по промокодам, откаченные списания учитываются только в rolled_back.
+// В abuses попадают телефоны и устройства, с которых списывали на abuseUsersThreshold и больше пользователей
+func promoCodeAnalytics(redemptions []models.PromoCodeRedemption, promoCodes []models.PromoCode) models.PromoCodeAnalyticsResponse {
+	byID := make(map[string]*models.PromoCodeAnalytics)
+	for _, promoCode := range promoCodes {
+		byID[promoCode.ID] = &models.PromoCodeAnalytics{PromoCodeID: promoCode.ID, Code: promoCode.Code, Name: promoCode.Name}
+	}
+
+	type identity struct {
+		identityType, value string
+	}
+	type identityUsage struct {
+		users       map[string]bool
+		promoCodes  map[string]bool
+		redemptions int
+		discount    float64
+	}
+	identities := make(map[identity]*identityUsage)
+
+	var total models.PromoCodeAnalytics
+	for _, redemption := range redemptions {
+		analytics, ok := byID[redemption.PromoCodeID]
+		if !ok {
+			analytics = &models.PromoCodeAnalytics{PromoCodeID: redemption.PromoCodeID, Code: redemption.PromoCode}
+			byID[redemption.PromoCodeID] = analytics
+		}
+
+		if redemption.Status == models.PromoCodeRedemptionRolledBack {
+			analytics.RolledBack++
+			total.RolledBack++
+			continue
+		}
+
+		for _, a := range []*models.PromoCodeAnalytics{analytics, &total} {
+			a.Redemptions++
+			a.DiscountCost += redemption.Discount
+			a.Revenue += redemption.OrderTotal - redemption.Discount
+			if redemption.IsNewCustomer {
+				a.NewCustomers++
+				a.NewCustomersRevenue += redemption.OrderTotal - redemption.Discount
+			}
+		}
+
+		for _, key := range []identity{{abuseIdentityPhone, redemption.Phone}, {abuseIdentityDevice, redemption.DeviceID}} {
+			if key.value == "" {
+				continue
+			}
+			usage, ok := identities[key]
+			if !ok {
+				usage = &identityUsage{users: map[string]bool{}, promoCodes: map[string]bool{}}
+				identities[key] = usage
+			}
+			usage.users[redemption.UserId] = true
+			usage.promoCodes[redemption.PromoCode] = true
+			usage.redemptions++
+			usage.discount += redemption.Discount
+		}
+	}
+
+	res := models.PromoCodeAnalyticsResponse{
+		PromoCodes: make([]models.PromoCodeAnalytics, 0, len(byID)),
+		Abuses:     make([]models.PromoCodeAbuse, 0),
+	}
+	for _, analytics := range byID {
+		if analytics.Redemptions == 0 && analytics.RolledBack == 0 {
+			continue
+		}
+		res.PromoCodes = append(res.PromoCodes, roundPromoCodeAnalytics(*analytics))
+	}
+	sort.Slice(res.PromoCodes, func(i, j int) bool {
+		return res.PromoCodes[i].Code < res.PromoCodes[j].Code
+	})
+	res.Total = roundPromoCodeAnalytics(total)
+
+	for key, usage := range identities {
+		if len(usage.users) < abuseUsersThreshold {
+			continue
+		}
+		res.Abuses = append(res.Abuses, models.PromoCodeAbuse{
+			IdentityType: key.identityType,
+			Identity:     key.value,
+			UserIDs:      sortedKeys(usage.users),
+			PromoCodes:   sortedKeys(usage.promoCodes),
+			Redemptions:  usage.redemptions,
+			Discount:     math.Round(usage.discount*100) / 100,
+		})
+	}
+	sort.Slice(res.Abuses, func(i, j int) bool {
+		if len(res.Abuses[i].UserIDs) != len(res.Abuses[j].UserIDs) {
+			return len(res.Abuses[i].UserIDs) > len(res.Abuses[j].UserIDs)
+		}
+		return res.Abuses[i].Identity < res.Abuses[j].Identity
+	})
+
+	return res
+}
+
+func roundPromoCodeAnalytics(analytics models.PromoCodeAnalytics) models.PromoCodeAnalytics {
+	round := func(value float64) float64 {
+		return math.Round(value*100) / 100
+	}
+	analytics.DiscountCost = round(analytics.DiscountCost)
+	analytics.Revenue = round(analytics.Revenue)
+	analytics.NewCustomersRevenue = round(analytics.NewCustomersRevenue)
+	analytics.IncrementalRevenue = round(analytics.NewCustomersRevenue - analytics.DiscountCost)
+	return analytics
+}
+
+func sortedKeys(values map[string]bool) []string {
+	res := make([]string, 0, len(values))
+	for value := range values {
+		res = append(res, value)
+	}
+	sort.Strings(res)
+	return res
+}
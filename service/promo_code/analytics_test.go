@@ -0,0 +1,49 @@
+package promo_code
+
+import (
+	"testing"
+
+	"github.com/kwaaka-team/orders-core/core/externalapi/models"
+)
+
+func TestPromoCodeAnalytics(t *testing.T) {
+	redemption := func(userID, phone, deviceID string, orderTotal, discount float64, isNew bool, status string) models.PromoCodeRedemption {
+		return models.PromoCodeRedemption{
+			PromoCodeID:   "welcome-id",
+			PromoCode:     "WELCOME",
+			UserId:        userID,
+			Phone:         phone,
+			DeviceID:      deviceID,
+			OrderTotal:    orderTotal,
+			Discount:      discount,
+			IsNewCustomer: isNew,
+			Status:        status,
+		}
+	}
+
+	res := promoCodeAnalytics([]models.PromoCodeRedemption{
+		redemption("user-1", "+77000000001", "device", 5000, 500, true, models.PromoCodeRedemptionRedeemed),
+		redemption("user-2", "+77000000002", "device", 3000, 300, true, models.PromoCodeRedemptionRedeemed),
+		redemption("user-3", "+77000000003", "device", 4000, 400, false, models.PromoCodeRedemptionRedeemed),
+		redemption("user-4", "+77000000004", "", 9000, 900, true, models.PromoCodeRedemptionRolledBack),
+	}, []models.PromoCode{{ID: "welcome-id", Code: "WELCOME", Name: "Welcome"}})
+
+	if len(res.PromoCodes) != 1 || res.PromoCodes[0].Name != "Welcome" {
+		t.Fatalf("unexpected promo codes %+v", res.PromoCodes)
+	}
+
+	total := res.Total
+	if total.Redemptions != 3 || total.RolledBack != 1 || total.NewCustomers != 2 {
+		t.Errorf("unexpected counts %+v", total)
+	}
+	if total.DiscountCost != 1200 || total.Revenue != 10800 || total.NewCustomersRevenue != 7200 || total.IncrementalRevenue != 6000 {
+		t.Errorf("unexpected money %+v", total)
+	}
+
+	if len(res.Abuses) != 1 {
+		t.Fatalf("expected one abuse, got %+v", res.Abuses)
+	}
+	if abuse := res.Abuses[0]; abuse.IdentityType != abuseIdentityDevice || abuse.Identity != "device" || len(abuse.UserIDs) != 3 || abuse.Discount != 1200 {
+		t.Errorf("unexpected abuse %+v", abuse)
+	}
+}
@@ -5,4 +5,8 @@ import "github.com/pkg/errors"
 var (
 	ErrPromoCodeNotFound  = errors.New("promo code not found")
 	ErrInvalidPromoCodeID = errors.New("invalid promocode id")
+	ErrBudgetExceeded     = errors.New("promo code usage limit or budget exceeded")
+	ErrRedemptionNotFound = errors.New("promo code redemption not found")
+	// ErrRedemptionExists - у заказа уже есть действующее списание
+	ErrRedemptionExists = errors.New("promo code redemption already exists for order")
 )
@@ -0,0 +1,165 @@
+package promo_code
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kwaaka-team/orders-core/core/externalapi/models"
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+	orderSelector "github.com/kwaaka-team/orders-core/core/models/selector"
+	"github.com/kwaaka-team/orders-core/service/promo_code/dto"
+	"github.com/pkg/errors"
+)
+
+const redemptionDayLayout = "2006-01-02"
+
+// RedeemPromoCode проверяет промокод, атомарно списывает его из лимитов и записывает списание по заказу.
+// Повторный вызов по тому же заказу возвращает уже сделанное списание
+func (s *ServiceImpl) RedeemPromoCode(ctx context.Context, req models.RedeemPromoCodeRequest) (models.ValidateUserPromoCodeResponse, error) {
+
+	s.logger.Infof("service: redeem promo code: %v for order id: %v", req.PromoCode, req.OrderID)
+
+	redemption, err := s.redemptionRepo.GetActiveByOrderID(ctx, req.OrderID)
+	switch {
+	case err == nil:
+		return existingRedemptionResponse(redemption), nil
+	case !errors.Is(err, dto.ErrRedemptionNotFound):
+		return models.ValidateUserPromoCodeResponse{}, err
+	}
+
+	exist, comment, totalPrice, salePrice, products, err := s.ValidatePromoCodeForUser(ctx, req.ValidateUserPromoCode)
+	if err != nil || !exist {
+		return models.ValidateUserPromoCodeResponse{Comment: comment}, err
+	}
+
+	promo, err := s.repository.GetPromoByCodeAndRestaurantID(ctx, req.PromoCode, req.RestaurantID)
+	if err != nil {
+		return models.ValidateUserPromoCodeResponse{}, err
+	}
+
+	// лимит на пользователя действует и на телефон с устройством, иначе его обходят новыми пользователями
+	identityUsages, err := s.redemptionRepo.CountActiveByIdentity(ctx, promo.ID, req.Phone, req.DeviceID)
+	if err != nil {
+		return models.ValidateUserPromoCodeResponse{}, err
+	}
+	if identityLimitReached(promo.UsageTime, identityUsages) {
+		s.logger.Infof("promo code: %v usage limit exceeded by phone: %v or device: %v", req.PromoCode, req.Phone, req.DeviceID)
+		return models.ValidateUserPromoCodeResponse{
+			Comment: fmt.Sprintf("Промокод %v можно использовать %v раз, вы исчерпали свой лимит", req.PromoCode, promo.UsageTime),
+		}, nil
+	}
+
+	restaurant, err := s.store.GetByID(ctx, req.RestaurantID)
+	if err != nil {
+		return models.ValidateUserPromoCodeResponse{}, err
+	}
+	day := time.Now().In(restaurant.Settings.TimeZone.Location()).Format(redemptionDayLayout)
+
+	isNewCustomer := false
+	if req.Phone != "" {
+		ordersCount, err := s.orderCounter.GetOrderNumber(ctx, orderSelector.EmptyOrderSearch().
+			SetCustomerNumber(req.Phone).
+			SetRestaurants([]string{req.RestaurantID}))
+		if err != nil {
+			return models.ValidateUserPromoCodeResponse{}, err
+		}
+		isNewCustomer = ordersCount == 0
+	}
+
+	discount := float64(salePrice)
+	if err = s.repository.ReserveBudget(ctx, promo.ID, day, discount); err != nil {
+		if errors.Is(err, dto.ErrBudgetExceeded) {
+			return models.ValidateUserPromoCodeResponse{
+				Comment: fmt.Sprintf("Промокод %v больше недоступен, лимит промокода исчерпан", req.PromoCode),
+			}, nil
+		}
+		return models.ValidateUserPromoCodeResponse{}, err
+	}
+
+	if _, err = s.redemptionRepo.Insert(ctx, models.PromoCodeRedemption{
+		PromoCodeID:   promo.ID,
+		PromoCode:     promo.Code,
+		RestaurantID:  req.RestaurantID,
+		OrderID:       req.OrderID,
+		UserId:        req.UserId,
+		Phone:         req.Phone,
+		DeviceID:      req.DeviceID,
+		Day:           day,
+		OrderTotal:    float64(req.TotalSum),
+		Discount:      discount,
+		IsNewCustomer: isNewCustomer,
+	}); err != nil {
+		if releaseErr := s.repository.ReleaseBudget(ctx, promo.ID, day, discount); releaseErr != nil {
+			s.logger.Errorf("release promo code: %v budget error: %s", promo.ID, releaseErr.Error())
+		}
+		// одновременный запрос по тому же заказу уже списал промокод
+		if errors.Is(err, dto.ErrRedemptionExists) {
+			if redemption, err = s.redemptionRepo.GetActiveByOrderID(ctx, req.OrderID); err != nil {
+				return models.ValidateUserPromoCodeResponse{}, err
+			}
+			return existingRedemptionResponse(redemption), nil
+		}
+		return models.ValidateUserPromoCodeResponse{}, err
+	}
+
+	if err = s.AddUserPromoCodeUsageTimeToDB(ctx, req.UserId, req.PromoCode, req.RestaurantID); err != nil {
+		s.logger.Errorf("add user: %v promo code: %v usage time error: %s", req.UserId, req.PromoCode, err.Error())
+	}
+
+	if req.UserId != "" && req.Phone != "" {
+		if err = s.identityLinker.LinkIdentity(ctx, req.Phone, coreModels.CustomerIdentity{
+			Source:     coreModels.CustomerIdentityQrMenuUser,
+			ExternalID: req.UserId,
+		}); err != nil {
+			s.logger.Errorf("link qr menu user: %v to customer profile error: %s", req.UserId, err.Error())
+		}
+	}
+
+	return models.ValidateUserPromoCodeResponse{
+		Exist:      true,
+		Comment:    comment,
+		TotalPrice: totalPrice,
+		SalePrice:  salePrice,
+		Products:   products,
+	}, nil
+}
+
+func existingRedemptionResponse(redemption models.PromoCodeRedemption) models.ValidateUserPromoCodeResponse {
+	return models.ValidateUserPromoCodeResponse{
+		Exist:      true,
+		Comment:    fmt.Sprintf("Промокод %v уже применен к заказу", redemption.PromoCode),
+		TotalPrice: redemption.OrderTotal - redemption.Discount,
+		SalePrice:  int(redemption.Discount),
+	}
+}
+
+// identityLimitReached - лимит на телефон и устройство. usage_time 0 проверяет ValidatePromoCodeForUser, здесь он лимитом не считается
+func identityLimitReached(usageTime, identityUsages int) bool {
+	return usageTime > 0 && identityUsages >= usageTime
+}
+
+// RollbackRedemption возвращает списание заказа в лимиты промокода и пользователя, повторный откат ничего не делает
+func (s *ServiceImpl) RollbackRedemption(ctx context.Context, orderID, reason string) error {
+
+	s.logger.Infof("service: rollback promo code redemption for order id: %v, reason: %v", orderID, reason)
+
+	redemption, err := s.redemptionRepo.GetActiveByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	rolledBack, err := s.redemptionRepo.SetRolledBack(ctx, redemption.ID, reason)
+	if err != nil {
+		return err
+	}
+	if !rolledBack {
+		return nil
+	}
+
+	if err = s.repository.ReleaseBudget(ctx, redemption.PromoCodeID, redemption.Day, redemption.Discount); err != nil {
+		return err
+	}
+
+	return s.userPromoCodeRepo.DecrementUsageTimeForUser(ctx, redemption.UserId, redemption.PromoCode, redemption.RestaurantID)
+}
@@ -0,0 +1,148 @@
+package redemption_repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kwaaka-team/orders-core/core/externalapi/models"
+	"github.com/kwaaka-team/orders-core/service/promo_code/dto"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const redemptionCollectionName = "promo_code_redemptions"
+
+type Repository interface {
+	Insert(ctx context.Context, redemption models.PromoCodeRedemption) (string, error)
+	GetActiveByOrderID(ctx context.Context, orderID string) (models.PromoCodeRedemption, error)
+	CountActiveByIdentity(ctx context.Context, promoCodeID, phone, deviceID string) (int, error)
+	SetRolledBack(ctx context.Context, id, reason string) (bool, error)
+	FindByRestaurantIDs(ctx context.Context, restaurantIDs []string, from, to time.Time) ([]models.PromoCodeRedemption, error)
+}
+
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(db *mongo.Database) (*MongoRepository, error) {
+	r := MongoRepository{
+		collection: db.Collection(redemptionCollectionName),
+	}
+	return &r, nil
+}
+
+func (r *MongoRepository) Insert(ctx context.Context, redemption models.PromoCodeRedemption) (string, error) {
+	redemption.ID = ""
+	redemption.Status = models.PromoCodeRedemptionRedeemed
+	redemption.CreatedAt = time.Now().UTC()
+
+	res, err := r.collection.InsertOne(ctx, redemption)
+	if err != nil {
+		// уникальный частичный индекс по order_id для status=redeemed
+		if mongo.IsDuplicateKeyError(err) {
+			return "", dto.ErrRedemptionExists
+		}
+		return "", err
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", errors.New("inserted id is not object id")
+	}
+
+	return oid.Hex(), nil
+}
+
+func (r *MongoRepository) GetActiveByOrderID(ctx context.Context, orderID string) (models.PromoCodeRedemption, error) {
+	filter := bson.D{
+		{Key: "order_id", Value: orderID},
+		{Key: "status", Value: models.PromoCodeRedemptionRedeemed},
+	}
+
+	var redemption models.PromoCodeRedemption
+	if err := r.collection.FindOne(ctx, filter).Decode(&redemption); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.PromoCodeRedemption{}, dto.ErrRedemptionNotFound
+		}
+		return models.PromoCodeRedemption{}, err
+	}
+
+	return redemption, nil
+}
+
+// CountActiveByIdentity - действующие списания промокода с телефона или устройства, пустые значения не учитываются
+func (r *MongoRepository) CountActiveByIdentity(ctx context.Context, promoCodeID, phone, deviceID string) (int, error) {
+	var identities bson.A
+	if phone != "" {
+		identities = append(identities, bson.D{{Key: "phone", Value: phone}})
+	}
+	if deviceID != "" {
+		identities = append(identities, bson.D{{Key: "device_id", Value: deviceID}})
+	}
+	if len(identities) == 0 {
+		return 0, nil
+	}
+
+	filter := bson.D{
+		{Key: "promo_code_id", Value: promoCodeID},
+		{Key: "status", Value: models.PromoCodeRedemptionRedeemed},
+		{Key: "$or", Value: identities},
+	}
+
+	count, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// SetRolledBack - false, если списание уже откатили
+func (r *MongoRepository) SetRolledBack(ctx context.Context, id, reason string) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: objID},
+		{Key: "status", Value: models.PromoCodeRedemptionRedeemed},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: models.PromoCodeRedemptionRolledBack},
+		{Key: "rollback_reason", Value: reason},
+		{Key: "rolled_back_at", Value: time.Now().UTC()},
+	}}}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return res.ModifiedCount != 0, nil
+}
+
+func (r *MongoRepository) FindByRestaurantIDs(ctx context.Context, restaurantIDs []string, from, to time.Time) ([]models.PromoCodeRedemption, error) {
+	filter := bson.D{
+		{Key: "restaurant_id", Value: bson.D{{Key: "$in", Value: restaurantIDs}}},
+		{Key: "created_at", Value: bson.D{
+			{Key: "$gte", Value: from},
+			{Key: "$lt", Value: to},
+		}},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var redemptions []models.PromoCodeRedemption
+	if err = cursor.All(ctx, &redemptions); err != nil {
+		return nil, err
+	}
+
+	return redemptions, nil
+}
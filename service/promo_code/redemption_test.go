@@ -0,0 +1,22 @@
+package promo_code
+
+import "testing"
+
+func TestIdentityLimitReached(t *testing.T) {
+	tests := []struct {
+		name           string
+		usageTime      int
+		identityUsages int
+		want           bool
+	}{
+		{"below limit", 2, 1, false},
+		{"limit reached", 2, 2, true},
+		{"zero usage time is not an identity limit", 0, 3, false},
+	}
+
+	for _, test := range tests {
+		if got := identityLimitReached(test.usageTime, test.identityUsages); got != test.want {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+}
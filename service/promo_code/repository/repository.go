@@ -21,6 +21,9 @@ type Repository interface {
 	GetPromoCodeByID(ctx context.Context, promoCodeID string) (models.PromoCode, error)
 	GetPromoCodesByRestaurantId(ctx context.Context, restaurantId string, pagination selector.Pagination) ([]models.PromoCode, error)
 	GetAvailablePromoCodeByCode(ctx context.Context, promoCodeValue string) (models.PromoCode, error)
+	ReserveBudget(ctx context.Context, promoCodeID, day string, discount float64) error
+	ReleaseBudget(ctx context.Context, promoCodeID, day string, discount float64) error
+	GetPromoCodesByIDs(ctx context.Context, ids []string) ([]models.PromoCode, error)
 }
 
 type MongoRepository struct {
@@ -37,6 +40,9 @@ func NewMongoRepository(db *mongo.Database) (*MongoRepository, error) {
 func (r *MongoRepository) CreatePromo(ctx context.Context, promoCodeRequest models.PromoCode) error {
 
 	promoCodeRequest.CreatedAt = time.Now()
+	promoCodeRequest.RedemptionsCount = 0
+	promoCodeRequest.DiscountSpent = 0
+	promoCodeRequest.DailyUsages = nil
 
 	_, err := r.collection.InsertOne(ctx, promoCodeRequest)
 	if err != nil {
@@ -174,6 +180,30 @@ func (r *MongoRepository) setPromocodeUpdateFields(promocode models.UpdatePromoC
 			Value: *promocode.IsDeleted,
 		})
 	}
+	if promocode.TotalUsageLimit != nil {
+		update = append(update, bson.E{
+			Key:   "total_usage_limit",
+			Value: *promocode.TotalUsageLimit,
+		})
+	}
+	if promocode.DailyUsageLimit != nil {
+		update = append(update, bson.E{
+			Key:   "daily_usage_limit",
+			Value: *promocode.DailyUsageLimit,
+		})
+	}
+	if promocode.TotalBudget != nil {
+		update = append(update, bson.E{
+			Key:   "total_budget",
+			Value: *promocode.TotalBudget,
+		})
+	}
+	if promocode.DailyBudget != nil {
+		update = append(update, bson.E{
+			Key:   "daily_budget",
+			Value: *promocode.DailyBudget,
+		})
+	}
 	update = append(update, bson.E{
 		Key:   "updated_at",
 		Value: time.Now().UTC(),
@@ -301,3 +331,98 @@ func (r *MongoRepository) applyPagination(pagination selector.Pagination) []bson
 
 	return withPagination
 }
+
+// ReserveBudget атомарно увеличивает счетчики промокода, если после списания не будет превышен ни один из лимитов.
+// Проверка и списание в одном update, поэтому параллельные списания не выходят за лимиты
+func (r *MongoRepository) ReserveBudget(ctx context.Context, promoCodeID, day string, discount float64) error {
+	objID, err := primitive.ObjectIDFromHex(promoCodeID)
+	if err != nil {
+		return dto.ErrInvalidPromoCodeID
+	}
+
+	dailyCount := "daily_usages." + day + ".count"
+	dailyDiscount := "daily_usages." + day + ".discount"
+
+	filter := bson.D{
+		{Key: "_id", Value: objID},
+		{Key: "$expr", Value: bson.D{{Key: "$and", Value: bson.A{
+			limitNotExceeded("total_usage_limit", "redemptions_count", 1),
+			limitNotExceeded("daily_usage_limit", dailyCount, 1),
+			limitNotExceeded("total_budget", "discount_spent", discount),
+			limitNotExceeded("daily_budget", dailyDiscount, discount),
+		}}}},
+	}
+
+	res, err := r.collection.UpdateOne(ctx, filter, budgetIncrement(dailyCount, dailyDiscount, 1, discount))
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return dto.ErrBudgetExceeded
+	}
+
+	return nil
+}
+
+// ReleaseBudget возвращает списание в счетчики промокода, дневные счетчики - дня списания
+func (r *MongoRepository) ReleaseBudget(ctx context.Context, promoCodeID, day string, discount float64) error {
+	objID, err := primitive.ObjectIDFromHex(promoCodeID)
+	if err != nil {
+		return dto.ErrInvalidPromoCodeID
+	}
+
+	res, err := r.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: objID}},
+		budgetIncrement("daily_usages."+day+".count", "daily_usages."+day+".discount", -1, -discount))
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return dto.ErrPromoCodeNotFound
+	}
+
+	return nil
+}
+
+func (r *MongoRepository) GetPromoCodesByIDs(ctx context.Context, ids []string) ([]models.PromoCode, error) {
+	objIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, dto.ErrInvalidPromoCodeID
+		}
+		objIDs = append(objIDs, objID)
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: objIDs}}}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var promoCodes []models.PromoCode
+	if err = cursor.All(ctx, &promoCodes); err != nil {
+		return nil, err
+	}
+
+	return promoCodes, nil
+}
+
+// limitNotExceeded - лимит не задан или значение после списания add не больше лимита
+func limitNotExceeded(limitField, valueField string, add interface{}) bson.D {
+	limit := bson.D{{Key: "$ifNull", Value: bson.A{"$" + limitField, 0}}}
+	value := bson.D{{Key: "$ifNull", Value: bson.A{"$" + valueField, 0}}}
+
+	return bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "$lte", Value: bson.A{limit, 0}}},
+		bson.D{{Key: "$lte", Value: bson.A{bson.D{{Key: "$add", Value: bson.A{value, add}}}, limit}}},
+	}}}
+}
+
+func budgetIncrement(dailyCount, dailyDiscount string, count int, discount float64) bson.D {
+	return bson.D{{Key: "$inc", Value: bson.D{
+		{Key: "redemptions_count", Value: count},
+		{Key: "discount_spent", Value: discount},
+		{Key: dailyCount, Value: count},
+		{Key: dailyDiscount, Value: discount},
+	}}}
+}
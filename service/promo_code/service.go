@@ -6,10 +6,13 @@ import (
 	"github.com/kwaaka-team/orders-core/core/externalapi/models"
 	models3 "github.com/kwaaka-team/orders-core/core/menu/models"
 	"github.com/kwaaka-team/orders-core/core/menu/models/selector"
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+	orderSelector "github.com/kwaaka-team/orders-core/core/models/selector"
 	models2 "github.com/kwaaka-team/orders-core/core/qrmenu/models"
 	models4 "github.com/kwaaka-team/orders-core/core/storecore/models"
 	menuServicePkg "github.com/kwaaka-team/orders-core/service/menu"
 	"github.com/kwaaka-team/orders-core/service/promo_code/dto"
+	"github.com/kwaaka-team/orders-core/service/promo_code/redemption_repository"
 	"github.com/kwaaka-team/orders-core/service/promo_code/repository"
 	"github.com/kwaaka-team/orders-core/service/promo_code/user_repository"
 	"github.com/kwaaka-team/orders-core/service/store"
@@ -29,23 +32,46 @@ type Service interface {
 	GetPromoCodeByCodeAndRestaurantId(ctx context.Context, promoCodeValue string, restaurantId string) (models.PromoCode, error)
 	AddUserPromoCodeUsageTimeToDB(ctx context.Context, userId string, promoCodeValue string, restaurantId string) error
 	GetAvailablePromoCodeByCode(ctx context.Context, promoCodeValue string) (models.PromoCode, error)
+	RedeemPromoCode(ctx context.Context, req models.RedeemPromoCodeRequest) (models.ValidateUserPromoCodeResponse, error)
+	RollbackRedemption(ctx context.Context, orderID, reason string) error
+	Analytics(ctx context.Context, req models.PromoCodeAnalyticsRequest) (models.PromoCodeAnalyticsResponse, error)
+}
+
+// OrderCounter - количество заказов клиента для определения новых клиентов, реализуется репозиторием заказов
+type OrderCounter interface {
+	GetOrderNumber(ctx context.Context, query orderSelector.Order) (int64, error)
+}
+
+// IdentityLinker - привязка пользователя qr меню к профилю клиента по телефону, реализуется сервисом клиентов
+type IdentityLinker interface {
+	LinkIdentity(ctx context.Context, phone string, identity coreModels.CustomerIdentity) error
 }
 
 type ServiceImpl struct {
 	repository        repository.Repository
 	userPromoCodeRepo user_repository.Repository
+	redemptionRepo    redemption_repository.Repository
 	store             store.Service
 	menuService       *menuServicePkg.Service
+	orderCounter      OrderCounter
+	identityLinker    IdentityLinker
 	logger            *zap.SugaredLogger
 }
 
-func NewPromoCodeService(logger *zap.SugaredLogger, repo repository.Repository, userRepo user_repository.Repository, store store.Service, menu *menuServicePkg.Service) (*ServiceImpl, error) {
+func NewPromoCodeService(logger *zap.SugaredLogger, repo repository.Repository, userRepo user_repository.Repository, redemptionRepo redemption_repository.Repository, store store.Service, menu *menuServicePkg.Service, orderCounter OrderCounter, identityLinker IdentityLinker) (*ServiceImpl, error) {
+	if identityLinker == nil {
+		return nil, errors.New("identity linker is nil")
+	}
+
 	return &ServiceImpl{
 		logger:            logger,
 		repository:        repo,
 		userPromoCodeRepo: userRepo,
+		redemptionRepo:    redemptionRepo,
 		store:             store,
 		menuService:       menu,
+		orderCounter:      orderCounter,
+		identityLinker:    identityLinker,
 	}, nil
 }
 
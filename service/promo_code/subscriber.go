@@ -0,0 +1,44 @@
+package promo_code
+
+import (
+	"context"
+
+	"github.com/kwaaka-team/orders-core/core/models"
+	coreStoreModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/promo_code/dto"
+	"github.com/pkg/errors"
+)
+
+// RedemptionSubscriber откатывает списание промокода, когда заказ отменен или отклонен
+type RedemptionSubscriber struct {
+	promoCodeService Service
+}
+
+func NewRedemptionSubscriber(promoCodeService Service) (*RedemptionSubscriber, error) {
+	if promoCodeService == nil {
+		return nil, errors.New("promo code service is nil")
+	}
+	return &RedemptionSubscriber{promoCodeService: promoCodeService}, nil
+}
+
+func (s *RedemptionSubscriber) SendOrder(ctx context.Context, order models.Order, store coreStoreModels.Store, posStatus models.PosStatus) error {
+	if order.PromoCode == "" || !isReleaseStatus(posStatus) {
+		return nil
+	}
+
+	err := s.promoCodeService.RollbackRedemption(ctx, order.OrderID, posStatus.String())
+	if err != nil && !errors.Is(err, dto.ErrRedemptionNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// isReleaseStatus - статусы отмены и отклонения заказа, после которых использование промокода возвращается
+func isReleaseStatus(posStatus models.PosStatus) bool {
+	switch posStatus {
+	case models.CANCELLED_BY_POS_SYSTEM, models.FAILED, models.PAYMENT_CANCELLED, models.PAYMENT_DELETED:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package promo_code
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kwaaka-team/orders-core/core/models"
+	coreStoreModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+)
+
+type rollbackServiceStub struct {
+	Service
+	rolledBack []string
+}
+
+func (s *rollbackServiceStub) RollbackRedemption(ctx context.Context, orderID, reason string) error {
+	s.rolledBack = append(s.rolledBack, orderID)
+	return nil
+}
+
+func TestRedemptionSubscriber_SendOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		promoCode string
+		status    models.PosStatus
+		want      bool
+	}{
+		{"cancelled by pos", "SALE", models.CANCELLED_BY_POS_SYSTEM, true},
+		{"failed", "SALE", models.FAILED, true},
+		{"payment cancelled", "SALE", models.PAYMENT_CANCELLED, true},
+		{"payment deleted", "SALE", models.PAYMENT_DELETED, true},
+		{"accepted keeps usage", "SALE", models.ACCEPTED, false},
+		{"closed keeps usage", "SALE", models.CLOSED, false},
+		{"order without promo code", "", models.CANCELLED_BY_POS_SYSTEM, false},
+	}
+
+	for _, test := range tests {
+		service := &rollbackServiceStub{}
+		subscriber, err := NewRedemptionSubscriber(service)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		order := models.Order{OrderID: "order-1", PromoCode: test.promoCode}
+		if err := subscriber.SendOrder(context.Background(), order, coreStoreModels.Store{}, test.status); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+
+		if got := len(service.rolledBack) == 1; got != test.want {
+			t.Errorf("%s: expected rollback %v, got %v", test.name, test.want, got)
+		}
+	}
+}
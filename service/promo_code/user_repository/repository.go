@@ -14,6 +14,7 @@ type Repository interface {
 	CreateUserUsePromoCodeTime(ctx context.Context, userId, promoCode string, restIds []string) error
 	GetUsageCountForUser(ctx context.Context, userId, promoCode, restaurantId string) (int, error)
 	UpdateUsageTimeForUser(ctx context.Context, userId, promoCode, restaurantId string, usageTime int) error
+	DecrementUsageTimeForUser(ctx context.Context, userId, promoCode, restaurantId string) error
 }
 
 type MongoRepository struct {
@@ -87,3 +88,20 @@ func (r *MongoRepository) UpdateUsageTimeForUser(ctx context.Context, userId, pr
 	}
 	return nil
 }
+
+func (r *MongoRepository) DecrementUsageTimeForUser(ctx context.Context, userId, promoCode, restaurantId string) error {
+
+	filter := bson.D{
+		{Key: "user_id", Value: userId},
+		{Key: "promo_code", Value: promoCode},
+		{Key: "restaurant_ids", Value: restaurantId},
+		{Key: "usage_time", Value: bson.D{{Key: "$gt", Value: 0}}},
+	}
+
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "usage_time", Value: -1}}}}
+
+	if _, err := r.collection.UpdateOne(ctx, filter, update); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package models
+
+import (
+	"math"
+	"sort"
+
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+)
+
+type AppliedPromotion struct {
+	PromotionID string  `bson:"promotion_id" json:"promotion_id"`
+	Name        string  `bson:"name" json:"name"`
+	Type        Type    `bson:"type" json:"type"`
+	Discount    float64 `bson:"discount" json:"discount"`
+}
+
+// LineDiscount - скидки позиции корзины, price - цена единицы без модификаторов
+type LineDiscount struct {
+	ProductID  string             `bson:"product_id" json:"product_id"`
+	Name       string             `bson:"name" json:"name"`
+	Quantity   int                `bson:"quantity" json:"quantity"`
+	Price      float64            `bson:"price" json:"price"`
+	Discount   float64            `bson:"discount" json:"discount"`
+	Promotions []AppliedPromotion `bson:"promotions" json:"promotions"`
+}
+
+// Result - скидки акций на корзину, при онлайн оплате сохраняется снимком в платеже
+type Result struct {
+	Subtotal   float64            `bson:"subtotal" json:"subtotal"`
+	Discount   float64            `bson:"discount" json:"discount"`
+	Total      float64            `bson:"total" json:"total"`
+	Lines      []LineDiscount     `bson:"lines" json:"lines"`
+	Promotions []AppliedPromotion `bson:"promotions" json:"promotions"`
+}
+
+type unit struct {
+	line      int
+	productID string
+	remaining float64
+}
+
+// Apply применяет акции к корзине и возвращает скидки по позициям.
+// Скидки считаются от цены продуктов без модификаторов, каждая следующая акция - от цены после предыдущих
+func Apply(cart coreModels.Cart, promotions []Promotion, c Context) Result {
+	res := Result{Lines: make([]LineDiscount, 0, len(cart.Items))}
+
+	var units []*unit
+	for i, item := range cart.Items {
+		res.Lines = append(res.Lines, LineDiscount{
+			ProductID: item.ProductID,
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			Price:     item.Price,
+		})
+		res.Subtotal += item.Price * float64(item.Quantity)
+		for q := 0; q < item.Quantity; q++ {
+			units = append(units, &unit{line: i, productID: item.ProductID, remaining: item.Price})
+		}
+	}
+
+	sorted := make([]Promotion, len(promotions))
+	copy(sorted, promotions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority > sorted[j].Priority
+	})
+
+	for _, promotion := range sorted {
+		if !promotion.IsApplicable(c) {
+			continue
+		}
+		if !promotion.Stackable && len(res.Promotions) != 0 {
+			continue
+		}
+
+		discounts := promotion.discounts(units)
+
+		lineDiscounts := make(map[int]float64)
+		var total float64
+		for u, discount := range discounts {
+			discount = math.Min(roundPrice(discount), u.remaining)
+			if discount <= 0 {
+				continue
+			}
+			u.remaining -= discount
+			lineDiscounts[u.line] += discount
+			total += discount
+		}
+		if total == 0 {
+			continue
+		}
+
+		for line, discount := range lineDiscounts {
+			res.Lines[line].Discount = roundPrice(res.Lines[line].Discount + discount)
+			res.Lines[line].Promotions = append(res.Lines[line].Promotions, promotion.applied(discount))
+		}
+		res.Promotions = append(res.Promotions, promotion.applied(total))
+		res.Discount = roundPrice(res.Discount + total)
+
+		if !promotion.Stackable {
+			break
+		}
+	}
+
+	res.Subtotal = roundPrice(res.Subtotal)
+	res.Total = roundPrice(res.Subtotal - res.Discount)
+
+	return res
+}
+
+func (p Promotion) applied(discount float64) AppliedPromotion {
+	return AppliedPromotion{
+		PromotionID: p.ID,
+		Name:        p.Name,
+		Type:        p.Type,
+		Discount:    roundPrice(discount),
+	}
+}
+
+// discounts - скидка на единицы корзины по правилу акции
+func (p Promotion) discounts(units []*unit) map[*unit]float64 {
+	res := make(map[*unit]float64)
+
+	var eligible []*unit
+	for _, u := range units {
+		if u.remaining > 0 && p.hasProduct(u.productID) {
+			eligible = append(eligible, u)
+		}
+	}
+	// дорогие единицы первыми, скидки buy_x_get_y и nth_item достаются более дешевым
+	sort.SliceStable(eligible, func(i, j int) bool {
+		return eligible[i].remaining > eligible[j].remaining
+	})
+
+	switch p.Type {
+	case TypePercentage:
+		for _, u := range eligible {
+			res[u] = u.remaining * p.Percent / 100
+		}
+	case TypeFixed:
+		for _, u := range eligible {
+			res[u] = p.Amount
+		}
+	case TypeBuyXGetY:
+		percent := p.Percent
+		if percent == 0 {
+			percent = 100
+		}
+		free := len(eligible) / (p.BuyQuantity + p.GetQuantity) * p.GetQuantity
+		for _, u := range eligible[len(eligible)-free:] {
+			res[u] = u.remaining * percent / 100
+		}
+	case TypeNthItem:
+		for i, u := range eligible {
+			if (i+1)%p.NthItem == 0 {
+				res[u] = u.remaining * p.Percent / 100
+			}
+		}
+	case TypeBundlePrice:
+		byProduct := make(map[string][]*unit)
+		for _, u := range eligible {
+			byProduct[u.productID] = append(byProduct[u.productID], u)
+		}
+
+		bundles := -1
+		for _, productID := range p.ProductIDs {
+			if bundles == -1 || len(byProduct[productID]) < bundles {
+				bundles = len(byProduct[productID])
+			}
+		}
+
+		for i := 0; i < bundles; i++ {
+			var sum float64
+			for _, productID := range p.ProductIDs {
+				sum += byProduct[productID][i].remaining
+			}
+			if sum <= p.BundlePrice {
+				continue
+			}
+			// разница с ценой набора распределяется пропорционально цене единиц
+			for _, productID := range p.ProductIDs {
+				u := byProduct[productID][i]
+				res[u] = (sum - p.BundlePrice) * u.remaining / sum
+			}
+		}
+	}
+
+	return res
+}
+
+// FillOrderProducts записывает скидки акций в promos продуктов заказа как фиксированную скидку на единицу
+func FillOrderProducts(products []coreModels.OrderProduct, result Result) []coreModels.OrderProduct {
+	discounts := make(map[string]float64)
+	quantities := make(map[string]int)
+	for _, line := range result.Lines {
+		if line.Discount > 0 {
+			discounts[line.ProductID] += line.Discount
+		}
+	}
+	if len(discounts) == 0 {
+		return products
+	}
+
+	for _, product := range products {
+		quantities[product.ID] += product.Quantity
+	}
+
+	for i, product := range products {
+		discount, ok := discounts[product.ID]
+		if !ok || quantities[product.ID] == 0 {
+			continue
+		}
+		perUnit := int(math.Floor(discount / float64(quantities[product.ID])))
+		if perUnit == 0 {
+			continue
+		}
+		products[i].Promos = append(products[i].Promos, coreModels.Promo{
+			Type:     coreModels.PROMO_TYPE_FIXED,
+			Discount: perUnit,
+		})
+	}
+
+	return products
+}
+
+// FillOrderTotals пересчитывает итоги заказа по скидке акций: скидка уходит в partner_discounts_products,
+// total_customer_to_pay уменьшается на скидку, estimated_total_price остается суммой без скидок
+func FillOrderTotals(order coreModels.Order, result Result) coreModels.Order {
+	if result.Discount <= 0 {
+		return order
+	}
+
+	order.PartnerDiscountsProducts.Value = roundPrice(order.PartnerDiscountsProducts.Value + result.Discount)
+	order.PartnerDiscountsProducts.CurrencyCode = order.EstimatedTotalPrice.CurrencyCode
+	order.TotalCustomerToPay.Value = math.Max(roundPrice(order.EstimatedTotalPrice.Value-order.PartnerDiscountsProducts.Value), 0)
+	order.TotalCustomerToPay.CurrencyCode = order.EstimatedTotalPrice.CurrencyCode
+
+	return order
+}
+
+func roundPrice(value float64) float64 {
+	return math.Round(value*100) / 100
+}
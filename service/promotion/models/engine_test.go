@@ -0,0 +1,154 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	menuModels "github.com/kwaaka-team/orders-core/core/menu/models"
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+	scheduleModels "github.com/kwaaka-team/orders-core/service/availability_schedule/models"
+)
+
+func TestApply(t *testing.T) {
+	// среда 13:00
+	now := time.Date(2026, 3, 4, 13, 0, 0, 0, time.UTC)
+	cart := coreModels.Cart{
+		Items: []coreModels.CartProduct{
+			{ProductID: "burger", Name: "Burger", Quantity: 3, Price: 2000},
+			{ProductID: "fries", Name: "Fries", Quantity: 1, Price: 800},
+			{ProductID: "cola", Name: "Cola", Quantity: 2, Price: 500},
+		},
+	}
+	lunch := []scheduleModels.Window{{
+		StartTime: menuModels.TimeScheduler{Hour: 12},
+		EndTime:   menuModels.TimeScheduler{Hour: 15},
+	}}
+
+	tests := []struct {
+		name       string
+		promotions []Promotion
+		context    Context
+		discount   float64
+		lines      []float64
+	}{
+		{
+			name:       "buy 2 get 1 burger",
+			promotions: []Promotion{{ID: "b2g1", Type: TypeBuyXGetY, ProductIDs: []string{"burger"}, BuyQuantity: 2, GetQuantity: 1, IsActive: true}},
+			discount:   2000,
+			lines:      []float64{2000, 0, 0},
+		},
+		{
+			name:       "bundle burger fries cola for 2640",
+			promotions: []Promotion{{ID: "combo", Type: TypeBundlePrice, ProductIDs: []string{"burger", "fries", "cola"}, BundlePrice: 2640, IsActive: true}},
+			discount:   660,
+			lines:      []float64{400, 160, 100},
+		},
+		{
+			name:       "every second cola half price",
+			promotions: []Promotion{{ID: "nth", Type: TypeNthItem, ProductIDs: []string{"cola"}, NthItem: 2, Percent: 50, IsActive: true}},
+			discount:   250,
+			lines:      []float64{0, 0, 250},
+		},
+		{
+			name: "stackable promotions apply on discounted price",
+			promotions: []Promotion{
+				{ID: "happy", Type: TypePercentage, Percent: 10, HappyHours: lunch, Priority: 1, Stackable: true, IsActive: true},
+				{ID: "b2g1", Type: TypeBuyXGetY, ProductIDs: []string{"burger"}, BuyQuantity: 2, GetQuantity: 1, Priority: 2, Stackable: true, IsActive: true},
+			},
+			discount: 2000 + 580,
+			lines:    []float64{2400, 80, 100},
+		},
+		{
+			name: "exclusive promotion with higher priority blocks others",
+			promotions: []Promotion{
+				{ID: "happy", Type: TypePercentage, Percent: 10, Priority: 1, Stackable: true, IsActive: true},
+				{ID: "fixed", Type: TypeFixed, Amount: 100, ProductIDs: []string{"cola"}, Priority: 2, IsActive: true},
+			},
+			discount: 200,
+			lines:    []float64{0, 0, 200},
+		},
+		{
+			name: "exclusive promotion is skipped after stackable one",
+			promotions: []Promotion{
+				{ID: "happy", Type: TypePercentage, Percent: 10, Priority: 2, Stackable: true, IsActive: true},
+				{ID: "fixed", Type: TypeFixed, Amount: 100, ProductIDs: []string{"cola"}, Priority: 1, IsActive: true},
+			},
+			discount: 780,
+			lines:    []float64{600, 80, 100},
+		},
+		{
+			name: "conditions",
+			promotions: []Promotion{
+				{ID: "evening", Type: TypePercentage, Percent: 10, HappyHours: []scheduleModels.Window{{StartTime: menuModels.TimeScheduler{Hour: 18}, EndTime: menuModels.TimeScheduler{Hour: 2}}}, IsActive: true},
+				{ID: "first", Type: TypePercentage, Percent: 10, FirstOrderOnly: true, Stackable: true, IsActive: true},
+				{ID: "capped", Type: TypePercentage, Percent: 10, PerCustomerLimit: 1, Stackable: true, IsActive: true},
+				{ID: "admin", Type: TypePercentage, Percent: 10, DeliveryServices: []string{"kwaaka_admin"}, Stackable: true, IsActive: true},
+				{ID: "inactive", Type: TypePercentage, Percent: 10, Stackable: true},
+			},
+			context: Context{CustomerPhone: "+77001234567", Usages: map[string]int{"capped": 1}},
+			lines:   []float64{0, 0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.context
+			c.Now = now
+			if c.DeliveryService == "" {
+				c.DeliveryService = "qr_menu"
+			}
+
+			res := Apply(cart, tt.promotions, c)
+
+			if res.Discount != tt.discount || res.Subtotal != 7800 || res.Total != 7800-tt.discount {
+				t.Errorf("unexpected totals %+v", res)
+			}
+			for i, discount := range tt.lines {
+				if res.Lines[i].Discount != discount {
+					t.Errorf("line %s: expected discount %v, got %v", res.Lines[i].ProductID, discount, res.Lines[i].Discount)
+				}
+			}
+		})
+	}
+}
+
+func TestFillOrderProducts(t *testing.T) {
+	products := FillOrderProducts([]coreModels.OrderProduct{
+		{ID: "burger", Quantity: 2},
+		{ID: "burger", Quantity: 1},
+		{ID: "cola", Quantity: 1},
+	}, Result{Lines: []LineDiscount{{ProductID: "burger", Discount: 2000}, {ProductID: "cola", Discount: 0.5}}})
+
+	for i, expected := range []int{666, 666, 0} {
+		var discount int
+		for _, promo := range products[i].Promos {
+			discount += promo.Discount
+		}
+		if discount != expected {
+			t.Errorf("product %d: expected discount %d, got %d", i, expected, discount)
+		}
+	}
+}
+
+func TestFillOrderTotals(t *testing.T) {
+	order := coreModels.Order{
+		EstimatedTotalPrice: coreModels.Price{Value: 7000, CurrencyCode: "KZT"},
+		TotalCustomerToPay:  coreModels.Price{Value: 7000, CurrencyCode: "KZT"},
+	}
+
+	order = FillOrderTotals(order, Result{Subtotal: 7000, Discount: 1500.5, Total: 5499.5})
+
+	if order.EstimatedTotalPrice.Value != 7000 {
+		t.Errorf("expected estimated total 7000, got %v", order.EstimatedTotalPrice.Value)
+	}
+	if order.PartnerDiscountsProducts.Value != 1500.5 || order.PartnerDiscountsProducts.CurrencyCode != "KZT" {
+		t.Errorf("expected partner discount 1500.5 KZT, got %+v", order.PartnerDiscountsProducts)
+	}
+	if order.TotalCustomerToPay.Value != 5499.5 {
+		t.Errorf("expected total customer to pay 5499.5, got %v", order.TotalCustomerToPay.Value)
+	}
+
+	if unchanged := FillOrderTotals(order, Result{}); unchanged.TotalCustomerToPay.Value != 5499.5 {
+		t.Errorf("expected totals unchanged without discount, got %v", unchanged.TotalCustomerToPay.Value)
+	}
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"time"
+
+	scheduleModels "github.com/kwaaka-team/orders-core/service/availability_schedule/models"
+	"github.com/pkg/errors"
+)
+
+type Type string
+
+const (
+	// TypePercentage - скидка percent на каждую единицу продуктов акции
+	TypePercentage Type = "percentage"
+	// TypeFixed - скидка amount на каждую единицу продуктов акции
+	TypeFixed Type = "fixed"
+	// TypeBuyXGetY - за каждые buy_quantity единиц get_quantity самых дешевых со скидкой percent, 0 - бесплатно
+	TypeBuyXGetY Type = "buy_x_get_y"
+	// TypeBundlePrice - по одной единице каждого продукта акции за bundle_price
+	TypeBundlePrice Type = "bundle_price"
+	// TypeNthItem - каждая nth_item единица по убыванию цены со скидкой percent
+	TypeNthItem Type = "nth_item"
+)
+
+var (
+	ErrInvalidType     = errors.New("invalid promotion type")
+	ErrInvalidPercent  = errors.New("percent must be between 0 and 100")
+	ErrInvalidAmount   = errors.New("amount must be positive")
+	ErrInvalidQuantity = errors.New("invalid promotion quantity")
+	ErrInvalidBundle   = errors.New("bundle must contain at least two products and positive price")
+	ErrInvalidPeriod   = errors.New("valid from must be before valid until")
+)
+
+// Promotion - акция прямых каналов (qr_menu, kwaaka_admin) ресторана.
+// Акции применяются по убыванию priority, не stackable акция применяется только одна и только если до нее ничего не применилось
+type Promotion struct {
+	ID      string `bson:"_id,omitempty" json:"id"`
+	StoreID string `bson:"store_id" json:"store_id"`
+	Name    string `bson:"name" json:"name"`
+	Type    Type   `bson:"type" json:"type"`
+	// DeliveryServices - каналы акции, пустой список - все прямые каналы
+	DeliveryServices []string `bson:"delivery_services" json:"delivery_services"`
+	// ProductIDs - продукты акции, пустой список - все продукты корзины
+	ProductIDs  []string `bson:"product_ids" json:"product_ids"`
+	Percent     float64  `bson:"percent" json:"percent"`
+	Amount      float64  `bson:"amount" json:"amount"`
+	BuyQuantity int      `bson:"buy_quantity" json:"buy_quantity"`
+	GetQuantity int      `bson:"get_quantity" json:"get_quantity"`
+	NthItem     int      `bson:"nth_item" json:"nth_item"`
+	BundlePrice float64  `bson:"bundle_price" json:"bundle_price"`
+	// HappyHours - окна действия акции во времени ресторана, пустой список - весь день
+	HappyHours []scheduleModels.Window `bson:"happy_hours" json:"happy_hours"`
+	ValidFrom  *time.Time              `bson:"valid_from,omitempty" json:"valid_from,omitempty"`
+	ValidUntil *time.Time              `bson:"valid_until,omitempty" json:"valid_until,omitempty"`
+	// FirstOrderOnly - только для первого заказа клиента в ресторане
+	FirstOrderOnly bool `bson:"first_order_only" json:"first_order_only"`
+	// PerCustomerLimit - сколько раз клиент может воспользоваться акцией, 0 - без ограничений
+	PerCustomerLimit int       `bson:"per_customer_limit" json:"per_customer_limit"`
+	Priority         int       `bson:"priority" json:"priority"`
+	Stackable        bool      `bson:"stackable" json:"stackable"`
+	IsActive         bool      `bson:"is_active" json:"is_active"`
+	CreatedAt        time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+func (p Promotion) Validate() error {
+	switch p.Type {
+	case TypePercentage:
+		if p.Percent <= 0 || p.Percent > 100 {
+			return ErrInvalidPercent
+		}
+	case TypeFixed:
+		if p.Amount <= 0 {
+			return ErrInvalidAmount
+		}
+	case TypeBuyXGetY:
+		if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+			return errors.Wrap(ErrInvalidQuantity, "buy and get quantity must be positive")
+		}
+		if p.Percent < 0 || p.Percent > 100 {
+			return ErrInvalidPercent
+		}
+	case TypeBundlePrice:
+		if len(p.ProductIDs) < 2 || p.BundlePrice <= 0 {
+			return ErrInvalidBundle
+		}
+	case TypeNthItem:
+		if p.NthItem < 2 {
+			return errors.Wrap(ErrInvalidQuantity, "nth item must be at least 2")
+		}
+		if p.Percent <= 0 || p.Percent > 100 {
+			return ErrInvalidPercent
+		}
+	default:
+		return errors.Wrap(ErrInvalidType, string(p.Type))
+	}
+
+	if p.PerCustomerLimit < 0 {
+		return errors.Wrap(ErrInvalidQuantity, "per customer limit must not be negative")
+	}
+	if p.ValidFrom != nil && p.ValidUntil != nil && !p.ValidFrom.Before(*p.ValidUntil) {
+		return ErrInvalidPeriod
+	}
+
+	for _, w := range p.HappyHours {
+		if err := w.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Context - условия заказа для проверки акций, Now - во времени ресторана
+type Context struct {
+	DeliveryService string
+	Now             time.Time
+	CustomerPhone   string
+	IsFirstOrder    bool
+	// Usages - сколько раз клиент уже воспользовался акциями
+	Usages map[string]int
+}
+
+// IsApplicable - проверка условий акции без учета корзины.
+// Акции с ограничениями на клиента не применяются, если клиент неизвестен
+func (p Promotion) IsApplicable(c Context) bool {
+	if !p.IsActive {
+		return false
+	}
+
+	if len(p.DeliveryServices) != 0 && !contains(p.DeliveryServices, c.DeliveryService) {
+		return false
+	}
+
+	if p.ValidFrom != nil && c.Now.Before(*p.ValidFrom) {
+		return false
+	}
+	if p.ValidUntil != nil && !c.Now.Before(*p.ValidUntil) {
+		return false
+	}
+
+	if len(p.HappyHours) != 0 {
+		inWindow := false
+		for _, w := range p.HappyHours {
+			if w.Contains(c.Now) {
+				inWindow = true
+				break
+			}
+		}
+		if !inWindow {
+			return false
+		}
+	}
+
+	if (p.FirstOrderOnly || p.PerCustomerLimit > 0) && c.CustomerPhone == "" {
+		return false
+	}
+	if p.FirstOrderOnly && !c.IsFirstOrder {
+		return false
+	}
+	if p.PerCustomerLimit > 0 && c.Usages[p.ID] >= p.PerCustomerLimit {
+		return false
+	}
+
+	return true
+}
+
+func (p Promotion) hasProduct(productID string) bool {
+	return len(p.ProductIDs) == 0 || contains(p.ProductIDs, productID)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
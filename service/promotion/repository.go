@@ -0,0 +1,262 @@
+package promotion
+
+import (
+	"context"
+	"time"
+
+	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	"github.com/kwaaka-team/orders-core/service/promotion/models"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	collectionName      = "promotions"
+	usageCollectionName = "promotion_usages"
+)
+
+type Repository interface {
+	Create(ctx context.Context, promotion models.Promotion) (string, error)
+	Update(ctx context.Context, promotion models.Promotion) error
+	Delete(ctx context.Context, id string) error
+	GetByID(ctx context.Context, id string) (models.Promotion, error)
+	FindByStoreID(ctx context.Context, storeID string) ([]models.Promotion, error)
+	FindActiveByStoreID(ctx context.Context, storeID string) ([]models.Promotion, error)
+	GetUsages(ctx context.Context, customerPhone string, promotionIDs []string) (map[string]int, error)
+	ClaimUsages(ctx context.Context, customerPhone string, promotions []models.Promotion) error
+	ReleaseUsages(ctx context.Context, customerPhone string, promotionIDs []string) error
+}
+
+// ErrUsageLimitReached - клиент уже исчерпал лимит использований акции
+var ErrUsageLimitReached = errors.New("promotion usage limit reached")
+
+type MongoRepository struct {
+	collection      *mongo.Collection
+	usageCollection *mongo.Collection
+}
+
+func NewMongoRepository(db *mongo.Database) (*MongoRepository, error) {
+	return &MongoRepository{
+		collection:      db.Collection(collectionName),
+		usageCollection: db.Collection(usageCollectionName),
+	}, nil
+}
+
+func (m *MongoRepository) Create(ctx context.Context, promotion models.Promotion) (string, error) {
+	promotion.ID = ""
+	promotion.CreatedAt = time.Now().UTC()
+	promotion.UpdatedAt = promotion.CreatedAt
+
+	res, err := m.collection.InsertOne(ctx, promotion)
+	if err != nil {
+		return "", errorSwitch(err)
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", drivers.ErrInvalid
+	}
+
+	return oid.Hex(), nil
+}
+
+func (m *MongoRepository) Update(ctx context.Context, promotion models.Promotion) error {
+	oid, err := primitive.ObjectIDFromHex(promotion.ID)
+	if err != nil {
+		return err
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "name", Value: promotion.Name},
+			{Key: "type", Value: promotion.Type},
+			{Key: "delivery_services", Value: promotion.DeliveryServices},
+			{Key: "product_ids", Value: promotion.ProductIDs},
+			{Key: "percent", Value: promotion.Percent},
+			{Key: "amount", Value: promotion.Amount},
+			{Key: "buy_quantity", Value: promotion.BuyQuantity},
+			{Key: "get_quantity", Value: promotion.GetQuantity},
+			{Key: "nth_item", Value: promotion.NthItem},
+			{Key: "bundle_price", Value: promotion.BundlePrice},
+			{Key: "happy_hours", Value: promotion.HappyHours},
+			{Key: "valid_from", Value: promotion.ValidFrom},
+			{Key: "valid_until", Value: promotion.ValidUntil},
+			{Key: "first_order_only", Value: promotion.FirstOrderOnly},
+			{Key: "per_customer_limit", Value: promotion.PerCustomerLimit},
+			{Key: "priority", Value: promotion.Priority},
+			{Key: "stackable", Value: promotion.Stackable},
+			{Key: "is_active", Value: promotion.IsActive},
+			{Key: "updated_at", Value: time.Now().UTC()},
+		}},
+	}
+
+	res, err := m.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: oid}}, update)
+	if err != nil {
+		return errorSwitch(err)
+	}
+	if res.MatchedCount == 0 {
+		return drivers.ErrNotFound
+	}
+
+	return nil
+}
+
+func (m *MongoRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	res, err := m.collection.DeleteOne(ctx, bson.D{{Key: "_id", Value: oid}})
+	if err != nil {
+		return errorSwitch(err)
+	}
+	if res.DeletedCount == 0 {
+		return drivers.ErrNotFound
+	}
+
+	return nil
+}
+
+func (m *MongoRepository) GetByID(ctx context.Context, id string) (models.Promotion, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.Promotion{}, err
+	}
+
+	var promotion models.Promotion
+	if err = m.collection.FindOne(ctx, bson.D{{Key: "_id", Value: oid}}).Decode(&promotion); err != nil {
+		return models.Promotion{}, errorSwitch(err)
+	}
+
+	return promotion, nil
+}
+
+func (m *MongoRepository) FindByStoreID(ctx context.Context, storeID string) ([]models.Promotion, error) {
+	return m.find(ctx, bson.D{{Key: "store_id", Value: storeID}})
+}
+
+func (m *MongoRepository) FindActiveByStoreID(ctx context.Context, storeID string) ([]models.Promotion, error) {
+	return m.find(ctx, bson.D{
+		{Key: "store_id", Value: storeID},
+		{Key: "is_active", Value: true},
+	})
+}
+
+func (m *MongoRepository) GetUsages(ctx context.Context, customerPhone string, promotionIDs []string) (map[string]int, error) {
+	filter := bson.D{
+		{Key: "customer_phone", Value: customerPhone},
+		{Key: "promotion_id", Value: bson.D{{Key: "$in", Value: promotionIDs}}},
+	}
+
+	cur, err := m.usageCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, errorSwitch(err)
+	}
+	defer cur.Close(ctx)
+
+	usages := make(map[string]int, len(promotionIDs))
+	for cur.Next(ctx) {
+		var usage struct {
+			PromotionID string `bson:"promotion_id"`
+			Count       int    `bson:"count"`
+		}
+		if err = cur.Decode(&usage); err != nil {
+			log.Err(err).Msgf("error decoding into promotion usage model")
+			continue
+		}
+		usages[usage.PromotionID] = usage.Count
+	}
+
+	return usages, nil
+}
+
+// ClaimUsages атомарно увеличивает использования акций клиентом. Лимит проверяется в фильтре обновления:
+// если лимит исчерпан, upsert упирается в уникальный индекс (customer_phone, promotion_id), уже учтенные акции откатываются
+func (m *MongoRepository) ClaimUsages(ctx context.Context, customerPhone string, promotions []models.Promotion) error {
+	claimed := make([]string, 0, len(promotions))
+	for _, promotion := range promotions {
+		filter := bson.D{
+			{Key: "customer_phone", Value: customerPhone},
+			{Key: "promotion_id", Value: promotion.ID},
+		}
+		if promotion.PerCustomerLimit > 0 {
+			filter = append(filter, bson.E{Key: "count", Value: bson.D{{Key: "$lt", Value: promotion.PerCustomerLimit}}})
+		}
+		update := bson.D{
+			{Key: "$inc", Value: bson.D{{Key: "count", Value: 1}}},
+			{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now().UTC()}}},
+		}
+
+		if _, err := m.usageCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true)); err != nil {
+			if releaseErr := m.ReleaseUsages(ctx, customerPhone, claimed); releaseErr != nil {
+				log.Err(releaseErr).Msgf("release promotion usages of %s", customerPhone)
+			}
+			if err = errorSwitch(err); errors.Is(err, drivers.ErrAlreadyExist) {
+				return ErrUsageLimitReached
+			}
+			return err
+		}
+		claimed = append(claimed, promotion.ID)
+	}
+
+	return nil
+}
+
+// ReleaseUsages откатывает использования акций, например если заказ не сохранился
+func (m *MongoRepository) ReleaseUsages(ctx context.Context, customerPhone string, promotionIDs []string) error {
+	if len(promotionIDs) == 0 {
+		return nil
+	}
+
+	filter := bson.D{
+		{Key: "customer_phone", Value: customerPhone},
+		{Key: "promotion_id", Value: bson.D{{Key: "$in", Value: promotionIDs}}},
+		{Key: "count", Value: bson.D{{Key: "$gt", Value: 0}}},
+	}
+	update := bson.D{
+		{Key: "$inc", Value: bson.D{{Key: "count", Value: -1}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_at", Value: time.Now().UTC()}}},
+	}
+
+	if _, err := m.usageCollection.UpdateMany(ctx, filter, update); err != nil {
+		return errorSwitch(err)
+	}
+
+	return nil
+}
+
+func (m *MongoRepository) find(ctx context.Context, filter bson.D) ([]models.Promotion, error) {
+	cur, err := m.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, errorSwitch(err)
+	}
+	defer cur.Close(ctx)
+
+	promotions := make([]models.Promotion, 0, cur.RemainingBatchLength())
+	for cur.Next(ctx) {
+		var promotion models.Promotion
+		if err = cur.Decode(&promotion); err != nil {
+			log.Err(err).Msgf("error decoding into promotion model")
+			continue
+		}
+		promotions = append(promotions, promotion)
+	}
+
+	return promotions, nil
+}
+
+func errorSwitch(err error) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return drivers.ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return drivers.ErrAlreadyExist
+	default:
+		return err
+	}
+}
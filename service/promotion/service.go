@@ -0,0 +1,216 @@
+package promotion
+
+import (
+	"context"
+	"time"
+
+	coreModels "github.com/kwaaka-team/orders-core/core/models"
+	"github.com/kwaaka-team/orders-core/core/models/selector"
+	"github.com/kwaaka-team/orders-core/service/promotion/models"
+	storeServicePkg "github.com/kwaaka-team/orders-core/service/store"
+	"github.com/pkg/errors"
+)
+
+type Service interface {
+	Create(ctx context.Context, promotion models.Promotion) (string, error)
+	Update(ctx context.Context, promotion models.Promotion) error
+	Delete(ctx context.Context, id string) error
+	GetByID(ctx context.Context, id string) (models.Promotion, error)
+	GetByStoreID(ctx context.Context, storeID string) ([]models.Promotion, error)
+	Calculate(ctx context.Context, cart coreModels.Cart, deliveryService string) (models.Result, error)
+	ApplyToOrder(ctx context.Context, order coreModels.Order, cart coreModels.Cart) (coreModels.Order, models.Result, error)
+	ApplySnapshot(ctx context.Context, order coreModels.Order, cart coreModels.Cart, result models.Result) (coreModels.Order, error)
+	ReleaseUsages(ctx context.Context, cart coreModels.Cart, result models.Result) error
+}
+
+// claimAttempts - повторный расчет, если между расчетом и учетом использований клиент исчерпал лимит акции
+const claimAttempts = 2
+
+// OrderCounter - количество заказов клиента для акций на первый заказ, реализуется репозиторием заказов
+type OrderCounter interface {
+	GetOrderNumber(ctx context.Context, query selector.Order) (int64, error)
+}
+
+type ServiceImpl struct {
+	repo         Repository
+	storeService storeServicePkg.Service
+	orderCounter OrderCounter
+}
+
+func NewService(repo Repository, storeService storeServicePkg.Service, orderCounter OrderCounter) (*ServiceImpl, error) {
+	if repo == nil {
+		return nil, errors.New("promotion repository is nil")
+	}
+	if storeService == nil {
+		return nil, errors.New("store service is nil")
+	}
+	if orderCounter == nil {
+		return nil, errors.New("order counter is nil")
+	}
+
+	return &ServiceImpl{
+		repo:         repo,
+		storeService: storeService,
+		orderCounter: orderCounter,
+	}, nil
+}
+
+func (s *ServiceImpl) Create(ctx context.Context, promotion models.Promotion) (string, error) {
+	if err := promotion.Validate(); err != nil {
+		return "", err
+	}
+
+	if _, err := s.storeService.GetByID(ctx, promotion.StoreID); err != nil {
+		return "", err
+	}
+
+	return s.repo.Create(ctx, promotion)
+}
+
+func (s *ServiceImpl) Update(ctx context.Context, promotion models.Promotion) error {
+	if err := promotion.Validate(); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, promotion)
+}
+
+func (s *ServiceImpl) Delete(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *ServiceImpl) GetByID(ctx context.Context, id string) (models.Promotion, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *ServiceImpl) GetByStoreID(ctx context.Context, storeID string) ([]models.Promotion, error) {
+	return s.repo.FindByStoreID(ctx, storeID)
+}
+
+// Calculate - скидки акций ресторана на корзину для канала deliveryService
+func (s *ServiceImpl) Calculate(ctx context.Context, cart coreModels.Cart, deliveryService string) (models.Result, error) {
+	result, _, err := s.calculate(ctx, cart, deliveryService)
+	return result, err
+}
+
+// calculate возвращает также примененные акции, использования учитываются по телефону в виде models.NormalizePhone
+func (s *ServiceImpl) calculate(ctx context.Context, cart coreModels.Cart, deliveryService string) (models.Result, []models.Promotion, error) {
+	store, err := s.storeService.GetByID(ctx, cart.RestaurantID)
+	if err != nil {
+		return models.Result{}, nil, err
+	}
+
+	promotions, err := s.repo.FindActiveByStoreID(ctx, store.ID)
+	if err != nil {
+		return models.Result{}, nil, err
+	}
+
+	promotionContext := models.Context{
+		DeliveryService: deliveryService,
+		Now:             time.Now().In(store.Settings.TimeZone.Location()),
+		CustomerPhone:   coreModels.NormalizePhone(cart.Customer.PhoneNumber),
+	}
+
+	if promotionContext.CustomerPhone != "" && len(promotions) != 0 {
+		ordersCount, err := s.orderCounter.GetOrderNumber(ctx, selector.EmptyOrderSearch().
+			SetCustomerPhones(coreModels.PhoneVariants(promotionContext.CustomerPhone)).
+			SetRestaurants([]string{store.ID}))
+		if err != nil {
+			return models.Result{}, nil, err
+		}
+		promotionContext.IsFirstOrder = ordersCount == 0
+
+		ids := make([]string, 0, len(promotions))
+		for _, promotion := range promotions {
+			ids = append(ids, promotion.ID)
+		}
+		if promotionContext.Usages, err = s.repo.GetUsages(ctx, promotionContext.CustomerPhone, ids); err != nil {
+			return models.Result{}, nil, err
+		}
+	}
+
+	result := models.Apply(cart, promotions, promotionContext)
+
+	applied := make([]models.Promotion, 0, len(result.Promotions))
+	for _, promotion := range promotions {
+		for _, a := range result.Promotions {
+			if a.PromotionID == promotion.ID {
+				applied = append(applied, promotion)
+				break
+			}
+		}
+	}
+
+	return result, applied, nil
+}
+
+// ApplyToOrder записывает скидки акций в promos продуктов и итоги заказа и атомарно учитывает использование акций клиентом.
+// Вызывается до сохранения заказа, иначе заказ будет считаться не первым. Если заказ не сохранился, использования откатываются через ReleaseUsages
+func (s *ServiceImpl) ApplyToOrder(ctx context.Context, order coreModels.Order, cart coreModels.Cart) (coreModels.Order, models.Result, error) {
+	customerPhone := coreModels.NormalizePhone(cart.Customer.PhoneNumber)
+
+	for attempt := 0; attempt < claimAttempts; attempt++ {
+		result, applied, err := s.calculate(ctx, cart, order.DeliveryService)
+		if err != nil {
+			return order, models.Result{}, err
+		}
+		if len(result.Promotions) == 0 {
+			return order, result, nil
+		}
+
+		if customerPhone != "" {
+			err = s.repo.ClaimUsages(ctx, customerPhone, applied)
+			if errors.Is(err, ErrUsageLimitReached) {
+				continue
+			}
+			if err != nil {
+				return order, models.Result{}, err
+			}
+		}
+
+		order.Products = models.FillOrderProducts(order.Products, result)
+		order = models.FillOrderTotals(order, result)
+
+		return order, result, nil
+	}
+
+	return order, models.Result{}, ErrUsageLimitReached
+}
+
+// ApplySnapshot записывает в заказ скидки, по которым клиент уже оплатил корзину, без пересчета.
+// Использования учитываются без проверки per_customer_limit: лимит проверен при оплате, а скидка уже оплачена
+func (s *ServiceImpl) ApplySnapshot(ctx context.Context, order coreModels.Order, cart coreModels.Cart, result models.Result) (coreModels.Order, error) {
+	if len(result.Promotions) == 0 {
+		return order, nil
+	}
+
+	if customerPhone := coreModels.NormalizePhone(cart.Customer.PhoneNumber); customerPhone != "" {
+		claimed := make([]models.Promotion, 0, len(result.Promotions))
+		for _, promotion := range result.Promotions {
+			claimed = append(claimed, models.Promotion{ID: promotion.PromotionID})
+		}
+		if err := s.repo.ClaimUsages(ctx, customerPhone, claimed); err != nil {
+			return order, err
+		}
+	}
+
+	order.Products = models.FillOrderProducts(order.Products, result)
+	order = models.FillOrderTotals(order, result)
+
+	return order, nil
+}
+
+// ReleaseUsages откатывает использования акций, учтенные ApplyToOrder и ApplySnapshot
+func (s *ServiceImpl) ReleaseUsages(ctx context.Context, cart coreModels.Cart, result models.Result) error {
+	customerPhone := coreModels.NormalizePhone(cart.Customer.PhoneNumber)
+	if customerPhone == "" || len(result.Promotions) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(result.Promotions))
+	for _, promotion := range result.Promotions {
+		ids = append(ids, promotion.PromotionID)
+	}
+
+	return s.repo.ReleaseUsages(ctx, customerPhone, ids)
+}
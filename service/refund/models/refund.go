@@ -3,11 +3,45 @@ package models
 import "time"
 
 type Refund struct {
-	ID            string    `json:"_id" bson:"_id"`
-	Amount        int       `json:"amount" bson:"amount"`
-	Reason        string    `json:"reason" bson:"reason"`
-	OrderID       string    `json:"order_id" bson:"order_id"`
-	PaymentID     string    `json:"payment_id" bson:"payment_id"`
-	PaymentSystem string    `json:"payment_system" bson:"payment_system"`
-	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+	ID            string       `json:"_id" bson:"_id"`
+	Amount        int          `json:"amount" bson:"amount"`
+	Reason        string       `json:"reason" bson:"reason"`
+	OrderID       string       `json:"order_id" bson:"order_id"`
+	PaymentID     string       `json:"payment_id" bson:"payment_id"`
+	PaymentSystem string       `json:"payment_system" bson:"payment_system"`
+	Lines         []RefundLine `json:"lines,omitempty" bson:"lines,omitempty"`
+	CreatedAt     time.Time    `json:"created_at" bson:"created_at"`
+}
+
+// RefundLine - возврат по позиции заказа, LineIndex - индекс позиции в order.products
+type RefundLine struct {
+	LineIndex int    `json:"line_index" bson:"line_index"`
+	ProductID string `json:"product_id" bson:"product_id"`
+	Name      string `json:"name" bson:"name"`
+	Quantity  int    `json:"quantity" bson:"quantity"`
+	// Amount - сумма возврата клиенту с долей сервисного сбора
+	Amount int `json:"amount" bson:"amount"`
+	// ItemsAmount - доля Amount за позиции без сервисного сбора, вычитается из стоимости заказа в отчетах
+	ItemsAmount int `json:"items_amount" bson:"items_amount"`
+}
+
+type RefundLineRequest struct {
+	LineIndex int `json:"line_index"`
+	Quantity  int `json:"quantity"`
+}
+
+func (r Refund) LinesAmount() int {
+	var amount int
+	for _, line := range r.Lines {
+		amount += line.Amount
+	}
+	return amount
+}
+
+func (r Refund) LinesItemsAmount() int {
+	var amount int
+	for _, line := range r.Lines {
+		amount += line.ItemsAmount
+	}
+	return amount
 }
@@ -6,30 +6,46 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"time"
 )
 
-const collectionName = "refunds"
+const (
+	collectionName     = "refunds"
+	lockCollectionName = "refund_locks"
+)
 
 type Repository interface {
 	InsertRefundInfo(ctx context.Context, refund refundModels.Refund) error
-	GetRefund(ctx context.Context, orderID string) (refundModels.Refund, error)
+	GetRefundsByOrderID(ctx context.Context, orderID string) ([]refundModels.Refund, error)
+	GetRefundsByOrderIDs(ctx context.Context, orderIDs []string) ([]refundModels.Refund, error)
+	// Lock returns false if refund of order is already in progress and its lease is not expired
+	Lock(ctx context.Context, orderID string, now time.Time, lease time.Duration) (bool, error)
+	Unlock(ctx context.Context, orderID string) error
 }
 
 type MongoRepository struct {
-	collection *mongo.Collection
+	collection     *mongo.Collection
+	lockCollection *mongo.Collection
 }
 
 func NewMongoRepository(db *mongo.Database) (*MongoRepository, error) {
-	return &MongoRepository{collection: db.Collection(collectionName)}, nil
+	return &MongoRepository{
+		collection:     db.Collection(collectionName),
+		lockCollection: db.Collection(lockCollectionName),
+	}, nil
 }
 
 func (m *MongoRepository) InsertRefundInfo(ctx context.Context, request refundModels.Refund) error {
 	request.CreatedAt = time.Now().UTC()
 
-	oid, err := primitive.ObjectIDFromHex(request.ID)
-	if err != nil {
-		return err
+	// частичных возвратов по позициям может быть несколько на один платеж
+	oid := primitive.NewObjectID()
+	if request.ID != "" {
+		var err error
+		if oid, err = primitive.ObjectIDFromHex(request.ID); err != nil {
+			return err
+		}
 	}
 	create := bson.D{
 		{Key: "_id", Value: oid},
@@ -40,7 +56,10 @@ func (m *MongoRepository) InsertRefundInfo(ctx context.Context, request refundMo
 		{Key: "payment_system", Value: request.PaymentSystem},
 		{Key: "created_at", Value: request.CreatedAt},
 	}
-	_, err = m.collection.InsertOne(ctx, create)
+	if len(request.Lines) != 0 {
+		create = append(create, bson.E{Key: "lines", Value: request.Lines})
+	}
+	_, err := m.collection.InsertOne(ctx, create)
 	if err != nil {
 		return err
 	}
@@ -48,15 +67,68 @@ func (m *MongoRepository) InsertRefundInfo(ctx context.Context, request refundMo
 	return nil
 }
 
-func (m *MongoRepository) GetRefund(ctx context.Context, orderID string) (refundModels.Refund, error) {
+func (m *MongoRepository) GetRefundsByOrderID(ctx context.Context, orderID string) ([]refundModels.Refund, error) {
 	filter := bson.D{
 		{Key: "order_id", Value: orderID},
 	}
-	var result refundModels.Refund
 
-	err := m.collection.FindOne(ctx, filter).Decode(&result)
+	cur, err := m.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []refundModels.Refund
+	if err = cur.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (m *MongoRepository) GetRefundsByOrderIDs(ctx context.Context, orderIDs []string) ([]refundModels.Refund, error) {
+	if len(orderIDs) == 0 {
+		return nil, nil
+	}
+
+	filter := bson.D{
+		{Key: "order_id", Value: bson.D{{Key: "$in", Value: orderIDs}}},
+		{Key: "lines", Value: bson.D{{Key: "$exists", Value: true}}},
+	}
+
+	cur, err := m.collection.Find(ctx, filter)
 	if err != nil {
-		return refundModels.Refund{}, err
+		return nil, err
+	}
+
+	var result []refundModels.Refund
+	if err = cur.All(ctx, &result); err != nil {
+		return nil, err
 	}
 	return result, nil
 }
+
+// Lock - блокировка возвратов заказа: документ с _id заказа вставляется или перехватывается только после истечения lease,
+// при активной блокировке upsert получает duplicate key
+func (m *MongoRepository) Lock(ctx context.Context, orderID string, now time.Time, lease time.Duration) (bool, error) {
+	filter := bson.D{
+		{Key: "_id", Value: orderID},
+		{Key: "locked_until", Value: bson.D{{Key: "$lte", Value: now}}},
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "locked_until", Value: now.Add(lease)}}},
+	}
+
+	_, err := m.lockCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	switch {
+	case mongo.IsDuplicateKeyError(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (m *MongoRepository) Unlock(ctx context.Context, orderID string) error {
+	_, err := m.lockCollection.DeleteOne(ctx, bson.D{{Key: "_id", Value: orderID}})
+	return err
+}
@@ -0,0 +1,124 @@
+package review
+
+import (
+	"context"
+	"time"
+
+	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	"github.com/kwaaka-team/orders-core/core/models"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const collectionName = "order_reviews"
+
+type Repository interface {
+	Create(ctx context.Context, review models.OrderReview) error
+	GetByToken(ctx context.Context, token string) (models.OrderReview, error)
+	SetChannel(ctx context.Context, orderID, channel string) error
+	Rate(ctx context.Context, review models.OrderReview) error
+	FindRated(ctx context.Context, restaurantIDs []string, from, to time.Time) ([]models.OrderReview, error)
+}
+
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(db *mongo.Database) (*MongoRepository, error) {
+	return &MongoRepository{
+		collection: db.Collection(collectionName),
+	}, nil
+}
+
+// Create сохраняет запрос отзыва, повторный запрос по тому же заказу возвращает drivers.ErrAlreadyExist
+func (m *MongoRepository) Create(ctx context.Context, review models.OrderReview) error {
+	if _, err := m.collection.InsertOne(ctx, review); err != nil {
+		return errorSwitch(err)
+	}
+
+	return nil
+}
+
+func (m *MongoRepository) GetByToken(ctx context.Context, token string) (models.OrderReview, error) {
+	var review models.OrderReview
+	if err := m.collection.FindOne(ctx, bson.D{{Key: "token", Value: token}}).Decode(&review); err != nil {
+		return models.OrderReview{}, errorSwitch(err)
+	}
+
+	return review, nil
+}
+
+func (m *MongoRepository) SetChannel(ctx context.Context, orderID, channel string) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "channel", Value: channel}}},
+	}
+
+	if _, err := m.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: orderID}}, update); err != nil {
+		return errorSwitch(err)
+	}
+
+	return nil
+}
+
+// Rate записывает оценку, если отзыв еще не оценен, иначе models.ErrOrderReviewAlreadyRated
+func (m *MongoRepository) Rate(ctx context.Context, review models.OrderReview) error {
+	filter := bson.D{
+		{Key: "_id", Value: review.OrderID},
+		{Key: "status", Value: models.OrderReviewRequested},
+	}
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: review.Status},
+			{Key: "rating", Value: review.Rating},
+			{Key: "comment", Value: review.Comment},
+			{Key: "products", Value: review.Products},
+			{Key: "rated_at", Value: review.RatedAt},
+		}},
+	}
+
+	res, err := m.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return errorSwitch(err)
+	}
+	if res.MatchedCount == 0 {
+		return models.ErrOrderReviewAlreadyRated
+	}
+
+	return nil
+}
+
+func (m *MongoRepository) FindRated(ctx context.Context, restaurantIDs []string, from, to time.Time) ([]models.OrderReview, error) {
+	filter := bson.D{
+		{Key: "restaurant_id", Value: bson.D{{Key: "$in", Value: restaurantIDs}}},
+		{Key: "status", Value: models.OrderReviewRated},
+		{Key: "rated_at", Value: bson.D{
+			{Key: "$gte", Value: from},
+			{Key: "$lt", Value: to},
+		}},
+	}
+
+	cursor, err := m.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, errorSwitch(err)
+	}
+
+	reviews := make([]models.OrderReview, 0)
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+func errorSwitch(err error) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return drivers.ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return drivers.ErrAlreadyExist
+	default:
+		return err
+	}
+}
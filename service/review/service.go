@@ -0,0 +1,213 @@
+package review
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kwaaka-team/orders-core/core/managers/telegram"
+	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	menuModels "github.com/kwaaka-team/orders-core/core/menu/models"
+	"github.com/kwaaka-team/orders-core/core/models"
+	selector2 "github.com/kwaaka-team/orders-core/core/storecore/managers/selector"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/order"
+	storeServicePkg "github.com/kwaaka-team/orders-core/service/store"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// CustomerNotifier - отправка сообщений клиенту в whatsapp, реализуется whatsapp.Service
+type CustomerNotifier interface {
+	SendMessage(ctx context.Context, to, message, storeId string) error
+}
+
+// SmsSender - отправка sms клиенту, если whatsapp недоступен, реализуется sms.Service
+type SmsSender interface {
+	SendMessage(ctx context.Context, phoneNumber string, message string) error
+}
+
+// OrderReviewer - запись оценки в заказ, реализуется репозиторием заказов
+type OrderReviewer interface {
+	FindOrderByID(ctx context.Context, id string) (models.Order, error)
+	SetOrderReview(ctx context.Context, orderID string, review models.Review) error
+}
+
+type ServiceImpl struct {
+	repo             Repository
+	orderReviewer    OrderReviewer
+	storeService     storeServicePkg.Service
+	customerNotifier CustomerNotifier
+	smsSender        SmsSender
+	telegramService  order.TelegramService
+}
+
+func NewService(repo Repository, orderReviewer OrderReviewer, storeService storeServicePkg.Service, customerNotifier CustomerNotifier, smsSender SmsSender, telegramService order.TelegramService) (*ServiceImpl, error) {
+	if repo == nil {
+		return nil, errors.New("review repository is nil")
+	}
+	if orderReviewer == nil {
+		return nil, errors.New("order reviewer is nil")
+	}
+	if storeService == nil {
+		return nil, errors.New("store service is nil")
+	}
+	if customerNotifier == nil {
+		return nil, errors.New("customer notifier is nil")
+	}
+	if smsSender == nil {
+		return nil, errors.New("sms sender is nil")
+	}
+	if telegramService == nil {
+		return nil, errors.New("telegram service is nil")
+	}
+
+	return &ServiceImpl{
+		repo:             repo,
+		orderReviewer:    orderReviewer,
+		storeService:     storeService,
+		customerNotifier: customerNotifier,
+		smsSender:        smsSender,
+		telegramService:  telegramService,
+	}, nil
+}
+
+// RequestReview отправляет клиенту заказа qr menu или kwaaka admin ссылку на отзыв в whatsapp, при ошибке - в sms.
+// Ссылка отправляется один раз на заказ и только ресторанам с qr_menu.send_review_request
+func (s *ServiceImpl) RequestReview(ctx context.Context, o models.Order, store storeModels.Store) error {
+	if o.IsTestOrder || !store.QRMenu.SendReviewRequest || store.QRMenu.URL == "" {
+		return nil
+	}
+	if o.DeliveryService != models.QRMENU.String() && o.DeliveryService != models.KWAAKA_ADMIN.String() {
+		return nil
+	}
+
+	review := models.NewOrderReview(o, uuid.New().String(), store.Name, store.RestaurantGroupID, time.Now().UTC())
+	if review.CustomerPhone == "" || review.CustomerPhone == models.Default3plCustomerPhone {
+		return nil
+	}
+
+	err := s.repo.Create(ctx, review)
+	switch {
+	case errors.Is(err, drivers.ErrAlreadyExist):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	msg := review.RequestMessage(store.QRMenu.URL)
+
+	channel := models.ReviewChannelWhatsapp
+	if err := s.customerNotifier.SendMessage(ctx, o.Customer.PhoneNumber, url.QueryEscape(msg), store.ID); err != nil {
+		log.Err(err).Msgf("send review request in whatsapp error, order id: %s", o.ID)
+
+		channel = models.ReviewChannelSms
+		if err := s.smsSender.SendMessage(ctx, review.CustomerPhone, msg); err != nil {
+			return errors.Wrapf(err, "send review request in sms, order id: %s", o.ID)
+		}
+	}
+
+	return s.repo.SetChannel(ctx, review.OrderID, channel)
+}
+
+func (s *ServiceImpl) GetReview(ctx context.Context, token string) (models.OrderReview, error) {
+	return s.repo.GetByToken(ctx, token)
+}
+
+// SubmitReview сохраняет оценку клиента в отзыв и заказ. Низкая оценка отправляется в telegram чат ресторана,
+// для высокой возвращается ссылка на отзыв в 2ГИС
+func (s *ServiceImpl) SubmitReview(ctx context.Context, token string, req models.SubmitOrderReviewRequest) (models.SubmitOrderReviewResponse, error) {
+	review, err := s.repo.GetByToken(ctx, token)
+	if err != nil {
+		return models.SubmitOrderReviewResponse{}, err
+	}
+
+	if err := review.Rate(req, time.Now().UTC()); err != nil {
+		return models.SubmitOrderReviewResponse{}, err
+	}
+
+	if err := s.repo.Rate(ctx, review); err != nil {
+		return models.SubmitOrderReviewResponse{}, err
+	}
+
+	if err := s.orderReviewer.SetOrderReview(ctx, review.OrderID, models.Review{
+		ReviewContent: review.Comment,
+		Rating:        float32(review.Rating),
+	}); err != nil {
+		log.Err(err).Msgf("set order review error, order id: %s", review.OrderID)
+	}
+
+	res := models.SubmitOrderReviewResponse{Review: review}
+
+	switch {
+	case review.IsLowRating():
+		if err := s.notifyLowRating(ctx, review); err != nil {
+			log.Err(err).Msgf("notify low rating error, order id: %s", review.OrderID)
+		}
+	case review.IsHighRating():
+		link, err := s.storeService.GetTwoGisReviewLink(ctx, review.RestaurantID)
+		if err != nil {
+			log.Info().Msgf("no 2gis review link for store %s: %s", review.RestaurantID, err)
+			break
+		}
+		res.RedirectURL = link
+	}
+
+	return res, nil
+}
+
+func (s *ServiceImpl) notifyLowRating(ctx context.Context, review models.OrderReview) error {
+	o, err := s.orderReviewer.FindOrderByID(ctx, review.OrderID)
+	if err != nil {
+		return err
+	}
+
+	store, err := s.storeService.GetByID(ctx, review.RestaurantID)
+	if err != nil {
+		return err
+	}
+
+	msg := telegram.ConstructLowRatingReviewMessage(o, store, review)
+
+	return s.telegramService.SendMessageToQueue(telegram.LowRatingReview, o, store, "", msg, "", menuModels.Product{})
+}
+
+// Ratings - оценки ресторанов, продуктов и сервисов доставки по отзывам за период
+func (s *ServiceImpl) Ratings(ctx context.Context, query models.ReviewRatingsRequest) (models.ReviewRatings, error) {
+	if !query.StartDate.Before(query.EndDate) {
+		return models.ReviewRatings{}, errors.New("start date must be before end date")
+	}
+
+	var (
+		stores []storeModels.Store
+		err    error
+	)
+	switch {
+	case len(query.RestaurantIDs) != 0:
+		stores, err = s.storeService.GetStoresBySelectorFilter(ctx, selector2.NewEmptyStoreSearch().SetStoreIDs(query.RestaurantIDs))
+	case query.RestaurantGroupID != "":
+		stores, err = s.storeService.GetRestaurantsByGroupId(ctx, selector2.Pagination{}, query.RestaurantGroupID)
+	default:
+		return models.ReviewRatings{}, errors.New("restaurant ids or restaurant group id is required")
+	}
+	if err != nil {
+		return models.ReviewRatings{}, err
+	}
+
+	storeIDs := make([]string, 0, len(stores))
+	for _, st := range stores {
+		storeIDs = append(storeIDs, st.ID)
+	}
+
+	reviews, err := s.repo.FindRated(ctx, storeIDs, query.StartDate, query.EndDate)
+	if err != nil {
+		return models.ReviewRatings{}, err
+	}
+
+	ratings := models.BuildReviewRatings(reviews)
+	ratings.StartDate = query.StartDate
+	ratings.EndDate = query.EndDate
+
+	return ratings, nil
+}
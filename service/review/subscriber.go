@@ -0,0 +1,30 @@
+package review
+
+import (
+	"context"
+
+	"github.com/kwaaka-team/orders-core/core/models"
+	coreStoreModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/order"
+	"github.com/pkg/errors"
+)
+
+// RequestSubscriber отправляет клиенту ссылку на отзыв, когда заказ доставлен или закрыт
+type RequestSubscriber struct {
+	reviewService order.ReviewService
+}
+
+func NewRequestSubscriber(reviewService order.ReviewService) (*RequestSubscriber, error) {
+	if reviewService == nil {
+		return nil, errors.New("review service is nil")
+	}
+	return &RequestSubscriber{reviewService: reviewService}, nil
+}
+
+func (s *RequestSubscriber) SendOrder(ctx context.Context, o models.Order, store coreStoreModels.Store, posStatus models.PosStatus) error {
+	if posStatus != models.DELIVERED && posStatus != models.CLOSED {
+		return nil
+	}
+
+	return s.reviewService.RequestReview(ctx, o, store)
+}
@@ -0,0 +1,135 @@
+package settlement
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/jung-kurt/gofpdf/v2"
+	"github.com/kwaaka-team/orders-core/service/settlement/models"
+	"github.com/tealeg/xlsx"
+)
+
+const (
+	xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	pdfContentType  = "application/pdf"
+	dateLayout      = "02.01.2006"
+)
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func formatPeriod(statement models.Statement) string {
+	return fmt.Sprintf("%s - %s", statement.PeriodFrom.Format(dateLayout), statement.PeriodTo.AddDate(0, 0, -1).Format(dateLayout))
+}
+
+// toXlsx - детализация сверки построчно
+func toXlsx(statement models.Statement) ([]byte, error) {
+	file := xlsx.NewFile()
+	sheet, err := file.AddSheet("Settlement")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, values := range [][]string{
+		{"Юр. лицо", statement.LegalEntityName},
+		{"Период", formatPeriod(statement)},
+		{"Начальный баланс", formatAmount(statement.OpeningBalance)},
+		{"Оплаты с прошлой сверки", formatAmount(statement.Payments)},
+		{"Конечный баланс", formatAmount(statement.ClosingBalance)},
+		{"К выплате ресторану", formatAmount(statement.DueToRestaurant)},
+		{"К оплате Kwaaka", formatAmount(statement.DueToKwaaka)},
+		{},
+		{"Дата", "Ресторан", "ID заказа", "Тип", "Платежная система", "ID доставки", "Описание", "Сумма"},
+	} {
+		row := sheet.AddRow()
+		for _, value := range values {
+			row.AddCell().Value = value
+		}
+	}
+
+	for _, line := range statement.Lines {
+		row := sheet.AddRow()
+		row.AddCell().Value = line.Date.Format("02.01.2006 15:04")
+		row.AddCell().Value = line.RestaurantName
+		row.AddCell().Value = line.OrderID
+		row.AddCell().Value = line.Type
+		row.AddCell().Value = line.PaymentSystem
+		row.AddCell().Value = line.DeliveryOrderID
+		row.AddCell().Value = line.Description
+		row.AddCell().Value = formatAmount(line.Amount)
+	}
+
+	var b bytes.Buffer
+	if err = file.Write(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// toPdf - акт сверки с итогами, детализация по строкам в xlsx
+func toPdf(statement models.Statement) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+
+	fontBytes, err := os.ReadFile("fonts/ArialCyrRegular.ttf")
+	if err != nil {
+		return nil, err
+	}
+
+	boldFontBytes, err := os.ReadFile("fonts/kzArialBold.ttf")
+	if err != nil {
+		return nil, err
+	}
+
+	pdf.AddUTF8FontFromBytes("Arial", "", fontBytes)
+	pdf.AddUTF8FontFromBytes("Arial", "B", boldFontBytes)
+
+	pdf.AddPage()
+	pdf.SetMargins(10, 20, 20)
+
+	pdf.SetFont("Arial", "B", 14)
+	pdf.Cell(0, 6, fmt.Sprintf("Акт сверки взаиморасчетов за период %s", formatPeriod(statement)))
+	pdf.Ln(10)
+
+	pdf.SetFont("Arial", "", 10)
+	pdf.Cell(0, 5, fmt.Sprintf("Юр. лицо: %s", statement.LegalEntityName))
+	pdf.Ln(5)
+	pdf.Cell(0, 5, fmt.Sprintf("Количество заказов: %d", statement.Totals.OrdersCount))
+	pdf.Ln(5)
+	if statement.Totals.Unreconciled > 0 {
+		pdf.Cell(0, 5, fmt.Sprintf("Не сверено заказов: %d, см. детализацию", statement.Totals.Unreconciled))
+		pdf.Ln(5)
+	}
+	pdf.Ln(3)
+
+	for _, row := range [][2]string{
+		{"Начальный баланс", formatAmount(statement.OpeningBalance)},
+		{"В т.ч. оплаты с прошлой сверки", formatAmount(statement.Payments)},
+		{"Оплаты заказов через Kwaaka", formatAmount(statement.Totals.Orders)},
+		{"Возвраты", formatAmount(statement.Totals.Refunds)},
+		{"Комиссии", formatAmount(statement.Totals.ServiceFees)},
+		{"Доставки", formatAmount(statement.Totals.Deliveries)},
+		{"Сервисный сбор Kwaaka за доставки", formatAmount(statement.Totals.KwaakaCharges)},
+		{"Конечный баланс", formatAmount(statement.ClosingBalance)},
+	} {
+		pdf.CellFormat(120, 7, row[0], "1", 0, "L", false, 0, "")
+		pdf.CellFormat(60, 7, row[1], "1", 1, "R", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	pdf.SetFont("Arial", "B", 11)
+	pdf.Cell(0, 5, fmt.Sprintf("К выплате ресторану: %s", formatAmount(statement.DueToRestaurant)))
+	pdf.Ln(6)
+	pdf.Cell(0, 5, fmt.Sprintf("К оплате Kwaaka: %s", formatAmount(statement.DueToKwaaka)))
+	pdf.Ln(6)
+
+	var buf bytes.Buffer
+	if err = pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,166 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	LineTypeOrder        = "order"
+	LineTypeRefund       = "refund"
+	LineTypeServiceFee   = "service_fee"
+	LineTypeDelivery     = "delivery"
+	LineTypeKwaakaCharge = "kwaaka_charge"
+	LineTypeUnreconciled = "unreconciled"
+)
+
+// статусы сверки: draft - сохранена без документов, documents_failed - выгрузка документов или счет не удались, ready - документы готовы
+const (
+	StatusDraft           = "draft"
+	StatusDocumentsFailed = "documents_failed"
+	StatusReady           = "ready"
+)
+
+var (
+	ErrInvalidPeriod = errors.New("settlement period is invalid")
+	ErrNoStores      = errors.New("legal entity has no stores")
+)
+
+// Line - строка сверки. Amount > 0 - Kwaaka должна ресторану, Amount < 0 - ресторан должен Kwaaka
+type Line struct {
+	Type            string    `bson:"type" json:"type"`
+	OrderID         string    `bson:"order_id" json:"order_id"`
+	RestaurantID    string    `bson:"restaurant_id" json:"restaurant_id"`
+	RestaurantName  string    `bson:"restaurant_name" json:"restaurant_name"`
+	PaymentSystem   string    `bson:"payment_system,omitempty" json:"payment_system,omitempty"`
+	DeliveryOrderID string    `bson:"delivery_order_id,omitempty" json:"delivery_order_id,omitempty"`
+	Date            time.Time `bson:"date" json:"date"`
+	Description     string    `bson:"description" json:"description"`
+	Amount          float64   `bson:"amount" json:"amount"`
+}
+
+type Totals struct {
+	Orders        float64 `bson:"orders" json:"orders"`
+	Refunds       float64 `bson:"refunds" json:"refunds"`
+	ServiceFees   float64 `bson:"service_fees" json:"service_fees"`
+	Deliveries    float64 `bson:"deliveries" json:"deliveries"`
+	KwaakaCharges float64 `bson:"kwaaka_charges" json:"kwaaka_charges"`
+	OrdersCount   int     `bson:"orders_count" json:"orders_count"`
+	Unreconciled  int     `bson:"unreconciled" json:"unreconciled"`
+}
+
+// Statement - сверка с юр. лицом за период.
+// ClosingBalance = OpeningBalance + сумма строк, OpeningBalance - ClosingBalance предыдущей сверки юр. лица
+// за вычетом выплаты ресторану по ней и с учетом оплат юр. лица (Payments), подтвержденных после нее
+type Statement struct {
+	ID                   string    `bson:"_id,omitempty" json:"id"`
+	LegalEntityID        string    `bson:"legal_entity_id" json:"legal_entity_id"`
+	LegalEntityName      string    `bson:"legal_entity_name" json:"legal_entity_name"`
+	StoreIDs             []string  `bson:"store_ids" json:"store_ids"`
+	PeriodFrom           time.Time `bson:"period_from" json:"period_from"`
+	PeriodTo             time.Time `bson:"period_to" json:"period_to"`
+	PreviousStatementID  string    `bson:"previous_statement_id,omitempty" json:"previous_statement_id,omitempty"`
+	OpeningBalance       float64   `bson:"opening_balance" json:"opening_balance"`
+	Payments             float64   `bson:"payments" json:"payments"`
+	ClosingBalance       float64   `bson:"closing_balance" json:"closing_balance"`
+	DueToRestaurant      float64   `bson:"due_to_restaurant" json:"due_to_restaurant"`
+	DueToKwaaka          float64   `bson:"due_to_kwaaka" json:"due_to_kwaaka"`
+	Totals               Totals    `bson:"totals" json:"totals"`
+	Lines                []Line    `bson:"lines" json:"lines"`
+	XlsxUrl              string    `bson:"xlsx_url,omitempty" json:"xlsx_url,omitempty"`
+	PdfUrl               string    `bson:"pdf_url,omitempty" json:"pdf_url,omitempty"`
+	LegalEntityPaymentID string    `bson:"legal_entity_payment_id,omitempty" json:"legal_entity_payment_id,omitempty"`
+	Status               string    `bson:"status,omitempty" json:"status,omitempty"`
+	CreatedAt            time.Time `bson:"created_at" json:"created_at"`
+}
+
+// NeedsDocuments - документы сверки не собраны до конца, сверки без статуса проверяются по ссылкам на документы
+func (s Statement) NeedsDocuments() bool {
+	switch s.Status {
+	case StatusDraft, StatusDocumentsFailed:
+		return true
+	case StatusReady:
+		return false
+	}
+	return s.XlsxUrl == "" || s.PdfUrl == "" || (s.DueToKwaaka > 0 && s.LegalEntityPaymentID == "")
+}
+
+type GenerateRequest struct {
+	LegalEntityID        string    `json:"legal_entity_id"`
+	PeriodFrom           time.Time `json:"period_from"`
+	PeriodTo             time.Time `json:"period_to"`
+	LegalEntityPaymentID string    `json:"legal_entity_payment_id"`
+}
+
+func (r GenerateRequest) Validate() error {
+	if r.LegalEntityID == "" {
+		return errors.New("legal entity id is empty")
+	}
+	return ValidatePeriod(r.PeriodFrom, r.PeriodTo)
+}
+
+func ValidatePeriod(from, to time.Time) error {
+	if from.IsZero() || to.IsZero() || !from.Before(to) {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// PreviousMonth - период сверки за прошлый календарный месяц в UTC
+func PreviousMonth(now time.Time) (time.Time, time.Time) {
+	to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return to.AddDate(0, -1, 0), to
+}
+
+// CarryOver переносит баланс предыдущей сверки: DueToRestaurant по ней считается выплаченным,
+// оплаты юр. лица, подтвержденные после нее, уменьшают долг перед Kwaaka, поэтому оплаченный счет не выставляется повторно
+func (s *Statement) CarryOver(previous Statement, payments float64) {
+	s.PreviousStatementID = previous.ID
+	s.Payments = round(payments)
+	s.OpeningBalance = round(previous.ClosingBalance - previous.DueToRestaurant + payments)
+}
+
+// Calculate пересчитывает итоги, конечный баланс и суммы к оплате по строкам
+func (s *Statement) Calculate() {
+	s.Totals = Totals{}
+	orders := make(map[string]struct{})
+
+	closing := s.OpeningBalance
+	for _, line := range s.Lines {
+		closing += line.Amount
+
+		if line.Type == LineTypeUnreconciled {
+			s.Totals.Unreconciled++
+			continue
+		}
+		orders[line.OrderID] = struct{}{}
+
+		switch line.Type {
+		case LineTypeOrder:
+			s.Totals.Orders += line.Amount
+		case LineTypeRefund:
+			s.Totals.Refunds += line.Amount
+		case LineTypeServiceFee:
+			s.Totals.ServiceFees += line.Amount
+		case LineTypeDelivery:
+			s.Totals.Deliveries += line.Amount
+		case LineTypeKwaakaCharge:
+			s.Totals.KwaakaCharges += line.Amount
+		}
+	}
+	s.Totals.OrdersCount = len(orders)
+
+	s.ClosingBalance = round(closing)
+	s.DueToRestaurant, s.DueToKwaaka = 0, 0
+	if s.ClosingBalance > 0 {
+		s.DueToRestaurant = s.ClosingBalance
+	} else {
+		s.DueToKwaaka = -s.ClosingBalance
+	}
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
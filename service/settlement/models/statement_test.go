@@ -0,0 +1,103 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatementCalculate(t *testing.T) {
+	lines := []Line{
+		{Type: LineTypeOrder, OrderID: "1", Amount: 10000},
+		{Type: LineTypeServiceFee, OrderID: "1", Amount: -500},
+		{Type: LineTypeDelivery, OrderID: "1", Amount: -1200},
+		{Type: LineTypeKwaakaCharge, OrderID: "1", Amount: -150},
+		{Type: LineTypeServiceFee, OrderID: "2", Amount: -90},
+		{Type: LineTypeRefund, OrderID: "1", Amount: -1000},
+	}
+
+	tests := []struct {
+		name            string
+		opening         float64
+		closing         float64
+		dueToRestaurant float64
+		dueToKwaaka     float64
+	}{
+		{"kwaaka owes restaurant", 0, 7060, 7060, 0},
+		{"debt from previous statement", -9000, -1940, 0, 1940},
+	}
+
+	for _, test := range tests {
+		statement := Statement{OpeningBalance: test.opening, Lines: lines}
+		statement.Calculate()
+
+		if statement.ClosingBalance != test.closing || statement.DueToRestaurant != test.dueToRestaurant || statement.DueToKwaaka != test.dueToKwaaka {
+			t.Errorf("%s: expected closing %v, due %v/%v, got %v, due %v/%v", test.name, test.closing, test.dueToRestaurant, test.dueToKwaaka,
+				statement.ClosingBalance, statement.DueToRestaurant, statement.DueToKwaaka)
+		}
+		if statement.Totals.OrdersCount != 2 || statement.Totals.ServiceFees != -590 || statement.Totals.Deliveries != -1200 {
+			t.Errorf("%s: unexpected totals %+v", test.name, statement.Totals)
+		}
+	}
+
+	from, to := PreviousMonth(time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC))
+	if !from.Equal(time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected previous month %v - %v", from, to)
+	}
+}
+
+func TestStatementCarryOver(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous Statement
+		payments float64
+		opening  float64
+	}{
+		{"unpaid debt is carried over", Statement{ID: "prev", ClosingBalance: -1940, DueToKwaaka: 1940}, 0, -1940},
+		{"paid debt is not billed again", Statement{ID: "prev", ClosingBalance: -1940, DueToKwaaka: 1940}, 1940, 0},
+		{"partially paid debt", Statement{ID: "prev", ClosingBalance: -1940, DueToKwaaka: 1940}, 1000, -940},
+		{"payout to restaurant is not paid twice", Statement{ID: "prev", ClosingBalance: 7060, DueToRestaurant: 7060}, 0, 0},
+	}
+
+	for _, test := range tests {
+		statement := Statement{Lines: []Line{{Type: LineTypeServiceFee, OrderID: "1", Amount: -100}}}
+		statement.CarryOver(test.previous, test.payments)
+		statement.Calculate()
+
+		if statement.PreviousStatementID != "prev" || statement.OpeningBalance != test.opening || statement.ClosingBalance != test.opening-100 {
+			t.Errorf("%s: expected opening %v, closing %v, got %v, %v", test.name, test.opening, test.opening-100, statement.OpeningBalance, statement.ClosingBalance)
+		}
+	}
+}
+
+func TestStatementCalculateUnreconciled(t *testing.T) {
+	statement := Statement{Lines: []Line{
+		{Type: LineTypeOrder, OrderID: "1", Amount: 10000},
+		{Type: LineTypeUnreconciled, OrderID: "2"},
+	}}
+	statement.Calculate()
+
+	if statement.Totals.OrdersCount != 1 || statement.Totals.Unreconciled != 1 || statement.ClosingBalance != 10000 {
+		t.Errorf("unexpected statement totals %+v, closing %v", statement.Totals, statement.ClosingBalance)
+	}
+}
+
+func TestStatementNeedsDocuments(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement Statement
+		want      bool
+	}{
+		{"draft", Statement{Status: StatusDraft, XlsxUrl: "x", PdfUrl: "p"}, true},
+		{"documents failed", Statement{Status: StatusDocumentsFailed}, true},
+		{"ready", Statement{Status: StatusReady}, false},
+		{"without status and documents", Statement{}, true},
+		{"without status, bill is not created", Statement{XlsxUrl: "x", PdfUrl: "p", DueToKwaaka: 100}, true},
+		{"without status, documents attached", Statement{XlsxUrl: "x", PdfUrl: "p", DueToKwaaka: 100, LegalEntityPaymentID: "1"}, false},
+	}
+
+	for _, test := range tests {
+		if got := test.statement.NeedsDocuments(); got != test.want {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package settlement
+
+import (
+	"context"
+	"time"
+
+	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	"github.com/kwaaka-team/orders-core/service/settlement/models"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const collectionName = "settlement_statements"
+
+type Repository interface {
+	Insert(ctx context.Context, statement models.Statement) (string, error)
+	GetByID(ctx context.Context, id string) (models.Statement, error)
+	FindByLegalEntityID(ctx context.Context, legalEntityID string) ([]models.Statement, error)
+	FindLastBefore(ctx context.Context, legalEntityID string, periodFrom time.Time) (models.Statement, error)
+	FindByPeriod(ctx context.Context, legalEntityID string, periodFrom, periodTo time.Time) (models.Statement, error)
+	SetDocuments(ctx context.Context, id, xlsxUrl, pdfUrl, legalEntityPaymentID string) error
+	SetPayment(ctx context.Context, id, legalEntityPaymentID string) error
+	SetStatus(ctx context.Context, id, status string) error
+}
+
+type MongoRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(db *mongo.Database) (*MongoRepository, error) {
+	return &MongoRepository{collection: db.Collection(collectionName)}, nil
+}
+
+func (m *MongoRepository) Insert(ctx context.Context, statement models.Statement) (string, error) {
+	statement.ID = ""
+	statement.Status = models.StatusDraft
+	statement.CreatedAt = time.Now().UTC()
+
+	res, err := m.collection.InsertOne(ctx, statement)
+	if err != nil {
+		return "", errorSwitch(err)
+	}
+
+	oid, ok := res.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", drivers.ErrInvalid
+	}
+
+	return oid.Hex(), nil
+}
+
+func (m *MongoRepository) GetByID(ctx context.Context, id string) (models.Statement, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.Statement{}, err
+	}
+
+	var statement models.Statement
+	if err = m.collection.FindOne(ctx, bson.D{{Key: "_id", Value: oid}}).Decode(&statement); err != nil {
+		return models.Statement{}, errorSwitch(err)
+	}
+
+	return statement, nil
+}
+
+// FindByLegalEntityID - сверки юр. лица без строк, новые первыми
+func (m *MongoRepository) FindByLegalEntityID(ctx context.Context, legalEntityID string) ([]models.Statement, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "period_to", Value: -1}}).
+		SetProjection(bson.D{{Key: "lines", Value: 0}})
+
+	cur, err := m.collection.Find(ctx, bson.D{{Key: "legal_entity_id", Value: legalEntityID}}, opts)
+	if err != nil {
+		return nil, errorSwitch(err)
+	}
+	defer cur.Close(ctx)
+
+	statements := make([]models.Statement, 0, cur.RemainingBatchLength())
+	for cur.Next(ctx) {
+		var statement models.Statement
+		if err = cur.Decode(&statement); err != nil {
+			log.Err(err).Msgf("error decoding into settlement statement model")
+			continue
+		}
+		statements = append(statements, statement)
+	}
+
+	return statements, nil
+}
+
+// FindLastBefore - последняя сверка юр. лица, закончившаяся не позже начала периода
+func (m *MongoRepository) FindLastBefore(ctx context.Context, legalEntityID string, periodFrom time.Time) (models.Statement, error) {
+	filter := bson.D{
+		{Key: "legal_entity_id", Value: legalEntityID},
+		{Key: "period_to", Value: bson.D{{Key: "$lte", Value: periodFrom}}},
+	}
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "period_to", Value: -1}, {Key: "created_at", Value: -1}}).
+		SetProjection(bson.D{{Key: "lines", Value: 0}})
+
+	var statement models.Statement
+	if err := m.collection.FindOne(ctx, filter, opts).Decode(&statement); err != nil {
+		return models.Statement{}, errorSwitch(err)
+	}
+
+	return statement, nil
+}
+
+// FindByPeriod - сверка юр. лица за период, уникальна по индексу legal_entity_id, period_from, period_to
+func (m *MongoRepository) FindByPeriod(ctx context.Context, legalEntityID string, periodFrom, periodTo time.Time) (models.Statement, error) {
+	filter := bson.D{
+		{Key: "legal_entity_id", Value: legalEntityID},
+		{Key: "period_from", Value: periodFrom},
+		{Key: "period_to", Value: periodTo},
+	}
+
+	var statement models.Statement
+	if err := m.collection.FindOne(ctx, filter).Decode(&statement); err != nil {
+		return models.Statement{}, errorSwitch(err)
+	}
+
+	return statement, nil
+}
+
+// SetDocuments - ссылки на документы и счет, сверка переходит в статус ready
+func (m *MongoRepository) SetDocuments(ctx context.Context, id, xlsxUrl, pdfUrl, legalEntityPaymentID string) error {
+	return m.set(ctx, id, bson.D{
+		{Key: "xlsx_url", Value: xlsxUrl},
+		{Key: "pdf_url", Value: pdfUrl},
+		{Key: "legal_entity_payment_id", Value: legalEntityPaymentID},
+		{Key: "status", Value: models.StatusReady},
+	})
+}
+
+// SetPayment - созданный по сверке legal entity payment сохраняется до выставления счета, чтобы повторная сборка его не дублировала
+func (m *MongoRepository) SetPayment(ctx context.Context, id, legalEntityPaymentID string) error {
+	return m.set(ctx, id, bson.D{{Key: "legal_entity_payment_id", Value: legalEntityPaymentID}})
+}
+
+func (m *MongoRepository) SetStatus(ctx context.Context, id, status string) error {
+	return m.set(ctx, id, bson.D{{Key: "status", Value: status}})
+}
+
+func (m *MongoRepository) set(ctx context.Context, id string, fields bson.D) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	res, err := m.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: oid}}, bson.D{{Key: "$set", Value: fields}})
+	if err != nil {
+		return errorSwitch(err)
+	}
+	if res.MatchedCount == 0 {
+		return drivers.ErrNotFound
+	}
+
+	return nil
+}
+
+func errorSwitch(err error) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return drivers.ErrNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return drivers.ErrAlreadyExist
+	default:
+		return err
+	}
+}
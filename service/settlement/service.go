@@ -0,0 +1,300 @@
+package settlement
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kwaaka-team/orders-core/config/general"
+	"github.com/kwaaka-team/orders-core/core/menu/database/drivers"
+	"github.com/kwaaka-team/orders-core/core/menu/models/selector"
+	selector2 "github.com/kwaaka-team/orders-core/core/storecore/managers/selector"
+	"github.com/kwaaka-team/orders-core/service/aws_s3"
+	"github.com/kwaaka-team/orders-core/service/legal_entity_payment"
+	paymentModels "github.com/kwaaka-team/orders-core/service/legal_entity_payment/models"
+	"github.com/kwaaka-team/orders-core/service/legalentity"
+	legalEntityModels "github.com/kwaaka-team/orders-core/service/legalentity/models"
+	"github.com/kwaaka-team/orders-core/service/order_report"
+	"github.com/kwaaka-team/orders-core/service/settlement/models"
+	storeServicePkg "github.com/kwaaka-team/orders-core/service/store"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type Service interface {
+	Generate(ctx context.Context, req models.GenerateRequest) (models.Statement, error)
+	GenerateAll(ctx context.Context, from, to time.Time) ([]models.Statement, error)
+	GetByID(ctx context.Context, id string) (models.Statement, error)
+	GetByLegalEntityID(ctx context.Context, legalEntityID string) ([]models.Statement, error)
+}
+
+type ServiceImpl struct {
+	cfg                       general.Configuration
+	repo                      Repository
+	storeService              storeServicePkg.Service
+	orderReport               order_report.OrderReport
+	legalEntityService        legalentity.LegalEntityService
+	legalEntityPaymentService legal_entity_payment.Service
+	s3Service                 aws_s3.Service
+}
+
+func NewService(cfg general.Configuration, repo Repository, storeService storeServicePkg.Service, orderReport order_report.OrderReport,
+	legalEntityService legalentity.LegalEntityService, legalEntityPaymentService legal_entity_payment.Service, s3Service aws_s3.Service) (*ServiceImpl, error) {
+	if repo == nil {
+		return nil, errors.New("settlement repository is nil")
+	}
+	if storeService == nil {
+		return nil, errors.New("store service is nil")
+	}
+	if orderReport == nil {
+		return nil, errors.New("order report service is nil")
+	}
+	if legalEntityService == nil {
+		return nil, errors.New("legal entity service is nil")
+	}
+	if legalEntityPaymentService == nil {
+		return nil, errors.New("legal entity payment service is nil")
+	}
+	if s3Service == nil {
+		return nil, errors.New("s3 service is nil")
+	}
+
+	return &ServiceImpl{
+		cfg:                       cfg,
+		repo:                      repo,
+		storeService:              storeService,
+		orderReport:               orderReport,
+		legalEntityService:        legalEntityService,
+		legalEntityPaymentService: legalEntityPaymentService,
+		s3Service:                 s3Service,
+	}, nil
+}
+
+func (s *ServiceImpl) GetByID(ctx context.Context, id string) (models.Statement, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *ServiceImpl) GetByLegalEntityID(ctx context.Context, legalEntityID string) ([]models.Statement, error) {
+	return s.repo.FindByLegalEntityID(ctx, legalEntityID)
+}
+
+// Generate собирает сверку юр. лица за период, выгружает xlsx и pdf в s3.
+// Если юр. лицо должно Kwaaka, на сумму долга выставляется счет в legal entity payment.
+// Сверка за период одна: при повторном запуске возвращается уже собранная, недособранные документы и счет собираются заново
+func (s *ServiceImpl) Generate(ctx context.Context, req models.GenerateRequest) (models.Statement, error) {
+	if err := req.Validate(); err != nil {
+		return models.Statement{}, err
+	}
+
+	existing, err := s.repo.FindByPeriod(ctx, req.LegalEntityID, req.PeriodFrom, req.PeriodTo)
+	switch {
+	case err == nil:
+		if !existing.NeedsDocuments() {
+			return existing, nil
+		}
+		return s.retryDocuments(ctx, existing, req.LegalEntityPaymentID)
+	case !errors.Is(err, drivers.ErrNotFound):
+		return models.Statement{}, err
+	}
+
+	legalEntity, err := s.legalEntityService.Get(ctx, req.LegalEntityID)
+	if err != nil {
+		return models.Statement{}, err
+	}
+
+	stores, err := s.storeService.GetStoresBySelectorFilter(ctx, selector2.NewEmptyStoreSearch().SetLegalEntityID(req.LegalEntityID))
+	if err != nil {
+		return models.Statement{}, err
+	}
+	if len(stores) == 0 {
+		return models.Statement{}, models.ErrNoStores
+	}
+
+	statement := models.Statement{
+		LegalEntityID:   req.LegalEntityID,
+		LegalEntityName: legalEntity.Name,
+		PeriodFrom:      req.PeriodFrom,
+		PeriodTo:        req.PeriodTo,
+	}
+	for _, store := range stores {
+		statement.StoreIDs = append(statement.StoreIDs, store.ID)
+	}
+
+	statement.Lines, err = s.orderReport.SettlementLines(ctx, statement.StoreIDs, req.PeriodFrom, req.PeriodTo)
+	if err != nil {
+		return models.Statement{}, err
+	}
+	sort.SliceStable(statement.Lines, func(i, j int) bool {
+		return statement.Lines[i].Date.Before(statement.Lines[j].Date)
+	})
+
+	previous, err := s.repo.FindLastBefore(ctx, req.LegalEntityID, req.PeriodFrom)
+	switch {
+	case err == nil:
+		payments, err := s.paymentsAfter(ctx, req.LegalEntityID, previous.CreatedAt)
+		if err != nil {
+			return models.Statement{}, err
+		}
+		statement.CarryOver(previous, payments)
+	case !errors.Is(err, drivers.ErrNotFound):
+		return models.Statement{}, err
+	}
+
+	statement.Calculate()
+
+	statement.ID, err = s.repo.Insert(ctx, statement)
+	if errors.Is(err, drivers.ErrAlreadyExist) {
+		return s.repo.FindByPeriod(ctx, req.LegalEntityID, req.PeriodFrom, req.PeriodTo)
+	}
+	if err != nil {
+		return models.Statement{}, err
+	}
+	statement.Status = models.StatusDraft
+
+	if err = s.attachDocuments(ctx, &statement, req.LegalEntityPaymentID, legalEntity.PaymentType); err != nil {
+		return s.documentsFailed(ctx, statement, err)
+	}
+
+	return statement, nil
+}
+
+// retryDocuments - повторная выгрузка документов и выставление счета по сохраненной сверке, строки и балансы не пересчитываются
+func (s *ServiceImpl) retryDocuments(ctx context.Context, statement models.Statement, paymentID string) (models.Statement, error) {
+	legalEntity, err := s.legalEntityService.Get(ctx, statement.LegalEntityID)
+	if err != nil {
+		return models.Statement{}, err
+	}
+
+	if paymentID == "" {
+		paymentID = statement.LegalEntityPaymentID
+	}
+
+	if err = s.attachDocuments(ctx, &statement, paymentID, legalEntity.PaymentType); err != nil {
+		return s.documentsFailed(ctx, statement, err)
+	}
+
+	return statement, nil
+}
+
+func (s *ServiceImpl) documentsFailed(ctx context.Context, statement models.Statement, err error) (models.Statement, error) {
+	log.Err(err).Msgf("settlement statement %s documents error", statement.ID)
+
+	if statusErr := s.repo.SetStatus(ctx, statement.ID, models.StatusDocumentsFailed); statusErr != nil {
+		log.Err(statusErr).Msgf("set settlement statement %s status error", statement.ID)
+	}
+	statement.Status = models.StatusDocumentsFailed
+
+	return statement, err
+}
+
+// GenerateAll - сверки по всем юр. лицам, у которых есть рестораны, ошибки по отдельному юр. лицу только логируются
+func (s *ServiceImpl) GenerateAll(ctx context.Context, from, to time.Time) ([]models.Statement, error) {
+	if err := models.ValidatePeriod(from, to); err != nil {
+		return nil, err
+	}
+
+	legalEntities, err := s.legalEntityService.List(ctx, selector.Pagination{}, legalEntityModels.Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	statements := make([]models.Statement, 0, len(legalEntities))
+	for _, legalEntity := range legalEntities {
+		statement, err := s.Generate(ctx, models.GenerateRequest{
+			LegalEntityID: legalEntity.LegalEntityID,
+			PeriodFrom:    from,
+			PeriodTo:      to,
+		})
+		if err != nil {
+			if !errors.Is(err, models.ErrNoStores) {
+				log.Err(err).Msgf("generate settlement statement for legal entity %s error", legalEntity.LegalEntityID)
+			}
+			continue
+		}
+		statements = append(statements, statement)
+	}
+
+	return statements, nil
+}
+
+// paymentsAfter - сумма оплат юр. лица, подтвержденных после сборки предыдущей сверки
+func (s *ServiceImpl) paymentsAfter(ctx context.Context, legalEntityID string, after time.Time) (float64, error) {
+	payments, err := s.legalEntityPaymentService.GetConfirmedPayments(ctx, legalEntityID, after)
+	if err != nil {
+		return 0, err
+	}
+
+	var sum float64
+	for _, payment := range payments {
+		if payment.PaidAmount > 0 {
+			sum += payment.PaidAmount
+			continue
+		}
+		sum += payment.Amount
+	}
+
+	return sum, nil
+}
+
+func (s *ServiceImpl) attachDocuments(ctx context.Context, statement *models.Statement, paymentID, paymentType string) error {
+	link := fmt.Sprintf("settlement_statements/%s/%s", statement.LegalEntityID, statement.ID)
+
+	xlsxFile, err := toXlsx(*statement)
+	if err != nil {
+		return err
+	}
+	if err = s.s3Service.PutDocument(link, xlsxFile, s.cfg.KwaakaFilesBucket, xlsxContentType); err != nil {
+		return err
+	}
+	statement.XlsxUrl = fmt.Sprintf("%s/%s.xlsx", s.cfg.KwaakaFilesBaseUrl, link)
+
+	pdfFile, err := toPdf(*statement)
+	if err != nil {
+		return err
+	}
+	if err = s.s3Service.PutDocument(link, pdfFile, s.cfg.KwaakaFilesBucket, pdfContentType); err != nil {
+		return err
+	}
+	statement.PdfUrl = fmt.Sprintf("%s/%s.pdf", s.cfg.KwaakaFilesBaseUrl, link)
+
+	if statement.DueToKwaaka > 0 || paymentID != "" {
+		if paymentID == "" {
+			paymentID, err = s.legalEntityPaymentService.CreatePayment(ctx, paymentModels.LegalEntityPayment{
+				Name:            fmt.Sprintf("Сверка %s", formatPeriod(*statement)),
+				LegalEntityID:   statement.LegalEntityID,
+				LegalEntityName: statement.LegalEntityName,
+				Amount:          statement.DueToKwaaka,
+				StartDate:       statement.PeriodFrom,
+				EndDate:         statement.PeriodTo,
+				PaymentType:     paymentType,
+				CreatedAt:       time.Now().UTC(),
+			})
+			if err != nil {
+				return err
+			}
+			if err = s.repo.SetPayment(ctx, statement.ID, paymentID); err != nil {
+				return err
+			}
+		}
+		statement.LegalEntityPaymentID = paymentID
+
+		if err = s.legalEntityPaymentService.CreateBill(ctx, paymentModels.LegalEntityPaymentCreateBillRequest{
+			LegalEntityPaymentID: paymentID,
+			Name:                 fmt.Sprintf("Сверка %s", formatPeriod(*statement)),
+			Amount:               statement.DueToKwaaka,
+			StartDate:            statement.PeriodFrom,
+			EndDate:              statement.PeriodTo,
+			BillLink:             statement.PdfUrl,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err = s.repo.SetDocuments(ctx, statement.ID, statement.XlsxUrl, statement.PdfUrl, statement.LegalEntityPaymentID); err != nil {
+		return err
+	}
+	statement.Status = models.StatusReady
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package sla
+
+import (
+	"context"
+	"time"
+
+	"github.com/kwaaka-team/orders-core/core/models"
+	"github.com/kwaaka-team/orders-core/core/models/selector"
+	selector2 "github.com/kwaaka-team/orders-core/core/storecore/managers/selector"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/order"
+	storeServicePkg "github.com/kwaaka-team/orders-core/service/store"
+	"github.com/pkg/errors"
+)
+
+const defaultBreachesLimit = 100
+
+type Service interface {
+	Analytics(ctx context.Context, query models.SLAAnalyticsRequest) (models.SLAAnalytics, error)
+	WorstBreaches(ctx context.Context, stores []storeModels.Store, from, to time.Time, limit int) (map[string][]models.SLABreach, error)
+}
+
+type ServiceImpl struct {
+	orderRepo    order.Repository
+	storeService storeServicePkg.Service
+}
+
+func NewService(orderRepo order.Repository, storeService storeServicePkg.Service) (*ServiceImpl, error) {
+	if orderRepo == nil {
+		return nil, errors.New("order repository is nil")
+	}
+	if storeService == nil {
+		return nil, errors.New("store service is nil")
+	}
+
+	return &ServiceImpl{
+		orderRepo:    orderRepo,
+		storeService: storeService,
+	}, nil
+}
+
+// Analytics - перцентили этапов заказов ресторанов за период. Пороги из запроса заменяют пороги ресторанов
+func (s *ServiceImpl) Analytics(ctx context.Context, query models.SLAAnalyticsRequest) (models.SLAAnalytics, error) {
+	if !query.StartDate.Before(query.EndDate) {
+		return models.SLAAnalytics{}, errors.New("start date must be before end date")
+	}
+
+	var (
+		stores []storeModels.Store
+		err    error
+	)
+	switch {
+	case len(query.RestaurantIDs) != 0:
+		stores, err = s.storeService.GetStoresBySelectorFilter(ctx, selector2.NewEmptyStoreSearch().SetStoreIDs(query.RestaurantIDs))
+	case query.RestaurantGroupID != "":
+		stores, err = s.storeService.GetRestaurantsByGroupId(ctx, selector2.Pagination{}, query.RestaurantGroupID)
+	default:
+		return models.SLAAnalytics{}, errors.New("restaurant ids or restaurant group id is required")
+	}
+	if err != nil {
+		return models.SLAAnalytics{}, err
+	}
+
+	thresholds := make(map[string]models.SLAThresholds, len(stores))
+	storeIDs := make([]string, 0, len(stores))
+	for _, st := range stores {
+		storeIDs = append(storeIDs, st.ID)
+		thresholds[st.ID] = st.SLAThresholds.Merge(models.DefaultSLAThresholds())
+		if query.Thresholds != nil {
+			thresholds[st.ID] = query.Thresholds.Merge(models.DefaultSLAThresholds())
+		}
+	}
+
+	orders, err := s.getOrders(ctx, storeIDs, query.StartDate, query.EndDate)
+	if err != nil {
+		return models.SLAAnalytics{}, err
+	}
+
+	limit := query.BreachesLimit
+	if limit <= 0 {
+		limit = defaultBreachesLimit
+	}
+
+	analytics := models.BuildSLAAnalytics(orders, func(restaurantID string) models.SLAThresholds {
+		return thresholds[restaurantID]
+	}, limit)
+	analytics.StartDate = query.StartDate
+	analytics.EndDate = query.EndDate
+
+	return analytics, nil
+}
+
+// WorstBreaches - нарушения порогов с наибольшим превышением за период по каждому ресторану.
+// Заказы всех ресторанов читаются одной агрегацией, сгруппированной по ресторану
+func (s *ServiceImpl) WorstBreaches(ctx context.Context, stores []storeModels.Store, from, to time.Time, limit int) (map[string][]models.SLABreach, error) {
+	storeIDs := make([]string, 0, len(stores))
+	for _, st := range stores {
+		storeIDs = append(storeIDs, st.ID)
+	}
+
+	ordersByStore, err := s.orderRepo.GetSLAOrdersByRestaurants(ctx, storeIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string][]models.SLABreach, len(stores))
+	for _, st := range stores {
+		thresholds := st.SLAThresholds.Merge(models.DefaultSLAThresholds())
+		breaches := models.BuildSLAAnalytics(ordersByStore[st.ID], func(string) models.SLAThresholds {
+			return thresholds
+		}, limit).Breaches
+		if len(breaches) != 0 {
+			res[st.ID] = breaches
+		}
+	}
+
+	return res, nil
+}
+
+func (s *ServiceImpl) getOrders(ctx context.Context, storeIDs []string, from, to time.Time) ([]models.Order, error) {
+	if len(storeIDs) == 0 {
+		return nil, nil
+	}
+
+	orders, _, err := s.orderRepo.GetAllOrders(ctx, selector.EmptyOrderSearch().
+		SetRestaurants(storeIDs).
+		SetOrderTimeFrom(from).
+		SetOrderTimeTo(to))
+	if err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
@@ -0,0 +1,60 @@
+package sla
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kwaaka-team/orders-core/core/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/order"
+	"github.com/stretchr/testify/assert"
+)
+
+type slaOrderRepositoryStub struct {
+	order.Repository
+	calls  int
+	orders map[string][]models.Order
+}
+
+func (r *slaOrderRepositoryStub) GetSLAOrdersByRestaurants(ctx context.Context, restaurantIDs []string, from, to time.Time) (map[string][]models.Order, error) {
+	r.calls++
+	return r.orders, nil
+}
+
+func slaOrder(restaurantID string, acceptedAfter time.Duration) models.Order {
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	return models.Order{
+		OrderID:      restaurantID + "_order",
+		RestaurantID: restaurantID,
+		CreatedAt:    models.Time{Time: created},
+		StatusesHistory: []models.OrderStatusUpdate{
+			{Name: string(models.STATUS_ACCEPTED), Time: created.Add(acceptedAfter)},
+		},
+	}
+}
+
+func TestWorstBreaches(t *testing.T) {
+	repo := &slaOrderRepositoryStub{orders: map[string][]models.Order{
+		"slow": {slaOrder("slow", 10*time.Minute)},
+		"fast": {slaOrder("fast", time.Minute)},
+		"own":  {slaOrder("own", 10*time.Minute)},
+	}}
+	service := &ServiceImpl{orderRepo: repo}
+
+	stores := []storeModels.Store{
+		{ID: "slow"},
+		{ID: "fast"},
+		{ID: "own", SLAThresholds: models.SLAThresholds{WebhookToAccepted: 15}},
+	}
+
+	res, err := service.WorstBreaches(context.Background(), stores, time.Time{}, time.Now(), 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, repo.calls)
+	assert.Len(t, res, 1)
+	if assert.Len(t, res["slow"], 1) {
+		assert.Equal(t, models.SLAStageWebhookToAccepted, res["slow"][0].Stage)
+		assert.Equal(t, float64(10), res["slow"][0].Duration)
+	}
+}
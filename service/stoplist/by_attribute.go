@@ -9,13 +9,15 @@ import (
 )
 
 func (s *ServiceImpl) UpdateStopListByAttributeID(ctx context.Context, isAvailable bool, storeID string, attributeID string) error {
+	return s.UpdateStopListByAttributeIDs(ctx, isAvailable, storeID, []string{attributeID})
+}
+
+func (s *ServiceImpl) UpdateStopListByAttributeIDs(ctx context.Context, isAvailable bool, storeID string, attributeIDs []string) error {
 	store, err := s.storeService.GetByID(ctx, storeID)
 	if err != nil {
 		return err
 	}
 
-	attributeIDs := []string{attributeID}
-
 	posMenu, err := s.getPosMenu(ctx, store)
 	if err != nil {
 		return err
@@ -10,13 +10,20 @@ import (
 )
 
 func (s *ServiceImpl) UpdateStopListByPosProductID(ctx context.Context, isAvailable bool, storeID string, posProductID string) error {
+	return s.UpdateStopListByPosProductIDWithReason(ctx, s.productDisableReason(), isAvailable, storeID, posProductID)
+}
+
+// UpdateStopListByPosProductIDWithReason adds or removes only given reason, product stays disabled while other reasons are active
+func (s *ServiceImpl) UpdateStopListByPosProductIDWithReason(ctx context.Context, reason models.DisableReason, isAvailable bool, storeID string, posProductID string) error {
+	return s.UpdateStopListByPosProductIDsWithReason(ctx, reason, isAvailable, storeID, []string{posProductID})
+}
+
+func (s *ServiceImpl) UpdateStopListByPosProductIDsWithReason(ctx context.Context, reason models.DisableReason, isAvailable bool, storeID string, posProductIDs []string) error {
 	store, err := s.storeService.GetByID(ctx, storeID)
 	if err != nil {
 		return err
 	}
 
-	posProductIDs := []string{posProductID}
-
 	posMenu, err := s.getPosMenu(ctx, store)
 	if err != nil {
 		return err
@@ -39,13 +46,13 @@ func (s *ServiceImpl) UpdateStopListByPosProductID(ctx context.Context, isAvaila
 		return nil
 	}
 
-	if err = s.stopProductPosMenu(ctx, isAvailable, posMenu, posProducts); err != nil {
+	if err = s.stopProductPosMenu(ctx, reason, isAvailable, posMenu, posProducts); err != nil {
 		return err
 	}
 
 	for i := range store.Menus {
 		aggMenu := store.Menus[i]
-		if err = s.stopProductsAggregatorMenu(ctx, isAvailable, store, posMenu, posProducts, aggMenu.Delivery); err != nil {
+		if err = s.stopProductsAggregatorMenu(ctx, reason, isAvailable, store, posMenu, posProducts, aggMenu.Delivery); err != nil {
 			return err
 		}
 	}
@@ -194,49 +201,17 @@ func (s *ServiceImpl) filterAggrProducts(aggrMenu models.Menu, productIDs []stri
 }
 
 func (s *ServiceImpl) updateProductsInAggrByStatusForValidateStoreMenus(ctx context.Context, store coreStoreModels.Store, menuID, deliveryService string, aggrProducts []models.Product, disabledByValidation bool) error {
-	for i := range aggrProducts {
-		aggrProducts[i].DisabledByValidation = disabledByValidation
-	}
-
-	aggUpdateProducts := s.productsUpdateInAggrForValidateStoreMenus(aggrProducts, disabledByValidation)
+	setDisableReason(aggrProducts, models.DisableReasonValidation, !disabledByValidation)
 
-	log.Info().Msgf("update aggregator products in aggregator: %+v", aggUpdateProducts)
+	log.Info().Msgf("update aggregator products in aggregator: %+v", aggrProducts)
 
-	if err := s.updateStopListByProductIDInAggregator(ctx, store, aggUpdateProducts, deliveryService, nil); err != nil {
+	if err := s.updateStopListByProductIDInAggregator(ctx, store, aggrProducts, deliveryService, nil); err != nil {
 		return err
 	}
 
-	// not save isAvailable status in DB, only disabledByValidation
-	if err := s.updateProductsDisabledByValidationInDatabase(ctx, menuID, aggrProducts); err != nil {
+	if err := s.updateStopListByProductIDsInDatabase(ctx, menuID, aggrProducts, models.DisableReasonValidation); err != nil {
 		return err
 	}
 
 	return nil
 }
-
-func (s *ServiceImpl) productsUpdateInAggrForValidateStoreMenus(products []models.Product, disabledByValidation bool) []models.Product {
-	if len(products) == 0 {
-		return products
-	}
-
-	switch disabledByValidation {
-	case false:
-		res := make([]models.Product, 0, len(products))
-		for i := range products {
-			if products[i].IsDisabled {
-				continue
-			}
-			products[i].IsAvailable = true
-			res = append(res, products[i])
-		}
-
-		return res
-
-	default:
-		for i := range products {
-			products[i].IsAvailable = false
-		}
-
-		return products
-	}
-}
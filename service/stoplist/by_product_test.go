@@ -6,6 +6,7 @@ import (
 	"github.com/kwaaka-team/orders-core/core/config"
 	menuModels "github.com/kwaaka-team/orders-core/core/menu/models"
 	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/pkg/que"
 	aggregatorMock "github.com/kwaaka-team/orders-core/service/aggregator/mocks"
 	"github.com/kwaaka-team/orders-core/service/menu"
 	menuMock "github.com/kwaaka-team/orders-core/service/menu/mocks"
@@ -18,6 +19,10 @@ import (
 	"testing"
 )
 
+type sqsStub struct {
+	que.SQSInterface
+}
+
 func TestServiceImpl_UpdateStopListByPosProductID(t *testing.T) {
 	var storeService = &storeMock.Service{}
 	var storeGroupService = &storeGroupMock.Service{}
@@ -34,7 +39,7 @@ func TestServiceImpl_UpdateStopListByPosProductID(t *testing.T) {
 	var repo = &stopListMock.Repository{}
 	var woltCfg = config.WoltConfiguration{}
 
-	stopListService, err := NewStopListServiceCron(storeService, storeGroupService, menuService, aggFactory, posFactory, repo, woltCfg, 1)
+	stopListService, err := NewStopListServiceCron(storeService, storeGroupService, menuService, aggFactory, posFactory, repo, woltCfg, 1, sqsStub{})
 	if err != nil {
 		t.Error(err)
 		return
@@ -84,13 +89,13 @@ func TestServiceImpl_UpdateStopListByPosProductID(t *testing.T) {
 		t.Error(err)
 		return
 	}
-	menuRepository.AssertNotCalled(t, "BulkUpdateProductsAvailability", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	menuRepository.AssertNotCalled(t, "BulkUpdateProductsDisableReason", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 
 	if err = stopListService.UpdateStopListByPosProductID(context.Background(), true, storeID, "pos_5_2_deleted"); err != nil {
 		t.Error(err)
 		return
 	}
-	menuRepository.AssertNotCalled(t, "BulkUpdateProductsAvailability", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	menuRepository.AssertNotCalled(t, "BulkUpdateProductsDisableReason", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 
 	ctx := context.Background()
 
@@ -105,8 +110,8 @@ func TestServiceImpl_UpdateStopListByPosProductID(t *testing.T) {
 	}
 	menuRepository.On("FindById", mock.Anything, aggMenuID2).Return(&aggMenu2, nil)
 
-	expectedErr := errors.New("BulkUpdateProductsAvailability error")
-	menuRepository.On("BulkUpdateProductsAvailability", mock.Anything, mock.Anything, []string{"system_1"}, true).Return(expectedErr)
+	expectedErr := errors.New("BulkUpdateProductsDisableReason error")
+	menuRepository.On("BulkUpdateProductsDisableReason", mock.Anything, mock.Anything, []string{"system_1"}, menuModels.DisableReasonPosStopList, false).Return(expectedErr)
 	if err = stopListService.UpdateStopListByPosProductID(ctx, true, storeID, "pos_1"); err == nil {
 		t.Error("expected error, got nil")
 		return
@@ -117,29 +122,26 @@ func TestServiceImpl_UpdateStopListByPosProductID(t *testing.T) {
 		}
 	}
 
-	menuRepository.On("BulkUpdateProductsAvailability", mock.Anything, mock.Anything, []string{"system_2_1", "system_2_2"}, true).Return(nil)
+	menuRepository.On("BulkUpdateProductsDisableReason", mock.Anything, mock.Anything, []string{"system_2_1", "system_2_2"}, menuModels.DisableReasonPosStopList, false).Return(nil)
 
 	storeService.On("GetStoreExternalIds", store, "delivery_1").Return([]string{"delivery_1_store_1", "delivery_1_store_2"}, nil)
 	aggregator1 := aggregatorMock.Aggregator{}
 	//aggFactory.On("GetAggregator", "delivery_1", store).Return(aggregator1, nil)
 	aggFactory.On("GetAggregator", mock.Anything, mock.Anything).Return(&aggregator1, nil)
-	aggregator1.On("UpdateStopListByProductsBulk", mock.Anything, mock.Anything, mock.Anything).Return("transactionID1", nil)
+	aggregator1.On("UpdateStopListByProductsBulk", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("transactionID1", nil)
 
 	repo.On("InsertStopListTransaction", mock.Anything, mock.Anything).Return(nil)
 
-	menuRepository.On("BulkUpdateProductsAvailability", mock.Anything, mock.Anything, []string{"delivery_2_1", "delivery_2_2"}, true).Return(nil)
-
-	menuRepository.On("BulkUpdateProductsDisabledStatus", mock.Anything, "posMenuID", []string{"system_2_1", "system_2_2"}, false).Return(nil)
-	menuRepository.On("BulkUpdateProductsDisabledStatus", mock.Anything, "aggMenuID1", []string{"delivery_2_1", "delivery_2_2"}, false).Return(nil)
+	menuRepository.On("BulkUpdateProductsDisableReason", mock.Anything, mock.Anything, []string{"delivery_2_1", "delivery_2_2"}, menuModels.DisableReasonPosStopList, false).Return(nil)
 
 	if err = stopListService.UpdateStopListByPosProductID(ctx, true, storeID, "pos_2"); err != nil {
 		t.Error(err)
 		return
 	}
 
-	menuRepository.AssertCalled(t, "BulkUpdateProductsAvailability", mock.Anything, posMenuID, []string{"system_1"}, true)
-	menuRepository.AssertCalled(t, "BulkUpdateProductsAvailability", mock.Anything, aggMenu1.ID, []string{"delivery_2_1", "delivery_2_2"}, true)
-	aggregator1.AssertCalled(t, "UpdateStopListByProductsBulk", ctx, "delivery_1_store_1", mock.Anything)
+	menuRepository.AssertCalled(t, "BulkUpdateProductsDisableReason", mock.Anything, posMenuID, []string{"system_1"}, menuModels.DisableReasonPosStopList, false)
+	menuRepository.AssertCalled(t, "BulkUpdateProductsDisableReason", mock.Anything, aggMenu1.ID, []string{"delivery_2_1", "delivery_2_2"}, menuModels.DisableReasonPosStopList, false)
+	aggregator1.AssertCalled(t, "UpdateStopListByProductsBulk", ctx, "delivery_1_store_1", mock.Anything, mock.Anything)
 }
 
 func generatePosMenuProducts() []menuModels.Product {
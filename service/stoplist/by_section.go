@@ -42,7 +42,7 @@ func (s *ServiceImpl) UpdateStopListBySectionID(ctx context.Context, isAvailable
 				continue
 			}
 
-			if err := s.updateStopListByProductIDsInDatabase(ctx, menu.ID, products); err != nil {
+			if err := s.updateStopListByProductIDsInDatabase(ctx, menu.ID, products, models.DisableReasonSection); err != nil {
 				log.Err(err).Msgf("[Database error] Failed to update stop list by sectionIDs(%+v) for delivery %s in store: %s", sectionIDs, menu.Delivery, store.Name)
 			}
 
@@ -73,8 +73,7 @@ func (s *ServiceImpl) getProductsBySectionIDsAndUpdateAvailableStatus(ctx contex
 		if !uniqueSections[aggregatorMenu.Products[i].Section] {
 			continue
 		}
-		aggregatorMenu.Products[i].IsAvailable = isAvailable
-		aggregatorMenu.Products[i].IsDisabled = !isAvailable
+		aggregatorMenu.Products[i].SetDisableReason(models.DisableReasonSection, !isAvailable)
 
 		products = append(products, aggregatorMenu.Products[i])
 	}
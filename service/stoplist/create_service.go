@@ -83,6 +83,11 @@ func getServices(db *mongo.Database, cfg config.Configuration, concurrencyLevel
 		return nil, nil, nil, nil, nil, nil, 0, nil, err
 	}
 
+	sandboxOrderRepository, err := pos.NewMongoSandboxOrderRepository(db)
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, 0, nil, err
+	}
+
 	sqsCli := notifyQueue.NewSQS(sqs.NewFromConfig(cfg.AwsConfig))
 
 	posFactory, err := pos.NewFactory(
@@ -92,7 +97,7 @@ func getServices(db *mongo.Database, cfg config.Configuration, concurrencyLevel
 		cfg.JowiConfiguration.ApiKey, cfg.JowiConfiguration.ApiSecret, cfg.RKeeperBaseURL, cfg.RKeeperApiKey,
 		cfg.BurgerKingConfiguration.BaseURL, bkOfferRepository, cfg.RKeeper7XMLConfiguration.LicenseBaseURL,
 		cfg.SyrveConfiguration.BaseURL, cfg.YarosConfiguration.BaseURL, cfg.YarosConfiguration.InfoSystem,
-		cfg.TillypadConfiguration.BaseUrl, cfg.Ytimes.BaseUrl, cfg.Ytimes.Token, cfg.PosistConfiguration.BaseUrl)
+		cfg.TillypadConfiguration.BaseUrl, cfg.Ytimes.BaseUrl, cfg.Ytimes.Token, cfg.PosistConfiguration.BaseUrl, sandboxOrderRepository)
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, 0, nil, err
 	}
@@ -7,16 +7,14 @@ import (
 	coreStoreModels "github.com/kwaaka-team/orders-core/core/storecore/models"
 )
 
-func (s *ServiceImpl) stopProductPosMenu(ctx context.Context, isAvailable bool, posMenu *menuModels.Menu, posProducts []menuModels.Product) error {
+func (s *ServiceImpl) stopProductPosMenu(ctx context.Context, reason menuModels.DisableReason, isAvailable bool, posMenu *menuModels.Menu, posProducts []menuModels.Product) error {
 	if len(posProducts) == 0 {
 		return nil
 	}
 
-	for i := range posProducts {
-		posProducts[i].IsAvailable = isAvailable
-	}
+	setDisableReason(posProducts, reason, isAvailable)
 
-	if err := s.updateStopListByProductIDsInDatabase(ctx, posMenu.ID, posProducts); err != nil {
+	if err := s.updateStopListByProductIDsInDatabase(ctx, posMenu.ID, posProducts, reason); err != nil {
 		return err
 	}
 
@@ -32,7 +30,7 @@ func (s *ServiceImpl) getPosMenu(ctx context.Context, store coreStoreModels.Stor
 	return menu, nil
 }
 
-func (s *ServiceImpl) stopProductsAggregatorMenu(ctx context.Context, isAvailable bool, store coreStoreModels.Store, posMenu *menuModels.Menu, posProducts []menuModels.Product, deliveryService string) error {
+func (s *ServiceImpl) stopProductsAggregatorMenu(ctx context.Context, reason menuModels.DisableReason, isAvailable bool, store coreStoreModels.Store, posMenu *menuModels.Menu, posProducts []menuModels.Product, deliveryService string) error {
 	isMenuExists := s.menuService.IsMenuExists(store, deliveryService)
 	if !isMenuExists {
 		return fmt.Errorf("menu %s for delivery service %s is not exists", store.ID, deliveryService)
@@ -55,15 +53,13 @@ func (s *ServiceImpl) stopProductsAggregatorMenu(ctx context.Context, isAvailabl
 		return nil
 	}
 
-	for i := range aggProducts {
-		aggProducts[i].IsAvailable = isAvailable
-	}
+	setDisableReason(aggProducts, reason, isAvailable)
 
 	if err = s.updateStopListByProductIDInAggregator(ctx, store, aggProducts, deliveryService, nil); err != nil {
 		return err
 	}
 
-	if err = s.updateStopListByProductIDsInDatabase(ctx, aggregatorMenu.ID, aggProducts); err != nil {
+	if err = s.updateStopListByProductIDsInDatabase(ctx, aggregatorMenu.ID, aggProducts, reason); err != nil {
 		return err
 	}
 
@@ -124,11 +120,9 @@ func (s *ServiceImpl) disabledByValidationProductsInPosMenu(ctx context.Context,
 		return nil
 	}
 
-	for i := range posProducts {
-		posProducts[i].DisabledByValidation = disabledByValidation
-	}
+	setDisableReason(posProducts, menuModels.DisableReasonValidation, !disabledByValidation)
 
-	if err := s.updateProductsDisabledByValidationInDatabase(ctx, posMenu.ID, posProducts); err != nil {
+	if err := s.updateStopListByProductIDsInDatabase(ctx, posMenu.ID, posProducts, menuModels.DisableReasonValidation); err != nil {
 		return err
 	}
 
@@ -18,7 +18,10 @@ import (
 
 type Service interface {
 	UpdateStopListByPosProductID(ctx context.Context, isAvailable bool, storeID string, productID string) error
+	UpdateStopListByPosProductIDWithReason(ctx context.Context, reason menuModels.DisableReason, isAvailable bool, storeID string, productID string) error
+	UpdateStopListByPosProductIDsWithReason(ctx context.Context, reason menuModels.DisableReason, isAvailable bool, storeID string, productIDs []string) error
 	UpdateStopListByAttributeID(ctx context.Context, isAvailable bool, storeID string, attributeID string) error
+	UpdateStopListByAttributeIDs(ctx context.Context, isAvailable bool, storeID string, attributeIDs []string) error
 	UpdateStopListBySectionID(ctx context.Context, isAvailable bool, storeGroupID string, deliveryToSectionIDs map[string][]string) error
 	ActualizeStopListByStoreID(ctx context.Context, storeID string) error
 	ActualizeStopListByToken(ctx context.Context, token string) error
@@ -29,6 +32,8 @@ type Service interface {
 	GetRetailRemains(ctx context.Context, externalStoreId, deliveryService string, storeSecret string) (menuModels.StopListProducts, menuModels.StopListAttributes, error)
 	UpdateStopListForValidateStoreMenus(ctx context.Context, storeID, deliveryService string, productDetails []menu.ProductDetail) error
 	AddYandexTransaction(ctx context.Context, storeID, deliveryService string, products menuModels.StopListProducts, attributes menuModels.StopListAttributes) error
+	ReconcileStopListByStoreID(ctx context.Context, storeID string, heal bool) ([]StopListReconcileReport, error)
+	ReconcileStopListByPosTypes(ctx context.Context, posTypes []string, heal bool) ([]StopListReconcileReport, error)
 }
 
 type ServiceImpl struct {
@@ -94,39 +99,30 @@ func (s *ServiceImpl) updateStopListByProductIDInAggregator(ctx context.Context,
 	return nil
 }
 
-func (s *ServiceImpl) updateStopListByProductIDsInDatabase(ctx context.Context, menuID string, products []menuModels.Product) error {
-	if err := s.updateStopListAvailableStatusByProductIDsInDatabase(ctx, menuID, products); err != nil {
-		return err
-	}
-	if err := s.updateDisabledStatusByProductIDsInDatabase(ctx, menuID, products); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (s *ServiceImpl) updateStopListAvailableStatusByProductIDsInDatabase(ctx context.Context, menuID string, products []menuModels.Product) error {
+// updateStopListByProductIDsInDatabase saves only given reason, products must be prepared by SetDisableReason.
+// Availability in database is recalculated by all reasons of product
+func (s *ServiceImpl) updateStopListByProductIDsInDatabase(ctx context.Context, menuID string, products []menuModels.Product, reason menuModels.DisableReason) error {
 	var (
-		productIdsWithAvailabilityFalse = make([]string, 0, len(products))
-		productIdsWithAvailabilityTrue  = make([]string, 0, len(products))
+		productIdsWithReason    = make([]string, 0, len(products))
+		productIdsWithoutReason = make([]string, 0, len(products))
 	)
 
 	for _, product := range products {
-		if product.IsAvailable {
-			productIdsWithAvailabilityTrue = append(productIdsWithAvailabilityTrue, product.ExtID)
+		if product.HasDisableReason(reason) {
+			productIdsWithReason = append(productIdsWithReason, product.ExtID)
 		} else {
-			productIdsWithAvailabilityFalse = append(productIdsWithAvailabilityFalse, product.ExtID)
+			productIdsWithoutReason = append(productIdsWithoutReason, product.ExtID)
 		}
 	}
 
-	if len(productIdsWithAvailabilityTrue) != 0 {
-		if err := s.menuService.UpdateProductsAvailabilityStatus(ctx, menuID, productIdsWithAvailabilityTrue, true); err != nil {
+	if len(productIdsWithReason) != 0 {
+		if err := s.menuService.UpdateProductsDisableReason(ctx, menuID, productIdsWithReason, reason, true); err != nil {
 			return err
 		}
 	}
 
-	if len(productIdsWithAvailabilityFalse) != 0 {
-		if err := s.menuService.UpdateProductsAvailabilityStatus(ctx, menuID, productIdsWithAvailabilityFalse, false); err != nil {
+	if len(productIdsWithoutReason) != 0 {
+		if err := s.menuService.UpdateProductsDisableReason(ctx, menuID, productIdsWithoutReason, reason, false); err != nil {
 			return err
 		}
 	}
@@ -134,6 +130,13 @@ func (s *ServiceImpl) updateStopListAvailableStatusByProductIDsInDatabase(ctx co
 	return nil
 }
 
+// setDisableReason applies requested availability by reason, IsAvailable of products becomes resulting availability
+func setDisableReason(products []menuModels.Product, reason menuModels.DisableReason, isAvailable bool) {
+	for i := range products {
+		products[i].SetDisableReason(reason, !isAvailable)
+	}
+}
+
 func (s *ServiceImpl) toStopListProducts(products []menuModels.Product) menuModels.StopListProducts {
 
 	res := make([]menuModels.StopListProduct, 0, len(products))
@@ -174,38 +177,6 @@ func (s *ServiceImpl) IsSendRemains(store storeModels.Store) bool {
 	return false
 }
 
-func (s *ServiceImpl) updateProductsDisabledByValidationInDatabase(ctx context.Context, menuID string, products []menuModels.Product) error {
-	var (
-		productIDsDisabledByValidationTrue  = make([]string, 0, len(products))
-		productIDsDisabledByValidationFalse = make([]string, 0, len(products))
-	)
-
-	for _, product := range products {
-		if product.DisabledByValidation {
-			productIDsDisabledByValidationTrue = append(productIDsDisabledByValidationTrue, product.ExtID)
-		} else {
-			productIDsDisabledByValidationFalse = append(productIDsDisabledByValidationFalse, product.ExtID)
-		}
-
-	}
-
-	if len(productIDsDisabledByValidationTrue) != 0 {
-		log.Info().Msgf("update status on true in disabled by validation for products: %v", productIDsDisabledByValidationTrue)
-		if err := s.menuService.UpdateProductsDisabledByValidation(ctx, menuID, productIDsDisabledByValidationTrue, true); err != nil {
-			return err
-		}
-	}
-
-	if len(productIDsDisabledByValidationFalse) != 0 {
-		log.Info().Msgf("update status on false in disabled by validation for products: %v", productIDsDisabledByValidationFalse)
-		if err := s.menuService.UpdateProductsDisabledByValidation(ctx, menuID, productIDsDisabledByValidationFalse, false); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func (s *ServiceImpl) AddYandexTransaction(ctx context.Context, storeID, deliveryService string, products menuModels.StopListProducts, attributes menuModels.StopListAttributes) error {
 
 	store, err := s.storeService.GetByExternalIdAndDeliveryService(ctx, storeID, deliveryService)
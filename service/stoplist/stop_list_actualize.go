@@ -216,7 +216,7 @@ func (s *ServiceImpl) actualizeStopListPosMenu(ctx context.Context, store storeM
 	}
 
 	if len(products) != 0 {
-		if err = s.updateStopListByProductIDsInDatabase(ctx, menuID, products); err != nil {
+		if err = s.updateStopListByProductIDsInDatabase(ctx, menuID, products, posDisableReason(isByBalance)); err != nil {
 			return nil, err
 		}
 	}
@@ -297,7 +297,7 @@ func (s *ServiceImpl) actualizeStopListAggregatorMenu(ctx context.Context, store
 	if err = s.updateStopListByProductIDInAggregator(ctx, store, products, menu.Delivery, posStopListItems); err != nil {
 		return err
 	}
-	if err = s.updateStopListByProductIDsInDatabase(ctx, menuID, products); err != nil {
+	if err = s.updateStopListByProductIDsInDatabase(ctx, menuID, products, posDisableReason(isByBalance)); err != nil {
 		return err
 	}
 
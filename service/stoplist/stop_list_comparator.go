@@ -121,15 +121,14 @@ func (s stopListMenuComparator) process() ([]menuModels.Product, []menuModels.At
 
 func (s stopListMenuComparator) processProducts() (products []menuModels.Product) {
 
+	reason := s.disableReason()
+
 	for _, product := range s.menu.Products {
 		productID, _ := s.idExtractor.getProductID(product)
 
-		if product.IsDisabled || product.DisabledByValidation {
-			continue
-		}
-
+		// удаленный на кассе продукт выключаем причиной кассы
 		if product.IsDeleted {
-			product.IsAvailable = false
+			product.SetDisableReason(reason, true)
 			product.Balance = s.stopListFromPos[productID].Balance
 			products = append(products, product)
 			continue
@@ -140,8 +139,8 @@ func (s stopListMenuComparator) processProducts() (products []menuModels.Product
 			continue
 		}
 
-		targetAvailability := s.isProductAvailable(productID)
-		product.IsAvailable = targetAvailability
+		// выключенные админом или по расписанию продукты тоже получают причину кассы, но остаются выключенными
+		product.SetDisableReason(reason, !s.isProductAvailable(productID))
 		product.Balance = s.stopListFromPos[productID].Balance
 		products = append(products, product)
 	}
@@ -174,6 +173,18 @@ func (s stopListMenuComparator) processAttributes() (attributes []menuModels.Att
 	return
 }
 
+func (s stopListMenuComparator) disableReason() menuModels.DisableReason {
+	return posDisableReason(s.isByBalance)
+}
+
+// posDisableReason - при остатках продукт выключается по балансу, иначе по стоп-листу кассы
+func posDisableReason(isByBalance bool) menuModels.DisableReason {
+	if isByBalance {
+		return menuModels.DisableReasonBalance
+	}
+	return menuModels.DisableReasonPosStopList
+}
+
 func (s stopListMenuComparator) isProductAvailable(productID string) bool {
 	stopListItem, isOnStop := s.getStopListItem(productID)
 	if !isOnStop {
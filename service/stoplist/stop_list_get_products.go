@@ -79,7 +79,7 @@ func (s *ServiceImpl) actualizeStopListAggregatorMenu2(ctx context.Context, stor
 
 	menuID := menu.ID
 
-	if err = s.updateStopListByProductIDsInDatabase(ctx, menuID, aggregatorProducts); err != nil {
+	if err = s.updateStopListByProductIDsInDatabase(ctx, menuID, aggregatorProducts, coreMenuModels.DisableReasonPosStopList); err != nil {
 		return err
 	}
 	if err = s.updateStopListByProductIDInAggregator(ctx, store, aggregatorProducts, menu.Delivery, posStopListItems); err != nil {
@@ -105,7 +105,7 @@ func (s *ServiceImpl) setAvailabilityToAggregatorProductsFromPosProducts(aggrega
 		if !ok {
 			continue
 		}
-		aggregatorProducts[i].IsAvailable = availability
+		aggregatorProducts[i].SetDisableReason(coreMenuModels.DisableReasonPosStopList, !availability)
 	}
 
 	return aggregatorProducts
@@ -133,7 +133,7 @@ func (s *ServiceImpl) actualizeStopListPosMenu2(ctx context.Context, store store
 	//}
 
 	if len(products) != 0 {
-		if err := s.updateStopListByProductIDsInDatabase(ctx, menuID, products); err != nil {
+		if err := s.updateStopListByProductIDsInDatabase(ctx, menuID, products, coreMenuModels.DisableReasonPosStopList); err != nil {
 			return err
 		}
 	}
@@ -163,13 +163,13 @@ func (s *ServiceImpl) getChangedProducts(stopListItems coreMenuModels.StopListIt
 			targetAvailability = true
 		}
 
-		currentAvailability := product.IsAvailable
+		currentAvailability := !product.HasDisableReason(coreMenuModels.DisableReasonPosStopList)
 
 		if targetAvailability == currentAvailability {
 			continue
 		}
 
-		product.IsAvailable = targetAvailability
+		product.SetDisableReason(coreMenuModels.DisableReasonPosStopList, !targetAvailability)
 		result = append(result, product)
 	}
 
@@ -220,7 +220,7 @@ func (s *ServiceImpl) toMapPosProductsAvailability(posProducts []coreMenuModels.
 	for i := range posProducts {
 		posProduct := posProducts[i]
 		systemID := s.menuService.GetSystemIDFromPosProduct(posProduct)
-		result[systemID] = posProduct.IsAvailable
+		result[systemID] = !posProduct.HasDisableReason(coreMenuModels.DisableReasonPosStopList)
 	}
 
 	return result
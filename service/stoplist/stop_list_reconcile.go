@@ -0,0 +1,295 @@
+package stoplist
+
+import (
+	"context"
+	"time"
+
+	menuModels "github.com/kwaaka-team/orders-core/core/menu/models"
+	"github.com/kwaaka-team/orders-core/core/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/service/aggregator"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DriftSourceMenu       = "menu"
+	DriftSourceAggregator = "aggregator"
+)
+
+// StopListDrift - продукт, доступность которого в меню или у агрегатора расходится с кассой
+type StopListDrift struct {
+	ExtID               string `json:"ext_id"`
+	PosID               string `json:"pos_id"`
+	Name                string `json:"name"`
+	Source              string `json:"source"`
+	AggregatorStoreID   string `json:"aggregator_store_id,omitempty"`
+	ExpectedAvailable   bool   `json:"expected_available"`
+	MenuAvailable       bool   `json:"menu_available"`
+	AggregatorAvailable *bool  `json:"aggregator_available,omitempty"`
+}
+
+type StopListReconcileReport struct {
+	StoreID           string          `json:"store_id"`
+	StoreName         string          `json:"store_name"`
+	DeliveryService   string          `json:"delivery_service"`
+	MenuID            string          `json:"menu_id"`
+	AggregatorChecked bool            `json:"aggregator_checked"`
+	Drifts            []StopListDrift `json:"drifts"`
+	HealedProducts    int             `json:"healed_products"`
+	Error             string          `json:"error,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+}
+
+func (r StopListReconcileReport) HasDrift() bool {
+	return len(r.Drifts) != 0
+}
+
+func (s *ServiceImpl) ReconcileStopListByStoreID(ctx context.Context, storeID string, heal bool) ([]StopListReconcileReport, error) {
+	store, err := s.storeService.GetByID(ctx, storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reconcileStopList(ctx, store, heal)
+}
+
+func (s *ServiceImpl) ReconcileStopListByPosTypes(ctx context.Context, posTypes []string, heal bool) ([]StopListReconcileReport, error) {
+	reports := make([]StopListReconcileReport, 0)
+
+	for _, posType := range posTypes {
+		stores, err := s.storeService.FindStoresByPosType(ctx, posType)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, store := range stores {
+			if store.Settings.IgnoreUpdateStopList {
+				continue
+			}
+
+			storeReports, err := s.reconcileStopList(ctx, store, heal)
+			if err != nil {
+				log.Err(err).Msgf("reconcile stop list error, store_id = %s", store.ID)
+				reports = append(reports, StopListReconcileReport{
+					StoreID:   store.ID,
+					StoreName: store.Name,
+					Error:     err.Error(),
+					CreatedAt: time.Now().UTC(),
+				})
+				continue
+			}
+
+			reports = append(reports, storeReports...)
+		}
+	}
+
+	return reports, nil
+}
+
+// reconcileStopList compares pos stop list with aggregator menus and with aggregators itself (where API allows),
+// drifted products are sent to aggregator again if heal is true
+func (s *ServiceImpl) reconcileStopList(ctx context.Context, store storeModels.Store, heal bool) ([]StopListReconcileReport, error) {
+	posService, err := s.posFactory.GetPosService(models.Pos(store.PosType), store)
+	if err != nil {
+		return nil, err
+	}
+
+	stopListItems, err := posService.GetStopList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	posMenu, err := s.menuService.GetMenuById(ctx, store.MenuID)
+	if err != nil {
+		return nil, err
+	}
+
+	stopListItems, err = posService.SortStoplistItemsByIsIgnored(ctx, posMenu, stopListItems)
+	if err != nil {
+		return nil, err
+	}
+
+	isByBalance := posService.IsStopListByBalance(ctx, store)
+	balanceLimit := float64(posService.GetBalanceLimit(ctx, store))
+
+	reports := make([]StopListReconcileReport, 0, len(store.Menus))
+	for _, menu := range store.Menus {
+		if !menu.IsActive {
+			continue
+		}
+
+		report := s.reconcileAggregatorMenu(ctx, store, menu, stopListItems, isByBalance, balanceLimit, heal)
+		if report.HasDrift() {
+			log.Warn().Msgf("stop list drift for store_id = %s, delivery = %s: %d products, healed %d", store.ID, menu.Delivery, len(report.Drifts), report.HealedProducts)
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+func (s *ServiceImpl) reconcileAggregatorMenu(ctx context.Context, store storeModels.Store, menu storeModels.StoreDSMenu, stopListItems menuModels.StopListItems,
+	isByBalance bool, balanceLimit float64, heal bool) StopListReconcileReport {
+	report := StopListReconcileReport{
+		StoreID:         store.ID,
+		StoreName:       store.Name,
+		DeliveryService: menu.Delivery,
+		MenuID:          menu.ID,
+		CreatedAt:       time.Now().UTC(),
+	}
+
+	aggMenu, err := s.menuService.FindById(ctx, menu.ID)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	comparator, err := newStopListMenuComparator(aggMenu, stopListItems, isByBalance, balanceLimit, aggregatorMenuIDExtractor{false, store.ID})
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	expected := expectedProductsAvailability(aggMenu, comparator)
+	report.Drifts = append(report.Drifts, menuDrifts(aggMenu, expected)...)
+
+	aggregatorService, err := s.aggregatorFactory.GetAggregator(menu.Delivery, store)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	if reader, ok := aggregatorService.(aggregator.ProductsAvailabilityReader); ok {
+		externalStoreIDs, err := s.storeService.GetStoreExternalIds(store, menu.Delivery)
+		if err != nil {
+			report.Error = err.Error()
+			return report
+		}
+
+		for _, externalStoreID := range externalStoreIDs {
+			availability, err := reader.GetProductsAvailability(ctx, externalStoreID)
+			if err != nil {
+				log.Err(err).Msgf("get products availability from %s, aggregator_store_id = %s", menu.Delivery, externalStoreID)
+				report.Error = err.Error()
+				continue
+			}
+			report.AggregatorChecked = true
+			report.Drifts = append(report.Drifts, aggregatorDrifts(aggMenu, expected, externalStoreID, availability)...)
+		}
+	}
+
+	if !heal || !report.HasDrift() {
+		return report
+	}
+
+	products := driftedProducts(aggMenu, comparator, report.Drifts)
+
+	if err = s.updateStopListByProductIDInAggregator(ctx, store, products, menu.Delivery, stopListItems); err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	if err = s.updateStopListByProductIDsInDatabase(ctx, menu.ID, products, comparator.disableReason()); err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	report.HealedProducts = len(products)
+
+	return report
+}
+
+// expectedProductsAvailability - доступность по кассе с учетом остальных причин отключения продукта, ключ - ext id
+func expectedProductsAvailability(menu *menuModels.Menu, comparator *stopListMenuComparator) map[string]bool {
+	expected := make(map[string]bool, len(menu.Products))
+
+	for _, product := range menu.Products {
+		if product.IsDeleted {
+			continue
+		}
+
+		posID, ok := comparator.idExtractor.getProductID(product)
+		if !ok {
+			continue
+		}
+
+		product.SetDisableReason(comparator.disableReason(), !comparator.isProductAvailable(posID))
+		expected[product.ExtID] = product.IsAvailable
+	}
+
+	return expected
+}
+
+func menuDrifts(menu *menuModels.Menu, expected map[string]bool) []StopListDrift {
+	drifts := make([]StopListDrift, 0)
+
+	for _, product := range menu.Products {
+		isAvailable, ok := expected[product.ExtID]
+		if !ok || product.IsAvailable == isAvailable {
+			continue
+		}
+
+		drifts = append(drifts, newStopListDrift(product, DriftSourceMenu, isAvailable))
+	}
+
+	return drifts
+}
+
+func aggregatorDrifts(menu *menuModels.Menu, expected map[string]bool, aggregatorStoreID string, availability map[string]bool) []StopListDrift {
+	drifts := make([]StopListDrift, 0)
+
+	for _, product := range menu.Products {
+		isAvailable, ok := expected[product.ExtID]
+		if !ok {
+			continue
+		}
+
+		aggregatorAvailable, ok := availability[product.ExtID]
+		if !ok || aggregatorAvailable == isAvailable {
+			continue
+		}
+
+		drift := newStopListDrift(product, DriftSourceAggregator, isAvailable)
+		drift.AggregatorStoreID = aggregatorStoreID
+		drift.AggregatorAvailable = &aggregatorAvailable
+		drifts = append(drifts, drift)
+	}
+
+	return drifts
+}
+
+func driftedProducts(menu *menuModels.Menu, comparator *stopListMenuComparator, drifts []StopListDrift) []menuModels.Product {
+	drifted := make(map[string]struct{}, len(drifts))
+	for _, drift := range drifts {
+		drifted[drift.ExtID] = struct{}{}
+	}
+
+	products := make([]menuModels.Product, 0, len(drifted))
+	for _, product := range menu.Products {
+		if _, ok := drifted[product.ExtID]; !ok {
+			continue
+		}
+		posID, _ := comparator.idExtractor.getProductID(product)
+		product.SetDisableReason(comparator.disableReason(), !comparator.isProductAvailable(posID))
+		product.Balance = comparator.stopListFromPos[posID].Balance
+		products = append(products, product)
+		delete(drifted, product.ExtID)
+	}
+
+	return products
+}
+
+func newStopListDrift(product menuModels.Product, source string, expectedAvailable bool) StopListDrift {
+	drift := StopListDrift{
+		ExtID:             product.ExtID,
+		PosID:             product.PosID,
+		Source:            source,
+		ExpectedAvailable: expectedAvailable,
+		MenuAvailable:     product.IsAvailable,
+	}
+	if len(product.Name) != 0 {
+		drift.Name = product.Name[0].Value
+	}
+	return drift
+}
@@ -0,0 +1,271 @@
+package stoplist
+
+import (
+	menuModels "github.com/kwaaka-team/orders-core/core/menu/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newReconcileComparator(t *testing.T, menu *menuModels.Menu, stopListItems []menuModels.StopListItem, isByBalance bool, balanceLimit float64) *stopListMenuComparator {
+	comparator, err := newStopListMenuComparator(menu, stopListItems, isByBalance, balanceLimit, aggregatorMenuIDExtractor{storeID: "store"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return comparator
+}
+
+func TestExpectedProductsAvailability(t *testing.T) {
+	tests := []struct {
+		name          string
+		products      []menuModels.Product
+		stopListItems []menuModels.StopListItem
+		isByBalance   bool
+		balanceLimit  float64
+		want          map[string]bool
+	}{
+		{
+			name: "product on pos stop list is not available",
+			products: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: true},
+				{ExtID: "cola", IsAvailable: true},
+			},
+			stopListItems: []menuModels.StopListItem{{ProductID: "burger"}},
+			want:          map[string]bool{"burger": false, "cola": true},
+		},
+		{
+			name: "product is matched by pos id",
+			products: []menuModels.Product{
+				{ExtID: "glovo_burger", PosID: "burger", IsAvailable: true},
+			},
+			stopListItems: []menuModels.StopListItem{{ProductID: "burger"}},
+			want:          map[string]bool{"glovo_burger": false},
+		},
+		{
+			name: "deleted product is skipped",
+			products: []menuModels.Product{
+				{ExtID: "burger", IsDeleted: true},
+			},
+			stopListItems: []menuModels.StopListItem{},
+			want:          map[string]bool{},
+		},
+		{
+			name: "product disabled by admin stays unavailable",
+			products: []menuModels.Product{
+				{ExtID: "burger", DisableReasons: []menuModels.DisableReason{menuModels.DisableReasonAdmin}},
+			},
+			stopListItems: []menuModels.StopListItem{},
+			want:          map[string]bool{"burger": false},
+		},
+		{
+			name: "product removed from pos stop list becomes available",
+			products: []menuModels.Product{
+				{ExtID: "burger", DisableReasons: []menuModels.DisableReason{menuModels.DisableReasonPosStopList}},
+			},
+			stopListItems: []menuModels.StopListItem{},
+			want:          map[string]bool{"burger": true},
+		},
+		{
+			name: "by balance product is available above limit",
+			products: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: true},
+				{ExtID: "cola", IsAvailable: true},
+			},
+			stopListItems: []menuModels.StopListItem{
+				{ProductID: "burger", Balance: 2},
+				{ProductID: "cola", Balance: 5},
+			},
+			isByBalance:  true,
+			balanceLimit: 3,
+			want:         map[string]bool{"burger": false, "cola": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			menu := &menuModels.Menu{Products: tt.products}
+			comparator := newReconcileComparator(t, menu, tt.stopListItems, tt.isByBalance, tt.balanceLimit)
+
+			assert.Equal(t, tt.want, expectedProductsAvailability(menu, comparator))
+		})
+	}
+}
+
+func TestMenuDrifts(t *testing.T) {
+	tests := []struct {
+		name     string
+		products []menuModels.Product
+		expected map[string]bool
+		want     []StopListDrift
+	}{
+		{
+			name: "available in menu but stopped in pos",
+			products: []menuModels.Product{
+				{ExtID: "burger", PosID: "pos_burger", Name: []menuModels.LanguageDescription{{Value: "Бургер"}}, IsAvailable: true},
+			},
+			expected: map[string]bool{"burger": false},
+			want: []StopListDrift{
+				{ExtID: "burger", PosID: "pos_burger", Name: "Бургер", Source: DriftSourceMenu, ExpectedAvailable: false, MenuAvailable: true},
+			},
+		},
+		{
+			name: "stopped in menu but available in pos",
+			products: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: false},
+			},
+			expected: map[string]bool{"burger": true},
+			want: []StopListDrift{
+				{ExtID: "burger", Source: DriftSourceMenu, ExpectedAvailable: true, MenuAvailable: false},
+			},
+		},
+		{
+			name: "matching availability is not a drift",
+			products: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: true},
+				{ExtID: "cola", IsAvailable: false},
+			},
+			expected: map[string]bool{"burger": true, "cola": false},
+			want:     []StopListDrift{},
+		},
+		{
+			name: "product without expectation is skipped",
+			products: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: true, IsDeleted: true},
+			},
+			expected: map[string]bool{},
+			want:     []StopListDrift{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, menuDrifts(&menuModels.Menu{Products: tt.products}, tt.expected))
+		})
+	}
+}
+
+func TestAggregatorDrifts(t *testing.T) {
+	available, unavailable := true, false
+
+	tests := []struct {
+		name         string
+		products     []menuModels.Product
+		expected     map[string]bool
+		availability map[string]bool
+		want         []StopListDrift
+	}{
+		{
+			name: "available at aggregator but stopped in pos",
+			products: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: false},
+			},
+			expected:     map[string]bool{"burger": false},
+			availability: map[string]bool{"burger": true},
+			want: []StopListDrift{
+				{ExtID: "burger", Source: DriftSourceAggregator, AggregatorStoreID: "glovo_store", ExpectedAvailable: false, MenuAvailable: false, AggregatorAvailable: &available},
+			},
+		},
+		{
+			name: "stopped at aggregator but available in pos",
+			products: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: true},
+			},
+			expected:     map[string]bool{"burger": true},
+			availability: map[string]bool{"burger": false},
+			want: []StopListDrift{
+				{ExtID: "burger", Source: DriftSourceAggregator, AggregatorStoreID: "glovo_store", ExpectedAvailable: true, MenuAvailable: true, AggregatorAvailable: &unavailable},
+			},
+		},
+		{
+			name: "product unknown to aggregator is skipped",
+			products: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: true},
+			},
+			expected:     map[string]bool{"burger": false},
+			availability: map[string]bool{},
+			want:         []StopListDrift{},
+		},
+		{
+			name: "matching availability is not a drift",
+			products: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: true},
+			},
+			expected:     map[string]bool{"burger": true},
+			availability: map[string]bool{"burger": true},
+			want:         []StopListDrift{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, aggregatorDrifts(&menuModels.Menu{Products: tt.products}, tt.expected, "glovo_store", tt.availability))
+		})
+	}
+}
+
+func TestDriftedProducts(t *testing.T) {
+	tests := []struct {
+		name          string
+		products      []menuModels.Product
+		stopListItems []menuModels.StopListItem
+		isByBalance   bool
+		drifts        []StopListDrift
+		want          []menuModels.Product
+	}{
+		{
+			name: "drifted product gets pos reason and balance",
+			products: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: true},
+				{ExtID: "cola", IsAvailable: true},
+			},
+			stopListItems: []menuModels.StopListItem{{ProductID: "burger", Balance: 0}},
+			isByBalance:   true,
+			drifts:        []StopListDrift{{ExtID: "burger"}},
+			want: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: false, DisableReasons: []menuModels.DisableReason{menuModels.DisableReasonBalance}},
+			},
+		},
+		{
+			name: "pos reason is removed from product available in pos",
+			products: []menuModels.Product{
+				{ExtID: "burger", DisableReasons: []menuModels.DisableReason{menuModels.DisableReasonPosStopList}},
+			},
+			stopListItems: []menuModels.StopListItem{},
+			drifts:        []StopListDrift{{ExtID: "burger"}},
+			want: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: true, DisableReasons: []menuModels.DisableReason{}},
+			},
+		},
+		{
+			name: "product drifted in menu and at aggregator is healed once",
+			products: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: true},
+			},
+			stopListItems: []menuModels.StopListItem{{ProductID: "burger"}},
+			drifts: []StopListDrift{
+				{ExtID: "burger", Source: DriftSourceMenu},
+				{ExtID: "burger", Source: DriftSourceAggregator},
+			},
+			want: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: false, DisableReasons: []menuModels.DisableReason{menuModels.DisableReasonPosStopList}},
+			},
+		},
+		{
+			name: "no drifts",
+			products: []menuModels.Product{
+				{ExtID: "burger", IsAvailable: true},
+			},
+			stopListItems: []menuModels.StopListItem{},
+			drifts:        []StopListDrift{},
+			want:          []menuModels.Product{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			menu := &menuModels.Menu{Products: tt.products}
+			comparator := newReconcileComparator(t, menu, tt.stopListItems, tt.isByBalance, 0)
+
+			assert.Equal(t, tt.want, driftedProducts(menu, comparator, tt.drifts))
+		})
+	}
+}
@@ -9,7 +9,7 @@ import (
 
 type stopListType interface {
 	filterProducts(products []menuModels.Product) []menuModels.Product
-	updateDisabledStatusByProductIDsInDatabase(ctx context.Context, menuID string, products []menuModels.Product) error
+	productDisableReason() menuModels.DisableReason
 
 	filterAttributes(attributes []menuModels.Attribute) []menuModels.Attribute
 	updateDisabledStatusByAttributeIDsInDatabase(ctx context.Context, menuID string, attributes []menuModels.Attribute) error
@@ -32,32 +32,9 @@ func (s *cronStopList) filterProducts(products []menuModels.Product) []menuModel
 	return products
 }
 
-func (s *cronStopList) updateDisabledStatusByProductIDsInDatabase(ctx context.Context, menuID string, products []menuModels.Product) error {
-	var (
-		productIdsWithDisabledFalse = make([]string, 0, len(products))
-		productIdsWithDisabledTrue  = make([]string, 0, len(products))
-	)
-
-	for _, product := range products {
-		if product.IsAvailable {
-			productIdsWithDisabledFalse = append(productIdsWithDisabledFalse, product.ExtID)
-		} else {
-			productIdsWithDisabledTrue = append(productIdsWithDisabledTrue, product.ExtID)
-		}
-	}
-
-	if len(productIdsWithDisabledTrue) != 0 {
-		if err := s.menuService.UpdateProductsDisabledStatus(ctx, menuID, productIdsWithDisabledTrue, true); err != nil {
-			return err
-		}
-	}
-
-	if len(productIdsWithDisabledFalse) != 0 {
-		if err := s.menuService.UpdateProductsDisabledStatus(ctx, menuID, productIdsWithDisabledFalse, false); err != nil {
-			return err
-		}
-	}
-	return nil
+// productDisableReason - продукт выключает стоп-лист кассы, причины секции и админа остаются нетронутыми
+func (s *cronStopList) productDisableReason() menuModels.DisableReason {
+	return menuModels.DisableReasonPosStopList
 }
 
 func (s *cronStopList) filterAttributes(attributes []menuModels.Attribute) []menuModels.Attribute {
@@ -100,16 +77,9 @@ func newWebhookStopList() (*webhookStopList, error) {
 	return &webhookStopList{}, nil
 }
 
+// filterProducts does not skip disabled products: reason of pos is saved separately and does not enable them
 func (s *webhookStopList) filterProducts(products []menuModels.Product) []menuModels.Product {
-	result := make([]menuModels.Product, 0)
-	for i := range products {
-		product := products[i]
-		if product.IsDisabled {
-			continue
-		}
-		result = append(result, product)
-	}
-	return result
+	return products
 }
 
 func (s *webhookStopList) updateDisabledStatusByAttributeIDsInDatabase(ctx context.Context, menuID string, attributes []menuModels.Attribute) error {
@@ -128,8 +98,8 @@ func (s *webhookStopList) filterAttributes(attributes []menuModels.Attribute) []
 	return result
 }
 
-func (s *webhookStopList) updateDisabledStatusByProductIDsInDatabase(ctx context.Context, menuID string, products []menuModels.Product) error {
-	return nil
+func (s *webhookStopList) productDisableReason() menuModels.DisableReason {
+	return menuModels.DisableReasonPosStopList
 }
 
 type validateStopList struct{}
@@ -148,6 +118,6 @@ func (s *validateStopList) updateDisabledStatusByAttributeIDsInDatabase(ctx cont
 func (s *validateStopList) filterAttributes(attributes []menuModels.Attribute) []menuModels.Attribute {
 	return attributes
 }
-func (s *validateStopList) updateDisabledStatusByProductIDsInDatabase(ctx context.Context, menuID string, products []menuModels.Product) error {
-	return nil
+func (s *validateStopList) productDisableReason() menuModels.DisableReason {
+	return menuModels.DisableReasonValidation
 }
@@ -686,6 +686,34 @@ func (_m *Service) IsMarketplace(_a0 storecoremodels.Store, deliveryService stri
 	return r0, r1
 }
 
+// IsPostAutoAccept provides a mock function with given fields: _a0, deliveryService
+func (_m *Service) IsPostAutoAccept(_a0 storecoremodels.Store, deliveryService string) (bool, error) {
+	ret := _m.Called(_a0, deliveryService)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsPostAutoAccept")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(storecoremodels.Store, string) (bool, error)); ok {
+		return rf(_a0, deliveryService)
+	}
+	if rf, ok := ret.Get(0).(func(storecoremodels.Store, string) bool); ok {
+		r0 = rf(_a0, deliveryService)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(storecoremodels.Store, string) error); ok {
+		r1 = rf(_a0, deliveryService)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsSecretValid provides a mock function with given fields: _a0, deliveryService, secret
 func (_m *Service) IsSecretValid(_a0 storecoremodels.Store, deliveryService string, secret string) (bool, error) {
 	ret := _m.Called(_a0, deliveryService, secret)
@@ -742,6 +770,34 @@ func (_m *Service) IsSendToPos(_a0 storecoremodels.Store, deliveryService string
 	return r0, r1
 }
 
+// QuoteDelivery provides a mock function with given fields: ctx, restaurantID, point, basket
+func (_m *Service) QuoteDelivery(ctx context.Context, restaurantID string, point storecoremodels.Coordinates, basket float64) (storecoremodels.DeliveryQuote, error) {
+	ret := _m.Called(ctx, restaurantID, point, basket)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QuoteDelivery")
+	}
+
+	var r0 storecoremodels.DeliveryQuote
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, storecoremodels.Coordinates, float64) (storecoremodels.DeliveryQuote, error)); ok {
+		return rf(ctx, restaurantID, point, basket)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, storecoremodels.Coordinates, float64) storecoremodels.DeliveryQuote); ok {
+		r0 = rf(ctx, restaurantID, point, basket)
+	} else {
+		r0 = ret.Get(0).(storecoremodels.DeliveryQuote)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, storecoremodels.Coordinates, float64) error); ok {
+		r1 = rf(ctx, restaurantID, point, basket)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetActualRkeeper7xmlSeqNumber provides a mock function with given fields: ctx, storeID, seqNumber
 func (_m *Service) SetActualRkeeper7xmlSeqNumber(ctx context.Context, storeID string, seqNumber string) error {
 	ret := _m.Called(ctx, storeID, seqNumber)
@@ -760,6 +816,24 @@ func (_m *Service) SetActualRkeeper7xmlSeqNumber(ctx context.Context, storeID st
 	return r0
 }
 
+// UpdateAggregatorCommissions provides a mock function with given fields: ctx, storeId, contracts
+func (_m *Service) UpdateAggregatorCommissions(ctx context.Context, storeId string, contracts storecoremodels.AggregatorCommissionContracts) error {
+	ret := _m.Called(ctx, storeId, contracts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAggregatorCommissions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, storecoremodels.AggregatorCommissionContracts) error); ok {
+		r0 = rf(ctx, storeId, contracts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateKwaakaAdminBusyMode provides a mock function with given fields: ctx, req
 func (_m *Service) UpdateKwaakaAdminBusyMode(ctx context.Context, req []dto.BusyModeRequest) error {
 	ret := _m.Called(ctx, req)
@@ -778,6 +852,24 @@ func (_m *Service) UpdateKwaakaAdminBusyMode(ctx context.Context, req []dto.Busy
 	return r0
 }
 
+// UpdateMarkupRules provides a mock function with given fields: ctx, storeId, rules
+func (_m *Service) UpdateMarkupRules(ctx context.Context, storeId string, rules storecoremodels.MarkupRules) error {
+	ret := _m.Called(ctx, storeId, rules)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMarkupRules")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, storecoremodels.MarkupRules) error); ok {
+		r0 = rf(ctx, storeId, rules)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateMenuId provides a mock function with given fields: ctx, storeId, menuId
 func (_m *Service) UpdateMenuId(ctx context.Context, storeId string, menuId string) error {
 	ret := _m.Called(ctx, storeId, menuId)
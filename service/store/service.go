@@ -36,9 +36,12 @@ type Service interface {
 	AddMenuObjectToMenus(ctx context.Context, storeId, deliveryService, menuId string) error
 	GetStoresInRestGroupByName(ctx context.Context, restaurantGroupId, name string, legalEntities []string) ([]models.Store, error)
 	UpdateMenuId(ctx context.Context, storeId, menuId string) error
+	UpdateMarkupRules(ctx context.Context, storeId string, rules models.MarkupRules) error
+	UpdateAggregatorCommissions(ctx context.Context, storeId string, contracts models.AggregatorCommissionContracts) error
 	CreatePolygon(ctx context.Context, request kwaakaAdminModels.PolygonRequest) error
 	UpdatePolygon(ctx context.Context, request kwaakaAdminModels.PolygonRequest) error
 	GetPolygonByRestaurantID(ctx context.Context, restaurantID string) (kwaakaAdminModels.GetPolygonResponse, error)
+	QuoteDelivery(ctx context.Context, restaurantID string, point models.Coordinates, basket float64) (models.DeliveryQuote, error)
 	GetTwoGisReviewLink(ctx context.Context, restaurantID string) (string, error)
 	CreateStorePhoneEmail(ctx context.Context, restaurantID string, request kwaakaAdminModels.StorePhoneEmail) error
 	UpdateKwaakaAdminBusyMode(ctx context.Context, req []dto.BusyModeRequest) error
@@ -200,6 +203,20 @@ func (s *ServiceImpl) UpdateMenuId(ctx context.Context, storeId, menuId string)
 	return s.storeRepository.UpdateMenuId(ctx, storeId, menuId)
 }
 
+func (s *ServiceImpl) UpdateMarkupRules(ctx context.Context, storeId string, rules models.MarkupRules) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+	return s.storeRepository.UpdateMarkupRules(ctx, storeId, rules)
+}
+
+func (s *ServiceImpl) UpdateAggregatorCommissions(ctx context.Context, storeId string, contracts models.AggregatorCommissionContracts) error {
+	if err := contracts.Validate(); err != nil {
+		return err
+	}
+	return s.storeRepository.UpdateAggregatorCommissions(ctx, storeId, contracts)
+}
+
 func (s *ServiceImpl) GetByID(ctx context.Context, storeID string) (models.Store, error) {
 	store, err := s.storeRepository.GetById(ctx, storeID)
 	if err != nil {
@@ -427,6 +444,15 @@ func (s *ServiceImpl) GetPolygonByRestaurantID(ctx context.Context, restaurantID
 	return result, nil
 }
 
+func (s *ServiceImpl) QuoteDelivery(ctx context.Context, restaurantID string, point models.Coordinates, basket float64) (models.DeliveryQuote, error) {
+	store, err := s.storeRepository.GetById(ctx, restaurantID)
+	if err != nil {
+		return models.DeliveryQuote{}, err
+	}
+
+	return store.Kwaaka3PL.QuoteDelivery(point, basket)
+}
+
 func (s *ServiceImpl) GetTwoGisReviewLink(ctx context.Context, restaurantID string) (string, error) {
 	store, err := s.storeRepository.GetById(ctx, restaurantID)
 	if err != nil {
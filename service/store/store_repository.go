@@ -35,6 +35,8 @@ type Repository interface {
 	FindStoreInRestGroupByName(ctx context.Context, name string, restaurantGroupId string) ([]models.Store, error)
 	UpdateStoreSchedule(ctx context.Context, storeId string, schedule models.AggregatorSchedule, queryPrefix string) error
 	UpdateMenuId(ctx context.Context, storeId, menuId string) error
+	UpdateMarkupRules(ctx context.Context, storeId string, rules models.MarkupRules) error
+	UpdateAggregatorCommissions(ctx context.Context, storeId string, contracts models.AggregatorCommissionContracts) error
 	CreatePolygon(ctx context.Context, restaurantID string, request models.Geometry) error
 	UpdatePolygon(ctx context.Context, restaurantID string, request models.Geometry) error
 	GetPolygonByRestaurantID(ctx context.Context, restaurantID string) (models.Geometry, error)
@@ -153,6 +155,60 @@ func (r *MongoRepository) UpdateMenuId(ctx context.Context, storeId, menuId stri
 	return nil
 }
 
+func (r *MongoRepository) UpdateMarkupRules(ctx context.Context, storeId string, rules models.MarkupRules) error {
+	filter, err := r.filterFrom(selector.NewEmptyStoreSearch().SetID(storeId))
+	if err != nil {
+		return errorSwitch(err)
+	}
+
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "markup_rules", Value: rules},
+			},
+		},
+	}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return errors.New("matched count is equal 0, not found")
+	}
+
+	return nil
+}
+
+func (r *MongoRepository) UpdateAggregatorCommissions(ctx context.Context, storeId string, contracts models.AggregatorCommissionContracts) error {
+	filter, err := r.filterFrom(selector.NewEmptyStoreSearch().SetID(storeId))
+	if err != nil {
+		return errorSwitch(err)
+	}
+
+	update := bson.D{
+		{
+			Key: "$set",
+			Value: bson.D{
+				{Key: "aggregator_commissions", Value: contracts},
+			},
+		},
+	}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return errors.New("matched count is equal 0, not found")
+	}
+
+	return nil
+}
+
 func (r *MongoRepository) UpdateStoreSchedule(ctx context.Context, storeId string, schedule models.AggregatorSchedule, queryPrefix string) error {
 	filter, err := r.filterFrom(selector.NewEmptyStoreSearch().SetID(storeId))
 	if err != nil {
@@ -374,6 +430,13 @@ func (r *MongoRepository) filterFrom(query selector.Store) (bson.D, error) {
 		})
 	}
 
+	if query.HasLegalEntityID() {
+		result = append(result, bson.E{
+			Key:   "legal_entity_id",
+			Value: query.LegalEntityID,
+		})
+	}
+
 	if query.HasLanguageCode() {
 		result = append(result, bson.E{
 			Key:   "settings.language_code",
@@ -0,0 +1,301 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kwaaka-team/orders-core/core/models"
+	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
+	"github.com/kwaaka-team/orders-core/pkg/whatsapp"
+	"github.com/kwaaka-team/orders-core/pkg/whatsapp/clients"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	newsletterSendTimeLayout = "2006-01-02 15:04"
+	// newsletterSendInterval - пауза между сообщениями рассылки, чтобы не попасть под ограничения whatsapp
+	newsletterSendInterval = time.Second
+	// newsletterMessagesPerRun - сколько сообщений отправляется за один запуск крона, остальные - в следующих запусках
+	newsletterMessagesPerRun = 20
+	newsletterLock           = 2 * time.Minute
+
+	newsletterOptOutReply = "Вы отписались от рассылок. Уведомления о ваших заказах продолжат приходить."
+)
+
+// ScheduleNewsletter сохраняет рассылку, получатели определяются по сегменту в момент отправки
+func (s *ServiceImpl) ScheduleNewsletter(ctx context.Context, req models.ScheduleNewsletterRequest) (models.Newsletter, error) {
+	if strings.TrimSpace(req.Text) == "" {
+		return models.Newsletter{}, errors.New("newsletter text is empty")
+	}
+	if req.Segment.MaxOrdersCount > 0 && req.Segment.MaxOrdersCount < req.Segment.MinOrdersCount {
+		return models.Newsletter{}, errors.New("max orders count is less than min orders count")
+	}
+
+	if _, err := s.storeGroupService.GetStoreGroupByID(ctx, req.RestaurantGroupID); err != nil {
+		return models.Newsletter{}, err
+	}
+
+	now := time.Now().UTC()
+	sendDate := now
+	if req.SendTime != "" {
+		stores, err := s.StoreService.GetStoresByStoreGroupID(ctx, req.RestaurantGroupID)
+		if err != nil {
+			return models.Newsletter{}, err
+		}
+		location := time.UTC
+		if len(stores) != 0 {
+			location = stores[0].Settings.TimeZone.Location()
+		}
+
+		local, err := time.ParseInLocation(newsletterSendTimeLayout, req.SendTime, location)
+		if err != nil {
+			return models.Newsletter{}, err
+		}
+		sendDate = local.UTC()
+	}
+
+	newsletter := models.Newsletter{
+		ID:                uuid.New().String(),
+		Name:              req.Name,
+		RestaurantGroupId: req.RestaurantGroupID,
+		Text:              req.Text,
+		PromoCode:         req.PromoCode,
+		Segment:           req.Segment,
+		Recipients:        []string{},
+		Deliveries:        []models.NewsletterDelivery{},
+		Status:            Scheduled,
+		SendDate:          sendDate,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	if err := s.NewsletterRepo.CreateNewsletter(ctx, newsletter); err != nil {
+		return models.Newsletter{}, err
+	}
+
+	return newsletter, nil
+}
+
+func (s *ServiceImpl) GetNewsletter(ctx context.Context, id string) (models.Newsletter, error) {
+	return s.NewsletterRepo.GetNewsletter(ctx, id)
+}
+
+func (s *ServiceImpl) GetNewsletters(ctx context.Context, restGroupId string) ([]models.Newsletter, error) {
+	return s.NewsletterRepo.GetNewslettersByRestaurantGroup(ctx, restGroupId)
+}
+
+// SendScheduledNewsletters отправляет рассылки, время которых наступило, не больше newsletterMessagesPerRun сообщений за запуск.
+// Рассылка с ошибкой остается заблокированной до истечения блокировки и повторяется следующим запуском
+func (s *ServiceImpl) SendScheduledNewsletters(ctx context.Context, now time.Time) ([]models.Newsletter, error) {
+	res := make([]models.Newsletter, 0)
+
+	budget := newsletterMessagesPerRun
+	for budget > 0 {
+		newsletter, ok, err := s.NewsletterRepo.ClaimDueNewsletter(ctx, []string{Scheduled, Sending}, now, newsletterLock)
+		if err != nil {
+			return res, err
+		}
+		if !ok {
+			break
+		}
+
+		newsletter, sent, err := s.sendNewsletter(ctx, newsletter, now, budget)
+		if err != nil {
+			log.Err(err).Msgf("send newsletter %s error", newsletter.ID)
+			continue
+		}
+		budget -= sent
+
+		newsletter.Deliveries = nil
+		res = append(res, newsletter)
+	}
+
+	return res, nil
+}
+
+func (s *ServiceImpl) sendNewsletter(ctx context.Context, newsletter models.Newsletter, now time.Time, budget int) (models.Newsletter, int, error) {
+	if newsletter.Status == Scheduled {
+		deliveries, err := s.newsletterDeliveries(ctx, newsletter, now)
+		if err != nil {
+			return newsletter, 0, err
+		}
+
+		recipients := make([]string, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			recipients = append(recipients, delivery.Phone)
+		}
+
+		if err := s.NewsletterRepo.SetDeliveries(ctx, newsletter.ID, recipients, deliveries, Sending); err != nil {
+			return newsletter, 0, err
+		}
+		newsletter.Recipients = recipients
+		newsletter.Deliveries = deliveries
+		newsletter.Status = Sending
+	}
+
+	optedOut, err := s.NewsletterRepo.GetOptedOutPhones(ctx, newsletter.RestaurantGroupId)
+	if err != nil {
+		return newsletter, 0, err
+	}
+
+	wppClient, err := s.newsletterClient(ctx, newsletter.RestaurantGroupId)
+	if err != nil {
+		return newsletter, 0, err
+	}
+
+	sent := 0
+	pending := 0
+	for i, delivery := range newsletter.Deliveries {
+		if delivery.Status != models.NewsletterDeliveryPending {
+			continue
+		}
+		if sent == budget {
+			pending++
+			continue
+		}
+
+		if optedOut[delivery.Phone] {
+			delivery.Status = models.NewsletterDeliveryOptedOut
+		} else {
+			if sent != 0 {
+				select {
+				case <-ctx.Done():
+					return newsletter, sent, ctx.Err()
+				case <-time.After(newsletterSendInterval):
+				}
+			}
+
+			text := models.RenderNewsletterText(newsletter.Text, delivery.Name, newsletter.PromoCode)
+			if err := wppClient.SendMessage(ctx, strings.TrimPrefix(delivery.Phone, "+"), url.QueryEscape(text)); err != nil {
+				delivery.Status = models.NewsletterDeliveryFailed
+				delivery.Error = err.Error()
+			} else {
+				delivery.Status = models.NewsletterDeliverySent
+			}
+			delivery.SentAt = time.Now().UTC()
+			sent++
+		}
+
+		if err := s.NewsletterRepo.UpdateDelivery(ctx, newsletter.ID, i, delivery); err != nil {
+			return newsletter, sent, err
+		}
+		newsletter.Deliveries[i] = delivery
+	}
+
+	if pending == 0 {
+		newsletter.Status = Completed
+	}
+	if err := s.NewsletterRepo.ReleaseNewsletter(ctx, newsletter.ID, newsletter.Status); err != nil {
+		return newsletter, sent, err
+	}
+
+	return newsletter, sent, nil
+}
+
+func (s *ServiceImpl) newsletterDeliveries(ctx context.Context, newsletter models.Newsletter, now time.Time) ([]models.NewsletterDelivery, error) {
+	if s.customerSegmenter == nil {
+		return nil, errors.New("customer segmenter is nil")
+	}
+
+	customers, err := s.customerSegmenter.GetGroupCustomers(ctx, newsletter.RestaurantGroupId, models.QRMENU.String(), newsletter.Segment.OrdersFrom(now), now)
+	if err != nil {
+		return nil, err
+	}
+
+	var stores map[string]bool
+	if newsletter.Segment.HasStores() {
+		groupStores, err := s.StoreService.GetStoresByStoreGroupID(ctx, newsletter.RestaurantGroupId)
+		if err != nil {
+			return nil, err
+		}
+		stores = segmentStores(newsletter.Segment, groupStores)
+	}
+
+	return models.NewsletterDeliveries(customers, newsletter.Segment, stores, now), nil
+}
+
+// segmentStores - рестораны группы из сегмента по id или городу
+func segmentStores(segment models.NewsletterSegment, groupStores []storeModels.Store) map[string]bool {
+	stores := make(map[string]bool)
+	for _, st := range groupStores {
+		for _, storeID := range segment.StoreIDs {
+			if st.ID == storeID {
+				stores[st.ID] = true
+			}
+		}
+		for _, city := range segment.Cities {
+			if strings.EqualFold(strings.TrimSpace(st.City), strings.TrimSpace(city)) {
+				stores[st.ID] = true
+			}
+		}
+	}
+	return stores
+}
+
+func (s *ServiceImpl) newsletterClient(ctx context.Context, restGroupId string) (clients.Whatsapp, error) {
+	restGroup, err := s.storeGroupService.GetStoreGroupByID(ctx, restGroupId)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceId, authToken, exist, err := s.getWppSettingsIfExist(ctx, restGroup)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return s.WhatsappClient, nil
+	}
+
+	return whatsapp.NewWhatsappClient(&clients.Config{
+		Insecure:  true,
+		Protocol:  "http",
+		Instance:  instanceId,
+		AuthToken: authToken,
+	})
+}
+
+// optOutFromNewsletters отписывает клиента от рассылок групп ресторанов, на whatsapp номер которых он ответил.
+// Если ресторан по номеру не найден - от всех рассылок
+func (s *ServiceImpl) optOutFromNewsletters(ctx context.Context, from, to string) error {
+	phone := models.NormalizePhone(strings.Split(from, "@")[0])
+	if phone == "" {
+		return errors.New("invalid opt out phone")
+	}
+
+	stores, err := s.StoreService.GetStoresByWppPhoneNum(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	groups := map[string]bool{}
+	for _, st := range stores {
+		groups[st.RestaurantGroupID] = true
+	}
+	if len(groups) == 0 {
+		groups[""] = true
+	}
+
+	for restGroupId := range groups {
+		if err := s.NewsletterRepo.OptOut(ctx, models.NewsletterOptOut{
+			Phone:             phone,
+			RestaurantGroupID: restGroupId,
+			CreatedAt:         time.Now().UTC(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	var storeId string
+	if len(stores) != 0 {
+		storeId = stores[0].ID
+	}
+	if err := s.SendMessage(ctx, from, url.QueryEscape(newsletterOptOutReply), storeId); err != nil {
+		log.Err(err).Msgf("send newsletter opt out reply to %s error", from)
+	}
+
+	return nil
+}
@@ -2,21 +2,32 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"time"
+
 	"github.com/kwaaka-team/orders-core/core/models"
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
-	NewsletterCollection = "newsletter"
+	NewsletterCollection       = "newsletter"
+	NewsletterOptOutCollection = "newsletter_opt_outs"
 )
 
+var ErrNewsletterNotFound = errors.New("newsletter not found")
+
 type Repository struct {
-	collection *mongo.Collection
+	collection       *mongo.Collection
+	optOutCollection *mongo.Collection
 }
 
 func NewNewsletterRepository(db *mongo.Database) *Repository {
 	return &Repository{
-		collection: db.Collection(NewsletterCollection),
+		collection:       db.Collection(NewsletterCollection),
+		optOutCollection: db.Collection(NewsletterOptOutCollection),
 	}
 }
 
@@ -26,3 +37,135 @@ func (r *Repository) CreateNewsletter(ctx context.Context, newsletter models.New
 	}
 	return nil
 }
+
+func (r *Repository) GetNewsletter(ctx context.Context, id string) (models.Newsletter, error) {
+	var newsletter models.Newsletter
+	if err := r.collection.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&newsletter); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.Newsletter{}, ErrNewsletterNotFound
+		}
+		return models.Newsletter{}, err
+	}
+	return newsletter, nil
+}
+
+func (r *Repository) GetNewslettersByRestaurantGroup(ctx context.Context, restGroupId string) ([]models.Newsletter, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "send_date", Value: -1}}).
+		SetProjection(bson.D{{Key: "deliveries", Value: 0}})
+
+	cursor, err := r.collection.Find(ctx, bson.D{{Key: "restaurant_group_id", Value: restGroupId}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	newsletters := make([]models.Newsletter, 0)
+	if err := cursor.All(ctx, &newsletters); err != nil {
+		return nil, err
+	}
+	return newsletters, nil
+}
+
+// ClaimDueNewsletter блокирует на lockFor одну рассылку, время отправки которой наступило.
+// false - рассылок к отправке нет
+func (r *Repository) ClaimDueNewsletter(ctx context.Context, statuses []string, now time.Time, lockFor time.Duration) (models.Newsletter, bool, error) {
+	filter := bson.D{
+		{Key: "status", Value: bson.D{{Key: "$in", Value: statuses}}},
+		{Key: "send_date", Value: bson.D{{Key: "$lte", Value: now}}},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "locked_until", Value: bson.D{{Key: "$exists", Value: false}}}},
+			bson.D{{Key: "locked_until", Value: bson.D{{Key: "$lt", Value: now}}}},
+		}},
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "locked_until", Value: now.Add(lockFor)}}},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "send_date", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var newsletter models.Newsletter
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&newsletter); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.Newsletter{}, false, nil
+		}
+		return models.Newsletter{}, false, err
+	}
+	return newsletter, true, nil
+}
+
+func (r *Repository) SetDeliveries(ctx context.Context, id string, recipients []string, deliveries []models.NewsletterDelivery, status string) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "recipients", Value: recipients},
+			{Key: "deliveries", Value: deliveries},
+			{Key: "status", Value: status},
+			{Key: "updated_at", Value: time.Now().UTC()},
+		}},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: id}}, update)
+	return err
+}
+
+func (r *Repository) UpdateDelivery(ctx context.Context, id string, index int, delivery models.NewsletterDelivery) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: fmt.Sprintf("deliveries.%d", index), Value: delivery},
+			{Key: "updated_at", Value: time.Now().UTC()},
+		}},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: id}}, update)
+	return err
+}
+
+// ReleaseNewsletter снимает блокировку рассылки и выставляет статус
+func (r *Repository) ReleaseNewsletter(ctx context.Context, id string, status string) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "status", Value: status},
+			{Key: "updated_at", Value: time.Now().UTC()},
+		}},
+		{Key: "$unset", Value: bson.D{{Key: "locked_until", Value: ""}}},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: id}}, update)
+	return err
+}
+
+func (r *Repository) OptOut(ctx context.Context, optOut models.NewsletterOptOut) error {
+	filter := bson.D{
+		{Key: "phone", Value: optOut.Phone},
+		{Key: "restaurant_group_id", Value: optOut.RestaurantGroupID},
+	}
+	update := bson.D{
+		{Key: "$setOnInsert", Value: optOut},
+	}
+
+	_, err := r.optOutCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// GetOptedOutPhones - телефоны, отписанные от рассылок группы или от всех рассылок
+func (r *Repository) GetOptedOutPhones(ctx context.Context, restGroupId string) (map[string]bool, error) {
+	filter := bson.D{
+		{Key: "restaurant_group_id", Value: bson.D{{Key: "$in", Value: bson.A{restGroupId, ""}}}},
+	}
+
+	cursor, err := r.optOutCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var optOuts []models.NewsletterOptOut
+	if err := cursor.All(ctx, &optOuts); err != nil {
+		return nil, err
+	}
+
+	phones := make(map[string]bool, len(optOuts))
+	for _, optOut := range optOuts {
+		phones[optOut.Phone] = true
+	}
+	return phones, nil
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/kwaaka-team/orders-core/core/models"
 	storeModels "github.com/kwaaka-team/orders-core/core/storecore/models"
 	"github.com/kwaaka-team/orders-core/pkg/order"
-	"github.com/kwaaka-team/orders-core/pkg/order/dto"
 	"github.com/kwaaka-team/orders-core/pkg/whatsapp"
 	"github.com/kwaaka-team/orders-core/pkg/whatsapp/clients"
 	paymentModels "github.com/kwaaka-team/orders-core/service/payment/models"
@@ -21,12 +20,13 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
-	"sync"
 	"time"
 )
 
 const (
 	Pending           = "Pending"
+	Scheduled         = "Scheduled"
+	Sending           = "Sending"
 	Completed         = "Completed"
 	autoReplyCooldown = 3 * time.Hour
 )
@@ -34,15 +34,24 @@ const (
 var ErrWpp = errors.New("whatsapp success status")
 
 type Service interface {
-	SendNewsletter(ctx context.Context, restGroupId string, text string, name string) error
+	ScheduleNewsletter(ctx context.Context, req models.ScheduleNewsletterRequest) (models.Newsletter, error)
+	SendScheduledNewsletters(ctx context.Context, now time.Time) ([]models.Newsletter, error)
+	GetNewsletter(ctx context.Context, id string) (models.Newsletter, error)
+	GetNewsletters(ctx context.Context, restGroupId string) ([]models.Newsletter, error)
 	SendMessage(ctx context.Context, to, message, storeId string) error
 	GetSystemWebhookEventRequestByPaymentSystemRequest(r interface{}) (paymentModels.WebhookEvent, error)
 	SendFilePdf(ctx context.Context, to, fileName, message string, pdfFile []byte) error
 	SendMessageFromBaseEnvs(ctx context.Context, to, message string) error
 }
 
+// CustomerSegmenter - профили клиентов группы ресторанов для сегментов рассылок, реализуется customer.Service
+type CustomerSegmenter interface {
+	GetGroupCustomers(ctx context.Context, restaurantGroupID, deliveryService string, from, to time.Time) ([]models.CustomerGroupProfile, error)
+}
+
 type ServiceImpl struct {
 	NewsletterRepo    *repository.Repository
+	customerSegmenter CustomerSegmenter
 	WhatsappClient    clients.Whatsapp
 	StoreService      store.Service
 	storeGroupService storeGroupServicePkg.Service
@@ -53,9 +62,10 @@ type ServiceImpl struct {
 	BaseUrl           string
 }
 
-func NewWhatsappService(wsClient clients.Whatsapp, Instance, AuthToken, BaseUrl string, newsletterRepo *repository.Repository, StoreService store.Service, OrderService order.Client, storeGroupService storeGroupServicePkg.Service, redisClient *redis.Client) (Service, error) {
+func NewWhatsappService(wsClient clients.Whatsapp, Instance, AuthToken, BaseUrl string, newsletterRepo *repository.Repository, StoreService store.Service, OrderService order.Client, storeGroupService storeGroupServicePkg.Service, redisClient *redis.Client, customerSegmenter CustomerSegmenter) (Service, error) {
 	return &ServiceImpl{
 		NewsletterRepo:    newsletterRepo,
+		customerSegmenter: customerSegmenter,
 		WhatsappClient:    wsClient,
 		StoreService:      StoreService,
 		OrderService:      OrderService,
@@ -67,78 +77,6 @@ func NewWhatsappService(wsClient clients.Whatsapp, Instance, AuthToken, BaseUrl
 	}, nil
 }
 
-func (s *ServiceImpl) SendNewsletter(ctx context.Context, restGroupId string, text string, name string) error {
-	restGroup, err := s.storeGroupService.GetStoreGroupByID(ctx, restGroupId)
-	if err != nil {
-		return err
-	}
-
-	instanceId, authToken, exist, err := s.getWppSettingsIfExist(ctx, restGroup)
-	if err != nil {
-		return err
-	}
-	if exist {
-		s.WhatsappClient, err = whatsapp.NewWhatsappClient(&clients.Config{
-			Insecure:  true,
-			Protocol:  "http",
-			Instance:  instanceId,
-			AuthToken: authToken,
-		})
-		if err != nil {
-			return err
-		}
-	}
-
-	recipients, err := s.getRecipientsOfRestaurantGroup(ctx, restGroupId)
-	if err != nil {
-		return err
-	}
-
-	var wg sync.WaitGroup
-	errors := make(chan error, len(recipients))
-	wg.Add(len(recipients))
-
-	for _, recipient := range recipients {
-		go func(to, msg string) {
-			defer wg.Done()
-			if err := s.WhatsappClient.SendMessage(ctx, to, text); err != nil {
-				errors <- err
-			} else {
-				errors <- nil
-			}
-		}(recipient, text)
-	}
-
-	wg.Wait()
-	close(errors)
-
-	var sendError error
-	for range recipients {
-		if err := <-errors; err != nil && sendError == nil {
-			sendError = err
-		}
-	}
-	if sendError != nil {
-		return sendError
-	}
-
-	newsletter := models.Newsletter{
-		Name:              name,
-		Text:              text,
-		RestaurantGroupId: restGroupId,
-		Recipients:        recipients,
-		Status:            Completed,
-		CreatedAt:         time.Now().UTC(),
-		UpdatedAt:         time.Now().UTC(),
-	}
-
-	if err := s.NewsletterRepo.CreateNewsletter(ctx, newsletter); err != nil {
-		return err
-	}
-
-	return nil
-}
-
 func (s *ServiceImpl) getWppSettingsIfExist(ctx context.Context, restGroup storeModels.StoreGroup) (string, string, bool, error) {
 	for _, storeId := range restGroup.StoreIds {
 		store, err := s.StoreService.GetByID(ctx, storeId)
@@ -153,40 +91,6 @@ func (s *ServiceImpl) getWppSettingsIfExist(ctx context.Context, restGroup store
 	return "", "", false, nil
 }
 
-func (s *ServiceImpl) getRecipientsOfRestaurantGroup(ctx context.Context, restGroupId string) ([]string, error) {
-	stores, err := s.StoreService.GetStoresByStoreGroupID(ctx, restGroupId)
-	if err != nil {
-		return []string{}, err
-	}
-
-	var allOrders []models.Order
-	for _, store := range stores {
-		orders, _, err := s.OrderService.GetOrdersWithFilters(ctx, dto.OrderSelector{
-			DeliveryService: "qr_menu",
-			StoreID:         store.ID,
-		})
-		if err != nil {
-			return []string{}, err
-		}
-		allOrders = append(allOrders, orders...)
-	}
-
-	uniquePhones := make(map[string]models.Customer)
-	for _, order := range allOrders {
-		phone := order.Customer.PhoneNumber
-		if _, exists := uniquePhones[phone]; !exists {
-			uniquePhones[phone] = order.Customer
-		}
-	}
-
-	var phoneNumbers []string
-	for phone := range uniquePhones {
-		phoneNumbers = append(phoneNumbers, phone)
-	}
-
-	return phoneNumbers, nil
-}
-
 func (s *ServiceImpl) SendMessage(ctx context.Context, to, message, storeId string) error {
 	wppClient, err := s.initWppClient(ctx, storeId)
 	if err != nil {
@@ -245,6 +149,11 @@ func (s *ServiceImpl) GetSystemWebhookEventRequestByPaymentSystemRequest(r inter
 			if err != nil {
 				return paymentModels.WebhookEvent{}, err
 			}
+		} else if models.IsNewsletterOptOut(wppWebhook.Data.Body) {
+			if err := s.optOutFromNewsletters(ctx, wppWebhook.Data.From, wppWebhook.Data.To); err != nil {
+				return paymentModels.WebhookEvent{}, err
+			}
+			return paymentModels.WebhookEvent{}, ErrWpp
 		} else {
 			st, err := s.StoreService.GetStoresByWppPhoneNum(ctx, wppWebhook.Data.To)
 			if err != nil {